@@ -17,6 +17,214 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for account_deletion
+-- ----------------------------
+DROP TABLE IF EXISTS `account_deletion`;
+CREATE TABLE `account_deletion`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '清理任务ID，主键',
+  `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '注销的用户ID',
+  `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '注销用户的手机号，用于清理短信记录，清理完成后清空',
+  `stage` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '当前清理阶段',
+  `retention_until` datetime NULL DEFAULT NULL COMMENT '数据保留截止时间',
+  `comments_anonymized` bigint NULL DEFAULT 0 COMMENT '已匿名化评论数',
+  `relations_removed` bigint NULL DEFAULT 0 COMMENT '已删除关系数',
+  `posts_deleted` bigint NULL DEFAULT 0 COMMENT '已删除动态数',
+  `images_deleted` bigint NULL DEFAULT 0 COMMENT '已删除图片数',
+  `sms_scrubbed` bigint NULL DEFAULT 0 COMMENT '已清理短信记录数',
+  `attempts` bigint NULL DEFAULT 0 COMMENT '失败重试次数',
+  `error_message` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '最近一次错误信息',
+  `completed_at` datetime NULL DEFAULT NULL COMMENT '完成时间',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_account_deletion_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_account_deletion_stage`(`stage` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for announcement
+-- ----------------------------
+DROP TABLE IF EXISTS `announcement`;
+CREATE TABLE `announcement`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '公告ID，主键',
+  `title` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '公告标题',
+  `content` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL COMMENT '公告正文',
+  `target` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '目标范围：all-全部用户，segment-用户分群，tag-用户标签',
+  `segment` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '目标范围为segment时为用户分群，为tag时为标签名称',
+  `push` tinyint(1) NULL DEFAULT 0 COMMENT '发布时是否通过推送渠道通知目标用户',
+  `created_by` bigint UNSIGNED NULL DEFAULT NULL COMMENT '发布公告的管理员用户ID',
+  `publish_at` datetime NULL DEFAULT NULL COMMENT '发布时间，之前不向用户展示',
+  `expire_at` datetime NULL DEFAULT NULL COMMENT '过期时间，之后不再向用户展示，为空时不过期',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_announcement_publish_at`(`publish_at` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for announcement_read
+-- ----------------------------
+DROP TABLE IF EXISTS `announcement_read`;
+CREATE TABLE `announcement_read`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '回执ID，主键',
+  `announcement_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '公告ID',
+  `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '阅读公告的用户ID',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '阅读时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_announcement_read_announcement_user`(`announcement_id` ASC, `user_id` ASC) USING BTREE,
+  INDEX `idx_announcement_read_user_id`(`user_id` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for api_client
+-- ----------------------------
+DROP TABLE IF EXISTS `api_client`;
+CREATE TABLE `api_client`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '调用方ID，主键',
+  `name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '调用方名称',
+  `app_key` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '应用标识，随请求明文传递',
+  `app_secret` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '应用密钥，使用配置的加密密钥AES加密后存储',
+  `scopes` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '授权范围，多个以逗号分隔',
+  `status` smallint NULL DEFAULT 1 COMMENT '状态：1-启用，0-禁用',
+  `last_used_at` datetime NULL DEFAULT NULL COMMENT '最近一次调用时间',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_api_client_app_key`(`app_key` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for comment_image
+-- ----------------------------
+DROP TABLE IF EXISTS `comment_image`;
+CREATE TABLE `comment_image`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '图片ID，主键',
+  `comment_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '关联的评论ID',
+  `post_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '评论所属的动态ID',
+  `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '用户ID',
+  `object_key` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '对象存储中的键名',
+  `url` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '图片访问URL',
+  `bucket` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '存储桶名称',
+  `size` bigint NULL DEFAULT NULL COMMENT '图片大小(字节)',
+  `width` bigint NULL DEFAULT NULL COMMENT '图片宽度',
+  `height` bigint NULL DEFAULT NULL COMMENT '图片高度',
+  `content_type` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '内容类型',
+  `content_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '文件内容SHA-256摘要',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_comment_image_comment_id`(`comment_id` ASC) USING BTREE,
+  INDEX `idx_comment_image_post_id`(`post_id` ASC) USING BTREE,
+  INDEX `idx_comment_image_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_comment_image_content_hash`(`content_hash` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for comment_like
+-- ----------------------------
+DROP TABLE IF EXISTS `comment_like`;
+CREATE TABLE `comment_like`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '点赞ID，主键',
+  `comment_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '评论ID',
+  `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '点赞用户ID',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_comment_like_comment_user`(`comment_id` ASC, `user_id` ASC) USING BTREE,
+  INDEX `idx_comment_like_user_id`(`user_id` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for counter_checkpoint
+-- ----------------------------
+DROP TABLE IF EXISTS `counter_checkpoint`;
+CREATE TABLE `counter_checkpoint`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '记录ID，主键',
+  `name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '计数日志名称',
+  `position` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '已同步的最后一条日志ID',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_counter_checkpoint_name`(`name` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for daily_statistics
+-- ----------------------------
+DROP TABLE IF EXISTS `daily_statistics`;
+CREATE TABLE `daily_statistics`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '统计ID，主键',
+  `date` date NULL DEFAULT NULL COMMENT '统计日期',
+  `active_users` bigint NULL DEFAULT 0 COMMENT '日活跃用户数',
+  `new_users` bigint NULL DEFAULT 0 COMMENT '新注册用户数',
+  `total_users` bigint NULL DEFAULT 0 COMMENT '累计用户数',
+  `new_posts` bigint NULL DEFAULT 0 COMMENT '新增动态数',
+  `new_comments` bigint NULL DEFAULT 0 COMMENT '新增评论数',
+  `sms_count` bigint NULL DEFAULT 0 COMMENT '短信发送成功数',
+  `sms_cost` decimal(12, 3) NULL DEFAULT 0.000 COMMENT '短信费用（元）',
+  `storage_bytes` bigint NULL DEFAULT 0 COMMENT '图片存储占用(字节)',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_daily_statistics_date`(`date` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for data_export
+-- ----------------------------
+DROP TABLE IF EXISTS `data_export`;
+CREATE TABLE `data_export`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '导出任务ID，主键',
+  `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '用户ID',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '任务状态：pending-等待处理，processing-处理中，completed-已完成，failed-失败',
+  `bucket` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '存储桶名称',
+  `object_key` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '导出文件在对象存储中的键名',
+  `file_size` bigint NULL DEFAULT NULL COMMENT '导出文件大小(字节)',
+  `error_message` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '错误信息',
+  `completed_at` datetime NULL DEFAULT NULL COMMENT '完成时间',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_data_export_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_data_export_status`(`status` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for link_preview
+-- ----------------------------
+DROP TABLE IF EXISTS `link_preview`;
+CREATE TABLE `link_preview`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '链接预览ID，主键',
+  `url_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '链接的SHA-256摘要（十六进制）',
+  `url` varchar(2048) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '动态中的原始链接',
+  `status` smallint NULL DEFAULT 0 COMMENT '抓取状态：0-待抓取，1-成功，2-失败',
+  `title` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '网页标题',
+  `description` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '网页描述',
+  `image_url` varchar(2048) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '预览图片地址',
+  `site_name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '网站名称',
+  `fetched_at` datetime NULL DEFAULT NULL COMMENT '最近一次抓取完成时间，待抓取时为空',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_link_preview_url_hash`(`url_hash` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for location
+-- ----------------------------
+DROP TABLE IF EXISTS `location`;
+CREATE TABLE `location`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '位置ID，主键',
+  `latitude` double NULL DEFAULT NULL COMMENT '纬度（GCJ-02坐标系）',
+  `longitude` double NULL DEFAULT NULL COMMENT '经度（GCJ-02坐标系）',
+  `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '逆地理编码解析出的地址，解析完成前为空',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for post
 -- ----------------------------
@@ -26,8 +234,11 @@ CREATE TABLE `post`  (
   `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '用户ID',
   `content` varchar(2000) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '动态内容',
   `visibility` smallint NULL DEFAULT 1 COMMENT '可见性：1-公开，2-仅好友，3-私密',
+  `location_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '位置ID，未附带位置时为空',
+  `link_preview_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '内容中第一个链接的预览ID，不含链接时为空',
   `likes` bigint NULL DEFAULT 0 COMMENT '点赞数',
   `comments` bigint NULL DEFAULT 0 COMMENT '评论数',
+  `views` bigint NULL DEFAULT 0 COMMENT '浏览数（按天去重后累计）',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
@@ -44,10 +255,12 @@ CREATE TABLE `post_comment`  (
   `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '评论用户ID',
   `parent_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '父评论ID，用于回复功能',
   `content` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '评论内容',
+  `likes` bigint NULL DEFAULT 0 COMMENT '点赞数',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
-  PRIMARY KEY (`id`) USING BTREE
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_post_comment_parent_id`(`parent_id` ASC) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
@@ -65,12 +278,14 @@ CREATE TABLE `post_image`  (
   `width` bigint NULL DEFAULT NULL COMMENT '图片宽度',
   `height` bigint NULL DEFAULT NULL COMMENT '图片高度',
   `content_type` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '内容类型',
+  `content_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '文件内容SHA-256摘要',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `idx_post_image_post_id`(`post_id` ASC) USING BTREE,
-  INDEX `idx_post_image_user_id`(`user_id` ASC) USING BTREE
+  INDEX `idx_post_image_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_post_image_content_hash`(`content_hash` ASC) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
@@ -84,7 +299,8 @@ CREATE TABLE `sms_record`  (
   `content` varchar(1000) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '短信内容',
   `template_code` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '短信模板代码',
   `template_param` varchar(1000) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '短信模板参数，JSON格式',
-  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '发送状态：success-成功，failed-失败',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '发送状态：pending-等待发送，success-成功，failed-失败',
+  `attempts` bigint NULL DEFAULT 0 COMMENT '尝试发送次数',
   `error_message` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '错误信息',
   `request_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '请求ID',
   `biz_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '发送回执ID',
@@ -94,6 +310,25 @@ CREATE TABLE `sms_record`  (
   PRIMARY KEY (`id`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 2 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
 
+-- ----------------------------
+-- Table structure for tag
+-- ----------------------------
+DROP TABLE IF EXISTS `tag`;
+CREATE TABLE `tag`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '标签ID，主键',
+  `name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '标签名称，功能开关和公告通过名称引用标签',
+  `description` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '标签说明',
+  `type` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '标签类型：manual-手动，rule-规则',
+  `rule` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '规则类型，仅规则标签有效',
+  `threshold` bigint NULL DEFAULT 0 COMMENT '规则阈值，仅规则标签有效',
+  `member_count` bigint NULL DEFAULT 0 COMMENT '成员数量',
+  `refreshed_at` datetime NULL DEFAULT NULL COMMENT '规则标签最近一次计算成员的时间',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_tag_name`(`name` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for temp_image
 -- ----------------------------
@@ -105,12 +340,16 @@ CREATE TABLE `temp_image`  (
   `url` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '图片访问URL',
   `bucket` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '存储桶名称',
   `size` bigint NULL DEFAULT NULL COMMENT '图片大小(字节)',
+  `width` bigint NULL DEFAULT NULL COMMENT '图片宽度',
+  `height` bigint NULL DEFAULT NULL COMMENT '图片高度',
   `content_type` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '内容类型',
+  `content_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '文件内容SHA-256摘要',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
   PRIMARY KEY (`id`) USING BTREE,
-  INDEX `idx_temp_image_user_id`(`user_id` ASC) USING BTREE
+  INDEX `idx_temp_image_user_id`(`user_id` ASC) USING BTREE,
+  INDEX `idx_temp_image_content_hash`(`content_hash` ASC) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 2 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
@@ -124,11 +363,27 @@ CREATE TABLE `user`  (
   `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '手机号，用于验证码登录',
   `nickname` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '用户昵称，显示名称',
   `avatar` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '用户头像URL',
-  `status` smallint NULL DEFAULT 1 COMMENT '用户状态：1-正常，0-禁用',
+  `avatar_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '头像内容SHA-256摘要，用于CDN缓存刷新',
+  `status` smallint NULL DEFAULT 1 COMMENT '用户状态：1-正常，0-禁用，2-休眠，3-注销冷静期',
+  `is_private` tinyint(1) NULL DEFAULT 0 COMMENT '是否为私密账号，关注需经本人同意',
+  `mobile_hash` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '手机号SHA-256摘要，用于通讯录匹配',
+  `allow_mobile_search` tinyint(1) NULL DEFAULT 1 COMMENT '是否允许他人通过手机号搜索或通讯录匹配到自己',
+  `record_visits` tinyint(1) NULL DEFAULT 1 COMMENT '是否记录主页访问足迹，关闭后访问他人主页不留记录，也不能查看自己的访客',
+  `last_active_at` datetime NULL DEFAULT NULL COMMENT '最近活跃时间',
+  `deactivate_at` datetime NULL DEFAULT NULL COMMENT '注销生效时间，注销冷静期结束后删除账号，撤销注销时清空',
+  `username_changed_at` datetime NULL DEFAULT NULL COMMENT '最近一次修改用户名的时间，用于限制修改频率',
+  `relation_version` bigint UNSIGNED NULL DEFAULT 0 COMMENT '关注关系版本号，关注或粉丝变化时递增，用于计算ETag',
+  `token_version` bigint UNSIGNED NULL DEFAULT 0 COMMENT '令牌版本号，退出所有设备时递增，签发时写入令牌，低于该版本的令牌失效',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
-  PRIMARY KEY (`id`) USING BTREE
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_user_username`(`username` ASC) USING BTREE,
+  INDEX `idx_user_mobile`(`mobile` ASC) USING BTREE,
+  INDEX `idx_user_nickname`(`nickname` ASC) USING BTREE,
+  INDEX `idx_user_mobile_hash`(`mobile_hash` ASC) USING BTREE,
+  INDEX `idx_user_last_active_at`(`last_active_at` ASC) USING BTREE,
+  INDEX `idx_user_deactivate_at`(`deactivate_at` ASC) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 3 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
@@ -139,6 +394,7 @@ CREATE TABLE `user_follower`  (
   `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '关注ID，主键',
   `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '用户ID，关注发起者',
   `target_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '目标用户ID，被关注者',
+  `status` smallint NULL DEFAULT 1 COMMENT '关注状态：1-已通过，2-待审核',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
@@ -155,10 +411,29 @@ CREATE TABLE `user_friend`  (
   `target_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '目标用户ID，好友对象',
   `status` smallint NULL DEFAULT 0 COMMENT '好友状态：0-待确认，1-已确认',
   `direction` smallint NULL DEFAULT 0 COMMENT '关系方向：0-发起方，1-接收方',
+  `message` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '好友请求附言',
+  `remark` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '好友备注名，仅记录所有者可见',
+  `group_name` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL COMMENT '好友分组名称，仅记录所有者可见',
   `created_at` datetime NULL DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime NULL DEFAULT NULL COMMENT '更新时间',
   `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间',
-  PRIMARY KEY (`id`) USING BTREE
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_user_friend_group_name`(`group_name` ASC) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for user_tag
+-- ----------------------------
+DROP TABLE IF EXISTS `user_tag`;
+CREATE TABLE `user_tag`  (
+  `id` bigint UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '成员记录ID，主键',
+  `tag_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '标签ID',
+  `user_id` bigint UNSIGNED NULL DEFAULT NULL COMMENT '用户ID',
+  `refreshed_at` datetime NULL DEFAULT NULL COMMENT '规则标签最近一次匹配的时间，早于本次计算的成员会被移除',
+  `created_at` datetime NULL DEFAULT NULL COMMENT '加入时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_user_tag_tag_user`(`tag_id` ASC, `user_id` ASC) USING BTREE,
+  INDEX `idx_user_tag_user_id`(`user_id` ASC) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_unicode_ci ROW_FORMAT = Dynamic;
 
 SET FOREIGN_KEY_CHECKS = 1;
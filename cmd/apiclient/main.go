@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/internal/utils"
+	"app/pkg/database"
+)
+
+// credentialCharset 生成应用标识和密钥使用的字符集
+const credentialCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// 创建服务端调用方，生成应用标识和密钥
+// 应用密钥仅在创建时输出一次，数据库中加密存储
+func main() {
+	name := flag.String("name", "", "调用方名称")
+	scopes := flag.String("scopes", constant.APIScopeStatsRead, "授权范围，多个以逗号分隔")
+	flag.Parse()
+
+	if strings.TrimSpace(*name) == "" {
+		log.Fatal("调用方名称不能为空")
+	}
+
+	// 初始化配置
+	err := config.Init()
+	if err != nil {
+		fmt.Printf("配置初始化失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	secretKey := config.GetAPISignConfig().SecretKey
+	if secretKey == "" {
+		log.Fatal("未配置应用密钥的加密密钥")
+	}
+
+	// 初始化数据库连接
+	err = database.Init()
+	if err != nil {
+		log.Fatalf("数据库连接失败: %v", err)
+	}
+	defer database.Close()
+
+	appKey := utils.GenerateRandomString(constant.SignAppKeyLength, credentialCharset)
+	appSecret := utils.GenerateRandomString(constant.SignAppSecretLength, credentialCharset)
+	encrypted, err := utils.EncryptAES([]byte(appSecret), []byte(secretKey))
+	if err != nil {
+		log.Fatalf("加密应用密钥失败: %v", err)
+	}
+
+	client := &model.APIClient{
+		Name:      strings.TrimSpace(*name),
+		AppKey:    appKey,
+		AppSecret: encrypted,
+		Scopes:    *scopes,
+		Status:    constant.APIClientStatusEnabled,
+	}
+	if err := repository.NewAPIClientRepository(database.GetDB()).Create(context.Background(), client); err != nil {
+		log.Fatalf("创建调用方失败: %v", err)
+	}
+
+	fmt.Printf("调用方创建成功\n")
+	fmt.Printf("ID: %d\n", client.ID)
+	fmt.Printf("AppKey: %s\n", appKey)
+	fmt.Printf("AppSecret: %s\n", appSecret)
+	fmt.Printf("授权范围: %s\n", client.Scopes)
+	fmt.Println("请妥善保存应用密钥，之后将无法再次查看")
+}
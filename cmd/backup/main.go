@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"app/config"
+	"app/internal/backup"
+	"app/pkg/cos"
+	"app/pkg/database"
+	"app/pkg/logger"
+)
+
+// 备份或恢复用户内容（用户、动态、评论和关系），用于灾难恢复演练
+// 备份：go run ./cmd/backup -mode auto|full|incremental -format json|csv
+// 恢复：go run ./cmd/backup -restore latest|<清单对象键> [-dry-run]
+func main() {
+	mode := flag.String("mode", backup.ModeAuto, "备份模式：auto、full、incremental")
+	format := flag.String("format", backup.FormatJSON, "备份格式：json（可用于恢复）、csv（仅用于数据分析，只支持全量备份）")
+	restore := flag.String("restore", "", "从指定备份恢复，latest表示最近一次备份，否则为备份清单的对象键")
+	dryRun := flag.Bool("dry-run", false, "恢复时只读取备份并校验关联引用，不写入数据库")
+	flag.Parse()
+
+	// 初始化配置
+	err := config.Init()
+	if err != nil {
+		fmt.Printf("配置初始化失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 初始化日志系统
+	if err := logger.Init(); err != nil {
+		fmt.Printf("日志系统初始化失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 初始化数据库连接
+	err = database.Init()
+	if err != nil {
+		log.Fatalf("数据库连接失败: %v", err)
+	}
+	defer database.Close()
+
+	storage, err := cos.GetStorageClient()
+	if err != nil {
+		log.Fatalf("获取COS客户端失败: %v", err)
+	}
+
+	service := backup.New(database.GetDB(), storage, config.GetBackupConfig())
+	ctx := context.Background()
+
+	if *restore == "" {
+		manifestKey, manifest, err := service.Backup(ctx, *mode, *format)
+		if err != nil {
+			log.Fatalf("备份失败: %v", err)
+		}
+		fmt.Printf("备份完成，模式: %s，清单: %s\n", manifest.Mode, manifestKey)
+		for _, t := range manifest.Tables {
+			fmt.Printf("  %s: %d 行\n", t.Name, t.Rows)
+		}
+		return
+	}
+
+	result, err := service.Restore(ctx, *restore, *dryRun)
+	if result != nil {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+	}
+	if errors.Is(err, backup.ErrIntegrityViolation) {
+		log.Fatal("备份数据存在无效的关联引用，未写入数据库")
+	}
+	if err != nil {
+		log.Fatalf("恢复失败: %v", err)
+	}
+	if *dryRun {
+		fmt.Println("校验通过，未写入数据库")
+		return
+	}
+	fmt.Println("恢复完成")
+}
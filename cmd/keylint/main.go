@@ -0,0 +1,211 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 被检查的包路径
+const (
+	redisImportPath    = "app/pkg/redis"
+	constantImportPath = "app/internal/constant"
+)
+
+// allowedDirs 允许直接构造Redis键的目录
+var allowedDirs = []string{
+	filepath.Join("internal", "cachekey"),
+}
+
+var (
+	// keyLiteralPattern 形如 命名空间:片段 的字符串字面量，排除 http:// 等URL
+	keyLiteralPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*:([a-z0-9_%]|$)`)
+	// keyNamePattern 保存键或键前缀的变量、常量名称，名称中包含Key或Prefix
+	keyNamePattern = regexp.MustCompile(`(?i)(key|prefix)`)
+)
+
+// issue 检查发现的问题
+type issue struct {
+	pos     token.Position
+	message string
+}
+
+// 检查业务代码中直接拼接的Redis键，所有键应通过 internal/cachekey 构造
+// 用法: go run ./cmd/keylint [目录...]，默认检查 internal 和 cmd，发现问题时以非零状态退出
+func main() {
+	flag.Parse()
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"internal", "cmd"}
+	}
+
+	fset := token.NewFileSet()
+	var issues []issue
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if isAllowed(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			issues = append(issues, checkFile(fset, file)...)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "检查目录 %s 失败: %v\n", dir, err)
+			os.Exit(2)
+		}
+	}
+
+	for _, is := range issues {
+		fmt.Printf("%s: %s\n", is.pos, is.message)
+	}
+	if len(issues) > 0 {
+		fmt.Printf("发现 %d 处直接构造的Redis键，请在 internal/cachekey 中添加构造函数\n", len(issues))
+		os.Exit(1)
+	}
+}
+
+// isAllowed 判断目录是否允许直接构造Redis键
+func isAllowed(path string) bool {
+	for _, dir := range allowedDirs {
+		if filepath.Clean(path) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFile 检查单个文件
+// 规则一: 调用redis包函数或Store方法时，键参数中出现字符串字面量或constant包中的标识
+// 规则二: 名称中包含Key或Prefix的变量、常量被赋值为形如 命名空间:片段 的字符串
+func checkFile(fset *token.FileSet, file *ast.File) []issue {
+	redisName := importName(file, redisImportPath)
+	constantName := importName(file, constantImportPath)
+
+	var issues []issue
+	report := func(node ast.Node, message string) {
+		issues = append(issues, issue{pos: fset.Position(node.Pos()), message: message})
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if arg := keyArgument(node, redisName); arg != nil && containsRawKey(arg, constantName) {
+				report(arg, "Redis键应通过 cachekey 包构造")
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if i < len(node.Values) && keyNamePattern.MatchString(name.Name) && containsKeyLiteral(node.Values[i]) {
+					report(name, fmt.Sprintf("%s 直接定义了Redis键，应通过 cachekey 包构造", name.Name))
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || i >= len(node.Rhs) {
+					continue
+				}
+				if keyNamePattern.MatchString(ident.Name) && containsKeyLiteral(node.Rhs[i]) {
+					report(ident, fmt.Sprintf("%s 直接拼接了Redis键，应通过 cachekey 包构造", ident.Name))
+				}
+			}
+		}
+		return true
+	})
+	return issues
+}
+
+// importName 返回文件中导入指定包使用的名称，未导入时返回空字符串
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
+
+// keyArgument 返回Redis调用中的键参数，非Redis调用时返回nil
+// 支持 redis.Get(key)、redis.GetClient().Get(ctx, key) 和 s.store.Get(key) 三种形式
+func keyArgument(call *ast.CallExpr, redisName string) ast.Expr {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		if redisName != "" && x.Name == redisName {
+			return call.Args[0]
+		}
+	case *ast.SelectorExpr:
+		if x.Sel.Name == "store" {
+			return call.Args[0]
+		}
+	case *ast.CallExpr:
+		if inner, ok := x.Fun.(*ast.SelectorExpr); ok && inner.Sel.Name == "GetClient" && len(call.Args) > 1 {
+			if pkg, ok := inner.X.(*ast.Ident); ok && redisName != "" && pkg.Name == redisName {
+				return call.Args[1]
+			}
+		}
+	}
+	return nil
+}
+
+// containsRawKey 判断键参数是否包含字符串字面量或constant包中的标识
+func containsRawKey(expr ast.Expr, constantName string) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.BasicLit:
+			if node.Kind == token.STRING {
+				found = true
+			}
+		case *ast.SelectorExpr:
+			if pkg, ok := node.X.(*ast.Ident); ok && constantName != "" && pkg.Name == constantName {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// containsKeyLiteral 判断表达式是否包含形如 命名空间:片段 的字符串字面量
+func containsKeyLiteral(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if value, err := strconv.Unquote(lit.Value); err == nil && keyLiteralPattern.MatchString(value) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
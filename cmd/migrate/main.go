@@ -6,8 +6,14 @@ import (
 	"os"
 
 	"app/config"
+	"app/internal/constant"
 	"app/internal/model"
+	"app/internal/utils"
 	"app/pkg/database"
+	"app/pkg/geohash"
+	"app/pkg/idgen"
+
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -34,17 +40,7 @@ func main() {
 	log.Println("开始迁移数据库表结构...")
 
 	// 自动迁移数据库表结构
-	models := []interface{}{
-		&model.User{},
-		&model.SMSRecord{},
-		&model.UserFollower{},
-		&model.UserFriend{},
-		&model.Post{},
-		&model.PostComment{},
-		&model.PostImage{},
-		&model.TempImage{},
-		// 在此处添加其他模型
-	}
+	models := model.All()
 
 	for _, m := range models {
 		modelName := fmt.Sprintf("%T", m)
@@ -58,4 +54,113 @@ func main() {
 	}
 
 	log.Println("数据库表结构迁移完成")
+
+	// 为存量用户回填手机号摘要
+	if err := backfillMobileHash(db); err != nil {
+		log.Fatalf("回填手机号摘要失败: %v", err)
+	}
+
+	// 为存量用户、动态和评论回填公开ID
+	for _, t := range []struct {
+		name  string
+		model interface{}
+	}{
+		{"用户", &model.User{}},
+		{"动态", &model.Post{}},
+		{"评论", &model.PostComment{}},
+	} {
+		if err := backfillPublicID(db, t.name, t.model); err != nil {
+			log.Fatalf("回填%s公开ID失败: %v", t.name, err)
+		}
+	}
+
+	// 为存量位置回填geohash编码
+	if err := backfillGeohash(db); err != nil {
+		log.Fatalf("回填位置geohash失败: %v", err)
+	}
+}
+
+// backfillMobileHash 为尚未生成手机号摘要的用户分批回填，用于通讯录好友发现
+func backfillMobileHash(db *gorm.DB) error {
+	log.Println("开始回填用户手机号摘要...")
+
+	var users []model.User
+	total := 0
+	result := db.Select("id", "mobile").
+		Where("mobile <> '' AND (mobile_hash IS NULL OR mobile_hash = '')").
+		FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				err := db.Model(&model.User{}).Where("id = ?", user.ID).
+					Update("mobile_hash", utils.HashMobile(user.Mobile)).Error
+				if err != nil {
+					return err
+				}
+			}
+			total += len(users)
+			return nil
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.Printf("手机号摘要回填完成，共处理 %d 个用户", total)
+	return nil
+}
+
+// backfillPublicID 为尚未生成公开ID的记录分批回填，包含已删除的记录，不修改更新时间
+func backfillPublicID(db *gorm.DB, name string, m interface{}) error {
+	log.Printf("开始回填%s公开ID...", name)
+
+	total := 0
+	for {
+		// 回填后的记录不再满足条件，每次查询尚未回填的前一批
+		var ids []uint
+		err := db.Model(m).Unscoped().Where("public_id IS NULL OR public_id = ''").
+			Order("id").Limit(500).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		for _, id := range ids {
+			err := db.Model(m).Unscoped().Where("id = ?", id).
+				UpdateColumn("public_id", idgen.New()).Error
+			if err != nil {
+				return err
+			}
+		}
+		total += len(ids)
+	}
+
+	log.Printf("%s公开ID回填完成，共处理 %d 条记录", name, total)
+	return nil
+}
+
+// backfillGeohash 为尚未生成geohash编码的位置分批回填，用于附近动态地图
+func backfillGeohash(db *gorm.DB) error {
+	log.Println("开始回填位置geohash...")
+
+	var locations []model.Location
+	total := 0
+	result := db.Select("id", "latitude", "longitude").
+		Where("geohash IS NULL OR geohash = ''").
+		FindInBatches(&locations, 500, func(tx *gorm.DB, batch int) error {
+			for _, location := range locations {
+				hash := geohash.Encode(location.Latitude, location.Longitude, constant.LocationGeohashPrecision)
+				err := db.Model(&model.Location{}).Where("id = ?", location.ID).
+					UpdateColumn("geohash", hash).Error
+				if err != nil {
+					return err
+				}
+			}
+			total += len(locations)
+			return nil
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.Printf("位置geohash回填完成，共处理 %d 个位置", total)
+	return nil
 }
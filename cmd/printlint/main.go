@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fmtImportPath 被检查的包路径
+const fmtImportPath = "fmt"
+
+// forbiddenFuncs 禁止使用的fmt包函数，输出到标准输出的日志会丢失请求ID且不经过日志系统
+var forbiddenFuncs = map[string]bool{
+	"Print":   true,
+	"Printf":  true,
+	"Println": true,
+}
+
+// forbiddenBuiltins 禁止使用的内置函数
+var forbiddenBuiltins = map[string]bool{
+	"print":   true,
+	"println": true,
+}
+
+// issue 检查发现的问题
+type issue struct {
+	pos     token.Position
+	message string
+}
+
+// 检查业务代码中通过fmt.Print输出的日志，服务和仓库应通过 pkg/logger 携带上下文记录日志
+// 用法: go run ./cmd/printlint [目录...]，默认检查 internal、pkg 和 config，发现问题时以非零状态退出
+// cmd 下的命令行工具在日志系统初始化前或面向终端输出，不在默认检查范围内
+func main() {
+	flag.Parse()
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"internal", "pkg", "config"}
+	}
+
+	fset := token.NewFileSet()
+	var issues []issue
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			issues = append(issues, checkFile(fset, file)...)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "检查目录 %s 失败: %v\n", dir, err)
+			os.Exit(2)
+		}
+	}
+
+	for _, is := range issues {
+		fmt.Printf("%s: %s\n", is.pos, is.message)
+	}
+	if len(issues) > 0 {
+		fmt.Printf("发现 %d 处直接输出的日志，请使用 pkg/logger 记录\n", len(issues))
+		os.Exit(1)
+	}
+}
+
+// checkFile 检查单个文件中对fmt.Print系列函数和内置print函数的调用
+func checkFile(fset *token.FileSet, file *ast.File) []issue {
+	fmtName := importName(file, fmtImportPath)
+
+	var issues []issue
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := fun.X.(*ast.Ident); ok && fmtName != "" && pkg.Name == fmtName && forbiddenFuncs[fun.Sel.Name] {
+				issues = append(issues, issue{
+					pos:     fset.Position(call.Pos()),
+					message: fmt.Sprintf("不应使用 fmt.%s 输出日志，请使用 logger 并传入上下文", fun.Sel.Name),
+				})
+			}
+		case *ast.Ident:
+			if forbiddenBuiltins[fun.Name] && fun.Obj == nil {
+				issues = append(issues, issue{
+					pos:     fset.Position(call.Pos()),
+					message: fmt.Sprintf("不应使用内置函数 %s 输出日志，请使用 logger 并传入上下文", fun.Name),
+				})
+			}
+		}
+		return true
+	})
+	return issues
+}
+
+// importName 返回文件中导入指定包使用的名称，未导入时返回空字符串
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/pkg/redis"
+)
+
+// 为启用redis.key_prefix之前写入的存量键添加环境前缀，按命名空间逐个迁移
+// 用法: go run ./cmd/rediskeys [-dry-run]，需先在配置中设置key_prefix，迁移期间应停止服务避免写入新的未加前缀的键
+func main() {
+	dryRun := flag.Bool("dry-run", false, "只统计需要迁移的键，不修改数据")
+	flag.Parse()
+
+	// 初始化配置
+	if err := config.Init(); err != nil {
+		fmt.Printf("配置初始化失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	prefix := redis.KeyPrefix()
+	if prefix == "" {
+		log.Fatal("未配置redis.key_prefix，无需迁移")
+	}
+
+	// 初始化Redis连接
+	if err := redis.Init(); err != nil {
+		log.Fatalf("Redis连接失败: %v", err)
+	}
+	defer redis.Close()
+
+	log.Printf("开始为存量键添加前缀 %s ...", prefix)
+
+	total := 0
+	for _, ns := range cachekey.Namespaces() {
+		count, err := redis.MigrateKeyPrefix([]string{ns.Pattern()}, *dryRun)
+		total += count
+		if err != nil {
+			log.Fatalf("迁移命名空间 %s 失败: %v", ns, err)
+		}
+		if count > 0 {
+			log.Printf("命名空间 %s: %d 个键", ns, count)
+		}
+	}
+
+	if *dryRun {
+		log.Printf("共 %d 个键需要迁移", total)
+		return
+	}
+	log.Printf("迁移完成，共迁移 %d 个键", total)
+}
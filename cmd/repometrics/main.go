@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 默认的仓库目录和生成文件名
+const (
+	defaultDir    = "internal/repository"
+	defaultOutput = "metrics_gen.go"
+	// 本项目的模块路径前缀
+	modulePrefix = "app/"
+)
+
+// repoInterface 仓库接口
+type repoInterface struct {
+	name    string
+	methods []*ast.Field
+	file    *ast.File
+}
+
+// generator 生成仓库指标装饰器的代码
+type generator struct {
+	fset    *token.FileSet
+	buf     bytes.Buffer
+	imports map[string]string // 包名到导入路径
+}
+
+// 为 internal/repository 中的所有 XxxRepository 接口生成记录调用指标的装饰器
+// 用法: go run ./cmd/repometrics [-dir 仓库目录] [-out 生成文件名]，仓库接口变更后需重新生成
+func main() {
+	dir := flag.String("dir", defaultDir, "仓库目录")
+	out := flag.String("out", defaultOutput, "生成的文件名，位于仓库目录下")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	interfaces, err := parseInterfaces(fset, *dir, *out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "解析仓库接口失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	g := &generator{fset: fset, imports: map[string]string{
+		"time": "time",
+	}}
+	src, err := g.generate(interfaces)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "生成代码失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	path := filepath.Join(*dir, *out)
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "写入 %s 失败: %v\n", path, err)
+		os.Exit(1)
+	}
+	fmt.Printf("已为 %d 个仓库接口生成 %s\n", len(interfaces), path)
+}
+
+// parseInterfaces 解析目录中以Repository结尾的导出接口，按名称排序
+func parseInterfaces(fset *token.FileSet, dir, output string) ([]repoInterface, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []repoInterface
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == output {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				it, ok := ts.Type.(*ast.InterfaceType)
+				if !ok || !ts.Name.IsExported() || !strings.HasSuffix(ts.Name.Name, "Repository") {
+					continue
+				}
+				for _, m := range it.Methods.List {
+					if _, ok := m.Type.(*ast.FuncType); !ok {
+						return nil, fmt.Errorf("%s: 不支持嵌入接口", fset.Position(m.Pos()))
+					}
+				}
+				interfaces = append(interfaces, repoInterface{name: ts.Name.Name, methods: it.Methods.List, file: file})
+			}
+		}
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool { return interfaces[i].name < interfaces[j].name })
+	return interfaces, nil
+}
+
+// generate 生成装饰器代码并格式化
+func (g *generator) generate(interfaces []repoInterface) ([]byte, error) {
+	var body bytes.Buffer
+	for _, iface := range interfaces {
+		if err := g.writeDecorator(&body, iface); err != nil {
+			return nil, err
+		}
+	}
+
+	// 按标准库、本项目、第三方库分组导入
+	var groups [3][]string
+	for _, path := range g.imports {
+		switch {
+		case !strings.Contains(strings.Split(path, "/")[0], "."):
+			if strings.HasPrefix(path, modulePrefix) {
+				groups[1] = append(groups[1], path)
+			} else {
+				groups[0] = append(groups[0], path)
+			}
+		default:
+			groups[2] = append(groups[2], path)
+		}
+	}
+
+	g.buf.WriteString("// Code generated by go run ./cmd/repometrics; DO NOT EDIT.\n\n")
+	g.buf.WriteString("package repository\n\nimport (")
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Strings(group)
+		g.buf.WriteString("\n")
+		for _, path := range group {
+			fmt.Fprintf(&g.buf, "\t%s\n", strconv.Quote(path))
+		}
+	}
+	g.buf.WriteString(")\n")
+	g.buf.Write(body.Bytes())
+
+	return format.Source(g.buf.Bytes())
+}
+
+// writeDecorator 生成一个仓库接口的装饰器类型、构造函数和方法
+func (g *generator) writeDecorator(w *bytes.Buffer, iface repoInterface) error {
+	typeName := lowerFirst(iface.name) + "Metrics"
+
+	fmt.Fprintf(w, "\n// %s 记录%s各方法调用指标的装饰器\n", typeName, iface.name)
+	fmt.Fprintf(w, "type %s struct {\n\tnext %s\n}\n", typeName, iface.name)
+	fmt.Fprintf(w, "\n// With%sMetrics 包装%s，记录各方法的调用次数、耗时和错误次数\n", iface.name, iface.name)
+	fmt.Fprintf(w, "func With%sMetrics(repo %s) %s {\n\treturn &%s{next: repo}\n}\n", iface.name, iface.name, iface.name, typeName)
+
+	for _, m := range iface.methods {
+		ft := m.Type.(*ast.FuncType)
+		for _, name := range m.Names {
+			if err := g.writeMethod(w, iface, typeName, name.Name, ft); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeMethod 生成一个装饰器方法，最后一个返回值为error时记录错误
+func (g *generator) writeMethod(w *bytes.Buffer, iface repoInterface, typeName, method string, ft *ast.FuncType) error {
+	var params, args []string
+	variadic := false
+	index := 0
+	if ft.Params != nil {
+		for _, field := range ft.Params.List {
+			typ, err := g.typeString(iface.file, field.Type)
+			if err != nil {
+				return err
+			}
+			_, variadic = field.Type.(*ast.Ellipsis)
+
+			names := make([]string, 0, len(field.Names))
+			for _, n := range field.Names {
+				names = append(names, n.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, "")
+			}
+			for _, n := range names {
+				if n == "" || n == "_" || n == "r" || n == "err" {
+					n = fmt.Sprintf("p%d", index)
+				}
+				index++
+				params = append(params, n+" "+typ)
+				args = append(args, n)
+			}
+		}
+	}
+	if variadic {
+		args[len(args)-1] += "..."
+	}
+
+	var results []string
+	hasErr := false
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			typ, err := g.typeString(iface.file, field.Type)
+			if err != nil {
+				return err
+			}
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				results = append(results, typ)
+			}
+		}
+		hasErr = results[len(results)-1] == "error"
+	}
+
+	resultList := ""
+	switch {
+	case hasErr:
+		named := make([]string, len(results))
+		for i, typ := range results[:len(results)-1] {
+			named[i] = "_ " + typ
+		}
+		named[len(named)-1] = "err error"
+		resultList = " (" + strings.Join(named, ", ") + ")"
+	case len(results) == 1:
+		resultList = " " + results[0]
+	case len(results) > 1:
+		resultList = " (" + strings.Join(results, ", ") + ")"
+	}
+
+	errArg := "nil"
+	if hasErr {
+		errArg = "&err"
+	}
+	call := fmt.Sprintf("r.next.%s(%s)", method, strings.Join(args, ", "))
+	if len(results) > 0 {
+		call = "return " + call
+	}
+
+	fmt.Fprintf(w, "\nfunc (r *%s) %s(%s)%s {\n", typeName, method, strings.Join(params, ", "), resultList)
+	fmt.Fprintf(w, "\tdefer observe(%q, %q, time.Now(), %s)\n", iface.name, method, errArg)
+	fmt.Fprintf(w, "\t%s\n}\n", call)
+	return nil
+}
+
+// typeString 输出类型表达式的源码，并登记其引用的包
+func (g *generator) typeString(file *ast.File, expr ast.Expr) (string, error) {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		path, found := importPath(file, pkg.Name)
+		if !found {
+			err = fmt.Errorf("%s: 未找到包 %s 的导入路径", g.fset.Position(sel.Pos()), pkg.Name)
+			return false
+		}
+		g.imports[pkg.Name] = path
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, g.fset, expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// lowerFirst 将名称开头的大写字母（含缩写）转为小写，如APIClientRepository转为apiClientRepository
+func lowerFirst(name string) string {
+	upper := 0
+	for upper < len(name) && name[upper] >= 'A' && name[upper] <= 'Z' {
+		upper++
+	}
+	if upper > 1 && upper < len(name) {
+		upper--
+	}
+	return strings.ToLower(name[:upper]) + name[upper:]
+}
+
+// importPath 查找文件中包名对应的导入路径
+func importPath(file *ast.File, name string) (string, bool) {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return path, true
+			}
+			continue
+		}
+		if path == name || strings.HasSuffix(path, "/"+name) {
+			return path, true
+		}
+	}
+	return "", false
+}
@@ -5,19 +5,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/maintenance"
+	"app/internal/middleware"
 	"app/internal/scheduler"
 	"app/internal/utils"
 	"app/pkg/database"
+	"app/pkg/debug"
 	"app/pkg/logger"
+	"app/pkg/notify"
 	"app/pkg/redis"
+	"app/pkg/resilience"
 	pkgscheduler "app/pkg/scheduler"
 
 	"github.com/gin-gonic/gin"
@@ -77,16 +87,69 @@ func initComponents() {
 // initAndStartScheduler 初始化并启动定时任务调度器
 // 注册所有任务并启动调度器
 func initAndStartScheduler() {
-	// 初始化定时任务调度器（使用Redis分布式锁）
-	schedulerInstance = pkgscheduler.Init(pkgscheduler.WithRedisLock())
+	schedulerConfig := config.GetSchedulerConfig()
 
-	// 注册所有定时任务
+	// 停止时等待运行中任务完成的最长时间，未配置或格式错误时使用默认值
+	drainTimeout, _ := time.ParseDuration(schedulerConfig.DrainTimeout)
+
+	// 多节点部署时通过选主或逐个任务加Redis分布式锁保证任务只在一个节点执行
+	coordination := pkgscheduler.WithRedisLock()
+	if election := schedulerConfig.LeaderElection; election.Enabled {
+		leaseTTL, _ := time.ParseDuration(election.LeaseTTL)
+		coordination = pkgscheduler.WithLeaderElection(election.NodeID, leaseTTL)
+	}
+
+	// 初始化定时任务调度器（维护模式下暂停非关键任务）
 	ctx := context.Background()
-	for taskName, config := range scheduler.TaskConfigs {
+	options := []pkgscheduler.Option{
+		coordination,
+		pkgscheduler.WithKeyPrefix(cachekey.NamespaceScheduler.Prefix()),
+		pkgscheduler.WithDrainTimeout(drainTimeout),
+		pkgscheduler.WithPauseCheck(func(ctx context.Context) bool {
+			_, active := maintenance.Active(ctx)
+			return active
+		}),
+	}
+
+	// 启用任务SLO告警检查，未配置通知渠道时只记录告警状态
+	if alertConfig := schedulerConfig.Alert; alertConfig.Enabled {
+		notifier, err := notify.NewNotifiers(alertConfig.Notifiers)
+		if err != nil {
+			logger.Error(ctx, "告警通知渠道配置无效", zap.Error(err))
+			os.Exit(1)
+		}
+		interval, _ := time.ParseDuration(alertConfig.Interval)
+		options = append(options, pkgscheduler.WithAlerting(scheduler.NewAlertNotifier(notifier), interval))
+	}
+
+	schedulerInstance = pkgscheduler.Init(options...)
+
+	// 注册配置中启用的定时任务，scheduler.tasks中不存在的任务名称可能是拼写错误
+	tasks := scheduler.EnabledTaskConfigs()
+	for taskName := range schedulerConfig.Tasks {
+		if _, exists := scheduler.TaskConfigs[taskName]; !exists {
+			logger.Warn(ctx, "配置了不存在的定时任务", zap.String("task", taskName))
+		}
+	}
+	for taskName := range scheduler.TaskConfigs {
+		if _, enabled := tasks[taskName]; !enabled {
+			logger.Info(ctx, "定时任务已在配置中禁用", zap.String("task", taskName))
+		}
+	}
+	for taskName, config := range tasks {
 		// 创建注册选项，使用任务配置中的设置
 		options := pkgscheduler.RegisterOption{
-			RunImmediately: config.RunImmediately, // 使用配置中的立即执行设置
-			LockTimeout:    config.LockTimeout,    // 使用配置中的锁超时设置
+			RunImmediately:   config.RunImmediately,   // 使用配置中的立即执行设置
+			LockTimeout:      config.LockTimeout,      // 使用配置中的锁超时设置
+			MisfirePolicy:    config.MisfirePolicy,    // 使用配置中的错过执行策略
+			CatchUpLimit:     config.CatchUpLimit,     // 使用配置中的补执行次数上限
+			DependsOn:        config.DependsOn,        // 使用配置中的任务依赖
+			DependencyWindow: config.DependencyWindow, // 使用配置中的依赖窗口期
+			Critical:         config.Critical,         // 使用配置中的关键任务标记
+			Timeout:          config.Timeout,          // 使用配置中的任务超时时间
+			SLO:              config.SLO,              // 使用配置中的服务等级目标
+			RetryCount:       config.RetryCount,       // 使用配置中的失败重试次数
+			RetryInterval:    config.RetryInterval,    // 使用配置中的重试间隔
 		}
 
 		// 使用选项注册任务
@@ -98,6 +161,20 @@ func initAndStartScheduler() {
 		logger.Info(ctx, "成功注册定时任务", zap.String("task", taskName), zap.String("spec", config.Spec))
 	}
 
+	// 注册可通过管理接口提交的一次性任务类型
+	for typeName, jobType := range scheduler.JobTypes {
+		if err := schedulerInstance.RegisterJobType(typeName, jobType); err != nil {
+			logger.Error(ctx, "注册一次性任务类型失败", zap.String("type", typeName), zap.Error(err))
+			os.Exit(1)
+		}
+	}
+
+	// 校验任务依赖关系，依赖不存在或存在环时退出
+	if err := schedulerInstance.ValidateDependencies(); err != nil {
+		logger.Error(ctx, "定时任务依赖关系无效", zap.Error(err))
+		os.Exit(1)
+	}
+
 	// 启动定时任务调度器
 	schedulerInstance.Start()
 }
@@ -107,6 +184,8 @@ func initAndStartScheduler() {
 func setupHTTPServer(cfg *config.Config) *http.Server {
 	// 初始化Gin引擎
 	router := gin.Default()
+	// 为管理接口的请求分配请求ID，提交的一次性任务执行时沿用该ID
+	router.Use(middleware.RequestID())
 
 	// 设置API路由
 	setupRouter(router)
@@ -114,10 +193,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	// 准备服务器地址
 	serverAddr := fmt.Sprintf("%s:%d", cfg.Scheduler.Host, cfg.Scheduler.Port) // 使用Scheduler配置
 
-	// 创建HTTP服务器
+	// 创建HTTP服务器，读写超时限制慢客户端占用连接的时间
 	srv := &http.Server{
-		Addr:    serverAddr,
-		Handler: router,
+		Addr:         serverAddr,
+		Handler:      router,
+		ReadTimeout:  config.ParseDuration(cfg.Scheduler.ReadTimeout, 60*time.Second),
+		WriteTimeout: config.ParseDuration(cfg.Scheduler.WriteTimeout, 60*time.Second),
 	}
 
 	// 启动HTTP服务器（非阻塞）
@@ -144,7 +225,7 @@ func setupGracefulShutdown(srv *http.Server) {
 	<-quit
 	fmt.Println("正在关闭定时任务服务器...")
 
-	// 停止定时任务调度器
+	// 停止定时任务调度器，等待运行中的任务完成并释放分布式锁
 	schedulerInstance.Stop()
 
 	// 创建一个超时上下文，等待现有请求完成
@@ -171,17 +252,61 @@ func setupRouter(router *gin.Engine) {
 	// 健康检查接口
 	router.GET("/health", handleHealthCheck)
 
-	// 任务管理API组
-	taskGroup := router.Group("/tasks")
+	readPermission := middleware.SchedulerPermissionMiddleware(constant.SchedulerPermissionRead)
+	runPermission := middleware.SchedulerPermissionMiddleware(constant.SchedulerPermissionRun)
+
+	// 查询当前主节点（需要认证）
+	router.GET("/leader", middleware.SchedulerAuthMiddleware(), readPermission, handleGetLeader)
+
+	// 任务管理API组（需要认证）
+	taskGroup := router.Group("/tasks", middleware.SchedulerAuthMiddleware())
 	{
 		// 获取所有任务列表
-		taskGroup.GET("", handleGetAllTasks)
+		taskGroup.GET("", readPermission, handleGetAllTasks)
 
 		// 获取指定任务信息
-		taskGroup.GET("/:name", handleGetTaskInfo)
+		taskGroup.GET("/:name", readPermission, handleGetTaskInfo)
+
+		// 获取任务最近的执行记录
+		taskGroup.GET("/:name/history", readPermission, handleGetTaskHistory)
 
 		// 手动执行任务
-		taskGroup.POST("/:name/run", handleRunTask)
+		taskGroup.POST("/:name/run", runPermission, handleRunTask)
+	}
+
+	// Prometheus格式的任务指标（需要认证）
+	router.GET("/metrics", middleware.SchedulerAuthMiddleware(), readPermission, handleMetrics)
+
+	// 任务SLO告警API组（需要认证）
+	alertGroup := router.Group("/alerts", middleware.SchedulerAuthMiddleware())
+	{
+		// 获取告警中的告警
+		alertGroup.GET("", readPermission, handleGetAlerts)
+
+		// 获取未失效的静默规则
+		alertGroup.GET("/silences", readPermission, handleGetSilences)
+
+		// 添加静默规则
+		alertGroup.POST("/silences", runPermission, handleAddSilence)
+
+		// 删除静默规则
+		alertGroup.DELETE("/silences/:id", runPermission, handleDeleteSilence)
+	}
+
+	// 一次性任务API组（需要认证）
+	jobGroup := router.Group("/jobs", middleware.SchedulerAuthMiddleware())
+	{
+		// 提交一次性任务
+		jobGroup.POST("", runPermission, handleScheduleJob)
+
+		// 获取一次性任务状态
+		jobGroup.GET("/:id", readPermission, handleGetJob)
+	}
+
+	// 运行时调试接口（需要认证，默认关闭）
+	if config.GetDebugConfig().Enabled {
+		debugPermission := middleware.SchedulerPermissionMiddleware(constant.SchedulerPermissionDebug)
+		debug.Register(router.Group("/debug", middleware.SchedulerAuthMiddleware(), debugPermission))
 	}
 }
 
@@ -200,6 +325,19 @@ func handleHealthCheck(c *gin.Context) {
 	}
 }
 
+// handleGetLeader 处理查询选主状态请求
+// 返回本节点标识、当前主节点和租约剩余有效期，未启用选主模式时enabled为false
+func handleGetLeader(c *gin.Context) {
+	info, err := schedulerInstance.LeaderInfo(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
 // handleGetAllTasks 处理获取所有任务列表请求
 func handleGetAllTasks(c *gin.Context) {
 	tasks := schedulerInstance.GetAllTasksInfo()
@@ -220,16 +358,217 @@ func handleGetTaskInfo(c *gin.Context) {
 }
 
 // handleRunTask 处理手动执行任务请求
+// 记录调用方身份作为审计日志
 func handleRunTask(c *gin.Context) {
 	name := c.Param("name")
+	caller := c.GetString("schedulerCaller")
 	err := schedulerInstance.RunTask(name)
 	if err != nil {
+		logger.Audit().Warn(c.Request.Context(), "手动执行任务失败", zap.String("task", name), zap.String("caller", caller), zap.String("client_ip", c.ClientIP()), zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
+	logger.Audit().Info(c.Request.Context(), "手动触发执行任务", zap.String("task", name), zap.String("caller", caller), zap.String("client_ip", c.ClientIP()))
 	c.JSON(http.StatusOK, gin.H{
 		"message": fmt.Sprintf("任务 %s 已手动触发执行", name),
 	})
 }
+
+// scheduleJobRequest 提交一次性任务请求
+// run_at和delay最多指定一个，都不指定时立即执行
+type scheduleJobRequest struct {
+	Type   string          `json:"type" binding:"required"` // 任务类型，必须是已注册的类型
+	Params json.RawMessage `json:"params"`                  // 任务参数
+	RunAt  *time.Time      `json:"run_at"`                  // 计划执行时间，RFC3339格式
+	Delay  string          `json:"delay"`                   // 延迟执行时间，如10m、1h30m
+}
+
+// handleScheduleJob 处理提交一次性任务请求
+// 记录调用方身份作为审计日志
+func handleScheduleJob(c *gin.Context) {
+	var req scheduleJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if req.RunAt != nil && req.Delay != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "run_at和delay不能同时指定",
+		})
+		return
+	}
+
+	runAt := time.Now()
+	if req.RunAt != nil {
+		runAt = *req.RunAt
+	} else if req.Delay != "" {
+		delay, err := time.ParseDuration(req.Delay)
+		if err != nil || delay < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "无效的延迟时间",
+			})
+			return
+		}
+		runAt = runAt.Add(delay)
+	}
+
+	caller := c.GetString("schedulerCaller")
+	job, err := schedulerInstance.ScheduleJob(c.Request.Context(), req.Type, req.Params, runAt, caller)
+	if err != nil {
+		logger.Audit().Warn(c.Request.Context(), "提交一次性任务失败", zap.String("type", req.Type), zap.String("caller", caller), zap.String("client_ip", c.ClientIP()), zap.Error(err))
+		status := http.StatusInternalServerError
+		if errors.Is(err, pkgscheduler.ErrJobTypeNotFound) || errors.Is(err, pkgscheduler.ErrInvalidJobParams) || errors.Is(err, pkgscheduler.ErrJobRunAtOutOfRange) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	logger.Audit().Info(c.Request.Context(), "提交一次性任务", zap.String("job_id", job.ID), zap.String("type", job.Type), zap.Time("run_at", job.RunAt), zap.String("caller", caller), zap.String("client_ip", c.ClientIP()))
+	c.JSON(http.StatusCreated, job)
+}
+
+// handleGetJob 处理获取一次性任务状态请求
+func handleGetJob(c *gin.Context) {
+	job, err := schedulerInstance.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, pkgscheduler.ErrJobNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// handleGetTaskHistory 处理获取任务执行记录请求，limit指定返回的记录数
+func handleGetTaskHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	records, err := schedulerInstance.RunHistory(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, pkgscheduler.ErrHistoryUnavailable) {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// handleMetrics 处理获取Prometheus格式指标请求
+func handleMetrics(c *gin.Context) {
+	c.Header("Content-Type", pkgscheduler.MetricsContentType)
+	c.Status(http.StatusOK)
+	if err := schedulerInstance.WriteMetrics(c.Request.Context(), c.Writer); err != nil {
+		logger.Warn(c.Request.Context(), "输出任务指标失败", zap.Error(err))
+		return
+	}
+	// 清理和备份任务访问对象存储，一并输出熔断器指标
+	if err := resilience.WriteMetrics(c.Writer); err != nil {
+		logger.Warn(c.Request.Context(), "输出熔断器指标失败", zap.Error(err))
+	}
+}
+
+// handleGetAlerts 处理获取告警中的任务SLO告警请求
+func handleGetAlerts(c *gin.Context) {
+	alerts, err := schedulerInstance.Alerts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, alerts)
+}
+
+// handleGetSilences 处理获取静默规则请求
+func handleGetSilences(c *gin.Context) {
+	silences, err := schedulerInstance.Silences(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, silences)
+}
+
+// addSilenceRequest 添加静默规则请求
+// tasks和rules为空时分别匹配所有任务和所有规则，starts_at为空时立即生效
+type addSilenceRequest struct {
+	Tasks    []string   `json:"tasks"`                      // 匹配的任务
+	Rules    []string   `json:"rules"`                      // 匹配的告警规则：consecutive_failures、max_duration、missed_runs
+	StartsAt *time.Time `json:"starts_at"`                  // 生效时间，RFC3339格式
+	EndsAt   time.Time  `json:"ends_at" binding:"required"` // 失效时间，RFC3339格式
+	Comment  string     `json:"comment" binding:"required"` // 静默原因
+}
+
+// handleAddSilence 处理添加静默规则请求
+// 记录调用方身份作为审计日志
+func handleAddSilence(c *gin.Context) {
+	var req addSilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	caller := c.GetString("schedulerCaller")
+	silence := pkgscheduler.Silence{
+		Tasks:     req.Tasks,
+		Rules:     req.Rules,
+		EndsAt:    req.EndsAt,
+		Comment:   req.Comment,
+		CreatedBy: caller,
+	}
+	if req.StartsAt != nil {
+		silence.StartsAt = *req.StartsAt
+	}
+
+	created, err := schedulerInstance.AddSilence(c.Request.Context(), silence)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, pkgscheduler.ErrInvalidSilence) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	logger.Audit().Info(c.Request.Context(), "添加告警静默规则", zap.String("silence_id", created.ID), zap.Strings("tasks", created.Tasks), zap.Strings("rules", created.Rules), zap.Time("ends_at", created.EndsAt), zap.String("caller", caller), zap.String("client_ip", c.ClientIP()))
+	c.JSON(http.StatusCreated, created)
+}
+
+// handleDeleteSilence 处理删除静默规则请求
+// 记录调用方身份作为审计日志
+func handleDeleteSilence(c *gin.Context) {
+	id := c.Param("id")
+	caller := c.GetString("schedulerCaller")
+	if err := schedulerInstance.DeleteSilence(c.Request.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, pkgscheduler.ErrSilenceNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	logger.Audit().Info(c.Request.Context(), "删除告警静默规则", zap.String("silence_id", id), zap.String("caller", caller), zap.String("client_ip", c.ClientIP()))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "静默规则已删除",
+	})
+}
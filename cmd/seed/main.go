@@ -0,0 +1,350 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/model"
+	"app/internal/utils"
+	"app/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// seedOptions 数据填充参数
+type seedOptions struct {
+	Seed            int64  // 随机数种子，相同种子生成相同数据
+	Users           int    // 用户数量
+	FollowsPerUser  int    // 每个用户的平均关注数
+	FriendsPerUser  int    // 每个用户的平均好友数
+	PostsPerUser    int    // 每个用户的平均动态数
+	ImagesPerPost   int    // 每条动态的最大图片数
+	CommentsPerPost int    // 每条动态的平均评论数
+	Days            int    // 数据时间跨度（天）
+	BatchSize       int    // 批量插入大小
+	Password        string // 所有生成用户的登录密码
+}
+
+// 生成内容使用的词库
+var (
+	nicknamePrefixes = []string{"快乐的", "安静的", "勇敢的", "慵懒的", "好奇的", "温柔的", "机智的", "认真的"}
+	nicknameSuffixes = []string{"小猫", "旅人", "程序员", "摄影师", "咖啡", "读书人", "跑者", "厨师"}
+	postSentences    = []string{
+		"今天天气不错，出去走了走。",
+		"分享一张最近拍的照片。",
+		"终于把这周的工作做完了！",
+		"推荐一本最近在读的书。",
+		"周末去爬山，风景很美。",
+		"尝试了一道新菜，味道还可以。",
+		"记录一下今天的心情。",
+		"有没有人一起去看展？",
+	}
+	commentSentences = []string{"赞！", "好看", "羡慕了", "哈哈哈", "说得对", "下次带上我", "在哪里拍的？", "加油！"}
+)
+
+func main() {
+	opts := parseFlags()
+
+	// 初始化配置
+	err := config.Init()
+	if err != nil {
+		fmt.Printf("配置初始化失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 初始化数据库连接
+	err = database.Init()
+	if err != nil {
+		log.Fatalf("数据库连接失败: %v", err)
+	}
+	defer database.Close()
+
+	// 获取数据库连接
+	db := database.GetDB()
+	if db == nil {
+		log.Fatal("获取数据库连接失败")
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	// 以当天零点为基准生成时间，保证同一天内多次运行结果一致
+	now := time.Now().Truncate(24 * time.Hour)
+
+	log.Printf("开始填充数据，种子: %d，用户数: %d", opts.Seed, opts.Users)
+
+	userIDs, err := seedUsers(db, rng, opts, now)
+	if err != nil {
+		log.Fatalf("生成用户失败: %v", err)
+	}
+	log.Printf("已生成用户: %d", len(userIDs))
+
+	follows, err := seedFollowers(db, rng, opts, userIDs, now)
+	if err != nil {
+		log.Fatalf("生成关注关系失败: %v", err)
+	}
+	log.Printf("已生成关注关系: %d", follows)
+
+	friends, err := seedFriends(db, rng, opts, userIDs, now)
+	if err != nil {
+		log.Fatalf("生成好友关系失败: %v", err)
+	}
+	log.Printf("已生成好友关系: %d", friends)
+
+	posts, images, comments, err := seedPosts(db, rng, opts, userIDs, now)
+	if err != nil {
+		log.Fatalf("生成动态失败: %v", err)
+	}
+	log.Printf("已生成动态: %d，图片: %d，评论: %d", posts, images, comments)
+
+	log.Println("数据填充完成")
+}
+
+// parseFlags 解析命令行参数
+func parseFlags() seedOptions {
+	var opts seedOptions
+	flag.Int64Var(&opts.Seed, "seed", 1, "随机数种子，相同种子生成相同数据")
+	flag.IntVar(&opts.Users, "users", 100, "用户数量")
+	flag.IntVar(&opts.FollowsPerUser, "follows", 20, "每个用户的平均关注数")
+	flag.IntVar(&opts.FriendsPerUser, "friends", 5, "每个用户的平均好友数")
+	flag.IntVar(&opts.PostsPerUser, "posts", 10, "每个用户的平均动态数")
+	flag.IntVar(&opts.ImagesPerPost, "images", 3, "每条动态的最大图片数")
+	flag.IntVar(&opts.CommentsPerPost, "comments", 5, "每条动态的平均评论数")
+	flag.IntVar(&opts.Days, "days", 30, "数据时间跨度（天）")
+	flag.IntVar(&opts.BatchSize, "batch", 500, "批量插入大小")
+	flag.StringVar(&opts.Password, "password", "123456", "所有生成用户的登录密码")
+	flag.Parse()
+
+	if opts.Users <= 0 {
+		log.Fatal("用户数量必须大于0")
+	}
+	if opts.Days <= 0 {
+		opts.Days = 1
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	return opts
+}
+
+// randomTime 生成时间跨度内的随机时间
+func randomTime(rng *rand.Rand, now time.Time, days int) time.Time {
+	return now.Add(-time.Duration(rng.Int63n(int64(days) * int64(24*time.Hour))))
+}
+
+// randomCount 生成以avg为平均值的随机数量
+func randomCount(rng *rand.Rand, avg int) int {
+	if avg <= 0 {
+		return 0
+	}
+	return rng.Intn(avg*2 + 1)
+}
+
+// seedUsers 生成用户，返回用户ID列表
+func seedUsers(db *gorm.DB, rng *rand.Rand, opts seedOptions, now time.Time) ([]uint, error) {
+	password := utils.HashPassword(opts.Password)
+	users := make([]model.User, 0, opts.Users)
+	for i := 0; i < opts.Users; i++ {
+		createdAt := randomTime(rng, now, opts.Days)
+		lastActiveAt := createdAt.Add(time.Duration(rng.Int63n(int64(now.Sub(createdAt)) + 1)))
+		users = append(users, model.User{
+			Username:     fmt.Sprintf("seed_user_%d_%d", opts.Seed, i+1),
+			Password:     password,
+			Mobile:       fmt.Sprintf("199%08d", (opts.Seed*1000003+int64(i))%100000000),
+			Nickname:     nicknamePrefixes[rng.Intn(len(nicknamePrefixes))] + nicknameSuffixes[rng.Intn(len(nicknameSuffixes))],
+			Status:       constant.UserStatusNormal,
+			LastActiveAt: &lastActiveAt,
+			CreatedAt:    createdAt,
+			UpdatedAt:    createdAt,
+		})
+	}
+
+	if err := db.CreateInBatches(&users, opts.BatchSize).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(users))
+	for i := range users {
+		ids[i] = users[i].ID
+	}
+	return ids, nil
+}
+
+// pickOthers 从用户列表中随机挑选不重复且不等于自身的用户
+func pickOthers(rng *rand.Rand, userIDs []uint, self int, count int) []uint {
+	if count > len(userIDs)-1 {
+		count = len(userIDs) - 1
+	}
+	picked := make(map[int]bool, count)
+	result := make([]uint, 0, count)
+	for len(result) < count {
+		idx := rng.Intn(len(userIDs))
+		if idx == self || picked[idx] {
+			continue
+		}
+		picked[idx] = true
+		result = append(result, userIDs[idx])
+	}
+	return result
+}
+
+// seedFollowers 生成关注关系
+func seedFollowers(db *gorm.DB, rng *rand.Rand, opts seedOptions, userIDs []uint, now time.Time) (int, error) {
+	var follows []model.UserFollower
+	for i, userID := range userIDs {
+		for _, targetID := range pickOthers(rng, userIDs, i, randomCount(rng, opts.FollowsPerUser)) {
+			createdAt := randomTime(rng, now, opts.Days)
+			follows = append(follows, model.UserFollower{
+				UserID:    userID,
+				TargetID:  targetID,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			})
+		}
+	}
+	if len(follows) == 0 {
+		return 0, nil
+	}
+	return len(follows), db.CreateInBatches(&follows, opts.BatchSize).Error
+}
+
+// seedFriends 生成已确认的好友关系，每个关系按双记录模式写入两条记录
+func seedFriends(db *gorm.DB, rng *rand.Rand, opts seedOptions, userIDs []uint, now time.Time) (int, error) {
+	seen := make(map[[2]uint]bool)
+	var friends []model.UserFriend
+	for i, userID := range userIDs {
+		// 每个关系由双方共同计入，因此每个用户只发起一半
+		for _, targetID := range pickOthers(rng, userIDs, i, randomCount(rng, opts.FriendsPerUser/2)) {
+			pair := [2]uint{userID, targetID}
+			if targetID < userID {
+				pair = [2]uint{targetID, userID}
+			}
+			if seen[pair] {
+				continue
+			}
+			seen[pair] = true
+
+			createdAt := randomTime(rng, now, opts.Days)
+			friends = append(friends,
+				model.UserFriend{
+					UserID:    userID,
+					TargetID:  targetID,
+					Status:    int(constant.FriendStatusConfirmed),
+					Direction: 0, // 发起方
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				},
+				model.UserFriend{
+					UserID:    targetID,
+					TargetID:  userID,
+					Status:    int(constant.FriendStatusConfirmed),
+					Direction: 1, // 接收方
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				},
+			)
+		}
+	}
+	if len(friends) == 0 {
+		return 0, nil
+	}
+	return len(seen), db.CreateInBatches(&friends, opts.BatchSize).Error
+}
+
+// seedPosts 生成动态及其图片和评论，返回动态数、图片数和评论数
+func seedPosts(db *gorm.DB, rng *rand.Rand, opts seedOptions, userIDs []uint, now time.Time) (int, int, int, error) {
+	var posts []model.Post
+	for _, userID := range userIDs {
+		for j := randomCount(rng, opts.PostsPerUser); j > 0; j-- {
+			createdAt := randomTime(rng, now, opts.Days)
+			posts = append(posts, model.Post{
+				UserID:     userID,
+				Content:    postSentences[rng.Intn(len(postSentences))],
+				Visibility: randomVisibility(rng),
+				Likes:      rng.Intn(100),
+				Views:      int64(rng.Intn(1000)),
+				CreatedAt:  createdAt,
+				UpdatedAt:  createdAt,
+			})
+		}
+	}
+	if len(posts) == 0 {
+		return 0, 0, 0, nil
+	}
+
+	// 先生成评论数，保证动态的评论计数与评论记录一致
+	commentCounts := make([]int, len(posts))
+	for i := range posts {
+		commentCounts[i] = randomCount(rng, opts.CommentsPerPost)
+		posts[i].Comments = commentCounts[i]
+	}
+	if err := db.CreateInBatches(&posts, opts.BatchSize).Error; err != nil {
+		return 0, 0, 0, err
+	}
+
+	var images []model.PostImage
+	var comments []model.PostComment
+	for i, post := range posts {
+		imageCount := 0
+		if opts.ImagesPerPost > 0 {
+			imageCount = rng.Intn(opts.ImagesPerPost + 1)
+		}
+		for k := 0; k < imageCount; k++ {
+			objectKey := fmt.Sprintf("seed/%d/%d/%d.jpg", opts.Seed, post.ID, k)
+			images = append(images, model.PostImage{
+				PostID:      post.ID,
+				UserID:      post.UserID,
+				ObjectKey:   objectKey,
+				URL:         "https://example.com/" + objectKey,
+				Bucket:      "seed",
+				Size:        int64(50000 + rng.Intn(500000)),
+				Width:       640 + rng.Intn(1280),
+				Height:      480 + rng.Intn(960),
+				ContentType: "image/jpeg",
+				CreatedAt:   post.CreatedAt,
+				UpdatedAt:   post.CreatedAt,
+			})
+		}
+
+		for k := 0; k < commentCounts[i]; k++ {
+			createdAt := post.CreatedAt.Add(time.Duration(rng.Int63n(int64(now.Sub(post.CreatedAt)) + 1)))
+			comments = append(comments, model.PostComment{
+				PostID:    post.ID,
+				UserID:    userIDs[rng.Intn(len(userIDs))],
+				Content:   commentSentences[rng.Intn(len(commentSentences))],
+				Likes:     rng.Intn(20),
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			})
+		}
+	}
+
+	if len(images) > 0 {
+		if err := db.CreateInBatches(&images, opts.BatchSize).Error; err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	if len(comments) > 0 {
+		if err := db.CreateInBatches(&comments, opts.BatchSize).Error; err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	return len(posts), len(images), len(comments), nil
+}
+
+// randomVisibility 生成动态可见性，大部分为公开
+func randomVisibility(rng *rand.Rand) int {
+	switch n := rng.Intn(10); {
+	case n < 7:
+		return int(constant.VisibilityPublic)
+	case n < 9:
+		return int(constant.VisibilityFriends)
+	default:
+		return int(constant.VisibilityPrivate)
+	}
+}
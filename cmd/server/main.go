@@ -13,10 +13,15 @@ import (
 	"time"
 
 	"app/config"
+	"app/internal/container"
+	"app/internal/jwtkey"
 	"app/internal/routes"
+	"app/internal/service"
 	"app/internal/utils"
 	"app/pkg/database"
+	"app/pkg/jwt"
 	"app/pkg/logger"
+	"app/pkg/poolmonitor"
 	"app/pkg/redis"
 	"app/pkg/validation"
 
@@ -66,18 +71,49 @@ func initComponents() {
 		os.Exit(1)
 	}
 
+	// 监听配置文件变更，热更新跨域和日志脱敏等配置
+	config.Watch(func(file string, err error) {
+		if err != nil {
+			logger.Error(context.Background(), "重新加载配置失败", logger.String("file", file), logger.Err(err))
+			return
+		}
+		logger.Info(context.Background(), "配置文件已更新，CORS和日志脱敏配置已重新加载", logger.String("file", file))
+	})
+
 	// 初始化验证器
 	if err := validation.Init(); err != nil {
 		fmt.Printf("验证器初始化失败: %v\n", err)
 		os.Exit(1)
 	}
+
+	// 签发令牌时使用管理后台轮换后的密钥，并在启动时校验密钥配置
+	jwt.SetKeySelector(jwtkey.Current)
+	if _, err := jwt.Keys(); err != nil {
+		fmt.Printf("JWT密钥配置错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 校验各类型验证码的短信模板配置
+	if err := service.ValidateVerificationTemplates(); err != nil {
+		fmt.Printf("验证码短信模板配置错误: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 启动数据库和Redis连接池监控
+	poolmonitor.Start()
+
+	// 启动短信发送队列的消费者
+	container.GetInstance().GetSMSQueue().Start()
 }
 
 // setupHTTPServer 配置并启动HTTP服务器
 // 返回服务器实例以便后续优雅关闭
 func setupHTTPServer(cfg *config.Config) *http.Server {
-	// 初始化Gin引擎
-	router := gin.Default()
+	// 初始化Gin引擎，异常恢复由路由中的Recovery中间件处理
+	router := gin.New()
+	router.Use(gin.Logger())
+	// 直接以gin.Context作为上下文传递时，截止时间和取消信号沿用请求上下文
+	router.ContextWithFallback = true
 
 	// 设置路由
 	routes.SetupRouter(router)
@@ -85,10 +121,12 @@ func setupHTTPServer(cfg *config.Config) *http.Server {
 	// 准备服务器地址
 	serverAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 
-	// 创建HTTP服务器
+	// 创建HTTP服务器，读写超时限制慢客户端占用连接的时间
 	srv := &http.Server{
-		Addr:    serverAddr,
-		Handler: router,
+		Addr:         serverAddr,
+		Handler:      router,
+		ReadTimeout:  config.ParseDuration(cfg.Server.ReadTimeout, 30*time.Second),
+		WriteTimeout: config.ParseDuration(cfg.Server.WriteTimeout, 30*time.Second),
 	}
 
 	// 启动HTTP服务器（非阻塞）
@@ -125,6 +163,9 @@ func setupGracefulShutdown(srv *http.Server) {
 	}
 	fmt.Println("HTTP服务已停止接受新请求")
 
+	// 停止短信发送队列的消费者，未发送的短信留在队列中
+	container.GetInstance().GetSMSQueue().Stop()
+
 	// 按照依赖关系的相反顺序关闭资源
 	utils.CloseResources()
 
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 	"github.com/subosito/gotenv"
 )
@@ -13,30 +15,131 @@ import (
 
 // Config 应用配置结构体
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	Scheduler SchedulerConfig `mapstructure:"scheduler"`
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Redis     RedisConfig     `mapstructure:"redis"`
-	JWT       JWTConfig       `mapstructure:"jwt"`
-	Logger    LoggerConfig    `mapstructure:"logger"`
-	SMS       SMSConfig       `mapstructure:"sms"`
-	COS       COSConfig       `mapstructure:"cos"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Scheduler   SchedulerConfig   `mapstructure:"scheduler"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Logger      LoggerConfig      `mapstructure:"logger"`
+	SMS         SMSConfig         `mapstructure:"sms"`
+	COS         COSConfig         `mapstructure:"cos"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Account     AccountConfig     `mapstructure:"account"`
+	AntiSpam    AntiSpamConfig    `mapstructure:"anti_spam"`
+	Content     ContentConfig     `mapstructure:"content"`
+	CDN         CDNConfig         `mapstructure:"cdn"`
+	Geocode     GeocodeConfig     `mapstructure:"geocode"`
+	GeoIP       GeoIPConfig       `mapstructure:"geoip"`
+	Share       ShareConfig       `mapstructure:"share"`
+	PublicID    PublicIDConfig    `mapstructure:"public_id"`
+	ErrTrack    ErrTrackConfig    `mapstructure:"errtrack"`
+	CORS        CORSConfig        `mapstructure:"cors"`
+	Security    SecurityConfig    `mapstructure:"security"`
+	APISign     APISignConfig     `mapstructure:"api_sign"`
+	FeatureFlag FeatureFlagConfig `mapstructure:"feature_flag"`
+	Backup      BackupConfig      `mapstructure:"backup"`
+	Webhook     WebhookConfig     `mapstructure:"webhook"`
+	Push        PushConfig        `mapstructure:"push"`
+	Translate   TranslateConfig   `mapstructure:"translate"`
+	PoolMonitor PoolMonitorConfig `mapstructure:"pool_monitor"`
+	Debug       DebugConfig       `mapstructure:"debug"`
+	Image       ImageConfig       `mapstructure:"image"`
+	LinkPreview LinkPreviewConfig `mapstructure:"link_preview"`
+	Feed        FeedConfig        `mapstructure:"feed"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port         int    `mapstructure:"port"`
-	Host         string `mapstructure:"host"`
-	ReadTimeout  string `mapstructure:"read_timeout"`
-	WriteTimeout string `mapstructure:"write_timeout"`
+	Port           int                         `mapstructure:"port"`
+	Host           string                      `mapstructure:"host"`
+	ReadTimeout    string                      `mapstructure:"read_timeout"`
+	WriteTimeout   string                      `mapstructure:"write_timeout"`
+	RequestTimeout string                      `mapstructure:"request_timeout"` // 默认请求处理超时，超时后取消请求上下文
+	MaxBodySize    int64                       `mapstructure:"max_body_size"`   // 默认请求体大小上限（字节）
+	Limits         map[string]RouteLimitConfig `mapstructure:"limits"`          // 按路由组覆盖的请求限制，key为路由组名称
+	Compression    CompressionConfig           `mapstructure:"compression"`     // 响应压缩配置
+}
+
+// CompressionConfig 响应压缩配置
+// 客户端支持时按br、gzip的优先顺序压缩响应，只压缩允许的内容类型且达到最小大小的响应
+type CompressionConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`       // 是否启用响应压缩
+	MinSize      int      `mapstructure:"min_size"`      // 压缩的最小响应体大小（字节），流式输出的响应不受限制
+	ContentTypes []string `mapstructure:"content_types"` // 允许压缩的内容类型，不含charset等参数
 }
 
-// SchedulerConfig 定时程序配置
+// RouteLimitConfig 路由组的请求限制配置，未配置的项沿用默认值
+type RouteLimitConfig struct {
+	Timeout     string `mapstructure:"timeout"`       // 请求处理超时
+	MaxBodySize int64  `mapstructure:"max_body_size"` // 请求体大小上限（字节）
+}
+
+// SchedulerConfig 定时程序配置，与Server配置相互独立，定时程序只读取本节和公共的数据库、Redis等配置
 type SchedulerConfig struct {
-	Port         int    `mapstructure:"port"`
-	Host         string `mapstructure:"host"`
-	ReadTimeout  string `mapstructure:"read_timeout"`
-	WriteTimeout string `mapstructure:"write_timeout"`
+	Port           int                            `mapstructure:"port"`
+	Host           string                         `mapstructure:"host"`
+	ReadTimeout    string                         `mapstructure:"read_timeout"`
+	WriteTimeout   string                         `mapstructure:"write_timeout"`
+	DrainTimeout   string                         `mapstructure:"drain_timeout"`   // 停止时等待运行中任务完成的最长时间，超时后取消任务
+	Auth           SchedulerAuthConfig            `mapstructure:"auth"`            // 任务管理接口认证配置
+	LeaderElection SchedulerLeaderElectionConfig  `mapstructure:"leader_election"` // 选主模式配置
+	Alert          SchedulerAlertConfig           `mapstructure:"alert"`           // 任务SLO告警配置
+	TaskDefaults   SchedulerTaskDefaultsConfig    `mapstructure:"task_defaults"`   // 任务未指定锁超时时间和重试策略时使用的默认值
+	Tasks          map[string]SchedulerTaskConfig `mapstructure:"tasks"`           // 按任务名称覆盖任务配置，key为任务名称
+}
+
+// SchedulerTaskDefaultsConfig 定时任务默认配置，任务本身和scheduler.tasks均未指定时使用
+type SchedulerTaskDefaultsConfig struct {
+	LockTimeout   string `mapstructure:"lock_timeout"`   // 分布式锁超时时间
+	RetryCount    int    `mapstructure:"retry_count"`    // 执行失败后的重试次数，超时不重试
+	RetryInterval string `mapstructure:"retry_interval"` // 重试间隔
+}
+
+// SchedulerTaskConfig 单个定时任务的配置，未配置的项沿用任务本身的设置
+type SchedulerTaskConfig struct {
+	Enabled       *bool  `mapstructure:"enabled"`        // 是否启用任务，未配置时启用
+	LockTimeout   string `mapstructure:"lock_timeout"`   // 分布式锁超时时间
+	RetryCount    *int   `mapstructure:"retry_count"`    // 执行失败后的重试次数，超时不重试
+	RetryInterval string `mapstructure:"retry_interval"` // 重试间隔
+}
+
+// SchedulerAlertConfig 定时任务SLO告警配置
+// 按任务配置的连续失败次数、执行耗时和错过执行次数检查任务是否达标，告警和恢复时发送通知
+type SchedulerAlertConfig struct {
+	Enabled   bool             `mapstructure:"enabled"`   // 是否启用告警检查
+	Interval  string           `mapstructure:"interval"`  // 检查间隔
+	Notifiers []NotifierConfig `mapstructure:"notifiers"` // 通知渠道，可配置多个
+}
+
+// NotifierConfig 告警通知渠道配置
+type NotifierConfig struct {
+	Type    string `mapstructure:"type"`               // 渠道类型：webhook-通用Webhook（兼容Alertmanager格式），dingtalk-钉钉群机器人，wecom-企业微信群机器人
+	URL     string `mapstructure:"url" redact:"query"` // 接收地址或群机器人Webhook地址，为空时不发送；钉钉和企业微信的地址在查询参数中携带凭据，有效配置中隐藏参数值
+	Secret  string `mapstructure:"secret"`             // 钉钉群机器人加签密钥，未开启加签时为空
+	Timeout string `mapstructure:"timeout"`            // 发送请求超时时间
+}
+
+// SchedulerLeaderElectionConfig 定时程序选主模式配置
+// 启用后各节点竞争主节点租约，只有主节点执行定时任务，不再逐个任务加分布式锁
+type SchedulerLeaderElectionConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`   // 是否启用选主模式
+	NodeID   string `mapstructure:"node_id"`   // 节点标识，为空时使用主机名和进程号
+	LeaseTTL string `mapstructure:"lease_ttl"` // 主节点租约有效期，主节点异常退出后最长经过该时间由其他节点接管
+}
+
+// SchedulerAuthConfig 定时程序管理接口认证配置
+// 支持静态访问令牌和JWT两种方式，JWT用户按ID分配角色
+type SchedulerAuthConfig struct {
+	Tokens          []SchedulerTokenConfig `mapstructure:"tokens"`            // 静态访问令牌列表
+	ViewerUserIDs   []uint                 `mapstructure:"viewer_user_ids"`   // 拥有只读权限的用户ID列表
+	OperatorUserIDs []uint                 `mapstructure:"operator_user_ids"` // 拥有执行任务权限的用户ID列表
+}
+
+// SchedulerTokenConfig 定时程序静态访问令牌配置
+type SchedulerTokenConfig struct {
+	Name  string `mapstructure:"name"`  // 令牌名称，用于审计日志标识调用方
+	Token string `mapstructure:"token"` // 令牌值
+	Role  string `mapstructure:"role"`  // 角色：viewer-只读，operator-可执行任务
 }
 
 // DatabaseConfig 数据库配置
@@ -49,6 +152,8 @@ type DatabaseConfig struct {
 	MaxConnections  int    `mapstructure:"max_connections"`
 	ConnMaxLifetime string `mapstructure:"conn_max_lifetime"`
 	ConnMaxIdleTime string `mapstructure:"conn_max_idle_time"`
+
+	Retry ConnectRetryConfig `mapstructure:"retry"` // 连接重试配置
 }
 
 // RedisConfig Redis配置
@@ -62,13 +167,42 @@ type RedisConfig struct {
 	DialTimeout  string `mapstructure:"dial_timeout"`
 	ReadTimeout  string `mapstructure:"read_timeout"`
 	WriteTimeout string `mapstructure:"write_timeout"`
+
+	Mode             string   `mapstructure:"mode"`              // 部署模式：single-单节点，cluster-集群，sentinel-哨兵
+	Addrs            []string `mapstructure:"addrs"`             // 集群节点或哨兵地址列表
+	MasterName       string   `mapstructure:"master_name"`       // 哨兵模式下的主节点名称
+	SentinelPassword string   `mapstructure:"sentinel_password"` // 哨兵认证密码
+	KeyPrefix        string   `mapstructure:"key_prefix"`        // 键的环境前缀，多个环境共用同一Redis时用于隔离
+
+	Retry ConnectRetryConfig `mapstructure:"retry"` // 连接重试配置
+}
+
+// ConnectRetryConfig 依赖服务连接重试配置
+// 启动时按指数退避等待依赖就绪，运行期间定期检查连接，持续失败时重建连接
+type ConnectRetryConfig struct {
+	InitialInterval     string `mapstructure:"initial_interval"`      // 首次重试间隔，之后每次翻倍
+	MaxInterval         string `mapstructure:"max_interval"`          // 最大重试间隔
+	MaxWait             string `mapstructure:"max_wait"`              // 启动时等待依赖就绪的最长时间，超过后初始化失败，为0时不重试
+	HealthCheckInterval string `mapstructure:"health_check_interval"` // 运行期间检查连接的间隔，为0时不检查
+	ReconnectAfter      int    `mapstructure:"reconnect_after"`       // 连续检查失败达到该次数后重建连接
 }
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	SecretKey   string `mapstructure:"secret_key"`
-	ExpiresTime string `mapstructure:"expires_time"`
-	Issuer      string `mapstructure:"issuer"`
+	SecretKey    string         `mapstructure:"secret_key"`
+	ExpiresTime  string         `mapstructure:"expires_time"`
+	Issuer       string         `mapstructure:"issuer"`
+	CurrentKeyID string         `mapstructure:"current_key_id"` // 默认用于签发令牌的密钥ID，为空时使用secret_key
+	Keys         []JWTKeyConfig `mapstructure:"keys"`           // 轮换密钥列表，已停用的密钥保留在列表中直到其签发的令牌全部过期
+}
+
+// JWTKeyConfig JWT签名密钥配置
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"id"`               // 密钥ID，写入令牌头部的kid字段
+	Algorithm      string `mapstructure:"algorithm"`        // 签名算法：HS256-对称密钥，RS256-RSA非对称密钥，默认HS256
+	Secret         string `mapstructure:"secret"`           // HS256的对称密钥
+	PrivateKeyFile string `mapstructure:"private_key_file"` // RS256的PEM格式私钥文件，只用于校验的密钥可不配置
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // RS256的PEM格式公钥文件
 }
 
 // LoggerConfig 日志配置
@@ -84,11 +218,41 @@ type LoggerConfig struct {
 	EnableStacktrace bool   `mapstructure:"enable_stacktrace"` // 是否启用调用栈
 	StacktraceLevel  string `mapstructure:"stacktrace_level"`  // 记录调用栈的最低日志级别
 	StacktraceDepth  int    `mapstructure:"stacktrace_depth"`  // 调用栈深度
+
+	Redaction LoggerRedactionConfig         `mapstructure:"redaction"` // 请求日志脱敏配置
+	Outputs   map[string]LoggerOutputConfig `mapstructure:"outputs"`   // 按名称配置独立输出的日志（access-请求日志，audit-审计日志，analytics-分析日志），key为日志名称
+}
+
+// LoggerOutputConfig 独立输出的日志配置，未配置的项沿用应用日志的配置
+type LoggerOutputConfig struct {
+	Level      string `mapstructure:"level"`
+	Format     string `mapstructure:"format"`
+	OutputPath string `mapstructure:"output_path"` // 日志输出路径，必填且不能与其他日志相同
+	MaxSize    int    `mapstructure:"max_size"`
+	MaxAge     int    `mapstructure:"max_age"`
+	MaxBackups int    `mapstructure:"max_backups"`
+	Compress   *bool  `mapstructure:"compress"`
+	Console    *bool  `mapstructure:"console"` // 是否同时输出到控制台
+}
+
+// LoggerRedactionConfig 请求日志脱敏配置，配置文件修改后热更新，对新请求立即生效
+type LoggerRedactionConfig struct {
+	Fields  []string `mapstructure:"fields"`  // 查询参数和请求、响应体中需要脱敏的字段，字段名包含其中任一项即脱敏（不区分大小写），为空时使用内置列表
+	Headers []string `mapstructure:"headers"` // 需要脱敏的请求头（不区分大小写），为空时使用内置列表
 }
 
 // SMSConfig 短信服务配置
 type SMSConfig struct {
-	Aliyun AliyunSMSConfig `mapstructure:"aliyun"`
+	Aliyun    AliyunSMSConfig              `mapstructure:"aliyun"`
+	Templates map[string]SMSTemplateConfig `mapstructure:"templates"` // 验证码短信模板，key为验证码类型
+}
+
+// SMSTemplateConfig 验证码短信模板配置
+// 模板参数和短信内容中的{code}替换为验证码，{minutes}替换为验证码有效分钟数
+type SMSTemplateConfig struct {
+	Code    string            `mapstructure:"code"`    // 短信模板代码，为空时不支持发送该类型验证码
+	Params  map[string]string `mapstructure:"params"`  // 模板参数，key为模板变量名
+	Content string            `mapstructure:"content"` // 短信内容，用于短信记录
 }
 
 // AliyunSMSConfig 阿里云短信服务配置
@@ -97,12 +261,34 @@ type AliyunSMSConfig struct {
 	AccessKeySecret string            `mapstructure:"access_key_secret"`
 	Endpoint        string            `mapstructure:"endpoint"`
 	SignName        string            `mapstructure:"sign_name"`
-	Templates       map[string]string `mapstructure:"templates"` // 短信模板代码映射
+	Templates       map[string]string `mapstructure:"templates"`  // 短信模板代码映射
+	UnitPrice       float64           `mapstructure:"unit_price"` // 每条短信单价（元），用于统计短信费用
 }
 
 // COSConfig 对象存储服务配置
 type COSConfig struct {
-	Tencent TencentCOSConfig `mapstructure:"tencent"`
+	Tencent    TencentCOSConfig    `mapstructure:"tencent"`
+	Resilience COSResilienceConfig `mapstructure:"resilience"` // 超时、重试和熔断配置
+	URL        COSURLConfig        `mapstructure:"url"`        // 保存到数据库的文件地址配置
+}
+
+// COSURLConfig 保存到数据库的文件地址配置
+// 公有读的存储桶保存永久URL；私有读的存储桶保存带有效期的预签名URL，由定时任务在过期前重新生成
+type COSURLConfig struct {
+	PrivateBuckets []string `mapstructure:"private_buckets"` // 私有读的存储桶名称，默认存储桶使用default_bucket的名称
+	PresignExpire  string   `mapstructure:"presign_expire"`  // 预签名URL的有效期
+	RefreshBefore  string   `mapstructure:"refresh_before"`  // 预签名URL在过期前多久重新生成，需大于刷新任务的执行间隔
+}
+
+// COSResilienceConfig 对象存储请求的超时、重试和熔断配置
+// 只有幂等操作在失败后重试；连续失败达到阈值后熔断，熔断期间请求直接失败
+type COSResilienceConfig struct {
+	Timeout          string `mapstructure:"timeout"`           // 单次请求超时，不含上传和下载
+	TransferTimeout  string `mapstructure:"transfer_timeout"`  // 单次上传或下载请求超时，为空时不限制
+	MaxRetries       int    `mapstructure:"max_retries"`       // 幂等操作失败后的最大重试次数，为0时不重试
+	RetryInterval    string `mapstructure:"retry_interval"`    // 首次重试间隔，之后每次翻倍
+	FailureThreshold int    `mapstructure:"failure_threshold"` // 连续失败多少次后熔断
+	OpenTimeout      string `mapstructure:"open_timeout"`      // 熔断持续时间，到期后放行一个试探请求
 }
 
 // TencentCOSConfig 腾讯云对象存储服务配置
@@ -115,7 +301,229 @@ type TencentCOSConfig struct {
 	UseDomainMap  bool              `mapstructure:"use_domain_map"` // 是否使用自定义域名映射
 }
 
-var config *Config
+// CDNConfig CDN访问配置，key为存储桶名称，未配置的存储桶直接返回源地址
+type CDNConfig struct {
+	Buckets map[string]CDNBucketConfig `mapstructure:"buckets"`
+}
+
+// CDNBucketConfig 单个存储桶的CDN配置
+type CDNBucketConfig struct {
+	Domain    string `mapstructure:"domain"`     // CDN加速域名，为空时沿用源地址的域名
+	Private   bool   `mapstructure:"private"`    // 是否为私有桶，私有桶的地址需要签名鉴权
+	SignKey   string `mapstructure:"sign_key"`   // CDN鉴权密钥（TypeA鉴权）
+	SignParam string `mapstructure:"sign_param"` // 鉴权参数名，默认sign
+	Expire    string `mapstructure:"expire"`     // 签名有效期，需与CDN控制台配置一致
+	CacheBust bool   `mapstructure:"cache_bust"` // 是否按内容摘要追加版本参数，内容变化后绕过CDN缓存
+}
+
+// GeocodeConfig 逆地理编码服务配置
+type GeocodeConfig struct {
+	Provider string `mapstructure:"provider"`  // 服务提供商：tencent-腾讯位置服务，amap-高德地图
+	Key      string `mapstructure:"key"`       // 服务密钥
+	Timeout  string `mapstructure:"timeout"`   // 请求超时时间
+	CacheTTL string `mapstructure:"cache_ttl"` // 解析结果在Redis中的缓存时间
+}
+
+// GeoIPConfig IP归属地解析配置
+type GeoIPConfig struct {
+	MMDBPath string `mapstructure:"mmdb_path"` // 本地MaxMind DB格式数据库文件路径，如GeoLite2-City.mmdb，优先使用
+	Provider string `mapstructure:"provider"`  // 本地数据库未命中时使用的在线服务：tencent-腾讯位置服务
+	Key      string `mapstructure:"key"`       // 在线服务密钥
+	Timeout  string `mapstructure:"timeout"`   // 在线服务请求超时时间
+	CacheTTL string `mapstructure:"cache_ttl"` // 在线服务解析结果在Redis中的缓存时间
+}
+
+// TranslateConfig 翻译服务配置
+type TranslateConfig struct {
+	Provider  string `mapstructure:"provider"`   // 服务提供商：tencent-腾讯云机器翻译，deepl-DeepL
+	SecretID  string `mapstructure:"secret_id"`  // 腾讯云SecretId，DeepL无需配置
+	SecretKey string `mapstructure:"secret_key"` // 腾讯云SecretKey或DeepL认证密钥
+	Region    string `mapstructure:"region"`     // 腾讯云地域
+	Timeout   string `mapstructure:"timeout"`    // 请求超时时间
+	CacheTTL  string `mapstructure:"cache_ttl"`  // 译文在Redis中的缓存时间
+}
+
+// LinkPreviewConfig 链接预览配置，抓取动态中第一个链接的OpenGraph元数据
+type LinkPreviewConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`       // 是否生成链接预览
+	Timeout     string `mapstructure:"timeout"`       // 抓取网页的总超时，包含跳转
+	MaxBodySize int64  `mapstructure:"max_body_size"` // 读取的网页大小上限（字节）
+	CacheTTL    string `mapstructure:"cache_ttl"`     // 预览结果在Redis中的缓存时间
+	UserAgent   string `mapstructure:"user_agent"`    // 抓取网页时的User-Agent
+}
+
+// PoolMonitorConfig 连接池监控配置，定期采样数据库和Redis连接池状态
+type PoolMonitorConfig struct {
+	Interval            string  `mapstructure:"interval"`             // 采样间隔，为空或0时不采样
+	Retention           int     `mapstructure:"retention"`            // 保留的采样点数量
+	SaturationThreshold float64 `mapstructure:"saturation_threshold"` // 使用中的连接数占连接池上限的比例达到该值时告警
+	WaitThreshold       int64   `mapstructure:"wait_threshold"`       // 两次采样之间等待连接的次数增长达到该值时告警
+}
+
+// DebugConfig 运行时调试接口配置，API服务和定时程序共用
+type DebugConfig struct {
+	Enabled bool `mapstructure:"enabled"` // 是否开启pprof、GC统计、goroutine堆栈和构建信息接口
+}
+
+// ImageConfig 图片处理配置
+type ImageConfig struct {
+	// 按上传方式配置上传到COS前是否去除EXIF等元数据，键为上传方式（upload-经服务器上传，presign-客户端直传），未配置的上传方式默认去除
+	StripMetadata map[string]bool `mapstructure:"strip_metadata"`
+}
+
+// PushConfig 移动推送配置，iOS设备使用APNs，Android设备使用FCM
+type PushConfig struct {
+	Timeout string     `mapstructure:"timeout"` // 请求超时时间
+	APNs    APNsConfig `mapstructure:"apns"`
+	FCM     FCMConfig  `mapstructure:"fcm"`
+}
+
+// APNsConfig 苹果推送服务配置，使用基于令牌的认证
+type APNsConfig struct {
+	KeyFile  string `mapstructure:"key_file"`  // .p8私钥文件路径，为空时不启用APNs
+	KeyID    string `mapstructure:"key_id"`    // 私钥ID
+	TeamID   string `mapstructure:"team_id"`   // 开发者团队ID
+	BundleID string `mapstructure:"bundle_id"` // 应用的Bundle ID，作为推送主题
+	Sandbox  bool   `mapstructure:"sandbox"`   // 是否使用开发环境
+}
+
+// FCMConfig Firebase云消息配置
+type FCMConfig struct {
+	CredentialsFile string `mapstructure:"credentials_file"` // 服务账号JSON密钥文件路径，为空时不启用FCM
+}
+
+// ShareConfig 分享配置
+type ShareConfig struct {
+	DeepLinkBase string `mapstructure:"deep_link_base"` // 分享深度链接前缀，客户端据此识别扫码内容
+	SignKey      string `mapstructure:"sign_key"`       // 分享链接签名密钥，用于校验扫码内容未被篡改
+	QRCodeSize   int    `mapstructure:"qrcode_size"`    // 二维码图片边长（像素）
+}
+
+// PublicIDConfig 公开ID配置
+// 接口中的用户、动态和评论只返回公开ID，过渡期内仍接受客户端传入旧的数字ID
+type PublicIDConfig struct {
+	AcceptInternal bool `mapstructure:"accept_internal"` // 是否接受客户端传入的数字ID，所有客户端升级后关闭
+}
+
+// ErrTrackConfig 错误追踪服务配置
+type ErrTrackConfig struct {
+	Provider    string `mapstructure:"provider"`    // 服务提供商：sentry
+	DSN         string `mapstructure:"dsn"`         // 项目DSN，为空时不上报
+	Environment string `mapstructure:"environment"` // 环境名称，如production、staging
+	Timeout     string `mapstructure:"timeout"`     // 上报请求超时时间
+}
+
+// CORSConfig 跨域访问配置，配置文件修改后热更新
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`   // 允许的来源，*表示所有来源，*.example.com表示该域名的所有子域名
+	AllowedMethods   []string `mapstructure:"allowed_methods"`   // 允许的请求方法
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`   // 允许的请求头，为空时允许预检请求声明的所有请求头
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`   // 允许客户端读取的响应头
+	AllowCredentials bool     `mapstructure:"allow_credentials"` // 是否允许携带Cookie等凭证
+	MaxAge           string   `mapstructure:"max_age"`           // 预检请求结果的缓存时间
+}
+
+// SecurityConfig 安全响应头配置
+type SecurityConfig struct {
+	HSTSMaxAge            string `mapstructure:"hsts_max_age"`            // HSTS有效期，为空或0时不发送HSTS响应头
+	HSTSIncludeSubdomains bool   `mapstructure:"hsts_include_subdomains"` // HSTS是否包含子域名
+	FrameOptions          string `mapstructure:"frame_options"`           // X-Frame-Options，默认DENY
+	ContentSecurityPolicy string `mapstructure:"content_security_policy"` // Content-Security-Policy，默认禁止加载任何资源
+	ReferrerPolicy        string `mapstructure:"referrer_policy"`         // Referrer-Policy，默认no-referrer
+}
+
+// APISignConfig 服务端请求签名配置
+type APISignConfig struct {
+	SecretKey     string `mapstructure:"secret_key"`     // 应用密钥的加密密钥，修改后已创建的调用方密钥将无法解密
+	TimestampSkew string `mapstructure:"timestamp_skew"` // 允许的客户端与服务器时间偏差，超出时拒绝请求
+}
+
+// FeatureFlagConfig 功能开关配置
+type FeatureFlagConfig struct {
+	RefreshInterval string          `mapstructure:"refresh_interval"` // 各实例本地开关缓存的刷新间隔，修改开关后其他实例在该间隔内生效
+	Defaults        map[string]bool `mapstructure:"defaults"`         // 开关未创建时的默认值，key为开关标识
+}
+
+// BackupConfig 用户内容备份配置
+type BackupConfig struct {
+	Scheduled    bool   `mapstructure:"scheduled"`     // 是否由定时任务自动备份
+	Bucket       string `mapstructure:"bucket"`        // 备份文件存储桶，为空时使用默认存储桶
+	Prefix       string `mapstructure:"prefix"`        // 备份文件对象键前缀
+	BatchSize    int    `mapstructure:"batch_size"`    // 每批读取或写入的行数
+	FullInterval string `mapstructure:"full_interval"` // 定时备份时距上次全量备份超过该时长则执行全量备份，否则执行增量备份
+}
+
+// WebhookConfig Webhook事件投递配置
+type WebhookConfig struct {
+	Timeout       string `mapstructure:"timeout"`        // 单次投递的请求超时
+	MaxAttempts   int    `mapstructure:"max_attempts"`   // 最大投递次数，包含首次投递，用尽后标记为失败
+	RetryInterval string `mapstructure:"retry_interval"` // 首次重试间隔，之后每次翻倍
+	LogRetention  string `mapstructure:"log_retention"`  // 已结束投递记录的保留期
+}
+
+// FeedConfig 动态列表配置
+type FeedConfig struct {
+	Discover DiscoverFeedConfig `mapstructure:"discover"`
+}
+
+// DiscoverFeedConfig 发现页配置，按比例混合关注、附近和热门动态
+type DiscoverFeedConfig struct {
+	Weights        map[string]int `mapstructure:"weights"`         // 各来源的混合比例，key为来源名称（followed、nearby、trending），为0时不使用该来源
+	CandidateSize  int            `mapstructure:"candidate_size"`  // 每个来源最多取的候选动态数
+	NearbyRadius   float64        `mapstructure:"nearby_radius"`   // 附近动态的搜索半径（公里）
+	NearbyWindow   string         `mapstructure:"nearby_window"`   // 附近动态只包含该时长内发布的动态
+	TrendingWindow string         `mapstructure:"trending_window"` // 热门动态只包含该时长内发布的动态
+}
+
+// AdminConfig 管理后台配置
+type AdminConfig struct {
+	UserIDs []uint `mapstructure:"user_ids"` // 拥有管理员权限的用户ID列表
+}
+
+// AccountConfig 账号相关配置
+type AccountConfig struct {
+	SMSRetention string `mapstructure:"sms_retention"`  // 账号注销后短信记录的保留期，到期后清除个人信息
+	DormantAfter string `mapstructure:"dormant_after"`  // 用户连续未活跃超过该时长后标记为休眠
+	TempImageTTL string `mapstructure:"temp_image_ttl"` // 休眠用户未使用的临时图片超过该时长后删除
+	// 追加的保留用户名，不区分大小写，在内置保留用户名的基础上生效
+	ReservedUsernames []string `mapstructure:"reserved_usernames"`
+}
+
+// ContentConfig 动态和评论的内容规则配置，由internal/contentpolicy统一校验
+type ContentConfig struct {
+	Post    ContentRuleConfig `mapstructure:"post"`    // 动态的内容规则
+	Comment ContentRuleConfig `mapstructure:"comment"` // 评论的内容规则
+}
+
+// ContentRuleConfig 内容规则，数量上限为0时不限制
+type ContentRuleConfig struct {
+	MaxLength         int      `mapstructure:"max_length"`          // 文字的最大字符数，按Unicode字符计算
+	MaxImages         int      `mapstructure:"max_images"`          // 最多附带的图片数
+	MaxLinks          int      `mapstructure:"max_links"`           // 文字中最多包含的链接数
+	AllowedImageTypes []string `mapstructure:"allowed_image_types"` // 允许附带的图片类型，为空时不限制
+	RejectEmojiOnly   bool     `mapstructure:"reject_emoji_only"`   // 是否拒绝只包含表情符号的文字
+}
+
+// AntiSpamConfig 反垃圾配置
+// 限制用户发布动态和评论的频率，次数上限为0时不限制
+type AntiSpamConfig struct {
+	PostLimit       int    `mapstructure:"post_limit"`       // 时间窗口内最多发布的动态数
+	PostWindow      string `mapstructure:"post_window"`      // 发布动态的限流时间窗口
+	CommentLimit    int    `mapstructure:"comment_limit"`    // 时间窗口内最多发布的评论数
+	CommentWindow   string `mapstructure:"comment_window"`   // 发布评论的限流时间窗口
+	SearchLimit     int    `mapstructure:"search_limit"`     // 时间窗口内最多搜索用户的次数
+	SearchWindow    string `mapstructure:"search_window"`    // 搜索用户的限流时间窗口
+	TranslateLimit  int    `mapstructure:"translate_limit"`  // 时间窗口内最多翻译动态和评论的次数
+	TranslateWindow string `mapstructure:"translate_window"` // 翻译的限流时间窗口
+}
+
+var (
+	config *Config
+	// 配置读取实例，用于监听配置文件变更
+	vp *viper.Viper
+	// 保护可热更新的配置项
+	reloadMu sync.RWMutex
+)
 
 // Init 初始化配置
 func Init() error {
@@ -127,13 +535,10 @@ func Init() error {
 	v.SetConfigType("yaml")
 	v.AddConfigPath("./config")
 
-	// 读取配置文件
-	err := v.ReadInConfig()
-	if err != nil {
-		return fmt.Errorf("读取配置文件失败: %w", err)
-	}
+	// 设置默认值，配置文件和环境变量均未提供时生效
+	setDefaults(v)
 
-	// 加载.env文件
+	// 加载.env文件，其中可以设置APP_ENV选择环境配置
 	envFile := "./config/.env"
 	if _, err := os.Stat(envFile); err == nil {
 		// 使用gotenv库加载.env文件
@@ -142,6 +547,17 @@ func Init() error {
 		}
 	}
 
+	// 读取基础配置文件
+	err := v.ReadInConfig()
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	// 合并当前环境的配置文件
+	if err := mergeProfile(v); err != nil {
+		return err
+	}
+
 	// 读取环境变量
 	v.SetEnvPrefix("")
 	v.AutomaticEnv()
@@ -153,11 +569,47 @@ func Init() error {
 		return fmt.Errorf("解析配置失败: %w", err)
 	}
 
-	// 配置初始化完成
+	// 在各组件初始化之前校验配置，一次性报告所有错误
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	vp = v
 
 	return nil
 }
 
+// Watch 监听配置文件变更，热更新支持动态调整的配置项（目前为CORS配置和请求日志脱敏配置）
+// 只监听基础配置文件，重新读取后再次合并环境配置文件；其他配置项在启动时读取后被各组件缓存，修改后仍需重启服务
+// 每次重新加载后调用onReload，err为nil表示加载成功；日志系统依赖配置，由调用方记录日志
+func Watch(onReload func(file string, err error)) {
+	if vp == nil {
+		return
+	}
+
+	vp.OnConfigChange(func(e fsnotify.Event) {
+		if err := mergeProfile(vp); err != nil {
+			onReload(e.Name, err)
+			return
+		}
+		updated := &Config{}
+		if err := vp.Unmarshal(updated); err != nil {
+			onReload(e.Name, err)
+			return
+		}
+		if err := updated.Validate(); err != nil {
+			onReload(e.Name, err)
+			return
+		}
+
+		reloadMu.Lock()
+		config.CORS = updated.CORS
+		config.Logger.Redaction = updated.Logger.Redaction
+		reloadMu.Unlock()
+		onReload(e.Name, nil)
+	})
+	vp.WatchConfig()
+}
+
 // GetConfig 获取配置
 func GetConfig() *Config {
 	return config
@@ -190,9 +642,18 @@ func GetJWTConfig() JWTConfig {
 
 // GetLoggerConfig 获取日志配置
 func GetLoggerConfig() LoggerConfig {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
 	return config.Logger
 }
 
+// GetLoggerRedactionConfig 获取请求日志脱敏配置，配置文件修改后热更新
+func GetLoggerRedactionConfig() LoggerRedactionConfig {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return config.Logger.Redaction
+}
+
 // GetSMSConfig 获取短信服务配置
 func GetSMSConfig() SMSConfig {
 	return config.SMS
@@ -202,3 +663,120 @@ func GetSMSConfig() SMSConfig {
 func GetCOSConfig() COSConfig {
 	return config.COS
 }
+
+// GetFeedConfig 获取动态列表配置
+func GetFeedConfig() FeedConfig {
+	return config.Feed
+}
+
+// GetAdminConfig 获取管理后台配置
+func GetAdminConfig() AdminConfig {
+	return config.Admin
+}
+
+// GetAccountConfig 获取账号相关配置
+func GetAccountConfig() AccountConfig {
+	return config.Account
+}
+
+// GetContentConfig 获取内容规则配置
+func GetContentConfig() ContentConfig {
+	return config.Content
+}
+
+// GetAntiSpamConfig 获取反垃圾配置
+func GetAntiSpamConfig() AntiSpamConfig {
+	return config.AntiSpam
+}
+
+// GetCDNConfig 获取CDN配置
+func GetCDNConfig() CDNConfig {
+	return config.CDN
+}
+
+// GetGeocodeConfig 获取逆地理编码服务配置
+func GetGeocodeConfig() GeocodeConfig {
+	return config.Geocode
+}
+
+// GetGeoIPConfig 获取IP归属地解析配置
+func GetGeoIPConfig() GeoIPConfig {
+	return config.GeoIP
+}
+
+// GetTranslateConfig 获取翻译服务配置
+func GetTranslateConfig() TranslateConfig {
+	return config.Translate
+}
+
+// GetLinkPreviewConfig 获取链接预览配置
+func GetLinkPreviewConfig() LinkPreviewConfig {
+	return config.LinkPreview
+}
+
+// GetPoolMonitorConfig 获取连接池监控配置
+func GetPoolMonitorConfig() PoolMonitorConfig {
+	return config.PoolMonitor
+}
+
+// GetDebugConfig 获取运行时调试接口配置
+func GetDebugConfig() DebugConfig {
+	return config.Debug
+}
+
+// GetImageConfig 获取图片处理配置
+func GetImageConfig() ImageConfig {
+	return config.Image
+}
+
+// GetPushConfig 获取移动推送配置
+func GetPushConfig() PushConfig {
+	return config.Push
+}
+
+// GetShareConfig 获取分享配置
+func GetShareConfig() ShareConfig {
+	return config.Share
+}
+
+// GetPublicIDConfig 获取公开ID配置
+func GetPublicIDConfig() PublicIDConfig {
+	return config.PublicID
+}
+
+// GetCORSConfig 获取跨域访问配置
+func GetCORSConfig() CORSConfig {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return config.CORS
+}
+
+// GetSecurityConfig 获取安全响应头配置
+func GetSecurityConfig() SecurityConfig {
+	return config.Security
+}
+
+// GetAPISignConfig 获取服务端请求签名配置
+func GetAPISignConfig() APISignConfig {
+	return config.APISign
+}
+
+// GetFeatureFlagConfig 获取功能开关配置
+func GetFeatureFlagConfig() FeatureFlagConfig {
+	return config.FeatureFlag
+}
+
+// GetErrTrackConfig 获取错误追踪服务配置
+func GetErrTrackConfig() ErrTrackConfig {
+	return config.ErrTrack
+}
+
+// GetBackupConfig 获取用户内容备份配置
+func GetBackupConfig() BackupConfig {
+	return config.Backup
+}
+
+// GetWebhookConfig 获取Webhook事件投递配置
+func GetWebhookConfig() WebhookConfig {
+	return config.Webhook
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+// ParseDuration 解析时长配置，为空、格式错误或为负数时返回fallback，显式配置为0时返回0
+// 时长格式在启动时已由Validate校验，这里的回退只用于未配置的项
+func ParseDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
+}
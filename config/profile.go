@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvVar 选择环境配置的环境变量，如APP_ENV=prod时在config.yaml之上合并config.prod.yaml
+const EnvVar = "APP_ENV"
+
+// redactedValue 敏感配置项在有效配置中的显示值
+const redactedValue = "******"
+
+// sensitiveKeys 配置项名称包含这些片段时视为敏感信息，输出有效配置时隐藏其取值
+// 名称无法判断的配置项通过redact标签单独指定，见redactAlways等
+var sensitiveKeys = []string{"password", "secret", "token", "dsn", "sign_key", "access_key"}
+
+// sensitiveNames 配置项名称与这些名称完全相同时视为敏感信息，如geocode.key、geoip.key等服务密钥
+// 使用完全匹配避免key_prefix、key_id等非敏感配置项被隐藏
+var sensitiveNames = []string{"key"}
+
+var (
+	// 当前环境名称，未设置APP_ENV时为空
+	env string
+	// 已加载的配置文件，按合并顺序排列
+	loadedFiles []string
+)
+
+// mergeProfile 在基础配置之上合并APP_ENV选择的环境配置文件
+// 环境配置文件只需包含与基础配置不同的配置项，环境变量的优先级仍高于两者
+func mergeProfile(v *viper.Viper) error {
+	name := strings.TrimSpace(os.Getenv(EnvVar))
+	files := []string{v.ConfigFileUsed()}
+	if name != "" {
+		overlay := viper.New()
+		overlay.SetConfigName("config." + name)
+		overlay.SetConfigType("yaml")
+		overlay.AddConfigPath("./config")
+		if err := overlay.ReadInConfig(); err != nil {
+			return fmt.Errorf("读取%s=%s对应的环境配置文件config.%s.yaml失败: %w", EnvVar, name, name, err)
+		}
+		if err := v.MergeConfigMap(overlay.AllSettings()); err != nil {
+			return fmt.Errorf("合并环境配置文件失败: %w", err)
+		}
+		files = append(files, overlay.ConfigFileUsed())
+	}
+
+	reloadMu.Lock()
+	env, loadedFiles = name, files
+	reloadMu.Unlock()
+	return nil
+}
+
+// Env 获取当前环境名称，未设置APP_ENV时为空
+func Env() string {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return env
+}
+
+// LoadedFiles 获取已加载的配置文件，按合并顺序排列
+func LoadedFiles() []string {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return append([]string(nil), loadedFiles...)
+}
+
+// Effective 获取合并环境配置和环境变量后的有效配置，键与配置文件一致，敏感配置项已隐藏
+func Effective() map[string]any {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return redact(reflect.ValueOf(*config), "", "").(map[string]any)
+}
+
+// redact标签的取值，用于逐项覆盖按名称判断的结果
+const (
+	redactAlways = "true"  // 始终隐藏
+	redactNever  = "false" // 不隐藏，用于名称匹配敏感规则但取值并非敏感信息的配置项
+	redactQuery  = "query" // 只隐藏URL查询参数的取值，用于地址中携带access_token等凭据的配置项
+)
+
+// redact 按mapstructure标签将配置转换为键值结构
+// 配置项的redact标签优先，未设置时名称匹配敏感规则的非空字符串替换为redactedValue
+func redact(v reflect.Value, name, mode string) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" {
+				continue
+			}
+			out[key] = redact(v.Field(i), key, field.Tag.Get("redact"))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			// 字符串映射的值沿用上级配置项名称和标签判断，如sms.aliyun.templates
+			childName, childMode := key, ""
+			if iter.Value().Kind() == reflect.String {
+				childName, childMode = name, mode
+			}
+			out[key] = redact(iter.Value(), childName, childMode)
+		}
+		return out
+	case reflect.Slice:
+		out := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redact(v.Index(i), name, mode)
+		}
+		return out
+	case reflect.String:
+		return redactString(v.String(), name, mode)
+	default:
+		return v.Interface()
+	}
+}
+
+// redactString 按标签或名称隐藏字符串配置项的取值
+func redactString(value, name, mode string) string {
+	if value == "" {
+		return value
+	}
+	switch mode {
+	case redactAlways:
+		return redactedValue
+	case redactNever:
+		return value
+	case redactQuery:
+		return redactURLQuery(value)
+	}
+	if isSensitive(name) {
+		return redactedValue
+	}
+	return value
+}
+
+// redactURLQuery 隐藏URL中查询参数的取值，保留参数名便于排查；无法解析或地址中包含用户名密码时隐藏整个地址
+func redactURLQuery(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User != nil {
+		return redactedValue
+	}
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		params[i] = key + "=" + redactedValue
+	}
+	u.RawQuery = strings.Join(params, "&")
+	return u.String()
+}
+
+// isSensitive 判断配置项名称是否为敏感名称或包含敏感片段
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	if slices.Contains(sensitiveNames, name) {
+		return true
+	}
+	for _, s := range sensitiveKeys {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
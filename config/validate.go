@@ -0,0 +1,495 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// setDefaults 设置配置项的默认值，配置文件和环境变量均未提供时生效
+// 显式配置为零值的配置项不会被默认值覆盖，由Validate检查其取值范围
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.host", "0.0.0.0")
+	v.SetDefault("server.read_timeout", "30s")
+	v.SetDefault("server.write_timeout", "30s")
+	v.SetDefault("server.request_timeout", "10s")
+	v.SetDefault("server.max_body_size", 1<<20)
+	v.SetDefault("server.compression.min_size", 1024)
+
+	v.SetDefault("scheduler.port", 8081)
+	v.SetDefault("scheduler.host", "0.0.0.0")
+	v.SetDefault("scheduler.read_timeout", "60s")
+	v.SetDefault("scheduler.write_timeout", "60s")
+	v.SetDefault("scheduler.drain_timeout", "30s")
+	v.SetDefault("scheduler.leader_election.lease_ttl", "15s")
+	v.SetDefault("scheduler.alert.interval", "1m")
+	v.SetDefault("scheduler.task_defaults.lock_timeout", "5m")
+	v.SetDefault("scheduler.task_defaults.retry_interval", "30s")
+
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 3306)
+	v.SetDefault("database.max_connections", 100)
+	v.SetDefault("database.conn_max_lifetime", "1h")
+	v.SetDefault("database.conn_max_idle_time", "30m")
+
+	v.SetDefault("redis.host", "localhost")
+	v.SetDefault("redis.port", 6379)
+	v.SetDefault("redis.pool_size", 100)
+	v.SetDefault("redis.min_idle_conns", 10)
+	v.SetDefault("redis.dial_timeout", "5s")
+	v.SetDefault("redis.read_timeout", "5s")
+	v.SetDefault("redis.write_timeout", "5s")
+	v.SetDefault("redis.mode", "single")
+
+	v.SetDefault("jwt.expires_time", "24h")
+	v.SetDefault("jwt.issuer", "app")
+
+	v.SetDefault("cos.resilience.timeout", "10s")
+	v.SetDefault("cos.resilience.max_retries", 2)
+	v.SetDefault("cos.resilience.retry_interval", "200ms")
+	v.SetDefault("cos.resilience.failure_threshold", 5)
+	v.SetDefault("cos.resilience.open_timeout", "30s")
+	v.SetDefault("cos.url.presign_expire", "168h")
+	v.SetDefault("cos.url.refresh_before", "24h")
+
+	v.SetDefault("logger.level", "info")
+	v.SetDefault("logger.format", "console")
+	v.SetDefault("logger.output_path", "./logs/app.log")
+	v.SetDefault("logger.max_size", 100)
+	v.SetDefault("logger.max_age", 30)
+	v.SetDefault("logger.max_backups", 30)
+	v.SetDefault("logger.stacktrace_level", "error")
+	v.SetDefault("logger.stacktrace_depth", 10)
+
+	v.SetDefault("content.post.max_length", 1000)
+	v.SetDefault("content.post.max_images", 10)
+	v.SetDefault("content.post.max_links", 3)
+	v.SetDefault("content.post.allowed_image_types", []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+	v.SetDefault("content.post.reject_emoji_only", true)
+	v.SetDefault("content.comment.max_length", 500)
+	v.SetDefault("content.comment.max_images", 1)
+	v.SetDefault("content.comment.max_links", 1)
+	v.SetDefault("content.comment.allowed_image_types", []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+
+	v.SetDefault("webhook.max_attempts", 6)
+	v.SetDefault("share.qrcode_size", 512)
+	v.SetDefault("public_id.accept_internal", true)
+	v.SetDefault("pool_monitor.retention", 240)
+}
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Field   string // 配置项路径，与配置文件中的键一致，如database.max_connections
+	Message string // 错误说明及修改建议
+}
+
+// ValidationError 配置校验错误，汇总所有未通过校验的配置项，启动时一次性输出
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error 实现error接口，每个配置项的错误占一行
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "配置校验失败，共%d项错误:", len(e.Errors))
+	for _, fe := range e.Errors {
+		fmt.Fprintf(&b, "\n  - %s: %s", fe.Field, fe.Message)
+	}
+	return b.String()
+}
+
+// validator 收集配置校验错误
+type validator struct {
+	errors []FieldError
+}
+
+// addf 记录一个配置项的校验错误
+func (v *validator) addf(field, format string, args ...any) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// required 检查字符串配置项不为空
+func (v *validator) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(field, "不能为空")
+	}
+}
+
+// port 检查端口号在有效范围内
+func (v *validator) port(field string, value int) {
+	if value < 1 || value > 65535 {
+		v.addf(field, "端口号%d无效，取值范围为1-65535", value)
+	}
+}
+
+// atLeast 检查整数配置项不小于下限
+func (v *validator) atLeast(field string, value, min int) {
+	if value < min {
+		v.addf(field, "取值%d无效，不能小于%d", value, min)
+	}
+}
+
+// duration 检查时长配置项可以解析且不为负数，为空时视为未配置
+func (v *validator) duration(field, value string) {
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		v.addf(field, "时长%q格式错误，应为如30s、5m、1h的格式", value)
+		return
+	}
+	if d < 0 {
+		v.addf(field, "时长%q不能为负数", value)
+	}
+}
+
+// requiredDuration 检查时长配置项已配置、可以解析且大于0
+func (v *validator) requiredDuration(field, value string) {
+	if value == "" {
+		v.addf(field, "不能为空，应为如30s、5m、1h的格式")
+		return
+	}
+	if d, err := time.ParseDuration(value); err == nil && d <= 0 {
+		v.addf(field, "时长%q必须大于0", value)
+		return
+	}
+	v.duration(field, value)
+}
+
+// oneOf 检查配置项取值在允许范围内
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.addf(field, "取值%q无效，可选值为%s", value, strings.Join(allowed, "、"))
+}
+
+// Validate 校验配置，返回汇总所有错误的*ValidationError，全部通过时返回nil
+// 只检查配置项本身的必填项、取值范围和时长格式，不检查依赖服务是否可用
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	c.validateServer(v)
+	c.validateScheduler(v)
+	c.validateDatabase(v)
+	c.validateRedis(v)
+	c.validateJWT(v)
+	c.validateLogger(v)
+	c.validateServices(v)
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// validateServer 校验API服务配置
+func (c *Config) validateServer(v *validator) {
+	s := c.Server
+	v.port("server.port", s.Port)
+	v.duration("server.read_timeout", s.ReadTimeout)
+	v.duration("server.write_timeout", s.WriteTimeout)
+	v.duration("server.request_timeout", s.RequestTimeout)
+	if s.MaxBodySize < 0 {
+		v.addf("server.max_body_size", "取值%d无效，不能为负数", s.MaxBodySize)
+	}
+	for name, limit := range s.Limits {
+		v.duration("server.limits."+name+".timeout", limit.Timeout)
+		if limit.MaxBodySize < 0 {
+			v.addf("server.limits."+name+".max_body_size", "取值%d无效，不能为负数", limit.MaxBodySize)
+		}
+	}
+	if s.Compression.MinSize < 0 {
+		v.addf("server.compression.min_size", "取值%d无效，不能为负数", s.Compression.MinSize)
+	}
+}
+
+// validateScheduler 校验定时程序配置
+func (c *Config) validateScheduler(v *validator) {
+	s := c.Scheduler
+	v.port("scheduler.port", s.Port)
+	v.duration("scheduler.read_timeout", s.ReadTimeout)
+	v.duration("scheduler.write_timeout", s.WriteTimeout)
+	v.duration("scheduler.drain_timeout", s.DrainTimeout)
+
+	for i, t := range s.Auth.Tokens {
+		field := fmt.Sprintf("scheduler.auth.tokens[%d]", i)
+		v.required(field+".token", t.Token)
+		v.oneOf(field+".role", t.Role, "viewer", "operator")
+	}
+
+	if s.LeaderElection.Enabled {
+		v.requiredDuration("scheduler.leader_election.lease_ttl", s.LeaderElection.LeaseTTL)
+	}
+
+	if s.Alert.Enabled {
+		v.requiredDuration("scheduler.alert.interval", s.Alert.Interval)
+	}
+	for i, n := range s.Alert.Notifiers {
+		field := fmt.Sprintf("scheduler.alert.notifiers[%d]", i)
+		v.oneOf(field+".type", n.Type, "webhook", "dingtalk", "wecom")
+		v.duration(field+".timeout", n.Timeout)
+	}
+
+	v.duration("scheduler.task_defaults.lock_timeout", s.TaskDefaults.LockTimeout)
+	v.atLeast("scheduler.task_defaults.retry_count", s.TaskDefaults.RetryCount, 0)
+	v.duration("scheduler.task_defaults.retry_interval", s.TaskDefaults.RetryInterval)
+	for name, t := range s.Tasks {
+		field := "scheduler.tasks." + name
+		v.duration(field+".lock_timeout", t.LockTimeout)
+		if t.RetryCount != nil {
+			v.atLeast(field+".retry_count", *t.RetryCount, 0)
+		}
+		v.duration(field+".retry_interval", t.RetryInterval)
+	}
+}
+
+// validateDatabase 校验数据库配置
+func (c *Config) validateDatabase(v *validator) {
+	d := c.Database
+	v.required("database.host", d.Host)
+	v.port("database.port", d.Port)
+	v.required("database.user", d.User)
+	v.required("database.name", d.Name)
+	v.atLeast("database.max_connections", d.MaxConnections, 1)
+	v.duration("database.conn_max_lifetime", d.ConnMaxLifetime)
+	v.duration("database.conn_max_idle_time", d.ConnMaxIdleTime)
+	validateRetry(v, "database.retry", d.Retry)
+}
+
+// validateRedis 校验Redis配置
+func (c *Config) validateRedis(v *validator) {
+	r := c.Redis
+	switch r.Mode {
+	case "", "single":
+		v.required("redis.host", r.Host)
+		v.port("redis.port", r.Port)
+	case "cluster", "sentinel":
+		if len(r.Addrs) == 0 {
+			v.addf("redis.addrs", "%s模式下不能为空，格式为host:port", r.Mode)
+		}
+		if r.Mode == "sentinel" {
+			v.required("redis.master_name", r.MasterName)
+		}
+	default:
+		v.oneOf("redis.mode", r.Mode, "single", "cluster", "sentinel")
+	}
+	v.atLeast("redis.db", r.DB, 0)
+	v.atLeast("redis.pool_size", r.PoolSize, 1)
+	v.atLeast("redis.min_idle_conns", r.MinIdleConns, 0)
+	if r.PoolSize > 0 && r.MinIdleConns > r.PoolSize {
+		v.addf("redis.min_idle_conns", "取值%d无效，不能大于pool_size（%d）", r.MinIdleConns, r.PoolSize)
+	}
+	v.duration("redis.dial_timeout", r.DialTimeout)
+	v.duration("redis.read_timeout", r.ReadTimeout)
+	v.duration("redis.write_timeout", r.WriteTimeout)
+	validateRetry(v, "redis.retry", r.Retry)
+}
+
+// validateRetry 校验依赖服务连接重试配置
+func validateRetry(v *validator, prefix string, r ConnectRetryConfig) {
+	v.duration(prefix+".initial_interval", r.InitialInterval)
+	v.duration(prefix+".max_interval", r.MaxInterval)
+	v.duration(prefix+".max_wait", r.MaxWait)
+	v.duration(prefix+".health_check_interval", r.HealthCheckInterval)
+	v.atLeast(prefix+".reconnect_after", r.ReconnectAfter, 0)
+}
+
+// validateJWT 校验JWT配置，密钥文件能否读取由jwt.Keys在启动时检查
+func (c *Config) validateJWT(v *validator) {
+	j := c.JWT
+	if j.SecretKey == "" && len(j.Keys) == 0 {
+		v.addf("jwt.secret_key", "secret_key和keys不能同时为空，至少配置一个签名密钥")
+	}
+	v.requiredDuration("jwt.expires_time", j.ExpiresTime)
+
+	ids := make(map[string]bool, len(j.Keys))
+	for i, k := range j.Keys {
+		field := fmt.Sprintf("jwt.keys[%d]", i)
+		v.required(field+".id", k.ID)
+		if ids[k.ID] {
+			v.addf(field+".id", "密钥ID%q重复", k.ID)
+		}
+		ids[k.ID] = true
+
+		switch k.Algorithm {
+		case "", "HS256":
+			v.required(field+".secret", k.Secret)
+		case "RS256":
+			v.required(field+".public_key_file", k.PublicKeyFile)
+		default:
+			v.oneOf(field+".algorithm", k.Algorithm, "HS256", "RS256")
+		}
+	}
+	if j.CurrentKeyID != "" && !ids[j.CurrentKeyID] {
+		v.addf("jwt.current_key_id", "密钥ID%q不在keys列表中", j.CurrentKeyID)
+	}
+}
+
+// validateLogger 校验日志配置
+func (c *Config) validateLogger(v *validator) {
+	l := c.Logger
+	levels := []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+	v.oneOf("logger.level", l.Level, levels...)
+	v.oneOf("logger.format", l.Format, "json", "console")
+	v.required("logger.output_path", l.OutputPath)
+	v.atLeast("logger.max_size", l.MaxSize, 0)
+	v.atLeast("logger.max_age", l.MaxAge, 0)
+	v.atLeast("logger.max_backups", l.MaxBackups, 0)
+	if l.EnableStacktrace {
+		v.oneOf("logger.stacktrace_level", l.StacktraceLevel, levels...)
+	}
+
+	paths := map[string]string{l.OutputPath: "logger.output_path"}
+	for name, o := range l.Outputs {
+		prefix := "logger.outputs." + name
+		if o.Level != "" {
+			v.oneOf(prefix+".level", o.Level, levels...)
+		}
+		if o.Format != "" {
+			v.oneOf(prefix+".format", o.Format, "json", "console")
+		}
+		v.atLeast(prefix+".max_size", o.MaxSize, 0)
+		v.atLeast(prefix+".max_age", o.MaxAge, 0)
+		v.atLeast(prefix+".max_backups", o.MaxBackups, 0)
+		// 多个日志轮转同一个文件会互相覆盖
+		v.required(prefix+".output_path", o.OutputPath)
+		if o.OutputPath == "" {
+			continue
+		}
+		if other, ok := paths[o.OutputPath]; ok {
+			v.addf(prefix+".output_path", "与%s相同", other)
+			continue
+		}
+		paths[o.OutputPath] = prefix + ".output_path"
+	}
+}
+
+// validateServices 校验各业务功能和第三方服务配置，第三方服务的密钥为空时视为未启用，不要求必填
+func (c *Config) validateServices(v *validator) {
+	cr := c.COS.Resilience
+	v.duration("cos.resilience.timeout", cr.Timeout)
+	v.duration("cos.resilience.transfer_timeout", cr.TransferTimeout)
+	v.atLeast("cos.resilience.max_retries", cr.MaxRetries, 0)
+	v.duration("cos.resilience.retry_interval", cr.RetryInterval)
+	v.atLeast("cos.resilience.failure_threshold", cr.FailureThreshold, 1)
+	v.duration("cos.resilience.open_timeout", cr.OpenTimeout)
+
+	cu := c.COS.URL
+	v.requiredDuration("cos.url.presign_expire", cu.PresignExpire)
+	v.requiredDuration("cos.url.refresh_before", cu.RefreshBefore)
+	expire, expireErr := time.ParseDuration(cu.PresignExpire)
+	refresh, refreshErr := time.ParseDuration(cu.RefreshBefore)
+	if expireErr == nil && refreshErr == nil && refresh >= expire {
+		v.addf("cos.url.refresh_before", "时长%q必须小于cos.url.presign_expire", cu.RefreshBefore)
+	}
+
+	v.duration("account.sms_retention", c.Account.SMSRetention)
+	v.duration("account.dormant_after", c.Account.DormantAfter)
+	v.duration("account.temp_image_ttl", c.Account.TempImageTTL)
+
+	v.atLeast("content.post.max_length", c.Content.Post.MaxLength, 0)
+	v.atLeast("content.post.max_images", c.Content.Post.MaxImages, 0)
+	v.atLeast("content.post.max_links", c.Content.Post.MaxLinks, 0)
+	v.atLeast("content.comment.max_length", c.Content.Comment.MaxLength, 0)
+	v.atLeast("content.comment.max_images", c.Content.Comment.MaxImages, 0)
+	v.atLeast("content.comment.max_links", c.Content.Comment.MaxLinks, 0)
+
+	a := c.AntiSpam
+	v.atLeast("anti_spam.post_limit", a.PostLimit, 0)
+	v.duration("anti_spam.post_window", a.PostWindow)
+	v.atLeast("anti_spam.comment_limit", a.CommentLimit, 0)
+	v.duration("anti_spam.comment_window", a.CommentWindow)
+	v.atLeast("anti_spam.search_limit", a.SearchLimit, 0)
+	v.duration("anti_spam.search_window", a.SearchWindow)
+	v.atLeast("anti_spam.translate_limit", a.TranslateLimit, 0)
+	v.duration("anti_spam.translate_window", a.TranslateWindow)
+
+	for name, b := range c.CDN.Buckets {
+		field := "cdn.buckets." + name
+		if b.Private {
+			v.required(field+".sign_key", b.SignKey)
+		}
+		v.duration(field+".expire", b.Expire)
+	}
+
+	if c.Geocode.Provider != "" {
+		v.oneOf("geocode.provider", c.Geocode.Provider, "tencent", "amap")
+	}
+	v.duration("geocode.timeout", c.Geocode.Timeout)
+	v.duration("geocode.cache_ttl", c.Geocode.CacheTTL)
+
+	if c.GeoIP.Provider != "" {
+		v.oneOf("geoip.provider", c.GeoIP.Provider, "tencent")
+	}
+	v.duration("geoip.timeout", c.GeoIP.Timeout)
+	v.duration("geoip.cache_ttl", c.GeoIP.CacheTTL)
+
+	if c.Translate.Provider != "" {
+		v.oneOf("translate.provider", c.Translate.Provider, "tencent", "deepl")
+	}
+	v.duration("translate.timeout", c.Translate.Timeout)
+	v.duration("translate.cache_ttl", c.Translate.CacheTTL)
+
+	v.duration("link_preview.timeout", c.LinkPreview.Timeout)
+	v.duration("link_preview.cache_ttl", c.LinkPreview.CacheTTL)
+	if c.LinkPreview.MaxBodySize < 0 {
+		v.addf("link_preview.max_body_size", "取值%d无效，不能为负数", c.LinkPreview.MaxBodySize)
+	}
+
+	d := c.Feed.Discover
+	for name, weight := range d.Weights {
+		v.oneOf("feed.discover.weights."+name, name, "followed", "nearby", "trending")
+		v.atLeast("feed.discover.weights."+name, weight, 0)
+	}
+	v.atLeast("feed.discover.candidate_size", d.CandidateSize, 0)
+	if d.NearbyRadius < 0 {
+		v.addf("feed.discover.nearby_radius", "取值%v无效，不能为负数", d.NearbyRadius)
+	}
+	v.duration("feed.discover.nearby_window", d.NearbyWindow)
+	v.duration("feed.discover.trending_window", d.TrendingWindow)
+
+	v.duration("push.timeout", c.Push.Timeout)
+	if apns := c.Push.APNs; apns.KeyFile != "" {
+		v.required("push.apns.key_id", apns.KeyID)
+		v.required("push.apns.team_id", apns.TeamID)
+		v.required("push.apns.bundle_id", apns.BundleID)
+	}
+
+	v.required("share.sign_key", c.Share.SignKey)
+	v.atLeast("share.qrcode_size", c.Share.QRCodeSize, 1)
+
+	if c.ErrTrack.DSN != "" {
+		v.oneOf("errtrack.provider", c.ErrTrack.Provider, "sentry")
+	}
+	v.duration("errtrack.timeout", c.ErrTrack.Timeout)
+
+	v.duration("cors.max_age", c.CORS.MaxAge)
+	v.duration("security.hsts_max_age", c.Security.HSTSMaxAge)
+
+	v.required("api_sign.secret_key", c.APISign.SecretKey)
+	v.duration("api_sign.timestamp_skew", c.APISign.TimestampSkew)
+
+	v.duration("feature_flag.refresh_interval", c.FeatureFlag.RefreshInterval)
+
+	v.atLeast("backup.batch_size", c.Backup.BatchSize, 0)
+	v.duration("backup.full_interval", c.Backup.FullInterval)
+
+	v.duration("webhook.timeout", c.Webhook.Timeout)
+	v.atLeast("webhook.max_attempts", c.Webhook.MaxAttempts, 1)
+	v.duration("webhook.retry_interval", c.Webhook.RetryInterval)
+	v.duration("webhook.log_retention", c.Webhook.LogRetention)
+
+	p := c.PoolMonitor
+	v.duration("pool_monitor.interval", p.Interval)
+	v.atLeast("pool_monitor.retention", p.Retention, 0)
+	if p.SaturationThreshold < 0 || p.SaturationThreshold > 1 {
+		v.addf("pool_monitor.saturation_threshold", "取值%v无效，取值范围为0-1", p.SaturationThreshold)
+	}
+}
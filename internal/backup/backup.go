@@ -0,0 +1,250 @@
+// Package backup 备份和恢复用户内容，用于灾难恢复演练
+// 备份文件按表保存为gzip压缩的JSON Lines或CSV，与描述本次备份的清单一起上传到对象存储
+// 增量备份只包含上次备份之后更新或软删除的行，并记录所基于的上一次备份，恢复时从全量备份开始按顺序应用
+// 物理删除的行无法通过增量备份感知，需要由下一次全量备份覆盖
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/internal/model"
+	"app/pkg/cos"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// 备份模式
+const (
+	ModeFull        = "full"        // 全量备份
+	ModeIncremental = "incremental" // 增量备份
+	ModeAuto        = "auto"        // 距上次全量备份超过配置的间隔时全量备份，否则增量备份
+)
+
+// 备份文件格式
+const (
+	FormatJSON = "json" // JSON Lines，可用于恢复
+	FormatCSV  = "csv"  // CSV，仅用于数据分析，不能用于恢复
+)
+
+// 默认配置
+const (
+	defaultPrefix       = "backups/"
+	defaultBatchSize    = 1000
+	defaultFullInterval = 7 * 24 * time.Hour
+)
+
+// 对象键相关常量
+const (
+	// manifestFile 备份清单文件名
+	manifestFile = "manifest.json"
+	// latestFile 最近一次可恢复备份的指针文件名
+	latestFile = "latest.json"
+	// LatestManifest 恢复时表示最近一次备份
+	LatestManifest = "latest"
+	// dirTimeLayout 每次备份目录名的时间格式
+	dirTimeLayout = "20060102T150405"
+)
+
+// 备份相关错误
+var (
+	ErrInvalidMode        = errors.New("无效的备份模式")
+	ErrInvalidFormat      = errors.New("无效的备份格式")
+	ErrCSVIncremental     = errors.New("CSV格式只支持全量备份")
+	ErrNoBaseBackup       = errors.New("没有可用于增量备份的上一次备份")
+	ErrCSVNotRestorable   = errors.New("CSV格式的备份仅用于数据分析，不能用于恢复")
+	ErrIntegrityViolation = errors.New("备份数据存在无效的关联引用")
+)
+
+// reference 外键引用，恢复前校验被引用的行存在于备份或数据库中
+type reference struct {
+	column string // 引用列
+	table  string // 被引用的表，数据库使用单数表名
+}
+
+// table 需要备份的表，按恢复顺序排列，被引用的表在前
+type table struct {
+	model interface{} // 模型，用于解析表名和字段
+	refs  []reference // 外键引用
+}
+
+// tables 需要备份的用户内容表
+var tables = []table{
+	{model: &model.User{}},
+	{model: &model.Post{}, refs: []reference{{"user_id", "user"}}},
+	{model: &model.PostImage{}, refs: []reference{{"post_id", "post"}, {"user_id", "user"}}},
+	{model: &model.PostComment{}, refs: []reference{{"post_id", "post"}, {"user_id", "user"}, {"parent_id", "post_comment"}}},
+	{model: &model.CommentImage{}, refs: []reference{{"comment_id", "post_comment"}, {"post_id", "post"}, {"user_id", "user"}}},
+	{model: &model.UserFollower{}, refs: []reference{{"user_id", "user"}, {"target_id", "user"}}},
+	{model: &model.UserFriend{}, refs: []reference{{"user_id", "user"}, {"target_id", "user"}}},
+}
+
+// Manifest 备份清单
+type Manifest struct {
+	Mode      string          `json:"mode"`            // 备份模式：full、incremental
+	Format    string          `json:"format"`          // 备份文件格式：json、csv
+	Since     *time.Time      `json:"since,omitempty"` // 增量备份包含该时间之后更新的行
+	StartedAt time.Time       `json:"started_at"`      // 备份开始时间，下一次增量备份从该时间开始
+	Base      string          `json:"base,omitempty"`  // 增量备份所基于的上一次备份清单的对象键
+	Tables    []ManifestTable `json:"tables"`          // 各表的备份文件
+}
+
+// ManifestTable 单个表的备份文件
+type ManifestTable struct {
+	Name      string `json:"name"`       // 表名
+	ObjectKey string `json:"object_key"` // 备份文件对象键
+	Rows      int    `json:"rows"`       // 行数
+}
+
+// latest 最近一次可恢复备份的指针，CSV备份不更新该指针
+type latest struct {
+	Manifest string    `json:"manifest"` // 备份清单的对象键
+	FullAt   time.Time `json:"full_at"`  // 最近一次全量备份的开始时间
+}
+
+// Service 备份服务
+type Service struct {
+	db           *gorm.DB
+	storage      *cos.StorageClient
+	bucket       string
+	prefix       string
+	batchSize    int
+	fullInterval time.Duration
+}
+
+// New 创建备份服务
+func New(db *gorm.DB, storage *cos.StorageClient, cfg config.BackupConfig) *Service {
+	s := &Service{
+		db:           db,
+		storage:      storage,
+		bucket:       cfg.Bucket,
+		prefix:       cfg.Prefix,
+		batchSize:    cfg.BatchSize,
+		fullInterval: defaultFullInterval,
+	}
+	if s.prefix == "" {
+		s.prefix = defaultPrefix
+	}
+	if !strings.HasSuffix(s.prefix, "/") {
+		s.prefix += "/"
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = defaultBatchSize
+	}
+	if d, err := time.ParseDuration(cfg.FullInterval); err == nil && d > 0 {
+		s.fullInterval = d
+	}
+	return s
+}
+
+// parseSchema 解析模型的表结构
+func (s *Service) parseSchema(m interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: s.db}
+	if err := stmt.Parse(m); err != nil {
+		return nil, fmt.Errorf("解析表结构失败: %w", err)
+	}
+	return stmt.Schema, nil
+}
+
+// latestKey 最近一次备份指针的对象键
+func (s *Service) latestKey() string {
+	return s.prefix + latestFile
+}
+
+// objectKey 生成备份目录下文件的对象键
+func (s *Service) objectKey(startedAt time.Time, name string) string {
+	return s.prefix + path.Join(startedAt.Format(dirTimeLayout), name)
+}
+
+// upload 压缩并上传备份文件，write负责写入未压缩的内容
+func (s *Service) upload(ctx context.Context, objectKey string, write func(w io.Writer) error) error {
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzip.NewWriter(pw)
+		err := write(zw)
+		if closeErr := zw.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	if _, err := s.storage.UploadFile(ctx, s.bucket, objectKey, pr, "application/gzip"); err != nil {
+		pr.CloseWithError(err)
+		return fmt.Errorf("上传备份文件 %s 失败: %w", objectKey, err)
+	}
+	return nil
+}
+
+// download 下载并解压备份文件
+func (s *Service) download(ctx context.Context, objectKey string) (io.Reader, error) {
+	var buf bytes.Buffer
+	if err := s.storage.DownloadFile(ctx, s.bucket, objectKey, &buf); err != nil {
+		return nil, fmt.Errorf("下载备份文件 %s 失败: %w", objectKey, err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("解压备份文件 %s 失败: %w", objectKey, err)
+	}
+	return zr, nil
+}
+
+// putJSON 上传JSON文件
+func (s *Service) putJSON(ctx context.Context, objectKey string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := s.storage.UploadFile(ctx, s.bucket, objectKey, bytes.NewReader(data), "application/json"); err != nil {
+		return fmt.Errorf("上传 %s 失败: %w", objectKey, err)
+	}
+	return nil
+}
+
+// getJSON 下载并解析JSON文件，文件不存在时返回cos.ErrObjectNotFound
+func (s *Service) getJSON(ctx context.Context, objectKey string, v interface{}) error {
+	if _, err := s.storage.StatFile(ctx, s.bucket, objectKey); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := s.storage.DownloadFile(ctx, s.bucket, objectKey, &buf); err != nil {
+		return fmt.Errorf("下载 %s 失败: %w", objectKey, err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", objectKey, err)
+	}
+	return nil
+}
+
+// getLatest 读取最近一次备份指针，没有备份时返回nil
+func (s *Service) getLatest(ctx context.Context) (*latest, error) {
+	var l latest
+	if err := s.getJSON(ctx, s.latestKey(), &l); err != nil {
+		if errors.Is(err, cos.ErrObjectNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &l, nil
+}
+
+// getManifest 读取备份清单
+func (s *Service) getManifest(ctx context.Context, objectKey string) (*Manifest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := s.getJSON(ctx, objectKey, &m); err != nil {
+		return nil, fmt.Errorf("读取备份清单 %s 失败: %w", objectKey, err)
+	}
+	return &m, nil
+}
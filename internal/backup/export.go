@@ -0,0 +1,216 @@
+package backup
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Backup 执行备份，返回备份清单的对象键和清单内容
+// mode为auto时距上次全量备份超过配置的间隔或没有上一次备份时执行全量备份，否则执行增量备份
+func (s *Service) Backup(ctx context.Context, mode, format string) (string, *Manifest, error) {
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatCSV {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidFormat, format)
+	}
+
+	last, err := s.getLatest(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("读取最近一次备份失败: %w", err)
+	}
+
+	switch mode {
+	case ModeAuto:
+		mode = ModeIncremental
+		if format == FormatCSV || last == nil || time.Since(last.FullAt) >= s.fullInterval {
+			mode = ModeFull
+		}
+	case ModeFull:
+	case ModeIncremental:
+		if format == FormatCSV {
+			return "", nil, ErrCSVIncremental
+		}
+		if last == nil {
+			return "", nil, ErrNoBaseBackup
+		}
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidMode, mode)
+	}
+
+	manifest := &Manifest{
+		Mode:      mode,
+		Format:    format,
+		StartedAt: time.Now(),
+	}
+	if mode == ModeIncremental {
+		base, err := s.getManifest(ctx, last.Manifest)
+		if err != nil {
+			return "", nil, err
+		}
+		manifest.Base = last.Manifest
+		manifest.Since = &base.StartedAt
+	}
+
+	logger.Info(ctx, "开始备份用户内容", zap.String("mode", mode), zap.String("format", format), zap.Timep("since", manifest.Since))
+
+	for _, t := range tables {
+		sch, err := s.parseSchema(t.model)
+		if err != nil {
+			return "", nil, err
+		}
+		objectKey := s.objectKey(manifest.StartedAt, sch.Table+fileExt(format))
+
+		rows := 0
+		err = s.upload(ctx, objectKey, func(w io.Writer) error {
+			var err error
+			rows, err = s.exportTable(ctx, sch, t.model, manifest.Since, format, w)
+			return err
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("备份表 %s 失败: %w", sch.Table, err)
+		}
+
+		manifest.Tables = append(manifest.Tables, ManifestTable{Name: sch.Table, ObjectKey: objectKey, Rows: rows})
+		logger.Info(ctx, "表备份完成", zap.String("table", sch.Table), zap.Int("rows", rows), zap.String("object_key", objectKey))
+	}
+
+	// 所有表上传完成后再写入清单，没有清单的备份目录视为不完整
+	manifestKey := s.objectKey(manifest.StartedAt, manifestFile)
+	if err := s.putJSON(ctx, manifestKey, manifest); err != nil {
+		return "", nil, err
+	}
+
+	// CSV备份不能用于恢复，不作为后续增量备份的基础
+	if format == FormatJSON {
+		pointer := latest{Manifest: manifestKey, FullAt: manifest.StartedAt}
+		if mode == ModeIncremental {
+			pointer.FullAt = last.FullAt
+		}
+		if err := s.putJSON(ctx, s.latestKey(), pointer); err != nil {
+			return "", nil, err
+		}
+	}
+
+	logger.Info(ctx, "用户内容备份完成", zap.String("mode", mode), zap.String("manifest", manifestKey))
+	return manifestKey, manifest, nil
+}
+
+// fileExt 备份文件扩展名
+func fileExt(format string) string {
+	if format == FormatCSV {
+		return ".csv.gz"
+	}
+	return ".jsonl.gz"
+}
+
+// exportTable 按主键顺序分批读取表中的行并写入w，包含已软删除的行
+// since不为空时只读取该时间之后更新或软删除的行
+func (s *Service) exportTable(ctx context.Context, sch *schema.Schema, m interface{}, since *time.Time, format string, w io.Writer) (int, error) {
+	write, flush, err := newRowWriter(sch, format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	rowType := reflect.TypeOf(m).Elem()
+	var lastID uint
+	total := 0
+	for {
+		batch := reflect.New(reflect.SliceOf(rowType))
+		query := s.db.WithContext(ctx).Unscoped().Model(m).Where("id > ?", lastID)
+		if since != nil {
+			// 软删除只更新deleted_at，需要同时按删除时间筛选
+			query = query.Where("(updated_at > ? OR deleted_at > ?)", *since, *since)
+		}
+		if err := query.Order("id").Limit(s.batchSize).Find(batch.Interface()).Error; err != nil {
+			return total, err
+		}
+
+		rows := batch.Elem()
+		for i := 0; i < rows.Len(); i++ {
+			if err := write(rows.Index(i)); err != nil {
+				return total, err
+			}
+		}
+		total += rows.Len()
+
+		if rows.Len() < s.batchSize {
+			break
+		}
+		lastID = uint(rows.Index(rows.Len() - 1).FieldByIndex(sch.PrioritizedPrimaryField.StructField.Index).Uint())
+	}
+
+	return total, flush()
+}
+
+// newRowWriter 创建行写入函数，JSON格式每行一个对象，CSV格式首行为列名
+// 按数据库列名输出所有列，不受模型JSON标签影响
+func newRowWriter(sch *schema.Schema, format string, w io.Writer) (func(reflect.Value) error, func() error, error) {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		write := func(row reflect.Value) error {
+			record := make(map[string]interface{}, len(sch.DBNames))
+			for _, name := range sch.DBNames {
+				record[name] = row.FieldByIndex(sch.FieldsByDBName[name].StructField.Index).Interface()
+			}
+			return enc.Encode(record)
+		}
+		return write, func() error { return nil }, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(sch.DBNames); err != nil {
+			return nil, nil, err
+		}
+		write := func(row reflect.Value) error {
+			values := make([]string, len(sch.DBNames))
+			for i, name := range sch.DBNames {
+				values[i] = csvValue(row.FieldByIndex(sch.FieldsByDBName[name].StructField.Index))
+			}
+			return cw.Write(values)
+		}
+		flush := func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		return write, flush, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidFormat, format)
+	}
+}
+
+// csvValue 将字段值格式化为CSV单元格，空指针和未删除的删除时间为空
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case gorm.DeletedAt:
+		if !x.Valid {
+			return ""
+		}
+		return x.Time.Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,316 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// maxViolationSamples 恢复结果中每个引用最多列出的无效值数量
+const maxViolationSamples = 20
+
+// RestoreResult 恢复结果
+type RestoreResult struct {
+	Manifests  []string      `json:"manifests"`  // 按应用顺序排列的备份清单
+	Tables     []TableResult `json:"tables"`     // 各表恢复的行数
+	Violations []Violation   `json:"violations"` // 无效的关联引用
+	DryRun     bool          `json:"dry_run"`    // 是否只校验不写入
+}
+
+// TableResult 单个表的恢复结果
+type TableResult struct {
+	Name string `json:"name"` // 表名
+	Rows int    `json:"rows"` // 合并各次备份后的行数
+}
+
+// Violation 无效的关联引用，被引用的行在备份和数据库中都不存在
+type Violation struct {
+	Table   string `json:"table"`   // 引用所在的表
+	Column  string `json:"column"`  // 引用列
+	Target  string `json:"target"`  // 被引用的表
+	Count   int    `json:"count"`   // 无效引用的行数
+	Samples []uint `json:"samples"` // 部分无效的引用值
+}
+
+// tableData 从备份中读取的单个表的数据
+type tableData struct {
+	table  table
+	schema *schema.Schema
+	rows   map[uint]reflect.Value // 按主键去重，后应用的备份覆盖先应用的备份
+}
+
+// Restore 从备份恢复用户内容，manifestKey为LatestManifest时使用最近一次备份
+// 增量备份会沿Base回溯到全量备份，按顺序合并后写入；写入前校验关联引用，存在无效引用时不写入
+// 已存在的行按主键覆盖，备份之后新增的行保留；dryRun为true时只校验不写入
+func (s *Service) Restore(ctx context.Context, manifestKey string, dryRun bool) (*RestoreResult, error) {
+	chain, keys, err := s.manifestChain(ctx, manifestKey)
+	if err != nil {
+		return nil, err
+	}
+	result := &RestoreResult{Manifests: keys, DryRun: dryRun}
+
+	data := make(map[string]*tableData, len(tables))
+	ordered := make([]*tableData, 0, len(tables))
+	for _, t := range tables {
+		sch, err := s.parseSchema(t.model)
+		if err != nil {
+			return nil, err
+		}
+		td := &tableData{table: t, schema: sch, rows: make(map[uint]reflect.Value)}
+		data[sch.Table] = td
+		ordered = append(ordered, td)
+	}
+
+	for i, m := range chain {
+		for _, mt := range m.Tables {
+			td, ok := data[mt.Name]
+			if !ok {
+				logger.Warn(ctx, "备份中包含未知的表，已跳过", zap.String("table", mt.Name), zap.String("manifest", keys[i]))
+				continue
+			}
+			if err := s.loadTable(ctx, td, mt.ObjectKey); err != nil {
+				return nil, fmt.Errorf("读取表 %s 的备份失败: %w", mt.Name, err)
+			}
+		}
+	}
+
+	for _, td := range ordered {
+		result.Tables = append(result.Tables, TableResult{Name: td.schema.Table, Rows: len(td.rows)})
+	}
+
+	result.Violations, err = s.checkIntegrity(ctx, data, ordered)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Violations) > 0 {
+		return result, ErrIntegrityViolation
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, td := range ordered {
+			if err := s.writeTable(tx, td); err != nil {
+				return fmt.Errorf("写入表 %s 失败: %w", td.schema.Table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(ctx, "用户内容恢复完成", zap.Strings("manifests", keys))
+	return result, nil
+}
+
+// manifestChain 从指定备份沿Base回溯到全量备份，返回按应用顺序排列的备份清单
+func (s *Service) manifestChain(ctx context.Context, manifestKey string) ([]*Manifest, []string, error) {
+	if manifestKey == "" || manifestKey == LatestManifest {
+		last, err := s.getLatest(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取最近一次备份失败: %w", err)
+		}
+		if last == nil {
+			return nil, nil, ErrNoBaseBackup
+		}
+		manifestKey = last.Manifest
+	}
+
+	var chain []*Manifest
+	var keys []string
+	seen := make(map[string]bool)
+	for key := manifestKey; ; {
+		if seen[key] {
+			return nil, nil, fmt.Errorf("备份清单 %s 的基础备份存在循环", key)
+		}
+		seen[key] = true
+
+		m, err := s.getManifest(ctx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if m.Format == FormatCSV {
+			return nil, nil, ErrCSVNotRestorable
+		}
+		chain = append([]*Manifest{m}, chain...)
+		keys = append([]string{key}, keys...)
+
+		if m.Mode == ModeFull {
+			return chain, keys, nil
+		}
+		if m.Base == "" {
+			return nil, nil, fmt.Errorf("增量备份 %s 缺少基础备份", key)
+		}
+		key = m.Base
+	}
+}
+
+// loadTable 读取单个表的备份文件，按主键合并到已读取的数据中
+func (s *Service) loadTable(ctx context.Context, td *tableData, objectKey string) error {
+	r, err := s.download(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+
+	rowType := reflect.TypeOf(td.table.model).Elem()
+	primary := td.schema.PrioritizedPrimaryField
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("第%d行格式错误: %w", line, err)
+		}
+
+		row := reflect.New(rowType).Elem()
+		for name, raw := range record {
+			field, ok := td.schema.FieldsByDBName[name]
+			if !ok {
+				continue
+			}
+			if err := json.Unmarshal(raw, row.FieldByIndex(field.StructField.Index).Addr().Interface()); err != nil {
+				return fmt.Errorf("第%d行字段 %s 格式错误: %w", line, name, err)
+			}
+		}
+
+		td.rows[uint(row.FieldByIndex(primary.StructField.Index).Uint())] = row
+	}
+	return scanner.Err()
+}
+
+// checkIntegrity 校验关联引用，被引用的行在备份和数据库中都不存在时视为无效
+func (s *Service) checkIntegrity(ctx context.Context, data map[string]*tableData, ordered []*tableData) ([]Violation, error) {
+	var violations []Violation
+	for _, td := range ordered {
+		for _, ref := range td.table.refs {
+			target, ok := data[ref.table]
+			if !ok {
+				return nil, fmt.Errorf("表 %s 引用的表 %s 不在备份范围内", td.schema.Table, ref.table)
+			}
+			field := td.schema.FieldsByDBName[ref.column]
+
+			// 统计引用值及其行数，忽略未设置的可选引用
+			missing := make(map[uint]int)
+			for _, row := range td.rows {
+				id, ok := uintValue(row.FieldByIndex(field.StructField.Index))
+				if !ok {
+					continue
+				}
+				if _, exists := target.rows[id]; !exists {
+					missing[id]++
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+
+			// 备份中不存在的行可能仍保留在数据库中
+			existing, err := s.existingIDs(ctx, target, missing)
+			if err != nil {
+				return nil, err
+			}
+
+			v := Violation{Table: td.schema.Table, Column: ref.column, Target: ref.table}
+			for id, count := range missing {
+				if existing[id] {
+					continue
+				}
+				v.Count += count
+				v.Samples = append(v.Samples, id)
+			}
+			if v.Count == 0 {
+				continue
+			}
+			sort.Slice(v.Samples, func(i, j int) bool { return v.Samples[i] < v.Samples[j] })
+			if len(v.Samples) > maxViolationSamples {
+				v.Samples = v.Samples[:maxViolationSamples]
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// existingIDs 查询数据库中存在的主键，包含已软删除的行
+func (s *Service) existingIDs(ctx context.Context, td *tableData, ids map[uint]int) (map[uint]bool, error) {
+	list := make([]uint, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+
+	existing := make(map[uint]bool, len(list))
+	for start := 0; start < len(list); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(list) {
+			end = len(list)
+		}
+		var found []uint
+		err := s.db.WithContext(ctx).Unscoped().Model(td.table.model).
+			Where("id IN ?", list[start:end]).Pluck("id", &found).Error
+		if err != nil {
+			return nil, fmt.Errorf("查询表 %s 失败: %w", td.schema.Table, err)
+		}
+		for _, id := range found {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// writeTable 按主键顺序分批写入，已存在的行整行覆盖
+func (s *Service) writeTable(tx *gorm.DB, td *tableData) error {
+	ids := make([]uint, 0, len(td.rows))
+	for id := range td.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	sliceType := reflect.SliceOf(reflect.PointerTo(reflect.TypeOf(td.table.model).Elem()))
+	for start := 0; start < len(ids); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := reflect.MakeSlice(sliceType, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = reflect.Append(batch, td.rows[id].Addr())
+		}
+		err := tx.Session(&gorm.Session{SkipHooks: true}).
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(batch.Interface()).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uintValue 读取整数或整数指针字段，空指针和0视为未设置
+func uintValue(v reflect.Value) (uint, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	id := uint(v.Uint())
+	return id, id != 0
+}
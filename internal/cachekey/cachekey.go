@@ -0,0 +1,88 @@
+// Package cachekey 统一构造Redis键，避免各模块手写键名导致相互覆盖
+// 键的格式为 命名空间:片段1:片段2...，环境前缀（redis.key_prefix配置）由 pkg/redis 在执行命令时统一添加
+// 业务代码不应直接拼接Redis键，新增键时在本包中添加对应的构造函数，可使用 cmd/keylint 检查
+package cachekey
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// separator 键各部分之间的分隔符
+const separator = ":"
+
+// Namespace 键的命名空间，每个命名空间只能在本包中分配，同一命名空间下的键由本包保证互不冲突
+type Namespace string
+
+// 命名空间
+const (
+	NamespaceToken        Namespace = "token"             // 令牌黑名单、失效时间和版本号
+	NamespaceJWT          Namespace = "jwt"               // 令牌签名密钥轮换状态
+	NamespaceVerification Namespace = "verification_code" // 短信验证码
+	NamespaceUser         Namespace = "user"              // 用户活跃记录、主页访问去重、主页计数缓存和禁言状态
+	NamespaceStats        Namespace = "stats"             // 数据统计计数器
+	NamespacePost         Namespace = "post"              // 动态浏览统计、每日互动计数和计数写回
+	NamespaceFeed         Namespace = "feed"              // 推荐排序和发现页缓存
+	NamespaceRelation     Namespace = "relation"          // 好友推荐和通讯录摘要
+	NamespaceAntiSpam     Namespace = "antispam"          // 反垃圾计数器
+	NamespaceGeocode      Namespace = "geocode"           // 逆地理编码缓存
+	NamespaceGeoIP        Namespace = "geoip"             // IP归属地缓存
+	NamespaceTranslate    Namespace = "translate"         // 动态和评论译文缓存
+	NamespaceLinkPreview  Namespace = "linkpreview"       // 链接预览缓存
+	NamespaceAPISign      Namespace = "api_sign"          // 开放接口签名防重放
+	NamespaceSystem       Namespace = "system"            // 系统状态
+	NamespaceFeature      Namespace = "feature"           // 功能开关
+	NamespaceScheduler    Namespace = "scheduler"         // 定时任务锁和执行记录
+	NamespaceQueue        Namespace = "queue"             // 消息队列
+)
+
+// Namespaces 返回所有命名空间，用于按命名空间遍历或迁移键
+func Namespaces() []Namespace {
+	return []Namespace{
+		NamespaceToken, NamespaceJWT, NamespaceVerification, NamespaceUser, NamespaceStats,
+		NamespacePost, NamespaceFeed, NamespaceRelation, NamespaceAntiSpam, NamespaceGeocode,
+		NamespaceGeoIP, NamespaceTranslate, NamespaceLinkPreview, NamespaceAPISign, NamespaceSystem,
+		NamespaceFeature, NamespaceScheduler, NamespaceQueue,
+	}
+}
+
+// Key Redis键及其约定的过期时间
+type Key struct {
+	name string
+	ttl  time.Duration
+}
+
+// String 返回键名，不含环境前缀
+func (k Key) String() string {
+	return k.name
+}
+
+// TTL 返回键约定的过期时间，为0表示不过期或由调用方根据业务确定
+func (k Key) TTL() time.Duration {
+	return k.ttl
+}
+
+// Prefix 返回命名空间的前缀（含末尾分隔符）
+// 用于 pkg 下无法依赖本包的组件（如调度器、逆地理编码、翻译），由组件在前缀后追加自己的片段
+func (n Namespace) Prefix() string {
+	return string(n) + separator
+}
+
+// Pattern 返回匹配命名空间下所有键的模式，用于SCAN遍历
+func (n Namespace) Pattern() string {
+	return n.Prefix() + "*"
+}
+
+// key 在命名空间下构造键
+func (n Namespace) key(ttl time.Duration, parts ...string) Key {
+	return Key{
+		name: n.Prefix() + strings.Join(parts, separator),
+		ttl:  ttl,
+	}
+}
+
+// id 将ID格式化为键片段
+func id(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
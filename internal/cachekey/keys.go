@@ -0,0 +1,180 @@
+package cachekey
+
+import (
+	"time"
+
+	"app/internal/constant"
+)
+
+// TokenBlacklist 已退出登录的令牌黑名单键，id为令牌ID（jti），过期时间由调用方按令牌剩余有效期设置
+func TokenBlacklist(id string) Key {
+	return NamespaceToken.key(0, "blacklist", id)
+}
+
+// TokenVersion 用户当前令牌版本号的缓存键，版本号以数据库为准
+func TokenVersion(userID uint) Key {
+	return NamespaceToken.key(constant.TokenVersionCacheExpiration, "version", id(userID))
+}
+
+// TokenRevokedBefore 用户令牌失效时间键，早于该时间签发的令牌视为已失效
+func TokenRevokedBefore(userID uint) Key {
+	return NamespaceToken.key(constant.TokenRevokedBeforeExpiration, "revoked_before", id(userID))
+}
+
+// JWTCurrentKey 当前签发令牌使用的密钥ID，由管理后台轮换后写入，各实例签发令牌时读取
+func JWTCurrentKey() Key {
+	return NamespaceJWT.key(0, "current_kid")
+}
+
+// VerificationCode 短信验证码哈希键，不同类型的验证码互不覆盖
+func VerificationCode(codeType, mobile string) Key {
+	return NamespaceVerification.key(constant.VerificationCodeExpiration, codeType, mobile)
+}
+
+// UserLastActive 用户最近活跃时间哈希键，字段为用户ID，值为Unix时间戳
+func UserLastActive() Key {
+	return NamespaceUser.key(0, "last_active")
+}
+
+// UserProfileVisit 主页访问去重键，存在期间同一访客重复访问同一主页不再记录
+func UserProfileVisit(visiteeID, visitorID uint) Key {
+	return NamespaceUser.key(constant.ProfileVisitDedupWindow, "visit", id(visiteeID), id(visitorID))
+}
+
+// UserMute 用户禁言状态键，过期时间由调用方按禁言结束时间设置
+func UserMute(userID uint) Key {
+	return NamespaceUser.key(0, "mute", id(userID))
+}
+
+// UserProfileCounts 用户主页粉丝数和关注数缓存键
+func UserProfileCounts(userID uint) Key {
+	return NamespaceUser.key(constant.UserProfileCountsExpiration, "profile_counts", id(userID))
+}
+
+// UsernameClaim 用户名占用锁键，name为小写的用户名，修改用户名期间占用，避免并发修改为同一用户名
+func UsernameClaim(name string) Key {
+	return NamespaceUser.key(constant.UsernameClaimLockTTL, "username_claim", name)
+}
+
+// StatsDAU 日活跃用户HyperLogLog键
+func StatsDAU(day time.Time) Key {
+	return NamespaceStats.key(constant.StatsDAUExpiration, "dau", day.Format(constant.StatsDAUKeyDateLayout))
+}
+
+// StatsPanics 每日panic次数计数器键
+func StatsPanics(day time.Time) Key {
+	return NamespaceStats.key(constant.StatsPanicExpiration, "panics", day.Format(constant.StatsDAUKeyDateLayout))
+}
+
+// StatsFunnel 登录注册漏斗每日计数哈希键，字段为漏斗步骤和登录失败原因
+func StatsFunnel(day time.Time) Key {
+	return NamespaceStats.key(constant.FunnelExpiration, "funnel", day.Format(constant.StatsDAUKeyDateLayout))
+}
+
+// PostViews 动态每日去重浏览HyperLogLog键
+func PostViews(day time.Time, postID uint) Key {
+	return NamespacePost.key(constant.PostViewExpiration, "views", day.Format(constant.PostViewKeyDateLayout), id(postID))
+}
+
+// PostViewsDirty 当日产生浏览的动态ID集合键
+func PostViewsDirty(day time.Time) Key {
+	return NamespacePost.key(constant.PostViewExpiration, "views", "dirty", day.Format(constant.PostViewKeyDateLayout))
+}
+
+// PostInsights 动态每日互动计数哈希键，字段为constant.PostInsightLikes等
+func PostInsights(day time.Time, postID uint) Key {
+	return NamespacePost.key(constant.PostInsightExpiration, "insights", day.Format(constant.PostViewKeyDateLayout), id(postID))
+}
+
+// PostInsightsDirty 当日产生浏览或互动的动态ID集合键，汇总任务据此确定需要汇总的动态
+func PostInsightsDirty(day time.Time) Key {
+	return NamespacePost.key(constant.PostInsightExpiration, "insights", "dirty", day.Format(constant.PostViewKeyDateLayout))
+}
+
+// postCounterTag 动态计数写回相关键的哈希标签，集群模式下日志、增量和进度位于同一插槽，可在同一脚本中操作
+const postCounterTag = "{counters}"
+
+// PostCounterLog 动态计数增量日志Stream键，每次计数变化追加一条，用于同步到数据库和故障后重放
+func PostCounterLog() Key {
+	return NamespacePost.key(0, postCounterTag, "log")
+}
+
+// PostCounterPending 尚未同步到数据库的动态计数增量哈希键，字段为 动态ID:计数名称
+func PostCounterPending() Key {
+	return NamespacePost.key(0, postCounterTag, "pending")
+}
+
+// PostCounterSettled 已从增量哈希中扣减的最后一条日志ID键
+func PostCounterSettled() Key {
+	return NamespacePost.key(0, postCounterTag, "settled")
+}
+
+// FeedAffinity 用户作者亲密度哈希键
+func FeedAffinity(userID uint) Key {
+	return NamespaceFeed.key(constant.FeedAffinityExpiration, "affinity", id(userID))
+}
+
+// FeedRanked 用户推荐排序结果缓存键
+func FeedRanked(userID uint) Key {
+	return NamespaceFeed.key(constant.FeedRankedExpiration, "ranked", id(userID))
+}
+
+// FeedDiscover 用户发现页混合结果缓存键
+func FeedDiscover(userID uint) Key {
+	return NamespaceFeed.key(constant.FeedDiscoverExpiration, "discover", id(userID))
+}
+
+// RelationContacts 用户最近上传的通讯录手机号摘要集合键
+func RelationContacts(userID uint) Key {
+	return NamespaceRelation.key(constant.RecommendationContactExpiration, "contacts", id(userID))
+}
+
+// RelationRecommendations 用户好友推荐结果键
+func RelationRecommendations(userID uint) Key {
+	return NamespaceRelation.key(constant.RecommendationExpiration, "recommendations", id(userID))
+}
+
+// RelationRecommendDismissed 用户标记为不感兴趣的推荐用户ID集合键
+func RelationRecommendDismissed(userID uint) Key {
+	return NamespaceRelation.key(constant.RecommendationDismissExpiration, "dismissed", id(userID))
+}
+
+// AntiSpamCount 用户操作频率计数器键，action为post、comment、search或translate，过期时间为调用方配置的计数窗口
+func AntiSpamCount(action string, userID uint) Key {
+	return NamespaceAntiSpam.key(0, action, "count", id(userID))
+}
+
+// AntiSpamLastPost 用户最近一条动态的内容摘要键
+func AntiSpamLastPost(userID uint) Key {
+	return NamespaceAntiSpam.key(constant.AntiSpamLastPostExpiration, "post", "last", id(userID))
+}
+
+// GeocodeRetry 位置地址重新解析的去重键
+func GeocodeRetry(locationID uint) Key {
+	return NamespaceGeocode.key(constant.GeocodeRetryInterval, "retry", id(locationID))
+}
+
+// LinkPreviewRetry 链接预览重新抓取的去重键
+func LinkPreviewRetry(previewID uint) Key {
+	return NamespaceLinkPreview.key(constant.LinkPreviewRetryInterval, "retry", id(previewID))
+}
+
+// APISignNonce 开放接口请求随机串键，过期时间由调用方按时间戳容差设置
+func APISignNonce(appKey, nonce string) Key {
+	return NamespaceAPISign.key(0, "nonce", appKey, nonce)
+}
+
+// Maintenance 维护模式状态键，关闭维护模式前一直保留
+func Maintenance() Key {
+	return NamespaceSystem.key(0, "maintenance")
+}
+
+// FeatureFlags 功能开关哈希键，字段为开关标识
+func FeatureFlags() Key {
+	return NamespaceFeature.key(0, "flags")
+}
+
+// SMSQueue 短信发送队列的流键，消息处理后删除
+func SMSQueue() Key {
+	return NamespaceQueue.key(0, "sms")
+}
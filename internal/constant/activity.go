@@ -0,0 +1,33 @@
+package constant
+
+// 平台内部事件类型，只在平台内部处理，不对第三方应用开放订阅
+const (
+	// 点赞动态
+	EventPostLiked = "post.liked"
+	// 点赞评论
+	EventCommentLiked = "comment.liked"
+	// 关注用户，关注私密账号时在对方通过请求后发布
+	EventUserFollowed = "user.followed"
+	// 生成动态分享码
+	EventPostShared = "post.shared"
+)
+
+// 动态时间线的活动类型
+const (
+	// 发布动态
+	ActivityTypePost = "post"
+	// 发表评论
+	ActivityTypeComment = "comment"
+	// 点赞动态
+	ActivityTypePostLike = "post_like"
+	// 点赞评论
+	ActivityTypeCommentLike = "comment_like"
+	// 关注用户
+	ActivityTypeFollow = "follow"
+)
+
+// 动态时间线相关常量
+const (
+	// 活动记录中动态或评论内容摘要的最大字数
+	ActivitySummaryLength = 100
+)
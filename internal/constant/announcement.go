@@ -0,0 +1,52 @@
+package constant
+
+import "time"
+
+// 公告目标范围
+const (
+	// 全部用户
+	AnnouncementTargetAll = "all"
+	// 指定用户分群
+	AnnouncementTargetSegment = "segment"
+	// 拥有指定标签的用户
+	AnnouncementTargetTag = "tag"
+)
+
+// 公告用户分群，按公告发布时间判断用户是否属于分群
+const (
+	// 新用户：公告发布前AnnouncementNewUserWindow内及发布后注册的用户
+	AnnouncementSegmentNewUsers = "new_users"
+	// 老用户：公告发布前注册的用户
+	AnnouncementSegmentExistingUsers = "existing_users"
+	// 私密账号用户
+	AnnouncementSegmentPrivateAccounts = "private_accounts"
+)
+
+// AnnouncementSegments 支持的用户分群
+var AnnouncementSegments = []string{
+	AnnouncementSegmentNewUsers,
+	AnnouncementSegmentExistingUsers,
+	AnnouncementSegmentPrivateAccounts,
+}
+
+// 公告相关常量
+const (
+	// AnnouncementNewUserWindow 新用户分群包含公告发布前多长时间内注册的用户
+	AnnouncementNewUserWindow = 7 * 24 * time.Hour
+	// AnnouncementMaxActive 用户获取未读公告时最多检查的已发布公告数量
+	AnnouncementMaxActive = 100
+	// AnnouncementPushBatchSize 推送公告时每批查询的目标用户数量
+	AnnouncementPushBatchSize = 500
+	// AnnouncementPushSummaryLength 推送通知中公告正文摘要的最大字符数
+	AnnouncementPushSummaryLength = 50
+)
+
+// 公告相关错误
+var (
+	// 公告不存在错误
+	ErrAnnouncementNotFound = "公告不存在"
+	// 用户分群无效错误
+	ErrAnnouncementInvalidSegment = "不支持的用户分群"
+	// 过期时间无效错误
+	ErrAnnouncementInvalidExpireAt = "过期时间必须晚于发布时间"
+)
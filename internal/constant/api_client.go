@@ -0,0 +1,45 @@
+package constant
+
+import "time"
+
+// 服务端调用方状态
+const (
+	// 禁用
+	APIClientStatusDisabled = 0
+	// 启用
+	APIClientStatusEnabled = 1
+)
+
+// 请求签名相关请求头
+const (
+	// 应用标识
+	SignAppKeyHeader = "X-App-Key"
+	// 请求时间戳（Unix秒）
+	SignTimestampHeader = "X-Timestamp"
+	// 随机串，同一调用方在有效期内不可重复
+	SignNonceHeader = "X-Nonce"
+	// 请求签名，HMAC-SHA256结果的小写十六进制
+	SignSignatureHeader = "X-Signature"
+)
+
+// 请求签名相关常量
+const (
+	// 未配置时允许的客户端与服务器时间偏差
+	SignDefaultTimestampSkew = 5 * time.Minute
+	// 随机串的最小长度
+	SignNonceMinLength = 8
+	// 随机串的最大长度
+	SignNonceMaxLength = 64
+	// 生成的应用标识长度
+	SignAppKeyLength = 24
+	// 生成的应用密钥长度
+	SignAppSecretLength = 40
+)
+
+// 服务端调用方授权范围
+const (
+	// 读取运营统计数据
+	APIScopeStatsRead = "stats:read"
+	// 读取本调用方的Webhook投递记录
+	APIScopeWebhookRead = "webhook:read"
+)
@@ -0,0 +1,24 @@
+package constant
+
+// 计数校准相关常量
+const (
+	// 每批扫描的记录数
+	CounterReconcileBatchSize = 500
+	// 动态评论数
+	CounterPostComments = "post_comments"
+	// 评论点赞数
+	CounterCommentLikes = "comment_likes"
+)
+
+// 动态计数写回相关常量
+// 点赞数和评论数先在Redis中累加并写入日志，由定时任务批量同步到数据库，避免热门动态的行锁竞争
+const (
+	// 动态点赞数增量
+	PostCounterLikes = "likes"
+	// 动态评论数增量
+	PostCounterComments = "comments"
+	// 计数日志名称，用于记录数据库同步进度
+	PostCounterCheckpoint = "post_counters"
+	// 每批同步的日志条数
+	PostCounterFlushBatchSize = 1000
+)
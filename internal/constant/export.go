@@ -0,0 +1,35 @@
+package constant
+
+import "time"
+
+// 数据导出任务状态
+const (
+	// 等待处理
+	ExportStatusPending = "pending"
+	// 处理中
+	ExportStatusProcessing = "processing"
+	// 已完成
+	ExportStatusCompleted = "completed"
+	// 处理失败
+	ExportStatusFailed = "failed"
+)
+
+// 数据导出相关常量
+const (
+	// 导出文件下载链接有效期
+	ExportDownloadExpiration = 24 * time.Hour
+	// 导出文件在对象存储中的前缀
+	ExportObjectKeyPrefix = "exports/"
+	// 处理中状态的超时时间，超过后视为进程中断，任务标记为失败，需大于定时任务的超时时间
+	ExportProcessingTimeout = 30 * time.Minute
+	// 处理中状态超时的失败原因
+	ExportStaleErrorMessage = "导出任务处理超时"
+	// 更新失败状态的超时时间，任务上下文已取消时仍需写回状态
+	ExportStatusUpdateTimeout = 5 * time.Second
+	// 每次定时任务处理的导出任务数量
+	ExportBatchSize = 10
+	// 分页收集用户数据时的每页数量
+	ExportPageSize = 100
+	// 导出完成通知短信模板配置键
+	ExportSMSTemplateKey = "data_export"
+)
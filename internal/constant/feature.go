@@ -0,0 +1,12 @@
+package constant
+
+// 功能开关标识，开关未创建时的默认值见配置 feature_flag.defaults
+const (
+	// 关注动态推荐排序，关闭时recommended排序回退为按时间排序
+	FeatureRankedFeed = "ranked_feed"
+	// 通讯录好友发现
+	FeatureContactDiscovery = "contact_discovery"
+)
+
+// 功能开关标识格式：小写字母开头，由小写字母、数字和下划线组成
+const FeatureFlagKeyPattern = `^[a-z][a-z0-9_]{1,49}$`
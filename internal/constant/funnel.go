@@ -0,0 +1,39 @@
+package constant
+
+import "time"
+
+// 登录注册漏斗步骤，同时作为分析日志的事件名和每日计数的字段名
+const (
+	// 登录验证码已加入发送队列
+	FunnelCodeSent = "code_sent"
+	// 登录验证码校验通过
+	FunnelCodeVerified = "code_verified"
+	// 首次登录创建了新用户
+	FunnelUserCreated = "user_created"
+	// 登录成功并签发了令牌
+	FunnelLoginSuccess = "login_success"
+	// 登录失败，附带失败原因
+	FunnelLoginFailed = "login_failed"
+)
+
+// 登录失败原因
+const (
+	// 验证码错误、不存在或已过期
+	LoginFailInvalidCode = "invalid_code"
+	// 验证码错误次数过多已锁定
+	LoginFailCodeLocked = "code_locked"
+	// 账号已注销
+	LoginFailDeactivated = "deactivated"
+	// 账号已被禁用
+	LoginFailDisabled = "disabled"
+	// 创建用户、签发令牌等内部错误
+	LoginFailInternal = "internal"
+)
+
+// 登录注册漏斗相关常量
+const (
+	// 每日漏斗计数的保留时间，覆盖统计数据的最大查询范围
+	FunnelExpiration = (StatsMaxQueryDays + 1) * 24 * time.Hour
+	// 每日计数中登录失败原因字段的前缀，完整字段为前缀加失败原因
+	FunnelFailReasonFieldPrefix = "login_failed."
+)
@@ -0,0 +1,55 @@
+package constant
+
+import "time"
+
+// 图片上传相关常量
+const (
+	// 单张图片大小上限（字节）
+	ImageMaxSize = 10 * 1024 * 1024
+	// 批量上传单次最多的图片数量
+	ImageMaxFiles = 10
+	// 解析图片宽高时读取的文件头大小（字节），需容纳JPEG的EXIF等元数据段
+	ImageHeaderSize = 256 * 1024
+	// 多文件表单中除文件内容外的分隔符和字段等开销上限（字节）
+	ImageMultipartOverhead = 1024 * 1024
+	// 临时图片对象键前缀，完整格式为 前缀+用户ID/时间戳+扩展名
+	ImageTempKeyPrefix = "temp/"
+	// 预签名上传URL的有效期
+	ImagePresignExpiration = 15 * time.Minute
+	// 缩略图处理参数（腾讯云数据万象），等比缩放到宽度不超过480像素
+	ImageThumbProcess = "imageMogr2/thumbnail/480x"
+)
+
+// 私有桶图片预签名URL刷新相关常量
+const (
+	// 每批刷新的图片数量
+	ImageURLRefreshBatchSize = 200
+	// 预签名URL默认在过期前多久重新生成
+	ImageURLDefaultRefreshBefore = 24 * time.Hour
+)
+
+// 图片上传方式，用于按上传方式配置图片处理
+const (
+	// 经服务器上传
+	ImageUploadTypeServer = "upload"
+	// 客户端通过预签名URL直传COS
+	ImageUploadTypePresign = "presign"
+)
+
+// 图片发布场景，用于图片审核
+const (
+	// 评论图片或表情
+	ImageSceneComment = "comment"
+)
+
+// 创建动态时单张图片的关联状态
+const (
+	// 已关联到动态
+	PostImageStatusAttached = "attached"
+	// 关联失败，动态未创建
+	PostImageStatusFailed = "failed"
+	// 已复制但因其他图片关联失败而回滚
+	PostImageStatusRolledBack = "rolled_back"
+	// 因之前的图片关联失败而未处理
+	PostImageStatusSkipped = "skipped"
+)
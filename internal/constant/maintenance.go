@@ -0,0 +1,9 @@
+package constant
+
+// 维护模式相关常量
+const (
+	// 未指定时返回给客户端的建议重试间隔（秒）
+	MaintenanceDefaultRetryAfter = 300
+	// 未指定时返回给客户端的维护提示
+	MaintenanceDefaultMessage = "系统维护中，请稍后再试"
+)
@@ -0,0 +1,7 @@
+package constant
+
+// 禁言相关常量
+const (
+	// 被禁言的用户调用发布、评论和点赞接口时返回的提示
+	MuteMessage = "账号已被禁言，禁言期间无法发布、评论和点赞"
+)
@@ -0,0 +1,83 @@
+package constant
+
+import "time"
+
+// 通知渠道常量
+const (
+	// NotificationChannelPush 推送通知
+	NotificationChannelPush = "push"
+	// NotificationChannelSMS 短信通知
+	NotificationChannelSMS = "sms"
+	// NotificationChannelEmail 邮件通知
+	NotificationChannelEmail = "email"
+)
+
+// 通知事件类型常量
+const (
+	// NotificationEventFollowRequest 有用户请求关注私密账号
+	NotificationEventFollowRequest = "follow_request"
+	// NotificationEventFollowApproved 关注请求已被通过
+	NotificationEventFollowApproved = "follow_approved"
+	// NotificationEventDataExport 个人数据导出已完成
+	NotificationEventDataExport = "data_export"
+	// NotificationEventPostLike 动态被点赞
+	NotificationEventPostLike = "post_like"
+	// NotificationEventComment 动态被评论或评论被回复
+	NotificationEventComment = "comment"
+	// NotificationEventCommentLike 评论被点赞
+	NotificationEventCommentLike = "comment_like"
+	// NotificationEventAnnouncement 管理员发布系统公告
+	NotificationEventAnnouncement = "announcement"
+)
+
+// NotificationEvents 用户可设置通知偏好的事件类型
+var NotificationEvents = []string{
+	NotificationEventFollowRequest,
+	NotificationEventFollowApproved,
+	NotificationEventDataExport,
+	NotificationEventPostLike,
+	NotificationEventComment,
+	NotificationEventCommentLike,
+	NotificationEventAnnouncement,
+}
+
+// NotificationDefaultChannels 各事件默认开启的通知渠道，注册时写入，未保存偏好的用户也按此发送
+var NotificationDefaultChannels = map[string][]string{
+	NotificationEventFollowRequest:  {NotificationChannelPush},
+	NotificationEventFollowApproved: {NotificationChannelPush},
+	NotificationEventDataExport:     {NotificationChannelPush, NotificationChannelSMS},
+	NotificationEventPostLike:       {NotificationChannelPush},
+	NotificationEventComment:        {NotificationChannelPush},
+	NotificationEventCommentLike:    {NotificationChannelPush},
+	NotificationEventAnnouncement:   {NotificationChannelPush},
+}
+
+// 免打扰时段相关常量
+const (
+	// NotificationQuietTimeLayout 免打扰开始和结束时间的格式
+	NotificationQuietTimeLayout = "15:04"
+	// NotificationDefaultQuietStart 默认免打扰开始时间
+	NotificationDefaultQuietStart = "22:00"
+	// NotificationDefaultQuietEnd 默认免打扰结束时间
+	NotificationDefaultQuietEnd = "08:00"
+	// NotificationDefaultTimezone 默认免打扰时区
+	NotificationDefaultTimezone = "Asia/Shanghai"
+)
+
+// 设备推送令牌相关常量
+const (
+	// DevicePlatformIOS iOS设备
+	DevicePlatformIOS = "ios"
+	// DevicePlatformAndroid Android设备
+	DevicePlatformAndroid = "android"
+	// DeviceTokenMaxPerUser 每个用户保留的设备令牌数，超出时删除最久未上报的
+	DeviceTokenMaxPerUser = 10
+)
+
+// 通知发送相关常量
+const (
+	// NotificationDispatchTimeout 后台发送一条通知的超时时间
+	NotificationDispatchTimeout = 30 * time.Second
+	// NotificationContentSummaryLength 通知中评论内容摘要的最大字符数
+	NotificationContentSummaryLength = 50
+)
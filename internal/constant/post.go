@@ -0,0 +1,167 @@
+package constant
+
+import "time"
+
+// 动态浏览量相关常量
+const (
+	// 浏览统计键的日期格式
+	PostViewKeyDateLayout = "20060102"
+	// 浏览统计数据在Redis中的保留时间，超过后未同步的数据将丢失
+	PostViewExpiration = 3 * 24 * time.Hour
+	// 同步浏览量时回溯的天数（不含当天）
+	PostViewFlushDays = 2
+)
+
+// 动态数据分析相关常量
+const (
+	// 动态每日互动计数在Redis中的保留时间，超过后未汇总的数据将丢失
+	PostInsightExpiration = 3 * 24 * time.Hour
+	// 汇总动态每日数据时回溯的天数（不含当天），需小于保留时间
+	PostInsightFlushDays = 2
+	// 汇总时每批查询的动态数量
+	PostInsightBatchSize = 500
+)
+
+// 动态每日互动计数的字段
+const (
+	// 点赞数
+	PostInsightLikes = "likes"
+	// 评论数
+	PostInsightComments = "comments"
+	// 分享数
+	PostInsightShares = "shares"
+	// 通过动态关注作者的用户数
+	PostInsightFollowerGains = "follower_gains"
+)
+
+// 动态列表排序方式
+const (
+	// 按发布时间倒序
+	PostSortLatest = "latest"
+	// 按推荐得分排序
+	PostSortRecommended = "recommended"
+	// 发现页，按比例混合关注、附近和热门动态
+	PostSortDiscover = "discover"
+)
+
+// 动态回收站相关常量
+const (
+	// 删除的动态在回收站中的保留时间，超过后不能恢复并由定时任务永久清除
+	PostTrashRetention = 30 * 24 * time.Hour
+	// 永久清除时每批处理的动态数量
+	PostTrashPurgeBatchSize = 100
+)
+
+// 评论列表排序方式
+const (
+	// 按发布时间倒序
+	CommentSortLatest = "latest"
+	// 按点赞数倒序，点赞数相同时按发布时间倒序
+	CommentSortHot = "hot"
+)
+
+// 推荐排序相关常量
+const (
+	// 亲密度缓存有效期，需长于刷新任务的执行间隔
+	FeedAffinityExpiration = 48 * time.Hour
+	// 推荐排序结果缓存有效期
+	FeedRankedExpiration = 5 * time.Minute
+	// 参与排序的候选动态数量
+	FeedCandidateSize = 200
+	// 统计作者亲密度时回溯的互动时间范围
+	FeedAffinityWindow = 30 * 24 * time.Hour
+	// 刷新亲密度时只处理该时间范围内活跃过的用户
+	FeedActiveUserWindow = 7 * 24 * time.Hour
+	// 刷新亲密度时每批处理的用户数量
+	FeedAffinityBatchSize = 100
+)
+
+// 发现页动态来源名称，与配置feed.discover.weights的key一致
+const (
+	// 关注用户和好友的动态
+	FeedSourceFollowed = "followed"
+	// 附近的公开动态
+	FeedSourceNearby = "nearby"
+	// 近期互动最多的公开动态
+	FeedSourceTrending = "trending"
+)
+
+// 发现页相关常量
+const (
+	// 发现页混合结果缓存有效期
+	FeedDiscoverExpiration = 5 * time.Minute
+	// 每个来源默认最多取的候选动态数
+	FeedDiscoverCandidateSize = 100
+	// 附近动态默认搜索半径（公里）
+	FeedNearbyRadius = 5.0
+	// 附近动态默认时间范围
+	FeedNearbyWindow = 7 * 24 * time.Hour
+	// 热门动态默认时间范围
+	FeedTrendingWindow = 3 * 24 * time.Hour
+)
+
+// 反垃圾相关常量
+const (
+	// 最近一条动态内容摘要的保留时间
+	AntiSpamLastPostExpiration = 24 * time.Hour
+	// 发布动态默认限流时间窗口
+	AntiSpamDefaultPostWindow = 10 * time.Minute
+	// 发布评论默认限流时间窗口
+	AntiSpamDefaultCommentWindow = time.Minute
+	// 搜索用户默认限流时间窗口
+	AntiSpamDefaultSearchWindow = time.Minute
+	// 翻译默认限流时间窗口
+	AntiSpamDefaultTranslateWindow = time.Hour
+)
+
+// 反垃圾相关错误信息
+const (
+	// 操作过于频繁
+	ErrRateLimited = "操作过于频繁，请稍后再试"
+	// 重复内容
+	ErrDuplicateContent = "请勿重复发布相同内容"
+)
+
+// 动态位置相关常量
+const (
+	// 后台解析位置地址的超时时间，超过该时间仍未解析出地址的位置在读取时重新解析
+	GeocodeResolveTimeout = 10 * time.Second
+	// 同一位置重新解析的最小间隔
+	GeocodeRetryInterval = 10 * time.Minute
+	// 位置geohash编码的长度，9位时格子约为5米
+	LocationGeohashPrecision = 9
+)
+
+// 附近动态地图相关常量
+const (
+	// 默认搜索半径（公里）
+	NearbyMapDefaultRadius = 5.0
+	// 最大搜索半径（公里）
+	NearbyMapMaxRadius = 50.0
+	// 只显示该时间范围内发布的动态
+	NearbyMapWindow = 30 * 24 * time.Hour
+	// 单次最多返回的聚合点数，按动态数量降序截取
+	NearbyMapMaxClusters = 200
+	// 未指定缩放级别时，搜索范围直径上最多划分的聚合格数
+	NearbyMapGridCells = 8
+	// 查询时匹配的geohash前缀数量上限，超过时使用更短的前缀
+	NearbyMapMaxCoverCells = 16
+)
+
+// 链接预览抓取状态
+const (
+	// LinkPreviewPending 待抓取
+	LinkPreviewPending = 0
+	// LinkPreviewSuccess 抓取成功
+	LinkPreviewSuccess = 1
+	// LinkPreviewFailed 抓取失败或网页未提供预览信息
+	LinkPreviewFailed = 2
+)
+
+// 链接预览相关常量
+const (
+	// 后台抓取链接预览的超时时间，超过该时间仍待抓取的链接在读取时重新抓取
+	LinkPreviewFetchTimeout = 15 * time.Second
+	// 同一链接重新抓取的最小间隔
+	LinkPreviewRetryInterval = 10 * time.Minute
+)
@@ -0,0 +1,17 @@
+package constant
+
+// 使用公开ID的资源类型
+const (
+	// 用户
+	PublicIDUser = "user"
+	// 动态
+	PublicIDPost = "post"
+	// 评论
+	PublicIDComment = "comment"
+)
+
+// 公开ID相关常量
+const (
+	// 单次批量查询的ID数量上限
+	PublicIDBatchSize = 500
+)
@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 // SocialRelationType 社交关系类型
 type SocialRelationType int
 
@@ -10,6 +12,16 @@ const (
 	SocialRelationTypeFriend SocialRelationType = 2
 )
 
+// FollowStatus 关注关系状态
+type FollowStatus int
+
+const (
+	// 关注已通过状态
+	FollowStatusApproved FollowStatus = 1
+	// 关注请求待审核状态（关注私密账号时）
+	FollowStatusPending FollowStatus = 2
+)
+
 // FriendStatus 好友关系状态
 type FriendStatus int
 
@@ -20,6 +32,14 @@ const (
 	FriendStatusConfirmed FriendStatus = 1
 )
 
+// 好友关系方向（双记录模式）
+const (
+	// 发起方
+	FriendDirectionSender = 0
+	// 接收方
+	FriendDirectionReceiver = 1
+)
+
 // Visibility 内容可见性类型
 type Visibility int
 
@@ -30,4 +50,103 @@ const (
 	VisibilityFriends Visibility = 2
 	// 私密可见
 	VisibilityPrivate Visibility = 3
+	// 仅指定好友列表可见
+	VisibilityList Visibility = 4
+)
+
+// 好友列表相关常量
+const (
+	// 每个用户最多创建的好友列表数量
+	AudienceListMaxLists = 20
+	// 每个好友列表最多包含的成员数量
+	AudienceListMaxMembers = 500
+)
+
+// 动态屏蔽相关常量
+const (
+	// 每个用户最多屏蔽的用户数量
+	PostExclusionMaxUsers = 1000
+	// 每次添加或移除屏蔽的用户数量上限
+	PostExclusionBatchMax = 100
+)
+
+// 好友推荐相关常量
+const (
+	// 每个用户保留的推荐数量上限
+	RecommendationMaxSize = 50
+	// 每种来源最多统计的候选用户数量
+	RecommendationCandidateLimit = 200
+	// 推荐结果缓存有效期，需长于计算任务的执行间隔
+	RecommendationExpiration = 48 * time.Hour
+	// 计算推荐时只处理该时间范围内活跃过的用户
+	RecommendationActiveUserWindow = 7 * 24 * time.Hour
+	// 计算推荐时每批处理的用户数量
+	RecommendationBatchSize = 100
+	// 用户上传的通讯录手机号摘要保留时间，用于计算通讯录推荐
+	RecommendationContactExpiration = 30 * 24 * time.Hour
+	// 不感兴趣的推荐用户的屏蔽时间
+	RecommendationDismissExpiration = 90 * 24 * time.Hour
+)
+
+// 推荐理由类型
+const (
+	// 共同好友
+	RecommendReasonMutualFriends = "mutual_friends"
+	// 共同关注，即关注的人也关注了对方
+	RecommendReasonMutualFollows = "mutual_follows"
+	// 通讯录联系人
+	RecommendReasonContact = "contact"
+)
+
+// 推荐理由的得分权重
+const (
+	// 每位共同好友的得分
+	RecommendWeightMutualFriend = 3.0
+	// 每位共同关注的得分
+	RecommendWeightMutualFollow = 1.0
+	// 通讯录联系人的得分
+	RecommendWeightContact = 5.0
+)
+
+// RecommendReasonText 各推荐理由的展示文案格式，%d为数量
+var RecommendReasonText = map[string]string{
+	RecommendReasonMutualFriends: "%d位共同好友",
+	RecommendReasonMutualFollows: "%d位你关注的人也关注了TA",
+	RecommendReasonContact:       "通讯录联系人",
+}
+
+// 批量关系操作相关常量
+const (
+	// 每次批量关注、取消关注或移除粉丝的目标用户数量上限
+	RelationBatchMaxTargets = 100
+	// 批量写入时每个事务处理的关系数量，某一批失败时只回滚该批
+	RelationBatchChunkSize = 20
+)
+
+// 批量关系操作中单个目标用户的处理结果
+const (
+	// 已关注
+	RelationBatchFollowed = "followed"
+	// 已发送关注请求，等待私密账号审核
+	RelationBatchRequested = "requested"
+	// 已取消关注或撤回关注请求
+	RelationBatchUnfollowed = "unfollowed"
+	// 已移除粉丝
+	RelationBatchRemoved = "removed"
+)
+
+// 社交关系图导出相关常量
+const (
+	// 导出关注关系
+	GraphExportTypeFollow = "follow"
+	// 导出好友关系
+	GraphExportTypeFriend = "friend"
+	// CSV格式，首行为列名
+	GraphExportFormatCSV = "csv"
+	// JSON Lines格式，每行一个JSON对象
+	GraphExportFormatJSONL = "jsonl"
+	// 每批从数据库读取并输出的关系数量
+	GraphExportChunkSize = 1000
+	// 流式导出粉丝列表时每批读取并输出的粉丝数量
+	FollowerExportChunkSize = 500
 )
@@ -0,0 +1,25 @@
+package constant
+
+// 定时程序管理接口角色
+const (
+	// 只读角色，可查看任务信息
+	SchedulerRoleViewer = "viewer"
+	// 操作员角色，可查看并手动执行任务
+	SchedulerRoleOperator = "operator"
+)
+
+// 定时程序管理接口权限
+const (
+	// 查看任务信息权限
+	SchedulerPermissionRead = "read"
+	// 手动执行任务权限
+	SchedulerPermissionRun = "run"
+	// 访问运行时调试接口权限
+	SchedulerPermissionDebug = "debug"
+)
+
+// 定时程序管理接口认证相关常量
+const (
+	// 静态访问令牌请求头名称
+	SchedulerTokenHeader = "X-Scheduler-Token"
+)
@@ -0,0 +1,19 @@
+package constant
+
+import "time"
+
+// 请求限制相关常量
+const (
+	// 未配置时的请求处理超时
+	RequestDefaultTimeout = 10 * time.Second
+	// 未配置时的请求体大小上限（字节）
+	RequestDefaultMaxBodySize = 1 << 20
+)
+
+// 响应压缩相关常量
+const (
+	// gzip压缩级别
+	CompressionGzipLevel = 5
+	// brotli压缩级别，动态响应使用较低级别以兼顾压缩率和CPU开销
+	CompressionBrotliLevel = 4
+)
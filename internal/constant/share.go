@@ -0,0 +1,25 @@
+package constant
+
+// 分享目标类型
+const (
+	// 用户主页
+	ShareTargetUser = "user"
+	// 动态
+	ShareTargetPost = "post"
+)
+
+// 分享二维码相关常量
+const (
+	// 二维码图片对象键前缀，完整格式为 前缀+目标类型/目标ID/内容摘要.png
+	ShareQRCodeKeyPrefix = "qrcode/"
+	// 未配置时的二维码图片边长（像素）
+	ShareQRCodeDefaultSize = 512
+	// 未配置时的分享深度链接前缀
+	ShareDefaultDeepLinkBase = "livefe://share"
+	// 分享链接签名参数名
+	ShareSignParam = "sig"
+	// 分享链接签名取HMAC结果的十六进制前缀长度
+	ShareSignLength = 16
+	// 扫码结果中动态内容摘要的最大字符数
+	ShareContentSummaryLength = 100
+)
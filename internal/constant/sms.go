@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 // SMSType 短信类型
 type SMSType string
 
@@ -16,12 +18,32 @@ const (
 
 // 短信状态常量
 const (
+	// 等待发送，已加入发送队列
+	SMSStatusPending = "pending"
 	// 发送成功
 	SMSStatusSuccess = "success"
 	// 发送失败
 	SMSStatusFailed = "failed"
 )
 
+// 短信发送队列相关常量
+const (
+	// 消费者组名称
+	SMSQueueGroup = "sms_sender"
+	// 单条短信最多尝试发送的次数，超过后标记为发送失败
+	SMSQueueMaxAttempts = 3
+	// 发送失败或消费者异常退出后，消息未确认超过该时间时重新认领发送
+	SMSQueueRetryInterval = 10 * time.Second
+	// 每次读取的消息数量
+	SMSQueueBatchSize = 10
+	// 没有新消息时阻塞等待的时间，需小于Redis操作的默认超时时间
+	SMSQueueBlock = 2 * time.Second
+	// 流的近似最大长度，超过后裁剪最早的消息
+	SMSQueueMaxLen = 100000
+	// 读取队列失败后重试的间隔
+	SMSQueueErrorBackoff = time.Second
+)
+
 // 阿里云短信相关常量
 const (
 	// 阿里云短信默认接入点
@@ -0,0 +1,17 @@
+package constant
+
+import "time"
+
+// 数据统计相关常量
+const (
+	// 日活跃用户统计键的日期格式
+	StatsDAUKeyDateLayout = "20060102"
+	// 日活跃用户统计数据保留时间
+	StatsDAUExpiration = 7 * 24 * time.Hour
+	// 统计日期格式
+	StatsDateLayout = "2006-01-02"
+	// 单次查询统计数据的最大天数
+	StatsMaxQueryDays = 90
+	// 每日panic次数计数器保留时间
+	StatsPanicExpiration = 30 * 24 * time.Hour
+)
@@ -0,0 +1,48 @@
+package constant
+
+// 用户标签类型
+const (
+	// 手动标签，由管理员添加和移除成员
+	TagTypeManual = "manual"
+	// 规则标签，由定时任务按规则计算成员
+	TagTypeRule = "rule"
+)
+
+// 规则标签的规则类型，阈值含义见各规则说明
+const (
+	// 最近N天内活跃过的用户
+	TagRuleActiveDays = "active_days"
+	// 发布过至少N条动态的用户
+	TagRuleMinPosts = "min_posts"
+	// 拥有至少N个粉丝的用户
+	TagRuleMinFollowers = "min_followers"
+)
+
+// TagRules 支持的规则类型
+var TagRules = []string{
+	TagRuleActiveDays,
+	TagRuleMinPosts,
+	TagRuleMinFollowers,
+}
+
+// 用户标签相关常量
+const (
+	// TagNamePattern 标签名称格式，与功能开关标识规则一致，便于在开关和公告中引用
+	TagNamePattern = `^[a-z][a-z0-9_]{1,49}$`
+	// TagRefreshBatchSize 计算规则标签成员时每批处理的用户数量
+	TagRefreshBatchSize = 1000
+)
+
+// 用户标签相关错误
+var (
+	// 标签不存在错误
+	ErrTagNotFound = "标签不存在"
+	// 标签名称格式错误
+	ErrTagInvalidName = "标签名称只能包含小写字母、数字和下划线，且以字母开头，长度为2-50个字符"
+	// 标签名称已存在错误
+	ErrTagNameTaken = "标签名称已存在"
+	// 规则无效错误
+	ErrTagInvalidRule = "标签规则无效，只有规则标签可以设置规则，且阈值必须大于0"
+	// 规则标签不能手动修改成员错误
+	ErrTagRuleManaged = "规则标签的成员由系统计算，不能手动修改"
+)
@@ -8,24 +8,89 @@ const (
 	UserStatusNormal = 1
 	// 用户状态：禁用
 	UserStatusDisabled = 0
+	// 用户状态：休眠（长时间未活跃，重新登录后恢复正常）
+	UserStatusDormant = 2
+	// 用户状态：注销冷静期（账号暂停使用，冷静期内重新登录可撤销注销）
+	UserStatusDeactivating = 3
 )
 
 // 验证码相关常量
 const (
-	// 登录验证码Redis前缀
-	VerificationCodePrefixLogin = "verification_code:login:"
-	// 注销验证码Redis前缀
-	VerificationCodePrefixDeactivate = "verification_code:deactivate:"
 	// 验证码有效期（5分钟）
 	VerificationCodeExpiration = 5 * time.Minute
 	// 验证码长度
 	VerificationCodeLength = 6
+	// 验证码最大尝试次数，超过后验证码被锁定
+	VerificationCodeMaxAttempts = 5
+	// 验证码Redis哈希字段：验证码摘要
+	VerificationCodeFieldHash = "hash"
+	// 验证码Redis哈希字段：验证码类型
+	VerificationCodeFieldType = "type"
+	// 验证码Redis哈希字段：已尝试次数
+	VerificationCodeFieldAttempts = "attempts"
 )
 
 // 用户认证相关常量
 const (
-	// 令牌黑名单前缀
-	TokenBlacklistPrefix = "token:blacklist:"
+	// 用户令牌失效时间记录的保留时间，需不短于令牌有效期
+	TokenRevokedBeforeExpiration = 30 * 24 * time.Hour
+	// 用户令牌版本号的缓存时间，缓存缺失时从数据库读取
+	TokenVersionCacheExpiration = 24 * time.Hour
+)
+
+// 用户活跃与休眠清理相关常量
+const (
+	// 同步最近活跃时间时每批处理的数量
+	UserLastActiveSyncBatchSize = 500
+	// 每批标记为休眠的用户数量，避免长事务
+	UserCleanupBatchSize = 100
+	// 默认休眠阈值（90天）
+	UserDefaultDormantAfter = 90 * 24 * time.Hour
+	// 默认休眠用户临时图片保留时间
+	UserDefaultTempImageTTL = 24 * time.Hour
+)
+
+// 主页访客相关常量
+const (
+	// 同一访客在该时间内重复访问同一主页只记录一次
+	ProfileVisitDedupWindow = time.Hour
+	// 访问记录保留时间，超过后由定时任务删除
+	ProfileVisitRetention = 30 * 24 * time.Hour
+	// 删除过期访问记录时每批处理的数量
+	ProfileVisitPurgeBatchSize = 1000
+)
+
+// 用户主页相关常量
+const (
+	// 主页聚合信息中返回的最近动态数量
+	UserProfileRecentPosts = 10
+	// 主页粉丝数和关注数的缓存时间，他人查看时计数最多延迟该时间更新
+	UserProfileCountsExpiration = time.Minute
+)
+
+// 用户名相关常量
+const (
+	// 两次修改用户名的最短间隔
+	UsernameChangeInterval = 30 * 24 * time.Hour
+	// 修改前的用户名保护期，期间其他用户不能使用该用户名，访问旧用户名跳转到当前用户
+	UsernameReuseProtection = 90 * 24 * time.Hour
+	// 修改用户名时占用新用户名的锁有效期，避免并发修改为同一用户名
+	UsernameClaimLockTTL = 10 * time.Second
+)
+
+// UsernameReservedWords 内置的保留用户名，不区分大小写，配置中的保留用户名在此基础上追加
+var UsernameReservedWords = []string{
+	"admin", "administrator", "root", "system", "official", "support", "help",
+	"service", "security", "staff", "moderator", "api", "www", "app",
+	"me", "settings", "login", "logout", "null", "undefined",
+}
+
+// 用户角色，认证通过后写入请求上下文的roles
+const (
+	// 普通用户，所有登录用户都拥有
+	RoleUser = "user"
+	// 管理员，由配置中的管理员用户ID决定
+	RoleAdmin = "admin"
 )
 
 // 验证码类型
@@ -34,6 +99,46 @@ const (
 	VerificationTypeLogin = "login"
 	// 注销验证码类型
 	VerificationTypeDeactivate = "deactivate"
+	// 更换手机号验证码类型
+	VerificationTypeChangeMobile = "change_mobile"
+)
+
+// 账号注销数据清理阶段，按顺序执行
+const (
+	// 清除用户及其粉丝和好友的动态推荐缓存
+	DeletionStagePurgeFeeds = "purge_feeds"
+	// 匿名化用户评论
+	DeletionStageAnonymizeComments = "anonymize_comments"
+	// 删除关注和好友关系
+	DeletionStageRemoveRelations = "remove_relations"
+	// 永久删除动态及其图片，包括回收站中的动态
+	DeletionStageDeletePosts = "delete_posts"
+	// 删除临时图片
+	DeletionStageDeleteTempImages = "delete_temp_images"
+	// 等待数据保留期结束
+	DeletionStageAwaitRetention = "await_retention"
+	// 清除短信记录和用户记录中的个人信息
+	DeletionStageScrubPII = "scrub_pii"
+	// 清理完成
+	DeletionStageCompleted = "completed"
+)
+
+// 账号注销数据清理相关常量
+const (
+	// 每批处理的数据条数，避免长事务
+	DeletionBatchSize = 100
+	// 每次定时任务处理的注销任务数量
+	DeletionTaskBatchSize = 20
+	// 短信记录默认保留期（180天）
+	DeletionDefaultSMSRetention = 180 * 24 * time.Hour
+	// 注销冷静期，申请注销后账号暂停使用，冷静期结束后才删除账号
+	DeactivationCoolingOff = 24 * time.Hour
+	// 每批完成注销的冷静期到期用户数量
+	DeactivationFinalizeBatchSize = 100
+	// 申请注销通知短信模板配置键
+	DeactivationRequestedSMSTemplateKey = "deactivation_requested"
+	// 撤销注销通知短信模板配置键
+	DeactivationCancelledSMSTemplateKey = "deactivation_cancelled"
 )
 
 // 用户相关错误
@@ -42,6 +147,20 @@ var (
 	ErrUserNotFound = "用户不存在"
 	// 验证码无效错误
 	ErrInvalidCode = "验证码无效或已过期"
+	// 验证码已锁定错误
+	ErrCodeLocked = "验证码错误次数过多，请重新获取"
+	// 不支持的验证码类型错误
+	ErrUnsupportedVerificationType = "不支持的验证码类型"
 	// 注销失败错误
 	ErrDeactivateFailed = "账号注销失败"
+	// 冷静期已结束，账号已注销错误
+	ErrAccountDeactivated = "账号已注销"
+	// 账号已被禁用错误
+	ErrAccountDisabled = "账号已被禁用"
+	// 用户名已被使用错误
+	ErrUsernameTaken = "用户名已被使用"
+	// 保留用户名错误
+	ErrUsernameReserved = "该用户名为系统保留，不能使用"
+	// 修改用户名过于频繁错误
+	ErrUsernameChangeTooSoon = "用户名30天内只能修改一次"
 )
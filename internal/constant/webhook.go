@@ -0,0 +1,72 @@
+package constant
+
+import "time"
+
+// 平台事件类型，第三方应用可通过Webhook订阅
+const (
+	// 新用户注册
+	WebhookEventUserCreated = "user.created"
+	// 发布公开动态
+	WebhookEventPostCreated = "post.created"
+	// 公开动态下发表评论
+	WebhookEventCommentCreated = "comment.created"
+)
+
+// WebhookEvents 支持订阅的全部事件类型
+var WebhookEvents = []string{
+	WebhookEventUserCreated,
+	WebhookEventPostCreated,
+	WebhookEventCommentCreated,
+}
+
+// Webhook订阅状态
+const (
+	// 禁用
+	WebhookStatusDisabled = 0
+	// 启用
+	WebhookStatusEnabled = 1
+)
+
+// Webhook投递状态
+const (
+	// 等待投递或等待重试
+	WebhookDeliveryPending = "pending"
+	// 投递成功
+	WebhookDeliverySuccess = "success"
+	// 重试次数用尽或订阅已失效
+	WebhookDeliveryFailed = "failed"
+)
+
+// Webhook投递请求头
+const (
+	// 事件类型
+	WebhookEventHeader = "X-Webhook-Event"
+	// 事件ID，重试时保持不变，接收方可据此去重
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+	// 投递时间戳（Unix秒）
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// 签名，以订阅密钥对"时间戳.请求体"计算HMAC-SHA256的小写十六进制
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// Webhook相关常量
+const (
+	// 未配置时单次投递的请求超时
+	WebhookDefaultTimeout = 5 * time.Second
+	// 未配置时的最大投递次数，包含首次投递
+	WebhookDefaultMaxAttempts = 6
+	// 未配置时的首次重试间隔，之后每次翻倍
+	WebhookDefaultRetryInterval = 30 * time.Second
+	// 重试间隔的上限
+	WebhookMaxRetryInterval = 6 * time.Hour
+	// 未配置时投递记录的保留期
+	WebhookDefaultLogRetention = 30 * 24 * time.Hour
+	// 定时任务每批处理的投递记录数
+	WebhookDeliveryBatchSize = 100
+	// 投递记录被认领后的锁定时长，超过后未完成的投递可被重新认领
+	WebhookClaimLease = 2 * time.Minute
+	// 生成的订阅密钥长度
+	WebhookSecretLength = 40
+	// 记录的错误信息最大长度
+	WebhookErrorMaxLength = 500
+)
@@ -2,10 +2,19 @@
 package container
 
 import (
+	"app/internal/cachekey"
 	"app/internal/handler"
 	"app/internal/repository"
 	"app/internal/service"
 	"app/pkg/database"
+	"app/pkg/featureflag"
+	"app/pkg/geocode"
+	"app/pkg/geoip"
+	"app/pkg/linkpreview"
+	"app/pkg/push"
+	"app/pkg/redis"
+	"app/pkg/translate"
+	"context"
 	"fmt"
 	"sync"
 
@@ -14,9 +23,10 @@ import (
 
 // Container 依赖注入容器，管理应用程序中的服务和仓库实例
 type Container struct {
-	db           *gorm.DB // 数据库连接实例
-	repositories sync.Map // 存储仓库实例的并发安全映射
-	services     sync.Map // 存储服务实例的并发安全映射
+	db           *gorm.DB    // 数据库连接实例
+	store        redis.Store // Redis存储实例
+	repositories sync.Map    // 存储仓库实例的并发安全映射
+	services     sync.Map    // 存储服务实例的并发安全映射
 }
 
 var (
@@ -29,7 +39,8 @@ var (
 func GetInstance() *Container {
 	once.Do(func() {
 		instance = &Container{
-			db: database.GetDB(),
+			db:    database.GetDB(),
+			store: redis.NewStore(),
 		}
 	})
 	return instance
@@ -64,7 +75,7 @@ func (c *Container) getOrCreateService(key string, creator func() interface{}) i
 // GetUserRepository 返回用户仓库实例
 func (c *Container) GetUserRepository() repository.UserRepository {
 	repo := c.getOrCreateRepository("user_repository", func() interface{} {
-		return repository.NewUserRepository(c.db)
+		return repository.WithUserRepositoryMetrics(repository.NewUserRepository(c.db))
 	})
 	return repo.(repository.UserRepository)
 }
@@ -72,7 +83,7 @@ func (c *Container) GetUserRepository() repository.UserRepository {
 // GetSMSRepository 返回短信仓库实例
 func (c *Container) GetSMSRepository() repository.SMSRepository {
 	repo := c.getOrCreateRepository("sms_repository", func() interface{} {
-		return repository.NewSMSRepository(c.db)
+		return repository.WithSMSRepositoryMetrics(repository.NewSMSRepository(c.db))
 	})
 	return repo.(repository.SMSRepository)
 }
@@ -80,15 +91,23 @@ func (c *Container) GetSMSRepository() repository.SMSRepository {
 // GetUserFollowerRepository 返回粉丝关注仓库实例
 func (c *Container) GetUserFollowerRepository() repository.UserFollowerRepository {
 	repo := c.getOrCreateRepository("user_follower_repository", func() interface{} {
-		return repository.NewUserFollowerRepository(c.db)
+		return repository.WithUserFollowerRepositoryMetrics(repository.NewUserFollowerRepository(c.db))
 	})
 	return repo.(repository.UserFollowerRepository)
 }
 
+// GetCounterRepository 返回计数校准仓库实例
+func (c *Container) GetCounterRepository() repository.CounterRepository {
+	repo := c.getOrCreateRepository("counter_repository", func() interface{} {
+		return repository.WithCounterRepositoryMetrics(repository.NewCounterRepository(c.db))
+	})
+	return repo.(repository.CounterRepository)
+}
+
 // GetUserFriendRepository 返回好友关系仓库实例
 func (c *Container) GetUserFriendRepository() repository.UserFriendRepository {
 	repo := c.getOrCreateRepository("user_friend_repository", func() interface{} {
-		return repository.NewUserFriendRepository(c.db)
+		return repository.WithUserFriendRepositoryMetrics(repository.NewUserFriendRepository(c.db))
 	})
 	return repo.(repository.UserFriendRepository)
 }
@@ -96,18 +115,15 @@ func (c *Container) GetUserFriendRepository() repository.UserFriendRepository {
 // GetPostRepository 返回动态仓库实例
 func (c *Container) GetPostRepository() repository.PostRepository {
 	repo := c.getOrCreateRepository("post_repository", func() interface{} {
-		return repository.NewPostRepository(c.db)
+		return repository.WithPostRepositoryMetrics(repository.NewPostRepository(c.db))
 	})
 	return repo.(repository.PostRepository)
 }
 
 // GetPostCommentRepository 返回动态评论仓库实例
 func (c *Container) GetPostCommentRepository() repository.PostCommentRepository {
-	// 先获取PostRepository实例
-	postRepo := c.GetPostRepository()
-
 	repo := c.getOrCreateRepository("post_comment_repository", func() interface{} {
-		return repository.NewPostCommentRepository(c.db, postRepo)
+		return repository.WithPostCommentRepositoryMetrics(repository.NewPostCommentRepository(c.db))
 	})
 	return repo.(repository.PostCommentRepository)
 }
@@ -115,11 +131,171 @@ func (c *Container) GetPostCommentRepository() repository.PostCommentRepository
 // GetPostImageRepository 返回动态图片仓库实例
 func (c *Container) GetPostImageRepository() repository.PostImageRepository {
 	repo := c.getOrCreateRepository("post_image_repository", func() interface{} {
-		return repository.NewPostImageRepository(c.db)
+		return repository.WithPostImageRepositoryMetrics(repository.NewPostImageRepository(c.db))
 	})
 	return repo.(repository.PostImageRepository)
 }
 
+// GetPostRevisionRepository 返回动态修订记录仓库实例
+func (c *Container) GetPostRevisionRepository() repository.PostRevisionRepository {
+	repo := c.getOrCreateRepository("post_revision_repository", func() interface{} {
+		return repository.WithPostRevisionRepositoryMetrics(repository.NewPostRevisionRepository(c.db))
+	})
+	return repo.(repository.PostRevisionRepository)
+}
+
+// GetAudienceListRepository 返回好友列表仓库实例
+func (c *Container) GetAudienceListRepository() repository.AudienceListRepository {
+	repo := c.getOrCreateRepository("audience_list_repository", func() interface{} {
+		return repository.WithAudienceListRepositoryMetrics(repository.NewAudienceListRepository(c.db))
+	})
+	return repo.(repository.AudienceListRepository)
+}
+
+// GetPostExclusionRepository 返回动态屏蔽仓库实例
+func (c *Container) GetPostExclusionRepository() repository.PostExclusionRepository {
+	repo := c.getOrCreateRepository("post_exclusion_repository", func() interface{} {
+		return repository.WithPostExclusionRepositoryMetrics(repository.NewPostExclusionRepository(c.db))
+	})
+	return repo.(repository.PostExclusionRepository)
+}
+
+// GetAnnouncementRepository 返回系统公告仓库实例
+func (c *Container) GetAnnouncementRepository() repository.AnnouncementRepository {
+	repo := c.getOrCreateRepository("announcement_repository", func() interface{} {
+		return repository.WithAnnouncementRepositoryMetrics(repository.NewAnnouncementRepository(c.db))
+	})
+	return repo.(repository.AnnouncementRepository)
+}
+
+// GetTagRepository 返回用户标签仓库实例
+func (c *Container) GetTagRepository() repository.TagRepository {
+	repo := c.getOrCreateRepository("tag_repository", func() interface{} {
+		return repository.WithTagRepositoryMetrics(repository.NewTagRepository(c.db))
+	})
+	return repo.(repository.TagRepository)
+}
+
+// GetCommentLikeRepository 返回评论点赞仓库实例
+func (c *Container) GetCommentLikeRepository() repository.CommentLikeRepository {
+	repo := c.getOrCreateRepository("comment_like_repository", func() interface{} {
+		return repository.WithCommentLikeRepositoryMetrics(repository.NewCommentLikeRepository(c.db))
+	})
+	return repo.(repository.CommentLikeRepository)
+}
+
+// GetStatisticsRepository 返回数据统计仓库实例
+func (c *Container) GetStatisticsRepository() repository.StatisticsRepository {
+	repo := c.getOrCreateRepository("statistics_repository", func() interface{} {
+		return repository.WithStatisticsRepositoryMetrics(repository.NewStatisticsRepository(c.db))
+	})
+	return repo.(repository.StatisticsRepository)
+}
+
+// GetPostStatsRepository 返回动态每日数据仓库实例
+func (c *Container) GetPostStatsRepository() repository.PostStatsRepository {
+	repo := c.getOrCreateRepository("post_stats_repository", func() interface{} {
+		return repository.WithPostStatsRepositoryMetrics(repository.NewPostStatsRepository(c.db))
+	})
+	return repo.(repository.PostStatsRepository)
+}
+
+// GetDataExportRepository 返回数据导出任务仓库实例
+func (c *Container) GetDataExportRepository() repository.DataExportRepository {
+	repo := c.getOrCreateRepository("data_export_repository", func() interface{} {
+		return repository.WithDataExportRepositoryMetrics(repository.NewDataExportRepository(c.db))
+	})
+	return repo.(repository.DataExportRepository)
+}
+
+// GetAccountDeletionRepository 返回账号注销清理任务仓库实例
+func (c *Container) GetAccountDeletionRepository() repository.AccountDeletionRepository {
+	repo := c.getOrCreateRepository("account_deletion_repository", func() interface{} {
+		return repository.WithAccountDeletionRepositoryMetrics(repository.NewAccountDeletionRepository(c.db))
+	})
+	return repo.(repository.AccountDeletionRepository)
+}
+
+// GetLocationRepository 返回位置仓库实例
+func (c *Container) GetLocationRepository() repository.LocationRepository {
+	repo := c.getOrCreateRepository("location_repository", func() interface{} {
+		return repository.WithLocationRepositoryMetrics(repository.NewLocationRepository(c.db))
+	})
+	return repo.(repository.LocationRepository)
+}
+
+// GetLinkPreviewRepository 返回链接预览仓库实例
+func (c *Container) GetLinkPreviewRepository() repository.LinkPreviewRepository {
+	repo := c.getOrCreateRepository("link_preview_repository", func() interface{} {
+		return repository.WithLinkPreviewRepositoryMetrics(repository.NewLinkPreviewRepository(c.db))
+	})
+	return repo.(repository.LinkPreviewRepository)
+}
+
+// GetAPIClientRepository 返回服务端调用方仓库实例
+func (c *Container) GetAPIClientRepository() repository.APIClientRepository {
+	repo := c.getOrCreateRepository("api_client_repository", func() interface{} {
+		return repository.WithAPIClientRepositoryMetrics(repository.NewAPIClientRepository(c.db))
+	})
+	return repo.(repository.APIClientRepository)
+}
+
+// GetWebhookRepository 返回Webhook仓库实例
+func (c *Container) GetWebhookRepository() repository.WebhookRepository {
+	repo := c.getOrCreateRepository("webhook_repository", func() interface{} {
+		return repository.WithWebhookRepositoryMetrics(repository.NewWebhookRepository(c.db))
+	})
+	return repo.(repository.WebhookRepository)
+}
+
+// GetNotificationRepository 返回通知设置仓库实例
+func (c *Container) GetNotificationRepository() repository.NotificationRepository {
+	repo := c.getOrCreateRepository("notification_repository", func() interface{} {
+		return repository.WithNotificationRepositoryMetrics(repository.NewNotificationRepository(c.db))
+	})
+	return repo.(repository.NotificationRepository)
+}
+
+// GetDeviceTokenRepository 返回设备推送令牌仓库实例
+func (c *Container) GetDeviceTokenRepository() repository.DeviceTokenRepository {
+	repo := c.getOrCreateRepository("device_token_repository", func() interface{} {
+		return repository.WithDeviceTokenRepositoryMetrics(repository.NewDeviceTokenRepository(c.db))
+	})
+	return repo.(repository.DeviceTokenRepository)
+}
+
+// GetProfileVisitRepository 返回主页访问记录仓库实例
+func (c *Container) GetProfileVisitRepository() repository.ProfileVisitRepository {
+	repo := c.getOrCreateRepository("profile_visit_repository", func() interface{} {
+		return repository.WithProfileVisitRepositoryMetrics(repository.NewProfileVisitRepository(c.db))
+	})
+	return repo.(repository.ProfileVisitRepository)
+}
+
+// GetUsernameHistoryRepository 返回用户名变更记录仓库实例
+func (c *Container) GetUsernameHistoryRepository() repository.UsernameHistoryRepository {
+	repo := c.getOrCreateRepository("username_history_repository", func() interface{} {
+		return repository.WithUsernameHistoryRepositoryMetrics(repository.NewUsernameHistoryRepository(c.db))
+	})
+	return repo.(repository.UsernameHistoryRepository)
+}
+
+// GetUserActivityRepository 返回用户活动记录仓库实例
+func (c *Container) GetUserActivityRepository() repository.UserActivityRepository {
+	repo := c.getOrCreateRepository("user_activity_repository", func() interface{} {
+		return repository.WithUserActivityRepositoryMetrics(repository.NewUserActivityRepository(c.db))
+	})
+	return repo.(repository.UserActivityRepository)
+}
+
+// GetPublicIDRepository 返回公开ID映射仓库实例
+func (c *Container) GetPublicIDRepository() repository.PublicIDRepository {
+	repo := c.getOrCreateRepository("public_id_repository", func() interface{} {
+		return repository.WithPublicIDRepositoryMetrics(repository.NewPublicIDRepository(c.db))
+	})
+	return repo.(repository.PublicIDRepository)
+}
+
 // ==================== 服务实例获取方法 ====================
 
 // GetUserService 返回用户服务实例
@@ -127,13 +303,26 @@ func (c *Container) GetUserService() service.UserService {
 	svc := c.getOrCreateService("user_service", func() interface{} {
 		return service.NewUserService(
 			c.GetUserRepository(),
-			c.GetSMSRepository(),
+			c.GetSMSQueue(),
 			c.GetImageService(),
+			c.GetAccountDeletionService(),
+			c.GetNotificationService(),
+			c.GetEventPublisher(),
+			c.GetFunnelService(),
+			c.store,
 		)
 	})
 	return svc.(service.UserService)
 }
 
+// GetSMSQueue 返回短信发送队列实例
+func (c *Container) GetSMSQueue() service.SMSQueue {
+	svc := c.getOrCreateService("sms_queue", func() interface{} {
+		return service.NewSMSQueue(c.GetSMSRepository())
+	})
+	return svc.(service.SMSQueue)
+}
+
 // GetRelationService 返回用户关系服务实例
 // 整合了粉丝关注和好友关系功能
 func (c *Container) GetRelationService() service.RelationService {
@@ -142,6 +331,10 @@ func (c *Container) GetRelationService() service.RelationService {
 			c.GetUserFollowerRepository(),
 			c.GetUserFriendRepository(),
 			c.GetUserRepository(),
+			service.NewDispatchFollowNotifier(c.GetNotificationService()),
+			c.getFeatureFlagClient(),
+			c.GetEventPublisher(),
+			c.GetPublicIDService(),
 		)
 	})
 	return svc.(service.RelationService)
@@ -155,7 +348,22 @@ func (c *Container) GetPostService() service.PostService {
 			c.GetPostCommentRepository(),
 			c.GetUserRepository(),
 			c.GetPostImageRepository(),
+			c.GetCommentImageRepository(),
+			c.GetPostRevisionRepository(),
+			c.GetAudienceListRepository(),
+			c.GetCommentLikeRepository(),
+			c.GetLocationRepository(),
+			c.GetLinkPreviewRepository(),
 			c.GetImageService(),
+			c.GetPostCounterService(),
+			c.GetEventPublisher(),
+			c.GetNotificationService(),
+			c.GetPublicIDService(),
+			c.getGeocodeClient(),
+			c.getTranslateClient(),
+			c.getLinkPreviewClient(),
+			service.NewDefaultDiscoverFeed(c.GetPostRepository()),
+			c.getFeatureFlagClient(),
 		)
 	})
 	return svc.(service.PostService)
@@ -164,19 +372,29 @@ func (c *Container) GetPostService() service.PostService {
 // GetTempImageRepository 返回临时图片存储库实例
 func (c *Container) GetTempImageRepository() repository.TempImageRepository {
 	repo := c.getOrCreateRepository("temp_image_repository", func() interface{} {
-		return repository.NewTempImageRepository(c.db)
+		return repository.WithTempImageRepositoryMetrics(repository.NewTempImageRepository(c.db))
 	})
 	return repo.(repository.TempImageRepository)
 }
 
+// GetCommentImageRepository 返回评论图片存储库实例
+func (c *Container) GetCommentImageRepository() repository.CommentImageRepository {
+	repo := c.getOrCreateRepository("comment_image_repository", func() interface{} {
+		return repository.WithCommentImageRepositoryMetrics(repository.NewCommentImageRepository(c.db))
+	})
+	return repo.(repository.CommentImageRepository)
+}
+
 // GetImageService 返回图片服务实例
 func (c *Container) GetImageService() service.ImageService {
 	svc := c.getOrCreateService("image_service", func() interface{} {
 		imageService, err := service.NewImageService(
 			c.GetPostImageRepository(),
 			c.GetTempImageRepository(),
+			c.GetCommentImageRepository(),
 			c.GetUserRepository(),
 			c.GetPostRepository(),
+			service.NewLogImageModerator(),
 		)
 		if err != nil {
 			panic(fmt.Sprintf("创建图片服务失败: %v", err))
@@ -186,24 +404,507 @@ func (c *Container) GetImageService() service.ImageService {
 	return svc.(service.ImageService)
 }
 
+// GetShareService 返回分享服务实例
+func (c *Container) GetShareService() service.ShareService {
+	svc := c.getOrCreateService("share_service", func() interface{} {
+		shareService, err := service.NewShareService(
+			c.GetUserRepository(),
+			c.GetPostRepository(),
+			c.GetPostImageRepository(),
+			c.GetUserFriendRepository(),
+			c.GetAudienceListRepository(),
+			c.GetPublicIDService(),
+			c.GetEventPublisher(),
+		)
+		if err != nil {
+			panic(fmt.Sprintf("创建分享服务失败: %v", err))
+		}
+		return shareService
+	})
+	return svc.(service.ShareService)
+}
+
+// GetEventPublisher 返回平台事件发布实例
+// 业务服务发布的事件依次分发给Webhook投递、用户活动记录和动态数据分析
+func (c *Container) GetEventPublisher() service.EventPublisher {
+	svc := c.getOrCreateService("event_publisher", func() interface{} {
+		return service.NewEventBus(
+			c.GetWebhookService(),
+			c.GetActivityService(),
+			c.GetPostInsightService(),
+		)
+	})
+	return svc.(service.EventPublisher)
+}
+
+// GetPostInsightService 返回动态数据分析服务实例
+func (c *Container) GetPostInsightService() service.PostInsightService {
+	svc := c.getOrCreateService("post_insight_service", func() interface{} {
+		return service.NewPostInsightService(c.GetPostStatsRepository(), c.GetPostRepository(), c.GetPublicIDService())
+	})
+	return svc.(service.PostInsightService)
+}
+
+// GetActivityService 返回用户动态时间线服务实例
+func (c *Container) GetActivityService() service.ActivityService {
+	svc := c.getOrCreateService("activity_service", func() interface{} {
+		return service.NewActivityService(
+			c.GetUserActivityRepository(),
+			c.GetUserRepository(),
+			c.GetPublicIDService(),
+		)
+	})
+	return svc.(service.ActivityService)
+}
+
+// GetWebhookService 返回Webhook服务实例
+func (c *Container) GetWebhookService() service.WebhookService {
+	svc := c.getOrCreateService("webhook_service", func() interface{} {
+		return service.NewWebhookService(c.GetWebhookRepository(), c.GetAPIClientRepository())
+	})
+	return svc.(service.WebhookService)
+}
+
+// GetAnnouncementService 返回系统公告服务实例
+func (c *Container) GetAnnouncementService() service.AnnouncementService {
+	svc := c.getOrCreateService("announcement_service", func() interface{} {
+		return service.NewAnnouncementService(
+			c.GetAnnouncementRepository(),
+			c.GetUserRepository(),
+			c.GetTagService(),
+			c.GetNotificationService(),
+		)
+	})
+	return svc.(service.AnnouncementService)
+}
+
+// GetTagService 返回用户标签服务实例
+func (c *Container) GetTagService() service.TagService {
+	svc := c.getOrCreateService("tag_service", func() interface{} {
+		return service.NewTagService(c.GetTagRepository(), c.GetUserRepository())
+	})
+	return svc.(service.TagService)
+}
+
+// GetNotificationService 返回用户通知服务实例
+func (c *Container) GetNotificationService() service.NotificationService {
+	svc := c.getOrCreateService("notification_service", func() interface{} {
+		return service.NewNotificationService(
+			c.GetNotificationRepository(),
+			c.GetDeviceTokenRepository(),
+			c.GetUserRepository(),
+			c.GetSMSRepository(),
+			c.getPushClient(),
+		)
+	})
+	return svc.(service.NotificationService)
+}
+
+// GetCounterService 返回计数校准服务实例
+func (c *Container) GetCounterService() service.CounterService {
+	svc := c.getOrCreateService("counter_service", func() interface{} {
+		return service.NewCounterService(c.GetCounterRepository(), c.GetPostCounterService())
+	})
+	return svc.(service.CounterService)
+}
+
+// GetPostCounterService 返回动态计数写回服务实例
+func (c *Container) GetPostCounterService() service.PostCounterService {
+	svc := c.getOrCreateService("post_counter_service", func() interface{} {
+		return service.NewPostCounterService(c.GetCounterRepository())
+	})
+	return svc.(service.PostCounterService)
+}
+
+// GetStatsService 返回数据统计服务实例
+func (c *Container) GetStatsService() service.StatsService {
+	svc := c.getOrCreateService("stats_service", func() interface{} {
+		return service.NewStatsService(c.GetStatisticsRepository())
+	})
+	return svc.(service.StatsService)
+}
+
+// GetFunnelService 返回登录注册漏斗服务实例
+func (c *Container) GetFunnelService() service.FunnelService {
+	svc := c.getOrCreateService("funnel_service", func() interface{} {
+		return service.NewFunnelService()
+	})
+	return svc.(service.FunnelService)
+}
+
+// GetGraphExportService 返回社交关系图导出服务实例
+func (c *Container) GetGraphExportService() service.GraphExportService {
+	svc := c.getOrCreateService("graph_export_service", func() interface{} {
+		return service.NewGraphExportService(c.GetUserFollowerRepository(), c.GetUserFriendRepository())
+	})
+	return svc.(service.GraphExportService)
+}
+
+// GetFeatureService 返回功能开关服务实例
+func (c *Container) GetFeatureService() service.FeatureService {
+	svc := c.getOrCreateService("feature_service", func() interface{} {
+		return service.NewFeatureService(c.getFeatureFlagClient(), c.GetTagService())
+	})
+	return svc.(service.FeatureService)
+}
+
+// GetJWTKeyService 返回JWT签名密钥服务实例
+func (c *Container) GetJWTKeyService() service.JWTKeyService {
+	svc := c.getOrCreateService("jwt_key", func() interface{} {
+		return service.NewJWTKeyService()
+	})
+	return svc.(service.JWTKeyService)
+}
+
+// GetMetricsService 返回运行指标服务实例
+func (c *Container) GetMetricsService() service.MetricsService {
+	svc := c.getOrCreateService("metrics_service", func() interface{} {
+		return service.NewMetricsService()
+	})
+	return svc.(service.MetricsService)
+}
+
+// GetConfigService 返回运行配置服务实例
+func (c *Container) GetConfigService() service.ConfigService {
+	svc := c.getOrCreateService("config_service", func() interface{} {
+		return service.NewConfigService()
+	})
+	return svc.(service.ConfigService)
+}
+
+// GetMaintenanceService 返回维护模式服务实例
+func (c *Container) GetMaintenanceService() service.MaintenanceService {
+	svc := c.getOrCreateService("maintenance_service", func() interface{} {
+		return service.NewMaintenanceService()
+	})
+	return svc.(service.MaintenanceService)
+}
+
+// GetMuteService 返回用户禁言服务实例
+func (c *Container) GetMuteService() service.MuteService {
+	svc := c.getOrCreateService("mute_service", func() interface{} {
+		return service.NewMuteService(c.GetUserRepository())
+	})
+	return svc.(service.MuteService)
+}
+
+// GetDataExportService 返回用户数据导出服务实例
+func (c *Container) GetDataExportService() service.DataExportService {
+	svc := c.getOrCreateService("data_export_service", func() interface{} {
+		exportService, err := service.NewDataExportService(
+			c.GetDataExportRepository(),
+			c.GetUserRepository(),
+			c.GetPostRepository(),
+			c.GetPostCommentRepository(),
+			c.GetPostImageRepository(),
+			c.GetUserFollowerRepository(),
+			c.GetUserFriendRepository(),
+			c.GetNotificationService(),
+		)
+		if err != nil {
+			panic(fmt.Sprintf("创建数据导出服务失败: %v", err))
+		}
+		return exportService
+	})
+	return svc.(service.DataExportService)
+}
+
+// GetAudienceService 返回好友列表服务实例
+func (c *Container) GetAudienceService() service.AudienceService {
+	svc := c.getOrCreateService("audience_service", func() interface{} {
+		return service.NewAudienceService(
+			c.GetAudienceListRepository(),
+			c.GetUserFriendRepository(),
+			c.GetUserRepository(),
+		)
+	})
+	return svc.(service.AudienceService)
+}
+
+// GetPostPrivacyService 返回动态隐私设置服务实例
+func (c *Container) GetPostPrivacyService() service.PostPrivacyService {
+	svc := c.getOrCreateService("post_privacy_service", func() interface{} {
+		return service.NewPostPrivacyService(c.GetPostExclusionRepository(), c.GetUserRepository())
+	})
+	return svc.(service.PostPrivacyService)
+}
+
+// GetAccountDeletionService 返回账号注销数据清理服务实例
+func (c *Container) GetAccountDeletionService() service.AccountDeletionService {
+	svc := c.getOrCreateService("account_deletion_service", func() interface{} {
+		deletionService, err := service.NewAccountDeletionService(
+			c.GetAccountDeletionRepository(),
+			c.GetUserRepository(),
+			c.GetPostRepository(),
+			c.GetPostCommentRepository(),
+			c.GetTempImageRepository(),
+			c.GetUserFollowerRepository(),
+			c.GetUserFriendRepository(),
+			c.GetAudienceListRepository(),
+			c.GetPostExclusionRepository(),
+			c.GetSMSRepository(),
+			c.GetNotificationRepository(),
+			c.GetDeviceTokenRepository(),
+			c.GetProfileVisitRepository(),
+			c.GetUsernameHistoryRepository(),
+			c.GetUserActivityRepository(),
+			c.GetAnnouncementRepository(),
+			c.GetTagRepository(),
+			c.GetImageService(),
+		)
+		if err != nil {
+			panic(fmt.Sprintf("创建账号注销清理服务失败: %v", err))
+		}
+		return deletionService
+	})
+	return svc.(service.AccountDeletionService)
+}
+
+// GetProfileVisitService 返回主页访客服务实例
+func (c *Container) GetProfileVisitService() service.ProfileVisitService {
+	svc := c.getOrCreateService("profile_visit_service", func() interface{} {
+		return service.NewProfileVisitService(
+			c.GetProfileVisitRepository(),
+			c.GetUserRepository(),
+		)
+	})
+	return svc.(service.ProfileVisitService)
+}
+
+// GetProfileService 返回用户主页服务实例
+func (c *Container) GetProfileService() service.ProfileService {
+	svc := c.getOrCreateService("profile_service", func() interface{} {
+		return service.NewProfileService(
+			c.GetUserRepository(),
+			c.GetUserFollowerRepository(),
+			c.GetUserFriendRepository(),
+			c.GetPostService(),
+			c.store,
+		)
+	})
+	return svc.(service.ProfileService)
+}
+
+// GetUsernameService 返回用户名服务实例
+func (c *Container) GetUsernameService() service.UsernameService {
+	svc := c.getOrCreateService("username_service", func() interface{} {
+		return service.NewUsernameService(
+			c.GetUserRepository(),
+			c.GetUsernameHistoryRepository(),
+		)
+	})
+	return svc.(service.UsernameService)
+}
+
+// GetPublicIDService 返回公开ID服务实例
+func (c *Container) GetPublicIDService() service.PublicIDService {
+	svc := c.getOrCreateService("public_id_service", func() interface{} {
+		return service.NewPublicIDService(c.GetPublicIDRepository())
+	})
+	return svc.(service.PublicIDService)
+}
+
+// GetUserCleanupService 返回用户清理服务实例
+func (c *Container) GetUserCleanupService() service.UserCleanupService {
+	svc := c.getOrCreateService("user_cleanup_service", func() interface{} {
+		cleanupService, err := service.NewUserCleanupService(
+			c.GetUserRepository(),
+			c.GetTempImageRepository(),
+			c.GetDeviceTokenRepository(),
+		)
+		if err != nil {
+			panic(fmt.Sprintf("创建用户清理服务失败: %v", err))
+		}
+		return cleanupService
+	})
+	return svc.(service.UserCleanupService)
+}
+
+// getGeocodeClient 创建逆地理编码客户端，未配置服务密钥时返回nil
+func (c *Container) getGeocodeClient() *geocode.Client {
+	client, err := geocode.GetGeocodeClient(c.store, cachekey.NamespaceGeocode.Prefix())
+	if err != nil {
+		panic(fmt.Sprintf("创建逆地理编码客户端失败: %v", err))
+	}
+	return client
+}
+
+// GetGeoIPClient 返回IP归属地客户端实例，未配置本地数据库和在线服务时返回nil
+func (c *Container) GetGeoIPClient() *geoip.Client {
+	svc := c.getOrCreateService("geoip_client", func() interface{} {
+		client, err := geoip.GetGeoIPClient(c.store, cachekey.NamespaceGeoIP.Prefix())
+		if err != nil {
+			panic(fmt.Sprintf("创建IP归属地客户端失败: %v", err))
+		}
+		return client
+	})
+	return svc.(*geoip.Client)
+}
+
+// getTranslateClient 创建翻译客户端，未配置服务密钥时返回nil
+func (c *Container) getTranslateClient() *translate.Client {
+	client, err := translate.GetTranslateClient(c.store, cachekey.NamespaceTranslate.Prefix())
+	if err != nil {
+		panic(fmt.Sprintf("创建翻译客户端失败: %v", err))
+	}
+	return client
+}
+
+// getLinkPreviewClient 创建链接预览客户端，未启用链接预览时返回nil
+func (c *Container) getLinkPreviewClient() *linkpreview.Client {
+	return linkpreview.GetLinkPreviewClient(c.store, cachekey.NamespaceLinkPreview.Prefix())
+}
+
+// getPushClient 创建推送客户端，APNs和FCM均未配置时返回nil
+func (c *Container) getPushClient() *push.Client {
+	client, err := push.GetPushClient()
+	if err != nil {
+		panic(fmt.Sprintf("创建推送客户端失败: %v", err))
+	}
+	return client
+}
+
+// getFeatureFlagClient 返回功能开关客户端，客户端持有本地缓存，全局共享同一实例
+func (c *Container) getFeatureFlagClient() *featureflag.Client {
+	client := c.getOrCreateService("feature_flag_client", func() interface{} {
+		client := featureflag.GetClient(c.store, cachekey.FeatureFlags().String())
+		// 在查询时才获取标签服务，避免与功能开关服务的创建互相依赖
+		client.SetTagResolver(func(userID uint) []string {
+			return c.GetTagService().UserTagNames(context.Background(), userID)
+		})
+		return client
+	})
+	return client.(*featureflag.Client)
+}
+
 // ==================== 处理器实例获取方法 ====================
 
 // GetUserHandler 返回用户处理器实例
 func (c *Container) GetUserHandler() *handler.UserHandler {
-	return handler.NewUserHandler(c.GetUserService())
+	return handler.NewUserHandler(c.GetUserService(), c.GetPublicIDService())
+}
+
+// GetProfileVisitHandler 返回主页访客处理器实例
+func (c *Container) GetProfileVisitHandler() *handler.ProfileVisitHandler {
+	return handler.NewProfileVisitHandler(c.GetProfileVisitService(), c.GetPublicIDService())
+}
+
+// GetProfileHandler 返回用户主页处理器实例
+func (c *Container) GetProfileHandler() *handler.ProfileHandler {
+	return handler.NewProfileHandler(c.GetProfileService(), c.GetPublicIDService())
+}
+
+// GetUsernameHandler 返回用户名处理器实例
+func (c *Container) GetUsernameHandler() *handler.UsernameHandler {
+	return handler.NewUsernameHandler(c.GetUsernameService())
+}
+
+// GetActivityHandler 返回用户动态时间线处理器实例
+func (c *Container) GetActivityHandler() *handler.ActivityHandler {
+	return handler.NewActivityHandler(c.GetActivityService(), c.GetPublicIDService())
 }
 
 // GetPostHandler 返回动态处理器实例
 func (c *Container) GetPostHandler() *handler.PostHandler {
-	return handler.NewPostHandler(c.GetPostService())
+	return handler.NewPostHandler(c.GetPostService(), c.GetPublicIDService())
+}
+
+// GetPostInsightHandler 返回动态数据分析处理器实例
+func (c *Container) GetPostInsightHandler() *handler.PostInsightHandler {
+	return handler.NewPostInsightHandler(c.GetPostInsightService(), c.GetPublicIDService())
+}
+
+// GetAudienceHandler 返回好友列表处理器实例
+func (c *Container) GetAudienceHandler() *handler.AudienceHandler {
+	return handler.NewAudienceHandler(c.GetAudienceService(), c.GetPublicIDService())
+}
+
+// GetPostPrivacyHandler 返回动态隐私设置处理器实例
+func (c *Container) GetPostPrivacyHandler() *handler.PostPrivacyHandler {
+	return handler.NewPostPrivacyHandler(c.GetPostPrivacyService(), c.GetPublicIDService())
 }
 
 // GetRelationHandler 返回用户关系处理器实例
 func (c *Container) GetRelationHandler() *handler.RelationHandler {
-	return handler.NewRelationHandler(c.GetRelationService())
+	return handler.NewRelationHandler(c.GetRelationService(), c.GetPublicIDService())
 }
 
 // GetImageHandler 返回图片处理器实例
 func (c *Container) GetImageHandler() *handler.ImageHandler {
 	return handler.NewImageHandler(c.GetImageService(), c.GetPostService())
 }
+
+// GetStatsHandler 返回数据统计处理器实例
+func (c *Container) GetStatsHandler() *handler.StatsHandler {
+	return handler.NewStatsHandler(c.GetStatsService())
+}
+
+// GetFunnelHandler 返回登录注册漏斗处理器实例
+func (c *Container) GetFunnelHandler() *handler.FunnelHandler {
+	return handler.NewFunnelHandler(c.GetFunnelService())
+}
+
+// GetGraphHandler 返回社交关系图导出处理器实例
+func (c *Container) GetGraphHandler() *handler.GraphHandler {
+	return handler.NewGraphHandler(c.GetGraphExportService())
+}
+
+// GetWebhookHandler 返回Webhook处理器实例
+func (c *Container) GetWebhookHandler() *handler.WebhookHandler {
+	return handler.NewWebhookHandler(c.GetWebhookService())
+}
+
+// GetAnnouncementHandler 返回系统公告处理器实例
+func (c *Container) GetAnnouncementHandler() *handler.AnnouncementHandler {
+	return handler.NewAnnouncementHandler(c.GetAnnouncementService())
+}
+
+// GetTagHandler 返回用户标签处理器实例
+func (c *Container) GetTagHandler() *handler.TagHandler {
+	return handler.NewTagHandler(c.GetTagService())
+}
+
+// GetNotificationHandler 返回通知设置处理器实例
+func (c *Container) GetNotificationHandler() *handler.NotificationHandler {
+	return handler.NewNotificationHandler(c.GetNotificationService())
+}
+
+// GetDataExportHandler 返回用户数据导出处理器实例
+func (c *Container) GetDataExportHandler() *handler.DataExportHandler {
+	return handler.NewDataExportHandler(c.GetDataExportService(), c.GetPublicIDService())
+}
+
+// GetShareHandler 返回分享处理器实例
+func (c *Container) GetShareHandler() *handler.ShareHandler {
+	return handler.NewShareHandler(c.GetShareService(), c.GetPublicIDService())
+}
+
+// GetFeatureHandler 返回功能开关处理器实例
+func (c *Container) GetFeatureHandler() *handler.FeatureHandler {
+	return handler.NewFeatureHandler(c.GetFeatureService())
+}
+
+// GetJWTKeyHandler 返回JWT签名密钥处理器实例
+func (c *Container) GetJWTKeyHandler() *handler.JWTKeyHandler {
+	return handler.NewJWTKeyHandler(c.GetJWTKeyService())
+}
+
+// GetMetricsHandler 返回运行指标处理器实例
+func (c *Container) GetMetricsHandler() *handler.MetricsHandler {
+	return handler.NewMetricsHandler(c.GetMetricsService())
+}
+
+// GetConfigHandler 返回运行配置处理器实例
+func (c *Container) GetConfigHandler() *handler.ConfigHandler {
+	return handler.NewConfigHandler(c.GetConfigService())
+}
+
+// GetMaintenanceHandler 返回维护模式处理器实例
+func (c *Container) GetMaintenanceHandler() *handler.MaintenanceHandler {
+	return handler.NewMaintenanceHandler(c.GetMaintenanceService())
+}
+
+// GetMuteHandler 返回用户禁言处理器实例
+func (c *Container) GetMuteHandler() *handler.MuteHandler {
+	return handler.NewMuteHandler(c.GetMuteService())
+}
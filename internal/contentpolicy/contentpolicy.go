@@ -0,0 +1,145 @@
+// Package contentpolicy 集中校验动态和评论的内容规则
+// 规则包括文字长度、图片数量、图片类型、链接数量和是否只包含表情符号，均由配置文件的content部分控制，
+// 校验不通过时返回包含全部违规项的ValidationError，便于客户端逐项提示
+package contentpolicy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"app/config"
+	"app/pkg/linkpreview"
+)
+
+// 违规代码
+const (
+	CodeTooLong             = "too_long"               // 文字超过最大字符数
+	CodeTooManyImages       = "too_many_images"        // 图片数量超过上限
+	CodeImageTypeNotAllowed = "image_type_not_allowed" // 图片类型不在允许范围内
+	CodeTooManyLinks        = "too_many_links"         // 链接数量超过上限
+	CodeEmojiOnly           = "emoji_only"             // 文字只包含表情符号
+)
+
+// 违规字段
+const (
+	FieldContent = "content" // 文字内容
+	FieldImages  = "images"  // 图片
+)
+
+// ErrContentInvalid 内容不符合发布规则
+var ErrContentInvalid = errors.New("内容不符合发布规则")
+
+// Violation 单项违规详情
+type Violation struct {
+	Field  string `json:"field"`            // 违规字段
+	Code   string `json:"code"`             // 违规代码
+	Limit  int    `json:"limit,omitempty"`  // 规则上限，数量类规则有效
+	Actual int    `json:"actual,omitempty"` // 实际数量，数量类规则有效
+	Value  string `json:"value,omitempty"`  // 违规的值，如不允许的图片类型
+}
+
+// ValidationError 内容校验失败的详情
+type ValidationError struct {
+	Violations []Violation // 全部违规项
+}
+
+// Error 返回错误信息
+func (e *ValidationError) Error() string {
+	codes := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		codes[i] = v.Field + "." + v.Code
+	}
+	return fmt.Sprintf("%s: %s", ErrContentInvalid, strings.Join(codes, ", "))
+}
+
+// Is 使errors.Is(err, ErrContentInvalid)成立
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrContentInvalid
+}
+
+// Content 待校验的内容
+type Content struct {
+	Text       string   // 文字内容
+	ImageCount int      // 图片数量
+	ImageTypes []string // 新增图片的MIME类型，已发布的图片无需再次校验
+}
+
+// Policy 内容规则
+type Policy struct {
+	rule config.ContentRuleConfig
+}
+
+// ForPost 返回动态的内容规则
+func ForPost() *Policy {
+	return &Policy{rule: config.GetContentConfig().Post}
+}
+
+// ForComment 返回评论的内容规则
+func ForComment() *Policy {
+	return &Policy{rule: config.GetContentConfig().Comment}
+}
+
+// Validate 校验内容，不符合规则时返回*ValidationError，包含全部违规项
+func (p *Policy) Validate(content Content) error {
+	var violations []Violation
+
+	if length := utf8.RuneCountInString(content.Text); p.rule.MaxLength > 0 && length > p.rule.MaxLength {
+		violations = append(violations, Violation{Field: FieldContent, Code: CodeTooLong, Limit: p.rule.MaxLength, Actual: length})
+	}
+	if links := linkpreview.CountURLs(content.Text); p.rule.MaxLinks > 0 && links > p.rule.MaxLinks {
+		violations = append(violations, Violation{Field: FieldContent, Code: CodeTooManyLinks, Limit: p.rule.MaxLinks, Actual: links})
+	}
+	if p.rule.RejectEmojiOnly && isEmojiOnly(content.Text) {
+		violations = append(violations, Violation{Field: FieldContent, Code: CodeEmojiOnly})
+	}
+
+	if p.rule.MaxImages > 0 && content.ImageCount > p.rule.MaxImages {
+		violations = append(violations, Violation{Field: FieldImages, Code: CodeTooManyImages, Limit: p.rule.MaxImages, Actual: content.ImageCount})
+	}
+	if len(p.rule.AllowedImageTypes) > 0 {
+		for _, contentType := range content.ImageTypes {
+			if !slices.Contains(p.rule.AllowedImageTypes, contentType) {
+				violations = append(violations, Violation{Field: FieldImages, Code: CodeImageTypeNotAllowed, Value: contentType})
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// isEmojiOnly 判断文字是否只包含表情符号和空白，不含任何表情符号的文字返回false
+// 表情符号序列中的零宽连接符、变体选择符、肤色修饰符、键帽符号和标签字符不单独计算
+func isEmojiOnly(text string) bool {
+	hasEmoji := false
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r), isEmojiComponent(r):
+		case unicode.Is(unicode.So, r):
+			hasEmoji = true
+		default:
+			return false
+		}
+	}
+	return hasEmoji
+}
+
+// isEmojiComponent 判断字符是否为表情符号序列的组成部分
+func isEmojiComponent(r rune) bool {
+	switch {
+	case r == 0x200D: // 零宽连接符
+	case r == 0xFE0E || r == 0xFE0F: // 变体选择符
+	case r >= 0x1F3FB && r <= 0x1F3FF: // 肤色修饰符
+	case r == 0x20E3: // 键帽符号
+	case r >= 0xE0020 && r <= 0xE007F: // 标签字符，用于地区旗帜
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// PostLikedData post.liked事件数据
+type PostLikedData struct {
+	PostID       uint      `json:"-"`       // 内部动态ID，供平台内部的订阅者使用
+	UserID       uint      `json:"-"`       // 内部用户ID
+	PublicPostID string    `json:"post_id"` // 动态ID
+	PublicUserID string    `json:"user_id"` // 点赞的用户ID
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CommentLikedData comment.liked事件数据
+type CommentLikedData struct {
+	CommentID       uint      `json:"-"`          // 内部评论ID，供平台内部的订阅者使用
+	PostID          uint      `json:"-"`          // 内部动态ID
+	UserID          uint      `json:"-"`          // 内部用户ID
+	PublicCommentID string    `json:"comment_id"` // 评论ID
+	PublicPostID    string    `json:"post_id"`    // 动态ID
+	PublicUserID    string    `json:"user_id"`    // 点赞的用户ID
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// UserFollowedData user.followed事件数据
+type UserFollowedData struct {
+	UserID         uint      `json:"-"`          // 内部关注者ID，供平台内部的订阅者使用
+	TargetID       uint      `json:"-"`          // 内部被关注的用户ID
+	PublicUserID   string    `json:"user_id"`    // 关注者ID
+	PublicTargetID string    `json:"target_id"`  // 被关注的用户ID
+	CreatedAt      time.Time `json:"created_at"` // 关注生效时间
+	SourcePostID   uint      `json:"-"`          // 用户通过该动态关注作者，为0表示来源未知
+}
+
+// PostSharedData post.shared事件数据
+type PostSharedData struct {
+	PostID    uint      `json:"-"` // 内部动态ID
+	UserID    uint      `json:"-"` // 生成分享码的用户ID
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetActivityRequest 获取动态时间线请求
+type GetActivityRequest struct {
+	Page int `json:"page" binding:"required" validate:"required,min=1"`
+	Size int `json:"size" binding:"required" validate:"required,min=1,max=50"`
+}
+
+// ActivityItem 动态时间线中的一条活动
+type ActivityItem struct {
+	ID             uint      `json:"id"`
+	Type           string    `json:"type"`                      // 活动类型：post、comment、post_like、comment_like、follow
+	TargetID       string    `json:"target_id"`                 // 动态、评论或被关注的用户ID
+	PostID         string    `json:"post_id,omitempty"`         // 活动关联的动态ID，关注时为空
+	Summary        string    `json:"summary,omitempty"`         // 发布的动态或评论的内容摘要
+	TargetNickname string    `json:"target_nickname,omitempty"` // 被关注用户的昵称，用户已不可见时为空
+	TargetAvatar   string    `json:"target_avatar,omitempty"`   // 被关注用户的头像URL
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// GetActivityResponse 获取动态时间线响应
+type GetActivityResponse struct {
+	Total   int            `json:"total"`
+	HasMore bool           `json:"has_more"` // 是否还有下一页
+	List    []ActivityItem `json:"list"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// 系统公告相关DTO
+// 公告由管理员发布，用户获取未读公告并上报已读回执
+
+// CreateAnnouncementRequest 发布公告请求
+type CreateAnnouncementRequest struct {
+	Title     string     `json:"title" binding:"required,max=100"`                // 公告标题
+	Content   string     `json:"content" binding:"required,max=5000"`             // 公告正文
+	Target    string     `json:"target" binding:"required,oneof=all segment tag"` // 目标范围：all-全部用户，segment-用户分群，tag-用户标签
+	Segment   string     `json:"segment" binding:"required_unless=Target all"`    // 目标范围为segment时为用户分群，为tag时为标签名称
+	Push      bool       `json:"push"`                                            // 是否通过推送渠道通知目标用户
+	PublishAt *time.Time `json:"publish_at"`                                      // 发布时间，为空时立即发布
+	ExpireAt  *time.Time `json:"expire_at"`                                       // 过期时间，为空时不过期
+}
+
+// AnnouncementInfo 公告信息，供管理后台使用
+type AnnouncementInfo struct {
+	ID        uint       `json:"id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	Target    string     `json:"target"`
+	Segment   string     `json:"segment"`
+	Push      bool       `json:"push"`
+	CreatedBy uint       `json:"created_by"`
+	PublishAt time.Time  `json:"publish_at"`
+	ExpireAt  *time.Time `json:"expire_at"`
+	ReadCount int64      `json:"read_count"` // 已读人数
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ListAnnouncementsResponse 公告列表响应
+type ListAnnouncementsResponse struct {
+	Total    int64              `json:"total"`
+	List     []AnnouncementInfo `json:"list"`
+	Segments []string           `json:"segments"` // 支持的用户分群
+}
+
+// UserAnnouncement 用户可见的公告
+type UserAnnouncement struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	PublishAt time.Time `json:"publish_at"`
+}
+
+// UnreadAnnouncementsResponse 未读公告响应
+type UnreadAnnouncementsResponse struct {
+	List []UserAnnouncement `json:"list"` // 最新发布的在前
+}
+
+// MarkAnnouncementsReadRequest 标记公告已读请求
+type MarkAnnouncementsReadRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100"` // 公告ID
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"app/pkg/idgen"
+)
+
+// 好友列表相关DTO
+// 好友列表用于设置动态的可见范围，如仅密友可见
+
+// CreateAudienceListRequest 创建好友列表请求
+type CreateAudienceListRequest struct {
+	Name       string      `json:"name" binding:"required" validate:"required,max=30"` // 列表名称
+	MemberRefs []idgen.Ref `json:"member_ids"`                                         // 可选，初始成员的用户ID列表，只能是已确认的好友
+	MemberIDs  []uint      `json:"-"`                                                  // 内部用户ID，由处理器内部设置
+}
+
+// UpdateAudienceListRequest 修改好友列表名称请求
+type UpdateAudienceListRequest struct {
+	ListID uint   `json:"list_id" binding:"required" validate:"required"`
+	Name   string `json:"name" binding:"required" validate:"required,max=30"`
+}
+
+// DeleteAudienceListRequest 删除好友列表请求
+type DeleteAudienceListRequest struct {
+	ListID uint `json:"list_id" binding:"required" validate:"required"`
+}
+
+// AudienceListMembersRequest 添加或移除好友列表成员请求
+type AudienceListMembersRequest struct {
+	ListID     uint        `json:"list_id" binding:"required" validate:"required"`
+	MemberRefs []idgen.Ref `json:"member_ids" binding:"required" validate:"required,min=1"` // 成员的用户ID列表
+	MemberIDs  []uint      `json:"-"`                                                       // 内部用户ID，由处理器内部设置
+}
+
+// GetAudienceListMembersRequest 获取好友列表成员请求
+type GetAudienceListMembersRequest struct {
+	ListID uint `json:"list_id" binding:"required" validate:"required"`
+	Page   int  `json:"page" binding:"required" validate:"required,min=1"`
+	Size   int  `json:"size" binding:"required" validate:"required,min=1,max=100"`
+}
+
+// AudienceListInfo 好友列表信息
+type AudienceListInfo struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	MemberCount int64     `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetAudienceListsResponse 获取好友列表响应
+type GetAudienceListsResponse struct {
+	List []AudienceListInfo `json:"list"`
+}
+
+// GetAudienceListMembersResponse 获取好友列表成员响应
+type GetAudienceListMembersResponse struct {
+	Total int         `json:"total"`
+	List  []UserBrief `json:"list"`
+}
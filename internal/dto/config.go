@@ -0,0 +1,10 @@
+package dto
+
+// 运行配置相关DTO
+
+// EffectiveConfigResponse 有效配置响应，用于排查部署时配置文件、环境配置和环境变量的合并结果
+type EffectiveConfigResponse struct {
+	Env    string         `json:"env"`    // 当前环境名称，未设置APP_ENV时为空
+	Files  []string       `json:"files"`  // 已加载的配置文件，按合并顺序排列
+	Config map[string]any `json:"config"` // 有效配置，密码、密钥等敏感配置项已隐藏
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// 用户数据导出相关DTO
+
+// DataExportResponse 数据导出任务响应
+type DataExportResponse struct {
+	ID           uint       `json:"id"`
+	Status       string     `json:"status"`                  // 任务状态：pending、processing、completed、failed
+	DownloadURL  string     `json:"download_url,omitempty"`  // 导出文件下载链接，仅完成后返回
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`    // 下载链接过期时间
+	FileSize     int64      `json:"file_size,omitempty"`     // 导出文件大小(字节)
+	ErrorMessage string     `json:"error_message,omitempty"` // 失败原因
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// 功能开关相关DTO
+
+// SaveFeatureFlagRequest 创建或更新功能开关请求
+type SaveFeatureFlagRequest struct {
+	Description string        `json:"description" binding:"max=200"`          // 开关说明
+	Enabled     *bool         `json:"enabled" binding:"required"`             // 总开关
+	Percentage  int           `json:"percentage" binding:"min=0,max=100"`     // 灰度比例（0-100）
+	Overrides   map[uint]bool `json:"overrides" binding:"omitempty,max=1000"` // 按用户覆盖，key为用户ID，value为是否开启
+	Tags        []string      `json:"tags" binding:"omitempty,max=20"`        // 对拥有任一标签的用户开启，标签需已创建
+}
+
+// FeatureFlagInfo 功能开关信息
+type FeatureFlagInfo struct {
+	Key         string        `json:"key"`
+	Description string        `json:"description"`
+	Enabled     bool          `json:"enabled"`
+	Percentage  int           `json:"percentage"`
+	Overrides   map[uint]bool `json:"overrides"`
+	Tags        []string      `json:"tags"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// FeatureFlagListResponse 功能开关列表响应
+type FeatureFlagListResponse struct {
+	Flags    []FeatureFlagInfo `json:"flags"`
+	Defaults map[string]bool   `json:"defaults"` // 开关未创建时的默认值
+}
+
+// UserFeaturesResponse 当前用户的功能开关状态响应
+type UserFeaturesResponse struct {
+	Features map[string]bool `json:"features"` // key为开关标识，value为是否开启
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// 管理后台社交关系图导出相关DTO
+
+// ExportGraphRequest 导出社交关系图请求
+type ExportGraphRequest struct {
+	Type   string `form:"type" binding:"required,oneof=follow friend"` // 关系类型：follow-关注关系，friend-好友关系
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`  // 输出格式：csv（默认）、jsonl
+	Start  string `form:"start"`                                       // 创建时间起点（含），格式2006-01-02或RFC3339
+	End    string `form:"end"`                                         // 创建时间终点（不含），只提供日期时包含当天
+	Cursor uint   `form:"cursor"`                                      // 从该关系ID之后开始导出，用于中断后续传
+}
+
+// GraphEdge 社交关系图中的一条边
+// 关注关系为用户关注目标用户，好友关系每对好友只输出一次，用户ID小于目标用户ID
+type GraphEdge struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	TargetID  uint      `json:"target_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// ReuseImageRequest 按内容摘要复用图片请求
+type ReuseImageRequest struct {
+	Hash     string `json:"hash" binding:"required,len=64,hexadecimal"` // 文件内容SHA-256摘要（十六进制）
+	Filename string `json:"filename"`                                   // 原始文件名，用于确定扩展名
+}
+
+// PresignUploadRequest 获取预签名上传地址请求
+type PresignUploadRequest struct {
+	Filename string `json:"filename" binding:"required"` // 原始文件名，用于确定扩展名和内容类型
+}
+
+// ConfirmUploadRequest 确认直传完成请求
+type ConfirmUploadRequest struct {
+	ObjectKey string `json:"object_key" binding:"required"` // 获取上传地址时返回的对象键
+}
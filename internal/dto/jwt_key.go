@@ -0,0 +1,21 @@
+package dto
+
+// JWT签名密钥相关DTO
+
+// RotateJWTKeyRequest 轮换JWT签名密钥请求
+type RotateJWTKeyRequest struct {
+	KeyID string `json:"key_id" binding:"max=64"` // 可选，轮换到的密钥ID，为空时按配置顺序切换到下一个可签名密钥
+}
+
+// JWTKeyInfo JWT签名密钥信息，不含密钥内容
+type JWTKeyInfo struct {
+	ID        string `json:"id"`
+	Algorithm string `json:"algorithm"`
+	CanSign   bool   `json:"can_sign"` // 是否可以签发令牌，只配置了公钥的密钥仅用于校验
+	Current   bool   `json:"current"`  // 是否为当前签发令牌使用的密钥
+}
+
+// JWTKeyListResponse JWT签名密钥列表响应
+type JWTKeyListResponse struct {
+	Keys []JWTKeyInfo `json:"keys"`
+}
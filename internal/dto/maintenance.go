@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// 维护模式相关DTO
+
+// SetMaintenanceRequest 设置维护模式请求
+type SetMaintenanceRequest struct {
+	Enabled    *bool  `json:"enabled" binding:"required"`                      // 是否开启维护模式
+	Message    string `json:"message" binding:"max=200"`                       // 可选，返回给客户端的维护提示
+	RetryAfter int    `json:"retry_after" binding:"omitempty,min=1,max=86400"` // 可选，建议客户端重试的间隔（秒）
+}
+
+// MaintenanceResponse 维护模式状态响应
+type MaintenanceResponse struct {
+	Enabled    bool       `json:"enabled"`
+	Message    string     `json:"message,omitempty"`
+	RetryAfter int        `json:"retry_after,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	OperatorID uint       `json:"operator_id,omitempty"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "app/pkg/poolmonitor"
+
+// 运行指标相关DTO
+
+// RepositoryMetricsRequest 获取仓库方法调用指标请求
+type RepositoryMetricsRequest struct {
+	SortBy string `form:"sort_by" binding:"omitempty,oneof=total_time avg_time max_time calls errors error_rate"` // 排序字段，默认按累计耗时倒序
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`                                                // 返回的方法数，默认返回全部
+}
+
+// RepositoryMethodMetrics 仓库方法调用指标，耗时单位为毫秒
+type RepositoryMethodMetrics struct {
+	Repository  string  `json:"repository"`
+	Method      string  `json:"method"`
+	Calls       uint64  `json:"calls"`
+	Errors      uint64  `json:"errors"` // 返回错误的次数，记录不存在不计入
+	ErrorRate   float64 `json:"error_rate"`
+	TotalTimeMs float64 `json:"total_time_ms"`
+	AvgTimeMs   float64 `json:"avg_time_ms"`
+	MaxTimeMs   float64 `json:"max_time_ms"`
+}
+
+// RepositoryMetricsResponse 仓库方法调用指标响应，统计自服务启动以来的本节点数据
+type RepositoryMetricsResponse struct {
+	Methods []RepositoryMethodMetrics `json:"methods"`
+}
+
+// PoolStatsResponse 连接池采样序列响应
+type PoolStatsResponse struct {
+	Status  string               `json:"status"`  // 最近一次采样的连接池状态：ok、saturated、unknown
+	Samples []poolmonitor.Sample `json:"samples"` // 本节点最近的采样，按时间正序
+}
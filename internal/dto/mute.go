@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// 用户禁言相关DTO
+
+// MuteUserRequest 禁言用户请求
+type MuteUserRequest struct {
+	UserID   uint   `json:"-"`                                               // 被禁言的用户ID，由处理器内部设置
+	Duration int    `json:"duration" binding:"required,min=60,max=31536000"` // 禁言时长（秒），最长一年
+	Reason   string `json:"reason" binding:"required,max=200"`               // 禁言原因，记录到审计日志
+}
+
+// UnmuteUserRequest 解除禁言请求
+type UnmuteUserRequest struct {
+	UserID uint   `form:"-"`                        // 被解除禁言的用户ID，由处理器内部设置
+	Reason string `form:"reason" binding:"max=200"` // 可选，解除原因，记录到审计日志
+}
+
+// MuteResponse 用户禁言状态响应
+type MuteResponse struct {
+	UserID     uint       `json:"user_id"`
+	Muted      bool       `json:"muted"`
+	Reason     string     `json:"reason,omitempty"`
+	MutedAt    *time.Time `json:"muted_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	OperatorID uint       `json:"operator_id,omitempty"`
+}
+
+// MutedNotice 被禁言的用户调用受限接口时返回的详情
+type MutedNotice struct {
+	ExpiresAt time.Time `json:"expires_at"` // 禁言结束的时间
+}
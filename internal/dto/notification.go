@@ -0,0 +1,38 @@
+package dto
+
+// 通知设置相关DTO
+// 未保存设置的用户返回默认值，更新时未传的部分保持不变
+
+// NotificationPreferenceInfo 单个事件的通知偏好
+type NotificationPreferenceInfo struct {
+	Event string `json:"event" binding:"required"` // 事件类型：follow_request、follow_approved、data_export
+	Push  bool   `json:"push"`                     // 是否开启推送通知
+	SMS   bool   `json:"sms"`                      // 是否开启短信通知
+	Email bool   `json:"email"`                    // 是否开启邮件通知
+}
+
+// QuietHoursInfo 免打扰时段，开始时间晚于结束时间表示跨天
+type QuietHoursInfo struct {
+	Enabled  bool   `json:"enabled"`                            // 是否开启免打扰
+	Start    string `json:"start" binding:"required,len=5"`     // 开始时间，格式HH:MM
+	End      string `json:"end" binding:"required,len=5"`       // 结束时间，格式HH:MM
+	Timezone string `json:"timezone" binding:"required,max=64"` // IANA时区名，如Asia/Shanghai
+}
+
+// NotificationSettingsResponse 通知设置响应
+type NotificationSettingsResponse struct {
+	Preferences []NotificationPreferenceInfo `json:"preferences"` // 各事件的通知偏好
+	QuietHours  QuietHoursInfo               `json:"quiet_hours"` // 免打扰时段
+}
+
+// UpdateNotificationSettingsRequest 更新通知设置请求
+type UpdateNotificationSettingsRequest struct {
+	Preferences []NotificationPreferenceInfo `json:"preferences" binding:"omitempty,dive"` // 需要修改的事件通知偏好
+	QuietHours  *QuietHoursInfo              `json:"quiet_hours"`                          // 免打扰时段
+}
+
+// RegisterDeviceRequest 上报设备推送令牌请求
+type RegisterDeviceRequest struct {
+	Platform string `json:"platform" binding:"required,oneof=ios android"` // 设备平台：ios-APNs令牌，android-FCM令牌
+	Token    string `json:"token" binding:"required,max=255"`              // 设备推送令牌
+}
@@ -1,30 +1,49 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"app/pkg/idgen"
+)
 
 // 社交动态相关DTO
 
 // CreatePostRequest 创建动态请求
 type CreatePostRequest struct {
-	Content    string `json:"content" validate:"required,max=1000"` // 动态内容
-	ImageIDs   []uint `json:"image_ids"`                            // 已上传图片的ID列表
-	Visibility int    `json:"visibility" validate:"min=0,max=2"`    // 可见性：0-公开，1-仅关注者可见，2-仅自己可见
+	Content        string   `json:"content" validate:"required"`       // 动态内容，长度和链接数等规则见配置content.post
+	ImageIDs       []uint   `json:"image_ids"`                         // 已上传图片的ID列表
+	Visibility     int      `json:"visibility" validate:"min=0,max=4"` // 可见性：1-公开，2-仅好友，3-仅自己可见，4-仅指定好友列表可见，不传时使用用户设置的默认可见性
+	AudienceListID *uint    `json:"audience_list_id"`                  // 可见性为4时必填，可见的好友列表ID
+	Latitude       *float64 `json:"latitude"`                          // 可选，纬度（GCJ-02坐标系），需与经度同时提供
+	Longitude      *float64 `json:"longitude"`                         // 可选，经度（GCJ-02坐标系）
 }
 
 // CreatePostResponse 创建动态响应
 type CreatePostResponse struct {
-	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
-	Content   string    `json:"content"`
-	Images    []string  `json:"images"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           string            `json:"id"`
+	UserID       string            `json:"user_id"`
+	Content      string            `json:"content"`
+	Images       []string          `json:"images"`
+	ImageResults []PostImageResult `json:"image_results"` // 各图片的关联结果，顺序与请求中的image_ids一致（重复的ID只保留一次）
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// PostImageResult 创建动态时单张图片的关联结果
+type PostImageResult struct {
+	ImageID uint   `json:"image_id"`        // 临时图片ID
+	Status  string `json:"status"`          // 关联状态：attached-已关联，failed-关联失败，rolled_back-已回滚，skipped-未处理
+	URL     string `json:"url,omitempty"`   // 关联成功时的图片地址
+	Error   string `json:"error,omitempty"` // 关联失败的原因
 }
 
 // GetPostsRequest 获取动态列表请求
 type GetPostsRequest struct {
-	UserID *uint `json:"user_id"` // 可选，为空表示获取关注用户的动态
-	Page   int   `json:"page" binding:"required" validate:"required,min=1"`
-	Size   int   `json:"size" binding:"required" validate:"required,min=1,max=100"`
+	UserID    *uint    `json:"user_id"`   // 可选，为空表示获取关注用户的动态
+	Sort      string   `json:"sort"`      // 可选，排序方式：latest-按时间（默认），recommended-推荐排序，discover-混合关注、附近和热门动态，仅对关注动态生效
+	Latitude  *float64 `json:"latitude"`  // 可选，查看者所在纬度（GCJ-02坐标系），仅discover生效，需与经度同时提供
+	Longitude *float64 `json:"longitude"` // 可选，查看者所在经度（GCJ-02坐标系）
+	Page      int      `json:"page" binding:"required" validate:"required,min=1"`
+	Size      int      `json:"size" binding:"required" validate:"required,min=1,max=100"`
 }
 
 // GetPostsResponse 获取动态列表响应
@@ -35,64 +54,214 @@ type GetPostsResponse struct {
 
 // PostDetail 动态详情
 type PostDetail struct {
-	ID         uint      `json:"id"`
-	UserID     uint      `json:"user_id"`
-	Nickname   string    `json:"nickname"`
-	Avatar     string    `json:"avatar"`
-	Content    string    `json:"content"`
-	Images     string    `json:"images"`
-	LocationID *uint     `json:"location_id"`
-	Address    string    `json:"address,omitempty"`
-	Likes      int       `json:"likes"`
-	Comments   int       `json:"comments"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID          string           `json:"id"`
+	UserID      string           `json:"user_id"`
+	Nickname    string           `json:"nickname"`
+	Avatar      string           `json:"avatar"`
+	Content     string           `json:"content"`
+	Language    string           `json:"language"` // 内容语言（ISO 639-1代码），无法识别时为空，客户端据此决定是否提供翻译
+	Images      []PostImageInfo  `json:"images"`
+	LocationID  *uint            `json:"location_id"`
+	Address     string           `json:"address,omitempty"`
+	LinkPreview *LinkPreviewInfo `json:"link_preview,omitempty"` // 内容中第一个链接的预览，抓取完成前或抓取失败时为空
+	Likes       int              `json:"likes"`
+	Comments    int              `json:"comments"`
+	Views       int64            `json:"views"`
+	Edited      bool             `json:"edited"`    // 是否编辑过
+	EditedAt    *time.Time       `json:"edited_at"` // 最后编辑时间，未编辑过时为空
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"-"` // 最后更新时间，点赞和评论同样会更新，用于计算ETag
+}
+
+// PostImageInfo 动态图片信息
+type PostImageInfo struct {
+	ID       uint   `json:"id"` // 动态图片ID，编辑动态时用于指定移除的图片
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	ThumbURL string `json:"thumb_url"`
+}
+
+// LinkPreviewInfo 链接预览信息
+type LinkPreviewInfo struct {
+	URL         string `json:"url"`         // 动态中的原始链接
+	Title       string `json:"title"`       // 网页标题
+	Description string `json:"description"` // 网页描述
+	Image       string `json:"image"`       // 预览图片地址，网页未提供时为空
+	SiteName    string `json:"site_name"`   // 网站名称
+}
+
+// GetPostsResponseV1 兼容v1客户端的动态列表响应
+type GetPostsResponseV1 struct {
+	Total int            `json:"total"`
+	List  []PostDetailV1 `json:"list"`
+}
+
+// PostDetailV1 兼容v1客户端的动态详情，images为逗号分隔的图片地址
+type PostDetailV1 struct {
+	PostDetail
+	Images string `json:"images"`
 }
 
 // LikePostRequest 点赞动态请求
 type LikePostRequest struct {
+	PostRef idgen.Ref `json:"post_id" binding:"required" validate:"required"` // 动态ID
+	PostID  uint      `json:"-"`                                              // 内部动态ID，由处理器内部设置
+}
+
+// UpdatePostRequest 编辑动态请求
+type UpdatePostRequest struct {
+	PostRef        idgen.Ref `json:"post_id" binding:"required" validate:"required"` // 动态ID
+	PostID         uint      `json:"-"`                                              // 内部动态ID，由处理器内部设置
+	Content        string    `json:"content" validate:"required"`                    // 编辑后的动态内容，规则同创建动态
+	AddImageIDs    []uint    `json:"add_image_ids"`                                  // 可选，新增的已上传图片ID列表
+	RemoveImageIDs []uint    `json:"remove_image_ids"`                               // 可选，移除的动态图片ID列表
+}
+
+// UpdatePostResponse 编辑动态响应
+type UpdatePostResponse struct {
+	ID       string          `json:"id"`
+	Content  string          `json:"content"`
+	Images   []PostImageInfo `json:"images"`
+	EditedAt *time.Time      `json:"edited_at"`
+}
+
+// GetPostRevisionsRequest 获取动态修订记录请求
+type GetPostRevisionsRequest struct {
 	PostID uint `json:"post_id" binding:"required" validate:"required"`
+	Page   int  `json:"page" binding:"required" validate:"required,min=1"`
+	Size   int  `json:"size" binding:"required" validate:"required,min=1,max=100"`
+}
+
+// GetPostRevisionsResponse 获取动态修订记录响应
+type GetPostRevisionsResponse struct {
+	Total int                  `json:"total"`
+	List  []PostRevisionDetail `json:"list"`
+}
+
+// PostRevisionDetail 动态修订记录
+type PostRevisionDetail struct {
+	ID            uint            `json:"id"`
+	EditorID      string          `json:"editor_id"`
+	ContentBefore string          `json:"content_before"`
+	ContentAfter  string          `json:"content_after"`
+	AddedImages   []PostImageInfo `json:"added_images"`   // 本次编辑新增的图片
+	RemovedImages []PostImageInfo `json:"removed_images"` // 本次编辑移除的图片
+	CreatedAt     time.Time       `json:"created_at"`     // 编辑时间
+}
+
+// DeletePostRequest 删除动态请求
+type DeletePostRequest struct {
+	PostRef idgen.Ref `json:"post_id" binding:"required" validate:"required"` // 动态ID
+	PostID  uint      `json:"-"`                                              // 内部动态ID，由处理器内部设置
+}
+
+// RestorePostRequest 从回收站恢复动态请求
+type RestorePostRequest struct {
+	PostRef idgen.Ref `json:"post_id" binding:"required" validate:"required"` // 动态ID
+	PostID  uint      `json:"-"`                                              // 内部动态ID，由处理器内部设置
+}
+
+// GetTrashRequest 获取回收站动态列表请求
+type GetTrashRequest struct {
+	Page int `json:"page" binding:"required" validate:"required,min=1"`
+	Size int `json:"size" binding:"required" validate:"required,min=1,max=100"`
+}
+
+// GetTrashResponse 获取回收站动态列表响应
+type GetTrashResponse struct {
+	Total int               `json:"total"`
+	List  []TrashPostDetail `json:"list"`
+}
+
+// TrashPostDetail 回收站中的动态
+type TrashPostDetail struct {
+	ID        string          `json:"id"`
+	Content   string          `json:"content"`
+	Images    []PostImageInfo `json:"images"`
+	CreatedAt time.Time       `json:"created_at"`
+	DeletedAt time.Time       `json:"deleted_at"`
+	ExpiresAt time.Time       `json:"expires_at"` // 超过该时间后不能恢复
 }
 
-// CommentPostRequest 评论动态请求
+// CommentPostRequest 评论动态请求，内容和图片至少填写一项
 type CommentPostRequest struct {
-	PostID   uint   `json:"post_id" binding:"required" validate:"required"`
-	Content  string `json:"content" binding:"required" validate:"required,max=500"`
-	ParentID *uint  `json:"parent_id"` // 可选，回复某条评论
+	PostRef   idgen.Ref `json:"post_id" binding:"required" validate:"required"` // 动态ID
+	PostID    uint      `json:"-"`                                              // 内部动态ID，由处理器内部设置
+	Content   string    `json:"content"`                                        // 可选，评论内容，长度和链接数等规则见配置content.comment
+	ParentRef idgen.Ref `json:"parent_id"`                                      // 可选，回复的评论ID
+	ParentID  *uint     `json:"-"`                                              // 内部评论ID，由处理器内部设置
+	ImageID   *uint     `json:"image_id"`                                       // 可选，已上传的图片或表情ID
+}
+
+// CommentImageInfo 评论图片信息
+type CommentImageInfo struct {
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	ThumbURL string `json:"thumb_url"`
 }
 
 // CommentPostResponse 评论动态响应
 type CommentPostResponse struct {
-	ID        uint      `json:"id"`
-	PostID    uint      `json:"post_id"`
-	UserID    uint      `json:"user_id"`
-	Nickname  string    `json:"nickname"`
-	Avatar    string    `json:"avatar"`
-	Content   string    `json:"content"`
-	ParentID  *uint     `json:"parent_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string            `json:"id"`
+	PostID    string            `json:"post_id"`
+	UserID    string            `json:"user_id"`
+	Nickname  string            `json:"nickname"`
+	Avatar    string            `json:"avatar"`
+	Content   string            `json:"content"`
+	ParentID  *string           `json:"parent_id"`
+	Image     *CommentImageInfo `json:"image,omitempty"` // 评论附带的图片或表情
+	CreatedAt time.Time         `json:"created_at"`
 }
 
 // GetCommentsRequest 获取评论列表请求
 type GetCommentsRequest struct {
-	PostID uint `json:"post_id" binding:"required" validate:"required"`
-	Page   int  `json:"page" binding:"required" validate:"required,min=1"`
-	Size   int  `json:"size" binding:"required" validate:"required,min=1,max=100"`
+	PostID uint   `json:"post_id" binding:"required" validate:"required"`
+	Sort   string `json:"sort"` // 可选，排序方式：latest-按时间（默认），hot-按点赞数
+	Page   int    `json:"page" binding:"required" validate:"required,min=1"`
+	Size   int    `json:"size" binding:"required" validate:"required,min=1,max=100"`
 }
 
 // GetCommentsResponse 获取评论列表响应
 type GetCommentsResponse struct {
-	Total int             `json:"total"`
-	List  []CommentDetail `json:"list"`
+	Total   int             `json:"total"`
+	HasMore bool            `json:"has_more"` // 是否还有下一页
+	List    []CommentDetail `json:"list"`
+}
+
+// GetCommentRepliesRequest 获取评论回复列表请求
+type GetCommentRepliesRequest struct {
+	CommentID uint `json:"comment_id" binding:"required" validate:"required"`
+	Page      int  `json:"page" binding:"required" validate:"required,min=1"`
+	Size      int  `json:"size" binding:"required" validate:"required,min=1,max=100"`
 }
 
 // CommentDetail 评论详情
 type CommentDetail struct {
-	ID        uint      `json:"id"`
-	PostID    uint      `json:"post_id"`
-	UserID    uint      `json:"user_id"`
-	Nickname  string    `json:"nickname"`
-	Avatar    string    `json:"avatar"`
-	Content   string    `json:"content"`
-	ParentID  *uint     `json:"parent_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         string            `json:"id"`
+	PostID     string            `json:"post_id"`
+	UserID     string            `json:"user_id"`
+	Nickname   string            `json:"nickname"`
+	Avatar     string            `json:"avatar"`
+	Content    string            `json:"content"`
+	ParentID   *string           `json:"parent_id"`
+	Image      *CommentImageInfo `json:"image,omitempty"` // 评论附带的图片或表情
+	Likes      int               `json:"likes"`
+	Liked      bool              `json:"liked"`       // 当前用户是否已点赞
+	ReplyCount int64             `json:"reply_count"` // 直接回复数
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// LikeCommentRequest 点赞评论请求
+type LikeCommentRequest struct {
+	CommentRef idgen.Ref `json:"comment_id" binding:"required" validate:"required"` // 评论ID
+	CommentID  uint      `json:"-"`                                                 // 内部评论ID，由处理器内部设置
+}
+
+// TranslationResponse 动态或评论的翻译结果
+type TranslationResponse struct {
+	SourceLang string `json:"source_lang"` // 原文语言，无法识别时为空
+	TargetLang string `json:"target_lang"` // 目标语言
+	Text       string `json:"text"`        // 译文
 }
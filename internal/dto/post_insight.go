@@ -0,0 +1,43 @@
+package dto
+
+import "app/pkg/idgen"
+
+// 动态数据分析相关DTO
+
+// GetPostInsightsRequest 获取动态数据分析请求
+type GetPostInsightsRequest struct {
+	StartDate string    `form:"start_date"` // 开始日期，格式2006-01-02，默认为7天前
+	EndDate   string    `form:"end_date"`   // 结束日期，格式2006-01-02，默认为昨天
+	PostRef   idgen.Ref `form:"post_id"`    // 可选，只查询指定动态，必须是当前用户的动态
+	PostID    uint      `form:"-"`          // 内部动态ID，由处理器内部设置，为0时查询全部动态
+}
+
+// PostInsightMetrics 动态数据指标
+type PostInsightMetrics struct {
+	Views         int64 `json:"views"`          // 去重浏览数
+	Likes         int64 `json:"likes"`          // 点赞数
+	Comments      int64 `json:"comments"`       // 评论数
+	Shares        int64 `json:"shares"`         // 分享数，按生成分享码的次数统计
+	FollowerGains int64 `json:"follower_gains"` // 通过动态关注作者的用户数
+}
+
+// PostInsightDay 动态单日数据
+type PostInsightDay struct {
+	Date string `json:"date"`
+	PostInsightMetrics
+}
+
+// PostInsightItem 单条动态的数据
+type PostInsightItem struct {
+	PostID  string             `json:"post_id"`
+	Summary PostInsightMetrics `json:"summary"` // 区间内合计
+	Daily   []PostInsightDay   `json:"daily"`   // 按日期升序，没有数据的日期不返回
+}
+
+// GetPostInsightsResponse 获取动态数据分析响应
+type GetPostInsightsResponse struct {
+	StartDate string             `json:"start_date"`
+	EndDate   string             `json:"end_date"`
+	Summary   PostInsightMetrics `json:"summary"` // 区间内全部动态的合计
+	Posts     []PostInsightItem  `json:"posts"`   // 区间内有数据的动态，按浏览数降序
+}
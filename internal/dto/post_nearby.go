@@ -0,0 +1,26 @@
+package dto
+
+// 附近动态地图相关DTO
+
+// GetNearbyPostsRequest 获取附近动态地图请求
+type GetNearbyPostsRequest struct {
+	Latitude  *float64 `form:"lat" binding:"required,min=-90,max=90"`   // 地图中心纬度（GCJ-02坐标系）
+	Longitude *float64 `form:"lng" binding:"required,min=-180,max=180"` // 地图中心经度（GCJ-02坐标系）
+	Radius    float64  `form:"radius" binding:"omitempty,gt=0,max=50"`  // 可选，搜索半径（公里），默认5
+	Zoom      int      `form:"zoom" binding:"omitempty,min=1,max=20"`   // 可选，地图缩放级别，决定聚合粒度，未指定时按搜索半径确定
+}
+
+// NearbyPostCluster 地图上的动态聚合点
+type NearbyPostCluster struct {
+	Geohash   string  `json:"geohash"`           // 聚合格的geohash前缀
+	Latitude  float64 `json:"latitude"`          // 格内动态位置的平均纬度，用于放置标记
+	Longitude float64 `json:"longitude"`         // 格内动态位置的平均经度
+	Count     int64   `json:"count"`             // 格内动态数量
+	PostID    string  `json:"post_id,omitempty"` // 格内只有一条动态时为该动态ID，点击标记可直接打开
+}
+
+// GetNearbyPostsResponse 获取附近动态地图响应
+type GetNearbyPostsResponse struct {
+	Precision int                 `json:"precision"` // 聚合使用的geohash长度，越长格子越小
+	Clusters  []NearbyPostCluster `json:"clusters"`  // 按动态数量降序，最多200个
+}
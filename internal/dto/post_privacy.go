@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"app/pkg/idgen"
+)
+
+// 动态隐私设置相关DTO
+// 包括发布动态的默认可见性，以及不让指定的人看自己的动态
+
+// SetDefaultVisibilityRequest 设置发布动态的默认可见性请求
+// 仅好友列表可见需要在发布时指定列表，不能作为默认值
+type SetDefaultVisibilityRequest struct {
+	Visibility int `json:"visibility" binding:"required,min=1,max=3" validate:"required,min=1,max=3"` // 默认可见性：1-公开，2-仅好友，3-仅自己可见
+}
+
+// PostExclusionsRequest 添加或移除动态屏蔽请求
+type PostExclusionsRequest struct {
+	UserRefs []idgen.Ref `json:"user_ids" binding:"required,min=1,max=100" validate:"required,min=1,max=100"` // 不让看自己动态的用户ID列表
+	UserIDs  []uint      `json:"-"`                                                                           // 内部用户ID，由处理器内部设置
+}
+
+// GetPostExclusionsRequest 获取动态屏蔽列表请求
+type GetPostExclusionsRequest struct {
+	Page int `json:"page" binding:"required" validate:"required,min=1"`
+	Size int `json:"size" binding:"required" validate:"required,min=1,max=100"`
+}
+
+// PostExclusionInfo 被屏蔽的用户
+type PostExclusionInfo struct {
+	UserBrief
+	ExcludedAt time.Time `json:"excluded_at"` // 屏蔽时间
+}
+
+// GetPostExclusionsResponse 获取动态屏蔽列表响应
+type GetPostExclusionsResponse struct {
+	Total   int                 `json:"total"`
+	HasMore bool                `json:"has_more"` // 是否还有下一页
+	List    []PostExclusionInfo `json:"list"`
+}
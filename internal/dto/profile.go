@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // UpdateProfileRequest 更新用户资料请求
 type UpdateProfileRequest struct {
 	Nickname   string `json:"nickname" validate:"max=50"` // 用户昵称
@@ -8,9 +10,68 @@ type UpdateProfileRequest struct {
 
 // UserProfileResponse 用户资料响应
 type UserProfileResponse struct {
-	ID       uint   `json:"id"`       // 用户ID
+	ID       string `json:"id"`       // 用户ID
 	Username string `json:"username"` // 用户名
 	Nickname string `json:"nickname"` // 用户昵称
 	Mobile   string `json:"mobile"`   // 手机号
 	Avatar   string `json:"avatar"`   // 头像URL
 }
+
+// GetProfileResponse 用户主页聚合信息响应，一次返回主页所需的用户信息、计数、关系和最近动态
+type GetProfileResponse struct {
+	User         ProfileUser          `json:"user"`
+	Counts       ProfileCounts        `json:"counts"`
+	Relationship *ProfileRelationship `json:"relationship,omitempty"` // 查看者与该用户的关系，查看本人主页时为空
+	RecentPosts  []PostDetail         `json:"recent_posts"`           // 查看者可见的最近动态
+}
+
+// ProfileUser 主页展示的用户公开信息
+type ProfileUser struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Nickname  string    `json:"nickname"`
+	Avatar    string    `json:"avatar"`
+	IsPrivate bool      `json:"is_private"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProfileCounts 主页展示的计数
+type ProfileCounts struct {
+	Followers int64 `json:"followers"` // 粉丝数，仅统计已通过的关注
+	Following int64 `json:"following"` // 关注数，仅统计已通过的关注
+	Posts     int64 `json:"posts"`     // 查看者可见的动态数
+}
+
+// ProfileRelationship 查看者与主页用户的关系
+type ProfileRelationship struct {
+	FollowStatus int  `json:"follow_status"` // 查看者对该用户的关注状态：0-未关注，1-已关注，2-待审核
+	FollowedBy   bool `json:"followed_by"`   // 该用户是否已关注查看者
+	FriendStatus int  `json:"friend_status"` // 好友状态：-1-非好友，0-待确认，1-已确认
+}
+
+// GetVisitorsRequest 获取主页访客或访问记录请求
+type GetVisitorsRequest struct {
+	Page int `json:"page" binding:"required" validate:"required,min=1"`
+	Size int `json:"size" binding:"required" validate:"required,min=1,max=50"`
+}
+
+// ProfileVisitor 按用户汇总的主页访问记录
+type ProfileVisitor struct {
+	UserID        string    `json:"user_id"`         // 访客或被访问的用户ID
+	Nickname      string    `json:"nickname"`        // 用户昵称
+	Avatar        string    `json:"avatar"`          // 头像URL
+	Visits        int64     `json:"visits"`          // 最近30天的访问次数，一小时内的重复访问只计一次
+	LastVisitedAt time.Time `json:"last_visited_at"` // 最近一次访问时间
+}
+
+// GetVisitorsResponse 获取主页访客或访问记录响应
+type GetVisitorsResponse struct {
+	Total   int              `json:"total"`
+	HasMore bool             `json:"has_more"` // 是否还有下一页
+	List    []ProfileVisitor `json:"list"`
+}
+
+// SetRecordVisitsRequest 设置是否记录主页访问足迹请求
+type SetRecordVisitsRequest struct {
+	Allow *bool `json:"allow" binding:"required" validate:"required"`
+}
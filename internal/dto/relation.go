@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"app/pkg/idgen"
+)
 
 // 用户关系相关DTO
 // 包含关注、好友等社交关系功能的数据传输对象
@@ -9,20 +13,46 @@ import "time"
 
 // FollowUserRequest 关注用户请求
 type FollowUserRequest struct {
-	TargetID uint `json:"target_id" binding:"required" validate:"required"`
+	TargetRef     idgen.Ref `json:"target_id" binding:"required" validate:"required"` // 目标用户ID
+	TargetID      uint      `json:"-"`                                                // 内部用户ID，由处理器内部设置
+	SourcePostRef idgen.Ref `json:"source_post_id"`                                   // 可选，用户从该动态进入并关注作者，用于统计动态带来的关注
+	SourcePostID  *uint     `json:"-"`                                                // 内部动态ID，由处理器内部设置
 }
 
 // FollowUserResponse 关注用户响应
 type FollowUserResponse struct {
 	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
-	TargetID  uint      `json:"target_id"`
+	UserID    string    `json:"user_id"`
+	TargetID  string    `json:"target_id"`
+	Status    int       `json:"status"` // 关注状态：1-已通过，2-待审核
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // UnfollowUserRequest 取消关注用户请求
 type UnfollowUserRequest struct {
-	TargetID uint `json:"target_id" binding:"required" validate:"required"`
+	TargetRef idgen.Ref `json:"target_id" binding:"required" validate:"required"` // 目标用户ID
+	TargetID  uint      `json:"-"`                                                // 内部用户ID，由处理器内部设置
+}
+
+// BatchRelationRequest 批量关注、取消关注或移除粉丝请求
+type BatchRelationRequest struct {
+	TargetRefs []idgen.Ref `json:"target_ids" binding:"required,min=1,max=100,dive,required"` // 目标用户ID，重复的ID只处理一次
+	TargetIDs  []uint      `json:"-"`                                                         // 内部用户ID，与TargetRefs一一对应，由处理器内部设置
+}
+
+// BatchRelationResult 批量操作中单个目标用户的处理结果
+type BatchRelationResult struct {
+	TargetID string `json:"target_id"`
+	Success  bool   `json:"success"`
+	Status   string `json:"status,omitempty"` // 成功时的结果：followed-已关注，requested-已发送关注请求，unfollowed-已取消关注，removed-已移除粉丝
+	Error    string `json:"error,omitempty"`  // 失败原因
+}
+
+// BatchRelationResponse 批量关系操作响应，部分目标失败时其余目标仍然生效
+type BatchRelationResponse struct {
+	Succeeded int                   `json:"succeeded"` // 成功的目标数量
+	Failed    int                   `json:"failed"`    // 失败的目标数量
+	Results   []BatchRelationResult `json:"results"`   // 按请求中的顺序返回每个目标的结果
 }
 
 // GetFollowersRequest 获取粉丝列表请求
@@ -38,6 +68,13 @@ type GetFollowersResponse struct {
 	List  []UserBrief `json:"list"`
 }
 
+// RelationCountsResponse 粉丝数和关注数响应
+type RelationCountsResponse struct {
+	UserID    string `json:"user_id"`
+	Followers int64  `json:"followers"` // 粉丝数，仅统计已通过的关注
+	Following int64  `json:"following"` // 关注数，仅统计已通过的关注
+}
+
 // GetFollowingRequest 获取关注列表请求
 type GetFollowingRequest struct {
 	UserID uint `json:"user_id" binding:"required" validate:"required"`
@@ -55,16 +92,18 @@ type GetFollowingResponse struct {
 
 // AddFriendRequest 添加好友请求
 type AddFriendRequest struct {
-	TargetID uint   `json:"target_id" binding:"required" validate:"required"`
-	Message  string `json:"message" binding:"omitempty" validate:"omitempty,max=200"`
+	TargetRef idgen.Ref `json:"target_id" binding:"required" validate:"required"` // 目标用户ID
+	TargetID  uint      `json:"-"`                                                // 内部用户ID，由处理器内部设置
+	Message   string    `json:"message" binding:"omitempty" validate:"omitempty,max=200"`
 }
 
 // AddFriendResponse 添加好友响应
 type AddFriendResponse struct {
 	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
-	TargetID  uint      `json:"target_id"`
+	UserID    string    `json:"user_id"`
+	TargetID  string    `json:"target_id"`
 	Status    int       `json:"status"`
+	Message   string    `json:"message"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -80,7 +119,8 @@ type RejectFriendRequest struct {
 
 // DeleteFriendRequest 删除好友请求
 type DeleteFriendRequest struct {
-	TargetID uint `json:"target_id" binding:"required" validate:"required"`
+	TargetRef idgen.Ref `json:"target_id" binding:"required" validate:"required"` // 目标用户ID
+	TargetID  uint      `json:"-"`                                                // 内部用户ID，由处理器内部设置
 }
 
 // GetFriendRequestsRequest 获取好友请求列表请求
@@ -92,7 +132,7 @@ type GetFriendRequestsRequest struct {
 // FriendRequestItem 好友请求项
 type FriendRequestItem struct {
 	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
+	UserID    string    `json:"user_id"`
 	Nickname  string    `json:"nickname"`
 	Avatar    string    `json:"avatar"`
 	Message   string    `json:"message"`
@@ -107,8 +147,9 @@ type GetFriendRequestsResponse struct {
 
 // GetFriendsRequest 获取好友列表请求
 type GetFriendsRequest struct {
-	Page int `json:"page" binding:"required" validate:"required,min=1"`
-	Size int `json:"size" binding:"required" validate:"required,min=1,max=100"`
+	Page  int    `json:"page" binding:"required" validate:"required,min=1"`
+	Size  int    `json:"size" binding:"required" validate:"required,min=1,max=100"`
+	Group string `json:"group"` // 好友分组，为空时返回全部好友
 }
 
 // GetFriendsResponse 获取好友列表响应
@@ -120,8 +161,108 @@ type GetFriendsResponse struct {
 // FriendItem 好友项
 type FriendItem struct {
 	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
+	UserID    string    `json:"user_id"`
+	Nickname  string    `json:"nickname"`
+	Avatar    string    `json:"avatar"`
+	Remark    string    `json:"remark"`
+	Group     string    `json:"group"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UpdateFriendRemarkRequest 设置好友备注请求
+type UpdateFriendRemarkRequest struct {
+	TargetRef idgen.Ref `json:"target_id" binding:"required" validate:"required"` // 目标用户ID
+	TargetID  uint      `json:"-"`                                                // 内部用户ID，由处理器内部设置
+	Remark    string    `json:"remark" binding:"max=50" validate:"max=50"`        // 为空时清除备注
+}
+
+// UpdateFriendGroupRequest 设置好友分组请求
+type UpdateFriendGroupRequest struct {
+	TargetRef idgen.Ref `json:"target_id" binding:"required" validate:"required"` // 目标用户ID
+	TargetID  uint      `json:"-"`                                                // 内部用户ID，由处理器内部设置
+	Group     string    `json:"group" binding:"max=30" validate:"max=30"`         // 为空时移出分组
+}
+
+// ===== 关注请求相关 =====
+
+// ApproveFollowRequest 通过关注请求
+type ApproveFollowRequest struct {
+	RequestID uint `json:"request_id" binding:"required" validate:"required"`
+}
+
+// RejectFollowRequest 拒绝关注请求
+type RejectFollowRequest struct {
+	RequestID uint `json:"request_id" binding:"required" validate:"required"`
+}
+
+// GetFollowRequestsRequest 获取关注请求列表请求
+type GetFollowRequestsRequest struct {
+	Page int `json:"page" binding:"required" validate:"required,min=1"`
+	Size int `json:"size" binding:"required" validate:"required,min=1,max=100"`
+}
+
+// FollowRequestItem 关注请求项
+type FollowRequestItem struct {
+	ID        uint      `json:"id"`
+	UserID    string    `json:"user_id"`
 	Nickname  string    `json:"nickname"`
 	Avatar    string    `json:"avatar"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// GetFollowRequestsResponse 获取关注请求列表响应
+type GetFollowRequestsResponse struct {
+	Total int                 `json:"total"`
+	List  []FollowRequestItem `json:"list"`
+}
+
+// SetPrivateAccountRequest 设置私密账号请求
+type SetPrivateAccountRequest struct {
+	IsPrivate *bool `json:"is_private" binding:"required" validate:"required"`
+}
+
+// DiscoverContactsRequest 通讯录好友发现请求
+type DiscoverContactsRequest struct {
+	MobileHashes []string `json:"mobile_hashes" binding:"required,min=1,max=500,dive,len=64,hexadecimal"` // 通讯录手机号的SHA-256摘要（小写十六进制）
+}
+
+// DiscoveredContact 通讯录匹配到的用户
+type DiscoveredContact struct {
+	MobileHash   string `json:"mobile_hash"` // 匹配的手机号摘要，客户端据此对应通讯录联系人
+	UserID       string `json:"user_id"`
+	Nickname     string `json:"nickname"`
+	Avatar       string `json:"avatar"`
+	FollowStatus int    `json:"follow_status"` // 关注状态：0-未关注，1-已关注，2-待审核
+	FriendStatus int    `json:"friend_status"` // 好友状态：-1-非好友，0-待确认，1-已确认
+}
+
+// DiscoverContactsResponse 通讯录好友发现响应
+type DiscoverContactsResponse struct {
+	List []DiscoveredContact `json:"list"`
+}
+
+// RecommendationReason 推荐理由
+type RecommendationReason struct {
+	Type  string `json:"type"`  // 理由类型：mutual_friends-共同好友，mutual_follows-共同关注，contact-通讯录联系人
+	Count int64  `json:"count"` // 共同好友或共同关注的数量，通讯录联系人时为0
+	Text  string `json:"text"`  // 展示文案，如"3位共同好友"
+}
+
+// RecommendedUser 推荐的用户
+type RecommendedUser struct {
+	UserID   string                 `json:"user_id"`
+	Nickname string                 `json:"nickname"`
+	Avatar   string                 `json:"avatar"`
+	Reasons  []RecommendationReason `json:"reasons"`
+}
+
+// GetRecommendationsResponse 好友推荐响应
+type GetRecommendationsResponse struct {
+	List []RecommendedUser `json:"list"`
+}
+
+// DismissRecommendationRequest 不感兴趣推荐用户请求
+type DismissRecommendationRequest struct {
+	UserRef idgen.Ref `json:"user_id" binding:"required"` // 不再推荐的用户ID
+	UserID  uint      `json:"-"`                          // 内部用户ID，由处理器内部设置
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "app/pkg/idgen"
+
+// ShareQRCodeRequest 获取分享二维码请求
+type ShareQRCodeRequest struct {
+	Type     string    `json:"type" form:"type" binding:"required,oneof=user post"` // 分享目标类型：user-用户主页，post-动态
+	IDRef    idgen.Ref `json:"id" form:"id" binding:"required"`                     // 分享目标ID
+	TargetID uint      `json:"-" form:"-"`                                          // 内部目标ID，由处理器内部设置
+}
+
+// ShareQRCodeResponse 获取分享二维码响应
+type ShareQRCodeResponse struct {
+	URL      string `json:"url"`       // 二维码图片地址
+	DeepLink string `json:"deep_link"` // 二维码中编码的深度链接
+}
+
+// ResolveScanRequest 解析扫码内容请求
+type ResolveScanRequest struct {
+	Content string `json:"content" binding:"required,max=512"` // 扫码得到的原始内容
+}
+
+// SharedPost 扫码解析得到的动态信息
+type SharedPost struct {
+	ID       string    `json:"id"`
+	Author   UserBrief `json:"author"`
+	Content  string    `json:"content"`   // 动态内容摘要
+	CoverURL string    `json:"cover_url"` // 首张图片缩略图，无图片时为空
+	Likes    int       `json:"likes"`
+	Comments int       `json:"comments"`
+}
+
+// ResolveScanResponse 解析扫码内容响应
+type ResolveScanResponse struct {
+	Type string      `json:"type"`           // 目标类型：user-用户主页，post-动态
+	ID   string      `json:"id"`             // 目标ID
+	User *UserBrief  `json:"user,omitempty"` // 目标为用户主页时返回
+	Post *SharedPost `json:"post,omitempty"` // 目标为动态时返回
+}
@@ -0,0 +1,81 @@
+package dto
+
+// 管理后台数据统计相关DTO
+
+// GetStatsRequest 获取统计数据请求
+type GetStatsRequest struct {
+	StartDate string `json:"start_date" form:"start_date"` // 开始日期，格式2006-01-02，默认为7天前
+	EndDate   string `json:"end_date" form:"end_date"`     // 结束日期，格式2006-01-02，默认为今天
+}
+
+// DailyStatsItem 每日统计数据项
+type DailyStatsItem struct {
+	Date         string  `json:"date"`
+	ActiveUsers  int64   `json:"active_users"`
+	NewUsers     int64   `json:"new_users"`
+	TotalUsers   int64   `json:"total_users"`
+	NewPosts     int64   `json:"new_posts"`
+	NewComments  int64   `json:"new_comments"`
+	SMSCount     int64   `json:"sms_count"`
+	SMSCost      float64 `json:"sms_cost"`
+	StorageBytes int64   `json:"storage_bytes"`
+}
+
+// StatsSummary 统计区间汇总数据
+type StatsSummary struct {
+	NewUsers     int64   `json:"new_users"`
+	NewPosts     int64   `json:"new_posts"`
+	NewComments  int64   `json:"new_comments"`
+	SMSCount     int64   `json:"sms_count"`
+	SMSCost      float64 `json:"sms_cost"`
+	PeakDAU      int64   `json:"peak_dau"`      // 区间内最高日活
+	StorageBytes int64   `json:"storage_bytes"` // 区间最后一天的存储占用
+}
+
+// GetStatsResponse 获取统计数据响应
+type GetStatsResponse struct {
+	StartDate string           `json:"start_date"`
+	EndDate   string           `json:"end_date"`
+	Summary   StatsSummary     `json:"summary"`
+	List      []DailyStatsItem `json:"list"`
+}
+
+// GetFunnelRequest 获取登录注册漏斗请求
+type GetFunnelRequest struct {
+	StartDate string `json:"start_date" form:"start_date"` // 开始日期，格式2006-01-02，默认为7天前
+	EndDate   string `json:"end_date" form:"end_date"`     // 结束日期，格式2006-01-02，默认为今天
+}
+
+// FunnelCounts 登录注册漏斗各步骤的次数
+type FunnelCounts struct {
+	CodesSent     int64 `json:"codes_sent"`     // 登录验证码发送次数
+	CodesVerified int64 `json:"codes_verified"` // 验证码校验通过次数
+	UsersCreated  int64 `json:"users_created"`  // 新注册用户数
+	LoginSuccess  int64 `json:"login_success"`  // 登录成功次数
+	LoginFailed   int64 `json:"login_failed"`   // 登录失败次数
+}
+
+// FunnelDay 登录注册漏斗每日数据
+type FunnelDay struct {
+	Date string `json:"date"`
+	FunnelCounts
+}
+
+// FunnelSummary 登录注册漏斗区间汇总及转化率
+type FunnelSummary struct {
+	FunnelCounts
+	VerifyRate       float64          `json:"verify_rate"`         // 验证码校验通过次数与发送次数之比
+	LoginRate        float64          `json:"login_rate"`          // 登录成功次数与发送次数之比
+	SignupRate       float64          `json:"signup_rate"`         // 新注册用户数与发送次数之比
+	SMSCost          float64          `json:"sms_cost"`            // 登录验证码短信费用（元），按发送次数和短信单价估算
+	SMSCostPerSignup float64          `json:"sms_cost_per_signup"` // 每个新注册用户的短信费用（元），区间内没有新用户时为0
+	FailReasons      map[string]int64 `json:"fail_reasons"`        // 各登录失败原因的次数
+}
+
+// GetFunnelResponse 获取登录注册漏斗响应
+type GetFunnelResponse struct {
+	StartDate string        `json:"start_date"`
+	EndDate   string        `json:"end_date"`
+	Summary   FunnelSummary `json:"summary"`
+	List      []FunnelDay   `json:"list"`
+}
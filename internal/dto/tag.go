@@ -0,0 +1,70 @@
+package dto
+
+import "time"
+
+// 用户标签相关DTO
+// 标签由管理员创建，手动标签由管理员维护成员，规则标签由定时任务计算成员
+
+// CreateTagRequest 创建标签请求
+type CreateTagRequest struct {
+	Name        string `json:"name" binding:"required"`                   // 标签名称，创建后不能修改
+	Description string `json:"description" binding:"max=200"`             // 标签说明
+	Type        string `json:"type" binding:"required,oneof=manual rule"` // 标签类型：manual-手动，rule-规则
+	Rule        string `json:"rule" binding:"required_if=Type rule"`      // 规则类型，规则标签必填
+	Threshold   int    `json:"threshold" binding:"min=0,max=100000"`      // 规则阈值，规则标签有效
+}
+
+// UpdateTagRequest 更新标签请求，未传的字段保持不变
+type UpdateTagRequest struct {
+	Description *string `json:"description" binding:"omitempty,max=200"`        // 标签说明
+	Rule        *string `json:"rule"`                                           // 规则类型，仅规则标签可修改
+	Threshold   *int    `json:"threshold" binding:"omitempty,min=0,max=100000"` // 规则阈值，仅规则标签可修改
+}
+
+// TagInfo 标签信息
+type TagInfo struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Type        string     `json:"type"`
+	Rule        string     `json:"rule,omitempty"`
+	Threshold   int        `json:"threshold,omitempty"`
+	MemberCount int64      `json:"member_count"`
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty"` // 规则标签最近一次计算成员的时间
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ListTagsResponse 标签列表响应
+type ListTagsResponse struct {
+	List  []TagInfo `json:"list"`
+	Rules []string  `json:"rules"` // 支持的规则类型
+}
+
+// TagMembersRequest 添加或移除标签成员请求
+type TagMembersRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1,max=1000"` // 用户ID
+}
+
+// TagMembersResponse 添加或移除标签成员响应
+type TagMembersResponse struct {
+	Affected int64 `json:"affected"` // 实际添加或移除的成员数量
+}
+
+// TagMemberInfo 标签成员信息
+type TagMemberInfo struct {
+	UserID    uint      `json:"user_id"`
+	Nickname  string    `json:"nickname"`
+	CreatedAt time.Time `json:"created_at"` // 加入时间
+}
+
+// GetTagMembersResponse 标签成员列表响应
+type GetTagMembersResponse struct {
+	Total int64           `json:"total"`
+	List  []TagMemberInfo `json:"list"`
+}
+
+// UserTagsResponse 用户拥有的标签响应
+type UserTagsResponse struct {
+	List []TagInfo `json:"list"`
+}
@@ -1,8 +1,14 @@
 package dto
 
+import (
+	"time"
+
+	"app/pkg/idgen"
+)
+
 // UserBrief 用户简要信息
 type UserBrief struct {
-	ID       uint   `json:"id"`       // 用户ID
+	ID       string `json:"id"`       // 用户ID
 	Nickname string `json:"nickname"` // 用户昵称
 	Avatar   string `json:"avatar"`   // 用户头像
 	// 可以根据需要扩展更多字段
@@ -13,8 +19,9 @@ type VerificationType string
 
 // 验证码类型常量
 const (
-	VerificationTypeLogin      VerificationType = "login"      // 登录验证码
-	VerificationTypeDeactivate VerificationType = "deactivate" // 注销账号验证码
+	VerificationTypeLogin        VerificationType = "login"         // 登录验证码
+	VerificationTypeDeactivate   VerificationType = "deactivate"    // 注销账号验证码
+	VerificationTypeChangeMobile VerificationType = "change_mobile" // 更换手机号验证码
 )
 
 // SendVerificationCodeRequest 发送验证码请求
@@ -34,43 +41,91 @@ type VerificationCodeLoginRequest struct {
 	Code   string `json:"code" binding:"required,len=6"`       // 验证码
 }
 
+// DeactivateAccountResponse 注销账号响应
+type DeactivateAccountResponse struct {
+	DeactivateAt time.Time `json:"deactivate_at"` // 注销生效时间，此前重新登录可撤销注销
+}
+
 // LoginResponse 登录响应
 type LoginResponse struct {
 	Token string `json:"token"` // JWT令牌
 	User  struct {
-		ID       uint   `json:"id"`
+		ID       string `json:"id"`
 		Username string `json:"username"`
 		Mobile   string `json:"mobile"`
 		Nickname string `json:"nickname"`
 		Avatar   string `json:"avatar"`
 	} `json:"user"` // 用户信息
+	DeactivationCancelled bool `json:"deactivation_cancelled,omitempty"` // 本次登录是否撤销了冷静期内的注销申请
 }
 
 // UserInfoResponse 用户信息响应
 type UserInfoResponse struct {
-	ID        uint   `json:"id"`
-	Username  string `json:"username"`
-	Mobile    string `json:"mobile"`
-	Nickname  string `json:"nickname"`
-	Avatar    string `json:"avatar"`
-	Status    int    `json:"status"`
-	CreatedAt string `json:"created_at"`
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Mobile    string    `json:"mobile"`
+	Nickname  string    `json:"nickname"`
+	Avatar    string    `json:"avatar"`
+	Status    int       `json:"status"`
+	IsPrivate bool      `json:"is_private"`
+	CreatedAt string    `json:"created_at"`
+	UpdatedAt time.Time `json:"-"` // 资料最后更新时间，用于计算ETag
 }
 
 // DeactivateAccountRequest 注销账号请求
 type DeactivateAccountRequest struct {
-	UserID uint   `json:"user_id" binding:"required"`          // 用户ID
-	Mobile string `json:"mobile" binding:"required,mobile_cn"` // 手机号
-	Code   string `json:"code" binding:"required,len=6"`       // 验证码
+	UserRef idgen.Ref `json:"user_id" binding:"required"`          // 用户ID
+	UserID  uint      `json:"-"`                                   // 内部用户ID，由处理器内部设置
+	Mobile  string    `json:"mobile" binding:"required,mobile_cn"` // 手机号
+	Code    string    `json:"code" binding:"required,len=6"`       // 验证码
+}
+
+// ChangeUsernameRequest 修改用户名请求
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,username"` // 新用户名，字母开头，4-20位字母、数字或下划线
+}
+
+// ChangeUsernameResponse 修改用户名响应
+type ChangeUsernameResponse struct {
+	Username     string    `json:"username"`       // 当前用户名
+	NextChangeAt time.Time `json:"next_change_at"` // 下次可以修改用户名的时间
+}
+
+// ResolveUsernameResponse 按用户名查找用户响应
+type ResolveUsernameResponse struct {
+	UserID     string `json:"user_id"`    // 用户ID
+	Username   string `json:"username"`   // 用户当前的用户名
+	Redirected bool   `json:"redirected"` // 是否通过旧用户名找到，为true时客户端应跳转到当前用户名
 }
 
 // LogoutRequest 退出登录请求
 type LogoutRequest struct {
-	UserID uint   `json:"user_id" binding:"required"` // 用户ID
-	Token  string `json:"-"`                          // JWT令牌，由处理器内部设置，不从请求中获取
+	UserRef     idgen.Ref `json:"user_id" binding:"required"`               // 用户ID
+	UserID      uint      `json:"-"`                                        // 内部用户ID，由处理器内部设置
+	DeviceToken string    `json:"device_token" binding:"omitempty,max=255"` // 当前设备的推送令牌，传入时退出后不再向该设备推送
+	Token       string    `json:"-"`                                        // JWT令牌，由处理器内部设置，不从请求中获取
 }
 
 // LogoutResponse 退出登录响应
 type LogoutResponse struct {
 	Message string `json:"message"` // 响应消息
 }
+
+// SearchUsersRequest 搜索用户请求
+type SearchUsersRequest struct {
+	Keyword string `json:"q" binding:"required" validate:"required,max=50"` // 昵称前缀或完整手机号
+	Page    int    `json:"page" binding:"required" validate:"required,min=1"`
+	Size    int    `json:"size" binding:"required" validate:"required,min=1,max=50"`
+}
+
+// SearchUsersResponse 搜索用户响应
+type SearchUsersResponse struct {
+	Total   int         `json:"total"`
+	HasMore bool        `json:"has_more"` // 是否还有下一页
+	List    []UserBrief `json:"list"`
+}
+
+// SetMobileSearchRequest 设置是否允许通过手机号搜索到自己请求
+type SetMobileSearchRequest struct {
+	Allow *bool `json:"allow" binding:"required" validate:"required"`
+}
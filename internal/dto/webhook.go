@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"time"
+
+	"app/internal/constant"
+)
+
+// Webhook相关DTO
+// 订阅由管理员为服务端调用方创建，调用方可通过开放接口查询自己的投递记录
+
+// CreateWebhookRequest 创建Webhook订阅请求
+type CreateWebhookRequest struct {
+	ClientID uint     `json:"client_id" binding:"required"`                  // 服务端调用方ID
+	URL      string   `json:"url" binding:"required,max=500"`                // 接收事件的地址，必须是http或https地址
+	Events   []string `json:"events" binding:"required,min=1,dive,required"` // 订阅的事件类型
+}
+
+// UpdateWebhookRequest 更新Webhook订阅请求，未传的字段保持不变
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" binding:"omitempty,max=500"`                // 接收事件的地址
+	Events      []string `json:"events" binding:"omitempty,min=1,dive,required"` // 订阅的事件类型
+	Status      *int     `json:"status" binding:"omitempty,oneof=0 1"`           // 状态：1-启用，0-禁用
+	ResetSecret bool     `json:"reset_secret"`                                   // 是否重新生成签名密钥
+}
+
+// WebhookInfo Webhook订阅信息
+type WebhookInfo struct {
+	ID        uint      `json:"id"`
+	ClientID  uint      `json:"client_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Status    int       `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SaveWebhookResponse 创建或更新Webhook订阅响应
+type SaveWebhookResponse struct {
+	WebhookInfo
+	Secret string `json:"secret,omitempty"` // 签名密钥，仅在创建或重新生成时返回一次
+}
+
+// ListWebhooksResponse Webhook订阅列表响应
+type ListWebhooksResponse struct {
+	List   []WebhookInfo `json:"list"`
+	Events []string      `json:"events"` // 支持订阅的事件类型
+}
+
+// GetWebhookDeliveriesRequest 获取投递记录请求
+type GetWebhookDeliveriesRequest struct {
+	SubscriptionID uint   `json:"subscription_id"` // 按订阅过滤
+	ClientID       uint   `json:"client_id"`       // 按调用方过滤
+	Status         string `json:"status"`          // 按投递状态过滤：pending、success、failed
+	Page           int    `json:"page"`
+	Size           int    `json:"size"`
+}
+
+// WebhookDeliveryInfo 投递记录信息
+type WebhookDeliveryInfo struct {
+	ID             uint       `json:"id"`
+	SubscriptionID uint       `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseCode   int        `json:"response_code"`
+	ErrorMessage   string     `json:"error_message"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"` // 待投递时为下次投递时间
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// GetWebhookDeliveriesResponse 获取投递记录响应
+type GetWebhookDeliveriesResponse struct {
+	Total int                   `json:"total"`
+	List  []WebhookDeliveryInfo `json:"list"`
+}
+
+// WebhookEvent 投递给订阅方的事件请求体
+type WebhookEvent struct {
+	ID        string      `json:"id"`         // 事件ID，重试时保持不变
+	Event     string      `json:"event"`      // 事件类型
+	CreatedAt time.Time   `json:"created_at"` // 事件发生时间
+	Data      interface{} `json:"data"`       // 事件数据
+}
+
+// WebhookUserData user.created事件数据，不包含手机号等敏感信息
+type WebhookUserData struct {
+	ID        uint      `json:"-"`  // 内部用户ID，供平台内部的订阅者使用
+	PublicID  string    `json:"id"` // 用户ID
+	Nickname  string    `json:"nickname"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicEventData 可选的事件数据接口，IsPublic返回false的事件只在平台内部处理，不投递给第三方应用
+type PublicEventData interface {
+	IsPublic() bool
+}
+
+// WebhookPostData post.created事件数据
+type WebhookPostData struct {
+	ID           uint      `json:"-"`       // 内部动态ID，供平台内部的订阅者使用
+	UserID       uint      `json:"-"`       // 内部用户ID
+	PublicID     string    `json:"id"`      // 动态ID
+	PublicUserID string    `json:"user_id"` // 发布者ID
+	Content      string    `json:"content"`
+	Images       []string  `json:"images"`
+	CreatedAt    time.Time `json:"created_at"`
+	Visibility   int       `json:"-"` // 动态可见性，仅公开动态投递给第三方应用
+}
+
+// IsPublic 是否为公开动态，实现PublicEventData接口
+func (d WebhookPostData) IsPublic() bool {
+	return constant.Visibility(d.Visibility) == constant.VisibilityPublic
+}
+
+// WebhookCommentData comment.created事件数据
+type WebhookCommentData struct {
+	ID             uint      `json:"-"`         // 内部评论ID，供平台内部的订阅者使用
+	PostID         uint      `json:"-"`         // 内部动态ID
+	UserID         uint      `json:"-"`         // 内部用户ID
+	PublicID       string    `json:"id"`        // 评论ID
+	PublicPostID   string    `json:"post_id"`   // 动态ID
+	PublicUserID   string    `json:"user_id"`   // 评论者ID
+	PublicParentID *string   `json:"parent_id"` // 回复的评论ID
+	Content        string    `json:"content"`
+	Image          string    `json:"image,omitempty"` // 评论附带的图片或表情地址
+	CreatedAt      time.Time `json:"created_at"`
+	PostVisibility int       `json:"-"` // 所属动态的可见性，仅公开动态下的评论投递给第三方应用
+}
+
+// IsPublic 是否为公开动态下的评论，实现PublicEventData接口
+func (d WebhookCommentData) IsPublic() bool {
+	return constant.Visibility(d.PostVisibility) == constant.VisibilityPublic
+}
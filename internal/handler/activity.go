@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"strconv"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/idgen"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityHandler 用户动态时间线处理器
+type ActivityHandler struct {
+	activityService service.ActivityService
+	ids             service.PublicIDService
+}
+
+// NewActivityHandler 创建用户动态时间线处理器实例
+func NewActivityHandler(activityService service.ActivityService, ids service.PublicIDService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService, ids: ids}
+}
+
+// GetActivity 获取用户本人的活动时间线，仅允许用户查看自己的时间线
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	id, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("id")))
+	if !ok {
+		return
+	}
+
+	currentUserID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+	if currentUserID.(uint) != id {
+		response.Forbidden(c, "权限不足，只能查看自己的动态", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if page < 1 || size < 1 || size > 50 {
+		response.BadRequest(c, "分页参数错误", nil)
+		return
+	}
+
+	resp, err := h.activityService.GetTimeline(c, &dto.GetActivityRequest{Page: page, Size: size}, id)
+	if err != nil {
+		response.InternalServerError(c, "获取动态时间线失败", err)
+		return
+	}
+
+	response.Success(c, "获取动态时间线成功", resp)
+}
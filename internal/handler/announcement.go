@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnnouncementHandler 系统公告处理器
+type AnnouncementHandler struct {
+	announcementService service.AnnouncementService
+}
+
+// NewAnnouncementHandler 创建系统公告处理器实例
+func NewAnnouncementHandler(announcementService service.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+	}
+}
+
+// CreateAnnouncement 发布公告
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	// 获取当前管理员ID
+	adminID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.announcementService.CreateAnnouncement(c.Request.Context(), adminID.(uint), &req)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "发布公告失败", err)
+		return
+	}
+
+	response.Success(c, "发布公告成功", res)
+}
+
+// ListAnnouncements 获取所有公告
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	res, err := h.announcementService.ListAnnouncements(c.Request.Context(), page, size)
+	if err != nil {
+		response.InternalServerError(c, "获取公告列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取公告列表成功", res)
+}
+
+// DeleteAnnouncement 删除公告
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "公告ID格式错误", err)
+		return
+	}
+
+	if err := h.announcementService.DeleteAnnouncement(c.Request.Context(), uint(id)); err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "删除公告失败", err)
+		return
+	}
+
+	response.Success(c, "删除公告成功", nil)
+}
+
+// GetUnread 获取当前用户未读的公告
+func (h *AnnouncementHandler) GetUnread(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	res, err := h.announcementService.GetUnread(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取公告失败", err)
+		return
+	}
+
+	response.Success(c, "获取公告成功", res)
+}
+
+// MarkRead 标记公告已读
+func (h *AnnouncementHandler) MarkRead(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.MarkAnnouncementsReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	if err := h.announcementService.MarkRead(c.Request.Context(), userID.(uint), req.IDs); err != nil {
+		response.InternalServerError(c, "标记公告已读失败", err)
+		return
+	}
+
+	response.Success(c, "标记公告已读成功", nil)
+}
+
+// handleError 处理公告的业务错误，已响应时返回true
+func (h *AnnouncementHandler) handleError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAnnouncementNotFound):
+		response.NotFound(c, err.Error(), err)
+	case errors.Is(err, service.ErrTagNotFound):
+		response.BadRequest(c, service.ErrTagNotFound.Error(), err)
+	case errors.Is(err, service.ErrAnnouncementInvalidSegment),
+		errors.Is(err, service.ErrAnnouncementInvalidExpireAt),
+		errors.Is(err, service.ErrAnnouncementScheduledPush):
+		response.BadRequest(c, err.Error(), err)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AudienceHandler 好友列表处理器
+type AudienceHandler struct {
+	audienceService service.AudienceService
+	ids             service.PublicIDService
+}
+
+// NewAudienceHandler 创建好友列表处理器实例
+func NewAudienceHandler(audienceService service.AudienceService, ids service.PublicIDService) *AudienceHandler {
+	return &AudienceHandler{
+		audienceService: audienceService,
+		ids:             ids,
+	}
+}
+
+// CreateList 创建好友列表
+func (h *AudienceHandler) CreateList(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.CreateAudienceListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	memberIDs, ok := resolveIDs(c, h.ids, constant.PublicIDUser, req.MemberRefs)
+	if !ok {
+		return
+	}
+	req.MemberIDs = memberIDs
+
+	res, err := h.audienceService.CreateList(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "创建好友列表失败", err)
+		return
+	}
+
+	response.Success(c, "创建好友列表成功", res)
+}
+
+// GetLists 获取好友列表
+func (h *AudienceHandler) GetLists(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	res, err := h.audienceService.GetLists(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取好友列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取好友列表成功", res)
+}
+
+// UpdateList 修改好友列表名称
+func (h *AudienceHandler) UpdateList(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdateAudienceListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	err := h.audienceService.UpdateList(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "修改好友列表失败", err)
+		return
+	}
+
+	response.Success(c, "修改好友列表成功", nil)
+}
+
+// DeleteList 删除好友列表
+func (h *AudienceHandler) DeleteList(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.DeleteAudienceListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	err := h.audienceService.DeleteList(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "删除好友列表失败", err)
+		return
+	}
+
+	response.Success(c, "删除好友列表成功", nil)
+}
+
+// AddMembers 添加好友列表成员
+func (h *AudienceHandler) AddMembers(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.AudienceListMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	memberIDs, ok := resolveIDs(c, h.ids, constant.PublicIDUser, req.MemberRefs)
+	if !ok {
+		return
+	}
+	req.MemberIDs = memberIDs
+
+	err := h.audienceService.AddMembers(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "添加列表成员失败", err)
+		return
+	}
+
+	response.Success(c, "添加列表成员成功", nil)
+}
+
+// RemoveMembers 移除好友列表成员
+func (h *AudienceHandler) RemoveMembers(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.AudienceListMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	memberIDs, ok := resolveIDs(c, h.ids, constant.PublicIDUser, req.MemberRefs)
+	if !ok {
+		return
+	}
+	req.MemberIDs = memberIDs
+
+	err := h.audienceService.RemoveMembers(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "移除列表成员失败", err)
+		return
+	}
+
+	response.Success(c, "移除列表成员成功", nil)
+}
+
+// GetMembers 获取好友列表成员
+func (h *AudienceHandler) GetMembers(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	listID, err := strconv.ParseUint(c.Param("list_id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "好友列表ID格式错误", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	req := &dto.GetAudienceListMembersRequest{
+		ListID: uint(listID),
+		Page:   page,
+		Size:   size,
+	}
+
+	res, err := h.audienceService.GetMembers(c.Request.Context(), req, userID.(uint))
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "获取列表成员失败", err)
+		return
+	}
+
+	response.Success(c, "获取列表成员成功", res)
+}
+
+// handleError 处理好友列表的业务错误，已响应时返回true
+func (h *AudienceHandler) handleError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrAudienceListNotFound):
+		response.NotFound(c, err.Error(), err)
+	case errors.Is(err, service.ErrAudienceListLimit),
+		errors.Is(err, service.ErrAudienceListNameEmpty),
+		errors.Is(err, service.ErrAudienceMemberLimit),
+		errors.Is(err, service.ErrAudienceMemberNotFriend):
+		response.BadRequest(c, err.Error(), err)
+	default:
+		return false
+	}
+	return true
+}
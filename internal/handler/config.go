@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler 运行配置处理器
+type ConfigHandler struct {
+	configService service.ConfigService
+}
+
+// NewConfigHandler 创建运行配置处理器实例
+func NewConfigHandler(configService service.ConfigService) *ConfigHandler {
+	return &ConfigHandler{
+		configService: configService,
+	}
+}
+
+// GetEffectiveConfig 获取本节点合并环境配置和环境变量后的有效配置
+func (h *ConfigHandler) GetEffectiveConfig(c *gin.Context) {
+	response.Success(c, "获取有效配置成功", h.configService.GetEffectiveConfig(c.Request.Context()))
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/constant"
+	"app/internal/service"
+	"app/pkg/idgen"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataExportHandler 用户数据导出处理器
+type DataExportHandler struct {
+	exportService service.DataExportService
+	ids           service.PublicIDService
+}
+
+// NewDataExportHandler 创建用户数据导出处理器实例
+func NewDataExportHandler(exportService service.DataExportService, ids service.PublicIDService) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+		ids:           ids,
+	}
+}
+
+// RequestExport 发起个人数据导出，仅允许用户导出自己的数据
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	userID, ok := h.checkOwner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.exportService.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "发起数据导出失败", err)
+		return
+	}
+
+	response.Success(c, "数据导出任务已提交", res)
+}
+
+// GetExport 查询数据导出任务状态
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	userID, ok := h.checkOwner(c)
+	if !ok {
+		return
+	}
+
+	exportID, err := strconv.ParseUint(c.Param("export_id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "导出任务ID格式错误", err)
+		return
+	}
+
+	res, err := h.exportService.GetExport(c.Request.Context(), userID, uint(exportID))
+	if err != nil {
+		if errors.Is(err, service.ErrExportNotFound) {
+			response.NotFound(c, "导出任务不存在", err)
+			return
+		}
+		response.InternalServerError(c, "查询导出任务失败", err)
+		return
+	}
+
+	response.Success(c, "查询导出任务成功", res)
+}
+
+// checkOwner 解析路径中的用户ID并校验是否为当前登录用户
+func (h *DataExportHandler) checkOwner(c *gin.Context) (uint, bool) {
+	id, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("id")))
+	if !ok {
+		return 0, false
+	}
+
+	currentUserID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return 0, false
+	}
+
+	if currentUserID.(uint) != id {
+		response.Forbidden(c, "权限不足，无法操作其他用户的数据", nil)
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureHandler 功能开关处理器
+type FeatureHandler struct {
+	featureService service.FeatureService
+}
+
+// NewFeatureHandler 创建功能开关处理器实例
+func NewFeatureHandler(featureService service.FeatureService) *FeatureHandler {
+	return &FeatureHandler{
+		featureService: featureService,
+	}
+}
+
+// ListFlags 获取所有功能开关
+func (h *FeatureHandler) ListFlags(c *gin.Context) {
+	res, err := h.featureService.ListFlags(c.Request.Context())
+	if err != nil {
+		response.InternalServerError(c, "获取功能开关列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取功能开关列表成功", res)
+}
+
+// SaveFlag 创建或更新功能开关
+func (h *FeatureHandler) SaveFlag(c *gin.Context) {
+	// 解析请求参数
+	var req dto.SaveFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.featureService.SaveFlag(c.Request.Context(), c.Param("key"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidFeatureFlagKey):
+			response.BadRequest(c, err.Error(), err)
+		case errors.Is(err, service.ErrTagNotFound):
+			response.BadRequest(c, service.ErrTagNotFound.Error(), err)
+		default:
+			response.InternalServerError(c, "保存功能开关失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "保存功能开关成功", res)
+}
+
+// DeleteFlag 删除功能开关
+func (h *FeatureHandler) DeleteFlag(c *gin.Context) {
+	err := h.featureService.DeleteFlag(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		if errors.Is(err, service.ErrFeatureFlagNotFound) {
+			response.NotFound(c, err.Error(), err)
+			return
+		}
+		response.InternalServerError(c, "删除功能开关失败", err)
+		return
+	}
+
+	response.Success(c, "删除功能开关成功", nil)
+}
+
+// GetUserFeatures 获取所有功能开关对当前用户的开启状态
+func (h *FeatureHandler) GetUserFeatures(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	res := h.featureService.GetUserFeatures(c.Request.Context(), userID.(uint))
+	response.Success(c, "获取功能开关成功", res)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/metrics"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FunnelHandler 管理后台登录注册漏斗处理器
+type FunnelHandler struct {
+	funnelService service.FunnelService
+}
+
+// NewFunnelHandler 创建登录注册漏斗处理器实例
+func NewFunnelHandler(funnelService service.FunnelService) *FunnelHandler {
+	return &FunnelHandler{
+		funnelService: funnelService,
+	}
+}
+
+// GetFunnel 获取日期范围内的登录注册漏斗数据、转化率和每个新用户的短信费用
+func (h *FunnelHandler) GetFunnel(c *gin.Context) {
+	var req dto.GetFunnelRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.funnelService.GetFunnel(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsDate) || errors.Is(err, service.ErrInvalidStatsRange) {
+			response.BadRequest(c, err.Error(), err)
+			return
+		}
+		response.InternalServerError(c, "获取登录注册漏斗数据失败", err)
+		return
+	}
+
+	response.Success(c, "获取登录注册漏斗数据成功", res)
+}
+
+// ExportFunnelMetrics 以Prometheus文本格式输出本节点的登录注册漏斗事件次数
+func (h *FunnelHandler) ExportFunnelMetrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.funnelService.WriteMetrics(&buf); err != nil {
+		response.InternalServerError(c, "获取登录注册漏斗指标失败", err)
+		return
+	}
+
+	c.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
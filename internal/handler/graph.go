@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/logger"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphHandler 社交关系图导出处理器
+type GraphHandler struct {
+	graphService service.GraphExportService
+}
+
+// NewGraphHandler 创建社交关系图导出处理器实例
+func NewGraphHandler(graphService service.GraphExportService) *GraphHandler {
+	return &GraphHandler{
+		graphService: graphService,
+	}
+}
+
+// ExportGraph 以CSV或JSON Lines格式流式输出关注或好友关系，供数据团队做关系图分析
+func (h *GraphHandler) ExportGraph(c *gin.Context) {
+	var req dto.ExportGraphRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误", err)
+		return
+	}
+	if req.Format == "" {
+		req.Format = constant.GraphExportFormatCSV
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == constant.GraphExportFormatJSONL {
+		contentType = "application/x-ndjson; charset=utf-8"
+	}
+	filename := fmt.Sprintf("graph_%s_%s.%s", req.Type, time.Now().Format("20060102150405"), req.Format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	_, err := h.graphService.ExportGraph(c.Request.Context(), &req, c.Writer)
+	if err == nil {
+		return
+	}
+
+	// 尚未输出数据时仍可返回错误响应
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		if errors.Is(err, service.ErrInvalidGraphExportTime) || errors.Is(err, service.ErrInvalidGraphExportRange) {
+			response.BadRequest(c, err.Error(), err)
+			return
+		}
+		response.InternalServerError(c, "导出社交关系图失败", err)
+		return
+	}
+
+	// 已输出部分数据时中断连接，客户端据此判断导出不完整，可按最后一行的关系ID续传
+	logger.Error(c, "导出社交关系图中断", logger.String("type", req.Type), logger.Err(err))
+	panic(http.ErrAbortHandler)
+}
@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
 	"app/internal/service"
 	"app/pkg/response"
+	"errors"
 	"io"
+	"mime/multipart"
+	"net/http"
 	"path/filepath"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +29,17 @@ func NewImageHandler(imageService service.ImageService, postService service.Post
 	}
 }
 
+// imageExtensions 允许上传的图片扩展名
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
 // UploadTempImage 上传临时图片
+// 以流的方式读取表单中的文件并直接上传到COS，不在内存或临时文件中缓存整个文件
 func (h *ImageHandler) UploadTempImage(c *gin.Context) {
 	// 获取当前用户ID
 	userID, exists := c.Get("userID")
@@ -32,45 +48,45 @@ func (h *ImageHandler) UploadTempImage(c *gin.Context) {
 		return
 	}
 
-	// 获取上传的文件
-	file, err := c.FormFile("image")
-	if err != nil {
-		response.BadRequest(c, "获取上传文件失败", err)
+	reader, ok := imageMultipartReader(c, 1)
+	if !ok {
 		return
 	}
 
-	// 检查文件大小（可选，例如限制为10MB）
-	if file.Size > 10*1024*1024 {
-		response.BadRequest(c, "文件大小超过限制", nil)
+	// 获取上传的文件
+	part, err := nextImagePart(reader, "image")
+	if err != nil {
+		if isRequestTooLarge(err) {
+			response.Fail(c, http.StatusRequestEntityTooLarge, "请求体过大", err)
+			return
+		}
+		response.BadRequest(c, "获取上传文件失败", err)
 		return
 	}
+	defer part.Close()
 
-	// 检查文件类型（可选）
-	ext := filepath.Ext(file.Filename)
-	validExts := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".gif":  true,
-		".webp": true,
-	}
-	if !validExts[ext] {
+	// 检查文件类型
+	filename := part.FileName()
+	if !imageExtensions[filepath.Ext(filename)] {
 		response.BadRequest(c, "不支持的文件类型", nil)
 		return
 	}
 
-	// 打开文件
-	src, err := file.Open()
-	if err != nil {
-		response.InternalServerError(c, "打开上传文件失败", err)
-		return
-	}
-	defer src.Close()
-
 	// 上传临时图片
-	tempImage, err := h.imageService.UploadTempImage(c.Request.Context(), userID.(uint), src, file.Filename, file.Size)
+	tempImage, err := h.imageService.UploadTempImage(c.Request.Context(), userID.(uint), part, filename)
 	if err != nil {
-		response.InternalServerError(c, "上传图片失败", err)
+		switch {
+		case isRequestTooLarge(err):
+			response.Fail(c, http.StatusRequestEntityTooLarge, "请求体过大", err)
+		case errors.Is(err, service.ErrUploadTooLarge):
+			response.BadRequest(c, "文件大小超过限制", err)
+		case errors.Is(err, service.ErrUnsupportedImageType):
+			response.BadRequest(c, "无法识别的图片文件", err)
+		case errors.Is(err, service.ErrStorageUnavailable):
+			response.Fail(c, http.StatusServiceUnavailable, "存储服务暂不可用，请稍后重试", err)
+		default:
+			response.InternalServerError(c, "上传图片失败", err)
+		}
 		return
 	}
 
@@ -78,12 +94,16 @@ func (h *ImageHandler) UploadTempImage(c *gin.Context) {
 		"id":           tempImage.ID,
 		"url":          tempImage.URL,
 		"size":         tempImage.Size,
+		"width":        tempImage.Width,
+		"height":       tempImage.Height,
 		"content_type": tempImage.ContentType,
-		"filename":     filepath.Base(file.Filename),
+		"hash":         tempImage.ContentHash,
+		"filename":     filepath.Base(filename),
 	})
 }
 
 // UploadMultipleTempImages 批量上传临时图片
+// 按表单顺序逐个读取文件并直接上传到COS，单个文件类型或大小不合规时记为失败并继续处理后续文件
 func (h *ImageHandler) UploadMultipleTempImages(c *gin.Context) {
 	// 获取当前用户ID
 	userID, exists := c.Get("userID")
@@ -92,112 +112,273 @@ func (h *ImageHandler) UploadMultipleTempImages(c *gin.Context) {
 		return
 	}
 
-	// 获取上传的文件（多文件表单）
-	form, err := c.MultipartForm()
-	if err != nil {
-		response.BadRequest(c, "获取上传文件失败", err)
+	reader, ok := imageMultipartReader(c, constant.ImageMaxFiles)
+	if !ok {
 		return
 	}
 
-	// 获取所有图片文件
-	files := form.File["images"]
-	if len(files) == 0 {
+	total := 0
+	imagesData := make([]map[string]interface{}, 0, constant.ImageMaxFiles)
+	failures := make([]map[string]interface{}, 0)
+	var firstErr error
+	for {
+		part, err := nextImagePart(reader, "images")
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if isRequestTooLarge(err) {
+				response.Fail(c, http.StatusRequestEntityTooLarge, "请求体过大", err)
+				return
+			}
+			response.BadRequest(c, "获取上传文件失败", err)
+			return
+		}
+
+		// 检查文件数量限制，已上传的图片作为临时图片由定时任务清理
+		total++
+		if total > constant.ImageMaxFiles {
+			part.Close()
+			response.BadRequest(c, "一次最多上传10张图片", nil)
+			return
+		}
+
+		filename := part.FileName()
+		var tempImage *model.TempImage
+		if imageExtensions[filepath.Ext(filename)] {
+			tempImage, err = h.imageService.UploadTempImage(c.Request.Context(), userID.(uint), part, filename)
+		} else {
+			err = service.ErrUnsupportedImageType
+		}
+		part.Close()
+
+		if err != nil {
+			if isRequestTooLarge(err) {
+				response.Fail(c, http.StatusRequestEntityTooLarge, "请求体过大", err)
+				return
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			failures = append(failures, map[string]interface{}{
+				"filename": filepath.Base(filename),
+				"reason":   uploadFailureReason(err),
+			})
+			continue
+		}
+
+		imagesData = append(imagesData, map[string]interface{}{
+			"id":           tempImage.ID,
+			"url":          tempImage.URL,
+			"size":         tempImage.Size,
+			"width":        tempImage.Width,
+			"height":       tempImage.Height,
+			"content_type": tempImage.ContentType,
+			"hash":         tempImage.ContentHash,
+			"filename":     filepath.Base(tempImage.ObjectKey),
+		})
+	}
+
+	if total == 0 {
 		response.BadRequest(c, "未找到上传的图片", nil)
 		return
 	}
 
-	// 检查文件数量限制（最多10张）
-	if len(files) > 10 {
-		response.BadRequest(c, "一次最多上传10张图片", nil)
+	// 检查是否全部失败
+	if len(imagesData) == 0 {
+		if errors.Is(firstErr, service.ErrStorageUnavailable) {
+			response.Fail(c, http.StatusServiceUnavailable, "存储服务暂不可用，请稍后重试", firstErr)
+			return
+		}
+		response.InternalServerError(c, "所有图片上传失败", firstErr)
 		return
 	}
 
-	// 准备参数
-	filenames := make([]string, len(files))
-	sizes := make([]int64, len(files))
-	validExts := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".gif":  true,
-		".webp": true,
+	response.Success(c, "上传完成", gin.H{
+		"total":         total,
+		"success_count": len(imagesData),
+		"fail_count":    len(failures),
+		"images":        imagesData,
+		"failures":      failures,
+	})
+}
+
+// imageMultipartReader 限制请求体大小并返回多文件表单的流式读取器，失败时写入响应并返回false
+// 参数: maxFiles - 允许上传的文件数，请求体上限为文件数乘以单个文件上限再加上表单开销
+func imageMultipartReader(c *gin.Context, maxFiles int) (*multipart.Reader, bool) {
+	limit := int64(maxFiles)*constant.ImageMaxSize + constant.ImageMultipartOverhead
+	if c.Request.ContentLength > limit {
+		response.Fail(c, http.StatusRequestEntityTooLarge, "请求体过大", nil)
+		return nil, false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		response.BadRequest(c, "获取上传文件失败", err)
+		return nil, false
 	}
+	return reader, true
+}
 
-	// 先检查所有文件的有效性
-	for i, file := range files {
-		// 检查文件大小
-		if file.Size > 10*1024*1024 {
-			response.BadRequest(c, "文件大小超过限制: "+file.Filename, nil)
-			return
+// nextImagePart 读取表单中下一个指定字段的文件，跳过其他字段，没有更多文件时返回io.EOF
+func nextImagePart(reader *multipart.Reader, field string) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
 		}
-
-		// 检查文件类型
-		ext := filepath.Ext(file.Filename)
-		if !validExts[ext] {
-			response.BadRequest(c, "不支持的文件类型: "+file.Filename, nil)
-			return
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
 		}
+		part.Close()
+	}
+}
 
-		// 保存文件信息
-		filenames[i] = file.Filename
-		sizes[i] = file.Size
+// isRequestTooLarge 判断错误是否由请求体超过大小上限引起
+func isRequestTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// uploadFailureReason 返回批量上传中单个文件失败的原因
+func uploadFailureReason(err error) string {
+	switch {
+	case errors.Is(err, service.ErrUnsupportedImageType):
+		return "不支持的文件类型"
+	case errors.Is(err, service.ErrUploadTooLarge):
+		return "文件大小超过限制"
+	case errors.Is(err, service.ErrStorageUnavailable):
+		return "存储服务暂不可用，请稍后重试"
+	default:
+		return "上传图片失败"
 	}
+}
 
-	// 打开所有文件
-	readers := make([]io.Reader, len(files))
-	openedFiles := make([]io.ReadCloser, len(files))
-	for i, file := range files {
-		src, err := file.Open()
-		if err != nil {
-			// 关闭已经打开的文件
-			for j := 0; j < i; j++ {
-				openedFiles[j].Close()
-			}
-			response.InternalServerError(c, "打开上传文件失败: "+file.Filename, err)
+// ReuseTempImage 按内容摘要复用已上传的图片
+// 客户端上传前先计算文件摘要，服务器已有相同内容时无需再次上传
+func (h *ImageHandler) ReuseTempImage(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.ReuseImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	tempImage, err := h.imageService.ReuseImageByHash(c.Request.Context(), userID.(uint), req.Hash, req.Filename)
+	if err != nil {
+		if errors.Is(err, service.ErrImageNotFound) {
+			response.NotFound(c, "图片不存在，请上传", err)
 			return
 		}
-		openedFiles[i] = src
-		readers[i] = src
+		if errors.Is(err, service.ErrStorageUnavailable) {
+			response.Fail(c, http.StatusServiceUnavailable, "存储服务暂不可用，请稍后重试", err)
+			return
+		}
+		response.InternalServerError(c, "复用图片失败", err)
+		return
 	}
 
-	// 确保所有文件都会被关闭
-	defer func() {
-		for _, f := range openedFiles {
-			if f != nil {
-				f.Close()
-			}
+	response.Success(c, "复用图片成功", gin.H{
+		"id":           tempImage.ID,
+		"url":          tempImage.URL,
+		"size":         tempImage.Size,
+		"width":        tempImage.Width,
+		"height":       tempImage.Height,
+		"content_type": tempImage.ContentType,
+		"hash":         tempImage.ContentHash,
+		"filename":     filepath.Base(tempImage.ObjectKey),
+	})
+}
+
+// PresignTempImage 获取直传COS的预签名上传地址
+// 客户端使用返回的地址和Content-Type直接PUT文件，完成后调用确认接口
+func (h *ImageHandler) PresignTempImage(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.PresignUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	upload, err := h.imageService.CreatePresignedUpload(c.Request.Context(), userID.(uint), req.Filename)
+	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedImageType) {
+			response.BadRequest(c, "不支持的文件类型", err)
+			return
+		}
+		if errors.Is(err, service.ErrStorageUnavailable) {
+			response.Fail(c, http.StatusServiceUnavailable, "存储服务暂不可用，请稍后重试", err)
+			return
 		}
-	}()
+		response.InternalServerError(c, "获取上传地址失败", err)
+		return
+	}
 
-	// 批量上传图片
-	tempImages, errs := h.imageService.UploadMultipleTempImages(c.Request.Context(), userID.(uint), readers, filenames, sizes)
+	response.Success(c, "获取上传地址成功", gin.H{
+		"upload_url":   upload.UploadURL,
+		"object_key":   upload.ObjectKey,
+		"content_type": upload.ContentType,
+		"max_size":     constant.ImageMaxSize,
+		"expires_at":   upload.ExpiresAt,
+	})
+}
 
-	// 检查是否全部失败
-	if len(tempImages) == 0 {
-		response.InternalServerError(c, "所有图片上传失败", errs[0])
+// ConfirmTempImage 确认直传完成并创建临时图片
+func (h *ImageHandler) ConfirmTempImage(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
 		return
 	}
 
-	// 准备响应数据
-	imagesData := make([]map[string]interface{}, 0, len(tempImages))
-	for _, img := range tempImages {
-		imagesData = append(imagesData, map[string]interface{}{
-			"id":           img.ID,
-			"url":          img.URL,
-			"size":         img.Size,
-			"content_type": img.ContentType,
-			"filename":     filepath.Base(img.ObjectKey),
-		})
+	// 解析请求参数
+	var req dto.ConfirmUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
 	}
 
-	// 统计上传结果
-	successCount := len(tempImages)
-	failCount := len(files) - successCount
+	tempImage, err := h.imageService.ConfirmPresignedUpload(c.Request.Context(), userID.(uint), req.ObjectKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidUploadKey),
+			errors.Is(err, service.ErrUploadTooLarge),
+			errors.Is(err, service.ErrUnsupportedImageType):
+			response.BadRequest(c, err.Error(), err)
+		case errors.Is(err, service.ErrUploadNotFound):
+			response.NotFound(c, "上传的文件不存在", err)
+		case errors.Is(err, service.ErrStorageUnavailable):
+			response.Fail(c, http.StatusServiceUnavailable, "存储服务暂不可用，请稍后重试", err)
+		default:
+			response.InternalServerError(c, "确认上传失败", err)
+		}
+		return
+	}
 
-	response.Success(c, "上传完成", gin.H{
-		"total":         len(files),
-		"success_count": successCount,
-		"fail_count":    failCount,
-		"images":        imagesData,
+	response.Success(c, "上传图片成功", gin.H{
+		"id":           tempImage.ID,
+		"url":          tempImage.URL,
+		"size":         tempImage.Size,
+		"width":        tempImage.Width,
+		"height":       tempImage.Height,
+		"content_type": tempImage.ContentType,
+		"hash":         tempImage.ContentHash,
+		"filename":     filepath.Base(tempImage.ObjectKey),
 	})
 }
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWTKeyHandler JWT签名密钥处理器
+type JWTKeyHandler struct {
+	jwtKeyService service.JWTKeyService
+}
+
+// NewJWTKeyHandler 创建JWT签名密钥处理器实例
+func NewJWTKeyHandler(jwtKeyService service.JWTKeyService) *JWTKeyHandler {
+	return &JWTKeyHandler{
+		jwtKeyService: jwtKeyService,
+	}
+}
+
+// ListKeys 获取所有已配置的签名密钥
+func (h *JWTKeyHandler) ListKeys(c *gin.Context) {
+	res, err := h.jwtKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		response.InternalServerError(c, "获取签名密钥失败", err)
+		return
+	}
+
+	response.Success(c, "获取签名密钥成功", res)
+}
+
+// RotateKey 轮换签发令牌使用的密钥
+func (h *JWTKeyHandler) RotateKey(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数，请求体可以为空
+	var req dto.RotateJWTKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "参数错误", err)
+			return
+		}
+	}
+
+	res, err := h.jwtKeyService.RotateKey(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrJWTKeyNotFound):
+			response.NotFound(c, err.Error(), err)
+		case errors.Is(err, service.ErrJWTKeyCannotRotate):
+			response.BadRequest(c, err.Error(), err)
+		default:
+			response.InternalServerError(c, "轮换签名密钥失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "轮换签名密钥成功", res)
+}
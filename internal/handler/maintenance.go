@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHandler 维护模式处理器
+type MaintenanceHandler struct {
+	maintenanceService service.MaintenanceService
+}
+
+// NewMaintenanceHandler 创建维护模式处理器实例
+func NewMaintenanceHandler(maintenanceService service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// GetMaintenance 获取当前维护模式状态
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	res, err := h.maintenanceService.GetMaintenance(c.Request.Context())
+	if err != nil {
+		response.InternalServerError(c, "获取维护模式状态失败", err)
+		return
+	}
+
+	response.Success(c, "获取维护模式状态成功", res)
+}
+
+// SetMaintenance 开启或关闭维护模式
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.maintenanceService.SetMaintenance(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "设置维护模式失败", err)
+		return
+	}
+
+	response.Success(c, "设置维护模式成功", res)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/metrics"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler 运行指标处理器
+type MetricsHandler struct {
+	metricsService service.MetricsService
+}
+
+// NewMetricsHandler 创建运行指标处理器实例
+func NewMetricsHandler(metricsService service.MetricsService) *MetricsHandler {
+	return &MetricsHandler{
+		metricsService: metricsService,
+	}
+}
+
+// GetRepositoryMetrics 获取本节点仓库各方法的调用次数、耗时和错误率
+func (h *MetricsHandler) GetRepositoryMetrics(c *gin.Context) {
+	var req dto.RepositoryMetricsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "请求参数错误", err)
+		return
+	}
+
+	response.Success(c, "获取仓库调用指标成功", h.metricsService.GetRepositoryMetrics(c.Request.Context(), &req))
+}
+
+// ExportRepositoryMetrics 以Prometheus文本格式输出本节点仓库各方法的调用指标
+func (h *MetricsHandler) ExportRepositoryMetrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.metricsService.WriteRepositoryMetrics(&buf); err != nil {
+		response.InternalServerError(c, "获取仓库调用指标失败", err)
+		return
+	}
+
+	c.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
+
+// GetPoolStats 获取本节点数据库和Redis连接池的采样序列
+func (h *MetricsHandler) GetPoolStats(c *gin.Context) {
+	response.Success(c, "获取连接池状态成功", h.metricsService.GetPoolStats(c.Request.Context()))
+}
+
+// ExportPoolMetrics 以Prometheus文本格式输出本节点连接池状态和告警次数
+func (h *MetricsHandler) ExportPoolMetrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.metricsService.WritePoolMetrics(&buf); err != nil {
+		response.InternalServerError(c, "获取连接池状态失败", err)
+		return
+	}
+
+	c.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
+
+// ExportResilienceMetrics 以Prometheus文本格式输出本节点第三方服务熔断器的状态和请求统计
+func (h *MetricsHandler) ExportResilienceMetrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.metricsService.WriteResilienceMetrics(&buf); err != nil {
+		response.InternalServerError(c, "获取熔断器指标失败", err)
+		return
+	}
+
+	c.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
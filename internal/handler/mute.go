@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MuteHandler 用户禁言处理器
+type MuteHandler struct {
+	muteService service.MuteService
+}
+
+// NewMuteHandler 创建用户禁言处理器实例
+func NewMuteHandler(muteService service.MuteService) *MuteHandler {
+	return &MuteHandler{
+		muteService: muteService,
+	}
+}
+
+// GetMute 获取用户的禁言状态
+func (h *MuteHandler) GetMute(c *gin.Context) {
+	userID, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.muteService.GetMute(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, "获取禁言状态失败", err)
+		return
+	}
+
+	response.Success(c, "获取禁言状态成功", res)
+}
+
+// MuteUser 禁言用户
+func (h *MuteHandler) MuteUser(c *gin.Context) {
+	// 获取当前用户ID
+	operatorID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	userID, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req dto.MuteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	req.UserID = userID
+
+	res, err := h.muteService.MuteUser(c.Request.Context(), &req, operatorID.(uint))
+	if err != nil {
+		h.handleError(c, "禁言用户失败", err)
+		return
+	}
+
+	response.Success(c, "禁言用户成功", res)
+}
+
+// UnmuteUser 解除用户禁言
+func (h *MuteHandler) UnmuteUser(c *gin.Context) {
+	// 获取当前用户ID
+	operatorID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	userID, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UnmuteUserRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	req.UserID = userID
+
+	if err := h.muteService.UnmuteUser(c.Request.Context(), &req, operatorID.(uint)); err != nil {
+		h.handleError(c, "解除禁言失败", err)
+		return
+	}
+
+	response.Success(c, "解除禁言成功", nil)
+}
+
+// parseUserID 解析路径中的用户ID，格式错误时响应并返回false
+func (h *MuteHandler) parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的用户ID", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 处理禁言服务返回的错误
+func (h *MuteHandler) handleError(c *gin.Context, message string, err error) {
+	if errors.Is(err, service.ErrUserNotFound) {
+		response.NotFound(c, "用户不存在", err)
+		return
+	}
+	response.InternalServerError(c, message, err)
+}
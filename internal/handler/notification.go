@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 通知设置处理器
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler 创建通知设置处理器实例
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetSettings 获取当前用户的通知设置
+func (h *NotificationHandler) GetSettings(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	res, err := h.notificationService.GetSettings(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取通知设置失败", err)
+		return
+	}
+
+	response.Success(c, "获取通知设置成功", res)
+}
+
+// UpdateSettings 更新当前用户的通知设置
+func (h *NotificationHandler) UpdateSettings(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdateNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.notificationService.UpdateSettings(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationInvalidEvent) ||
+			errors.Is(err, service.ErrNotificationInvalidQuietHours) ||
+			errors.Is(err, service.ErrNotificationInvalidTimezone) {
+			response.BadRequest(c, err.Error(), err)
+			return
+		}
+		response.InternalServerError(c, "更新通知设置失败", err)
+		return
+	}
+
+	response.Success(c, "更新通知设置成功", res)
+}
+
+// RegisterDevice 上报当前设备的推送令牌
+func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	if err := h.notificationService.RegisterDevice(c.Request.Context(), userID.(uint), &req); err != nil {
+		response.InternalServerError(c, "上报设备推送令牌失败", err)
+		return
+	}
+
+	response.Success(c, "上报设备推送令牌成功", nil)
+}
+
+// UnregisterDevice 删除当前用户的设备推送令牌
+func (h *NotificationHandler) UnregisterDevice(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	if err := h.notificationService.UnregisterDevice(c.Request.Context(), userID.(uint), c.Param("token")); err != nil {
+		response.InternalServerError(c, "删除设备推送令牌失败", err)
+		return
+	}
+
+	response.Success(c, "删除设备推送令牌成功", nil)
+}
@@ -1,10 +1,21 @@
 package handler
 
 import (
+	"app/internal/constant"
+	"app/internal/contentpolicy"
 	"app/internal/dto"
+	"app/internal/middleware"
 	"app/internal/service"
+	"app/pkg/i18n"
+	"app/pkg/idgen"
 	"app/pkg/response"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,12 +23,14 @@ import (
 // PostHandler 动态处理器
 type PostHandler struct {
 	postService service.PostService
+	ids         service.PublicIDService
 }
 
 // NewPostHandler 创建动态处理器实例
-func NewPostHandler(postService service.PostService) *PostHandler {
+func NewPostHandler(postService service.PostService, ids service.PublicIDService) *PostHandler {
 	return &PostHandler{
 		postService: postService,
+		ids:         ids,
 	}
 }
 
@@ -39,6 +52,37 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 	res, err := h.postService.CreatePost(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
+		if errors.Is(err, service.ErrRateLimited) {
+			response.TooManyRequests(c, "发布过于频繁", err)
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateContent) {
+			response.BadRequest(c, "重复发布", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidLocation) {
+			response.BadRequest(c, "位置信息无效", err)
+			return
+		}
+		if errors.Is(err, service.ErrAudienceListNotFound) {
+			response.BadRequest(c, "好友列表不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrImageNotFound) {
+			response.BadRequest(c, "图片不存在", err)
+			return
+		}
+		// 图片关联失败时动态未创建，返回各图片的关联结果
+		var imagesErr *service.PostImagesError
+		if errors.As(err, &imagesErr) {
+			response.FailWithData(c, http.StatusInternalServerError, "关联动态图片失败", imagesErr.Results, err)
+			return
+		}
+		var policyErr *contentpolicy.ValidationError
+		if errors.As(err, &policyErr) {
+			response.FailWithData(c, http.StatusBadRequest, "内容不符合发布规则", policyErr.Violations, err)
+			return
+		}
 		response.InternalServerError(c, "创建动态失败", err)
 		return
 	}
@@ -60,19 +104,26 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
 	// 解析用户ID参数（可选）
-	var targetUserID *uint
-	if userIDStr := c.Query("user_id"); userIDStr != "" {
-		id, err := strconv.ParseUint(userIDStr, 10, 32)
-		if err == nil {
-			uid := uint(id)
-			targetUserID = &uid
-		}
+	targetUserID, ok := resolveOptionalID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Query("user_id")))
+	if !ok {
+		return
+	}
+
+	// 解析查看者位置参数（可选），超出范围时忽略
+	var latitude, longitude *float64
+	lat, latErr := strconv.ParseFloat(c.Query("latitude"), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query("longitude"), 64)
+	if latErr == nil && lngErr == nil && lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180 {
+		latitude, longitude = &lat, &lng
 	}
 
 	req := &dto.GetPostsRequest{
-		UserID: targetUserID,
-		Page:   page,
-		Size:   size,
+		UserID:    targetUserID,
+		Sort:      c.DefaultQuery("sort", constant.PostSortLatest),
+		Latitude:  latitude,
+		Longitude: longitude,
+		Page:      page,
+		Size:      size,
 	}
 
 	res, err := h.postService.GetPosts(c.Request.Context(), req, userID.(uint))
@@ -81,9 +132,311 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 		return
 	}
 
+	// v1客户端的images字段为逗号分隔的图片地址
+	if middleware.GetAPIVersion(c) < 2 {
+		response.Success(c, "获取动态列表成功", toPostsResponseV1(res))
+		return
+	}
+
 	response.Success(c, "获取动态列表成功", res)
 }
 
+// GetNearbyPosts 获取地图范围内的附近动态，按缩放级别聚合为带数量的标记点
+func (h *PostHandler) GetNearbyPosts(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.GetNearbyPostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.postService.GetNearbyPosts(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取附近动态失败", err)
+		return
+	}
+
+	response.Success(c, "获取附近动态成功", res)
+}
+
+// toPostsResponseV1 将动态列表转换为v1兼容结构
+func toPostsResponseV1(res *dto.GetPostsResponse) *dto.GetPostsResponseV1 {
+	list := make([]dto.PostDetailV1, len(res.List))
+	for i, post := range res.List {
+		list[i] = toPostDetailV1(post)
+	}
+	return &dto.GetPostsResponseV1{
+		Total: res.Total,
+		List:  list,
+	}
+}
+
+// toPostDetailV1 将动态详情转换为v1兼容结构
+func toPostDetailV1(post dto.PostDetail) dto.PostDetailV1 {
+	urls := make([]string, len(post.Images))
+	for i, img := range post.Images {
+		urls[i] = img.URL
+	}
+	return dto.PostDetailV1{
+		PostDetail: post,
+		Images:     strings.Join(urls, ","),
+	}
+}
+
+// GetPost 获取动态详情
+func (h *PostHandler) GetPost(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, idgen.Ref(c.Param("post_id")))
+	if !ok {
+		return
+	}
+
+	res, err := h.postService.GetPost(c.Request.Context(), postID, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		response.InternalServerError(c, "获取动态详情失败", err)
+		return
+	}
+
+	// 动态未变化时返回304，浏览数不参与计算，允许缓存中的浏览数略有滞后
+	version := middleware.GetAPIVersion(c)
+	parts := []interface{}{version, res.ID, res.UpdatedAt.Unix(), res.Content, res.Likes, res.Comments,
+		res.Nickname, res.Avatar, res.Address}
+	if res.EditedAt != nil {
+		parts = append(parts, res.EditedAt.Unix())
+	}
+	for _, img := range res.Images {
+		parts = append(parts, img.ID, img.URL)
+	}
+	if response.NotModified(c, response.WeakETag(c, parts...)) {
+		return
+	}
+
+	// v1客户端的images字段为逗号分隔的图片地址
+	if version < 2 {
+		response.Success(c, "获取动态详情成功", toPostDetailV1(*res))
+		return
+	}
+
+	response.Success(c, "获取动态详情成功", res)
+}
+
+// UpdatePost 编辑动态
+func (h *PostHandler) UpdatePost(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, req.PostRef)
+	if !ok {
+		return
+	}
+	req.PostID = postID
+
+	res, err := h.postService.UpdatePost(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostForbidden) {
+			response.Forbidden(c, "无权操作此动态", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostNotModified) {
+			response.BadRequest(c, "动态内容未修改", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostImageInvalid) {
+			response.BadRequest(c, "图片不属于该动态", err)
+			return
+		}
+		var policyErr *contentpolicy.ValidationError
+		if errors.As(err, &policyErr) {
+			response.FailWithData(c, http.StatusBadRequest, "内容不符合发布规则", policyErr.Violations, err)
+			return
+		}
+		response.InternalServerError(c, "编辑动态失败", err)
+		return
+	}
+
+	response.Success(c, "编辑动态成功", res)
+}
+
+// GetRevisions 获取动态的修订记录，仅动态作者可以查看
+func (h *PostHandler) GetRevisions(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, idgen.Ref(c.Param("post_id")))
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	req := &dto.GetPostRevisionsRequest{
+		PostID: postID,
+		Page:   page,
+		Size:   size,
+	}
+
+	res, err := h.postService.GetRevisions(c.Request.Context(), req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostForbidden) {
+			response.Forbidden(c, "无权操作此动态", err)
+			return
+		}
+		response.InternalServerError(c, "获取动态修订记录失败", err)
+		return
+	}
+
+	response.Success(c, "获取动态修订记录成功", res)
+}
+
+// DeletePost 删除动态，动态移入回收站
+func (h *PostHandler) DeletePost(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.DeletePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, req.PostRef)
+	if !ok {
+		return
+	}
+	req.PostID = postID
+
+	err := h.postService.DeletePost(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostForbidden) {
+			response.Forbidden(c, "无权操作此动态", err)
+			return
+		}
+		response.InternalServerError(c, "删除动态失败", err)
+		return
+	}
+
+	response.Success(c, "删除动态成功", nil)
+}
+
+// GetTrash 获取回收站动态列表
+func (h *PostHandler) GetTrash(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	req := &dto.GetTrashRequest{
+		Page: page,
+		Size: size,
+	}
+
+	res, err := h.postService.GetTrash(c.Request.Context(), req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取回收站动态列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取回收站动态列表成功", res)
+}
+
+// RestorePost 从回收站恢复动态
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.RestorePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, req.PostRef)
+	if !ok {
+		return
+	}
+	req.PostID = postID
+
+	err := h.postService.RestorePost(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostForbidden) {
+			response.Forbidden(c, "无权操作此动态", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostTrashExpired) {
+			response.BadRequest(c, "动态已超过恢复期限", err)
+			return
+		}
+		response.InternalServerError(c, "恢复动态失败", err)
+		return
+	}
+
+	response.Success(c, "恢复动态成功", nil)
+}
+
 // LikePost 点赞动态
 func (h *PostHandler) LikePost(c *gin.Context) {
 	// 获取当前用户ID
@@ -99,9 +452,18 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 		response.BadRequest(c, "参数错误", err)
 		return
 	}
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, req.PostRef)
+	if !ok {
+		return
+	}
+	req.PostID = postID
 
 	err := h.postService.LikePost(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
 		response.InternalServerError(c, "点赞失败", err)
 		return
 	}
@@ -124,9 +486,44 @@ func (h *PostHandler) CommentPost(c *gin.Context) {
 		response.BadRequest(c, "参数错误", err)
 		return
 	}
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, req.PostRef)
+	if !ok {
+		return
+	}
+	req.PostID = postID
+	parentID, ok := resolveOptionalID(c, h.ids, constant.PublicIDComment, req.ParentRef)
+	if !ok {
+		return
+	}
+	req.ParentID = parentID
 
 	res, err := h.postService.CommentPost(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
+		if errors.Is(err, service.ErrRateLimited) {
+			response.TooManyRequests(c, "评论过于频繁", err)
+			return
+		}
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrCommentEmpty) {
+			response.BadRequest(c, "评论内容和图片不能同时为空", err)
+			return
+		}
+		if errors.Is(err, service.ErrImageNotFound) {
+			response.NotFound(c, "图片不存在", err)
+			return
+		}
+		if errors.Is(err, service.ErrImageRejected) {
+			response.BadRequest(c, "图片未通过审核", err)
+			return
+		}
+		var policyErr *contentpolicy.ValidationError
+		if errors.As(err, &policyErr) {
+			response.FailWithData(c, http.StatusBadRequest, "内容不符合发布规则", policyErr.Violations, err)
+			return
+		}
 		response.InternalServerError(c, "评论失败", err)
 		return
 	}
@@ -136,11 +533,16 @@ func (h *PostHandler) CommentPost(c *gin.Context) {
 
 // GetComments 获取评论列表
 func (h *PostHandler) GetComments(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
 	// 解析请求参数
-	postIDStr := c.Param("post_id")
-	postID, err := strconv.ParseUint(postIDStr, 10, 32)
-	if err != nil {
-		response.BadRequest(c, "动态ID格式错误", err)
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, idgen.Ref(c.Param("post_id")))
+	if !ok {
 		return
 	}
 
@@ -148,16 +550,226 @@ func (h *PostHandler) GetComments(c *gin.Context) {
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
 	req := &dto.GetCommentsRequest{
-		PostID: uint(postID),
+		PostID: postID,
+		Sort:   c.DefaultQuery("sort", constant.CommentSortLatest),
 		Page:   page,
 		Size:   size,
 	}
 
-	res, err := h.postService.GetComments(c.Request.Context(), req)
+	res, err := h.postService.GetComments(c.Request.Context(), req, userID.(uint))
 	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
 		response.InternalServerError(c, "获取评论列表失败", err)
 		return
 	}
 
 	response.Success(c, "获取评论列表成功", res)
 }
+
+// GetCommentReplies 获取评论的回复列表
+func (h *PostHandler) GetCommentReplies(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	commentID, ok := resolveID(c, h.ids, constant.PublicIDComment, idgen.Ref(c.Param("comment_id")))
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	req := &dto.GetCommentRepliesRequest{
+		CommentID: commentID,
+		Page:      page,
+		Size:      size,
+	}
+
+	res, err := h.postService.GetCommentReplies(c.Request.Context(), req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrCommentNotFound) {
+			response.NotFound(c, "评论不存在", err)
+			return
+		}
+		response.InternalServerError(c, "获取回复列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取回复列表成功", res)
+}
+
+// LikeComment 点赞评论
+func (h *PostHandler) LikeComment(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.LikeCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	commentID, ok := resolveID(c, h.ids, constant.PublicIDComment, req.CommentRef)
+	if !ok {
+		return
+	}
+	req.CommentID = commentID
+
+	err := h.postService.LikeComment(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "点赞评论失败", err)
+		return
+	}
+
+	response.Success(c, "点赞评论成功", nil)
+}
+
+// UnlikeComment 取消点赞评论
+func (h *PostHandler) UnlikeComment(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.LikeCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	commentID, ok := resolveID(c, h.ids, constant.PublicIDComment, req.CommentRef)
+	if !ok {
+		return
+	}
+	req.CommentID = commentID
+
+	err := h.postService.UnlikeComment(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "取消点赞评论失败", err)
+		return
+	}
+
+	response.Success(c, "取消点赞评论成功", nil)
+}
+
+// RecordView 记录动态浏览
+// 登录用户按用户ID去重，匿名访客按服务端获取的IP和User-Agent组合哈希去重，不信任客户端上报的标识
+func (h *PostHandler) RecordView(c *gin.Context) {
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, idgen.Ref(c.Param("post_id")))
+	if !ok {
+		return
+	}
+
+	var viewerID uint
+	var viewer string
+	if userID, exists := c.Get("userID"); exists {
+		viewerID = userID.(uint)
+		viewer = fmt.Sprintf("u:%d", viewerID)
+	} else {
+		sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+		viewer = "d:" + hex.EncodeToString(sum[:])
+	}
+
+	if err := h.postService.RecordClientView(c.Request.Context(), postID, viewerID, viewer); err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		response.InternalServerError(c, "记录浏览失败", err)
+		return
+	}
+
+	response.Success(c, "记录浏览成功", nil)
+}
+
+// TranslatePost 翻译动态内容，目标语言未指定时使用请求的语言
+func (h *PostHandler) TranslatePost(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	postID, ok := resolveID(c, h.ids, constant.PublicIDPost, idgen.Ref(c.Param("post_id")))
+	if !ok {
+		return
+	}
+
+	res, err := h.postService.TranslatePost(c.Request.Context(), postID, userID.(uint), translateTarget(c))
+	if err != nil {
+		if errors.Is(err, service.ErrPostNotFound) {
+			response.NotFound(c, "动态不存在", err)
+			return
+		}
+		respondTranslateError(c, err)
+		return
+	}
+
+	response.Success(c, "翻译成功", res)
+}
+
+// TranslateComment 翻译评论内容，目标语言未指定时使用请求的语言
+func (h *PostHandler) TranslateComment(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	commentID, ok := resolveID(c, h.ids, constant.PublicIDComment, idgen.Ref(c.Param("comment_id")))
+	if !ok {
+		return
+	}
+
+	res, err := h.postService.TranslateComment(c.Request.Context(), commentID, userID.(uint), translateTarget(c))
+	if err != nil {
+		if errors.Is(err, service.ErrCommentNotFound) {
+			response.NotFound(c, "评论不存在", err)
+			return
+		}
+		respondTranslateError(c, err)
+		return
+	}
+
+	response.Success(c, "翻译成功", res)
+}
+
+// translateTarget 返回请求的目标语言，未通过target参数指定时使用请求的语言
+func translateTarget(c *gin.Context) string {
+	if target := strings.TrimSpace(c.Query("target")); target != "" {
+		return target
+	}
+	return c.GetString(i18n.ContextKey)
+}
+
+// respondTranslateError 返回翻译动态和评论的通用错误响应
+func respondTranslateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTranslateLanguage):
+		response.BadRequest(c, "不支持的目标语言", err)
+	case errors.Is(err, service.ErrTranslateUnavailable):
+		response.Fail(c, http.StatusServiceUnavailable, "翻译服务暂不可用", err)
+	case errors.Is(err, service.ErrRateLimited):
+		response.TooManyRequests(c, "翻译过于频繁，请稍后再试", err)
+	default:
+		response.InternalServerError(c, "翻译失败", err)
+	}
+}
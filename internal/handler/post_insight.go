@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PostInsightHandler 动态数据分析处理器
+type PostInsightHandler struct {
+	insightService service.PostInsightService
+	ids            service.PublicIDService
+}
+
+// NewPostInsightHandler 创建动态数据分析处理器实例
+func NewPostInsightHandler(insightService service.PostInsightService, ids service.PublicIDService) *PostInsightHandler {
+	return &PostInsightHandler{
+		insightService: insightService,
+		ids:            ids,
+	}
+}
+
+// GetInsights 获取当前用户的动态在日期范围内的浏览、点赞、评论、分享和带来的关注数
+func (h *PostInsightHandler) GetInsights(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.GetPostInsightsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	postID, ok := resolveOptionalID(c, h.ids, constant.PublicIDPost, req.PostRef)
+	if !ok {
+		return
+	}
+	if postID != nil {
+		req.PostID = *postID
+	}
+
+	res, err := h.insightService.GetInsights(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidStatsDate), errors.Is(err, service.ErrInvalidStatsRange):
+			response.BadRequest(c, err.Error(), err)
+		case errors.Is(err, service.ErrPostNotFound):
+			response.NotFound(c, "动态不存在", err)
+		case errors.Is(err, service.ErrPostForbidden):
+			response.Forbidden(c, "只能查看自己动态的数据", err)
+		default:
+			response.InternalServerError(c, "获取动态数据失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "获取动态数据成功", res)
+}
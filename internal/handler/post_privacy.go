@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PostPrivacyHandler 动态隐私设置处理器
+type PostPrivacyHandler struct {
+	privacyService service.PostPrivacyService
+	ids            service.PublicIDService
+}
+
+// NewPostPrivacyHandler 创建动态隐私设置处理器实例
+func NewPostPrivacyHandler(privacyService service.PostPrivacyService, ids service.PublicIDService) *PostPrivacyHandler {
+	return &PostPrivacyHandler{
+		privacyService: privacyService,
+		ids:            ids,
+	}
+}
+
+// SetDefaultVisibility 设置发布动态的默认可见性
+func (h *PostPrivacyHandler) SetDefaultVisibility(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.SetDefaultVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	if err := h.privacyService.SetDefaultVisibility(c.Request.Context(), &req, userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "设置默认可见性失败", err)
+		return
+	}
+
+	response.Success(c, "设置默认可见性成功", nil)
+}
+
+// AddExclusions 不让指定的用户看自己的动态
+func (h *PostPrivacyHandler) AddExclusions(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.PostExclusionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	targetIDs, ok := resolveIDs(c, h.ids, constant.PublicIDUser, req.UserRefs)
+	if !ok {
+		return
+	}
+	req.UserIDs = targetIDs
+
+	if err := h.privacyService.AddExclusions(c.Request.Context(), &req, userID.(uint)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			response.NotFound(c, "用户不存在", err)
+		case errors.Is(err, service.ErrPostExclusionSelf), errors.Is(err, service.ErrPostExclusionLimit):
+			response.BadRequest(c, err.Error(), err)
+		default:
+			response.InternalServerError(c, "屏蔽用户失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "屏蔽用户成功", nil)
+}
+
+// RemoveExclusions 取消屏蔽
+func (h *PostPrivacyHandler) RemoveExclusions(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.PostExclusionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	targetIDs, ok := resolveIDs(c, h.ids, constant.PublicIDUser, req.UserRefs)
+	if !ok {
+		return
+	}
+	req.UserIDs = targetIDs
+
+	if err := h.privacyService.RemoveExclusions(c.Request.Context(), &req, userID.(uint)); err != nil {
+		response.InternalServerError(c, "取消屏蔽失败", err)
+		return
+	}
+
+	response.Success(c, "取消屏蔽成功", nil)
+}
+
+// GetExclusions 获取不让看自己动态的用户列表
+func (h *PostPrivacyHandler) GetExclusions(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	req := &dto.GetPostExclusionsRequest{
+		Page: page,
+		Size: size,
+	}
+
+	res, err := h.privacyService.GetExclusions(c.Request.Context(), req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取屏蔽用户失败", err)
+		return
+	}
+
+	response.Success(c, "获取屏蔽用户成功", res)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/constant"
+	"app/internal/service"
+	"app/pkg/idgen"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileHandler 用户主页处理器
+type ProfileHandler struct {
+	profileService service.ProfileService
+	ids            service.PublicIDService
+}
+
+// NewProfileHandler 创建用户主页处理器实例
+func NewProfileHandler(profileService service.ProfileService, ids service.PublicIDService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService, ids: ids}
+}
+
+// GetProfile 获取用户主页聚合信息
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	id, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("id")))
+	if !ok {
+		return
+	}
+
+	currentUserID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	resp, err := h.profileService.GetProfile(c.Request.Context(), id, currentUserID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "获取用户主页失败", err)
+		return
+	}
+
+	response.Success(c, "获取用户主页成功", resp)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/idgen"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileVisitHandler 主页访客处理器
+type ProfileVisitHandler struct {
+	visitService service.ProfileVisitService
+	ids          service.PublicIDService
+}
+
+// NewProfileVisitHandler 创建主页访客处理器实例
+func NewProfileVisitHandler(visitService service.ProfileVisitService, ids service.PublicIDService) *ProfileVisitHandler {
+	return &ProfileVisitHandler{visitService: visitService, ids: ids}
+}
+
+// RecordVisit 记录访问他人主页，客户端打开用户主页时调用
+func (h *ProfileVisitHandler) RecordVisit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	visiteeID, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("id")))
+	if !ok {
+		return
+	}
+
+	if err := h.visitService.RecordVisit(c, userID.(uint), visiteeID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "记录主页访问失败", err)
+		return
+	}
+
+	response.Success(c, "记录主页访问成功", nil)
+}
+
+// GetVisitors 获取最近30天访问过当前用户主页的访客
+func (h *ProfileVisitHandler) GetVisitors(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	req, ok := bindVisitPage(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.visitService.GetVisitors(c, req, userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			response.NotFound(c, "用户不存在", err)
+		case errors.Is(err, service.ErrVisitsDisabled):
+			response.Forbidden(c, err.Error(), err)
+		default:
+			response.InternalServerError(c, "获取访客记录失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "获取访客记录成功", resp)
+}
+
+// GetVisited 获取当前用户最近30天访问过的主页
+func (h *ProfileVisitHandler) GetVisited(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	req, ok := bindVisitPage(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.visitService.GetVisited(c, req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取访问记录失败", err)
+		return
+	}
+
+	response.Success(c, "获取访问记录成功", resp)
+}
+
+// SetRecordVisits 设置是否记录主页访问足迹
+func (h *ProfileVisitHandler) SetRecordVisits(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	var req dto.SetRecordVisitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误", err)
+		return
+	}
+
+	if err := h.visitService.SetRecordVisits(c, &req, userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "设置失败", err)
+		return
+	}
+
+	response.Success(c, "设置成功", nil)
+}
+
+// bindVisitPage 解析访问记录的分页参数，参数错误时写入响应并返回false
+func bindVisitPage(c *gin.Context) (*dto.GetVisitorsRequest, bool) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if page < 1 || size < 1 || size > 50 {
+		response.BadRequest(c, "分页参数错误", nil)
+		return nil, false
+	}
+	return &dto.GetVisitorsRequest{Page: page, Size: size}, true
+}
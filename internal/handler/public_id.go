@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/constant"
+	"app/internal/service"
+	"app/pkg/idgen"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicIDMessages 各资源类型的ID解析失败时的提示
+var publicIDMessages = map[string]struct{ invalid, notFound string }{
+	constant.PublicIDUser:    {invalid: "用户ID格式错误", notFound: "用户不存在"},
+	constant.PublicIDPost:    {invalid: "动态ID格式错误", notFound: "动态不存在"},
+	constant.PublicIDComment: {invalid: "评论ID格式错误", notFound: "评论不存在"},
+}
+
+// resolveID 将客户端传入的公开ID或过渡期内的数字ID解析为内部ID，失败时写入错误响应并返回false
+func resolveID(c *gin.Context, ids service.PublicIDService, kind string, ref idgen.Ref) (uint, bool) {
+	id, err := ids.Resolve(c.Request.Context(), kind, ref)
+	if err != nil {
+		respondResolveError(c, kind, err)
+		return 0, false
+	}
+	return id, true
+}
+
+// resolveIDs 批量解析客户端传入的ID，失败时写入错误响应并返回false
+func resolveIDs(c *gin.Context, ids service.PublicIDService, kind string, refs []idgen.Ref) ([]uint, bool) {
+	resolved, err := ids.ResolveAll(c.Request.Context(), kind, refs)
+	if err != nil {
+		respondResolveError(c, kind, err)
+		return nil, false
+	}
+	return resolved, true
+}
+
+// resolveOptionalID 解析可选的ID，未传入时返回nil
+func resolveOptionalID(c *gin.Context, ids service.PublicIDService, kind string, ref idgen.Ref) (*uint, bool) {
+	if ref.IsZero() {
+		return nil, true
+	}
+	id, ok := resolveID(c, ids, kind, ref)
+	if !ok {
+		return nil, false
+	}
+	return &id, true
+}
+
+// respondResolveError 按错误类型返回ID解析失败的响应
+func respondResolveError(c *gin.Context, kind string, err error) {
+	msg := publicIDMessages[kind]
+	switch {
+	case errors.Is(err, service.ErrInvalidID):
+		response.BadRequest(c, msg.invalid, err)
+	case errors.Is(err, service.ErrPublicIDNotFound):
+		response.NotFound(c, msg.notFound, err)
+	default:
+		response.InternalServerError(c, "查询ID失败", err)
+	}
+}
@@ -1,9 +1,16 @@
 package handler
 
 import (
+	"app/internal/constant"
 	"app/internal/dto"
 	"app/internal/service"
+	"app/pkg/i18n"
+	"app/pkg/idgen"
+	"app/pkg/logger"
 	"app/pkg/response"
+	"context"
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +19,14 @@ import (
 // RelationHandler 用户关系处理器
 type RelationHandler struct {
 	relationService service.RelationService
+	ids             service.PublicIDService
 }
 
 // NewRelationHandler 创建用户关系处理器实例
-func NewRelationHandler(relationService service.RelationService) *RelationHandler {
+func NewRelationHandler(relationService service.RelationService, ids service.PublicIDService) *RelationHandler {
 	return &RelationHandler{
 		relationService: relationService,
+		ids:             ids,
 	}
 }
 
@@ -36,6 +45,16 @@ func (h *RelationHandler) FollowUser(c *gin.Context) {
 		response.BadRequest(c, "参数错误", err)
 		return
 	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.TargetRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
+	sourcePostID, ok := resolveOptionalID(c, h.ids, constant.PublicIDPost, req.SourcePostRef)
+	if !ok {
+		return
+	}
+	req.SourcePostID = sourcePostID
 
 	res, err := h.relationService.FollowUser(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
@@ -61,6 +80,11 @@ func (h *RelationHandler) UnfollowUser(c *gin.Context) {
 		response.BadRequest(c, "参数错误", err)
 		return
 	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.TargetRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
 
 	err := h.relationService.UnfollowUser(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
@@ -71,13 +95,58 @@ func (h *RelationHandler) UnfollowUser(c *gin.Context) {
 	response.Success(c, "取消关注成功", nil)
 }
 
+// BatchFollow 批量关注用户，部分目标失败时返回每个目标的处理结果
+func (h *RelationHandler) BatchFollow(c *gin.Context) {
+	h.handleBatch(c, h.relationService.BatchFollow, "批量关注失败", "批量关注完成")
+}
+
+// BatchUnfollow 批量取消关注用户
+func (h *RelationHandler) BatchUnfollow(c *gin.Context) {
+	h.handleBatch(c, h.relationService.BatchUnfollow, "批量取消关注失败", "批量取消关注完成")
+}
+
+// RemoveFollowers 批量移除粉丝
+func (h *RelationHandler) RemoveFollowers(c *gin.Context) {
+	h.handleBatch(c, h.relationService.RemoveFollowers, "移除粉丝失败", "移除粉丝完成")
+}
+
+// handleBatch 解析批量关系操作请求并调用对应的服务方法
+func (h *RelationHandler) handleBatch(
+	c *gin.Context,
+	op func(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error),
+	failMsg, successMsg string,
+) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	var req dto.BatchRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误，每次最多处理100个用户", err)
+		return
+	}
+	targetIDs, ok := resolveIDs(c, h.ids, constant.PublicIDUser, req.TargetRefs)
+	if !ok {
+		return
+	}
+	req.TargetIDs = targetIDs
+
+	res, err := op(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, failMsg, err)
+		return
+	}
+
+	response.Success(c, successMsg, res)
+}
+
 // GetFollowers 获取粉丝列表
 func (h *RelationHandler) GetFollowers(c *gin.Context) {
 	// 解析请求参数
-	userIDStr := c.Param("user_id")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		response.BadRequest(c, "用户ID格式错误", err)
+	userID, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("user_id")))
+	if !ok {
 		return
 	}
 
@@ -85,7 +154,7 @@ func (h *RelationHandler) GetFollowers(c *gin.Context) {
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
 	req := &dto.GetFollowersRequest{
-		UserID: uint(userID),
+		UserID: userID,
 		Page:   page,
 		Size:   size,
 	}
@@ -99,13 +168,66 @@ func (h *RelationHandler) GetFollowers(c *gin.Context) {
 	response.Success(c, "获取粉丝列表成功", res)
 }
 
+// ExportFollowers 以统一响应格式流式输出当前用户的全部粉丝，粉丝较多时不会在内存中缓存整个列表
+func (h *RelationHandler) ExportFollowers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	next := h.relationService.IterateFollowers(c.Request.Context(), userID.(uint))
+	err := response.StreamList(c, "导出粉丝列表成功", next)
+	if err == nil {
+		return
+	}
+
+	// 尚未输出数据时仍可返回错误响应
+	if !c.Writer.Written() {
+		response.InternalServerError(c, "导出粉丝列表失败", err)
+		return
+	}
+
+	// 已输出部分数据时中断连接，客户端据此判断响应不完整
+	logger.Error(c, "导出粉丝列表中断", logger.Err(err))
+	panic(http.ErrAbortHandler)
+}
+
+// GetRelationCounts 获取用户的粉丝数和关注数
+func (h *RelationHandler) GetRelationCounts(c *gin.Context) {
+	userID, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("user_id")))
+	if !ok {
+		return
+	}
+
+	// 先按关注关系版本号校验缓存，未变化时无需统计
+	version, err := h.relationService.GetRelationVersion(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+		} else {
+			response.InternalServerError(c, "获取关注统计失败", err)
+		}
+		return
+	}
+	if response.NotModified(c, response.WeakETag(c, userID, version)) {
+		return
+	}
+
+	res, err := h.relationService.GetRelationCounts(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取关注统计失败", err)
+		return
+	}
+
+	response.Success(c, "获取关注统计成功", res)
+}
+
 // GetFollowing 获取关注列表
 func (h *RelationHandler) GetFollowing(c *gin.Context) {
 	// 解析请求参数
-	userIDStr := c.Param("user_id")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		response.BadRequest(c, "用户ID格式错误", err)
+	userID, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("user_id")))
+	if !ok {
 		return
 	}
 
@@ -113,7 +235,7 @@ func (h *RelationHandler) GetFollowing(c *gin.Context) {
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
 	req := &dto.GetFollowingRequest{
-		UserID: uint(userID),
+		UserID: userID,
 		Page:   page,
 		Size:   size,
 	}
@@ -142,6 +264,11 @@ func (h *RelationHandler) AddFriend(c *gin.Context) {
 		response.BadRequest(c, "参数错误", err)
 		return
 	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.TargetRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
 
 	res, err := h.relationService.AddFriend(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
@@ -217,6 +344,11 @@ func (h *RelationHandler) DeleteFriend(c *gin.Context) {
 		response.BadRequest(c, "参数错误", err)
 		return
 	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.TargetRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
 
 	err := h.relationService.DeleteFriend(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
@@ -268,8 +400,9 @@ func (h *RelationHandler) GetFriends(c *gin.Context) {
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
 	req := &dto.GetFriendsRequest{
-		Page: page,
-		Size: size,
+		Page:  page,
+		Size:  size,
+		Group: c.Query("group"),
 	}
 
 	res, err := h.relationService.GetFriends(c.Request.Context(), req, userID.(uint))
@@ -280,3 +413,249 @@ func (h *RelationHandler) GetFriends(c *gin.Context) {
 
 	response.Success(c, "获取好友列表成功", res)
 }
+
+// UpdateFriendRemark 设置好友备注
+func (h *RelationHandler) UpdateFriendRemark(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdateFriendRemarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.TargetRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
+
+	err := h.relationService.UpdateFriendRemark(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "设置好友备注失败", err)
+		return
+	}
+
+	response.Success(c, "设置好友备注成功", nil)
+}
+
+// UpdateFriendGroup 设置好友分组
+func (h *RelationHandler) UpdateFriendGroup(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdateFriendGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.TargetRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
+
+	err := h.relationService.UpdateFriendGroup(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "设置好友分组失败", err)
+		return
+	}
+
+	response.Success(c, "设置好友分组成功", nil)
+}
+
+// ApproveFollow 通过关注请求
+func (h *RelationHandler) ApproveFollow(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.ApproveFollowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	err := h.relationService.ApproveFollow(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "通过关注请求失败", err)
+		return
+	}
+
+	response.Success(c, "已通过关注请求", nil)
+}
+
+// RejectFollow 拒绝关注请求
+func (h *RelationHandler) RejectFollow(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.RejectFollowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	err := h.relationService.RejectFollow(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "拒绝关注请求失败", err)
+		return
+	}
+
+	response.Success(c, "已拒绝关注请求", nil)
+}
+
+// SetPrivateAccount 设置私密账号
+func (h *RelationHandler) SetPrivateAccount(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.SetPrivateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	err := h.relationService.SetPrivateAccount(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "设置私密账号失败", err)
+		return
+	}
+
+	response.Success(c, "设置私密账号成功", nil)
+}
+
+// GetFollowRequests 获取关注请求列表
+func (h *RelationHandler) GetFollowRequests(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	req := &dto.GetFollowRequestsRequest{
+		Page: page,
+		Size: size,
+	}
+
+	res, err := h.relationService.GetFollowRequests(c.Request.Context(), req, userID.(uint))
+	if err != nil {
+		response.InternalServerError(c, "获取关注请求列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取关注请求列表成功", res)
+}
+
+// DiscoverContacts 通讯录好友发现
+func (h *RelationHandler) DiscoverContacts(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.DiscoverContactsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.relationService.DiscoverContacts(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrFeatureDisabled) {
+			response.Forbidden(c, err.Error(), err)
+			return
+		}
+		if errors.Is(err, service.ErrRateLimited) {
+			response.TooManyRequests(c, "操作过于频繁，请稍后再试", err)
+			return
+		}
+		response.InternalServerError(c, "通讯录匹配失败", err)
+		return
+	}
+
+	response.Success(c, "通讯录匹配成功", res)
+}
+
+// GetRecommendations 获取好友推荐
+func (h *RelationHandler) GetRecommendations(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	res, err := h.relationService.GetRecommendations(c.Request.Context(), userID.(uint), c.GetString(i18n.ContextKey))
+	if err != nil {
+		response.InternalServerError(c, "获取好友推荐失败", err)
+		return
+	}
+
+	response.Success(c, "获取好友推荐成功", res)
+}
+
+// DismissRecommendation 对推荐的用户标记不感兴趣
+func (h *RelationHandler) DismissRecommendation(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.DismissRecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	targetID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.UserRef)
+	if !ok {
+		return
+	}
+	req.UserID = targetID
+
+	if err := h.relationService.DismissRecommendation(c.Request.Context(), &req, userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrRecommendDismissSelf) {
+			response.BadRequest(c, err.Error(), err)
+			return
+		}
+		response.InternalServerError(c, "标记不感兴趣失败", err)
+		return
+	}
+
+	response.Success(c, "已标记不感兴趣", nil)
+}
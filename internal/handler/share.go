@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShareHandler 分享处理器
+type ShareHandler struct {
+	shareService service.ShareService
+	ids          service.PublicIDService
+}
+
+// NewShareHandler 创建分享处理器实例
+func NewShareHandler(shareService service.ShareService, ids service.PublicIDService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+		ids:          ids,
+	}
+}
+
+// GetQRCode 获取分享二维码
+func (h *ShareHandler) GetQRCode(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.ShareQRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+	kind := constant.PublicIDUser
+	if req.Type == constant.ShareTargetPost {
+		kind = constant.PublicIDPost
+	}
+	targetID, ok := resolveID(c, h.ids, kind, req.IDRef)
+	if !ok {
+		return
+	}
+	req.TargetID = targetID
+
+	res, err := h.shareService.GetQRCode(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrShareTargetNotFound) {
+			response.NotFound(c, "分享的内容不存在", err)
+			return
+		}
+		response.InternalServerError(c, "获取分享二维码失败", err)
+		return
+	}
+
+	response.Success(c, "获取分享二维码成功", res)
+}
+
+// ResolveScan 解析扫码内容
+func (h *ShareHandler) ResolveScan(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未登录", nil)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.ResolveScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.shareService.ResolveScan(c.Request.Context(), &req, userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidShareLink):
+			response.BadRequest(c, "无效的分享链接", err)
+		case errors.Is(err, service.ErrShareTargetNotFound):
+			response.NotFound(c, "分享的内容不存在", err)
+		default:
+			response.InternalServerError(c, "解析分享链接失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "解析分享链接成功", res)
+}
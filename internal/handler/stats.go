@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler 管理后台数据统计处理器
+type StatsHandler struct {
+	statsService service.StatsService
+}
+
+// NewStatsHandler 创建数据统计处理器实例
+func NewStatsHandler(statsService service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetStats 获取日期范围内的统计数据
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	// 解析请求参数
+	var req dto.GetStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.statsService.GetStats(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsDate) || errors.Is(err, service.ErrInvalidStatsRange) {
+			response.BadRequest(c, err.Error(), err)
+			return
+		}
+		response.InternalServerError(c, "获取统计数据失败", err)
+		return
+	}
+
+	response.Success(c, "获取统计数据成功", res)
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler 用户标签处理器
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler 创建用户标签处理器实例
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// ListTags 获取所有标签
+func (h *TagHandler) ListTags(c *gin.Context) {
+	res, err := h.tagService.ListTags(c.Request.Context())
+	if err != nil {
+		response.InternalServerError(c, "获取标签列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取标签列表成功", res)
+}
+
+// CreateTag 创建标签
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	// 解析请求参数
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.tagService.CreateTag(c.Request.Context(), &req)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "创建标签失败", err)
+		return
+	}
+
+	response.Success(c, "创建标签成功", res)
+}
+
+// UpdateTag 更新标签
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, ok := h.parseTagID(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.tagService.UpdateTag(c.Request.Context(), id, &req)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "更新标签失败", err)
+		return
+	}
+
+	response.Success(c, "更新标签成功", res)
+}
+
+// DeleteTag 删除标签
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := h.parseTagID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), id); err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "删除标签失败", err)
+		return
+	}
+
+	response.Success(c, "删除标签成功", nil)
+}
+
+// GetMembers 获取标签成员
+func (h *TagHandler) GetMembers(c *gin.Context) {
+	id, ok := h.parseTagID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	res, err := h.tagService.GetMembers(c.Request.Context(), id, page, size)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "获取标签成员失败", err)
+		return
+	}
+
+	response.Success(c, "获取标签成员成功", res)
+}
+
+// AddMembers 向标签添加用户
+func (h *TagHandler) AddMembers(c *gin.Context) {
+	id, ok := h.parseTagID(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req dto.TagMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.tagService.AddMembers(c.Request.Context(), id, req.UserIDs)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "添加标签成员失败", err)
+		return
+	}
+
+	response.Success(c, "添加标签成员成功", res)
+}
+
+// RemoveMembers 从标签移除用户
+func (h *TagHandler) RemoveMembers(c *gin.Context) {
+	id, ok := h.parseTagID(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req dto.TagMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.tagService.RemoveMembers(c.Request.Context(), id, req.UserIDs)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "移除标签成员失败", err)
+		return
+	}
+
+	response.Success(c, "移除标签成员成功", res)
+}
+
+// GetUserTags 获取用户拥有的标签
+func (h *TagHandler) GetUserTags(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的用户ID", err)
+		return
+	}
+
+	res, err := h.tagService.GetUserTags(c.Request.Context(), uint(userID))
+	if err != nil {
+		response.InternalServerError(c, "获取用户标签失败", err)
+		return
+	}
+
+	response.Success(c, "获取用户标签成功", res)
+}
+
+// parseTagID 解析路径中的标签ID，格式错误时响应并返回false
+func (h *TagHandler) parseTagID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "标签ID格式错误", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 处理标签的业务错误，已响应时返回true
+func (h *TagHandler) handleError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		response.NotFound(c, service.ErrTagNotFound.Error(), err)
+	case errors.Is(err, service.ErrTagInvalidName),
+		errors.Is(err, service.ErrTagNameTaken),
+		errors.Is(err, service.ErrTagInvalidRule),
+		errors.Is(err, service.ErrTagRuleManaged):
+		response.BadRequest(c, err.Error(), err)
+	default:
+		return false
+	}
+	return true
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 
+	"app/internal/constant"
 	"app/internal/dto"
 	"app/internal/service"
+	"app/pkg/idgen"
 	"app/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -14,11 +17,12 @@ import (
 // UserHandler 用户处理器，负责处理用户相关的HTTP请求
 type UserHandler struct {
 	userService service.UserService
+	ids         service.PublicIDService
 }
 
 // NewUserHandler 创建用户处理器实例
-func NewUserHandler(userService service.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService service.UserService, ids service.PublicIDService) *UserHandler {
+	return &UserHandler{userService: userService, ids: ids}
 }
 
 // SendVerificationCode 发送验证码
@@ -32,6 +36,10 @@ func (h *UserHandler) SendVerificationCode(c *gin.Context) {
 	// 发送验证码
 	resp, err := h.userService.SendVerificationCode(c, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedVerificationType) {
+			response.BadRequest(c, "不支持的验证码类型", err)
+			return
+		}
 		response.InternalServerError(c, "发送验证码失败", err)
 		return
 	}
@@ -54,8 +62,14 @@ func (h *UserHandler) VerificationCodeLogin(c *gin.Context) {
 		switch err {
 		case service.ErrInvalidCode:
 			response.BadRequest(c, "验证码无效或已过期", err)
+		case service.ErrCodeLocked:
+			response.TooManyRequests(c, "验证码错误次数过多，请重新获取", err)
 		case service.ErrUserNotFound:
 			response.NotFound(c, "用户不存在", err)
+		case service.ErrAccountDeactivated:
+			response.Forbidden(c, "账号已注销", err)
+		case service.ErrAccountDisabled:
+			response.Forbidden(c, "账号已被禁用", err)
 		default:
 			response.InternalServerError(c, "登录失败", err)
 		}
@@ -72,6 +86,11 @@ func (h *UserHandler) Logout(c *gin.Context) {
 		response.BadRequest(c, "请求参数错误", err)
 		return
 	}
+	userID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.UserRef)
+	if !ok {
+		return
+	}
+	req.UserID = userID
 
 	// 从上下文中获取当前用户ID
 	currentUserID, exists := c.Get("userID")
@@ -113,6 +132,26 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	response.Success(c, resp.Message, nil)
 }
 
+// LogoutAll 退出所有设备，当前设备的令牌同样失效
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	if err := h.userService.LogoutAll(c, userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "退出所有设备失败", err)
+		return
+	}
+
+	response.Success(c, "已退出所有设备，请重新登录", nil)
+}
+
 // DeactivateAccount 注销账号
 func (h *UserHandler) DeactivateAccount(c *gin.Context) {
 	var req dto.DeactivateAccountRequest
@@ -120,6 +159,11 @@ func (h *UserHandler) DeactivateAccount(c *gin.Context) {
 		response.BadRequest(c, "请求参数错误", err)
 		return
 	}
+	userID, ok := resolveID(c, h.ids, constant.PublicIDUser, req.UserRef)
+	if !ok {
+		return
+	}
+	req.UserID = userID
 
 	// 从上下文中获取当前用户ID
 	currentUserID, exists := c.Get("userID")
@@ -134,13 +178,15 @@ func (h *UserHandler) DeactivateAccount(c *gin.Context) {
 		return
 	}
 
-	// 注销账号
-	err := h.userService.DeactivateAccount(c, &req)
+	// 申请注销账号
+	resp, err := h.userService.DeactivateAccount(c, &req)
 	if err != nil {
 		// 根据错误类型设置不同的状态码和错误消息
 		switch err {
 		case service.ErrInvalidCode:
 			response.BadRequest(c, "验证码无效或已过期", err)
+		case service.ErrCodeLocked:
+			response.TooManyRequests(c, "验证码错误次数过多，请重新获取", err)
 		case service.ErrUserNotFound:
 			response.NotFound(c, "用户不存在", err)
 		case service.ErrDeactivateFailed:
@@ -151,15 +197,13 @@ func (h *UserHandler) DeactivateAccount(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, "账号已成功注销", nil)
+	response.Success(c, "账号将在冷静期结束后注销，期间重新登录可撤销注销", resp)
 }
 
 // GetUserInfo 获取用户信息，仅允许用户查看自己的信息
 func (h *UserHandler) GetUserInfo(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		response.BadRequest(c, "无效的用户ID", err)
+	id, ok := resolveID(c, h.ids, constant.PublicIDUser, idgen.Ref(c.Param("id")))
+	if !ok {
 		return
 	}
 
@@ -169,12 +213,12 @@ func (h *UserHandler) GetUserInfo(c *gin.Context) {
 		return
 	}
 
-	if currentUserID.(uint) != uint(id) {
+	if currentUserID.(uint) != id {
 		response.Forbidden(c, "权限不足，无法查看其他用户信息", nil)
 		return
 	}
 
-	resp, err := h.userService.GetUserInfo(c, uint(id))
+	resp, err := h.userService.GetUserInfo(c, id)
 	if err != nil {
 		if err == service.ErrUserNotFound {
 			response.NotFound(c, "用户不存在", err)
@@ -184,5 +228,77 @@ func (h *UserHandler) GetUserInfo(c *gin.Context) {
 		return
 	}
 
+	// 资料未变化时返回304，客户端沿用缓存；updated_at精度为秒，可变字段一并参与计算
+	etag := response.WeakETag(c, resp.ID, resp.UpdatedAt.Unix(), resp.Username, resp.Mobile, resp.Nickname, resp.Avatar, resp.Status, resp.IsPrivate)
+	if response.NotModified(c, etag) {
+		return
+	}
+
 	response.Success(c, "获取用户信息成功", resp)
 }
+
+// SearchUsers 按昵称前缀或手机号搜索用户
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	keyword := strings.TrimSpace(c.Query("q"))
+	if keyword == "" || len([]rune(keyword)) > 50 {
+		response.BadRequest(c, "搜索关键词不能为空且不超过50个字符", nil)
+		return
+	}
+	if page < 1 || size < 1 || size > 50 {
+		response.BadRequest(c, "分页参数错误", nil)
+		return
+	}
+
+	req := &dto.SearchUsersRequest{
+		Keyword: keyword,
+		Page:    page,
+		Size:    size,
+	}
+
+	resp, err := h.userService.SearchUsers(c, req, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrRateLimited) {
+			response.TooManyRequests(c, "搜索过于频繁，请稍后再试", err)
+			return
+		}
+		response.InternalServerError(c, "搜索用户失败", err)
+		return
+	}
+
+	response.Success(c, "搜索用户成功", resp)
+}
+
+// SetMobileSearch 设置是否允许他人通过手机号搜索到自己
+func (h *UserHandler) SetMobileSearch(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	var req dto.SetMobileSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误", err)
+		return
+	}
+
+	if err := h.userService.SetMobileSearch(c, &req, userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "设置失败", err)
+		return
+	}
+
+	response.Success(c, "设置成功", nil)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsernameHandler 用户名处理器
+type UsernameHandler struct {
+	usernameService service.UsernameService
+}
+
+// NewUsernameHandler 创建用户名处理器实例
+func NewUsernameHandler(usernameService service.UsernameService) *UsernameHandler {
+	return &UsernameHandler{usernameService: usernameService}
+}
+
+// ChangeUsername 修改当前用户的用户名
+func (h *UsernameHandler) ChangeUsername(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未授权访问", nil)
+		return
+	}
+
+	var req dto.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "用户名须以字母开头，由4-20位字母、数字或下划线组成", err)
+		return
+	}
+
+	resp, err := h.usernameService.ChangeUsername(c, &req, userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			response.NotFound(c, "用户不存在", err)
+		case errors.Is(err, service.ErrUsernameTaken), errors.Is(err, service.ErrUsernameReserved):
+			response.BadRequest(c, err.Error(), err)
+		case errors.Is(err, service.ErrUsernameChangeTooSoon):
+			response.TooManyRequests(c, err.Error(), err)
+		default:
+			response.InternalServerError(c, "修改用户名失败", err)
+		}
+		return
+	}
+
+	response.Success(c, "修改用户名成功", resp)
+}
+
+// ResolveUsername 按用户名查找用户，旧用户名在保护期内返回用户当前的用户名
+func (h *UsernameHandler) ResolveUsername(c *gin.Context) {
+	resp, err := h.usernameService.ResolveUsername(c, c.Param("username"))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			response.NotFound(c, "用户不存在", err)
+			return
+		}
+		response.InternalServerError(c, "查找用户失败", err)
+		return
+	}
+
+	response.Success(c, "查找用户成功", resp)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"app/internal/dto"
+	"app/internal/service"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler Webhook处理器
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler 创建Webhook处理器实例
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ListWebhooks 获取所有Webhook订阅
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	res, err := h.webhookService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		response.InternalServerError(c, "获取Webhook订阅列表失败", err)
+		return
+	}
+
+	response.Success(c, "获取Webhook订阅列表成功", res)
+}
+
+// CreateWebhook 创建Webhook订阅
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	// 解析请求参数
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.webhookService.CreateSubscription(c.Request.Context(), &req)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "创建Webhook订阅失败", err)
+		return
+	}
+
+	response.Success(c, "创建Webhook订阅成功", res)
+}
+
+// UpdateWebhook 更新Webhook订阅
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "订阅ID格式错误", err)
+		return
+	}
+
+	// 解析请求参数
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误", err)
+		return
+	}
+
+	res, err := h.webhookService.UpdateSubscription(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "更新Webhook订阅失败", err)
+		return
+	}
+
+	response.Success(c, "更新Webhook订阅成功", res)
+}
+
+// DeleteWebhook 删除Webhook订阅
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "订阅ID格式错误", err)
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), uint(id)); err != nil {
+		if h.handleError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "删除Webhook订阅失败", err)
+		return
+	}
+
+	response.Success(c, "删除Webhook订阅成功", nil)
+}
+
+// GetDeliveries 获取指定订阅的投递记录
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "订阅ID格式错误", err)
+		return
+	}
+
+	req := h.deliveriesRequest(c)
+	req.SubscriptionID = uint(id)
+
+	res, err := h.webhookService.GetDeliveries(c.Request.Context(), req)
+	if err != nil {
+		response.InternalServerError(c, "获取投递记录失败", err)
+		return
+	}
+
+	response.Success(c, "获取投递记录成功", res)
+}
+
+// GetClientDeliveries 获取当前调用方所有订阅的投递记录，供开放接口使用
+func (h *WebhookHandler) GetClientDeliveries(c *gin.Context) {
+	// 获取当前调用方ID
+	clientID, exists := c.Get("apiClientID")
+	if !exists {
+		response.Unauthorized(c, "签名验证失败", nil)
+		return
+	}
+
+	req := h.deliveriesRequest(c)
+	req.ClientID = clientID.(uint)
+
+	res, err := h.webhookService.GetDeliveries(c.Request.Context(), req)
+	if err != nil {
+		response.InternalServerError(c, "获取投递记录失败", err)
+		return
+	}
+
+	response.Success(c, "获取投递记录成功", res)
+}
+
+// deliveriesRequest 从查询参数解析投递记录的过滤和分页条件
+func (h *WebhookHandler) deliveriesRequest(c *gin.Context) *dto.GetWebhookDeliveriesRequest {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	return &dto.GetWebhookDeliveriesRequest{
+		Status: c.Query("status"),
+		Page:   page,
+		Size:   size,
+	}
+}
+
+// handleError 处理Webhook的业务错误，已响应时返回true
+func (h *WebhookHandler) handleError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		response.NotFound(c, err.Error(), err)
+	case errors.Is(err, service.ErrWebhookInvalidURL),
+		errors.Is(err, service.ErrWebhookInvalidEvent),
+		errors.Is(err, service.ErrWebhookClientNotFound):
+		response.BadRequest(c, err.Error(), err)
+	default:
+		return false
+	}
+	return true
+}
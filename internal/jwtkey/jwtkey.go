@@ -0,0 +1,22 @@
+// Package jwtkey 保存JWT签名密钥的轮换状态
+// 当前密钥ID保存在Redis中，由管理后台接口轮换，各API服务实例签发令牌时读取，保证轮换后所有实例同时切换
+package jwtkey
+
+import (
+	"app/internal/cachekey"
+	"app/pkg/redis"
+)
+
+// Current 读取轮换后的当前密钥ID，未轮换或读取失败时返回空字符串，由调用方使用配置的默认密钥
+func Current() string {
+	kid, err := redis.Get(cachekey.JWTCurrentKey().String())
+	if err != nil {
+		return ""
+	}
+	return kid
+}
+
+// SetCurrent 设置当前密钥ID，轮换状态不过期
+func SetCurrent(kid string) error {
+	return redis.Set(cachekey.JWTCurrentKey().String(), kid, 0)
+}
@@ -0,0 +1,55 @@
+// Package maintenance 提供全局维护模式开关
+// 维护状态保存在Redis中，由管理后台接口设置，API服务和定时任务程序据此暂停对外服务和非关键任务
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/cachekey"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// State 维护模式状态
+type State struct {
+	Message    string    `json:"message"`     // 返回给客户端的维护提示
+	RetryAfter int       `json:"retry_after"` // 建议客户端重试的间隔（秒）
+	StartedAt  time.Time `json:"started_at"`  // 开始维护的时间
+	OperatorID uint      `json:"operator_id"` // 开启维护模式的管理员ID
+}
+
+// Get 获取当前维护状态，未处于维护模式时返回nil
+func Get() (*State, error) {
+	var state State
+	if err := redis.GetObj(cachekey.Maintenance().String(), &state); err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Enable 开启维护模式，维护状态不过期，需要手动关闭
+func Enable(state *State) error {
+	return redis.SetObj(cachekey.Maintenance().String(), state, 0)
+}
+
+// Disable 关闭维护模式
+func Disable() error {
+	_, err := redis.Del(cachekey.Maintenance().String())
+	return err
+}
+
+// Active 判断当前是否处于维护模式
+// 读取状态失败时视为未处于维护模式，避免Redis故障导致全部请求被拒绝
+func Active(ctx context.Context) (*State, bool) {
+	state, err := Get()
+	if err != nil {
+		logger.Warn(ctx, "读取维护模式状态失败", logger.Err(err))
+		return nil, false
+	}
+	return state, state != nil
+}
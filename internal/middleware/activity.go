@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"app/internal/cachekey"
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityTracker 用户活跃度统计中间件
+// 在请求处理完成后，将已认证用户记录到当日的HyperLogLog中，用于统计日活跃用户数
+// 同时记录用户最近活跃时间，由用户清理任务定期同步到数据库
+func ActivityTracker() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID, exists := c.Get("userID")
+		if !exists {
+			return
+		}
+
+		now := time.Now()
+		key := cachekey.StatsDAU(now)
+		if _, err := redis.PFAdd(key.String(), userID); err != nil {
+			logger.Warn(c, "记录用户活跃数据失败", logger.Err(err))
+			return
+		}
+		_, _ = redis.Expire(key.String(), key.TTL())
+
+		if _, err := redis.HSet(cachekey.UserLastActive().String(), fmt.Sprint(userID), now.Unix()); err != nil {
+			logger.Warn(c, "记录用户最近活跃时间失败", logger.Err(err))
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"app/config"
+	"app/internal/constant"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware 创建管理员权限校验中间件
+// 需在AuthMiddleware之后使用，仅允许拥有管理员角色的用户访问
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("userID"); !exists {
+			response.Unauthorized(c, "用户未登录", nil)
+			c.Abort()
+			return
+		}
+
+		if !hasRole(c, constant.RoleAdmin) {
+			response.Forbidden(c, "权限不足，仅管理员可访问", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// isAdmin 检查用户是否为配置中的管理员
+func isAdmin(userID uint) bool {
+	for _, id := range config.GetAdminConfig().UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
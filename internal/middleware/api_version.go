@@ -0,0 +1,24 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// APIVersionKey 上下文中保存API版本号的键
+const APIVersionKey = "apiVersion"
+
+// APIVersion 创建API版本标记中间件，处理器据此返回对应版本的响应结构
+func APIVersion(version int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(APIVersionKey, version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion 获取当前请求的API版本号，未标记版本的路由视为v1
+func GetAPIVersion(c *gin.Context) int {
+	if version, ok := c.Get(APIVersionKey); ok {
+		if v, ok := version.(int); ok {
+			return v
+		}
+	}
+	return 1
+}
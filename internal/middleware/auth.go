@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/pkg/jwt"
+	"app/pkg/redis"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 认证结果在请求上下文中的键
+const (
+	// claimsContextKey 校验通过的令牌声明
+	claimsContextKey = "authClaims"
+	// authFailureContextKey 令牌校验失败的原因
+	authFailureContextKey = "authFailure"
+)
+
+// TokenVersionLoader 获取用户当前的令牌版本号
+type TokenVersionLoader func(ctx context.Context, userID uint) (uint, error)
+
+// tokenVersionLoader 令牌版本号的获取方式，未设置时不校验令牌版本
+var tokenVersionLoader TokenVersionLoader
+
+// SetTokenVersionLoader 设置令牌版本号的获取方式，需在启动HTTP服务前调用
+func SetTokenVersionLoader(loader TokenVersionLoader) {
+	tokenVersionLoader = loader
+}
+
+// authFailure 令牌校验失败的响应信息
+type authFailure struct {
+	status  int
+	message string
+	err     error
+}
+
+// AuthMiddleware 创建JWT认证中间件，验证请求中的令牌并提取用户信息
+// 认证通过后在上下文中设置userID、username、tokenID和roles
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, failure := authenticate(c)
+		if failure != nil {
+			response.Fail(c, failure.status, failure.message, failure.err)
+			c.Abort()
+			return
+		}
+
+		setIdentity(c, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware 创建可选认证中间件
+// 请求携带有效令牌时提取用户信息，未携带或令牌无效时按匿名请求继续处理
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, failure := authenticate(c); failure == nil {
+			setIdentity(c, claims)
+		}
+		c.Next()
+	}
+}
+
+// authenticate 校验请求中的令牌：解析签名和有效期，检查令牌是否已退出登录或被统一失效
+// 校验结果缓存在请求上下文中，同一请求经过多个认证中间件时只校验一次
+func authenticate(c *gin.Context) (*jwt.CustomClaims, *authFailure) {
+	if v, ok := c.Get(claimsContextKey); ok {
+		return v.(*jwt.CustomClaims), nil
+	}
+	if v, ok := c.Get(authFailureContextKey); ok {
+		return nil, v.(*authFailure)
+	}
+
+	claims, failure := verifyRequestToken(c)
+	if failure != nil {
+		c.Set(authFailureContextKey, failure)
+		return nil, failure
+	}
+	c.Set(claimsContextKey, claims)
+	return claims, nil
+}
+
+// verifyRequestToken 从请求头中提取并校验令牌
+func verifyRequestToken(c *gin.Context) (*jwt.CustomClaims, *authFailure) {
+	authHeader := c.GetHeader(jwt.AuthHeaderName)
+	if authHeader == "" {
+		return nil, &authFailure{http.StatusUnauthorized, "未提供授权令牌", jwt.ErrTokenNotProvided}
+	}
+
+	parts := strings.SplitN(authHeader, " ", 2)
+	if !(len(parts) == 2 && parts[0] == jwt.AuthHeaderPrefix) {
+		return nil, &authFailure{http.StatusUnauthorized, "无效的授权格式", nil}
+	}
+
+	claims, err := jwt.ParseToken(parts[1])
+	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			return nil, &authFailure{http.StatusUnauthorized, "令牌已过期", err}
+		case errors.Is(err, jwt.ErrTokenInvalid):
+			return nil, &authFailure{http.StatusUnauthorized, "无效的令牌", err}
+		case errors.Is(err, jwt.ErrTokenNotProvided):
+			return nil, &authFailure{http.StatusUnauthorized, "未提供授权令牌", err}
+		default:
+			return nil, &authFailure{http.StatusInternalServerError, "验证令牌时发生错误", err}
+		}
+	}
+
+	// 已退出登录的令牌，用户会话被统一失效（如账号休眠）前签发的令牌，以及用户退出所有设备前签发的令牌不再有效
+	if isTokenBlacklisted(claims, parts[1]) || isTokenRevoked(claims) || isTokenVersionStale(c, claims) {
+		return nil, &authFailure{http.StatusUnauthorized, "令牌已失效，请重新登录", nil}
+	}
+
+	return claims, nil
+}
+
+// setIdentity 将认证通过的用户信息写入上下文，所有认证中间件使用相同的键
+func setIdentity(c *gin.Context, claims *jwt.CustomClaims) {
+	c.Set("userID", claims.UserID)
+	c.Set("username", claims.Username)
+	if claims.ID != "" {
+		c.Set("tokenID", claims.ID)
+	}
+
+	roles := []string{constant.RoleUser}
+	if isAdmin(claims.UserID) {
+		roles = append(roles, constant.RoleAdmin)
+	}
+	c.Set("roles", roles)
+}
+
+// hasRole 判断当前用户是否拥有指定角色，需在认证中间件之后使用
+func hasRole(c *gin.Context, role string) bool {
+	roles, _ := c.Get("roles")
+	list, _ := roles.([]string)
+	for _, r := range list {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isTokenBlacklisted 检查令牌是否已退出登录，按令牌ID记录，没有ID的令牌按令牌原文记录
+func isTokenBlacklisted(claims *jwt.CustomClaims, tokenString string) bool {
+	id := claims.ID
+	if id == "" {
+		id = tokenString
+	}
+	_, err := redis.Get(cachekey.TokenBlacklist(id).String())
+	return err == nil
+}
+
+// isTokenRevoked 检查令牌是否早于用户会话失效时间签发
+func isTokenRevoked(claims *jwt.CustomClaims) bool {
+	value, err := redis.Get(cachekey.TokenRevokedBefore(claims.UserID).String())
+	if err != nil || claims.IssuedAt == nil {
+		return false
+	}
+	revokedBefore, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return claims.IssuedAt.Time.Unix() < revokedBefore
+}
+
+// isTokenVersionStale 检查令牌的版本号是否低于用户当前的令牌版本号，获取版本号失败时不拒绝请求
+func isTokenVersionStale(c *gin.Context, claims *jwt.CustomClaims) bool {
+	if tokenVersionLoader == nil {
+		return false
+	}
+	version, err := tokenVersionLoader(c.Request.Context(), claims.UserID)
+	if err != nil {
+		return false
+	}
+	return claims.TokenVersion < version
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"app/config"
+	"app/internal/constant"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// 支持的响应压缩编码
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// 未配置server.compression.content_types时允许压缩的内容类型
+var defaultCompressContentTypes = []string{"application/json", "application/x-ndjson", "text/csv", "text/plain"}
+
+// Compress 响应压缩中间件，需在Logger之前注册，请求日志记录压缩前的响应体
+// 客户端支持时按br、gzip的优先顺序压缩配置允许的内容类型，响应体达到最小大小时才压缩
+// 响应体先缓存到最小大小再决定是否压缩；流式输出的响应在首次刷新时决定，之后每次刷新同时刷新压缩器
+func Compress() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetServerConfig().Compression
+		if !cfg.Enabled || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		contentTypes := cfg.ContentTypes
+		if len(contentTypes) == 0 {
+			contentTypes = defaultCompressContentTypes
+		}
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        cfg.MinSize,
+			contentTypes:   contentTypes,
+		}
+		c.Writer = cw
+
+		c.Next()
+
+		cw.close()
+	}
+}
+
+// negotiateEncoding 按Accept-Encoding选择压缩编码，权重相同时优先br，客户端不支持时返回空字符串
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = q
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+
+	// 未单独列出的编码沿用*的权重
+	weightOf := func(name string) float64 {
+		if weight, ok := weights[name]; ok {
+			return weight
+		}
+		return weights["*"]
+	}
+	br, gz := weightOf(encodingBrotli), weightOf(encodingGzip)
+	switch {
+	case br > 0 && br >= gz:
+		return encodingBrotli
+	case gz > 0:
+		return encodingGzip
+	}
+	return ""
+}
+
+// compressor 压缩器，gzip.Writer和brotli.Writer均实现该接口
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// 压缩器对象池，避免每个请求重新分配压缩器的内部缓冲区
+var compressorPools = map[string]*sync.Pool{
+	encodingGzip: {New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, constant.CompressionGzipLevel)
+		return w
+	}},
+	encodingBrotli: {New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, constant.CompressionBrotliLevel)
+	}},
+}
+
+// compressWriter 压缩响应写入器
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding     string
+	minSize      int
+	contentTypes []string
+
+	buf     []byte     // 决定是否压缩前缓存的响应体
+	decided bool       // 是否已决定是否压缩
+	encoder compressor // 压缩时使用的压缩器，不压缩时为nil
+}
+
+// Write 未决定是否压缩时缓存响应体，缓存达到最小大小后决定
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.minSize {
+			if err := w.decide(false); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 实现ResponseWriter接口的WriteString方法
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written 缓存中已有响应体时同样视为已写入
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// WriteHeaderNow 发送响应头前需要先决定是否压缩
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush 流式输出时不再等待最小大小，立即决定是否压缩并发送已压缩的数据
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide 根据响应状态码、响应头和已缓存的响应体决定是否压缩，并写出缓存的响应体
+// streaming为true时不检查最小大小
+func (w *compressWriter) decide(streaming bool) error {
+	w.decided = true
+	if w.compressible(streaming) {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+
+		w.encoder = compressorPools[w.encoding].Get().(compressor)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// compressible 检查响应是否需要压缩
+func (w *compressWriter) compressible(streaming bool) bool {
+	if !streaming && (len(w.buf) == 0 || len(w.buf) < w.minSize) {
+		return false
+	}
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusPartialContent || status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, contentType := range w.contentTypes {
+		if strings.EqualFold(mediaType, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// close 请求处理完成后写出未达到最小大小的响应体，或结束压缩并将压缩器放回对象池
+func (w *compressWriter) close() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.encoder == nil {
+		return
+	}
+	_ = w.encoder.Close()
+	w.encoder.Reset(io.Discard)
+	compressorPools[w.encoding].Put(w.encoder)
+	w.encoder = nil
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS 跨域访问中间件
+// 每次请求读取最新的跨域配置，配置文件修改后无需重启即可生效
+// 预检请求在此直接返回，不进入后续路由
+func CORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		cfg := config.GetCORSConfig()
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// 允许携带凭证时不能使用通配符，需回显具体来源
+		if containsString(cfg.AllowedOrigins, "*") && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(cfg.ExposedHeaders) > 0 {
+				c.Header("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		if len(cfg.AllowedMethods) > 0 {
+			c.Header("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+		}
+		if len(cfg.AllowedHeaders) > 0 {
+			c.Header("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		} else if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+			c.Header("Access-Control-Allow-Headers", requested)
+		}
+		if maxAge, err := time.ParseDuration(cfg.MaxAge); err == nil && maxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// originAllowed 判断来源是否在允许列表中
+// 支持完全匹配、*匹配所有来源，以及*.example.com匹配该域名的所有子域名
+func originAllowed(allowed []string, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Hostname()
+
+	for _, pattern := range allowed {
+		switch {
+		case pattern == "*":
+			return true
+		case strings.EqualFold(pattern, origin):
+			return true
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(strings.ToLower(host), strings.ToLower(pattern[1:])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(list []string, target string) bool {
+	for _, item := range list {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
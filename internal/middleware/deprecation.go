@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 弃用提示相关响应头
+const (
+	// DeprecationHeader 标识接口已被弃用
+	DeprecationHeader = "Deprecation"
+	// SunsetHeader 接口计划下线时间（HTTP日期格式）
+	SunsetHeader = "Sunset"
+	// LinkHeader 指向替代接口的链接
+	LinkHeader = "Link"
+)
+
+// Deprecation 创建接口弃用提示中间件
+// 为即将下线的接口添加Deprecation、Sunset和Link响应头，提示客户端迁移到新版本
+// sunset为零值时不设置Sunset头，successor为空时不设置Link头
+func Deprecation(sunset time.Time, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(DeprecationHeader, "true")
+		if !sunset.IsZero() {
+			c.Header(SunsetHeader, sunset.UTC().Format(http.TimeFormat))
+		}
+		if successor != "" {
+			c.Header(LinkHeader, fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+
+	"app/pkg/geoip"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GeoIP 创建IP归属地中间件，将客户端IP解析为国家或地区和省份
+// 解析结果保存到gin.Context和请求上下文，可通过geoip.FromContext获取，用于风控、数据分析和语言协商，日志中以client_location字段记录
+// 未配置IP归属地解析时client为nil，不做处理；解析失败不影响请求处理
+func GeoIP(client *geoip.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if client == nil {
+			c.Next()
+			return
+		}
+
+		location, err := client.Lookup(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			if !errors.Is(err, geoip.ErrNotFound) {
+				logger.Warn(c, "解析IP归属地失败", logger.String("client_ip", c.ClientIP()), logger.Err(err))
+			}
+			c.Next()
+			return
+		}
+
+		c.Set(geoip.ContextKey, location)
+		c.Set(logger.ClientLocationKey, location.String())
+		// 同时保存到请求上下文，以c.Request.Context()传递的调用链同样可以获取
+		ctx := geoip.NewContext(c.Request.Context(), location)
+		c.Request = c.Request.WithContext(logger.WithClientLocation(ctx, location.String()))
+		c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"app/config"
+	"app/internal/constant"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求限制相关错误
+var (
+	ErrRequestTimeout  = errors.New("请求处理超时")
+	ErrRequestTooLarge = errors.New("请求体过大")
+)
+
+// RequestLimit 创建请求限制中间件，为路由组设置处理超时和请求体大小上限
+// 超时通过请求上下文的截止时间传递到服务层和数据访问层，请求体超限时读取会返回错误
+// 参数: group - 路由组名称，对应server.limits中的配置，未配置的项使用全局默认值
+func RequestLimit(group string) gin.HandlerFunc {
+	timeout, maxBodySize := resolveLimit(group)
+
+	return func(c *gin.Context) {
+		// 声明了长度的请求体直接拒绝，避免读取无用数据
+		if c.Request.ContentLength > maxBodySize {
+			response.Fail(c, http.StatusRequestEntityTooLarge, "请求体过大", ErrRequestTooLarge)
+			c.Abort()
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// 处理器因超时提前返回但未写入响应时，统一返回超时错误
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			response.Fail(c, http.StatusGatewayTimeout, "请求处理超时", ErrRequestTimeout)
+		}
+	}
+}
+
+// resolveLimit 读取路由组的超时和请求体大小上限，依次使用路由组配置、全局配置和默认值
+func resolveLimit(group string) (time.Duration, int64) {
+	cfg := config.GetServerConfig()
+
+	timeout := constant.RequestDefaultTimeout
+	if d, err := time.ParseDuration(cfg.RequestTimeout); err == nil && d > 0 {
+		timeout = d
+	}
+	maxBodySize := int64(constant.RequestDefaultMaxBodySize)
+	if cfg.MaxBodySize > 0 {
+		maxBodySize = cfg.MaxBodySize
+	}
+
+	if limit, ok := cfg.Limits[group]; ok {
+		if d, err := time.ParseDuration(limit.Timeout); err == nil && d > 0 {
+			timeout = d
+		}
+		if limit.MaxBodySize > 0 {
+			maxBodySize = limit.MaxBodySize
+		}
+	}
+
+	return timeout, maxBodySize
+}
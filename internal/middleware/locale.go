@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"app/pkg/geoip"
+	"app/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale 创建语言协商中间件，根据Accept-Language请求头确定响应消息的语言
+// 未携带Accept-Language时按IP归属地选择默认语言，需在GeoIP中间件之后执行
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		acceptLanguage := c.GetHeader("Accept-Language")
+		lang := i18n.Match(acceptLanguage)
+		if acceptLanguage == "" {
+			if location := geoip.FromContext(c); location != nil {
+				lang = i18n.ForCountry(location.CountryCode)
+			}
+		}
+		c.Set(i18n.ContextKey, lang)
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 	"time"
 
+	"app/config"
+	"app/internal/constant"
 	"app/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -19,23 +23,22 @@ import (
 // 最大请求/响应体大小限制 (5MB)
 const MaxBodySize = 5 * 1024 * 1024
 
-// Logger 请求日志中间件
+// Logger 请求日志中间件，写入请求日志（logger.outputs.access）
+// 请求头、查询参数和JSON、表单格式的请求、响应体按logger.redaction配置脱敏后记录
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取或生成请求ID
-		requestID := c.GetHeader("X-Request-ID")
-		// 验证请求ID是否为有效的UUID
-		if requestID == "" || !isValidUUID(requestID) {
-			// 如果请求头中没有有效的UUID，则生成新的
-			requestID = uuid.New().String()
-		}
-		c.Set(logger.RequestIDKey, requestID)
-		c.Header("X-Request-ID", requestID)
+		assignRequestID(c)
+
+		redact := newRedactor()
+		logBody := !skipBodyRoutes[c.FullPath()]
 
-		// 记录请求体
+		// 记录请求体，文件上传等二进制内容只记录类型和大小，避免缓存整个请求体
 		var requestBody []byte
-		if c.Request.Body != nil && c.Request.ContentLength > 0 {
-			if c.Request.ContentLength <= MaxBodySize {
+		if logBody && c.Request.Body != nil && c.Request.ContentLength > 0 {
+			if contentType := c.ContentType(); isBinaryContentType(contentType) {
+				requestBody = []byte(fmt.Sprintf("[二进制请求体，类型: %s，大小: %d字节]", contentType, c.Request.ContentLength))
+			} else if c.Request.ContentLength <= MaxBodySize {
 				requestBody, _ = io.ReadAll(c.Request.Body)
 				c.Request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
 			} else {
@@ -48,24 +51,27 @@ func Logger() gin.HandlerFunc {
 			logger.String("client_ip", c.ClientIP()),
 			logger.String("method", c.Request.Method),
 			logger.String("path", c.Request.URL.Path),
-			logger.String("query", c.Request.URL.RawQuery),
+			logger.String("query", redact.query(c.Request.URL.RawQuery)),
 			logger.String("user_agent", c.Request.UserAgent()),
+			logger.Any("headers", redact.header(c.Request.Header)),
 		}
 
 		// 添加请求体（如果存在）
 		if len(requestBody) > 0 {
-			addBodyToFields(requestBody, "request_body", &requestFields)
+			addBodyToFields(requestBody, c.ContentType(), "request_body", redact, &requestFields)
 		}
 
 		// 记录请求信息
-		logger.Info(c, "收到HTTP请求", requestFields...)
+		logger.Access().Info(c, "收到HTTP请求", requestFields...)
 
 		// 记录请求开始时间
 		startTime := time.Now()
 
 		// 创建自定义响应写入器
 		blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
-		c.Writer = blw
+		if logBody {
+			c.Writer = blw
+		}
 
 		// 处理请求
 		c.Next()
@@ -82,52 +88,93 @@ func Logger() gin.HandlerFunc {
 		}
 
 		// 添加响应体（如果存在）
-		if blw.body.Len() > 0 {
-			if blw.body.Len() <= MaxBodySize {
-				addBodyToFields(blw.body.Bytes(), "response_body", &responseFields)
+		if blw.size > 0 {
+			if blw.size <= MaxBodySize {
+				addBodyToFields(blw.body.Bytes(), blw.Header().Get("Content-Type"), "response_body", redact, &responseFields)
 			} else {
 				responseFields = append(responseFields, logger.String("response_body",
-					fmt.Sprintf("[响应体太大，大小: %d字节]", blw.body.Len())))
+					fmt.Sprintf("[响应体太大，大小: %d字节]", blw.size)))
 			}
 		}
 
 		// 根据状态码选择日志级别
 		statusCode := c.Writer.Status()
 		if statusCode >= http.StatusInternalServerError {
-			logger.Error(c, "完成HTTP请求", responseFields...)
+			logger.Access().Error(c, "完成HTTP请求", responseFields...)
 		} else if statusCode >= http.StatusBadRequest {
-			logger.Warn(c, "完成HTTP请求", responseFields...)
+			logger.Access().Warn(c, "完成HTTP请求", responseFields...)
 		} else {
-			logger.Info(c, "完成HTTP请求", responseFields...)
+			logger.Access().Info(c, "完成HTTP请求", responseFields...)
 		}
 	}
 }
 
-// 添加请求/响应体到日志字段
-func addBodyToFields(body []byte, fieldName string, fields *[]zap.Field) {
+// skipBodyRoutes 不记录请求和响应体的路由，键为完整路由路径，只在启动注册路由时写入
+var skipBodyRoutes = make(map[string]bool)
+
+// SkipBodyLog 标记分组下的路由不记录请求和响应体，只记录请求方法、路径和状态码等信息
+// 用于图片上传、数据导出等请求或响应体较大、内容不适合写入日志的路由，需在注册路由时调用
+func SkipBodyLog(group *gin.RouterGroup, relativePaths ...string) {
+	for _, relativePath := range relativePaths {
+		skipBodyRoutes[path.Join(group.BasePath(), relativePath)] = true
+	}
+}
+
+// RequestID 请求ID中间件，用于不记录请求日志的服务（如定时任务服务的管理接口）
+// 使用Logger中间件时无需再添加
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		assignRequestID(c)
+		c.Next()
+	}
+}
+
+// assignRequestID 沿用请求头中有效的请求ID或生成新的请求ID，保存到gin.Context和请求上下文，并通过响应头返回
+func assignRequestID(c *gin.Context) {
+	requestID := c.GetHeader(logger.RequestIDHeader)
+	// 验证请求ID是否为有效的UUID
+	if requestID == "" || !isValidUUID(requestID) {
+		// 如果请求头中没有有效的UUID，则生成新的
+		requestID = logger.NewRequestID()
+	}
+	c.Set(logger.RequestIDKey, requestID)
+	// 同时保存到请求上下文，以c.Request.Context()传递的调用链同样可以获取
+	c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+	c.Header(logger.RequestIDHeader, requestID)
+}
+
+// addBodyToFields 添加请求/响应体到日志字段，JSON和表单格式的内容脱敏后记录
+func addBodyToFields(body []byte, contentType, fieldName string, redact *redactor, fields *[]zap.Field) {
 	if isJSON(body) {
-		var jsonMap map[string]interface{}
-		if err := json.Unmarshal(body, &jsonMap); err == nil {
-			// 敏感字段处理
-			sanitizeJSON(jsonMap)
-			*fields = append(*fields, logger.Any(fieldName, jsonMap))
-		} else {
-			*fields = append(*fields, logger.String(fieldName, string(body)))
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err == nil {
+			*fields = append(*fields, logger.Any(fieldName, redact.json(data)))
+			return
 		}
-	} else {
-		*fields = append(*fields, logger.String(fieldName, string(body)))
 	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			*fields = append(*fields, logger.Any(fieldName, redact.form(values)))
+			return
+		}
+	}
+	*fields = append(*fields, logger.String(fieldName, string(body)))
 }
 
 // bodyLogWriter 是一个自定义的响应写入器，用于捕获响应体
+// 超过MaxBodySize后只记录大小，流式导出等大响应不会全部缓存在内存中
 type bodyLogWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
+	size int // 已写入的响应体大小
 }
 
 // Write 实现ResponseWriter接口的Write方法
 func (w *bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	w.size += len(b)
+	if w.size <= MaxBodySize {
+		w.body.Write(b)
+	}
 	return w.ResponseWriter.Write(b)
 }
 
@@ -136,55 +183,148 @@ func isJSON(data []byte) bool {
 	return json.Valid(data) && (data[0] == '{' || data[0] == '[')
 }
 
+// binaryContentTypePrefixes 不记录内容的请求体类型前缀
+var binaryContentTypePrefixes = []string{
+	"multipart/",
+	"application/octet-stream",
+	"application/zip",
+	"application/gzip",
+	"image/",
+	"video/",
+	"audio/",
+}
+
+// isBinaryContentType 检查请求体类型是否为文件上传等二进制内容
+func isBinaryContentType(contentType string) bool {
+	for _, prefix := range binaryContentTypePrefixes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidUUID 检查字符串是否为有效的UUID
 func isValidUUID(u string) bool {
 	_, err := uuid.Parse(u)
 	return err == nil
 }
 
-// sensitiveFieldMap 敏感字段映射，用于快速查找
-var sensitiveFieldMap = map[string]bool{
-	"password":      true,
-	"token":         true,
-	"secret":        true,
-	"authorization": true,
-	"auth":          true,
-	"key":           true,
-}
-
-// sanitizeJSON 处理JSON中的敏感字段
-func sanitizeJSON(data map[string]interface{}) {
-	for k, v := range data {
-		// 转换为小写进行检查
-		lowerKey := strings.ToLower(k)
-
-		// 检查是否为敏感字段或包含敏感字段
-		isSensitive := false
-		for field := range sensitiveFieldMap {
-			if strings.Contains(lowerKey, field) {
-				isSensitive = true
-				break
+// redactedValue 脱敏后记录的值
+const redactedValue = "[REDACTED]"
+
+// 未配置logger.redaction时使用的脱敏字段和请求头
+var (
+	defaultRedactFields  = []string{"password", "token", "secret", "authorization", "auth", "key"}
+	defaultRedactHeaders = []string{"Authorization", "Cookie", constant.SignSignatureHeader}
+)
+
+// redactor 请求日志脱敏规则
+type redactor struct {
+	fields  []string        // 小写的敏感字段名，字段名包含其中任一项即脱敏
+	headers map[string]bool // 规范化后的敏感请求头
+}
+
+// newRedactor 按当前配置创建脱敏规则，每个请求读取一次配置，配置重新加载后立即生效
+func newRedactor() *redactor {
+	cfg := config.GetLoggerRedactionConfig()
+	fields := cfg.Fields
+	if len(fields) == 0 {
+		fields = defaultRedactFields
+	}
+	headers := cfg.Headers
+	if len(headers) == 0 {
+		headers = defaultRedactHeaders
+	}
+
+	r := &redactor{
+		fields:  make([]string, 0, len(fields)),
+		headers: make(map[string]bool, len(headers)),
+	}
+	for _, field := range fields {
+		if field != "" {
+			r.fields = append(r.fields, strings.ToLower(field))
+		}
+	}
+	for _, header := range headers {
+		r.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	return r
+}
+
+// sensitive 检查字段名是否为敏感字段或包含敏感字段
+func (r *redactor) sensitive(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, field := range r.fields {
+		if strings.Contains(lowerKey, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// json 递归处理JSON中的敏感字段，包括数组中的对象
+func (r *redactor) json(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.sensitive(key) {
+				v[key] = redactedValue
+				continue
 			}
+			v[key] = r.json(value)
 		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.json(item)
+		}
+	}
+	return data
+}
 
-		if isSensitive {
-			data[k] = "[REDACTED]"
-			continue
+// form 处理表单中的敏感字段，只有一个值的字段记录为字符串
+func (r *redactor) form(values url.Values) map[string]interface{} {
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		switch {
+		case r.sensitive(key):
+			data[key] = redactedValue
+		case len(value) == 1:
+			data[key] = value[0]
+		default:
+			data[key] = value
 		}
+	}
+	return data
+}
 
-		// 递归处理嵌套的map
-		if nestedMap, ok := v.(map[string]interface{}); ok {
-			sanitizeJSON(nestedMap)
-			continue
+// query 处理查询字符串中的敏感参数，其余参数保持原样
+func (r *redactor) query(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && r.sensitive(name) {
+			params[i] = key + "=" + redactedValue
 		}
+	}
+	return strings.Join(params, "&")
+}
 
-		// 处理数组中的map
-		if nestedSlice, ok := v.([]interface{}); ok {
-			for _, item := range nestedSlice {
-				if nestedMap, ok := item.(map[string]interface{}); ok {
-					sanitizeJSON(nestedMap)
-				}
-			}
+// header 处理请求头中的敏感请求头，只有一个值的请求头记录为字符串
+func (r *redactor) header(h http.Header) map[string]interface{} {
+	data := make(map[string]interface{}, len(h))
+	for key, value := range h {
+		switch {
+		case r.headers[http.CanonicalHeaderKey(key)]:
+			data[key] = redactedValue
+		case len(value) == 1:
+			data[key] = value[0]
+		default:
+			data[key] = value
 		}
 	}
+	return data
 }
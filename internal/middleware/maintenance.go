@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"app/internal/constant"
+	"app/internal/maintenance"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrMaintenance 系统处于维护模式
+var ErrMaintenance = errors.New("系统维护中")
+
+// maintenanceExemptSuffixes 维护模式下仍可访问的路由后缀
+// 登录令牌过期的管理员需要重新获取验证码并登录，才能关闭维护模式
+var maintenanceExemptSuffixes = []string{
+	"/user/verification-code",
+	"/user/login/code",
+}
+
+// Maintenance 创建维护模式中间件
+// 处于维护模式时，除管理后台接口、调试接口、登录接口和健康检查外的请求均返回503，并通过Retry-After告知客户端重试间隔
+// 需在路由匹配后执行，根据匹配到的路由判断是否为豁免接口，未匹配到路由的请求交由404处理
+func Maintenance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" || maintenanceExempt(path) {
+			c.Next()
+			return
+		}
+
+		state, active := maintenance.Active(c)
+		if !active {
+			c.Next()
+			return
+		}
+
+		message := state.Message
+		if message == "" {
+			message = constant.MaintenanceDefaultMessage
+		}
+		retryAfter := state.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = constant.MaintenanceDefaultRetryAfter
+		}
+
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		response.Fail(c, http.StatusServiceUnavailable, message, ErrMaintenance)
+		c.Abort()
+	}
+}
+
+// maintenanceExempt 判断路由是否不受维护模式影响
+// 管理后台和调试接口由管理员权限保护，登录接口保证管理员可以重新登录
+func maintenanceExempt(path string) bool {
+	if path == "/health" || strings.HasPrefix(path, "/debug/") || strings.Contains(path+"/", "/admin/") {
+		return true
+	}
+	for _, suffix := range maintenanceExemptSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/mute"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrUserMuted 用户已被禁言
+var ErrUserMuted = errors.New("用户已被禁言")
+
+// MuteMiddleware 创建禁言检查中间件
+// 需在AuthMiddleware之后使用，只挂载在发布、评论和点赞等接口上，被禁言的用户仍可正常浏览
+// 被禁言时返回403，并在响应数据中返回禁言结束时间
+func MuteMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		state, muted := mute.Active(c, userID.(uint))
+		if !muted {
+			c.Next()
+			return
+		}
+
+		response.FailWithData(c, http.StatusForbidden, constant.MuteMessage, dto.MutedNotice{ExpiresAt: state.ExpiresAt}, ErrUserMuted)
+		c.Abort()
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"app/internal/cachekey"
+	"app/pkg/errtrack"
+	"app/pkg/logger"
+	"app/pkg/redis"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery 异常恢复中间件
+// 捕获请求处理中的panic，记录带调用栈的错误日志、累加每日panic计数并上报错误追踪服务，
+// 然后以统一的响应格式返回500错误
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// 由net/http约定的中止信号，需要继续向上传递
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			// 客户端断开连接导致的写入失败无法再返回响应，也无需记录调用栈
+			if isBrokenPipe(rec) {
+				logger.Warn(c, "客户端连接已断开", logger.Any("error", rec),
+					logger.String("path", c.Request.URL.Path))
+				c.Abort()
+				return
+			}
+
+			stack := string(debug.Stack())
+			logger.Error(c, "请求处理发生panic",
+				logger.Any("panic", rec),
+				logger.String("method", c.Request.Method),
+				logger.String("path", c.Request.URL.Path),
+				logger.String("stack", stack),
+			)
+
+			recordPanic(c)
+			reportPanic(c, rec, stack)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			response.InternalServerError(c, "服务器内部错误", nil)
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
+
+// recordPanic 累加当日panic次数，计数失败只记录日志
+func recordPanic(c *gin.Context) {
+	key := cachekey.StatsPanics(time.Now())
+	if _, err := redis.Incr(key.String()); err != nil {
+		logger.Warn(c, "记录panic次数失败", logger.Err(err))
+		return
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+}
+
+// reportPanic 在后台上报panic到错误追踪服务，不阻塞响应
+func reportPanic(c *gin.Context, rec interface{}, stack string) {
+	event := &errtrack.Event{
+		Message:   fmt.Sprintf("panic: %v", rec),
+		Stack:     stack,
+		RequestID: c.GetString(logger.RequestIDKey),
+		Method:    c.Request.Method,
+		URL:       c.Request.URL.String(),
+		Tags:      map[string]string{"route": c.FullPath()},
+		Timestamp: time.Now(),
+	}
+	if userID, ok := c.Get("userID"); ok {
+		event.UserID, _ = userID.(uint)
+	}
+
+	// 请求上下文随请求结束而取消，上报使用独立上下文，超时由服务提供商控制
+	go func() {
+		ctx := context.Background()
+		if err := errtrack.Report(ctx, event); err != nil {
+			logger.Warn(ctx, "上报panic到错误追踪服务失败", logger.Err(err))
+		}
+	}()
+}
+
+// isBrokenPipe 判断panic是否由客户端断开连接引起
+func isBrokenPipe(rec interface{}) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+	msg := strings.ToLower(syscallErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
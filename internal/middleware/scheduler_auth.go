@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"app/config"
+	"app/internal/constant"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// schedulerRolePermissions 定时程序各角色拥有的权限
+var schedulerRolePermissions = map[string][]string{
+	constant.SchedulerRoleViewer:   {constant.SchedulerPermissionRead},
+	constant.SchedulerRoleOperator: {constant.SchedulerPermissionRead, constant.SchedulerPermissionRun, constant.SchedulerPermissionDebug},
+}
+
+// SchedulerAuthMiddleware 创建定时程序管理接口认证中间件
+// 优先校验静态访问令牌，未提供时校验JWT令牌并按用户ID分配角色
+// 认证通过后在上下文中设置调用方标识schedulerCaller和角色schedulerRole
+func SchedulerAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authConfig := config.GetSchedulerConfig().Auth
+
+		if token := c.GetHeader(constant.SchedulerTokenHeader); token != "" {
+			for _, t := range authConfig.Tokens {
+				if t.Token != "" && subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+					c.Set("schedulerCaller", "token:"+t.Name)
+					c.Set("schedulerRole", t.Role)
+					c.Next()
+					return
+				}
+			}
+			response.Unauthorized(c, "无效的访问令牌", nil)
+			c.Abort()
+			return
+		}
+
+		claims, failure := authenticate(c)
+		if failure != nil {
+			response.Fail(c, failure.status, failure.message, failure.err)
+			c.Abort()
+			return
+		}
+
+		role := schedulerUserRole(authConfig, claims.UserID)
+		if role == "" {
+			response.Forbidden(c, "权限不足，无权访问任务管理接口", nil)
+			c.Abort()
+			return
+		}
+
+		setIdentity(c, claims)
+		c.Set("schedulerCaller", fmt.Sprintf("user:%d", claims.UserID))
+		c.Set("schedulerRole", role)
+
+		c.Next()
+	}
+}
+
+// SchedulerPermissionMiddleware 创建定时程序接口权限校验中间件
+// 需在SchedulerAuthMiddleware之后使用，校验调用方角色是否拥有指定权限
+func SchedulerPermissionMiddleware(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("schedulerRole")
+		for _, p := range schedulerRolePermissions[role] {
+			if p == permission {
+				c.Next()
+				return
+			}
+		}
+
+		response.Forbidden(c, "权限不足", nil)
+		c.Abort()
+	}
+}
+
+// schedulerUserRole 获取JWT用户在定时程序中的角色，未分配时返回空字符串
+func schedulerUserRole(authConfig config.SchedulerAuthConfig, userID uint) string {
+	for _, id := range authConfig.OperatorUserIDs {
+		if id == userID {
+			return constant.SchedulerRoleOperator
+		}
+	}
+	for _, id := range authConfig.ViewerUserIDs {
+		if id == userID {
+			return constant.SchedulerRoleViewer
+		}
+	}
+	return ""
+}
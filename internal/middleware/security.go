@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"app/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 安全响应头默认值，适用于只返回JSON的API
+const (
+	defaultFrameOptions          = "DENY"
+	defaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+	defaultReferrerPolicy        = "no-referrer"
+)
+
+// SecurityHeaders 安全响应头中间件
+// 为所有响应添加防止MIME嗅探、点击劫持等的响应头，配置了HSTS有效期时同时发送HSTS响应头
+func SecurityHeaders() gin.HandlerFunc {
+	cfg := config.GetSecurityConfig()
+
+	frameOptions := cfg.FrameOptions
+	if frameOptions == "" {
+		frameOptions = defaultFrameOptions
+	}
+	csp := cfg.ContentSecurityPolicy
+	if csp == "" {
+		csp = defaultContentSecurityPolicy
+	}
+	referrerPolicy := cfg.ReferrerPolicy
+	if referrerPolicy == "" {
+		referrerPolicy = defaultReferrerPolicy
+	}
+
+	var hsts string
+	if maxAge, err := time.ParseDuration(cfg.HSTSMaxAge); err == nil && maxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", int(maxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Header("X-Frame-Options", frameOptions)
+		c.Header("Content-Security-Policy", csp)
+		c.Header("Referrer-Policy", referrerPolicy)
+		if hsts != "" {
+			c.Header("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/repository"
+	"app/internal/utils"
+	"app/pkg/logger"
+	"app/pkg/redis"
+	"app/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignatureAuthMiddleware 创建服务端请求签名认证中间件
+// 调用方使用应用密钥对请求计算HMAC-SHA256签名，待签名字符串由以下各项按换行符拼接：
+// 请求方法、请求路径、按参数名排序的查询字符串、时间戳、随机串、请求体SHA-256摘要的十六进制
+// 时间戳超出允许偏差或随机串已使用过的请求将被拒绝，防止请求被重放
+// 认证通过后在上下文中设置调用方ID apiClientID和授权范围apiClientScopes
+func SignatureAuthMiddleware(clientRepo repository.APIClientRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appKey := c.GetHeader(constant.SignAppKeyHeader)
+		timestamp := c.GetHeader(constant.SignTimestampHeader)
+		nonce := c.GetHeader(constant.SignNonceHeader)
+		signature := c.GetHeader(constant.SignSignatureHeader)
+		if appKey == "" || timestamp == "" || signature == "" ||
+			len(nonce) < constant.SignNonceMinLength || len(nonce) > constant.SignNonceMaxLength {
+			response.Unauthorized(c, "缺少签名参数", nil)
+			c.Abort()
+			return
+		}
+
+		// 校验时间戳
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || !withinSkew(time.Unix(ts, 0)) {
+			response.Unauthorized(c, "请求时间戳无效或已过期", nil)
+			c.Abort()
+			return
+		}
+
+		// 查找调用方并解密应用密钥
+		client, err := clientRepo.FindByAppKey(c, appKey)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				response.Unauthorized(c, "无效的应用标识", nil)
+			} else {
+				response.InternalServerError(c, "验证签名时发生错误", err)
+			}
+			c.Abort()
+			return
+		}
+		if client.Status != constant.APIClientStatusEnabled {
+			response.Unauthorized(c, "无效的应用标识", nil)
+			c.Abort()
+			return
+		}
+		secret, err := utils.DecryptAES(client.AppSecret, []byte(config.GetAPISignConfig().SecretKey))
+		if err != nil {
+			response.InternalServerError(c, "验证签名时发生错误", err)
+			c.Abort()
+			return
+		}
+
+		// 读取请求体计算摘要后放回，供后续处理器继续读取
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, "请求参数错误", err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		expected := SignRequest(secret, c.Request.Method, c.Request.URL.Path, c.Request.URL.Query().Encode(), timestamp, nonce, body)
+		if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+			response.Unauthorized(c, "签名验证失败", nil)
+			c.Abort()
+			return
+		}
+
+		// 签名通过后再占用随机串，避免伪造请求耗尽调用方的随机串
+		// 随机串保留时间覆盖时间戳前后的全部允许偏差，过期后的重放请求会被时间戳校验拦截
+		ok, err := redis.SetNX(cachekey.APISignNonce(appKey, nonce).String(), 1, 2*timestampSkew())
+		if err != nil {
+			response.InternalServerError(c, "验证签名时发生错误", err)
+			c.Abort()
+			return
+		}
+		if !ok {
+			response.Unauthorized(c, "重复的请求", nil)
+			c.Abort()
+			return
+		}
+
+		if err := clientRepo.UpdateLastUsedAt(c, client.ID, time.Now()); err != nil {
+			logger.Warn(c, "更新调用方最近调用时间失败", logger.Err(err))
+		}
+
+		c.Set("apiClientID", client.ID)
+		c.Set("apiClientScopes", strings.Split(client.Scopes, ","))
+
+		c.Next()
+	}
+}
+
+// APIScopeMiddleware 创建服务端调用方授权范围校验中间件
+// 需在SignatureAuthMiddleware之后使用，校验调用方是否拥有指定授权范围
+func APIScopeMiddleware(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, _ := c.Get("apiClientScopes")
+		list, _ := scopes.([]string)
+		for _, s := range list {
+			if strings.TrimSpace(s) == scope {
+				c.Next()
+				return
+			}
+		}
+
+		response.Forbidden(c, "权限不足，调用方未被授权访问该接口", nil)
+		c.Abort()
+	}
+}
+
+// SignRequest 计算请求签名，返回HMAC-SHA256结果的小写十六进制
+// query为按参数名排序并URL编码后的查询字符串，与url.Values.Encode的结果一致
+func SignRequest(secret []byte, method, path, query, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	payload := strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		query,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// timestampSkew 获取允许的客户端与服务器时间偏差
+func timestampSkew() time.Duration {
+	if d, err := time.ParseDuration(config.GetAPISignConfig().TimestampSkew); err == nil && d > 0 {
+		return d
+	}
+	return constant.SignDefaultTimestampSkew
+}
+
+// withinSkew 判断请求时间与服务器时间的偏差是否在允许范围内
+func withinSkew(t time.Time) bool {
+	diff := time.Since(t)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= timestampSkew()
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// AccountDeletion 账号注销数据清理任务模型
+// 记录注销账号后台清理流程的当前阶段和进度，支持中断后从当前阶段继续执行
+type AccountDeletion struct {
+	ID                 uint       `gorm:"primaryKey;comment:清理任务ID，主键" json:"id"`
+	UserID             uint       `gorm:"uniqueIndex;comment:注销的用户ID" json:"user_id"`
+	Mobile             string     `gorm:"size:20;comment:注销用户的手机号，用于清理短信记录，清理完成后清空" json:"-"`
+	Stage              string     `gorm:"size:30;index;comment:当前清理阶段" json:"stage"`
+	RetentionUntil     time.Time  `gorm:"type:datetime;comment:数据保留截止时间" json:"retention_until"`
+	CommentsAnonymized int64      `gorm:"default:0;comment:已匿名化评论数" json:"comments_anonymized"`
+	RelationsRemoved   int64      `gorm:"default:0;comment:已删除关系数" json:"relations_removed"`
+	PostsDeleted       int64      `gorm:"default:0;comment:已删除动态数" json:"posts_deleted"`
+	ImagesDeleted      int64      `gorm:"default:0;comment:已删除图片数" json:"images_deleted"`
+	SMSScrubbed        int64      `gorm:"default:0;comment:已清理短信记录数" json:"sms_scrubbed"`
+	Attempts           int        `gorm:"default:0;comment:失败重试次数" json:"attempts"`
+	ErrorMessage       string     `gorm:"size:500;comment:最近一次错误信息" json:"error_message"`
+	CompletedAt        *time.Time `gorm:"type:datetime;comment:完成时间" json:"completed_at"`
+	CreatedAt          time.Time  `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Announcement 系统公告模型
+// 由管理员发布，按目标范围向全部用户、指定用户分群或拥有指定标签的用户展示，用户阅读后记录已读回执
+type Announcement struct {
+	ID        uint           `gorm:"primaryKey;comment:公告ID，主键" json:"id"`
+	Title     string         `gorm:"size:100;comment:公告标题" json:"title"`
+	Content   string         `gorm:"type:text;comment:公告正文" json:"content"`
+	Target    string         `gorm:"size:20;comment:目标范围：all-全部用户，segment-用户分群，tag-用户标签" json:"target"`
+	Segment   string         `gorm:"size:50;comment:目标范围为segment时为用户分群，为tag时为标签名称" json:"segment"`
+	Push      bool           `gorm:"default:false;comment:发布时是否通过推送渠道通知目标用户" json:"push"`
+	CreatedBy uint           `gorm:"comment:发布公告的管理员用户ID" json:"created_by"`
+	PublishAt time.Time      `gorm:"type:datetime;index;comment:发布时间，之前不向用户展示" json:"publish_at"`
+	ExpireAt  *time.Time     `gorm:"type:datetime;comment:过期时间，之后不再向用户展示，为空时不过期" json:"expire_at"`
+	CreatedAt time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+}
+
+// AnnouncementRead 公告已读回执模型
+// 每个用户对每条公告只记录一次
+type AnnouncementRead struct {
+	ID             uint      `gorm:"primaryKey;comment:回执ID，主键" json:"id"`
+	AnnouncementID uint      `gorm:"uniqueIndex:idx_announcement_read_announcement_user;comment:公告ID" json:"announcement_id"`
+	UserID         uint      `gorm:"uniqueIndex:idx_announcement_read_announcement_user;index;comment:阅读公告的用户ID" json:"user_id"`
+	CreatedAt      time.Time `gorm:"type:datetime;comment:阅读时间" json:"created_at"`
+}
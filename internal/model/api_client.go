@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// APIClient 服务端调用方模型
+// 内部服务和合作方使用应用标识和密钥对请求签名，无需用户JWT即可调用已授权的接口
+type APIClient struct {
+	ID         uint       `gorm:"primaryKey;comment:调用方ID，主键" json:"id"`
+	Name       string     `gorm:"size:50;comment:调用方名称" json:"name"`
+	AppKey     string     `gorm:"size:32;uniqueIndex;comment:应用标识，随请求明文传递" json:"app_key"`
+	AppSecret  string     `gorm:"size:255;comment:应用密钥，使用配置的加密密钥AES加密后存储" json:"-"`
+	Scopes     string     `gorm:"size:500;comment:授权范围，多个以逗号分隔" json:"scopes"`
+	Status     int        `gorm:"type:smallint;default:1;comment:状态：1-启用，0-禁用" json:"status"`
+	LastUsedAt *time.Time `gorm:"type:datetime;comment:最近一次调用时间" json:"last_used_at"`
+	CreatedAt  time.Time  `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
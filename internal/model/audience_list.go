@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AudienceList 好友列表模型
+// 用户自定义的好友列表（如密友），动态可以设置为仅列表成员可见
+type AudienceList struct {
+	ID        uint           `gorm:"primaryKey;comment:好友列表ID，主键" json:"id"`
+	UserID    uint           `gorm:"index;comment:列表所有者用户ID" json:"user_id"`
+	Name      string         `gorm:"size:30;comment:列表名称" json:"name"`
+	CreatedAt time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+}
+
+// AudienceListMember 好友列表成员模型
+// 成员只能是列表所有者已确认的好友，删除列表时一并删除成员记录
+type AudienceListMember struct {
+	ID        uint      `gorm:"primaryKey;comment:成员记录ID，主键" json:"id"`
+	ListID    uint      `gorm:"uniqueIndex:idx_audience_list_member_list_member;comment:好友列表ID" json:"list_id"`
+	MemberID  uint      `gorm:"uniqueIndex:idx_audience_list_member_list_member;index;comment:成员用户ID" json:"member_id"`
+	CreatedAt time.Time `gorm:"type:datetime;comment:加入时间" json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// CommentLike 评论点赞模型
+// 记录用户对评论的点赞，同一用户对同一评论只能点赞一次
+type CommentLike struct {
+	ID        uint      `gorm:"primaryKey;comment:点赞ID，主键" json:"id"`
+	CommentID uint      `gorm:"uniqueIndex:idx_comment_like_comment_user;comment:评论ID" json:"comment_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_comment_like_comment_user;index;comment:点赞用户ID" json:"user_id"`
+	CreatedAt time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+}
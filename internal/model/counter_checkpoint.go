@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// CounterCheckpoint 计数同步进度模型
+// 记录已同步到数据库的Redis计数日志位置，与计数更新在同一事务中保存，重放日志时据此跳过已同步的增量
+type CounterCheckpoint struct {
+	ID        uint      `gorm:"primaryKey;comment:记录ID，主键" json:"id"`
+	Name      string    `gorm:"size:50;uniqueIndex;comment:计数日志名称" json:"name"`
+	Position  string    `gorm:"size:50;comment:已同步的最后一条日志ID" json:"position"`
+	UpdatedAt time.Time `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// DailyStatistics 每日统计快照模型
+// 存储按天汇总的运营数据，由数据统计定时任务生成
+type DailyStatistics struct {
+	ID           uint      `gorm:"primaryKey;comment:统计ID，主键" json:"id"`
+	Date         time.Time `gorm:"type:date;uniqueIndex;comment:统计日期" json:"date"`
+	ActiveUsers  int64     `gorm:"default:0;comment:日活跃用户数" json:"active_users"`
+	NewUsers     int64     `gorm:"default:0;comment:新注册用户数" json:"new_users"`
+	TotalUsers   int64     `gorm:"default:0;comment:累计用户数" json:"total_users"`
+	NewPosts     int64     `gorm:"default:0;comment:新增动态数" json:"new_posts"`
+	NewComments  int64     `gorm:"default:0;comment:新增评论数" json:"new_comments"`
+	SMSCount     int64     `gorm:"default:0;comment:短信发送成功数" json:"sms_count"`
+	SMSCost      float64   `gorm:"type:decimal(12,3);default:0;comment:短信费用（元）" json:"sms_cost"`
+	StorageBytes int64     `gorm:"default:0;comment:图片存储占用(字节)" json:"storage_bytes"`
+	CreatedAt    time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataExport 用户数据导出任务模型
+// 记录用户发起的个人数据导出请求及其处理状态
+type DataExport struct {
+	ID           uint           `gorm:"primaryKey;comment:导出任务ID，主键" json:"id"`
+	UserID       uint           `gorm:"index;comment:用户ID" json:"user_id"`
+	Status       string         `gorm:"size:20;index;comment:任务状态：pending-等待处理，processing-处理中，completed-已完成，failed-失败" json:"status"`
+	Bucket       string         `gorm:"size:100;comment:存储桶名称" json:"bucket"`
+	ObjectKey    string         `gorm:"size:255;comment:导出文件在对象存储中的键名" json:"object_key"`
+	FileSize     int64          `gorm:"comment:导出文件大小(字节)" json:"file_size"`
+	ErrorMessage string         `gorm:"size:500;comment:错误信息" json:"error_message"`
+	CompletedAt  *time.Time     `gorm:"type:datetime;comment:完成时间" json:"completed_at"`
+	CreatedAt    time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+}
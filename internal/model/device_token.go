@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// DeviceToken 设备推送令牌模型
+// 客户端登录后上报，同一令牌只属于最近上报的用户；推送服务返回令牌失效时删除
+type DeviceToken struct {
+	ID        uint      `gorm:"primaryKey;comment:令牌ID，主键" json:"id"`
+	UserID    uint      `gorm:"index;comment:用户ID" json:"user_id"`
+	Platform  string    `gorm:"size:20;comment:设备平台：ios、android" json:"platform"`
+	Token     string    `gorm:"size:255;uniqueIndex;comment:设备推送令牌" json:"token"`
+	CreatedAt time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:datetime;comment:最近上报时间" json:"updated_at"`
+}
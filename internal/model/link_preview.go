@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// LinkPreview 链接预览模型
+// 按链接存储抓取到的OpenGraph元数据，包含相同链接的动态共用同一条记录
+type LinkPreview struct {
+	ID          uint       `gorm:"primaryKey;comment:链接预览ID，主键" json:"id"`
+	URLHash     string     `gorm:"size:64;uniqueIndex;comment:链接的SHA-256摘要（十六进制）" json:"-"`
+	URL         string     `gorm:"size:2048;comment:动态中的原始链接" json:"url"`
+	Status      int        `gorm:"type:smallint;default:0;comment:抓取状态：0-待抓取，1-成功，2-失败" json:"status"`
+	Title       string     `gorm:"size:255;comment:网页标题" json:"title"`
+	Description string     `gorm:"size:500;comment:网页描述" json:"description"`
+	ImageURL    string     `gorm:"size:2048;comment:预览图片地址" json:"image_url"`
+	SiteName    string     `gorm:"size:255;comment:网站名称" json:"site_name"`
+	FetchedAt   *time.Time `gorm:"type:datetime;comment:最近一次抓取完成时间，待抓取时为空" json:"fetched_at"`
+	CreatedAt   time.Time  `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
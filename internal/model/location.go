@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// Location 位置模型
+// 存储动态附带的经纬度及逆地理编码解析出的地址
+type Location struct {
+	ID        uint      `gorm:"primaryKey;comment:位置ID，主键" json:"id"`
+	Latitude  float64   `gorm:"comment:纬度（GCJ-02坐标系）" json:"latitude"`
+	Longitude float64   `gorm:"comment:经度（GCJ-02坐标系）" json:"longitude"`
+	Geohash   string    `gorm:"size:12;index;comment:经纬度的geohash编码，用于按前缀查询范围内的位置和地图聚合" json:"geohash"`
+	Address   string    `gorm:"size:255;comment:逆地理编码解析出的地址，解析完成前为空" json:"address"`
+	CreatedAt time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
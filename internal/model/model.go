@@ -0,0 +1,42 @@
+package model
+
+// All 返回所有需要迁移的数据库模型
+// 新增模型时需要在此处注册，迁移命令和测试数据库均使用该列表
+func All() []interface{} {
+	return []interface{}{
+		&User{},
+		&SMSRecord{},
+		&UserFollower{},
+		&UserFriend{},
+		&AudienceList{},
+		&AudienceListMember{},
+		&PostExclusion{},
+		&Post{},
+		&PostComment{},
+		&CommentLike{},
+		&PostImage{},
+		&CommentImage{},
+		&PostRevision{},
+		&TempImage{},
+		&DailyStatistics{},
+		&PostStats{},
+		&DataExport{},
+		&AccountDeletion{},
+		&Location{},
+		&LinkPreview{},
+		&APIClient{},
+		&WebhookSubscription{},
+		&WebhookDelivery{},
+		&NotificationSetting{},
+		&NotificationPreference{},
+		&DeviceToken{},
+		&ProfileVisit{},
+		&UsernameHistory{},
+		&UserActivity{},
+		&Announcement{},
+		&AnnouncementRead{},
+		&Tag{},
+		&UserTag{},
+		&CounterCheckpoint{},
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// NotificationSetting 用户通知设置模型
+// 每个用户一条，注册时写入默认值，记录免打扰时段
+type NotificationSetting struct {
+	ID                uint      `gorm:"primaryKey;comment:设置ID，主键" json:"id"`
+	UserID            uint      `gorm:"uniqueIndex;comment:用户ID" json:"user_id"`
+	QuietHoursEnabled bool      `gorm:"default:false;comment:是否开启免打扰" json:"quiet_hours_enabled"`
+	QuietStart        string    `gorm:"size:5;comment:免打扰开始时间，格式HH:MM" json:"quiet_start"`
+	QuietEnd          string    `gorm:"size:5;comment:免打扰结束时间，格式HH:MM，早于开始时间表示跨天" json:"quiet_end"`
+	Timezone          string    `gorm:"size:64;comment:免打扰时段所在时区，IANA时区名" json:"timezone"`
+	CreatedAt         time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
+
+// NotificationPreference 用户通知偏好模型
+// 每个用户每种事件一条，记录各通知渠道是否开启
+type NotificationPreference struct {
+	ID        uint      `gorm:"primaryKey;comment:偏好ID，主键" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_notification_preference_user_event;comment:用户ID" json:"user_id"`
+	Event     string    `gorm:"size:50;uniqueIndex:idx_notification_preference_user_event;comment:事件类型" json:"event"`
+	Push      bool      `gorm:"default:false;comment:是否开启推送通知" json:"push"`
+	SMS       bool      `gorm:"default:false;comment:是否开启短信通知" json:"sms"`
+	Email     bool      `gorm:"default:false;comment:是否开启邮件通知" json:"email"`
+	CreatedAt time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
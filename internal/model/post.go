@@ -9,14 +9,21 @@ import (
 // Post 动态模型
 // 存储用户发布的动态内容
 type Post struct {
-	ID         uint           `gorm:"primaryKey;comment:动态ID，主键" json:"id"`
-	UserID     uint           `gorm:"comment:用户ID" json:"user_id"`
-	Content    string         `gorm:"size:2000;comment:动态内容" json:"content"`
-	Visibility int            `gorm:"type:smallint;default:1;comment:可见性：1-公开，2-仅好友，3-私密" json:"visibility"`
-	PostImages []PostImage    `gorm:"foreignKey:PostID" json:"-"` // 关联的图片列表
-	Likes      int            `gorm:"default:0;comment:点赞数" json:"likes"`
-	Comments   int            `gorm:"default:0;comment:评论数" json:"comments"`
-	CreatedAt  time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
-	UpdatedAt  time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+	ID             uint           `gorm:"primaryKey;comment:动态ID，主键" json:"id"`
+	PublicID       string         `gorm:"size:26;uniqueIndex;comment:对外公开的ID（ULID），接口中只返回该ID，创建时自动生成" json:"public_id"`
+	UserID         uint           `gorm:"comment:用户ID" json:"user_id"`
+	Content        string         `gorm:"size:2000;comment:动态内容" json:"content"`
+	Language       string         `gorm:"size:8;comment:内容语言（ISO 639-1代码），发布和编辑时识别，无法识别时为空" json:"language"`
+	Visibility     int            `gorm:"type:smallint;default:1;comment:可见性：1-公开，2-仅好友，3-私密，4-仅指定好友列表" json:"visibility"`
+	AudienceListID *uint          `gorm:"index;comment:可见的好友列表ID，仅可见性为4时有效" json:"audience_list_id"`
+	PostImages     []PostImage    `gorm:"foreignKey:PostID" json:"-"` // 关联的图片列表
+	LocationID     *uint          `gorm:"comment:位置ID，未附带位置时为空" json:"location_id"`
+	LinkPreviewID  *uint          `gorm:"comment:内容中第一个链接的预览ID，不含链接时为空" json:"link_preview_id"`
+	Likes          int            `gorm:"default:0;comment:点赞数" json:"likes"`
+	Comments       int            `gorm:"default:0;comment:评论数" json:"comments"`
+	Views          int64          `gorm:"default:0;comment:浏览数（按天去重后累计）" json:"views"`
+	EditedAt       *time.Time     `gorm:"type:datetime;comment:最后编辑时间，未编辑过时为空" json:"edited_at"`
+	CreatedAt      time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
 }
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// PostExclusion 动态屏蔽模型
+// 用户设置不让指定的人看自己的动态，被屏蔽的用户在动态列表、发现页和主页中都看不到该用户的任何动态
+type PostExclusion struct {
+	ID        uint      `gorm:"primaryKey;comment:屏蔽记录ID，主键" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_post_exclusion_user_target;comment:设置屏蔽的用户ID，即动态作者" json:"user_id"`
+	TargetID  uint      `gorm:"uniqueIndex:idx_post_exclusion_user_target;index;comment:被屏蔽的用户ID" json:"target_id"`
+	CreatedAt time.Time `gorm:"type:datetime;comment:屏蔽时间" json:"created_at"`
+}
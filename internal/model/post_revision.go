@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// PostRevision 动态修订记录模型
+// 每次编辑动态时记录一条，保存编辑前后的内容和图片变化，随动态一起永久清除
+type PostRevision struct {
+	ID              uint      `gorm:"primaryKey;comment:修订记录ID，主键" json:"id"`
+	PostID          uint      `gorm:"index;comment:动态ID" json:"post_id"`
+	EditorID        uint      `gorm:"comment:编辑者用户ID" json:"editor_id"`
+	ContentBefore   string    `gorm:"size:2000;comment:编辑前的内容" json:"content_before"`
+	ContentAfter    string    `gorm:"size:2000;comment:编辑后的内容" json:"content_after"`
+	AddedImageIDs   string    `gorm:"size:255;comment:新增的动态图片ID，逗号分隔" json:"added_image_ids"`
+	RemovedImageIDs string    `gorm:"size:255;comment:移除的动态图片ID，逗号分隔" json:"removed_image_ids"`
+	CreatedAt       time.Time `gorm:"type:datetime;comment:编辑时间" json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PostStats 动态每日数据模型
+// 按天汇总每条动态的浏览、点赞、评论、分享和带来的关注数，由动态数据汇总定时任务生成，供作者查看数据分析
+type PostStats struct {
+	ID            uint      `gorm:"primaryKey;comment:记录ID，主键" json:"id"`
+	PostID        uint      `gorm:"uniqueIndex:idx_post_stats_post_date;comment:动态ID" json:"post_id"`
+	UserID        uint      `gorm:"index:idx_post_stats_user_date;comment:动态作者ID" json:"user_id"`
+	Date          time.Time `gorm:"type:date;uniqueIndex:idx_post_stats_post_date;index:idx_post_stats_user_date;comment:统计日期" json:"date"`
+	Views         int64     `gorm:"default:0;comment:去重浏览数" json:"views"`
+	Likes         int64     `gorm:"default:0;comment:点赞数" json:"likes"`
+	Comments      int64     `gorm:"default:0;comment:评论数" json:"comments"`
+	Shares        int64     `gorm:"default:0;comment:分享数" json:"shares"`
+	FollowerGains int64     `gorm:"default:0;comment:通过动态关注作者的用户数" json:"follower_gains"`
+	CreatedAt     time.Time `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
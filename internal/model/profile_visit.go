@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// ProfileVisit 主页访问记录模型
+// 记录用户访问他人主页的足迹，同一访客一小时内重复访问只记录一次
+type ProfileVisit struct {
+	ID        uint      `gorm:"primaryKey;comment:访问记录ID，主键" json:"id"`
+	VisitorID uint      `gorm:"index:idx_profile_visit_visitor_created;comment:访客用户ID" json:"visitor_id"`
+	VisiteeID uint      `gorm:"index:idx_profile_visit_visitee_created;comment:被访问的用户ID" json:"visitee_id"`
+	CreatedAt time.Time `gorm:"type:datetime;index:idx_profile_visit_visitor_created;index:idx_profile_visit_visitee_created;index;comment:访问时间" json:"created_at"`
+}
@@ -19,7 +19,8 @@ type SMSRecord struct {
 	Content       string           `gorm:"size:1000;comment:短信内容" json:"content"`
 	TemplateCode  string           `gorm:"size:100;comment:短信模板代码" json:"template_code"`
 	TemplateParam string           `gorm:"size:1000;comment:短信模板参数，JSON格式" json:"template_param"`
-	Status        string           `gorm:"size:20;comment:发送状态：success-成功，failed-失败" json:"status"`
+	Status        string           `gorm:"size:20;comment:发送状态：pending-等待发送，success-成功，failed-失败" json:"status"`
+	Attempts      int              `gorm:"default:0;comment:尝试发送次数" json:"attempts"`
 	ErrorMessage  string           `gorm:"size:500;comment:错误信息" json:"error_message"`
 	RequestId     string           `gorm:"size:100;comment:请求ID" json:"request_id"`
 	BizId         string           `gorm:"size:100;comment:发送回执ID" json:"biz_id"`
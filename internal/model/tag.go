@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Tag 用户标签模型
+// 由管理员创建，用于运营分群；手动标签由管理员维护成员，规则标签由定时任务按规则计算成员
+type Tag struct {
+	ID          uint       `gorm:"primaryKey;comment:标签ID，主键" json:"id"`
+	Name        string     `gorm:"size:50;uniqueIndex;comment:标签名称，功能开关和公告通过名称引用标签" json:"name"`
+	Description string     `gorm:"size:200;comment:标签说明" json:"description"`
+	Type        string     `gorm:"size:20;comment:标签类型：manual-手动，rule-规则" json:"type"`
+	Rule        string     `gorm:"size:30;comment:规则类型，仅规则标签有效" json:"rule"`
+	Threshold   int        `gorm:"default:0;comment:规则阈值，仅规则标签有效" json:"threshold"`
+	MemberCount int64      `gorm:"default:0;comment:成员数量" json:"member_count"`
+	RefreshedAt *time.Time `gorm:"type:datetime;comment:规则标签最近一次计算成员的时间" json:"refreshed_at"`
+	CreatedAt   time.Time  `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
+
+// UserTag 用户标签成员模型
+// 删除标签时一并删除成员记录
+type UserTag struct {
+	ID          uint      `gorm:"primaryKey;comment:成员记录ID，主键" json:"id"`
+	TagID       uint      `gorm:"uniqueIndex:idx_user_tag_tag_user;comment:标签ID" json:"tag_id"`
+	UserID      uint      `gorm:"uniqueIndex:idx_user_tag_tag_user;index;comment:用户ID" json:"user_id"`
+	RefreshedAt time.Time `gorm:"type:datetime;comment:规则标签最近一次匹配的时间，早于本次计算的成员会被移除" json:"-"`
+	CreatedAt   time.Time `gorm:"type:datetime;comment:加入时间" json:"created_at"`
+}
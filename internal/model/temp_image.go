@@ -9,14 +9,19 @@ import (
 // TempImage 临时图片模型
 // 存储用户上传的临时图片信息
 type TempImage struct {
-	ID          uint           `gorm:"primaryKey;comment:图片ID，主键" json:"id"`
-	UserID      uint           `gorm:"index;comment:用户ID" json:"user_id"`
-	ObjectKey   string         `gorm:"size:255;comment:对象存储中的键名" json:"object_key"`
-	URL         string         `gorm:"size:500;comment:图片访问URL" json:"url"`
-	Bucket      string         `gorm:"size:100;comment:存储桶名称" json:"bucket"`
-	Size        int64          `gorm:"comment:图片大小(字节)" json:"size"`
-	ContentType string         `gorm:"size:50;comment:内容类型" json:"content_type"`
-	CreatedAt   time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+	ID           uint           `gorm:"primaryKey;comment:图片ID，主键" json:"id"`
+	UserID       uint           `gorm:"index;comment:用户ID" json:"user_id"`
+	ObjectKey    string         `gorm:"size:255;comment:对象存储中的键名" json:"object_key"`
+	URL          string         `gorm:"size:500;comment:图片访问URL" json:"url"`
+	URLPresigned bool           `gorm:"default:false;comment:访问URL是否为带有效期的预签名URL，私有桶的图片使用预签名URL" json:"-"`
+	URLExpiresAt *time.Time     `gorm:"type:datetime;index;comment:预签名URL的过期时间，永久URL为空" json:"-"`
+	Bucket       string         `gorm:"size:100;comment:存储桶名称" json:"bucket"`
+	Size         int64          `gorm:"comment:图片大小(字节)" json:"size"`
+	Width        int            `gorm:"comment:图片宽度" json:"width"`
+	Height       int            `gorm:"comment:图片高度" json:"height"`
+	ContentType  string         `gorm:"size:50;comment:内容类型" json:"content_type"`
+	ContentHash  string         `gorm:"size:64;index;comment:文件内容SHA-256摘要" json:"content_hash"`
+	CreatedAt    time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
 }
@@ -9,14 +9,26 @@ import (
 // User 用户模型
 // 存储系统用户的基本信息，包含用户的基础资料和账号状态
 type User struct {
-	ID        uint           `gorm:"primaryKey;comment:用户ID，主键" json:"id"`
-	Username  string         `gorm:"size:50;comment:用户名，登录账号" json:"username"`
-	Password  string         `gorm:"size:100;comment:密码，加密存储" json:"-"`
-	Mobile    string         `gorm:"size:20;comment:手机号，用于验证码登录" json:"mobile"`
-	Nickname  string         `gorm:"size:50;comment:用户昵称，显示名称" json:"nickname"`
-	Avatar    string         `gorm:"size:255;comment:用户头像URL" json:"avatar"`
-	Status    int            `gorm:"type:smallint;default:1;comment:用户状态：1-正常，0-禁用" json:"status"`
-	CreatedAt time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+	ID                uint           `gorm:"primaryKey;comment:用户ID，主键" json:"id"`
+	PublicID          string         `gorm:"size:26;uniqueIndex;comment:对外公开的ID（ULID），接口中只返回该ID，创建时自动生成" json:"public_id"`
+	Username          string         `gorm:"size:50;index;comment:用户名，登录账号" json:"username"`
+	Password          string         `gorm:"size:100;comment:密码，加密存储" json:"-"`
+	Mobile            string         `gorm:"size:20;index;comment:手机号，用于验证码登录" json:"mobile"`
+	Nickname          string         `gorm:"size:50;index;comment:用户昵称，显示名称" json:"nickname"`
+	Avatar            string         `gorm:"size:255;comment:用户头像URL" json:"avatar"`
+	AvatarHash        string         `gorm:"size:64;comment:头像内容SHA-256摘要，用于CDN缓存刷新" json:"-"`
+	Status            int            `gorm:"type:smallint;default:1;comment:用户状态：1-正常，0-禁用，2-休眠，3-注销冷静期" json:"status"`
+	IsPrivate         bool           `gorm:"default:false;comment:是否为私密账号，关注需经本人同意" json:"is_private"`
+	MobileHash        string         `gorm:"size:64;index;comment:手机号SHA-256摘要，用于通讯录匹配" json:"-"`
+	AllowMobileSearch bool           `gorm:"default:true;comment:是否允许他人通过手机号搜索或通讯录匹配到自己" json:"allow_mobile_search"`
+	RecordVisits      bool           `gorm:"default:true;comment:是否记录主页访问足迹，关闭后访问他人主页不留记录，也不能查看自己的访客" json:"record_visits"`
+	DefaultVisibility int            `gorm:"type:smallint;default:1;comment:发布动态时未指定可见性使用的默认值：1-公开，2-仅好友，3-私密" json:"default_visibility"`
+	LastActiveAt      *time.Time     `gorm:"type:datetime;index;comment:最近活跃时间" json:"last_active_at"`
+	DeactivateAt      *time.Time     `gorm:"type:datetime;index;comment:注销生效时间，注销冷静期结束后删除账号，撤销注销时清空" json:"-"`
+	UsernameChangedAt *time.Time     `gorm:"type:datetime;comment:最近一次修改用户名的时间，用于限制修改频率" json:"-"`
+	RelationVersion   uint64         `gorm:"default:0;comment:关注关系版本号，关注或粉丝变化时递增，用于计算ETag" json:"-"`
+	TokenVersion      uint           `gorm:"default:0;comment:令牌版本号，退出所有设备时递增，签发时写入令牌，低于该版本的令牌失效" json:"-"`
+	CreatedAt         time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
 }
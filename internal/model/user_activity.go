@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// UserActivity 用户活动记录模型
+// 记录用户本人的操作，如发布动态、发表评论、点赞和关注，由平台事件写入，用于展示"我的动态"时间线
+type UserActivity struct {
+	ID        uint      `gorm:"primaryKey;comment:活动记录ID，主键" json:"id"`
+	UserID    uint      `gorm:"index:idx_user_activity_user_created;comment:操作的用户ID" json:"user_id"`
+	Type      string    `gorm:"size:20;comment:活动类型：post-发布动态，comment-发表评论，post_like-点赞动态，comment_like-点赞评论，follow-关注用户" json:"type"`
+	TargetID  uint      `gorm:"comment:活动对象ID，按类型为动态、评论或被关注的用户ID" json:"target_id"`
+	PostID    uint      `gorm:"default:0;comment:活动关联的动态ID，关注时为0" json:"post_id"`
+	Summary   string    `gorm:"size:500;comment:发布的动态或评论的内容摘要" json:"summary"`
+	CreatedAt time.Time `gorm:"type:datetime;index:idx_user_activity_user_created;comment:活动时间" json:"created_at"`
+}
@@ -15,6 +15,9 @@ type UserFriend struct {
 	TargetID  uint           `gorm:"comment:目标用户ID，好友对象" json:"target_id"`
 	Status    int            `gorm:"type:smallint;default:0;comment:好友状态：0-待确认，1-已确认" json:"status"`
 	Direction int            `gorm:"type:smallint;default:0;comment:关系方向：0-发起方，1-接收方" json:"direction"`
+	Message   string         `gorm:"size:200;comment:好友请求附言" json:"message"`
+	Remark    string         `gorm:"size:50;comment:好友备注名，仅记录所有者可见" json:"remark"`
+	GroupName string         `gorm:"size:30;index;comment:好友分组名称，仅记录所有者可见" json:"group_name"`
 	CreatedAt time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
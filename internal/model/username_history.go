@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// UsernameHistory 用户名变更记录模型
+// 记录用户修改前的用户名，保护期内其他用户不能使用该用户名，访问旧用户名时跳转到当前用户
+type UsernameHistory struct {
+	ID             uint      `gorm:"primaryKey;comment:记录ID，主键" json:"id"`
+	UserID         uint      `gorm:"index;comment:用户ID" json:"user_id"`
+	Username       string    `gorm:"size:50;index:idx_username_history_username_protected;comment:修改前的用户名" json:"username"`
+	ProtectedUntil time.Time `gorm:"type:datetime;index:idx_username_history_username_protected;comment:保护期结束时间，此前其他用户不能使用该用户名" json:"protected_until"`
+	CreatedAt      time.Time `gorm:"type:datetime;comment:修改时间" json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookSubscription Webhook订阅模型
+// 由管理员为服务端调用方创建，订阅的事件发生时向指定地址投递签名后的事件
+type WebhookSubscription struct {
+	ID        uint           `gorm:"primaryKey;comment:订阅ID，主键" json:"id"`
+	ClientID  uint           `gorm:"index;comment:服务端调用方ID" json:"client_id"`
+	URL       string         `gorm:"size:500;comment:接收事件的地址" json:"url"`
+	Events    string         `gorm:"size:255;comment:订阅的事件类型，多个以逗号分隔" json:"events"`
+	Secret    string         `gorm:"size:255;comment:签名密钥，使用配置的加密密钥AES加密后存储" json:"-"`
+	Status    int            `gorm:"type:smallint;default:1;comment:状态：1-启用，0-禁用" json:"status"`
+	CreatedAt time.Time      `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"type:datetime;comment:删除时间" json:"-"`
+}
+
+// WebhookDelivery Webhook投递记录模型
+// 每个事件对每个匹配的订阅生成一条，记录投递结果并用于失败重试
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey;comment:投递记录ID，主键" json:"id"`
+	SubscriptionID uint       `gorm:"index;comment:订阅ID" json:"subscription_id"`
+	ClientID       uint       `gorm:"index;comment:服务端调用方ID" json:"client_id"`
+	EventID        string     `gorm:"size:32;index;comment:事件ID，同一事件投递到多个订阅时相同" json:"event_id"`
+	Event          string     `gorm:"size:50;comment:事件类型" json:"event"`
+	Payload        string     `gorm:"type:text;comment:投递的请求体" json:"payload"`
+	Status         string     `gorm:"size:20;index:idx_webhook_delivery_status_next;comment:投递状态：pending-待投递，success-成功，failed-失败" json:"status"`
+	Attempts       int        `gorm:"default:0;comment:已投递次数" json:"attempts"`
+	NextAttemptAt  *time.Time `gorm:"type:datetime;index:idx_webhook_delivery_status_next;comment:下次投递时间" json:"next_attempt_at"`
+	ResponseCode   int        `gorm:"default:0;comment:最近一次投递的响应状态码" json:"response_code"`
+	ErrorMessage   string     `gorm:"size:500;comment:最近一次投递的错误信息" json:"error_message"`
+	DeliveredAt    *time.Time `gorm:"type:datetime;comment:投递成功时间" json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"type:datetime;comment:创建时间" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"type:datetime;comment:更新时间" json:"updated_at"`
+}
@@ -0,0 +1,59 @@
+// Package mute 提供用户禁言状态
+// 禁言状态保存在Redis中并在禁言期满后自动过期，由管理后台接口设置，发布、评论和点赞接口通过中间件据此拒绝被禁言用户的请求
+package mute
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/cachekey"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// State 用户禁言状态
+type State struct {
+	Reason     string    `json:"reason"`      // 禁言原因
+	MutedAt    time.Time `json:"muted_at"`    // 开始禁言的时间
+	ExpiresAt  time.Time `json:"expires_at"`  // 禁言结束的时间
+	OperatorID uint      `json:"operator_id"` // 执行禁言的管理员ID
+}
+
+// Get 获取用户的禁言状态，未被禁言时返回nil
+func Get(userID uint) (*State, error) {
+	var state State
+	if err := redis.GetObj(cachekey.UserMute(userID).String(), &state); err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Set 禁言用户，状态在禁言结束时间过期，重复禁言时覆盖之前的状态
+func Set(userID uint, state *State) error {
+	ttl := time.Until(state.ExpiresAt)
+	if ttl <= 0 {
+		return Clear(userID)
+	}
+	return redis.SetObj(cachekey.UserMute(userID).String(), state, ttl)
+}
+
+// Clear 解除用户禁言
+func Clear(userID uint) error {
+	_, err := redis.Del(cachekey.UserMute(userID).String())
+	return err
+}
+
+// Active 判断用户当前是否被禁言
+// 读取状态失败时视为未被禁言，避免Redis故障导致全部用户无法发布内容
+func Active(ctx context.Context, userID uint) (*State, bool) {
+	state, err := Get(userID)
+	if err != nil {
+		logger.Warn(ctx, "读取用户禁言状态失败", logger.Uint("user_id", userID), logger.Err(err))
+		return nil, false
+	}
+	return state, state != nil
+}
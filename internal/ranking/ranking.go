@@ -0,0 +1,180 @@
+// Package ranking 提供动态推荐排序功能
+// 综合发布时间、作者亲密度和互动热度计算动态得分，并在Redis中缓存用户的亲密度特征和排序结果
+package ranking
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"app/internal/cachekey"
+	"app/pkg/redis"
+)
+
+// 排序权重
+const (
+	// WeightRecency 发布时间权重
+	WeightRecency = 0.5
+	// WeightAffinity 作者亲密度权重
+	WeightAffinity = 0.3
+	// WeightEngagement 互动热度权重
+	WeightEngagement = 0.2
+	// RecencyHalfLife 发布时间得分的半衰期
+	RecencyHalfLife = 24 * time.Hour
+)
+
+// Candidate 参与排序的候选动态
+type Candidate struct {
+	PostID    uint      // 动态ID
+	AuthorID  uint      // 作者ID
+	Likes     int       // 点赞数
+	Comments  int       // 评论数
+	Views     int64     // 浏览数
+	CreatedAt time.Time // 发布时间
+}
+
+// Score 计算候选动态的得分，各分项均归一化到[0, 1)
+func Score(c Candidate, affinity float64, now time.Time) float64 {
+	// 发布时间按半衰期指数衰减
+	age := now.Sub(c.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+	recency := math.Exp(-math.Ln2 * age.Hours() / RecencyHalfLife.Hours())
+
+	// 亲密度和互动热度使用x/(1+x)压缩到[0, 1)
+	affinityScore := affinity / (1 + affinity)
+	engagement := math.Log1p(float64(c.Likes) + 2*float64(c.Comments) + 0.1*float64(c.Views))
+	engagementScore := engagement / (1 + engagement)
+
+	return WeightRecency*recency + WeightAffinity*affinityScore + WeightEngagement*engagementScore
+}
+
+// Rank 按得分从高到低排序候选动态，返回排序后的动态ID
+// 得分相同时发布时间较新的排在前面
+func Rank(candidates []Candidate, affinities map[uint]float64, now time.Time) []uint {
+	type scored struct {
+		candidate Candidate
+		score     float64
+	}
+
+	items := make([]scored, len(candidates))
+	for i, c := range candidates {
+		items[i] = scored{candidate: c, score: Score(c, affinities[c.AuthorID], now)}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].score != items[j].score {
+			return items[i].score > items[j].score
+		}
+		return items[i].candidate.CreatedAt.After(items[j].candidate.CreatedAt)
+	})
+
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.candidate.PostID
+	}
+	return ids
+}
+
+// AffinityFromCount 将互动次数转换为亲密度
+func AffinityFromCount(count int64) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return math.Log1p(float64(count))
+}
+
+// SaveAffinity 缓存用户对各作者的亲密度
+// 没有互动记录时写入占位字段，避免重复计算
+func SaveAffinity(userID uint, counts map[uint]int64) error {
+	key := cachekey.FeedAffinity(userID)
+	values := []interface{}{"_", 0}
+	for authorID, count := range counts {
+		values = append(values, strconv.FormatUint(uint64(authorID), 10), AffinityFromCount(count))
+	}
+
+	if _, err := redis.Del(key.String()); err != nil {
+		return err
+	}
+	if _, err := redis.HSet(key.String(), values...); err != nil {
+		return err
+	}
+	_, err := redis.Expire(key.String(), key.TTL())
+	return err
+}
+
+// LoadAffinity 读取缓存的用户亲密度，缓存不存在时返回false
+func LoadAffinity(userID uint) (map[uint]float64, bool, error) {
+	values, err := redis.HGetAll(cachekey.FeedAffinity(userID).String())
+	if err != nil {
+		return nil, false, err
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+
+	affinities := make(map[uint]float64, len(values))
+	for field, value := range values {
+		authorID, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		affinity, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		affinities[uint(authorID)] = affinity
+	}
+	return affinities, true, nil
+}
+
+// ForgetAuthor 从用户缓存的亲密度中删除作者，并清除推荐排序结果，用于作者注销后不再出现在用户的推荐中
+func ForgetAuthor(userID, authorID uint) error {
+	if _, err := redis.HDel(cachekey.FeedAffinity(userID).String(), strconv.FormatUint(uint64(authorID), 10)); err != nil {
+		return err
+	}
+	_, err := redis.Del(cachekey.FeedRanked(userID).String())
+	return err
+}
+
+// ClearFeeds 删除用户缓存的亲密度、推荐排序结果和发现页混合结果
+func ClearFeeds(userID uint) error {
+	_, err := redis.Del(
+		cachekey.FeedAffinity(userID).String(),
+		cachekey.FeedRanked(userID).String(),
+		cachekey.FeedDiscover(userID).String(),
+	)
+	return err
+}
+
+// CacheFeed 缓存用户的推荐排序结果，保证翻页期间顺序稳定
+func CacheFeed(userID uint, postIDs []uint) error {
+	key := cachekey.FeedRanked(userID)
+	return redis.SetObj(key.String(), postIDs, key.TTL())
+}
+
+// GetCachedFeed 读取缓存的推荐排序结果，缓存不存在时返回false
+func GetCachedFeed(userID uint) ([]uint, bool) {
+	var postIDs []uint
+	if err := redis.GetObj(cachekey.FeedRanked(userID).String(), &postIDs); err != nil {
+		return nil, false
+	}
+	return postIDs, true
+}
+
+// CacheDiscoverFeed 缓存用户的发现页混合结果，保证翻页期间顺序稳定
+func CacheDiscoverFeed(userID uint, postIDs []uint) error {
+	key := cachekey.FeedDiscover(userID)
+	return redis.SetObj(key.String(), postIDs, key.TTL())
+}
+
+// GetCachedDiscoverFeed 读取缓存的发现页混合结果，缓存不存在时返回false
+func GetCachedDiscoverFeed(userID uint) ([]uint, bool) {
+	var postIDs []uint
+	if err := redis.GetObj(cachekey.FeedDiscover(userID).String(), &postIDs); err != nil {
+		return nil, false
+	}
+	return postIDs, true
+}
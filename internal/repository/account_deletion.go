@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AccountDeletionRepository 账号注销清理任务仓库接口
+type AccountDeletionRepository interface {
+	// Create 创建清理任务
+	Create(ctx context.Context, deletion *model.AccountDeletion) error
+	// FindByUserID 根据用户ID查找清理任务
+	FindByUserID(ctx context.Context, userID uint) (*model.AccountDeletion, error)
+	// GetRunnable 获取可执行的清理任务（未完成且不在保留期等待中）
+	GetRunnable(ctx context.Context, now time.Time, limit int) ([]model.AccountDeletion, error)
+	// Update 更新清理任务
+	Update(ctx context.Context, deletion *model.AccountDeletion) error
+}
+
+// accountDeletionRepository 账号注销清理任务仓库实现
+type accountDeletionRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountDeletionRepository 创建账号注销清理任务仓库实例
+func NewAccountDeletionRepository(db *gorm.DB) AccountDeletionRepository {
+	return &accountDeletionRepository{db: db}
+}
+
+// Create 创建清理任务
+func (r *accountDeletionRepository) Create(ctx context.Context, deletion *model.AccountDeletion) error {
+	return r.db.WithContext(ctx).Create(deletion).Error
+}
+
+// FindByUserID 根据用户ID查找清理任务
+func (r *accountDeletionRepository) FindByUserID(ctx context.Context, userID uint) (*model.AccountDeletion, error) {
+	var deletion model.AccountDeletion
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&deletion)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &deletion, nil
+}
+
+// GetRunnable 获取可执行的清理任务（未完成且不在保留期等待中）
+func (r *accountDeletionRepository) GetRunnable(ctx context.Context, now time.Time, limit int) ([]model.AccountDeletion, error) {
+	var deletions []model.AccountDeletion
+	err := r.db.WithContext(ctx).Where("stage <> ?", constant.DeletionStageCompleted).
+		Where("stage <> ? OR retention_until <= ?", constant.DeletionStageAwaitRetention, now).
+		Order("id ASC").Limit(limit).Find(&deletions).Error
+	return deletions, err
+}
+
+// Update 更新清理任务
+func (r *accountDeletionRepository) Update(ctx context.Context, deletion *model.AccountDeletion) error {
+	return r.db.WithContext(ctx).Save(deletion).Error
+}
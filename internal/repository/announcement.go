@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnnouncementRepository 系统公告仓库接口
+type AnnouncementRepository interface {
+	// 公告相关
+	Create(ctx context.Context, announcement *model.Announcement) error
+	GetByID(ctx context.Context, id uint) (*model.Announcement, error)
+	List(ctx context.Context, page, size int) ([]model.Announcement, int64, error)
+	Delete(ctx context.Context, id uint) error
+	FindPublished(ctx context.Context, now time.Time, limit int) ([]model.Announcement, error)
+	FindTargetUserIDs(ctx context.Context, announcement *model.Announcement, afterID uint, limit int) ([]uint, error)
+	// 已读回执相关
+	MarkRead(ctx context.Context, userID uint, announcementIDs []uint) (int64, error)
+	GetReadIDs(ctx context.Context, userID uint, announcementIDs []uint) (map[uint]bool, error)
+	CountReads(ctx context.Context, announcementIDs []uint) (map[uint]int64, error)
+	// 账号注销
+	DeleteReadsByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// announcementRepository 系统公告仓库实现
+type announcementRepository struct {
+	db *gorm.DB
+}
+
+// NewAnnouncementRepository 创建系统公告仓库实例
+func NewAnnouncementRepository(db *gorm.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+// Create 创建公告
+func (r *announcementRepository) Create(ctx context.Context, announcement *model.Announcement) error {
+	return r.db.WithContext(ctx).Create(announcement).Error
+}
+
+// GetByID 获取公告
+func (r *announcementRepository) GetByID(ctx context.Context, id uint) (*model.Announcement, error) {
+	var announcement model.Announcement
+	result := r.db.WithContext(ctx).First(&announcement, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &announcement, nil
+}
+
+// List 分页获取所有公告，最新发布的在前
+func (r *announcementRepository) List(ctx context.Context, page, size int) ([]model.Announcement, int64, error) {
+	var announcements []model.Announcement
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&model.Announcement{})
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("publish_at DESC, id DESC").Offset((page - 1) * size).Limit(size).Find(&announcements).Error
+	return announcements, count, err
+}
+
+// Delete 删除公告，已读回执保留用于统计
+func (r *announcementRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Announcement{}, id).Error
+}
+
+// FindPublished 查找已发布且未过期的公告，最新发布的在前
+func (r *announcementRepository) FindPublished(ctx context.Context, now time.Time, limit int) ([]model.Announcement, error) {
+	var announcements []model.Announcement
+	err := r.db.WithContext(ctx).
+		Where("publish_at <= ? AND (expire_at IS NULL OR expire_at > ?)", now, now).
+		Order("publish_at DESC, id DESC").Limit(limit).Find(&announcements).Error
+	return announcements, err
+}
+
+// FindTargetUserIDs 按ID顺序分批查找公告目标范围内的正常状态用户ID，用于推送通知
+func (r *announcementRepository) FindTargetUserIDs(ctx context.Context, announcement *model.Announcement, afterID uint, limit int) ([]uint, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id > ? AND status = ?", afterID, constant.UserStatusNormal)
+
+	switch announcement.Target {
+	case constant.AnnouncementTargetTag:
+		tagID := r.db.Model(&model.Tag{}).Select("id").Where("name = ?", announcement.Segment)
+		query = query.Where("id IN (?)", r.db.Model(&model.UserTag{}).Select("user_id").Where("tag_id = (?)", tagID))
+	case constant.AnnouncementTargetSegment:
+		switch announcement.Segment {
+		case constant.AnnouncementSegmentNewUsers:
+			query = query.Where("created_at >= ?", announcement.PublishAt.Add(-constant.AnnouncementNewUserWindow))
+		case constant.AnnouncementSegmentExistingUsers:
+			query = query.Where("created_at < ?", announcement.PublishAt)
+		case constant.AnnouncementSegmentPrivateAccounts:
+			query = query.Where("is_private = ?", true)
+		default:
+			return nil, nil
+		}
+	}
+
+	var ids []uint
+	err := query.Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// MarkRead 批量记录已读回执，已读过的公告忽略，返回新增的回执数量
+func (r *announcementRepository) MarkRead(ctx context.Context, userID uint, announcementIDs []uint) (int64, error) {
+	if len(announcementIDs) == 0 {
+		return 0, nil
+	}
+	reads := make([]model.AnnouncementRead, len(announcementIDs))
+	for i, id := range announcementIDs {
+		reads[i] = model.AnnouncementRead{AnnouncementID: id, UserID: userID}
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&reads)
+	return result.RowsAffected, result.Error
+}
+
+// GetReadIDs 获取用户已读的公告ID
+func (r *announcementRepository) GetReadIDs(ctx context.Context, userID uint, announcementIDs []uint) (map[uint]bool, error) {
+	read := make(map[uint]bool, len(announcementIDs))
+	if len(announcementIDs) == 0 {
+		return read, nil
+	}
+
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.AnnouncementRead{}).
+		Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).
+		Pluck("announcement_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		read[id] = true
+	}
+	return read, nil
+}
+
+// CountReads 批量统计公告的已读人数
+func (r *announcementRepository) CountReads(ctx context.Context, announcementIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(announcementIDs))
+	if len(announcementIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		AnnouncementID uint
+		Count          int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.AnnouncementRead{}).
+		Select("announcement_id, COUNT(*) AS count").
+		Where("announcement_id IN ?", announcementIDs).
+		Group("announcement_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.AnnouncementID] = row.Count
+	}
+	return counts, nil
+}
+
+// DeleteReadsByUser 删除用户的所有已读回执，返回删除的记录数
+func (r *announcementRepository) DeleteReadsByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.AnnouncementRead{})
+	return result.RowsAffected, result.Error
+}
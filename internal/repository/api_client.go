@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"app/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIClientRepository 服务端调用方仓库接口
+type APIClientRepository interface {
+	// Create 创建调用方
+	Create(ctx context.Context, client *model.APIClient) error
+	// FindByID 根据ID查找调用方
+	FindByID(ctx context.Context, id uint) (*model.APIClient, error)
+	// FindByAppKey 根据应用标识查找调用方
+	FindByAppKey(ctx context.Context, appKey string) (*model.APIClient, error)
+	// UpdateLastUsedAt 更新调用方最近一次调用时间
+	UpdateLastUsedAt(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+// apiClientRepository 服务端调用方仓库实现
+type apiClientRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIClientRepository 创建服务端调用方仓库实例
+func NewAPIClientRepository(db *gorm.DB) APIClientRepository {
+	return &apiClientRepository{db: db}
+}
+
+// Create 创建调用方
+func (r *apiClientRepository) Create(ctx context.Context, client *model.APIClient) error {
+	return r.db.WithContext(ctx).Create(client).Error
+}
+
+// FindByID 根据ID查找调用方
+func (r *apiClientRepository) FindByID(ctx context.Context, id uint) (*model.APIClient, error) {
+	var client model.APIClient
+	result := r.db.WithContext(ctx).First(&client, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &client, nil
+}
+
+// FindByAppKey 根据应用标识查找调用方
+func (r *apiClientRepository) FindByAppKey(ctx context.Context, appKey string) (*model.APIClient, error) {
+	var client model.APIClient
+	result := r.db.WithContext(ctx).Where("app_key = ?", appKey).First(&client)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &client, nil
+}
+
+// UpdateLastUsedAt 更新调用方最近一次调用时间
+func (r *apiClientRepository) UpdateLastUsedAt(ctx context.Context, id uint, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.APIClient{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
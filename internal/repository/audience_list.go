@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AudienceListRepository 好友列表仓库接口
+type AudienceListRepository interface {
+	// 列表相关
+	CreateList(ctx context.Context, list *model.AudienceList) error
+	GetList(ctx context.Context, id uint) (*model.AudienceList, error)
+	GetUserLists(ctx context.Context, userID uint) ([]model.AudienceList, error)
+	CountUserLists(ctx context.Context, userID uint) (int64, error)
+	UpdateListName(ctx context.Context, id uint, name string) error
+	DeleteList(ctx context.Context, id uint) error
+	// 成员相关
+	AddMembers(ctx context.Context, listID uint, memberIDs []uint) (int64, error)
+	RemoveMembers(ctx context.Context, listID uint, memberIDs []uint) (int64, error)
+	GetMembers(ctx context.Context, listID uint, page, size int) ([]model.AudienceListMember, int64, error)
+	CountMembers(ctx context.Context, listIDs []uint) (map[uint]int64, error)
+	IsMember(ctx context.Context, listID, memberID uint) (bool, error)
+	// 账号注销
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// audienceListRepository 好友列表仓库实现
+type audienceListRepository struct {
+	db *gorm.DB
+}
+
+// NewAudienceListRepository 创建好友列表仓库实例
+func NewAudienceListRepository(db *gorm.DB) AudienceListRepository {
+	return &audienceListRepository{db: db}
+}
+
+// CreateList 创建好友列表
+func (r *audienceListRepository) CreateList(ctx context.Context, list *model.AudienceList) error {
+	return r.db.WithContext(ctx).Create(list).Error
+}
+
+// GetList 获取好友列表
+func (r *audienceListRepository) GetList(ctx context.Context, id uint) (*model.AudienceList, error) {
+	var list model.AudienceList
+	err := r.db.WithContext(ctx).First(&list, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetUserLists 获取用户创建的所有好友列表，按创建时间排序
+func (r *audienceListRepository) GetUserLists(ctx context.Context, userID uint) ([]model.AudienceList, error) {
+	var lists []model.AudienceList
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&lists).Error
+	return lists, err
+}
+
+// CountUserLists 统计用户创建的好友列表数量
+func (r *audienceListRepository) CountUserLists(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.AudienceList{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// UpdateListName 修改好友列表名称
+func (r *audienceListRepository) UpdateListName(ctx context.Context, id uint, name string) error {
+	return r.db.WithContext(ctx).Model(&model.AudienceList{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// DeleteList 删除好友列表及其成员记录
+// 成员记录被删除后，仅该列表可见的动态只对作者自己可见
+func (r *audienceListRepository) DeleteList(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", id).Delete(&model.AudienceListMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.AudienceList{}, id).Error
+	})
+}
+
+// AddMembers 批量添加列表成员，已在列表中的成员忽略，返回新增的数量
+func (r *audienceListRepository) AddMembers(ctx context.Context, listID uint, memberIDs []uint) (int64, error) {
+	if len(memberIDs) == 0 {
+		return 0, nil
+	}
+	members := make([]model.AudienceListMember, len(memberIDs))
+	for i, id := range memberIDs {
+		members[i] = model.AudienceListMember{ListID: listID, MemberID: id}
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members)
+	return result.RowsAffected, result.Error
+}
+
+// RemoveMembers 批量移除列表成员，返回移除的数量
+func (r *audienceListRepository) RemoveMembers(ctx context.Context, listID uint, memberIDs []uint) (int64, error) {
+	if len(memberIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Where("list_id = ? AND member_id IN ?", listID, memberIDs).Delete(&model.AudienceListMember{})
+	return result.RowsAffected, result.Error
+}
+
+// GetMembers 获取列表成员，按加入时间倒序
+func (r *audienceListRepository) GetMembers(ctx context.Context, listID uint, page, size int) ([]model.AudienceListMember, int64, error) {
+	var members []model.AudienceListMember
+	var count int64
+
+	offset := (page - 1) * size
+	query := r.db.WithContext(ctx).Model(&model.AudienceListMember{}).Where("list_id = ?", listID)
+
+	// 计算总数
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err = query.Order("id DESC").Offset(offset).Limit(size).Find(&members).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return members, count, nil
+}
+
+// CountMembers 批量统计列表的成员数量
+func (r *audienceListRepository) CountMembers(ctx context.Context, listIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(listIDs))
+	if len(listIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ListID uint
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.AudienceListMember{}).
+		Select("list_id, COUNT(*) AS count").
+		Where("list_id IN ?", listIDs).
+		Group("list_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ListID] = row.Count
+	}
+	return counts, nil
+}
+
+// IsMember 检查用户是否为列表成员
+func (r *audienceListRepository) IsMember(ctx context.Context, listID, memberID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.AudienceListMember{}).
+		Where("list_id = ? AND member_id = ?", listID, memberID).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteAllByUser 删除用户创建的所有好友列表及成员记录，并将用户从他人的列表中移除，返回删除的记录数
+func (r *audienceListRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		listIDs := tx.Model(&model.AudienceList{}).Select("id").Where("user_id = ?", userID)
+		result := tx.Where("list_id IN (?) OR member_id = ?", listIDs, userID).Delete(&model.AudienceListMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected += result.RowsAffected
+
+		result = tx.Where("user_id = ?", userID).Delete(&model.AudienceList{})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected += result.RowsAffected
+		return nil
+	})
+	return affected, err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// CommentImageRepository 评论图片存储库接口
+type CommentImageRepository interface {
+	// CreateCommentImage 创建评论图片
+	CreateCommentImage(ctx context.Context, image *model.CommentImage) error
+	// GetByCommentIDs 批量获取评论的图片，返回评论ID到图片的映射
+	GetByCommentIDs(ctx context.Context, commentIDs []uint) (map[uint]model.CommentImage, error)
+	// GetPostCommentImagesWithDeleted 获取动态下所有评论的图片，包含已删除的记录
+	GetPostCommentImagesWithDeleted(ctx context.Context, postID uint) ([]model.CommentImage, error)
+	// FindExpiringURLs 按ID顺序获取afterID之后预签名URL在指定时间前过期的评论图片
+	FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) ([]model.CommentImage, error)
+	// UpdateURL 更新评论图片的访问URL、URL类型和过期时间
+	UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error
+}
+
+// commentImageRepository 评论图片存储库实现
+type commentImageRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentImageRepository 创建评论图片存储库实例
+func NewCommentImageRepository(db *gorm.DB) CommentImageRepository {
+	return &commentImageRepository{db: db}
+}
+
+// CreateCommentImage 创建评论图片
+func (r *commentImageRepository) CreateCommentImage(ctx context.Context, image *model.CommentImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+// GetByCommentIDs 批量获取评论的图片，返回评论ID到图片的映射
+func (r *commentImageRepository) GetByCommentIDs(ctx context.Context, commentIDs []uint) (map[uint]model.CommentImage, error) {
+	result := make(map[uint]model.CommentImage)
+	if len(commentIDs) == 0 {
+		return result, nil
+	}
+
+	var images []model.CommentImage
+	if err := r.db.WithContext(ctx).Where("comment_id IN ?", commentIDs).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		result[img.CommentID] = img
+	}
+	return result, nil
+}
+
+// GetPostCommentImagesWithDeleted 获取动态下所有评论的图片，包含已删除的记录
+func (r *commentImageRepository) GetPostCommentImagesWithDeleted(ctx context.Context, postID uint) ([]model.CommentImage, error) {
+	var images []model.CommentImage
+	err := r.db.WithContext(ctx).Unscoped().Where("post_id = ?", postID).Find(&images).Error
+	return images, err
+}
+
+// FindExpiringURLs 按ID顺序获取afterID之后预签名URL在指定时间前过期的评论图片
+func (r *commentImageRepository) FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) ([]model.CommentImage, error) {
+	var images []model.CommentImage
+	err := r.db.WithContext(ctx).
+		Where("id > ? AND url_presigned = ? AND url_expires_at < ?", afterID, true, before).
+		Order("id").Limit(limit).Find(&images).Error
+	return images, err
+}
+
+// UpdateURL 更新评论图片的访问URL、URL类型和过期时间，不修改更新时间
+func (r *commentImageRepository) UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.CommentImage{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"url": url, "url_presigned": presigned, "url_expires_at": expiresAt}).Error
+}
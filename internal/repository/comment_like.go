@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// CommentLikeRepository 评论点赞仓库接口
+type CommentLikeRepository interface {
+	// LikeComment 点赞评论并增加点赞数，返回是否新增了点赞
+	LikeComment(ctx context.Context, commentID, userID uint) (bool, error)
+	// UnlikeComment 取消点赞评论并减少点赞数，返回是否取消了点赞
+	UnlikeComment(ctx context.Context, commentID, userID uint) (bool, error)
+	// GetLikedCommentIDs 获取用户在指定评论中已点赞的评论ID集合
+	GetLikedCommentIDs(ctx context.Context, userID uint, commentIDs []uint) (map[uint]bool, error)
+}
+
+// commentLikeRepository 评论点赞仓库实现
+type commentLikeRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentLikeRepository 创建评论点赞仓库实例
+func NewCommentLikeRepository(db *gorm.DB) CommentLikeRepository {
+	return &commentLikeRepository{db: db}
+}
+
+// LikeComment 点赞评论并增加点赞数，返回是否新增了点赞
+// 在事务中完成，重复点赞时不改变点赞数
+func (r *commentLikeRepository) LikeComment(ctx context.Context, commentID, userID uint) (bool, error) {
+	liked := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing model.CommentLike
+		err := tx.Where("comment_id = ? AND user_id = ?", commentID, userID).First(&existing).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := tx.Create(&model.CommentLike{CommentID: commentID, UserID: userID}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.PostComment{}).Where("id = ?", commentID).
+			Update("likes", gorm.Expr("likes + ?", 1)).Error; err != nil {
+			return err
+		}
+
+		liked = true
+		return nil
+	})
+	return liked, err
+}
+
+// UnlikeComment 取消点赞评论并减少点赞数，返回是否取消了点赞
+func (r *commentLikeRepository) UnlikeComment(ctx context.Context, commentID, userID uint) (bool, error) {
+	unliked := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("comment_id = ? AND user_id = ?", commentID, userID).Delete(&model.CommentLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.PostComment{}).Where("id = ? AND likes > 0", commentID).
+			Update("likes", gorm.Expr("likes - ?", 1)).Error; err != nil {
+			return err
+		}
+
+		unliked = true
+		return nil
+	})
+	return unliked, err
+}
+
+// GetLikedCommentIDs 获取用户在指定评论中已点赞的评论ID集合
+func (r *commentLikeRepository) GetLikedCommentIDs(ctx context.Context, userID uint, commentIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(commentIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ?", userID, commentIDs).
+		Pluck("comment_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
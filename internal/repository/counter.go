@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Counter 冗余计数字段的定义，计数应等于来源表中关联到该记录的行数
+type Counter struct {
+	Name             string // 计数名称，用于日志和指标
+	Table            string // 计数字段所在的表
+	Column           string // 计数字段
+	SourceTable      string // 来源表
+	SourceKey        string // 来源表中关联计数记录ID的字段
+	SourceSoftDelete bool   // 来源表是否软删除，已软删除的行不计入
+	WriteBehind      string // 计数先在Redis中累加时对应的动态计数名称，有未同步增量的记录本次不校准
+}
+
+// Counters 需要定期校准的计数字段，新增冗余计数时在此登记
+// 动态点赞数没有逐条的点赞记录，无法从来源表重新统计，因此不在其中
+var Counters = []Counter{
+	{
+		Name:             constant.CounterPostComments,
+		Table:            "post",
+		Column:           "comments",
+		SourceTable:      "post_comment",
+		SourceKey:        "post_id",
+		SourceSoftDelete: true,
+		WriteBehind:      constant.PostCounterComments,
+	},
+	{
+		Name:        constant.CounterCommentLikes,
+		Table:       "post_comment",
+		Column:      "likes",
+		SourceTable: "comment_like",
+		SourceKey:   "comment_id",
+	},
+}
+
+// countSQL 返回按来源表统计计数的关联子查询
+func (c Counter) countSQL() string {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE `%s`.`%s` = `%s`.`id`", c.SourceTable, c.SourceTable, c.SourceKey, c.Table)
+	if c.SourceSoftDelete {
+		query += fmt.Sprintf(" AND `%s`.`deleted_at` IS NULL", c.SourceTable)
+	}
+	return query
+}
+
+// CounterDrift 计数与来源表统计结果不一致的记录
+type CounterDrift struct {
+	ID     uint  // 计数所在记录的ID
+	Stored int64 // 当前保存的计数
+	Actual int64 // 按来源表统计的计数
+}
+
+// CounterRepository 计数校准仓库接口
+type CounterRepository interface {
+	// FindDrifts 按ID顺序扫描afterID之后的至多limit条记录，返回本批最后一条记录的ID、扫描的记录数和计数不一致的记录
+	FindDrifts(ctx context.Context, counter Counter, afterID uint, limit int) (uint, int, []CounterDrift, error)
+	// FixCounts 按来源表重新统计指定记录的计数
+	FixCounts(ctx context.Context, counter Counter, ids []uint) error
+
+	// GetCheckpoint 获取计数日志已同步到数据库的位置，从未同步时返回空字符串
+	GetCheckpoint(ctx context.Context, name string) (string, error)
+	// ApplyPostCounterDeltas 在事务中累加动态计数并保存同步位置，保证同一段日志只会同步一次
+	ApplyPostCounterDeltas(ctx context.Context, name, position string, deltas []PostCounterDelta) error
+}
+
+// PostCounterDelta 一条动态尚未同步到数据库的计数增量
+type PostCounterDelta struct {
+	PostID   uint
+	Likes    int64
+	Comments int64
+}
+
+// counterRepository 计数校准仓库实现
+type counterRepository struct {
+	db *gorm.DB
+}
+
+// NewCounterRepository 创建计数校准仓库实例
+func NewCounterRepository(db *gorm.DB) CounterRepository {
+	return &counterRepository{db: db}
+}
+
+// FindDrifts 按ID顺序扫描一批记录并找出计数不一致的记录
+// 直接按表名查询，已软删除的记录（如回收站中的动态）同样参与校准
+func (r *counterRepository) FindDrifts(ctx context.Context, counter Counter, afterID uint, limit int) (uint, int, []CounterDrift, error) {
+	var rows []CounterDrift
+	err := r.db.WithContext(ctx).Table(counter.Table).
+		Select(fmt.Sprintf("`id`, `%s` AS stored, (%s) AS actual", counter.Column, counter.countSQL())).
+		Where("`id` > ?", afterID).
+		Order("`id`").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return afterID, 0, nil, err
+	}
+	if len(rows) == 0 {
+		return afterID, 0, nil, nil
+	}
+
+	drifts := make([]CounterDrift, 0)
+	for _, row := range rows {
+		if row.Stored != row.Actual {
+			drifts = append(drifts, row)
+		}
+	}
+	return rows[len(rows)-1].ID, len(rows), drifts, nil
+}
+
+// FixCounts 按来源表重新统计指定记录的计数
+// 在同一条语句中重新统计，不会覆盖扫描之后发生的计数变化；不更新updated_at
+func (r *counterRepository) FixCounts(ctx context.Context, counter Counter, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Table(counter.Table).
+		Where("`id` IN ?", ids).
+		UpdateColumn(counter.Column, gorm.Expr("("+counter.countSQL()+")")).Error
+}
+
+// GetCheckpoint 获取计数日志已同步到数据库的位置
+func (r *counterRepository) GetCheckpoint(ctx context.Context, name string) (string, error) {
+	var checkpoints []model.CounterCheckpoint
+	err := r.db.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&checkpoints).Error
+	if err != nil || len(checkpoints) == 0 {
+		return "", err
+	}
+	return checkpoints[0].Position, nil
+}
+
+// ApplyPostCounterDeltas 在事务中累加动态计数并保存同步位置
+// 已删除的动态同样累加，恢复后计数保持准确
+func (r *counterRepository) ApplyPostCounterDeltas(ctx context.Context, name, position string, deltas []PostCounterDelta) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, delta := range deltas {
+			updates := map[string]interface{}{}
+			if delta.Likes != 0 {
+				updates["likes"] = gorm.Expr("likes + ?", delta.Likes)
+			}
+			if delta.Comments != 0 {
+				updates["comments"] = gorm.Expr("comments + ?", delta.Comments)
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			if err := tx.Unscoped().Model(&model.Post{}).Where("id = ?", delta.PostID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"position", "updated_at"}),
+		}).Create(&model.CounterCheckpoint{Name: name, Position: position}).Error
+	})
+}
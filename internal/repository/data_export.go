@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// DataExportRepository 数据导出任务仓库接口
+type DataExportRepository interface {
+	// Create 创建导出任务
+	Create(ctx context.Context, export *model.DataExport) error
+	// FindByID 根据ID查找导出任务
+	FindByID(ctx context.Context, id uint) (*model.DataExport, error)
+	// FindActiveByUserID 查找用户未完成的导出任务，不包含处理超时的任务
+	FindActiveByUserID(ctx context.Context, userID uint) (*model.DataExport, error)
+	// GetPendingExports 获取等待处理的导出任务
+	GetPendingExports(ctx context.Context, limit int) ([]model.DataExport, error)
+	// ClaimExport 将等待处理的导出任务标记为处理中，返回是否抢占成功
+	ClaimExport(ctx context.Context, id uint) (bool, error)
+	// ReclaimStaleExports 将处理中且在指定时间前未更新的导出任务标记为失败，返回回收的任务数
+	ReclaimStaleExports(ctx context.Context, before time.Time) (int64, error)
+	// Update 更新导出任务
+	Update(ctx context.Context, export *model.DataExport) error
+}
+
+// dataExportRepository 数据导出任务仓库实现
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository 创建数据导出任务仓库实例
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+// Create 创建导出任务
+func (r *dataExportRepository) Create(ctx context.Context, export *model.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+// FindByID 根据ID查找导出任务
+func (r *dataExportRepository) FindByID(ctx context.Context, id uint) (*model.DataExport, error) {
+	var export model.DataExport
+	result := r.db.WithContext(ctx).First(&export, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &export, nil
+}
+
+// FindActiveByUserID 查找用户未完成的导出任务
+// 处理中超过constant.ExportProcessingTimeout未更新的任务视为已中断，不再阻止用户重新发起导出
+func (r *dataExportRepository) FindActiveByUserID(ctx context.Context, userID uint) (*model.DataExport, error) {
+	var export model.DataExport
+	staleBefore := time.Now().Add(-constant.ExportProcessingTimeout)
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND (status = ? OR (status = ? AND updated_at >= ?))", userID, constant.ExportStatusPending, constant.ExportStatusProcessing, staleBefore).
+		Order("created_at DESC").First(&export)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &export, nil
+}
+
+// GetPendingExports 获取等待处理的导出任务，按创建时间先后排序
+func (r *dataExportRepository) GetPendingExports(ctx context.Context, limit int) ([]model.DataExport, error) {
+	var exports []model.DataExport
+	err := r.db.WithContext(ctx).Where("status = ?", constant.ExportStatusPending).Order("created_at ASC").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+// ClaimExport 将等待处理的导出任务标记为处理中，返回是否抢占成功
+// 通过带状态条件的更新保证同一任务只会被处理一次
+func (r *dataExportRepository) ClaimExport(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.DataExport{}).
+		Where("id = ? AND status = ?", id, constant.ExportStatusPending).
+		Update("status", constant.ExportStatusProcessing)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReclaimStaleExports 将处理中且在指定时间前未更新的导出任务标记为失败，返回回收的任务数
+// 抢占任务时会刷新更新时间，进程崩溃、重启或任务超时后遗留的处理中任务由此回收
+func (r *dataExportRepository) ReclaimStaleExports(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.DataExport{}).
+		Where("status = ? AND updated_at < ?", constant.ExportStatusProcessing, before).
+		Updates(map[string]interface{}{
+			"status":        constant.ExportStatusFailed,
+			"error_message": constant.ExportStaleErrorMessage,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Update 更新导出任务
+func (r *dataExportRepository) Update(ctx context.Context, export *model.DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
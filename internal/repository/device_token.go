@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceTokenRepository 设备推送令牌仓库接口
+type DeviceTokenRepository interface {
+	// Save 保存设备令牌，令牌已存在时转移给当前用户并刷新上报时间
+	Save(ctx context.Context, token *model.DeviceToken) error
+	// GetUserTokens 获取用户的所有设备令牌，最近上报的在前
+	GetUserTokens(ctx context.Context, userID uint) ([]model.DeviceToken, error)
+	// TrimUserTokens 只保留用户最近上报的keep个设备令牌，返回删除的数量
+	TrimUserTokens(ctx context.Context, userID uint, keep int) (int64, error)
+	// DeleteUserToken 删除用户的指定设备令牌，返回删除的数量
+	DeleteUserToken(ctx context.Context, userID uint, token string) (int64, error)
+	// DeleteToken 删除已失效的设备令牌
+	DeleteToken(ctx context.Context, token string) error
+	// DeleteAllByUser 删除用户的所有设备令牌
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// deviceTokenRepository 设备推送令牌仓库实现
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository 创建设备推送令牌仓库实例
+func NewDeviceTokenRepository(db *gorm.DB) DeviceTokenRepository {
+	return &deviceTokenRepository{db: db}
+}
+
+// Save 保存设备令牌，令牌已存在时转移给当前用户并刷新上报时间
+// 同一设备切换账号后只推送给最近登录的用户
+func (r *deviceTokenRepository) Save(ctx context.Context, token *model.DeviceToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+	}).Create(token).Error
+}
+
+// GetUserTokens 获取用户的所有设备令牌，最近上报的在前
+func (r *deviceTokenRepository) GetUserTokens(ctx context.Context, userID uint) ([]model.DeviceToken, error) {
+	var tokens []model.DeviceToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("updated_at DESC, id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// TrimUserTokens 只保留用户最近上报的keep个设备令牌，返回删除的数量
+func (r *deviceTokenRepository) TrimUserTokens(ctx context.Context, userID uint, keep int) (int64, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.DeviceToken{}).Where("user_id = ?", userID).
+		Order("updated_at DESC, id DESC").Offset(keep).Limit(1000).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteUserToken 删除用户的指定设备令牌，返回删除的数量
+func (r *deviceTokenRepository) DeleteUserToken(ctx context.Context, userID uint, token string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND token = ?", userID, token).Delete(&model.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteToken 删除已失效的设备令牌
+func (r *deviceTokenRepository) DeleteToken(ctx context.Context, token string) error {
+	return r.db.WithContext(ctx).Where("token = ?", token).Delete(&model.DeviceToken{}).Error
+}
+
+// DeleteAllByUser 删除用户的所有设备令牌
+func (r *deviceTokenRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.DeviceToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"app/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LinkPreviewRepository 链接预览仓库接口
+type LinkPreviewRepository interface {
+	// FindOrCreate 按链接摘要查找链接预览，不存在时创建待抓取的记录
+	FindOrCreate(ctx context.Context, urlHash, url string) (*model.LinkPreview, error)
+	// GetByIDs 根据ID列表批量获取链接预览，不保证返回顺序
+	GetByIDs(ctx context.Context, ids []uint) ([]model.LinkPreview, error)
+	// UpdatePreview 更新抓取状态和预览信息
+	UpdatePreview(ctx context.Context, preview *model.LinkPreview) error
+}
+
+// linkPreviewRepository 链接预览仓库实现
+type linkPreviewRepository struct {
+	db *gorm.DB
+}
+
+// NewLinkPreviewRepository 创建链接预览仓库实例
+func NewLinkPreviewRepository(db *gorm.DB) LinkPreviewRepository {
+	return &linkPreviewRepository{db: db}
+}
+
+// FindOrCreate 按链接摘要查找链接预览，不存在时创建待抓取的记录
+// 并发创建同一链接时忽略唯一索引冲突，再查询已存在的记录
+func (r *linkPreviewRepository) FindOrCreate(ctx context.Context, urlHash, url string) (*model.LinkPreview, error) {
+	preview := &model.LinkPreview{URLHash: urlHash, URL: url}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(preview).Error
+	if err != nil {
+		return nil, err
+	}
+	if preview.ID != 0 {
+		return preview, nil
+	}
+
+	preview = &model.LinkPreview{}
+	if err := r.db.WithContext(ctx).Where("url_hash = ?", urlHash).First(preview).Error; err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// GetByIDs 根据ID列表批量获取链接预览，不保证返回顺序
+func (r *linkPreviewRepository) GetByIDs(ctx context.Context, ids []uint) ([]model.LinkPreview, error) {
+	var previews []model.LinkPreview
+	if len(ids) == 0 {
+		return previews, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&previews).Error
+	return previews, err
+}
+
+// UpdatePreview 更新抓取状态和预览信息
+func (r *linkPreviewRepository) UpdatePreview(ctx context.Context, preview *model.LinkPreview) error {
+	return r.db.WithContext(ctx).Model(&model.LinkPreview{}).Where("id = ?", preview.ID).Updates(map[string]interface{}{
+		"status":      preview.Status,
+		"title":       preview.Title,
+		"description": preview.Description,
+		"image_url":   preview.ImageURL,
+		"site_name":   preview.SiteName,
+		"fetched_at":  preview.FetchedAt,
+	}).Error
+}
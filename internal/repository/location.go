@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"app/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// LocationRepository 位置仓库接口
+type LocationRepository interface {
+	// CreateLocation 创建位置
+	CreateLocation(ctx context.Context, location *model.Location) error
+	// GetLocationsByIDs 根据ID列表批量获取位置，不保证返回顺序
+	GetLocationsByIDs(ctx context.Context, ids []uint) ([]model.Location, error)
+	// UpdateAddress 更新位置的地址
+	UpdateAddress(ctx context.Context, id uint, address string) error
+}
+
+// locationRepository 位置仓库实现
+type locationRepository struct {
+	db *gorm.DB
+}
+
+// NewLocationRepository 创建位置仓库实例
+func NewLocationRepository(db *gorm.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+// CreateLocation 创建位置
+func (r *locationRepository) CreateLocation(ctx context.Context, location *model.Location) error {
+	return r.db.WithContext(ctx).Create(location).Error
+}
+
+// GetLocationsByIDs 根据ID列表批量获取位置，不保证返回顺序
+func (r *locationRepository) GetLocationsByIDs(ctx context.Context, ids []uint) ([]model.Location, error) {
+	var locations []model.Location
+	if len(ids) == 0 {
+		return locations, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&locations).Error
+	return locations, err
+}
+
+// UpdateAddress 更新位置的地址
+func (r *locationRepository) UpdateAddress(ctx context.Context, id uint, address string) error {
+	return r.db.WithContext(ctx).Model(&model.Location{}).Where("id = ?", id).Update("address", address).Error
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"app/internal/constant"
+	"app/internal/model"
+	"app/pkg/database/dbtest"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testDB 仓库测试共用的内存数据库，每个测试通过dbtest.Begin在独立事务中执行
+var testDB *gorm.DB
+
+func TestMain(m *testing.M) {
+	db, err := dbtest.Open(sqlite.Open(dbtest.MemoryDSN))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	testDB = db
+	os.Exit(m.Run())
+}
+
+// createTestUser 创建正常状态的公开账号
+func createTestUser(t *testing.T, tx *gorm.DB, nickname string) *model.User {
+	t.Helper()
+	user := &model.User{Nickname: nickname, Status: constant.UserStatusNormal}
+	if err := tx.Create(user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	return user
+}
+
+// createTestPost 创建指定可见性的动态
+func createTestPost(t *testing.T, tx *gorm.DB, userID uint, visibility constant.Visibility) *model.Post {
+	t.Helper()
+	post := &model.Post{UserID: userID, Content: "test", Visibility: int(visibility)}
+	if err := NewPostRepository(tx).CreatePost(context.Background(), post); err != nil {
+		t.Fatalf("创建动态失败: %v", err)
+	}
+	return post
+}
+
+// createTestFollow 创建已通过的关注关系
+func createTestFollow(t *testing.T, tx *gorm.DB, userID, targetID uint) {
+	t.Helper()
+	follow := &model.UserFollower{UserID: userID, TargetID: targetID, Status: int(constant.FollowStatusApproved)}
+	if err := tx.Create(follow).Error; err != nil {
+		t.Fatalf("创建关注关系失败: %v", err)
+	}
+}
+
+// createTestFriend 创建已确认的好友关系
+func createTestFriend(t *testing.T, tx *gorm.DB, userID, targetID uint) {
+	t.Helper()
+	friend := &model.UserFriend{UserID: userID, TargetID: targetID, Status: int(constant.FriendStatusConfirmed)}
+	if err := NewUserFriendRepository(tx).CreateFriend(context.Background(), friend); err != nil {
+		t.Fatalf("创建好友关系失败: %v", err)
+	}
+}
+
+// postIDs 返回动态ID列表
+func postIDs(posts []model.Post) []uint {
+	ids := make([]uint, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"app/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run ../../cmd/repometrics -dir . -out metrics_gen.go
+
+// observe 记录一次仓库方法调用，由生成的指标装饰器在方法返回时调用
+// 记录不存在属于正常的查询结果，不计入错误次数
+func observe(repo, method string, start time.Time, errp *error) {
+	var err error
+	if errp != nil {
+		err = *errp
+	}
+	if errors.Is(err, ErrRecordNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	metrics.Repositories.Observe(repo, method, time.Since(start), err)
+}
@@ -0,0 +1,1465 @@
+// Code generated by go run ./cmd/repometrics; DO NOT EDIT.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"app/internal/model"
+)
+
+// apiClientRepositoryMetrics 记录APIClientRepository各方法调用指标的装饰器
+type apiClientRepositoryMetrics struct {
+	next APIClientRepository
+}
+
+// WithAPIClientRepositoryMetrics 包装APIClientRepository，记录各方法的调用次数、耗时和错误次数
+func WithAPIClientRepositoryMetrics(repo APIClientRepository) APIClientRepository {
+	return &apiClientRepositoryMetrics{next: repo}
+}
+
+func (r *apiClientRepositoryMetrics) Create(ctx context.Context, client *model.APIClient) (err error) {
+	defer observe("APIClientRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, client)
+}
+
+func (r *apiClientRepositoryMetrics) FindByID(ctx context.Context, id uint) (_ *model.APIClient, err error) {
+	defer observe("APIClientRepository", "FindByID", time.Now(), &err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *apiClientRepositoryMetrics) FindByAppKey(ctx context.Context, appKey string) (_ *model.APIClient, err error) {
+	defer observe("APIClientRepository", "FindByAppKey", time.Now(), &err)
+	return r.next.FindByAppKey(ctx, appKey)
+}
+
+func (r *apiClientRepositoryMetrics) UpdateLastUsedAt(ctx context.Context, id uint, usedAt time.Time) (err error) {
+	defer observe("APIClientRepository", "UpdateLastUsedAt", time.Now(), &err)
+	return r.next.UpdateLastUsedAt(ctx, id, usedAt)
+}
+
+// accountDeletionRepositoryMetrics 记录AccountDeletionRepository各方法调用指标的装饰器
+type accountDeletionRepositoryMetrics struct {
+	next AccountDeletionRepository
+}
+
+// WithAccountDeletionRepositoryMetrics 包装AccountDeletionRepository，记录各方法的调用次数、耗时和错误次数
+func WithAccountDeletionRepositoryMetrics(repo AccountDeletionRepository) AccountDeletionRepository {
+	return &accountDeletionRepositoryMetrics{next: repo}
+}
+
+func (r *accountDeletionRepositoryMetrics) Create(ctx context.Context, deletion *model.AccountDeletion) (err error) {
+	defer observe("AccountDeletionRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, deletion)
+}
+
+func (r *accountDeletionRepositoryMetrics) FindByUserID(ctx context.Context, userID uint) (_ *model.AccountDeletion, err error) {
+	defer observe("AccountDeletionRepository", "FindByUserID", time.Now(), &err)
+	return r.next.FindByUserID(ctx, userID)
+}
+
+func (r *accountDeletionRepositoryMetrics) GetRunnable(ctx context.Context, now time.Time, limit int) (_ []model.AccountDeletion, err error) {
+	defer observe("AccountDeletionRepository", "GetRunnable", time.Now(), &err)
+	return r.next.GetRunnable(ctx, now, limit)
+}
+
+func (r *accountDeletionRepositoryMetrics) Update(ctx context.Context, deletion *model.AccountDeletion) (err error) {
+	defer observe("AccountDeletionRepository", "Update", time.Now(), &err)
+	return r.next.Update(ctx, deletion)
+}
+
+// announcementRepositoryMetrics 记录AnnouncementRepository各方法调用指标的装饰器
+type announcementRepositoryMetrics struct {
+	next AnnouncementRepository
+}
+
+// WithAnnouncementRepositoryMetrics 包装AnnouncementRepository，记录各方法的调用次数、耗时和错误次数
+func WithAnnouncementRepositoryMetrics(repo AnnouncementRepository) AnnouncementRepository {
+	return &announcementRepositoryMetrics{next: repo}
+}
+
+func (r *announcementRepositoryMetrics) Create(ctx context.Context, announcement *model.Announcement) (err error) {
+	defer observe("AnnouncementRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, announcement)
+}
+
+func (r *announcementRepositoryMetrics) GetByID(ctx context.Context, id uint) (_ *model.Announcement, err error) {
+	defer observe("AnnouncementRepository", "GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *announcementRepositoryMetrics) List(ctx context.Context, page int, size int) (_ []model.Announcement, _ int64, err error) {
+	defer observe("AnnouncementRepository", "List", time.Now(), &err)
+	return r.next.List(ctx, page, size)
+}
+
+func (r *announcementRepositoryMetrics) Delete(ctx context.Context, id uint) (err error) {
+	defer observe("AnnouncementRepository", "Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *announcementRepositoryMetrics) FindPublished(ctx context.Context, now time.Time, limit int) (_ []model.Announcement, err error) {
+	defer observe("AnnouncementRepository", "FindPublished", time.Now(), &err)
+	return r.next.FindPublished(ctx, now, limit)
+}
+
+func (r *announcementRepositoryMetrics) FindTargetUserIDs(ctx context.Context, announcement *model.Announcement, afterID uint, limit int) (_ []uint, err error) {
+	defer observe("AnnouncementRepository", "FindTargetUserIDs", time.Now(), &err)
+	return r.next.FindTargetUserIDs(ctx, announcement, afterID, limit)
+}
+
+func (r *announcementRepositoryMetrics) MarkRead(ctx context.Context, userID uint, announcementIDs []uint) (_ int64, err error) {
+	defer observe("AnnouncementRepository", "MarkRead", time.Now(), &err)
+	return r.next.MarkRead(ctx, userID, announcementIDs)
+}
+
+func (r *announcementRepositoryMetrics) GetReadIDs(ctx context.Context, userID uint, announcementIDs []uint) (_ map[uint]bool, err error) {
+	defer observe("AnnouncementRepository", "GetReadIDs", time.Now(), &err)
+	return r.next.GetReadIDs(ctx, userID, announcementIDs)
+}
+
+func (r *announcementRepositoryMetrics) CountReads(ctx context.Context, announcementIDs []uint) (_ map[uint]int64, err error) {
+	defer observe("AnnouncementRepository", "CountReads", time.Now(), &err)
+	return r.next.CountReads(ctx, announcementIDs)
+}
+
+func (r *announcementRepositoryMetrics) DeleteReadsByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("AnnouncementRepository", "DeleteReadsByUser", time.Now(), &err)
+	return r.next.DeleteReadsByUser(ctx, userID)
+}
+
+// audienceListRepositoryMetrics 记录AudienceListRepository各方法调用指标的装饰器
+type audienceListRepositoryMetrics struct {
+	next AudienceListRepository
+}
+
+// WithAudienceListRepositoryMetrics 包装AudienceListRepository，记录各方法的调用次数、耗时和错误次数
+func WithAudienceListRepositoryMetrics(repo AudienceListRepository) AudienceListRepository {
+	return &audienceListRepositoryMetrics{next: repo}
+}
+
+func (r *audienceListRepositoryMetrics) CreateList(ctx context.Context, list *model.AudienceList) (err error) {
+	defer observe("AudienceListRepository", "CreateList", time.Now(), &err)
+	return r.next.CreateList(ctx, list)
+}
+
+func (r *audienceListRepositoryMetrics) GetList(ctx context.Context, id uint) (_ *model.AudienceList, err error) {
+	defer observe("AudienceListRepository", "GetList", time.Now(), &err)
+	return r.next.GetList(ctx, id)
+}
+
+func (r *audienceListRepositoryMetrics) GetUserLists(ctx context.Context, userID uint) (_ []model.AudienceList, err error) {
+	defer observe("AudienceListRepository", "GetUserLists", time.Now(), &err)
+	return r.next.GetUserLists(ctx, userID)
+}
+
+func (r *audienceListRepositoryMetrics) CountUserLists(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("AudienceListRepository", "CountUserLists", time.Now(), &err)
+	return r.next.CountUserLists(ctx, userID)
+}
+
+func (r *audienceListRepositoryMetrics) UpdateListName(ctx context.Context, id uint, name string) (err error) {
+	defer observe("AudienceListRepository", "UpdateListName", time.Now(), &err)
+	return r.next.UpdateListName(ctx, id, name)
+}
+
+func (r *audienceListRepositoryMetrics) DeleteList(ctx context.Context, id uint) (err error) {
+	defer observe("AudienceListRepository", "DeleteList", time.Now(), &err)
+	return r.next.DeleteList(ctx, id)
+}
+
+func (r *audienceListRepositoryMetrics) AddMembers(ctx context.Context, listID uint, memberIDs []uint) (_ int64, err error) {
+	defer observe("AudienceListRepository", "AddMembers", time.Now(), &err)
+	return r.next.AddMembers(ctx, listID, memberIDs)
+}
+
+func (r *audienceListRepositoryMetrics) RemoveMembers(ctx context.Context, listID uint, memberIDs []uint) (_ int64, err error) {
+	defer observe("AudienceListRepository", "RemoveMembers", time.Now(), &err)
+	return r.next.RemoveMembers(ctx, listID, memberIDs)
+}
+
+func (r *audienceListRepositoryMetrics) GetMembers(ctx context.Context, listID uint, page int, size int) (_ []model.AudienceListMember, _ int64, err error) {
+	defer observe("AudienceListRepository", "GetMembers", time.Now(), &err)
+	return r.next.GetMembers(ctx, listID, page, size)
+}
+
+func (r *audienceListRepositoryMetrics) CountMembers(ctx context.Context, listIDs []uint) (_ map[uint]int64, err error) {
+	defer observe("AudienceListRepository", "CountMembers", time.Now(), &err)
+	return r.next.CountMembers(ctx, listIDs)
+}
+
+func (r *audienceListRepositoryMetrics) IsMember(ctx context.Context, listID uint, memberID uint) (_ bool, err error) {
+	defer observe("AudienceListRepository", "IsMember", time.Now(), &err)
+	return r.next.IsMember(ctx, listID, memberID)
+}
+
+func (r *audienceListRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("AudienceListRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// commentImageRepositoryMetrics 记录CommentImageRepository各方法调用指标的装饰器
+type commentImageRepositoryMetrics struct {
+	next CommentImageRepository
+}
+
+// WithCommentImageRepositoryMetrics 包装CommentImageRepository，记录各方法的调用次数、耗时和错误次数
+func WithCommentImageRepositoryMetrics(repo CommentImageRepository) CommentImageRepository {
+	return &commentImageRepositoryMetrics{next: repo}
+}
+
+func (r *commentImageRepositoryMetrics) CreateCommentImage(ctx context.Context, image *model.CommentImage) (err error) {
+	defer observe("CommentImageRepository", "CreateCommentImage", time.Now(), &err)
+	return r.next.CreateCommentImage(ctx, image)
+}
+
+func (r *commentImageRepositoryMetrics) GetByCommentIDs(ctx context.Context, commentIDs []uint) (_ map[uint]model.CommentImage, err error) {
+	defer observe("CommentImageRepository", "GetByCommentIDs", time.Now(), &err)
+	return r.next.GetByCommentIDs(ctx, commentIDs)
+}
+
+func (r *commentImageRepositoryMetrics) GetPostCommentImagesWithDeleted(ctx context.Context, postID uint) (_ []model.CommentImage, err error) {
+	defer observe("CommentImageRepository", "GetPostCommentImagesWithDeleted", time.Now(), &err)
+	return r.next.GetPostCommentImagesWithDeleted(ctx, postID)
+}
+
+func (r *commentImageRepositoryMetrics) FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) (_ []model.CommentImage, err error) {
+	defer observe("CommentImageRepository", "FindExpiringURLs", time.Now(), &err)
+	return r.next.FindExpiringURLs(ctx, before, afterID, limit)
+}
+
+func (r *commentImageRepositoryMetrics) UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) (err error) {
+	defer observe("CommentImageRepository", "UpdateURL", time.Now(), &err)
+	return r.next.UpdateURL(ctx, id, url, presigned, expiresAt)
+}
+
+// commentLikeRepositoryMetrics 记录CommentLikeRepository各方法调用指标的装饰器
+type commentLikeRepositoryMetrics struct {
+	next CommentLikeRepository
+}
+
+// WithCommentLikeRepositoryMetrics 包装CommentLikeRepository，记录各方法的调用次数、耗时和错误次数
+func WithCommentLikeRepositoryMetrics(repo CommentLikeRepository) CommentLikeRepository {
+	return &commentLikeRepositoryMetrics{next: repo}
+}
+
+func (r *commentLikeRepositoryMetrics) LikeComment(ctx context.Context, commentID uint, userID uint) (_ bool, err error) {
+	defer observe("CommentLikeRepository", "LikeComment", time.Now(), &err)
+	return r.next.LikeComment(ctx, commentID, userID)
+}
+
+func (r *commentLikeRepositoryMetrics) UnlikeComment(ctx context.Context, commentID uint, userID uint) (_ bool, err error) {
+	defer observe("CommentLikeRepository", "UnlikeComment", time.Now(), &err)
+	return r.next.UnlikeComment(ctx, commentID, userID)
+}
+
+func (r *commentLikeRepositoryMetrics) GetLikedCommentIDs(ctx context.Context, userID uint, commentIDs []uint) (_ map[uint]bool, err error) {
+	defer observe("CommentLikeRepository", "GetLikedCommentIDs", time.Now(), &err)
+	return r.next.GetLikedCommentIDs(ctx, userID, commentIDs)
+}
+
+// counterRepositoryMetrics 记录CounterRepository各方法调用指标的装饰器
+type counterRepositoryMetrics struct {
+	next CounterRepository
+}
+
+// WithCounterRepositoryMetrics 包装CounterRepository，记录各方法的调用次数、耗时和错误次数
+func WithCounterRepositoryMetrics(repo CounterRepository) CounterRepository {
+	return &counterRepositoryMetrics{next: repo}
+}
+
+func (r *counterRepositoryMetrics) FindDrifts(ctx context.Context, counter Counter, afterID uint, limit int) (_ uint, _ int, _ []CounterDrift, err error) {
+	defer observe("CounterRepository", "FindDrifts", time.Now(), &err)
+	return r.next.FindDrifts(ctx, counter, afterID, limit)
+}
+
+func (r *counterRepositoryMetrics) FixCounts(ctx context.Context, counter Counter, ids []uint) (err error) {
+	defer observe("CounterRepository", "FixCounts", time.Now(), &err)
+	return r.next.FixCounts(ctx, counter, ids)
+}
+
+func (r *counterRepositoryMetrics) GetCheckpoint(ctx context.Context, name string) (_ string, err error) {
+	defer observe("CounterRepository", "GetCheckpoint", time.Now(), &err)
+	return r.next.GetCheckpoint(ctx, name)
+}
+
+func (r *counterRepositoryMetrics) ApplyPostCounterDeltas(ctx context.Context, name string, position string, deltas []PostCounterDelta) (err error) {
+	defer observe("CounterRepository", "ApplyPostCounterDeltas", time.Now(), &err)
+	return r.next.ApplyPostCounterDeltas(ctx, name, position, deltas)
+}
+
+// dataExportRepositoryMetrics 记录DataExportRepository各方法调用指标的装饰器
+type dataExportRepositoryMetrics struct {
+	next DataExportRepository
+}
+
+// WithDataExportRepositoryMetrics 包装DataExportRepository，记录各方法的调用次数、耗时和错误次数
+func WithDataExportRepositoryMetrics(repo DataExportRepository) DataExportRepository {
+	return &dataExportRepositoryMetrics{next: repo}
+}
+
+func (r *dataExportRepositoryMetrics) Create(ctx context.Context, export *model.DataExport) (err error) {
+	defer observe("DataExportRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, export)
+}
+
+func (r *dataExportRepositoryMetrics) FindByID(ctx context.Context, id uint) (_ *model.DataExport, err error) {
+	defer observe("DataExportRepository", "FindByID", time.Now(), &err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *dataExportRepositoryMetrics) FindActiveByUserID(ctx context.Context, userID uint) (_ *model.DataExport, err error) {
+	defer observe("DataExportRepository", "FindActiveByUserID", time.Now(), &err)
+	return r.next.FindActiveByUserID(ctx, userID)
+}
+
+func (r *dataExportRepositoryMetrics) GetPendingExports(ctx context.Context, limit int) (_ []model.DataExport, err error) {
+	defer observe("DataExportRepository", "GetPendingExports", time.Now(), &err)
+	return r.next.GetPendingExports(ctx, limit)
+}
+
+func (r *dataExportRepositoryMetrics) ClaimExport(ctx context.Context, id uint) (_ bool, err error) {
+	defer observe("DataExportRepository", "ClaimExport", time.Now(), &err)
+	return r.next.ClaimExport(ctx, id)
+}
+
+func (r *dataExportRepositoryMetrics) ReclaimStaleExports(ctx context.Context, before time.Time) (_ int64, err error) {
+	defer observe("DataExportRepository", "ReclaimStaleExports", time.Now(), &err)
+	return r.next.ReclaimStaleExports(ctx, before)
+}
+
+func (r *dataExportRepositoryMetrics) Update(ctx context.Context, export *model.DataExport) (err error) {
+	defer observe("DataExportRepository", "Update", time.Now(), &err)
+	return r.next.Update(ctx, export)
+}
+
+// deviceTokenRepositoryMetrics 记录DeviceTokenRepository各方法调用指标的装饰器
+type deviceTokenRepositoryMetrics struct {
+	next DeviceTokenRepository
+}
+
+// WithDeviceTokenRepositoryMetrics 包装DeviceTokenRepository，记录各方法的调用次数、耗时和错误次数
+func WithDeviceTokenRepositoryMetrics(repo DeviceTokenRepository) DeviceTokenRepository {
+	return &deviceTokenRepositoryMetrics{next: repo}
+}
+
+func (r *deviceTokenRepositoryMetrics) Save(ctx context.Context, token *model.DeviceToken) (err error) {
+	defer observe("DeviceTokenRepository", "Save", time.Now(), &err)
+	return r.next.Save(ctx, token)
+}
+
+func (r *deviceTokenRepositoryMetrics) GetUserTokens(ctx context.Context, userID uint) (_ []model.DeviceToken, err error) {
+	defer observe("DeviceTokenRepository", "GetUserTokens", time.Now(), &err)
+	return r.next.GetUserTokens(ctx, userID)
+}
+
+func (r *deviceTokenRepositoryMetrics) TrimUserTokens(ctx context.Context, userID uint, keep int) (_ int64, err error) {
+	defer observe("DeviceTokenRepository", "TrimUserTokens", time.Now(), &err)
+	return r.next.TrimUserTokens(ctx, userID, keep)
+}
+
+func (r *deviceTokenRepositoryMetrics) DeleteUserToken(ctx context.Context, userID uint, token string) (_ int64, err error) {
+	defer observe("DeviceTokenRepository", "DeleteUserToken", time.Now(), &err)
+	return r.next.DeleteUserToken(ctx, userID, token)
+}
+
+func (r *deviceTokenRepositoryMetrics) DeleteToken(ctx context.Context, token string) (err error) {
+	defer observe("DeviceTokenRepository", "DeleteToken", time.Now(), &err)
+	return r.next.DeleteToken(ctx, token)
+}
+
+func (r *deviceTokenRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("DeviceTokenRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// linkPreviewRepositoryMetrics 记录LinkPreviewRepository各方法调用指标的装饰器
+type linkPreviewRepositoryMetrics struct {
+	next LinkPreviewRepository
+}
+
+// WithLinkPreviewRepositoryMetrics 包装LinkPreviewRepository，记录各方法的调用次数、耗时和错误次数
+func WithLinkPreviewRepositoryMetrics(repo LinkPreviewRepository) LinkPreviewRepository {
+	return &linkPreviewRepositoryMetrics{next: repo}
+}
+
+func (r *linkPreviewRepositoryMetrics) FindOrCreate(ctx context.Context, urlHash string, url string) (_ *model.LinkPreview, err error) {
+	defer observe("LinkPreviewRepository", "FindOrCreate", time.Now(), &err)
+	return r.next.FindOrCreate(ctx, urlHash, url)
+}
+
+func (r *linkPreviewRepositoryMetrics) GetByIDs(ctx context.Context, ids []uint) (_ []model.LinkPreview, err error) {
+	defer observe("LinkPreviewRepository", "GetByIDs", time.Now(), &err)
+	return r.next.GetByIDs(ctx, ids)
+}
+
+func (r *linkPreviewRepositoryMetrics) UpdatePreview(ctx context.Context, preview *model.LinkPreview) (err error) {
+	defer observe("LinkPreviewRepository", "UpdatePreview", time.Now(), &err)
+	return r.next.UpdatePreview(ctx, preview)
+}
+
+// locationRepositoryMetrics 记录LocationRepository各方法调用指标的装饰器
+type locationRepositoryMetrics struct {
+	next LocationRepository
+}
+
+// WithLocationRepositoryMetrics 包装LocationRepository，记录各方法的调用次数、耗时和错误次数
+func WithLocationRepositoryMetrics(repo LocationRepository) LocationRepository {
+	return &locationRepositoryMetrics{next: repo}
+}
+
+func (r *locationRepositoryMetrics) CreateLocation(ctx context.Context, location *model.Location) (err error) {
+	defer observe("LocationRepository", "CreateLocation", time.Now(), &err)
+	return r.next.CreateLocation(ctx, location)
+}
+
+func (r *locationRepositoryMetrics) GetLocationsByIDs(ctx context.Context, ids []uint) (_ []model.Location, err error) {
+	defer observe("LocationRepository", "GetLocationsByIDs", time.Now(), &err)
+	return r.next.GetLocationsByIDs(ctx, ids)
+}
+
+func (r *locationRepositoryMetrics) UpdateAddress(ctx context.Context, id uint, address string) (err error) {
+	defer observe("LocationRepository", "UpdateAddress", time.Now(), &err)
+	return r.next.UpdateAddress(ctx, id, address)
+}
+
+// notificationRepositoryMetrics 记录NotificationRepository各方法调用指标的装饰器
+type notificationRepositoryMetrics struct {
+	next NotificationRepository
+}
+
+// WithNotificationRepositoryMetrics 包装NotificationRepository，记录各方法的调用次数、耗时和错误次数
+func WithNotificationRepositoryMetrics(repo NotificationRepository) NotificationRepository {
+	return &notificationRepositoryMetrics{next: repo}
+}
+
+func (r *notificationRepositoryMetrics) GetSetting(ctx context.Context, userID uint) (_ *model.NotificationSetting, err error) {
+	defer observe("NotificationRepository", "GetSetting", time.Now(), &err)
+	return r.next.GetSetting(ctx, userID)
+}
+
+func (r *notificationRepositoryMetrics) GetPreferences(ctx context.Context, userID uint) (_ []model.NotificationPreference, err error) {
+	defer observe("NotificationRepository", "GetPreferences", time.Now(), &err)
+	return r.next.GetPreferences(ctx, userID)
+}
+
+func (r *notificationRepositoryMetrics) Save(ctx context.Context, setting *model.NotificationSetting, preferences []model.NotificationPreference) (err error) {
+	defer observe("NotificationRepository", "Save", time.Now(), &err)
+	return r.next.Save(ctx, setting, preferences)
+}
+
+func (r *notificationRepositoryMetrics) DeleteByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("NotificationRepository", "DeleteByUser", time.Now(), &err)
+	return r.next.DeleteByUser(ctx, userID)
+}
+
+// postCommentRepositoryMetrics 记录PostCommentRepository各方法调用指标的装饰器
+type postCommentRepositoryMetrics struct {
+	next PostCommentRepository
+}
+
+// WithPostCommentRepositoryMetrics 包装PostCommentRepository，记录各方法的调用次数、耗时和错误次数
+func WithPostCommentRepositoryMetrics(repo PostCommentRepository) PostCommentRepository {
+	return &postCommentRepositoryMetrics{next: repo}
+}
+
+func (r *postCommentRepositoryMetrics) CreateComment(ctx context.Context, comment *model.PostComment) (err error) {
+	defer observe("PostCommentRepository", "CreateComment", time.Now(), &err)
+	return r.next.CreateComment(ctx, comment)
+}
+
+func (r *postCommentRepositoryMetrics) GetComment(ctx context.Context, id uint) (_ *model.PostComment, err error) {
+	defer observe("PostCommentRepository", "GetComment", time.Now(), &err)
+	return r.next.GetComment(ctx, id)
+}
+
+func (r *postCommentRepositoryMetrics) GetPostComments(ctx context.Context, postID uint, page int, size int, sort string) (_ []model.PostComment, _ int64, err error) {
+	defer observe("PostCommentRepository", "GetPostComments", time.Now(), &err)
+	return r.next.GetPostComments(ctx, postID, page, size, sort)
+}
+
+func (r *postCommentRepositoryMetrics) GetCommentReplies(ctx context.Context, parentID uint, page int, size int) (_ []model.PostComment, _ int64, err error) {
+	defer observe("PostCommentRepository", "GetCommentReplies", time.Now(), &err)
+	return r.next.GetCommentReplies(ctx, parentID, page, size)
+}
+
+func (r *postCommentRepositoryMetrics) CountReplies(ctx context.Context, parentIDs []uint) (_ map[uint]int64, err error) {
+	defer observe("PostCommentRepository", "CountReplies", time.Now(), &err)
+	return r.next.CountReplies(ctx, parentIDs)
+}
+
+func (r *postCommentRepositoryMetrics) GetUserComments(ctx context.Context, userID uint, page int, size int) (_ []model.PostComment, _ int64, err error) {
+	defer observe("PostCommentRepository", "GetUserComments", time.Now(), &err)
+	return r.next.GetUserComments(ctx, userID, page, size)
+}
+
+func (r *postCommentRepositoryMetrics) CountCommentsByAuthor(ctx context.Context, userID uint, since time.Time) (_ map[uint]int64, err error) {
+	defer observe("PostCommentRepository", "CountCommentsByAuthor", time.Now(), &err)
+	return r.next.CountCommentsByAuthor(ctx, userID, since)
+}
+
+func (r *postCommentRepositoryMetrics) AnonymizeUserComments(ctx context.Context, userID uint, limit int) (_ int64, err error) {
+	defer observe("PostCommentRepository", "AnonymizeUserComments", time.Now(), &err)
+	return r.next.AnonymizeUserComments(ctx, userID, limit)
+}
+
+// postExclusionRepositoryMetrics 记录PostExclusionRepository各方法调用指标的装饰器
+type postExclusionRepositoryMetrics struct {
+	next PostExclusionRepository
+}
+
+// WithPostExclusionRepositoryMetrics 包装PostExclusionRepository，记录各方法的调用次数、耗时和错误次数
+func WithPostExclusionRepositoryMetrics(repo PostExclusionRepository) PostExclusionRepository {
+	return &postExclusionRepositoryMetrics{next: repo}
+}
+
+func (r *postExclusionRepositoryMetrics) AddExclusions(ctx context.Context, userID uint, targetIDs []uint) (_ int64, err error) {
+	defer observe("PostExclusionRepository", "AddExclusions", time.Now(), &err)
+	return r.next.AddExclusions(ctx, userID, targetIDs)
+}
+
+func (r *postExclusionRepositoryMetrics) RemoveExclusions(ctx context.Context, userID uint, targetIDs []uint) (_ int64, err error) {
+	defer observe("PostExclusionRepository", "RemoveExclusions", time.Now(), &err)
+	return r.next.RemoveExclusions(ctx, userID, targetIDs)
+}
+
+func (r *postExclusionRepositoryMetrics) GetExclusions(ctx context.Context, userID uint, page int, size int) (_ []model.PostExclusion, _ int64, err error) {
+	defer observe("PostExclusionRepository", "GetExclusions", time.Now(), &err)
+	return r.next.GetExclusions(ctx, userID, page, size)
+}
+
+func (r *postExclusionRepositoryMetrics) CountExclusions(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("PostExclusionRepository", "CountExclusions", time.Now(), &err)
+	return r.next.CountExclusions(ctx, userID)
+}
+
+func (r *postExclusionRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("PostExclusionRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// postImageRepositoryMetrics 记录PostImageRepository各方法调用指标的装饰器
+type postImageRepositoryMetrics struct {
+	next PostImageRepository
+}
+
+// WithPostImageRepositoryMetrics 包装PostImageRepository，记录各方法的调用次数、耗时和错误次数
+func WithPostImageRepositoryMetrics(repo PostImageRepository) PostImageRepository {
+	return &postImageRepositoryMetrics{next: repo}
+}
+
+func (r *postImageRepositoryMetrics) CreatePostImage(ctx context.Context, image *model.PostImage) (err error) {
+	defer observe("PostImageRepository", "CreatePostImage", time.Now(), &err)
+	return r.next.CreatePostImage(ctx, image)
+}
+
+func (r *postImageRepositoryMetrics) GetPostImages(ctx context.Context, postID uint) (_ []model.PostImage, err error) {
+	defer observe("PostImageRepository", "GetPostImages", time.Now(), &err)
+	return r.next.GetPostImages(ctx, postID)
+}
+
+func (r *postImageRepositoryMetrics) GetPostImagesWithDeleted(ctx context.Context, postID uint) (_ []model.PostImage, err error) {
+	defer observe("PostImageRepository", "GetPostImagesWithDeleted", time.Now(), &err)
+	return r.next.GetPostImagesWithDeleted(ctx, postID)
+}
+
+func (r *postImageRepositoryMetrics) GetPostImagesByPostIDs(ctx context.Context, postIDs []uint) (_ []model.PostImage, err error) {
+	defer observe("PostImageRepository", "GetPostImagesByPostIDs", time.Now(), &err)
+	return r.next.GetPostImagesByPostIDs(ctx, postIDs)
+}
+
+func (r *postImageRepositoryMetrics) GetPostImagesByIDs(ctx context.Context, ids []uint) (_ []model.PostImage, err error) {
+	defer observe("PostImageRepository", "GetPostImagesByIDs", time.Now(), &err)
+	return r.next.GetPostImagesByIDs(ctx, ids)
+}
+
+func (r *postImageRepositoryMetrics) DeletePostImage(ctx context.Context, id uint) (err error) {
+	defer observe("PostImageRepository", "DeletePostImage", time.Now(), &err)
+	return r.next.DeletePostImage(ctx, id)
+}
+
+func (r *postImageRepositoryMetrics) DeletePostImages(ctx context.Context, postID uint) (err error) {
+	defer observe("PostImageRepository", "DeletePostImages", time.Now(), &err)
+	return r.next.DeletePostImages(ctx, postID)
+}
+
+func (r *postImageRepositoryMetrics) FindByID(ctx context.Context, id uint) (_ *model.PostImage, err error) {
+	defer observe("PostImageRepository", "FindByID", time.Now(), &err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *postImageRepositoryMetrics) UpdatePostImage(ctx context.Context, image *model.PostImage) (err error) {
+	defer observe("PostImageRepository", "UpdatePostImage", time.Now(), &err)
+	return r.next.UpdatePostImage(ctx, image)
+}
+
+func (r *postImageRepositoryMetrics) FindByUserAndHash(ctx context.Context, userID uint, hash string) (_ *model.PostImage, err error) {
+	defer observe("PostImageRepository", "FindByUserAndHash", time.Now(), &err)
+	return r.next.FindByUserAndHash(ctx, userID, hash)
+}
+
+func (r *postImageRepositoryMetrics) FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) (_ []model.PostImage, err error) {
+	defer observe("PostImageRepository", "FindExpiringURLs", time.Now(), &err)
+	return r.next.FindExpiringURLs(ctx, before, afterID, limit)
+}
+
+func (r *postImageRepositoryMetrics) UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) (err error) {
+	defer observe("PostImageRepository", "UpdateURL", time.Now(), &err)
+	return r.next.UpdateURL(ctx, id, url, presigned, expiresAt)
+}
+
+// postRepositoryMetrics 记录PostRepository各方法调用指标的装饰器
+type postRepositoryMetrics struct {
+	next PostRepository
+}
+
+// WithPostRepositoryMetrics 包装PostRepository，记录各方法的调用次数、耗时和错误次数
+func WithPostRepositoryMetrics(repo PostRepository) PostRepository {
+	return &postRepositoryMetrics{next: repo}
+}
+
+func (r *postRepositoryMetrics) GetPost(ctx context.Context, id uint) (_ *model.Post, err error) {
+	defer observe("PostRepository", "GetPost", time.Now(), &err)
+	return r.next.GetPost(ctx, id)
+}
+
+func (r *postRepositoryMetrics) GetVisiblePost(ctx context.Context, id uint, viewerID uint) (_ *model.Post, err error) {
+	defer observe("PostRepository", "GetVisiblePost", time.Now(), &err)
+	return r.next.GetVisiblePost(ctx, id, viewerID)
+}
+
+func (r *postRepositoryMetrics) GetUserPosts(ctx context.Context, userID uint, page int, size int, viewerID ...uint) (_ []model.Post, _ int64, err error) {
+	defer observe("PostRepository", "GetUserPosts", time.Now(), &err)
+	return r.next.GetUserPosts(ctx, userID, page, size, viewerID...)
+}
+
+func (r *postRepositoryMetrics) GetFollowingPosts(ctx context.Context, userID uint, page int, size int) (_ []model.Post, _ int64, err error) {
+	defer observe("PostRepository", "GetFollowingPosts", time.Now(), &err)
+	return r.next.GetFollowingPosts(ctx, userID, page, size)
+}
+
+func (r *postRepositoryMetrics) GetPostsByIDs(ctx context.Context, ids []uint, viewerID ...uint) (_ []model.Post, err error) {
+	defer observe("PostRepository", "GetPostsByIDs", time.Now(), &err)
+	return r.next.GetPostsByIDs(ctx, ids, viewerID...)
+}
+
+func (r *postRepositoryMetrics) GetNearbyPosts(ctx context.Context, viewerID uint, box GeoBox, since time.Time, limit int) (_ []model.Post, err error) {
+	defer observe("PostRepository", "GetNearbyPosts", time.Now(), &err)
+	return r.next.GetNearbyPosts(ctx, viewerID, box, since, limit)
+}
+
+func (r *postRepositoryMetrics) GetNearbyClusters(ctx context.Context, viewerID uint, box GeoBox, prefixes []string, precision int, since time.Time, limit int) (_ []GeoCluster, err error) {
+	defer observe("PostRepository", "GetNearbyClusters", time.Now(), &err)
+	return r.next.GetNearbyClusters(ctx, viewerID, box, prefixes, precision, since, limit)
+}
+
+func (r *postRepositoryMetrics) GetPopularPosts(ctx context.Context, viewerID uint, since time.Time, limit int) (_ []model.Post, err error) {
+	defer observe("PostRepository", "GetPopularPosts", time.Now(), &err)
+	return r.next.GetPopularPosts(ctx, viewerID, since, limit)
+}
+
+func (r *postRepositoryMetrics) GetTrashedPosts(ctx context.Context, userID uint, since time.Time, page int, size int) (_ []model.Post, _ int64, err error) {
+	defer observe("PostRepository", "GetTrashedPosts", time.Now(), &err)
+	return r.next.GetTrashedPosts(ctx, userID, since, page, size)
+}
+
+func (r *postRepositoryMetrics) GetTrashedPost(ctx context.Context, id uint) (_ *model.Post, err error) {
+	defer observe("PostRepository", "GetTrashedPost", time.Now(), &err)
+	return r.next.GetTrashedPost(ctx, id)
+}
+
+func (r *postRepositoryMetrics) GetExpiredTrashedPosts(ctx context.Context, before time.Time, limit int) (_ []model.Post, err error) {
+	defer observe("PostRepository", "GetExpiredTrashedPosts", time.Now(), &err)
+	return r.next.GetExpiredTrashedPosts(ctx, before, limit)
+}
+
+func (r *postRepositoryMetrics) CreatePost(ctx context.Context, post *model.Post) (err error) {
+	defer observe("PostRepository", "CreatePost", time.Now(), &err)
+	return r.next.CreatePost(ctx, post)
+}
+
+func (r *postRepositoryMetrics) CreatePostWithImages(ctx context.Context, post *model.Post, attach func(postID uint) ([]model.PostImage, error)) (err error) {
+	defer observe("PostRepository", "CreatePostWithImages", time.Now(), &err)
+	return r.next.CreatePostWithImages(ctx, post, attach)
+}
+
+func (r *postRepositoryMetrics) UpdatePost(ctx context.Context, post *model.Post) (err error) {
+	defer observe("PostRepository", "UpdatePost", time.Now(), &err)
+	return r.next.UpdatePost(ctx, post)
+}
+
+func (r *postRepositoryMetrics) DeletePost(ctx context.Context, id uint) (err error) {
+	defer observe("PostRepository", "DeletePost", time.Now(), &err)
+	return r.next.DeletePost(ctx, id)
+}
+
+func (r *postRepositoryMetrics) AddPostViews(ctx context.Context, postID uint, views int64) (err error) {
+	defer observe("PostRepository", "AddPostViews", time.Now(), &err)
+	return r.next.AddPostViews(ctx, postID, views)
+}
+
+func (r *postRepositoryMetrics) RestorePost(ctx context.Context, id uint) (err error) {
+	defer observe("PostRepository", "RestorePost", time.Now(), &err)
+	return r.next.RestorePost(ctx, id)
+}
+
+func (r *postRepositoryMetrics) PurgePost(ctx context.Context, id uint) (err error) {
+	defer observe("PostRepository", "PurgePost", time.Now(), &err)
+	return r.next.PurgePost(ctx, id)
+}
+
+// postRevisionRepositoryMetrics 记录PostRevisionRepository各方法调用指标的装饰器
+type postRevisionRepositoryMetrics struct {
+	next PostRevisionRepository
+}
+
+// WithPostRevisionRepositoryMetrics 包装PostRevisionRepository，记录各方法的调用次数、耗时和错误次数
+func WithPostRevisionRepositoryMetrics(repo PostRevisionRepository) PostRevisionRepository {
+	return &postRevisionRepositoryMetrics{next: repo}
+}
+
+func (r *postRevisionRepositoryMetrics) GetPostRevisions(ctx context.Context, postID uint, page int, size int) (_ []model.PostRevision, _ int64, err error) {
+	defer observe("PostRevisionRepository", "GetPostRevisions", time.Now(), &err)
+	return r.next.GetPostRevisions(ctx, postID, page, size)
+}
+
+func (r *postRevisionRepositoryMetrics) UpdatePostWithRevision(ctx context.Context, post *model.Post, revision *model.PostRevision, removedImageIDs []uint) (err error) {
+	defer observe("PostRevisionRepository", "UpdatePostWithRevision", time.Now(), &err)
+	return r.next.UpdatePostWithRevision(ctx, post, revision, removedImageIDs)
+}
+
+// postStatsRepositoryMetrics 记录PostStatsRepository各方法调用指标的装饰器
+type postStatsRepositoryMetrics struct {
+	next PostStatsRepository
+}
+
+// WithPostStatsRepositoryMetrics 包装PostStatsRepository，记录各方法的调用次数、耗时和错误次数
+func WithPostStatsRepositoryMetrics(repo PostStatsRepository) PostStatsRepository {
+	return &postStatsRepositoryMetrics{next: repo}
+}
+
+func (r *postStatsRepositoryMetrics) SavePostStats(ctx context.Context, stats []model.PostStats) (err error) {
+	defer observe("PostStatsRepository", "SavePostStats", time.Now(), &err)
+	return r.next.SavePostStats(ctx, stats)
+}
+
+func (r *postStatsRepositoryMetrics) GetUserPostStats(ctx context.Context, userID uint, postID uint, startDate time.Time, endDate time.Time) (_ []model.PostStats, err error) {
+	defer observe("PostStatsRepository", "GetUserPostStats", time.Now(), &err)
+	return r.next.GetUserPostStats(ctx, userID, postID, startDate, endDate)
+}
+
+// profileVisitRepositoryMetrics 记录ProfileVisitRepository各方法调用指标的装饰器
+type profileVisitRepositoryMetrics struct {
+	next ProfileVisitRepository
+}
+
+// WithProfileVisitRepositoryMetrics 包装ProfileVisitRepository，记录各方法的调用次数、耗时和错误次数
+func WithProfileVisitRepositoryMetrics(repo ProfileVisitRepository) ProfileVisitRepository {
+	return &profileVisitRepositoryMetrics{next: repo}
+}
+
+func (r *profileVisitRepositoryMetrics) Create(ctx context.Context, visit *model.ProfileVisit) (err error) {
+	defer observe("ProfileVisitRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, visit)
+}
+
+func (r *profileVisitRepositoryMetrics) GetVisitors(ctx context.Context, visiteeID uint, since time.Time, page int, size int) (_ []VisitSummary, _ int64, err error) {
+	defer observe("ProfileVisitRepository", "GetVisitors", time.Now(), &err)
+	return r.next.GetVisitors(ctx, visiteeID, since, page, size)
+}
+
+func (r *profileVisitRepositoryMetrics) GetVisited(ctx context.Context, visitorID uint, since time.Time, page int, size int) (_ []VisitSummary, _ int64, err error) {
+	defer observe("ProfileVisitRepository", "GetVisited", time.Now(), &err)
+	return r.next.GetVisited(ctx, visitorID, since, page, size)
+}
+
+func (r *profileVisitRepositoryMetrics) DeleteBefore(ctx context.Context, before time.Time, limit int) (_ int64, err error) {
+	defer observe("ProfileVisitRepository", "DeleteBefore", time.Now(), &err)
+	return r.next.DeleteBefore(ctx, before, limit)
+}
+
+func (r *profileVisitRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("ProfileVisitRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// publicIDRepositoryMetrics 记录PublicIDRepository各方法调用指标的装饰器
+type publicIDRepositoryMetrics struct {
+	next PublicIDRepository
+}
+
+// WithPublicIDRepositoryMetrics 包装PublicIDRepository，记录各方法的调用次数、耗时和错误次数
+func WithPublicIDRepositoryMetrics(repo PublicIDRepository) PublicIDRepository {
+	return &publicIDRepositoryMetrics{next: repo}
+}
+
+func (r *publicIDRepositoryMetrics) FindIDs(ctx context.Context, table PublicIDTable, publicIDs []string) (_ map[string]uint, err error) {
+	defer observe("PublicIDRepository", "FindIDs", time.Now(), &err)
+	return r.next.FindIDs(ctx, table, publicIDs)
+}
+
+func (r *publicIDRepositoryMetrics) FindPublicIDs(ctx context.Context, table PublicIDTable, ids []uint) (_ map[uint]string, err error) {
+	defer observe("PublicIDRepository", "FindPublicIDs", time.Now(), &err)
+	return r.next.FindPublicIDs(ctx, table, ids)
+}
+
+// smsRepositoryMetrics 记录SMSRepository各方法调用指标的装饰器
+type smsRepositoryMetrics struct {
+	next SMSRepository
+}
+
+// WithSMSRepositoryMetrics 包装SMSRepository，记录各方法的调用次数、耗时和错误次数
+func WithSMSRepositoryMetrics(repo SMSRepository) SMSRepository {
+	return &smsRepositoryMetrics{next: repo}
+}
+
+func (r *smsRepositoryMetrics) Create(ctx context.Context, record *model.SMSRecord) (err error) {
+	defer observe("SMSRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, record)
+}
+
+func (r *smsRepositoryMetrics) FindByPhoneNumber(ctx context.Context, phoneNumber string, limit int) (_ []*model.SMSRecord, err error) {
+	defer observe("SMSRepository", "FindByPhoneNumber", time.Now(), &err)
+	return r.next.FindByPhoneNumber(ctx, phoneNumber, limit)
+}
+
+func (r *smsRepositoryMetrics) FindByID(ctx context.Context, id uint) (_ *model.SMSRecord, err error) {
+	defer observe("SMSRepository", "FindByID", time.Now(), &err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *smsRepositoryMetrics) Update(ctx context.Context, record *model.SMSRecord) (err error) {
+	defer observe("SMSRepository", "Update", time.Now(), &err)
+	return r.next.Update(ctx, record)
+}
+
+func (r *smsRepositoryMetrics) ScrubByPhoneNumber(ctx context.Context, phoneNumber string) (_ int64, err error) {
+	defer observe("SMSRepository", "ScrubByPhoneNumber", time.Now(), &err)
+	return r.next.ScrubByPhoneNumber(ctx, phoneNumber)
+}
+
+// statisticsRepositoryMetrics 记录StatisticsRepository各方法调用指标的装饰器
+type statisticsRepositoryMetrics struct {
+	next StatisticsRepository
+}
+
+// WithStatisticsRepositoryMetrics 包装StatisticsRepository，记录各方法的调用次数、耗时和错误次数
+func WithStatisticsRepositoryMetrics(repo StatisticsRepository) StatisticsRepository {
+	return &statisticsRepositoryMetrics{next: repo}
+}
+
+func (r *statisticsRepositoryMetrics) CountUsers(ctx context.Context, end time.Time) (_ int64, err error) {
+	defer observe("StatisticsRepository", "CountUsers", time.Now(), &err)
+	return r.next.CountUsers(ctx, end)
+}
+
+func (r *statisticsRepositoryMetrics) CountNewUsers(ctx context.Context, start time.Time, end time.Time) (_ int64, err error) {
+	defer observe("StatisticsRepository", "CountNewUsers", time.Now(), &err)
+	return r.next.CountNewUsers(ctx, start, end)
+}
+
+func (r *statisticsRepositoryMetrics) CountPosts(ctx context.Context, start time.Time, end time.Time) (_ int64, err error) {
+	defer observe("StatisticsRepository", "CountPosts", time.Now(), &err)
+	return r.next.CountPosts(ctx, start, end)
+}
+
+func (r *statisticsRepositoryMetrics) CountComments(ctx context.Context, start time.Time, end time.Time) (_ int64, err error) {
+	defer observe("StatisticsRepository", "CountComments", time.Now(), &err)
+	return r.next.CountComments(ctx, start, end)
+}
+
+func (r *statisticsRepositoryMetrics) CountSuccessSMS(ctx context.Context, start time.Time, end time.Time) (_ int64, err error) {
+	defer observe("StatisticsRepository", "CountSuccessSMS", time.Now(), &err)
+	return r.next.CountSuccessSMS(ctx, start, end)
+}
+
+func (r *statisticsRepositoryMetrics) SumStorageBytes(ctx context.Context) (_ int64, err error) {
+	defer observe("StatisticsRepository", "SumStorageBytes", time.Now(), &err)
+	return r.next.SumStorageBytes(ctx)
+}
+
+func (r *statisticsRepositoryMetrics) SaveDailyStatistics(ctx context.Context, stats *model.DailyStatistics) (err error) {
+	defer observe("StatisticsRepository", "SaveDailyStatistics", time.Now(), &err)
+	return r.next.SaveDailyStatistics(ctx, stats)
+}
+
+func (r *statisticsRepositoryMetrics) GetDailyStatistics(ctx context.Context, startDate time.Time, endDate time.Time) (_ []model.DailyStatistics, err error) {
+	defer observe("StatisticsRepository", "GetDailyStatistics", time.Now(), &err)
+	return r.next.GetDailyStatistics(ctx, startDate, endDate)
+}
+
+// tagRepositoryMetrics 记录TagRepository各方法调用指标的装饰器
+type tagRepositoryMetrics struct {
+	next TagRepository
+}
+
+// WithTagRepositoryMetrics 包装TagRepository，记录各方法的调用次数、耗时和错误次数
+func WithTagRepositoryMetrics(repo TagRepository) TagRepository {
+	return &tagRepositoryMetrics{next: repo}
+}
+
+func (r *tagRepositoryMetrics) Create(ctx context.Context, tag *model.Tag) (err error) {
+	defer observe("TagRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, tag)
+}
+
+func (r *tagRepositoryMetrics) GetByID(ctx context.Context, id uint) (_ *model.Tag, err error) {
+	defer observe("TagRepository", "GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *tagRepositoryMetrics) FindByNames(ctx context.Context, names []string) (_ []model.Tag, err error) {
+	defer observe("TagRepository", "FindByNames", time.Now(), &err)
+	return r.next.FindByNames(ctx, names)
+}
+
+func (r *tagRepositoryMetrics) List(ctx context.Context) (_ []model.Tag, err error) {
+	defer observe("TagRepository", "List", time.Now(), &err)
+	return r.next.List(ctx)
+}
+
+func (r *tagRepositoryMetrics) FindRuleTags(ctx context.Context) (_ []model.Tag, err error) {
+	defer observe("TagRepository", "FindRuleTags", time.Now(), &err)
+	return r.next.FindRuleTags(ctx)
+}
+
+func (r *tagRepositoryMetrics) Update(ctx context.Context, tag *model.Tag) (err error) {
+	defer observe("TagRepository", "Update", time.Now(), &err)
+	return r.next.Update(ctx, tag)
+}
+
+func (r *tagRepositoryMetrics) Delete(ctx context.Context, id uint) (err error) {
+	defer observe("TagRepository", "Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *tagRepositoryMetrics) AddMembers(ctx context.Context, tagID uint, userIDs []uint) (_ int64, err error) {
+	defer observe("TagRepository", "AddMembers", time.Now(), &err)
+	return r.next.AddMembers(ctx, tagID, userIDs)
+}
+
+func (r *tagRepositoryMetrics) RemoveMembers(ctx context.Context, tagID uint, userIDs []uint) (_ int64, err error) {
+	defer observe("TagRepository", "RemoveMembers", time.Now(), &err)
+	return r.next.RemoveMembers(ctx, tagID, userIDs)
+}
+
+func (r *tagRepositoryMetrics) GetMembers(ctx context.Context, tagID uint, page int, size int) (_ []model.UserTag, _ int64, err error) {
+	defer observe("TagRepository", "GetMembers", time.Now(), &err)
+	return r.next.GetMembers(ctx, tagID, page, size)
+}
+
+func (r *tagRepositoryMetrics) GetMemberIDs(ctx context.Context, tagName string, afterID uint, limit int) (_ []uint, err error) {
+	defer observe("TagRepository", "GetMemberIDs", time.Now(), &err)
+	return r.next.GetMemberIDs(ctx, tagName, afterID, limit)
+}
+
+func (r *tagRepositoryMetrics) GetUserTags(ctx context.Context, userID uint) (_ []model.Tag, err error) {
+	defer observe("TagRepository", "GetUserTags", time.Now(), &err)
+	return r.next.GetUserTags(ctx, userID)
+}
+
+func (r *tagRepositoryMetrics) RefreshMemberCount(ctx context.Context, tagID uint) (err error) {
+	defer observe("TagRepository", "RefreshMemberCount", time.Now(), &err)
+	return r.next.RefreshMemberCount(ctx, tagID)
+}
+
+func (r *tagRepositoryMetrics) FindRuleMatches(ctx context.Context, tag *model.Tag, now time.Time, afterID uint, limit int) (_ []uint, err error) {
+	defer observe("TagRepository", "FindRuleMatches", time.Now(), &err)
+	return r.next.FindRuleMatches(ctx, tag, now, afterID, limit)
+}
+
+func (r *tagRepositoryMetrics) UpsertRuleMembers(ctx context.Context, tagID uint, userIDs []uint, refreshedAt time.Time) (err error) {
+	defer observe("TagRepository", "UpsertRuleMembers", time.Now(), &err)
+	return r.next.UpsertRuleMembers(ctx, tagID, userIDs, refreshedAt)
+}
+
+func (r *tagRepositoryMetrics) FinishRefresh(ctx context.Context, tagID uint, refreshedAt time.Time) (_ int64, err error) {
+	defer observe("TagRepository", "FinishRefresh", time.Now(), &err)
+	return r.next.FinishRefresh(ctx, tagID, refreshedAt)
+}
+
+func (r *tagRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("TagRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// tempImageRepositoryMetrics 记录TempImageRepository各方法调用指标的装饰器
+type tempImageRepositoryMetrics struct {
+	next TempImageRepository
+}
+
+// WithTempImageRepositoryMetrics 包装TempImageRepository，记录各方法的调用次数、耗时和错误次数
+func WithTempImageRepositoryMetrics(repo TempImageRepository) TempImageRepository {
+	return &tempImageRepositoryMetrics{next: repo}
+}
+
+func (r *tempImageRepositoryMetrics) CreateTempImage(ctx context.Context, image *model.TempImage) (err error) {
+	defer observe("TempImageRepository", "CreateTempImage", time.Now(), &err)
+	return r.next.CreateTempImage(ctx, image)
+}
+
+func (r *tempImageRepositoryMetrics) FindByID(ctx context.Context, id uint) (_ *model.TempImage, err error) {
+	defer observe("TempImageRepository", "FindByID", time.Now(), &err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *tempImageRepositoryMetrics) UpdateTempImage(ctx context.Context, image *model.TempImage) (err error) {
+	defer observe("TempImageRepository", "UpdateTempImage", time.Now(), &err)
+	return r.next.UpdateTempImage(ctx, image)
+}
+
+func (r *tempImageRepositoryMetrics) DeleteTempImage(ctx context.Context, id uint) (err error) {
+	defer observe("TempImageRepository", "DeleteTempImage", time.Now(), &err)
+	return r.next.DeleteTempImage(ctx, id)
+}
+
+func (r *tempImageRepositoryMetrics) GetUserTempImages(ctx context.Context, userID uint) (_ []model.TempImage, err error) {
+	defer observe("TempImageRepository", "GetUserTempImages", time.Now(), &err)
+	return r.next.GetUserTempImages(ctx, userID)
+}
+
+func (r *tempImageRepositoryMetrics) GetUserTempImagesBefore(ctx context.Context, userID uint, before time.Time) (_ []model.TempImage, err error) {
+	defer observe("TempImageRepository", "GetUserTempImagesBefore", time.Now(), &err)
+	return r.next.GetUserTempImagesBefore(ctx, userID, before)
+}
+
+func (r *tempImageRepositoryMetrics) FindByUserAndHash(ctx context.Context, userID uint, hash string) (_ *model.TempImage, err error) {
+	defer observe("TempImageRepository", "FindByUserAndHash", time.Now(), &err)
+	return r.next.FindByUserAndHash(ctx, userID, hash)
+}
+
+func (r *tempImageRepositoryMetrics) FindByObjectKey(ctx context.Context, objectKey string) (_ *model.TempImage, err error) {
+	defer observe("TempImageRepository", "FindByObjectKey", time.Now(), &err)
+	return r.next.FindByObjectKey(ctx, objectKey)
+}
+
+func (r *tempImageRepositoryMetrics) FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) (_ []model.TempImage, err error) {
+	defer observe("TempImageRepository", "FindExpiringURLs", time.Now(), &err)
+	return r.next.FindExpiringURLs(ctx, before, afterID, limit)
+}
+
+func (r *tempImageRepositoryMetrics) UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) (err error) {
+	defer observe("TempImageRepository", "UpdateURL", time.Now(), &err)
+	return r.next.UpdateURL(ctx, id, url, presigned, expiresAt)
+}
+
+// userActivityRepositoryMetrics 记录UserActivityRepository各方法调用指标的装饰器
+type userActivityRepositoryMetrics struct {
+	next UserActivityRepository
+}
+
+// WithUserActivityRepositoryMetrics 包装UserActivityRepository，记录各方法的调用次数、耗时和错误次数
+func WithUserActivityRepositoryMetrics(repo UserActivityRepository) UserActivityRepository {
+	return &userActivityRepositoryMetrics{next: repo}
+}
+
+func (r *userActivityRepositoryMetrics) Create(ctx context.Context, activity *model.UserActivity) (err error) {
+	defer observe("UserActivityRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, activity)
+}
+
+func (r *userActivityRepositoryMetrics) GetUserActivities(ctx context.Context, userID uint, page int, size int) (_ []model.UserActivity, _ int64, err error) {
+	defer observe("UserActivityRepository", "GetUserActivities", time.Now(), &err)
+	return r.next.GetUserActivities(ctx, userID, page, size)
+}
+
+func (r *userActivityRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("UserActivityRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// userFollowerRepositoryMetrics 记录UserFollowerRepository各方法调用指标的装饰器
+type userFollowerRepositoryMetrics struct {
+	next UserFollowerRepository
+}
+
+// WithUserFollowerRepositoryMetrics 包装UserFollowerRepository，记录各方法的调用次数、耗时和错误次数
+func WithUserFollowerRepositoryMetrics(repo UserFollowerRepository) UserFollowerRepository {
+	return &userFollowerRepositoryMetrics{next: repo}
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollower(ctx context.Context, userID uint, targetID uint) (_ *model.UserFollower, err error) {
+	defer observe("UserFollowerRepository", "GetFollower", time.Now(), &err)
+	return r.next.GetFollower(ctx, userID, targetID)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowerByID(ctx context.Context, id uint) (_ *model.UserFollower, err error) {
+	defer observe("UserFollowerRepository", "GetFollowerByID", time.Now(), &err)
+	return r.next.GetFollowerByID(ctx, id)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowers(ctx context.Context, userID uint, page int, size int) (_ []model.UserFollower, _ int64, err error) {
+	defer observe("UserFollowerRepository", "GetFollowers", time.Now(), &err)
+	return r.next.GetFollowers(ctx, userID, page, size)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowing(ctx context.Context, userID uint, page int, size int) (_ []model.UserFollower, _ int64, err error) {
+	defer observe("UserFollowerRepository", "GetFollowing", time.Now(), &err)
+	return r.next.GetFollowing(ctx, userID, page, size)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowRequests(ctx context.Context, userID uint, page int, size int) (_ []model.UserFollower, _ int64, err error) {
+	defer observe("UserFollowerRepository", "GetFollowRequests", time.Now(), &err)
+	return r.next.GetFollowRequests(ctx, userID, page, size)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowerIDs(ctx context.Context, targetID uint, afterID uint, limit int) (_ []uint, err error) {
+	defer observe("UserFollowerRepository", "GetFollowerIDs", time.Now(), &err)
+	return r.next.GetFollowerIDs(ctx, targetID, afterID, limit)
+}
+
+func (r *userFollowerRepositoryMetrics) ExportFollows(ctx context.Context, filter RelationExportFilter, limit int) (_ []model.UserFollower, err error) {
+	defer observe("UserFollowerRepository", "ExportFollows", time.Now(), &err)
+	return r.next.ExportFollows(ctx, filter, limit)
+}
+
+func (r *userFollowerRepositoryMetrics) CreateFollower(ctx context.Context, follower *model.UserFollower) (err error) {
+	defer observe("UserFollowerRepository", "CreateFollower", time.Now(), &err)
+	return r.next.CreateFollower(ctx, follower)
+}
+
+func (r *userFollowerRepositoryMetrics) UpdateFollowerStatus(ctx context.Context, id uint, status int) (err error) {
+	defer observe("UserFollowerRepository", "UpdateFollowerStatus", time.Now(), &err)
+	return r.next.UpdateFollowerStatus(ctx, id, status)
+}
+
+func (r *userFollowerRepositoryMetrics) ApproveAllPending(ctx context.Context, targetID uint) (_ int64, err error) {
+	defer observe("UserFollowerRepository", "ApproveAllPending", time.Now(), &err)
+	return r.next.ApproveAllPending(ctx, targetID)
+}
+
+func (r *userFollowerRepositoryMetrics) DeleteFollower(ctx context.Context, userID uint, targetID uint) (err error) {
+	defer observe("UserFollowerRepository", "DeleteFollower", time.Now(), &err)
+	return r.next.DeleteFollower(ctx, userID, targetID)
+}
+
+func (r *userFollowerRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("UserFollowerRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+func (r *userFollowerRepositoryMetrics) BatchCreateFollowers(ctx context.Context, followers []model.UserFollower, chunkSize int) map[uint]error {
+	defer observe("UserFollowerRepository", "BatchCreateFollowers", time.Now(), nil)
+	return r.next.BatchCreateFollowers(ctx, followers, chunkSize)
+}
+
+func (r *userFollowerRepositoryMetrics) BatchDeleteFollowing(ctx context.Context, userID uint, targetIDs []uint, chunkSize int) map[uint]error {
+	defer observe("UserFollowerRepository", "BatchDeleteFollowing", time.Now(), nil)
+	return r.next.BatchDeleteFollowing(ctx, userID, targetIDs, chunkSize)
+}
+
+func (r *userFollowerRepositoryMetrics) BatchDeleteFollowers(ctx context.Context, targetID uint, userIDs []uint, chunkSize int) map[uint]error {
+	defer observe("UserFollowerRepository", "BatchDeleteFollowers", time.Now(), nil)
+	return r.next.BatchDeleteFollowers(ctx, targetID, userIDs, chunkSize)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowStatuses(ctx context.Context, userID uint, targetIDs []uint) (_ map[uint]int, err error) {
+	defer observe("UserFollowerRepository", "GetFollowStatuses", time.Now(), &err)
+	return r.next.GetFollowStatuses(ctx, userID, targetIDs)
+}
+
+func (r *userFollowerRepositoryMetrics) GetFollowerStatuses(ctx context.Context, targetID uint, userIDs []uint) (_ map[uint]int, err error) {
+	defer observe("UserFollowerRepository", "GetFollowerStatuses", time.Now(), &err)
+	return r.next.GetFollowerStatuses(ctx, targetID, userIDs)
+}
+
+func (r *userFollowerRepositoryMetrics) CountFollows(ctx context.Context, userID uint) (_ int64, _ int64, err error) {
+	defer observe("UserFollowerRepository", "CountFollows", time.Now(), &err)
+	return r.next.CountFollows(ctx, userID)
+}
+
+func (r *userFollowerRepositoryMetrics) CountMutualFollows(ctx context.Context, userID uint, limit int) (_ map[uint]int64, err error) {
+	defer observe("UserFollowerRepository", "CountMutualFollows", time.Now(), &err)
+	return r.next.CountMutualFollows(ctx, userID, limit)
+}
+
+// userFriendRepositoryMetrics 记录UserFriendRepository各方法调用指标的装饰器
+type userFriendRepositoryMetrics struct {
+	next UserFriendRepository
+}
+
+// WithUserFriendRepositoryMetrics 包装UserFriendRepository，记录各方法的调用次数、耗时和错误次数
+func WithUserFriendRepositoryMetrics(repo UserFriendRepository) UserFriendRepository {
+	return &userFriendRepositoryMetrics{next: repo}
+}
+
+func (r *userFriendRepositoryMetrics) CreateFriend(ctx context.Context, friend *model.UserFriend) (err error) {
+	defer observe("UserFriendRepository", "CreateFriend", time.Now(), &err)
+	return r.next.CreateFriend(ctx, friend)
+}
+
+func (r *userFriendRepositoryMetrics) UpdateFriendStatus(ctx context.Context, id uint, status int) (err error) {
+	defer observe("UserFriendRepository", "UpdateFriendStatus", time.Now(), &err)
+	return r.next.UpdateFriendStatus(ctx, id, status)
+}
+
+func (r *userFriendRepositoryMetrics) DeleteFriend(ctx context.Context, userID uint, targetID uint) (err error) {
+	defer observe("UserFriendRepository", "DeleteFriend", time.Now(), &err)
+	return r.next.DeleteFriend(ctx, userID, targetID)
+}
+
+func (r *userFriendRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("UserFriendRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+func (r *userFriendRepositoryMetrics) GetFriend(ctx context.Context, userID uint, targetID uint) (_ *model.UserFriend, err error) {
+	defer observe("UserFriendRepository", "GetFriend", time.Now(), &err)
+	return r.next.GetFriend(ctx, userID, targetID)
+}
+
+func (r *userFriendRepositoryMetrics) GetFriendByID(ctx context.Context, id uint) (_ *model.UserFriend, err error) {
+	defer observe("UserFriendRepository", "GetFriendByID", time.Now(), &err)
+	return r.next.GetFriendByID(ctx, id)
+}
+
+func (r *userFriendRepositoryMetrics) GetFriendRequests(ctx context.Context, userID uint, page int, size int) (_ []model.UserFriend, _ int64, err error) {
+	defer observe("UserFriendRepository", "GetFriendRequests", time.Now(), &err)
+	return r.next.GetFriendRequests(ctx, userID, page, size)
+}
+
+func (r *userFriendRepositoryMetrics) GetFriends(ctx context.Context, userID uint, group string, page int, size int) (_ []model.UserFriend, _ int64, err error) {
+	defer observe("UserFriendRepository", "GetFriends", time.Now(), &err)
+	return r.next.GetFriends(ctx, userID, group, page, size)
+}
+
+func (r *userFriendRepositoryMetrics) GetFriendIDs(ctx context.Context, userID uint, afterID uint, limit int) (_ []uint, err error) {
+	defer observe("UserFriendRepository", "GetFriendIDs", time.Now(), &err)
+	return r.next.GetFriendIDs(ctx, userID, afterID, limit)
+}
+
+func (r *userFriendRepositoryMetrics) ExportFriends(ctx context.Context, filter RelationExportFilter, limit int) (_ []model.UserFriend, err error) {
+	defer observe("UserFriendRepository", "ExportFriends", time.Now(), &err)
+	return r.next.ExportFriends(ctx, filter, limit)
+}
+
+func (r *userFriendRepositoryMetrics) UpdateFriendRemark(ctx context.Context, userID uint, targetID uint, remark string) (err error) {
+	defer observe("UserFriendRepository", "UpdateFriendRemark", time.Now(), &err)
+	return r.next.UpdateFriendRemark(ctx, userID, targetID, remark)
+}
+
+func (r *userFriendRepositoryMetrics) UpdateFriendGroup(ctx context.Context, userID uint, targetID uint, group string) (err error) {
+	defer observe("UserFriendRepository", "UpdateFriendGroup", time.Now(), &err)
+	return r.next.UpdateFriendGroup(ctx, userID, targetID, group)
+}
+
+func (r *userFriendRepositoryMetrics) GetFriendStatuses(ctx context.Context, userID uint, targetIDs []uint) (_ map[uint]int, err error) {
+	defer observe("UserFriendRepository", "GetFriendStatuses", time.Now(), &err)
+	return r.next.GetFriendStatuses(ctx, userID, targetIDs)
+}
+
+func (r *userFriendRepositoryMetrics) CountMutualFriends(ctx context.Context, userID uint, limit int) (_ map[uint]int64, err error) {
+	defer observe("UserFriendRepository", "CountMutualFriends", time.Now(), &err)
+	return r.next.CountMutualFriends(ctx, userID, limit)
+}
+
+// userRepositoryMetrics 记录UserRepository各方法调用指标的装饰器
+type userRepositoryMetrics struct {
+	next UserRepository
+}
+
+// WithUserRepositoryMetrics 包装UserRepository，记录各方法的调用次数、耗时和错误次数
+func WithUserRepositoryMetrics(repo UserRepository) UserRepository {
+	return &userRepositoryMetrics{next: repo}
+}
+
+func (r *userRepositoryMetrics) FindByID(ctx context.Context, id uint) (_ *model.User, err error) {
+	defer observe("UserRepository", "FindByID", time.Now(), &err)
+	return r.next.FindByID(ctx, id)
+}
+
+func (r *userRepositoryMetrics) FindByMobile(ctx context.Context, mobile string) (_ *model.User, err error) {
+	defer observe("UserRepository", "FindByMobile", time.Now(), &err)
+	return r.next.FindByMobile(ctx, mobile)
+}
+
+func (r *userRepositoryMetrics) SearchUsers(ctx context.Context, nickname string, mobile string, excludeID uint, page int, size int) (_ []model.User, _ int64, err error) {
+	defer observe("UserRepository", "SearchUsers", time.Now(), &err)
+	return r.next.SearchUsers(ctx, nickname, mobile, excludeID, page, size)
+}
+
+func (r *userRepositoryMetrics) FindByMobileHashes(ctx context.Context, hashes []string, excludeID uint) (_ []model.User, err error) {
+	defer observe("UserRepository", "FindByMobileHashes", time.Now(), &err)
+	return r.next.FindByMobileHashes(ctx, hashes, excludeID)
+}
+
+func (r *userRepositoryMetrics) FindNormalByIDs(ctx context.Context, ids []uint) (_ []model.User, err error) {
+	defer observe("UserRepository", "FindNormalByIDs", time.Now(), &err)
+	return r.next.FindNormalByIDs(ctx, ids)
+}
+
+func (r *userRepositoryMetrics) FindByUsername(ctx context.Context, username string) (_ *model.User, err error) {
+	defer observe("UserRepository", "FindByUsername", time.Now(), &err)
+	return r.next.FindByUsername(ctx, username)
+}
+
+func (r *userRepositoryMetrics) UsernameExists(ctx context.Context, username string, excludeID uint) (_ bool, err error) {
+	defer observe("UserRepository", "UsernameExists", time.Now(), &err)
+	return r.next.UsernameExists(ctx, username, excludeID)
+}
+
+func (r *userRepositoryMetrics) Create(ctx context.Context, user *model.User) (err error) {
+	defer observe("UserRepository", "Create", time.Now(), &err)
+	return r.next.Create(ctx, user)
+}
+
+func (r *userRepositoryMetrics) Update(ctx context.Context, user *model.User) (err error) {
+	defer observe("UserRepository", "Update", time.Now(), &err)
+	return r.next.Update(ctx, user)
+}
+
+func (r *userRepositoryMetrics) SoftDelete(ctx context.Context, id uint) (err error) {
+	defer observe("UserRepository", "SoftDelete", time.Now(), &err)
+	return r.next.SoftDelete(ctx, id)
+}
+
+func (r *userRepositoryMetrics) ScrubDeleted(ctx context.Context, id uint) (err error) {
+	defer observe("UserRepository", "ScrubDeleted", time.Now(), &err)
+	return r.next.ScrubDeleted(ctx, id)
+}
+
+func (r *userRepositoryMetrics) UpdateLastActiveAt(ctx context.Context, id uint, activeAt time.Time) (err error) {
+	defer observe("UserRepository", "UpdateLastActiveAt", time.Now(), &err)
+	return r.next.UpdateLastActiveAt(ctx, id, activeAt)
+}
+
+func (r *userRepositoryMetrics) FindInactiveUsers(ctx context.Context, before time.Time, limit int) (_ []model.User, err error) {
+	defer observe("UserRepository", "FindInactiveUsers", time.Now(), &err)
+	return r.next.FindInactiveUsers(ctx, before, limit)
+}
+
+func (r *userRepositoryMetrics) MarkDormant(ctx context.Context, ids []uint, before time.Time) (_ int64, err error) {
+	defer observe("UserRepository", "MarkDormant", time.Now(), &err)
+	return r.next.MarkDormant(ctx, ids, before)
+}
+
+func (r *userRepositoryMetrics) FindActiveUserIDs(ctx context.Context, since time.Time, afterID uint, limit int) (_ []uint, err error) {
+	defer observe("UserRepository", "FindActiveUserIDs", time.Now(), &err)
+	return r.next.FindActiveUserIDs(ctx, since, afterID, limit)
+}
+
+func (r *userRepositoryMetrics) StartDeactivation(ctx context.Context, id uint, deactivateAt time.Time) (err error) {
+	defer observe("UserRepository", "StartDeactivation", time.Now(), &err)
+	return r.next.StartDeactivation(ctx, id, deactivateAt)
+}
+
+func (r *userRepositoryMetrics) CancelDeactivation(ctx context.Context, id uint) (_ bool, err error) {
+	defer observe("UserRepository", "CancelDeactivation", time.Now(), &err)
+	return r.next.CancelDeactivation(ctx, id)
+}
+
+func (r *userRepositoryMetrics) FindDueDeactivations(ctx context.Context, before time.Time, limit int) (_ []model.User, err error) {
+	defer observe("UserRepository", "FindDueDeactivations", time.Now(), &err)
+	return r.next.FindDueDeactivations(ctx, before, limit)
+}
+
+func (r *userRepositoryMetrics) FinalizeDeactivation(ctx context.Context, id uint, before time.Time) (_ bool, err error) {
+	defer observe("UserRepository", "FinalizeDeactivation", time.Now(), &err)
+	return r.next.FinalizeDeactivation(ctx, id, before)
+}
+
+func (r *userRepositoryMetrics) ChangeUsername(ctx context.Context, id uint, username string, changedBefore time.Time, history *model.UsernameHistory) (_ bool, err error) {
+	defer observe("UserRepository", "ChangeUsername", time.Now(), &err)
+	return r.next.ChangeUsername(ctx, id, username, changedBefore, history)
+}
+
+func (r *userRepositoryMetrics) GetTokenVersion(ctx context.Context, id uint) (_ uint, err error) {
+	defer observe("UserRepository", "GetTokenVersion", time.Now(), &err)
+	return r.next.GetTokenVersion(ctx, id)
+}
+
+func (r *userRepositoryMetrics) IncrementTokenVersion(ctx context.Context, id uint) (_ uint, err error) {
+	defer observe("UserRepository", "IncrementTokenVersion", time.Now(), &err)
+	return r.next.IncrementTokenVersion(ctx, id)
+}
+
+// usernameHistoryRepositoryMetrics 记录UsernameHistoryRepository各方法调用指标的装饰器
+type usernameHistoryRepositoryMetrics struct {
+	next UsernameHistoryRepository
+}
+
+// WithUsernameHistoryRepositoryMetrics 包装UsernameHistoryRepository，记录各方法的调用次数、耗时和错误次数
+func WithUsernameHistoryRepositoryMetrics(repo UsernameHistoryRepository) UsernameHistoryRepository {
+	return &usernameHistoryRepositoryMetrics{next: repo}
+}
+
+func (r *usernameHistoryRepositoryMetrics) FindProtected(ctx context.Context, username string, now time.Time) (_ *model.UsernameHistory, err error) {
+	defer observe("UsernameHistoryRepository", "FindProtected", time.Now(), &err)
+	return r.next.FindProtected(ctx, username, now)
+}
+
+func (r *usernameHistoryRepositoryMetrics) DeleteAllByUser(ctx context.Context, userID uint) (_ int64, err error) {
+	defer observe("UsernameHistoryRepository", "DeleteAllByUser", time.Now(), &err)
+	return r.next.DeleteAllByUser(ctx, userID)
+}
+
+// webhookRepositoryMetrics 记录WebhookRepository各方法调用指标的装饰器
+type webhookRepositoryMetrics struct {
+	next WebhookRepository
+}
+
+// WithWebhookRepositoryMetrics 包装WebhookRepository，记录各方法的调用次数、耗时和错误次数
+func WithWebhookRepositoryMetrics(repo WebhookRepository) WebhookRepository {
+	return &webhookRepositoryMetrics{next: repo}
+}
+
+func (r *webhookRepositoryMetrics) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) (err error) {
+	defer observe("WebhookRepository", "CreateSubscription", time.Now(), &err)
+	return r.next.CreateSubscription(ctx, sub)
+}
+
+func (r *webhookRepositoryMetrics) GetSubscription(ctx context.Context, id uint) (_ *model.WebhookSubscription, err error) {
+	defer observe("WebhookRepository", "GetSubscription", time.Now(), &err)
+	return r.next.GetSubscription(ctx, id)
+}
+
+func (r *webhookRepositoryMetrics) GetSubscriptions(ctx context.Context) (_ []model.WebhookSubscription, err error) {
+	defer observe("WebhookRepository", "GetSubscriptions", time.Now(), &err)
+	return r.next.GetSubscriptions(ctx)
+}
+
+func (r *webhookRepositoryMetrics) GetEnabledSubscriptions(ctx context.Context) (_ []model.WebhookSubscription, err error) {
+	defer observe("WebhookRepository", "GetEnabledSubscriptions", time.Now(), &err)
+	return r.next.GetEnabledSubscriptions(ctx)
+}
+
+func (r *webhookRepositoryMetrics) UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) (err error) {
+	defer observe("WebhookRepository", "UpdateSubscription", time.Now(), &err)
+	return r.next.UpdateSubscription(ctx, sub)
+}
+
+func (r *webhookRepositoryMetrics) DeleteSubscription(ctx context.Context, id uint) (err error) {
+	defer observe("WebhookRepository", "DeleteSubscription", time.Now(), &err)
+	return r.next.DeleteSubscription(ctx, id)
+}
+
+func (r *webhookRepositoryMetrics) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) (err error) {
+	defer observe("WebhookRepository", "CreateDeliveries", time.Now(), &err)
+	return r.next.CreateDeliveries(ctx, deliveries)
+}
+
+func (r *webhookRepositoryMetrics) GetDueDeliveries(ctx context.Context, now time.Time, limit int) (_ []model.WebhookDelivery, err error) {
+	defer observe("WebhookRepository", "GetDueDeliveries", time.Now(), &err)
+	return r.next.GetDueDeliveries(ctx, now, limit)
+}
+
+func (r *webhookRepositoryMetrics) ClaimDelivery(ctx context.Context, id uint, now time.Time, leaseUntil time.Time) (_ bool, err error) {
+	defer observe("WebhookRepository", "ClaimDelivery", time.Now(), &err)
+	return r.next.ClaimDelivery(ctx, id, now, leaseUntil)
+}
+
+func (r *webhookRepositoryMetrics) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) (err error) {
+	defer observe("WebhookRepository", "UpdateDelivery", time.Now(), &err)
+	return r.next.UpdateDelivery(ctx, delivery)
+}
+
+func (r *webhookRepositoryMetrics) GetDeliveries(ctx context.Context, filter WebhookDeliveryFilter, page int, size int) (_ []model.WebhookDelivery, _ int64, err error) {
+	defer observe("WebhookRepository", "GetDeliveries", time.Now(), &err)
+	return r.next.GetDeliveries(ctx, filter, page, size)
+}
+
+func (r *webhookRepositoryMetrics) DeleteFinishedDeliveries(ctx context.Context, before time.Time, limit int) (_ int64, err error) {
+	defer observe("WebhookRepository", "DeleteFinishedDeliveries", time.Now(), &err)
+	return r.next.DeleteFinishedDeliveries(ctx, before, limit)
+}
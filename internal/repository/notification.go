@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository 用户通知设置仓库接口
+type NotificationRepository interface {
+	// GetSetting 获取用户的通知设置，未保存时返回ErrRecordNotFound
+	GetSetting(ctx context.Context, userID uint) (*model.NotificationSetting, error)
+	// GetPreferences 获取用户已保存的各事件通知偏好
+	GetPreferences(ctx context.Context, userID uint) ([]model.NotificationPreference, error)
+	// Save 在同一事务中保存通知设置和通知偏好，已存在时覆盖
+	Save(ctx context.Context, setting *model.NotificationSetting, preferences []model.NotificationPreference) error
+	// DeleteByUser 删除用户的通知设置和通知偏好
+	DeleteByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// notificationRepository 用户通知设置仓库实现
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建用户通知设置仓库实例
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// GetSetting 获取用户的通知设置，未保存时返回ErrRecordNotFound
+func (r *notificationRepository) GetSetting(ctx context.Context, userID uint) (*model.NotificationSetting, error) {
+	var setting model.NotificationSetting
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &setting, nil
+}
+
+// GetPreferences 获取用户已保存的各事件通知偏好
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID uint) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&preferences).Error
+	return preferences, err
+}
+
+// Save 在同一事务中保存通知设置和通知偏好，已存在时覆盖
+// setting为nil时只保存通知偏好
+func (r *notificationRepository) Save(ctx context.Context, setting *model.NotificationSetting, preferences []model.NotificationPreference) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if setting != nil {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"quiet_hours_enabled", "quiet_start", "quiet_end", "timezone", "updated_at"}),
+			}).Create(setting).Error; err != nil {
+				return err
+			}
+		}
+		if len(preferences) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}},
+				DoUpdates: clause.AssignmentColumns([]string{"push", "sms", "email", "updated_at"}),
+			}).Create(&preferences).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByUser 删除用户的通知设置和通知偏好
+func (r *notificationRepository) DeleteByUser(ctx context.Context, userID uint) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&model.NotificationPreference{})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected += result.RowsAffected
+
+		result = tx.Where("user_id = ?", userID).Delete(&model.NotificationSetting{})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected += result.RowsAffected
+		return nil
+	})
+	return affected, err
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"app/internal/constant"
 	"app/internal/model"
-	"fmt"
 
 	"gorm.io/gorm"
 )
@@ -11,17 +15,32 @@ import (
 // PostRepository 动态仓库接口
 type PostRepository interface {
 	// 查询方法
-	GetPost(id uint) (*model.Post, error)
-	GetUserPosts(userID uint, page, size int, viewerID ...uint) ([]model.Post, int64, error)
-	GetFollowingPosts(userID uint, page, size int) ([]model.Post, int64, error)
+	GetPost(ctx context.Context, id uint) (*model.Post, error)
+	GetVisiblePost(ctx context.Context, id, viewerID uint) (*model.Post, error)
+	GetUserPosts(ctx context.Context, userID uint, page, size int, viewerID ...uint) ([]model.Post, int64, error)
+	GetFollowingPosts(ctx context.Context, userID uint, page, size int) ([]model.Post, int64, error)
+	// GetPostsByIDs 根据ID列表批量获取动态，不包含已注销用户的动态，指定查看者时不包含作者屏蔽了查看者的动态
+	GetPostsByIDs(ctx context.Context, ids []uint, viewerID ...uint) ([]model.Post, error)
+	// 发现页候选动态，只包含正常状态的公开账号发布的公开动态，不包含查看者自己的动态和作者屏蔽了查看者的动态
+	GetNearbyPosts(ctx context.Context, viewerID uint, box GeoBox, since time.Time, limit int) ([]model.Post, error)
+	// GetNearbyClusters 按geohash前缀聚合范围内的动态，prefixes为覆盖范围的geohash，precision为聚合使用的前缀长度
+	GetNearbyClusters(ctx context.Context, viewerID uint, box GeoBox, prefixes []string, precision int, since time.Time, limit int) ([]GeoCluster, error)
+	GetPopularPosts(ctx context.Context, viewerID uint, since time.Time, limit int) ([]model.Post, error)
+	// 回收站查询
+	GetTrashedPosts(ctx context.Context, userID uint, since time.Time, page, size int) ([]model.Post, int64, error)
+	GetTrashedPost(ctx context.Context, id uint) (*model.Post, error)
+	GetExpiredTrashedPosts(ctx context.Context, before time.Time, limit int) ([]model.Post, error)
 
 	// 修改方法
-	CreatePost(post *model.Post) error
-	UpdatePost(post *model.Post) error
-	IncrementPostLikes(postID uint) error
-	IncrementPostComments(postID uint) error
-	// 事务方法
-	IncrementPostCommentsWithTx(tx *gorm.DB, postID uint) error
+	CreatePost(ctx context.Context, post *model.Post) error
+	// CreatePostWithImages 在事务中创建动态及其图片记录
+	// attach在动态创建后、事务提交前调用，返回需要保存的图片记录，返回错误时事务回滚，动态不会被创建
+	CreatePostWithImages(ctx context.Context, post *model.Post, attach func(postID uint) ([]model.PostImage, error)) error
+	UpdatePost(ctx context.Context, post *model.Post) error
+	DeletePost(ctx context.Context, id uint) error
+	AddPostViews(ctx context.Context, postID uint, views int64) error
+	RestorePost(ctx context.Context, id uint) error
+	PurgePost(ctx context.Context, id uint) error
 }
 
 // postRepository 动态仓库实现
@@ -35,9 +54,9 @@ func NewPostRepository(db *gorm.DB) PostRepository {
 }
 
 // GetPost 获取动态
-func (r *postRepository) GetPost(id uint) (*model.Post, error) {
+func (r *postRepository) GetPost(ctx context.Context, id uint) (*model.Post, error) {
 	var post model.Post
-	err := r.db.First(&post, id).Error
+	err := r.db.WithContext(ctx).First(&post, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,29 +64,25 @@ func (r *postRepository) GetPost(id uint) (*model.Post, error) {
 }
 
 // GetUserPosts 获取用户动态列表
-func (r *postRepository) GetUserPosts(userID uint, page, size int, viewerID ...uint) ([]model.Post, int64, error) {
+func (r *postRepository) GetUserPosts(ctx context.Context, userID uint, page, size int, viewerID ...uint) ([]model.Post, int64, error) {
 	var posts []model.Post
 	var count int64
 
 	offset := (page - 1) * size
 
 	// 基础查询：获取指定用户的动态
-	query := r.db.Model(&model.Post{}).Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Model(&model.Post{}).Where("user_id = ?", userID)
 
 	// 如果提供了查看者ID且不是自己查看自己的动态，需要根据可见性过滤
 	if len(viewerID) > 0 && viewerID[0] != userID {
-		// 检查是否为好友关系（双记录模式）
-		var friendCount int64
-		r.db.Model(&model.UserFriend{}).
-			Where("user_id = ? AND target_id = ? AND status = ? AND direction IN (0, 1)", viewerID[0], userID, int(constant.FriendStatusConfirmed)).
-			Count(&friendCount)
-
-		if friendCount > 0 {
-			// 是好友关系，可以看到公开和好友可见的动态
-			query = query.Where("visibility IN (?, ?)", int(constant.VisibilityPublic), int(constant.VisibilityFriends))
-		} else {
-			// 不是好友关系，只能看到公开动态
-			query = query.Where("visibility = ?", int(constant.VisibilityPublic))
+		var visible bool
+		var err error
+		query, visible, err = r.filterVisible(ctx, query, userID, viewerID[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		if !visible {
+			return []model.Post{}, 0, nil
 		}
 	}
 
@@ -86,54 +101,141 @@ func (r *postRepository) GetUserPosts(userID uint, page, size int, viewerID ...u
 	return posts, count, nil
 }
 
+// GetVisiblePost 获取查看者可见的动态，不存在或无权查看时均返回gorm.ErrRecordNotFound
+func (r *postRepository) GetVisiblePost(ctx context.Context, id, viewerID uint) (*model.Post, error) {
+	post, err := r.GetPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID == viewerID {
+		return post, nil
+	}
+
+	query, visible, err := r.filterVisible(ctx, r.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id), post.UserID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return post, nil
+}
+
+// filterVisible 按查看者与作者的关系过滤动态可见性
+// 返回的布尔值为false表示查看者无权查看作者的任何动态
+func (r *postRepository) filterVisible(ctx context.Context, query *gorm.DB, userID, viewerID uint) (*gorm.DB, bool, error) {
+	// 已注销用户的动态对他人不可见，注销后好友关系在清理完成前仍然存在
+	query = query.Where("user_id IN (?)", activeUserIDs(r.db.WithContext(ctx)))
+
+	// 作者屏蔽了查看者时，无论关系如何都看不到作者的动态
+	var excludedCount int64
+	err := r.db.WithContext(ctx).Model(&model.PostExclusion{}).
+		Where("user_id = ? AND target_id = ?", userID, viewerID).
+		Count(&excludedCount).Error
+	if err != nil || excludedCount > 0 {
+		return query, false, err
+	}
+
+	// 检查是否为好友关系（双记录模式）
+	var friendCount int64
+	r.db.WithContext(ctx).Model(&model.UserFriend{}).
+		Where("user_id = ? AND target_id = ? AND status = ? AND direction IN (0, 1)", viewerID, userID, int(constant.FriendStatusConfirmed)).
+		Count(&friendCount)
+
+	if friendCount > 0 {
+		// 是好友关系，可以看到公开和好友可见的动态，以及查看者所在好友列表可见的动态
+		return query.Where(
+			r.db.WithContext(ctx).Where("visibility IN (?, ?)", int(constant.VisibilityPublic), int(constant.VisibilityFriends)).
+				Or("visibility = ? AND audience_list_id IN (?)", int(constant.VisibilityList), r.memberListIDs(ctx, viewerID)),
+		), true, nil
+	}
+
+	// 私密账号的动态只对好友和已通过的关注者可见
+	visible, err := r.canViewPrivateAccount(ctx, viewerID, userID)
+	if err != nil || !visible {
+		return query, false, err
+	}
+
+	// 不是好友关系，只能看到公开动态
+	return query.Where("visibility = ?", int(constant.VisibilityPublic)), true, nil
+}
+
+// canViewPrivateAccount 检查查看者能否查看用户的动态
+// 非私密账号对所有人可见，私密账号只对已通过的关注者可见
+func (r *postRepository) canViewPrivateAccount(ctx context.Context, viewerID, userID uint) (bool, error) {
+	var owner model.User
+	if err := r.db.WithContext(ctx).Select("id", "is_private").Where("id = ?", userID).First(&owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !owner.IsPrivate {
+		return true, nil
+	}
+
+	var followCount int64
+	err := r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("user_id = ? AND target_id = ? AND status = ?", viewerID, userID, int(constant.FollowStatusApproved)).
+		Count(&followCount).Error
+	if err != nil {
+		return false, err
+	}
+	return followCount > 0, nil
+}
+
+// memberListIDs 返回用户所在好友列表ID的子查询
+func (r *postRepository) memberListIDs(ctx context.Context, userID uint) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.AudienceListMember{}).Select("list_id").Where("member_id = ?", userID)
+}
+
+// excludingAuthorIDs 返回屏蔽了查看者的作者ID的子查询
+func (r *postRepository) excludingAuthorIDs(ctx context.Context, viewerID uint) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.PostExclusion{}).Select("user_id").Where("target_id = ?", viewerID)
+}
+
 // GetFollowingPosts 获取关注用户的动态列表
-func (r *postRepository) GetFollowingPosts(userID uint, page, size int) ([]model.Post, int64, error) {
+func (r *postRepository) GetFollowingPosts(ctx context.Context, userID uint, page, size int) ([]model.Post, int64, error) {
 	var posts []model.Post
 	var count int64
 
 	offset := (page - 1) * size
 
-	// 构建复杂查询
-	// 1. 获取所有关注用户的公开动态
-	publicPostsQuery := r.db.Table("posts").
-		Select("posts.*").
-		Joins("JOIN user_follower ON posts.user_id = user_follower.target_id").
-		Where("user_follower.user_id = ?", userID).
-		Where("posts.visibility = ?", int(constant.VisibilityPublic))
-
-	// 2. 获取好友的仅好友可见动态
-	friendPostsQuery := r.db.Table("posts").
-		Select("posts.*").
-		Joins("JOIN user_friend ON posts.user_id = user_friend.target_id").
-		Where("user_friend.user_id = ?", userID).
-		Where("user_friend.status = ? AND user_friend.direction IN (0, 1)", int(constant.FriendStatusConfirmed)). // 已确认的好友关系（双记录模式）
-		Where("posts.visibility = ?", int(constant.VisibilityFriends))
-
-	// 使用UNION合并查询结果
-	// 获取公开动态的SQL
-	publicSQL := publicPostsQuery.Session(&gorm.Session{DryRun: true}).Find(&[]model.Post{}).Statement.SQL.String()
-	publicVars := publicPostsQuery.Session(&gorm.Session{DryRun: true}).Find(&[]model.Post{}).Statement.Vars
-
-	// 获取好友动态的SQL
-	friendSQL := friendPostsQuery.Session(&gorm.Session{DryRun: true}).Find(&[]model.Post{}).Statement.SQL.String()
-	friendVars := friendPostsQuery.Session(&gorm.Session{DryRun: true}).Find(&[]model.Post{}).Statement.Vars
-
-	// 合并变量
-	allVars := append(publicVars, friendVars...)
-
-	// 构建UNION查询
-	unionSQL := fmt.Sprintf("(%s) UNION (%s)", publicSQL, friendSQL)
+	// 1. 已通过关注的用户ID（公开动态可见）
+	followingIDs := r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Select("target_id").
+		Where("user_id = ? AND status = ?", userID, int(constant.FollowStatusApproved))
+
+	// 2. 已确认好友的ID（仅好友可见动态可见，双记录模式下只需查询用户视角的记录）
+	friendIDs := r.db.WithContext(ctx).Model(&model.UserFriend{}).
+		Select("target_id").
+		Where("user_id = ? AND status = ?", userID, int(constant.FriendStatusConfirmed))
+
+	// 使用子查询代替UNION，避免依赖特定数据库的语法
+	// 好友列表可见的动态还需查看者在列表中，解除好友关系后即使仍在列表中也不可见
+	query := r.db.WithContext(ctx).Model(&model.Post{}).Where(
+		r.db.WithContext(ctx).Where("visibility = ? AND user_id IN (?)", int(constant.VisibilityPublic), followingIDs).
+			Or("visibility = ? AND user_id IN (?)", int(constant.VisibilityFriends), friendIDs).
+			Or("visibility = ? AND user_id IN (?) AND audience_list_id IN (?)", int(constant.VisibilityList), friendIDs, r.memberListIDs(ctx, userID)),
+	).Where("user_id IN (?)", activeUserIDs(r.db.WithContext(ctx))).
+		Where("user_id NOT IN (?)", r.excludingAuthorIDs(ctx, userID))
 
 	// 计算总数
-	countSQL := fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS count_table", unionSQL)
-	err := r.db.Raw(countSQL, allVars...).Count(&count).Error
+	err := query.Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// 获取分页数据
-	resultSQL := fmt.Sprintf("SELECT * FROM (%s) AS combined_posts ORDER BY created_at DESC LIMIT %d OFFSET %d", unionSQL, size, offset)
-	err = r.db.Raw(resultSQL, allVars...).Scan(&posts).Error
+	err = query.Order("created_at DESC").Offset(offset).Limit(size).Find(&posts).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -142,26 +244,194 @@ func (r *postRepository) GetFollowingPosts(userID uint, page, size int) ([]model
 }
 
 // CreatePost 创建动态
-func (r *postRepository) CreatePost(post *model.Post) error {
-	return r.db.Create(post).Error
+func (r *postRepository) CreatePost(ctx context.Context, post *model.Post) error {
+	return r.db.WithContext(ctx).Create(post).Error
 }
 
-// IncrementPostLikes 增加动态点赞数
-func (r *postRepository) IncrementPostLikes(postID uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", postID).Update("likes", gorm.Expr("likes + ?", 1)).Error
+// CreatePostWithImages 在事务中创建动态及其图片记录
+func (r *postRepository) CreatePostWithImages(ctx context.Context, post *model.Post, attach func(postID uint) ([]model.PostImage, error)) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+
+		images, err := attach(post.ID)
+		if err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			return nil
+		}
+		return tx.Create(&images).Error
+	})
 }
 
 // UpdatePost 更新动态信息
-func (r *postRepository) UpdatePost(post *model.Post) error {
-	return r.db.Save(post).Error
+func (r *postRepository) UpdatePost(ctx context.Context, post *model.Post) error {
+	return r.db.WithContext(ctx).Save(post).Error
+}
+
+// DeletePost 删除动态（软删除），删除后进入回收站
+func (r *postRepository) DeletePost(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Post{}, id).Error
+}
+
+// AddPostViews 累加动态浏览数
+func (r *postRepository) AddPostViews(ctx context.Context, postID uint, views int64) error {
+	return r.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", postID).Update("views", gorm.Expr("views + ?", views)).Error
+}
+
+// GetPostsByIDs 根据ID列表批量获取动态，不保证返回顺序
+// 缓存的推荐和发现页结果可能包含作者已注销或缓存后屏蔽了查看者的动态，查询时一并排除
+func (r *postRepository) GetPostsByIDs(ctx context.Context, ids []uint, viewerID ...uint) ([]model.Post, error) {
+	var posts []model.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	query := r.db.WithContext(ctx).Where("id IN ? AND user_id IN (?)", ids, activeUserIDs(r.db.WithContext(ctx)))
+	if len(viewerID) > 0 {
+		query = query.Where("user_id NOT IN (?)", r.excludingAuthorIDs(ctx, viewerID[0]))
+	}
+	err := query.Find(&posts).Error
+	return posts, err
+}
+
+// GeoBox 经纬度矩形范围（GCJ-02坐标系）
+type GeoBox struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// discoverablePosts 发现页可推荐的动态查询：正常状态的公开账号发布的公开动态，排除查看者自己的动态和屏蔽了查看者的作者的动态
+func (r *postRepository) discoverablePosts(ctx context.Context, viewerID uint, since time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.Post{}).
+		Joins("JOIN user AS u ON u.id = post.user_id AND u.status = ? AND u.is_private = ? AND u.deleted_at IS NULL", constant.UserStatusNormal, false).
+		Where("post.visibility = ? AND post.user_id <> ? AND post.created_at >= ?", int(constant.VisibilityPublic), viewerID, since).
+		Where("post.user_id NOT IN (?)", r.excludingAuthorIDs(ctx, viewerID))
+}
+
+// GetNearbyPosts 获取位置在指定范围内的近期动态，按发布时间倒序
+func (r *postRepository) GetNearbyPosts(ctx context.Context, viewerID uint, box GeoBox, since time.Time, limit int) ([]model.Post, error) {
+	var posts []model.Post
+	err := r.discoverablePosts(ctx, viewerID, since).
+		Joins("JOIN location AS l ON l.id = post.location_id").
+		Where("l.latitude BETWEEN ? AND ? AND l.longitude BETWEEN ? AND ?", box.MinLat, box.MaxLat, box.MinLng, box.MaxLng).
+		Order("post.created_at DESC").Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
+// GeoCluster 按geohash前缀聚合的动态
+type GeoCluster struct {
+	Geohash   string  // 聚合格的geohash前缀
+	Latitude  float64 // 格内动态位置的平均纬度
+	Longitude float64 // 格内动态位置的平均经度
+	Count     int64   // 格内动态数量
+	PostID    uint    // 格内最新的动态ID
+}
+
+// GetNearbyClusters 按geohash前缀聚合范围内的近期动态，按动态数量降序
+// 先按前缀匹配走geohash索引，再按经纬度过滤掉覆盖格中超出范围的部分；截取前缀使用SUBSTR，MySQL和SQLite均支持
+func (r *postRepository) GetNearbyClusters(ctx context.Context, viewerID uint, box GeoBox, prefixes []string, precision int, since time.Time, limit int) ([]GeoCluster, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+	match := r.db.Where("l.geohash LIKE ?", prefixes[0]+"%")
+	for _, prefix := range prefixes[1:] {
+		match = match.Or("l.geohash LIKE ?", prefix+"%")
+	}
+
+	var clusters []GeoCluster
+	err := r.discoverablePosts(ctx, viewerID, since).
+		Select("SUBSTR(l.geohash, 1, ?) AS geohash, AVG(l.latitude) AS latitude, AVG(l.longitude) AS longitude, COUNT(*) AS count, MAX(post.id) AS post_id", precision).
+		Joins("JOIN location AS l ON l.id = post.location_id").
+		Where(match).
+		Where("l.latitude BETWEEN ? AND ? AND l.longitude BETWEEN ? AND ?", box.MinLat, box.MaxLat, box.MinLng, box.MaxLng).
+		Group(fmt.Sprintf("SUBSTR(l.geohash, 1, %d)", precision)).
+		Order("count DESC").Limit(limit).Scan(&clusters).Error
+	return clusters, err
+}
+
+// GetPopularPosts 获取近期互动最多的动态，评论权重为点赞的2倍
+func (r *postRepository) GetPopularPosts(ctx context.Context, viewerID uint, since time.Time, limit int) ([]model.Post, error) {
+	var posts []model.Post
+	err := r.discoverablePosts(ctx, viewerID, since).
+		Order("post.likes + 2 * post.comments DESC").Order("post.created_at DESC").
+		Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
+// GetTrashedPosts 获取用户回收站中的动态，只包含since之后删除的动态，按删除时间倒序
+func (r *postRepository) GetTrashedPosts(ctx context.Context, userID uint, since time.Time, page, size int) ([]model.Post, int64, error) {
+	var posts []model.Post
+	var count int64
+
+	offset := (page - 1) * size
+
+	query := r.db.WithContext(ctx).Unscoped().Model(&model.Post{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL AND deleted_at > ?", userID, since)
+
+	// 计算总数
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err = query.Order("deleted_at DESC").Offset(offset).Limit(size).Find(&posts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return posts, count, nil
+}
+
+// GetTrashedPost 获取回收站中的动态，动态未删除时返回gorm.ErrRecordNotFound
+func (r *postRepository) GetTrashedPost(ctx context.Context, id uint) (*model.Post, error) {
+	var post model.Post
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&post).Error
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// GetExpiredTrashedPosts 获取before之前删除的动态，用于永久清除
+func (r *postRepository) GetExpiredTrashedPosts(ctx context.Context, before time.Time, limit int) ([]model.Post, error) {
+	var posts []model.Post
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", before).
+		Order("deleted_at").Limit(limit).Find(&posts).Error
+	return posts, err
 }
 
-// IncrementPostComments 增加动态评论数
-func (r *postRepository) IncrementPostComments(postID uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", postID).Update("comments", gorm.Expr("comments + ?", 1)).Error
+// RestorePost 从回收站恢复动态
+func (r *postRepository) RestorePost(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&model.Post{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil).Error
 }
 
-// IncrementPostCommentsWithTx 在事务中增加动态评论数
-func (r *postRepository) IncrementPostCommentsWithTx(tx *gorm.DB, postID uint) error {
-	return tx.Model(&model.Post{}).Where("id = ?", postID).Update("comments", gorm.Expr("comments + ?", 1)).Error
+// PurgePost 永久删除动态及其评论、评论点赞、评论图片记录、修订记录、每日数据和图片记录，图片文件需由调用方先行删除
+func (r *postRepository) PurgePost(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		commentIDs := tx.Unscoped().Model(&model.PostComment{}).Select("id").Where("post_id = ?", id)
+		if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&model.CommentLike{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("post_id = ?", id).Delete(&model.CommentImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("post_id = ?", id).Delete(&model.PostComment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", id).Delete(&model.PostRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", id).Delete(&model.PostStats{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("post_id = ?", id).Delete(&model.PostImage{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.Post{}, id).Error
+	})
 }
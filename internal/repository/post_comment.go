@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"app/internal/constant"
 	"app/internal/model"
-	"fmt"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,52 +12,82 @@ import (
 // PostCommentRepository 动态评论仓库接口
 type PostCommentRepository interface {
 	// 评论相关
-	CreateComment(comment *model.PostComment) error
-	GetComment(id uint) (*model.PostComment, error)
-	GetPostComments(postID uint, page, size int) ([]model.PostComment, int64, error)
-	// 事务操作
-	CreateCommentWithTransaction(comment *model.PostComment, postID uint) error
+	CreateComment(ctx context.Context, comment *model.PostComment) error
+	GetComment(ctx context.Context, id uint) (*model.PostComment, error)
+	GetPostComments(ctx context.Context, postID uint, page, size int, sort string) ([]model.PostComment, int64, error)
+	GetCommentReplies(ctx context.Context, parentID uint, page, size int) ([]model.PostComment, int64, error)
+	CountReplies(ctx context.Context, parentIDs []uint) (map[uint]int64, error)
+	GetUserComments(ctx context.Context, userID uint, page, size int) ([]model.PostComment, int64, error)
+	// 统计
+	CountCommentsByAuthor(ctx context.Context, userID uint, since time.Time) (map[uint]int64, error)
+	// 匿名化
+	AnonymizeUserComments(ctx context.Context, userID uint, limit int) (int64, error)
 }
 
 // postCommentRepository 动态评论仓库实现
 type postCommentRepository struct {
-	db       *gorm.DB
-	postRepo PostRepository
+	db *gorm.DB
 }
 
 // NewPostCommentRepository 创建动态评论仓库实例
-func NewPostCommentRepository(db *gorm.DB, postRepo PostRepository) PostCommentRepository {
-	return &postCommentRepository{db: db, postRepo: postRepo}
+// 动态评论数由动态计数写回服务在Redis中累加后同步，创建评论时不再更新动态
+func NewPostCommentRepository(db *gorm.DB) PostCommentRepository {
+	return &postCommentRepository{db: db}
 }
 
 // CreateComment 创建评论
-func (r *postCommentRepository) CreateComment(comment *model.PostComment) error {
-	return r.db.Create(comment).Error
+func (r *postCommentRepository) CreateComment(ctx context.Context, comment *model.PostComment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
 }
 
 // GetComment 获取评论详情
-func (r *postCommentRepository) GetComment(id uint) (*model.PostComment, error) {
+func (r *postCommentRepository) GetComment(ctx context.Context, id uint) (*model.PostComment, error) {
 	var comment model.PostComment
-	err := r.db.First(&comment, id).Error
+	err := r.db.WithContext(ctx).First(&comment, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &comment, nil
 }
 
-// GetPostComments 获取动态评论列表
-func (r *postCommentRepository) GetPostComments(postID uint, page, size int) ([]model.PostComment, int64, error) {
+// GetPostComments 获取动态的一级评论列表，回复通过GetCommentReplies按需加载
+// sort为hot时按点赞数倒序，否则按发布时间倒序
+func (r *postCommentRepository) GetPostComments(ctx context.Context, postID uint, page, size int, sort string) ([]model.PostComment, int64, error) {
 	var comments []model.PostComment
 	var count int64
 
 	offset := (page - 1) * size
 
-	err := r.db.Model(&model.PostComment{}).Where("post_id = ?", postID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).Where("post_id = ? AND parent_id IS NULL", postID).Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Where("post_id = ?", postID).Order("created_at DESC").Offset(offset).Limit(size).Find(&comments).Error
+	query := r.db.WithContext(ctx).Where("post_id = ? AND parent_id IS NULL", postID)
+	if sort == constant.CommentSortHot {
+		query = query.Order("likes DESC")
+	}
+	err = query.Order("created_at DESC").Offset(offset).Limit(size).Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, count, nil
+}
+
+// GetCommentReplies 获取评论的直接回复列表，按发布时间正序
+func (r *postCommentRepository) GetCommentReplies(ctx context.Context, parentID uint, page, size int) ([]model.PostComment, int64, error) {
+	var comments []model.PostComment
+	var count int64
+
+	offset := (page - 1) * size
+
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).Where("parent_id = ?", parentID).Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = r.db.WithContext(ctx).Where("parent_id = ?", parentID).Order("created_at ASC").Offset(offset).Limit(size).Find(&comments).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -63,20 +95,87 @@ func (r *postCommentRepository) GetPostComments(postID uint, page, size int) ([]
 	return comments, count, nil
 }
 
-// CreateCommentWithTransaction 在事务中创建评论并增加评论数
-func (r *postCommentRepository) CreateCommentWithTransaction(comment *model.PostComment, postID uint) error {
-	// 使用事务确保数据一致性
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		// 在事务中创建评论
-		if err := tx.Create(comment).Error; err != nil {
-			return fmt.Errorf("创建评论失败: %w", err)
-		}
-
-		// 在事务中增加评论数，使用postRepo的事务方法
-		if err := r.postRepo.IncrementPostCommentsWithTx(tx, postID); err != nil {
-			return fmt.Errorf("增加评论数失败: %w", err)
-		}
-
-		return nil
-	})
+// CountReplies 批量统计评论的直接回复数，没有回复的评论不在结果中
+func (r *postCommentRepository) CountReplies(ctx context.Context, parentIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParentID uint
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ?", parentIDs).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ParentID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetUserComments 获取用户发表的评论列表
+func (r *postCommentRepository) GetUserComments(ctx context.Context, userID uint, page, size int) ([]model.PostComment, int64, error) {
+	var comments []model.PostComment
+	var count int64
+
+	offset := (page - 1) * size
+
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Offset(offset).Limit(size).Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, count, nil
+}
+
+// AnonymizeUserComments 分批将用户的评论匿名化（解除与用户的关联）
+// 每次最多处理limit条，返回本次处理的数量，返回0表示已全部处理完毕
+func (r *postCommentRepository) AnonymizeUserComments(ctx context.Context, userID uint, limit int) (int64, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).Where("user_id = ?", userID).Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.PostComment{}).Where("id IN ?", ids).Update("user_id", 0)
+	return result.RowsAffected, result.Error
+}
+
+// CountCommentsByAuthor 统计用户自指定时间以来对各作者动态的评论次数，不包含对自己动态的评论
+func (r *postCommentRepository) CountCommentsByAuthor(ctx context.Context, userID uint, since time.Time) (map[uint]int64, error) {
+	var rows []struct {
+		AuthorID uint
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).
+		Select("post.user_id AS author_id, COUNT(*) AS count").
+		Joins("JOIN post ON post.id = post_comment.post_id AND post.deleted_at IS NULL").
+		Where("post_comment.user_id = ? AND post_comment.created_at >= ? AND post.user_id <> ?", userID, since, userID).
+		Group("post.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.AuthorID] = row.Count
+	}
+	return counts, nil
 }
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostExclusionRepository 动态屏蔽仓库接口
+// 屏蔽在查询动态时按查看者过滤，见PostRepository中的各查询方法
+type PostExclusionRepository interface {
+	// AddExclusions 批量屏蔽用户，已屏蔽的用户忽略，返回新增的数量
+	AddExclusions(ctx context.Context, userID uint, targetIDs []uint) (int64, error)
+	// RemoveExclusions 批量取消屏蔽，返回取消的数量
+	RemoveExclusions(ctx context.Context, userID uint, targetIDs []uint) (int64, error)
+	// GetExclusions 获取用户屏蔽的用户，按屏蔽时间倒序
+	GetExclusions(ctx context.Context, userID uint, page, size int) ([]model.PostExclusion, int64, error)
+	// CountExclusions 统计用户屏蔽的用户数量
+	CountExclusions(ctx context.Context, userID uint) (int64, error)
+	// DeleteAllByUser 删除用户设置的屏蔽以及屏蔽该用户的记录，返回删除的记录数
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// postExclusionRepository 动态屏蔽仓库实现
+type postExclusionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostExclusionRepository 创建动态屏蔽仓库实例
+func NewPostExclusionRepository(db *gorm.DB) PostExclusionRepository {
+	return &postExclusionRepository{db: db}
+}
+
+// AddExclusions 批量屏蔽用户
+func (r *postExclusionRepository) AddExclusions(ctx context.Context, userID uint, targetIDs []uint) (int64, error) {
+	if len(targetIDs) == 0 {
+		return 0, nil
+	}
+	exclusions := make([]model.PostExclusion, len(targetIDs))
+	for i, id := range targetIDs {
+		exclusions[i] = model.PostExclusion{UserID: userID, TargetID: id}
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&exclusions)
+	return result.RowsAffected, result.Error
+}
+
+// RemoveExclusions 批量取消屏蔽
+func (r *postExclusionRepository) RemoveExclusions(ctx context.Context, userID uint, targetIDs []uint) (int64, error) {
+	if len(targetIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Where("user_id = ? AND target_id IN ?", userID, targetIDs).Delete(&model.PostExclusion{})
+	return result.RowsAffected, result.Error
+}
+
+// GetExclusions 获取用户屏蔽的用户，按屏蔽时间倒序
+func (r *postExclusionRepository) GetExclusions(ctx context.Context, userID uint, page, size int) ([]model.PostExclusion, int64, error) {
+	var exclusions []model.PostExclusion
+	var count int64
+
+	offset := (page - 1) * size
+	query := r.db.WithContext(ctx).Model(&model.PostExclusion{}).Where("user_id = ?", userID)
+
+	// 计算总数
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err = query.Order("id DESC").Offset(offset).Limit(size).Find(&exclusions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return exclusions, count, nil
+}
+
+// CountExclusions 统计用户屏蔽的用户数量
+func (r *postExclusionRepository) CountExclusions(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PostExclusion{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// DeleteAllByUser 删除用户设置的屏蔽以及屏蔽该用户的记录
+func (r *postExclusionRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? OR target_id = ?", userID, userID).Delete(&model.PostExclusion{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+	"app/pkg/database/dbtest"
+	"app/pkg/geohash"
+
+	"gorm.io/gorm"
+)
+
+// exclusionFixture 屏蔽测试数据：author屏蔽了viewer，other未屏蔽，两人都是viewer的好友且被viewer关注
+type exclusionFixture struct {
+	tx     *gorm.DB
+	viewer *model.User
+	author *model.User
+	other  *model.User
+	hidden []*model.Post // author的动态，viewer在任何列表中都不应看到
+	shown  *model.Post   // other的公开动态，作为对照
+}
+
+// newExclusionFixture 创建屏蔽测试数据，所有动态都带有同一位置，使其同时出现在附近和发现页的查询范围内
+func newExclusionFixture(t *testing.T) *exclusionFixture {
+	t.Helper()
+	tx := dbtest.Begin(t, testDB)
+	f := &exclusionFixture{
+		tx:     tx,
+		viewer: createTestUser(t, tx, "viewer"),
+		author: createTestUser(t, tx, "author"),
+		other:  createTestUser(t, tx, "other"),
+	}
+	for _, user := range []*model.User{f.author, f.other} {
+		createTestFollow(t, tx, f.viewer.ID, user.ID)
+		createTestFriend(t, tx, f.viewer.ID, user.ID)
+	}
+
+	location := &model.Location{Latitude: 22.54, Longitude: 114.06, Geohash: geohash.Encode(22.54, 114.06, 12)}
+	if err := tx.Create(location).Error; err != nil {
+		t.Fatalf("创建位置失败: %v", err)
+	}
+	for _, visibility := range []constant.Visibility{constant.VisibilityPublic, constant.VisibilityFriends} {
+		post := createTestPost(t, tx, f.author.ID, visibility)
+		f.hidden = append(f.hidden, post)
+	}
+	f.shown = createTestPost(t, tx, f.other.ID, constant.VisibilityPublic)
+	if err := tx.Model(&model.Post{}).Where("user_id IN ?", []uint{f.author.ID, f.other.ID}).Update("location_id", location.ID).Error; err != nil {
+		t.Fatalf("关联位置失败: %v", err)
+	}
+
+	exclusion := &model.PostExclusion{UserID: f.author.ID, TargetID: f.viewer.ID}
+	if err := tx.Create(exclusion).Error; err != nil {
+		t.Fatalf("创建屏蔽记录失败: %v", err)
+	}
+	return f
+}
+
+// assertNoHidden 检查结果中不包含被屏蔽作者的动态，且包含对照动态
+func (f *exclusionFixture) assertNoHidden(t *testing.T, name string, ids []uint) {
+	t.Helper()
+	for _, post := range f.hidden {
+		if slices.Contains(ids, post.ID) {
+			t.Errorf("%s 返回了屏蔽查看者的作者的动态 %d", name, post.ID)
+		}
+	}
+	if !slices.Contains(ids, f.shown.ID) {
+		t.Errorf("%s 未返回对照动态 %d，结果 %v", name, f.shown.ID, ids)
+	}
+}
+
+func TestPostExclusionFeeds(t *testing.T) {
+	f := newExclusionFixture(t)
+	ctx := context.Background()
+	repo := NewPostRepository(f.tx)
+	since := time.Now().Add(-time.Hour)
+	box := GeoBox{MinLat: 22, MaxLat: 23, MinLng: 114, MaxLng: 115}
+
+	t.Run("GetFollowingPosts", func(t *testing.T) {
+		posts, _, err := repo.GetFollowingPosts(ctx, f.viewer.ID, 1, 10)
+		if err != nil {
+			t.Fatalf("GetFollowingPosts() error = %v", err)
+		}
+		f.assertNoHidden(t, "GetFollowingPosts", postIDs(posts))
+	})
+
+	t.Run("GetPostsByIDs", func(t *testing.T) {
+		ids := []uint{f.shown.ID}
+		for _, post := range f.hidden {
+			ids = append(ids, post.ID)
+		}
+		posts, err := repo.GetPostsByIDs(ctx, ids, f.viewer.ID)
+		if err != nil {
+			t.Fatalf("GetPostsByIDs() error = %v", err)
+		}
+		f.assertNoHidden(t, "GetPostsByIDs", postIDs(posts))
+	})
+
+	t.Run("GetNearbyPosts", func(t *testing.T) {
+		posts, err := repo.GetNearbyPosts(ctx, f.viewer.ID, box, since, 10)
+		if err != nil {
+			t.Fatalf("GetNearbyPosts() error = %v", err)
+		}
+		f.assertNoHidden(t, "GetNearbyPosts", postIDs(posts))
+	})
+
+	t.Run("GetPopularPosts", func(t *testing.T) {
+		posts, err := repo.GetPopularPosts(ctx, f.viewer.ID, since, 10)
+		if err != nil {
+			t.Fatalf("GetPopularPosts() error = %v", err)
+		}
+		f.assertNoHidden(t, "GetPopularPosts", postIDs(posts))
+	})
+
+	t.Run("GetNearbyClusters", func(t *testing.T) {
+		prefix := geohash.Encode(22.54, 114.06, 5)
+		clusters, err := repo.GetNearbyClusters(ctx, f.viewer.ID, box, []string{prefix}, 5, since, 10)
+		if err != nil {
+			t.Fatalf("GetNearbyClusters() error = %v", err)
+		}
+		// 所有动态位于同一聚合格，屏蔽的动态不计入数量
+		if len(clusters) != 1 || clusters[0].Count != 1 || clusters[0].PostID != f.shown.ID {
+			t.Errorf("GetNearbyClusters() = %+v, want one cluster of post %d", clusters, f.shown.ID)
+		}
+	})
+}
+
+func TestPostExclusionProfile(t *testing.T) {
+	f := newExclusionFixture(t)
+	ctx := context.Background()
+	repo := NewPostRepository(f.tx)
+
+	t.Run("GetUserPosts", func(t *testing.T) {
+		posts, count, err := repo.GetUserPosts(ctx, f.author.ID, 1, 10, f.viewer.ID)
+		if err != nil {
+			t.Fatalf("GetUserPosts() error = %v", err)
+		}
+		if count != 0 || len(posts) != 0 {
+			t.Errorf("GetUserPosts() = %v (count %d), want none", postIDs(posts), count)
+		}
+
+		// 对照：未屏蔽的作者主页正常可见
+		posts, _, err = repo.GetUserPosts(ctx, f.other.ID, 1, 10, f.viewer.ID)
+		if err != nil {
+			t.Fatalf("GetUserPosts() error = %v", err)
+		}
+		if !slices.Contains(postIDs(posts), f.shown.ID) {
+			t.Errorf("GetUserPosts() = %v, want post %d", postIDs(posts), f.shown.ID)
+		}
+	})
+
+	t.Run("GetVisiblePost", func(t *testing.T) {
+		for _, post := range f.hidden {
+			if _, err := repo.GetVisiblePost(ctx, post.ID, f.viewer.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Errorf("GetVisiblePost(%d) error = %v, want gorm.ErrRecordNotFound", post.ID, err)
+			}
+		}
+		if _, err := repo.GetVisiblePost(ctx, f.shown.ID, f.viewer.ID); err != nil {
+			t.Errorf("GetVisiblePost(%d) error = %v", f.shown.ID, err)
+		}
+	})
+
+	t.Run("作者本人不受屏蔽影响", func(t *testing.T) {
+		posts, _, err := repo.GetUserPosts(ctx, f.author.ID, 1, 10, f.author.ID)
+		if err != nil {
+			t.Fatalf("GetUserPosts() error = %v", err)
+		}
+		if len(posts) != len(f.hidden) {
+			t.Errorf("GetUserPosts() = %v, want %d posts", postIDs(posts), len(f.hidden))
+		}
+	})
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"app/internal/model"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -9,17 +11,29 @@ import (
 // PostImageRepository 动态图片存储库接口
 type PostImageRepository interface {
 	// CreatePostImage 创建动态图片
-	CreatePostImage(image *model.PostImage) error
+	CreatePostImage(ctx context.Context, image *model.PostImage) error
 	// GetPostImages 获取动态的所有图片
-	GetPostImages(postID uint) ([]model.PostImage, error)
+	GetPostImages(ctx context.Context, postID uint) ([]model.PostImage, error)
+	// GetPostImagesWithDeleted 获取动态的所有图片，包含编辑时移除的图片
+	GetPostImagesWithDeleted(ctx context.Context, postID uint) ([]model.PostImage, error)
+	// GetPostImagesByPostIDs 批量获取多条动态的图片
+	GetPostImagesByPostIDs(ctx context.Context, postIDs []uint) ([]model.PostImage, error)
+	// GetPostImagesByIDs 根据ID列表批量获取图片，包含编辑时移除的图片
+	GetPostImagesByIDs(ctx context.Context, ids []uint) ([]model.PostImage, error)
 	// DeletePostImage 删除动态图片
-	DeletePostImage(id uint) error
+	DeletePostImage(ctx context.Context, id uint) error
 	// DeletePostImages 删除动态的所有图片
-	DeletePostImages(postID uint) error
+	DeletePostImages(ctx context.Context, postID uint) error
 	// FindByID 根据ID查找图片
-	FindByID(id uint) (*model.PostImage, error)
+	FindByID(ctx context.Context, id uint) (*model.PostImage, error)
 	// UpdatePostImage 更新图片信息
-	UpdatePostImage(image *model.PostImage) error
+	UpdatePostImage(ctx context.Context, image *model.PostImage) error
+	// FindByUserAndHash 根据内容摘要查找用户的动态图片
+	FindByUserAndHash(ctx context.Context, userID uint, hash string) (*model.PostImage, error)
+	// FindExpiringURLs 按ID顺序获取afterID之后预签名URL在指定时间前过期的图片，包含编辑时移除的图片
+	FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) ([]model.PostImage, error)
+	// UpdateURL 更新图片的访问URL、URL类型和过期时间，包含编辑时移除的图片
+	UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error
 }
 
 // postImageRepository 动态图片存储库实现
@@ -33,31 +47,58 @@ func NewPostImageRepository(db *gorm.DB) PostImageRepository {
 }
 
 // CreatePostImage 创建动态图片
-func (r *postImageRepository) CreatePostImage(image *model.PostImage) error {
-	return r.db.Create(image).Error
+func (r *postImageRepository) CreatePostImage(ctx context.Context, image *model.PostImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
 }
 
 // GetPostImages 获取动态的所有图片
-func (r *postImageRepository) GetPostImages(postID uint) ([]model.PostImage, error) {
+func (r *postImageRepository) GetPostImages(ctx context.Context, postID uint) ([]model.PostImage, error) {
 	var images []model.PostImage
-	err := r.db.Where("post_id = ?", postID).Find(&images).Error
+	err := r.db.WithContext(ctx).Where("post_id = ?", postID).Find(&images).Error
+	return images, err
+}
+
+// GetPostImagesByPostIDs 批量获取多条动态的图片
+func (r *postImageRepository) GetPostImagesByPostIDs(ctx context.Context, postIDs []uint) ([]model.PostImage, error) {
+	var images []model.PostImage
+	if len(postIDs) == 0 {
+		return images, nil
+	}
+	err := r.db.WithContext(ctx).Where("post_id IN ?", postIDs).Find(&images).Error
+	return images, err
+}
+
+// GetPostImagesWithDeleted 获取动态的所有图片，包含编辑时移除的图片
+func (r *postImageRepository) GetPostImagesWithDeleted(ctx context.Context, postID uint) ([]model.PostImage, error) {
+	var images []model.PostImage
+	err := r.db.WithContext(ctx).Unscoped().Where("post_id = ?", postID).Find(&images).Error
+	return images, err
+}
+
+// GetPostImagesByIDs 根据ID列表批量获取图片，包含编辑时移除的图片，不保证返回顺序
+func (r *postImageRepository) GetPostImagesByIDs(ctx context.Context, ids []uint) ([]model.PostImage, error) {
+	var images []model.PostImage
+	if len(ids) == 0 {
+		return images, nil
+	}
+	err := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Find(&images).Error
 	return images, err
 }
 
 // DeletePostImage 删除动态图片
-func (r *postImageRepository) DeletePostImage(id uint) error {
-	return r.db.Delete(&model.PostImage{}, id).Error
+func (r *postImageRepository) DeletePostImage(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.PostImage{}, id).Error
 }
 
 // DeletePostImages 删除动态的所有图片
-func (r *postImageRepository) DeletePostImages(postID uint) error {
-	return r.db.Where("post_id = ?", postID).Delete(&model.PostImage{}).Error
+func (r *postImageRepository) DeletePostImages(ctx context.Context, postID uint) error {
+	return r.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&model.PostImage{}).Error
 }
 
 // FindByID 根据ID查找图片
-func (r *postImageRepository) FindByID(id uint) (*model.PostImage, error) {
+func (r *postImageRepository) FindByID(ctx context.Context, id uint) (*model.PostImage, error) {
 	var image model.PostImage
-	err := r.db.First(&image, id).Error
+	err := r.db.WithContext(ctx).First(&image, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +106,31 @@ func (r *postImageRepository) FindByID(id uint) (*model.PostImage, error) {
 }
 
 // UpdatePostImage 更新图片信息
-func (r *postImageRepository) UpdatePostImage(image *model.PostImage) error {
-	return r.db.Save(image).Error
+func (r *postImageRepository) UpdatePostImage(ctx context.Context, image *model.PostImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// FindByUserAndHash 根据内容摘要查找用户的动态图片
+func (r *postImageRepository) FindByUserAndHash(ctx context.Context, userID uint, hash string) (*model.PostImage, error) {
+	var image model.PostImage
+	err := r.db.WithContext(ctx).Where("user_id = ? AND content_hash = ?", userID, hash).Order("id DESC").First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// FindExpiringURLs 按ID顺序获取afterID之后预签名URL在指定时间前过期的图片，编辑时移除的图片仍在历史版本中展示
+func (r *postImageRepository) FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) ([]model.PostImage, error) {
+	var images []model.PostImage
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("id > ? AND url_presigned = ? AND url_expires_at < ?", afterID, true, before).
+		Order("id").Limit(limit).Find(&images).Error
+	return images, err
+}
+
+// UpdateURL 更新图片的访问URL、URL类型和过期时间，不修改更新时间
+func (r *postImageRepository) UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&model.PostImage{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"url": url, "url_presigned": presigned, "url_expires_at": expiresAt}).Error
 }
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// PostRevisionRepository 动态修订记录仓库接口
+type PostRevisionRepository interface {
+	// GetPostRevisions 获取动态的修订记录，按编辑时间倒序
+	GetPostRevisions(ctx context.Context, postID uint, page, size int) ([]model.PostRevision, int64, error)
+	// UpdatePostWithRevision 在事务中更新动态内容、移除图片并记录修订
+	UpdatePostWithRevision(ctx context.Context, post *model.Post, revision *model.PostRevision, removedImageIDs []uint) error
+}
+
+// postRevisionRepository 动态修订记录仓库实现
+type postRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostRevisionRepository 创建动态修订记录仓库实例
+func NewPostRevisionRepository(db *gorm.DB) PostRevisionRepository {
+	return &postRevisionRepository{db: db}
+}
+
+// GetPostRevisions 获取动态的修订记录，按编辑时间倒序
+func (r *postRevisionRepository) GetPostRevisions(ctx context.Context, postID uint, page, size int) ([]model.PostRevision, int64, error) {
+	var revisions []model.PostRevision
+	var count int64
+
+	offset := (page - 1) * size
+	query := r.db.WithContext(ctx).Model(&model.PostRevision{}).Where("post_id = ?", postID)
+
+	// 计算总数
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据
+	err = query.Order("id DESC").Offset(offset).Limit(size).Find(&revisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, count, nil
+}
+
+// UpdatePostWithRevision 在事务中更新动态内容和编辑时间、软删除移除的图片并记录修订
+// 移除的图片只删除记录，文件保留到动态永久清除，以便查看修订记录
+func (r *postRevisionRepository) UpdatePostWithRevision(ctx context.Context, post *model.Post, revision *model.PostRevision, removedImageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&model.Post{}).Where("id = ?", post.ID).
+			Updates(map[string]interface{}{"content": post.Content, "language": post.Language, "link_preview_id": post.LinkPreviewID, "edited_at": now}).Error
+		if err != nil {
+			return fmt.Errorf("更新动态失败: %w", err)
+		}
+		post.EditedAt = &now
+
+		if len(removedImageIDs) > 0 {
+			err := tx.Where("post_id = ? AND id IN ?", post.ID, removedImageIDs).Delete(&model.PostImage{}).Error
+			if err != nil {
+				return fmt.Errorf("移除动态图片失败: %w", err)
+			}
+		}
+
+		revision.CreatedAt = now
+		if err := tx.Create(revision).Error; err != nil {
+			return fmt.Errorf("记录动态修订失败: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostStatsRepository 动态每日数据仓库接口
+type PostStatsRepository interface {
+	// SavePostStats 批量保存动态每日数据，同一动态同一日期已存在时覆盖
+	SavePostStats(ctx context.Context, stats []model.PostStats) error
+	// GetUserPostStats 获取作者在日期范围内的动态每日数据，postID为0时查询作者的全部动态
+	GetUserPostStats(ctx context.Context, userID, postID uint, startDate, endDate time.Time) ([]model.PostStats, error)
+}
+
+// postStatsRepository 动态每日数据仓库实现
+type postStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewPostStatsRepository 创建动态每日数据仓库实例
+func NewPostStatsRepository(db *gorm.DB) PostStatsRepository {
+	return &postStatsRepository{db: db}
+}
+
+// SavePostStats 批量保存动态每日数据，同一动态同一日期已存在时覆盖
+// 汇总任务每次写入当日的完整数值，重复执行结果不变
+func (r *postStatsRepository) SavePostStats(ctx context.Context, stats []model.PostStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "post_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"user_id", "views", "likes", "comments", "shares", "follower_gains", "updated_at",
+		}),
+	}).Create(&stats).Error
+}
+
+// GetUserPostStats 获取作者在日期范围内的动态每日数据，按日期升序
+func (r *postStatsRepository) GetUserPostStats(ctx context.Context, userID, postID uint, startDate, endDate time.Time) ([]model.PostStats, error) {
+	var list []model.PostStats
+	query := r.db.WithContext(ctx).Where("user_id = ? AND date >= ? AND date <= ?", userID, startDate, endDate)
+	if postID != 0 {
+		query = query.Where("post_id = ?", postID)
+	}
+	err := query.Order("date ASC, post_id ASC").Find(&list).Error
+	return list, err
+}
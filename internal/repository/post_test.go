@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+	"app/pkg/database/dbtest"
+)
+
+func TestGetFollowingPosts(t *testing.T) {
+	tx := dbtest.Begin(t, testDB)
+	ctx := context.Background()
+
+	viewer := createTestUser(t, tx, "viewer")
+	followed := createTestUser(t, tx, "followed")
+	friend := createTestUser(t, tx, "friend")
+	stranger := createTestUser(t, tx, "stranger")
+	createTestFollow(t, tx, viewer.ID, followed.ID)
+	createTestFriend(t, tx, viewer.ID, friend.ID)
+
+	followedPublic := createTestPost(t, tx, followed.ID, constant.VisibilityPublic)
+	createTestPost(t, tx, followed.ID, constant.VisibilityFriends)
+	friendOnly := createTestPost(t, tx, friend.ID, constant.VisibilityFriends)
+	createTestPost(t, tx, friend.ID, constant.VisibilityPrivate)
+	createTestPost(t, tx, stranger.ID, constant.VisibilityPublic)
+
+	posts, count, err := NewPostRepository(tx).GetFollowingPosts(ctx, viewer.ID, 1, 10)
+	if err != nil {
+		t.Fatalf("GetFollowingPosts() error = %v", err)
+	}
+	got := postIDs(posts)
+	slices.Sort(got)
+	want := []uint{followedPublic.ID, friendOnly.ID}
+	if count != int64(len(want)) || !slices.Equal(got, want) {
+		t.Errorf("GetFollowingPosts() = %v (count %d), want %v", got, count, want)
+	}
+}
+
+func TestPurgePost(t *testing.T) {
+	tx := dbtest.Begin(t, testDB)
+	ctx := context.Background()
+	repo := NewPostRepository(tx)
+
+	author := createTestUser(t, tx, "author")
+	post := createTestPost(t, tx, author.ID, constant.VisibilityPublic)
+	kept := createTestPost(t, tx, author.ID, constant.VisibilityPublic)
+	today := time.Now().Truncate(24 * time.Hour)
+	for _, id := range []uint{post.ID, kept.ID} {
+		if err := tx.Create(&model.PostStats{PostID: id, UserID: author.ID, Date: today, Views: 1}).Error; err != nil {
+			t.Fatalf("创建动态每日数据失败: %v", err)
+		}
+	}
+
+	if err := repo.DeletePost(ctx, post.ID); err != nil {
+		t.Fatalf("DeletePost() error = %v", err)
+	}
+	if err := repo.PurgePost(ctx, post.ID); err != nil {
+		t.Fatalf("PurgePost() error = %v", err)
+	}
+
+	var posts, stats int64
+	tx.Unscoped().Model(&model.Post{}).Where("id = ?", post.ID).Count(&posts)
+	tx.Model(&model.PostStats{}).Where("post_id = ?", post.ID).Count(&stats)
+	if posts != 0 || stats != 0 {
+		t.Errorf("PurgePost() left %d posts and %d stats rows", posts, stats)
+	}
+	tx.Model(&model.PostStats{}).Where("post_id = ?", kept.ID).Count(&stats)
+	if stats != 1 {
+		t.Errorf("PurgePost() removed stats of other posts, remaining %d", stats)
+	}
+}
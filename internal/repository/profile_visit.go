@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// VisitSummary 按用户汇总的主页访问记录
+type VisitSummary struct {
+	UserID        uint      // 访客或被访问的用户ID
+	Visits        int64     // 访问次数
+	LastVisitedAt time.Time // 最近一次访问时间
+}
+
+// ProfileVisitRepository 主页访问记录仓库接口
+type ProfileVisitRepository interface {
+	// Create 创建访问记录
+	Create(ctx context.Context, visit *model.ProfileVisit) error
+	// GetVisitors 按访客汇总指定时间之后访问过用户主页的记录，最近访问的在前
+	// 只包含正常状态且开启了访问足迹的访客
+	GetVisitors(ctx context.Context, visiteeID uint, since time.Time, page, size int) ([]VisitSummary, int64, error)
+	// GetVisited 按被访问的用户汇总指定时间之后用户访问过的主页，最近访问的在前，只包含正常状态的用户
+	GetVisited(ctx context.Context, visitorID uint, since time.Time, page, size int) ([]VisitSummary, int64, error)
+	// DeleteBefore 删除指定时间之前的访问记录，每次最多删除limit条，返回删除的数量
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	// DeleteAllByUser 删除用户作为访客或被访问者的所有访问记录
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// profileVisitRepository 主页访问记录仓库实现
+type profileVisitRepository struct {
+	db *gorm.DB
+}
+
+// NewProfileVisitRepository 创建主页访问记录仓库实例
+func NewProfileVisitRepository(db *gorm.DB) ProfileVisitRepository {
+	return &profileVisitRepository{db: db}
+}
+
+// Create 创建访问记录
+func (r *profileVisitRepository) Create(ctx context.Context, visit *model.ProfileVisit) error {
+	return r.db.WithContext(ctx).Create(visit).Error
+}
+
+// GetVisitors 按访客汇总访问过用户主页的记录
+func (r *profileVisitRepository) GetVisitors(ctx context.Context, visiteeID uint, since time.Time, page, size int) ([]VisitSummary, int64, error) {
+	query := r.db.WithContext(ctx).Table("profile_visit AS v").
+		Joins("JOIN user AS u ON u.id = v.visitor_id AND u.status = ? AND u.record_visits = ? AND u.deleted_at IS NULL", constant.UserStatusNormal, true).
+		Where("v.visitee_id = ? AND v.created_at >= ?", visiteeID, since)
+	return r.summarize(query, "v.visitor_id", page, size)
+}
+
+// GetVisited 按被访问的用户汇总用户访问过的主页
+func (r *profileVisitRepository) GetVisited(ctx context.Context, visitorID uint, since time.Time, page, size int) ([]VisitSummary, int64, error) {
+	query := r.db.WithContext(ctx).Table("profile_visit AS v").
+		Joins("JOIN user AS u ON u.id = v.visitee_id AND u.status = ? AND u.deleted_at IS NULL", constant.UserStatusNormal).
+		Where("v.visitor_id = ? AND v.created_at >= ?", visitorID, since)
+	return r.summarize(query, "v.visitee_id", page, size)
+}
+
+// summarize 按指定字段分组汇总访问次数和最近访问时间，返回分页结果和分组总数
+func (r *profileVisitRepository) summarize(query *gorm.DB, groupColumn string, page, size int) ([]VisitSummary, int64, error) {
+	var count int64
+	if err := query.Session(&gorm.Session{}).Distinct(groupColumn).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var summaries []VisitSummary
+	err := query.Select(groupColumn + " AS user_id, COUNT(*) AS visits, MAX(v.created_at) AS last_visited_at").
+		Group(groupColumn).
+		Order("last_visited_at DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return summaries, count, nil
+}
+
+// DeleteBefore 分批删除过期的访问记录
+func (r *profileVisitRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.ProfileVisit{}).
+		Where("created_at < ?", before).
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.ProfileVisit{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteAllByUser 删除用户的所有访问记录
+func (r *profileVisitRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("visitor_id = ? OR visitee_id = ?", userID, userID).Delete(&model.ProfileVisit{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal/constant"
+
+	"gorm.io/gorm"
+)
+
+// PublicIDTable 使用公开ID的数据表
+type PublicIDTable struct {
+	Kind  string // 资源类型，对应constant.PublicIDUser等
+	Table string // 数据表
+}
+
+// PublicIDTables 使用公开ID的数据表，key为资源类型
+var PublicIDTables = map[string]PublicIDTable{
+	constant.PublicIDUser:    {Kind: constant.PublicIDUser, Table: "user"},
+	constant.PublicIDPost:    {Kind: constant.PublicIDPost, Table: "post"},
+	constant.PublicIDComment: {Kind: constant.PublicIDComment, Table: "post_comment"},
+}
+
+// PublicIDRepository 公开ID映射仓库接口
+// 直接按表名查询，已软删除的记录同样可以映射，是否可见由业务查询决定
+type PublicIDRepository interface {
+	// FindIDs 根据公开ID批量查询内部ID，返回公开ID到内部ID的映射，不存在的公开ID不在结果中
+	FindIDs(ctx context.Context, table PublicIDTable, publicIDs []string) (map[string]uint, error)
+	// FindPublicIDs 根据内部ID批量查询公开ID，返回内部ID到公开ID的映射，不存在或尚未回填的ID不在结果中
+	FindPublicIDs(ctx context.Context, table PublicIDTable, ids []uint) (map[uint]string, error)
+}
+
+// publicIDRepository 公开ID映射仓库实现
+type publicIDRepository struct {
+	db *gorm.DB
+}
+
+// NewPublicIDRepository 创建公开ID映射仓库实例
+func NewPublicIDRepository(db *gorm.DB) PublicIDRepository {
+	return &publicIDRepository{db: db}
+}
+
+// publicIDRow 公开ID映射查询结果
+type publicIDRow struct {
+	ID       uint
+	PublicID string
+}
+
+// FindIDs 根据公开ID批量查询内部ID
+func (r *publicIDRepository) FindIDs(ctx context.Context, table PublicIDTable, publicIDs []string) (map[string]uint, error) {
+	result := make(map[string]uint, len(publicIDs))
+	if len(publicIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []publicIDRow
+	err := r.db.WithContext(ctx).Table(table.Table).
+		Select("`id`, `public_id`").
+		Where("`public_id` IN ?", publicIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.PublicID] = row.ID
+	}
+	return result, nil
+}
+
+// FindPublicIDs 根据内部ID批量查询公开ID
+func (r *publicIDRepository) FindPublicIDs(ctx context.Context, table PublicIDTable, ids []uint) (map[uint]string, error) {
+	result := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []publicIDRow
+	err := r.db.WithContext(ctx).Table(table.Table).
+		Select("`id`, `public_id`").
+		Where("`id` IN ? AND `public_id` <> ''", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ID] = row.PublicID
+	}
+	return result, nil
+}
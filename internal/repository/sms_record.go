@@ -2,6 +2,7 @@ package repository
 
 import (
 	"app/internal/model"
+	"context"
 
 	"gorm.io/gorm"
 )
@@ -9,11 +10,15 @@ import (
 // SMSRepository SMS记录仓库接口
 type SMSRepository interface {
 	// Create 创建SMS记录
-	Create(record *model.SMSRecord) error
+	Create(ctx context.Context, record *model.SMSRecord) error
 	// FindByPhoneNumber 根据手机号查找SMS记录
-	FindByPhoneNumber(phoneNumber string, limit int) ([]*model.SMSRecord, error)
+	FindByPhoneNumber(ctx context.Context, phoneNumber string, limit int) ([]*model.SMSRecord, error)
 	// FindByID 根据ID查找SMS记录
-	FindByID(id uint) (*model.SMSRecord, error)
+	FindByID(ctx context.Context, id uint) (*model.SMSRecord, error)
+	// Update 更新SMS记录
+	Update(ctx context.Context, record *model.SMSRecord) error
+	// ScrubByPhoneNumber 清除指定手机号相关短信记录中的个人信息
+	ScrubByPhoneNumber(ctx context.Context, phoneNumber string) (int64, error)
 }
 
 // smsRepository SMS记录仓库实现
@@ -29,15 +34,15 @@ func NewSMSRepository(db *gorm.DB) SMSRepository {
 }
 
 // Create 创建SMS记录
-func (r *smsRepository) Create(record *model.SMSRecord) error {
-	result := r.db.Create(record)
+func (r *smsRepository) Create(ctx context.Context, record *model.SMSRecord) error {
+	result := r.db.WithContext(ctx).Create(record)
 	return result.Error
 }
 
 // FindByPhoneNumber 根据手机号查找SMS记录
-func (r *smsRepository) FindByPhoneNumber(phoneNumber string, limit int) ([]*model.SMSRecord, error) {
+func (r *smsRepository) FindByPhoneNumber(ctx context.Context, phoneNumber string, limit int) ([]*model.SMSRecord, error) {
 	var records []*model.SMSRecord
-	result := r.db.Where("phone_number = ?", phoneNumber).Order("created_at DESC").Limit(limit).Find(&records)
+	result := r.db.WithContext(ctx).Where("phone_number = ?", phoneNumber).Order("created_at DESC").Limit(limit).Find(&records)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -45,9 +50,9 @@ func (r *smsRepository) FindByPhoneNumber(phoneNumber string, limit int) ([]*mod
 }
 
 // FindByID 根据ID查找SMS记录
-func (r *smsRepository) FindByID(id uint) (*model.SMSRecord, error) {
+func (r *smsRepository) FindByID(ctx context.Context, id uint) (*model.SMSRecord, error) {
 	var record model.SMSRecord
-	result := r.db.First(&record, id)
+	result := r.db.WithContext(ctx).First(&record, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, ErrRecordNotFound
@@ -56,3 +61,21 @@ func (r *smsRepository) FindByID(id uint) (*model.SMSRecord, error) {
 	}
 	return &record, nil
 }
+
+// Update 更新SMS记录
+func (r *smsRepository) Update(ctx context.Context, record *model.SMSRecord) error {
+	return r.db.WithContext(ctx).Save(record).Error
+}
+
+// ScrubByPhoneNumber 清除指定手机号相关短信记录中的个人信息
+// 保留记录本身用于费用统计，仅清空手机号、内容和模板参数
+func (r *smsRepository) ScrubByPhoneNumber(ctx context.Context, phoneNumber string) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Model(&model.SMSRecord{}).
+		Where("phone_number = ?", phoneNumber).
+		Updates(map[string]interface{}{
+			"phone_number":   "",
+			"content":        "",
+			"template_param": "",
+		})
+	return result.RowsAffected, result.Error
+}
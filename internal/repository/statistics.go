@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StatisticsRepository 数据统计仓库接口
+type StatisticsRepository interface {
+	// 聚合查询方法
+	// CountUsers 统计截止到指定时间的累计用户数
+	CountUsers(ctx context.Context, end time.Time) (int64, error)
+	// CountNewUsers 统计时间范围内的新注册用户数
+	CountNewUsers(ctx context.Context, start, end time.Time) (int64, error)
+	// CountPosts 统计时间范围内的新增动态数
+	CountPosts(ctx context.Context, start, end time.Time) (int64, error)
+	// CountComments 统计时间范围内的新增评论数
+	CountComments(ctx context.Context, start, end time.Time) (int64, error)
+	// CountSuccessSMS 统计时间范围内发送成功的短信数
+	CountSuccessSMS(ctx context.Context, start, end time.Time) (int64, error)
+	// SumStorageBytes 统计当前图片存储占用字节数
+	SumStorageBytes(ctx context.Context) (int64, error)
+
+	// 快照方法
+	// SaveDailyStatistics 保存每日统计快照，同一日期已存在时覆盖
+	SaveDailyStatistics(ctx context.Context, stats *model.DailyStatistics) error
+	// GetDailyStatistics 获取日期范围内的统计快照
+	GetDailyStatistics(ctx context.Context, startDate, endDate time.Time) ([]model.DailyStatistics, error)
+}
+
+// statisticsRepository 数据统计仓库实现
+type statisticsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatisticsRepository 创建数据统计仓库实例
+func NewStatisticsRepository(db *gorm.DB) StatisticsRepository {
+	return &statisticsRepository{db: db}
+}
+
+// CountUsers 统计截止到指定时间的累计用户数
+func (r *statisticsRepository) CountUsers(ctx context.Context, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("created_at < ?", end).Count(&count).Error
+	return count, err
+}
+
+// CountNewUsers 统计时间范围内的新注册用户数
+func (r *statisticsRepository) CountNewUsers(ctx context.Context, start, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("created_at >= ? AND created_at < ?", start, end).Count(&count).Error
+	return count, err
+}
+
+// CountPosts 统计时间范围内的新增动态数
+func (r *statisticsRepository) CountPosts(ctx context.Context, start, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Post{}).Where("created_at >= ? AND created_at < ?", start, end).Count(&count).Error
+	return count, err
+}
+
+// CountComments 统计时间范围内的新增评论数
+func (r *statisticsRepository) CountComments(ctx context.Context, start, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PostComment{}).Where("created_at >= ? AND created_at < ?", start, end).Count(&count).Error
+	return count, err
+}
+
+// CountSuccessSMS 统计时间范围内发送成功的短信数
+func (r *statisticsRepository) CountSuccessSMS(ctx context.Context, start, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SMSRecord{}).
+		Where("status = ? AND created_at >= ? AND created_at < ?", constant.SMSStatusSuccess, start, end).
+		Count(&count).Error
+	return count, err
+}
+
+// SumStorageBytes 统计当前图片存储占用字节数（动态图片与临时图片之和）
+func (r *statisticsRepository) SumStorageBytes(ctx context.Context) (int64, error) {
+	var postBytes, tempBytes int64
+	if err := r.db.WithContext(ctx).Model(&model.PostImage{}).Select("COALESCE(SUM(size), 0)").Scan(&postBytes).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.WithContext(ctx).Model(&model.TempImage{}).Select("COALESCE(SUM(size), 0)").Scan(&tempBytes).Error; err != nil {
+		return 0, err
+	}
+	return postBytes + tempBytes, nil
+}
+
+// SaveDailyStatistics 保存每日统计快照，同一日期已存在时覆盖
+func (r *statisticsRepository) SaveDailyStatistics(ctx context.Context, stats *model.DailyStatistics) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"active_users", "new_users", "total_users", "new_posts", "new_comments",
+			"sms_count", "sms_cost", "storage_bytes", "updated_at",
+		}),
+	}).Create(stats).Error
+}
+
+// GetDailyStatistics 获取日期范围内的统计快照
+func (r *statisticsRepository) GetDailyStatistics(ctx context.Context, startDate, endDate time.Time) ([]model.DailyStatistics, error) {
+	var list []model.DailyStatistics
+	err := r.db.WithContext(ctx).Where("date >= ? AND date <= ?", startDate, endDate).Order("date ASC").Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository 用户标签仓库接口
+type TagRepository interface {
+	// 标签相关
+	Create(ctx context.Context, tag *model.Tag) error
+	GetByID(ctx context.Context, id uint) (*model.Tag, error)
+	FindByNames(ctx context.Context, names []string) ([]model.Tag, error)
+	List(ctx context.Context) ([]model.Tag, error)
+	FindRuleTags(ctx context.Context) ([]model.Tag, error)
+	Update(ctx context.Context, tag *model.Tag) error
+	Delete(ctx context.Context, id uint) error
+	// 成员相关
+	AddMembers(ctx context.Context, tagID uint, userIDs []uint) (int64, error)
+	RemoveMembers(ctx context.Context, tagID uint, userIDs []uint) (int64, error)
+	GetMembers(ctx context.Context, tagID uint, page, size int) ([]model.UserTag, int64, error)
+	GetMemberIDs(ctx context.Context, tagName string, afterID uint, limit int) ([]uint, error)
+	GetUserTags(ctx context.Context, userID uint) ([]model.Tag, error)
+	RefreshMemberCount(ctx context.Context, tagID uint) error
+	// 规则标签计算
+	FindRuleMatches(ctx context.Context, tag *model.Tag, now time.Time, afterID uint, limit int) ([]uint, error)
+	UpsertRuleMembers(ctx context.Context, tagID uint, userIDs []uint, refreshedAt time.Time) error
+	FinishRefresh(ctx context.Context, tagID uint, refreshedAt time.Time) (int64, error)
+	// 账号注销
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// tagRepository 用户标签仓库实现
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 创建用户标签仓库实例
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create 创建标签
+func (r *tagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+// GetByID 获取标签
+func (r *tagRepository) GetByID(ctx context.Context, id uint) (*model.Tag, error) {
+	var tag model.Tag
+	result := r.db.WithContext(ctx).First(&tag, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// FindByNames 按名称批量获取标签，不存在的名称忽略
+func (r *tagRepository) FindByNames(ctx context.Context, names []string) ([]model.Tag, error) {
+	var tags []model.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+	err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&tags).Error
+	return tags, err
+}
+
+// List 获取所有标签，按名称排序
+func (r *tagRepository) List(ctx context.Context) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+// FindRuleTags 获取所有规则标签
+func (r *tagRepository) FindRuleTags(ctx context.Context) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).Where("type = ?", constant.TagTypeRule).Order("id").Find(&tags).Error
+	return tags, err
+}
+
+// Update 更新标签
+func (r *tagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	return r.db.WithContext(ctx).Save(tag).Error
+}
+
+// Delete 删除标签及其成员记录
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&model.UserTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Tag{}, id).Error
+	})
+}
+
+// AddMembers 批量添加标签成员，已有该标签的用户忽略，返回新增的数量
+func (r *tagRepository) AddMembers(ctx context.Context, tagID uint, userIDs []uint) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	members := make([]model.UserTag, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = model.UserTag{TagID: tagID, UserID: id, RefreshedAt: now}
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members)
+	return result.RowsAffected, result.Error
+}
+
+// RemoveMembers 批量移除标签成员，返回移除的数量
+func (r *tagRepository) RemoveMembers(ctx context.Context, tagID uint, userIDs []uint) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Where("tag_id = ? AND user_id IN ?", tagID, userIDs).Delete(&model.UserTag{})
+	return result.RowsAffected, result.Error
+}
+
+// GetMembers 分页获取标签成员，按加入时间倒序
+func (r *tagRepository) GetMembers(ctx context.Context, tagID uint, page, size int) ([]model.UserTag, int64, error) {
+	var members []model.UserTag
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&model.UserTag{}).Where("tag_id = ?", tagID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&members).Error
+	return members, count, err
+}
+
+// GetMemberIDs 按用户ID顺序分批获取指定名称标签的成员ID，用于推送等批量触达
+func (r *tagRepository) GetMemberIDs(ctx context.Context, tagName string, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.UserTag{}).
+		Where("tag_id = (?) AND user_id > ?", r.db.Model(&model.Tag{}).Select("id").Where("name = ?", tagName), afterID).
+		Order("user_id ASC").Limit(limit).Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// GetUserTags 获取用户拥有的所有标签
+func (r *tagRepository) GetUserTags(ctx context.Context, userID uint) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&model.UserTag{}).Select("tag_id").Where("user_id = ?", userID)).
+		Order("name").Find(&tags).Error
+	return tags, err
+}
+
+// RefreshMemberCount 重新统计标签的成员数量
+func (r *tagRepository) RefreshMemberCount(ctx context.Context, tagID uint) error {
+	return r.db.WithContext(ctx).Model(&model.Tag{}).Where("id = ?", tagID).
+		Update("member_count", r.db.Model(&model.UserTag{}).Select("COUNT(*)").Where("tag_id = ?", tagID)).Error
+}
+
+// FindRuleMatches 按用户ID顺序分批查找满足规则标签条件的用户ID，规则无效时返回空
+func (r *tagRepository) FindRuleMatches(ctx context.Context, tag *model.Tag, now time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	var err error
+	switch tag.Rule {
+	case constant.TagRuleActiveDays:
+		err = r.db.WithContext(ctx).Model(&model.User{}).
+			Where("id > ? AND last_active_at >= ?", afterID, now.AddDate(0, 0, -tag.Threshold)).
+			Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	case constant.TagRuleMinPosts:
+		err = r.db.WithContext(ctx).Model(&model.Post{}).
+			Where("user_id > ?", afterID).
+			Group("user_id").Having("COUNT(*) >= ?", tag.Threshold).
+			Order("user_id ASC").Limit(limit).Pluck("user_id", &ids).Error
+	case constant.TagRuleMinFollowers:
+		err = r.db.WithContext(ctx).Model(&model.UserFollower{}).
+			Where("target_id > ? AND status = ?", afterID, constant.FollowStatusApproved).
+			Group("target_id").Having("COUNT(*) >= ?", tag.Threshold).
+			Order("target_id ASC").Limit(limit).Pluck("target_id", &ids).Error
+	}
+	return ids, err
+}
+
+// UpsertRuleMembers 批量写入规则标签成员，已是成员的更新匹配时间
+func (r *tagRepository) UpsertRuleMembers(ctx context.Context, tagID uint, userIDs []uint, refreshedAt time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]model.UserTag, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = model.UserTag{TagID: tagID, UserID: id, RefreshedAt: refreshedAt}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tag_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"refreshed_at"}),
+	}).Create(&members).Error
+}
+
+// FinishRefresh 完成规则标签的计算：移除本次未匹配的成员，并更新成员数量和计算时间，返回移除的数量
+func (r *tagRepository) FinishRefresh(ctx context.Context, tagID uint, refreshedAt time.Time) (int64, error) {
+	var removed int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tag_id = ? AND refreshed_at < ?", tagID, refreshedAt).Delete(&model.UserTag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected
+
+		return tx.Model(&model.Tag{}).Where("id = ?", tagID).Updates(map[string]interface{}{
+			"member_count": tx.Model(&model.UserTag{}).Select("COUNT(*)").Where("tag_id = ?", tagID),
+			"refreshed_at": refreshedAt,
+		}).Error
+	})
+	return removed, err
+}
+
+// DeleteAllByUser 删除用户的所有标签成员记录，返回删除的记录数
+func (r *tagRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UserTag{})
+	return result.RowsAffected, result.Error
+}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"app/internal/model"
 
 	"gorm.io/gorm"
@@ -9,15 +12,25 @@ import (
 // TempImageRepository 临时图片存储库接口
 type TempImageRepository interface {
 	// CreateTempImage 创建临时图片
-	CreateTempImage(image *model.TempImage) error
+	CreateTempImage(ctx context.Context, image *model.TempImage) error
 	// FindByID 根据ID查找临时图片
-	FindByID(id uint) (*model.TempImage, error)
+	FindByID(ctx context.Context, id uint) (*model.TempImage, error)
 	// UpdateTempImage 更新临时图片信息
-	UpdateTempImage(image *model.TempImage) error
+	UpdateTempImage(ctx context.Context, image *model.TempImage) error
 	// DeleteTempImage 删除临时图片
-	DeleteTempImage(id uint) error
+	DeleteTempImage(ctx context.Context, id uint) error
 	// GetUserTempImages 获取用户的所有临时图片
-	GetUserTempImages(userID uint) ([]model.TempImage, error)
+	GetUserTempImages(ctx context.Context, userID uint) ([]model.TempImage, error)
+	// GetUserTempImagesBefore 获取用户在指定时间之前上传的临时图片
+	GetUserTempImagesBefore(ctx context.Context, userID uint, before time.Time) ([]model.TempImage, error)
+	// FindByUserAndHash 根据内容摘要查找用户的临时图片
+	FindByUserAndHash(ctx context.Context, userID uint, hash string) (*model.TempImage, error)
+	// FindByObjectKey 根据对象键查找临时图片
+	FindByObjectKey(ctx context.Context, objectKey string) (*model.TempImage, error)
+	// FindExpiringURLs 按ID顺序获取afterID之后预签名URL在指定时间前过期的临时图片
+	FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) ([]model.TempImage, error)
+	// UpdateURL 更新临时图片的访问URL、URL类型和过期时间
+	UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error
 }
 
 // tempImageRepository 临时图片存储库实现
@@ -31,14 +44,14 @@ func NewTempImageRepository(db *gorm.DB) TempImageRepository {
 }
 
 // CreateTempImage 创建临时图片
-func (r *tempImageRepository) CreateTempImage(image *model.TempImage) error {
-	return r.db.Create(image).Error
+func (r *tempImageRepository) CreateTempImage(ctx context.Context, image *model.TempImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
 }
 
 // FindByID 根据ID查找临时图片
-func (r *tempImageRepository) FindByID(id uint) (*model.TempImage, error) {
+func (r *tempImageRepository) FindByID(ctx context.Context, id uint) (*model.TempImage, error) {
 	var image model.TempImage
-	err := r.db.First(&image, id).Error
+	err := r.db.WithContext(ctx).First(&image, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,18 +59,60 @@ func (r *tempImageRepository) FindByID(id uint) (*model.TempImage, error) {
 }
 
 // UpdateTempImage 更新临时图片信息
-func (r *tempImageRepository) UpdateTempImage(image *model.TempImage) error {
-	return r.db.Save(image).Error
+func (r *tempImageRepository) UpdateTempImage(ctx context.Context, image *model.TempImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
 }
 
 // DeleteTempImage 删除临时图片
-func (r *tempImageRepository) DeleteTempImage(id uint) error {
-	return r.db.Delete(&model.TempImage{}, id).Error
+func (r *tempImageRepository) DeleteTempImage(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.TempImage{}, id).Error
 }
 
 // GetUserTempImages 获取用户的所有临时图片
-func (r *tempImageRepository) GetUserTempImages(userID uint) ([]model.TempImage, error) {
+func (r *tempImageRepository) GetUserTempImages(ctx context.Context, userID uint) ([]model.TempImage, error) {
 	var images []model.TempImage
-	err := r.db.Where("user_id = ?", userID).Find(&images).Error
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&images).Error
 	return images, err
 }
+
+// GetUserTempImagesBefore 获取用户在指定时间之前上传的临时图片
+func (r *tempImageRepository) GetUserTempImagesBefore(ctx context.Context, userID uint, before time.Time) ([]model.TempImage, error) {
+	var images []model.TempImage
+	err := r.db.WithContext(ctx).Where("user_id = ? AND created_at < ?", userID, before).Find(&images).Error
+	return images, err
+}
+
+// FindByUserAndHash 根据内容摘要查找用户的临时图片
+func (r *tempImageRepository) FindByUserAndHash(ctx context.Context, userID uint, hash string) (*model.TempImage, error) {
+	var image model.TempImage
+	err := r.db.WithContext(ctx).Where("user_id = ? AND content_hash = ?", userID, hash).Order("id DESC").First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// FindByObjectKey 根据对象键查找临时图片
+func (r *tempImageRepository) FindByObjectKey(ctx context.Context, objectKey string) (*model.TempImage, error) {
+	var image model.TempImage
+	err := r.db.WithContext(ctx).Where("object_key = ?", objectKey).First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// FindExpiringURLs 按ID顺序获取afterID之后预签名URL在指定时间前过期的临时图片
+func (r *tempImageRepository) FindExpiringURLs(ctx context.Context, before time.Time, afterID uint, limit int) ([]model.TempImage, error) {
+	var images []model.TempImage
+	err := r.db.WithContext(ctx).
+		Where("id > ? AND url_presigned = ? AND url_expires_at < ?", afterID, true, before).
+		Order("id").Limit(limit).Find(&images).Error
+	return images, err
+}
+
+// UpdateURL 更新临时图片的访问URL、URL类型和过期时间，不修改更新时间
+func (r *tempImageRepository) UpdateURL(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.TempImage{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"url": url, "url_presigned": presigned, "url_expires_at": expiresAt}).Error
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"time"
 
+	"app/internal/constant"
 	"app/internal/model"
 
 	"gorm.io/gorm"
@@ -17,17 +21,52 @@ var (
 type UserRepository interface {
 	// 查询方法
 	// FindByID 根据ID查找用户
-	FindByID(id uint) (*model.User, error)
+	FindByID(ctx context.Context, id uint) (*model.User, error)
 	// FindByMobile 根据手机号查找用户
-	FindByMobile(mobile string) (*model.User, error)
+	FindByMobile(ctx context.Context, mobile string) (*model.User, error)
+	// SearchUsers 按昵称前缀或手机号搜索正常状态的用户，mobile为空时只匹配昵称
+	SearchUsers(ctx context.Context, nickname, mobile string, excludeID uint, page, size int) ([]model.User, int64, error)
+	// FindByMobileHashes 根据手机号摘要批量查找允许通讯录匹配的正常状态用户
+	FindByMobileHashes(ctx context.Context, hashes []string, excludeID uint) ([]model.User, error)
+	// FindNormalByIDs 根据ID批量查找正常状态的用户
+	FindNormalByIDs(ctx context.Context, ids []uint) ([]model.User, error)
+	// FindByUsername 根据用户名查找正常状态的用户，不区分大小写
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	// UsernameExists 判断用户名是否已被其他用户使用，包括已注销但尚未清除个人信息的用户
+	UsernameExists(ctx context.Context, username string, excludeID uint) (bool, error)
 
 	// 修改方法
 	// Create 创建用户
-	Create(user *model.User) error
+	Create(ctx context.Context, user *model.User) error
 	// Update 更新用户信息
-	Update(user *model.User) error
+	Update(ctx context.Context, user *model.User) error
 	// SoftDelete 软删除用户（注销账号）
-	SoftDelete(id uint) error
+	SoftDelete(ctx context.Context, id uint) error
+	// ScrubDeleted 清除已注销用户的个人信息
+	ScrubDeleted(ctx context.Context, id uint) error
+	// UpdateLastActiveAt 更新用户最近活跃时间，仅在新时间更晚时更新
+	UpdateLastActiveAt(ctx context.Context, id uint, activeAt time.Time) error
+	// FindInactiveUsers 查找在指定时间之前最后活跃的正常状态用户
+	FindInactiveUsers(ctx context.Context, before time.Time, limit int) ([]model.User, error)
+	// MarkDormant 将仍处于未活跃状态的正常用户标记为休眠，返回实际标记的数量
+	MarkDormant(ctx context.Context, ids []uint, before time.Time) (int64, error)
+	// FindActiveUserIDs 按ID顺序分批查找指定时间之后活跃过的用户ID
+	FindActiveUserIDs(ctx context.Context, since time.Time, afterID uint, limit int) ([]uint, error)
+	// StartDeactivation 将用户置为注销冷静期，冷静期在deactivateAt结束
+	StartDeactivation(ctx context.Context, id uint, deactivateAt time.Time) error
+	// CancelDeactivation 撤销处于注销冷静期的用户的注销，返回是否撤销成功
+	CancelDeactivation(ctx context.Context, id uint) (bool, error)
+	// FindDueDeactivations 查找注销冷静期在指定时间之前结束的用户
+	FindDueDeactivations(ctx context.Context, before time.Time, limit int) ([]model.User, error)
+	// FinalizeDeactivation 软删除注销冷静期在指定时间之前结束的用户，返回是否删除成功
+	FinalizeDeactivation(ctx context.Context, id uint, before time.Time) (bool, error)
+	// ChangeUsername 修改用户名并记录修改前的用户名，changedBefore之后修改过用户名时不修改，返回是否修改成功
+	// history为nil时不记录修改前的用户名
+	ChangeUsername(ctx context.Context, id uint, username string, changedBefore time.Time, history *model.UsernameHistory) (bool, error)
+	// GetTokenVersion 获取用户当前的令牌版本号
+	GetTokenVersion(ctx context.Context, id uint) (uint, error)
+	// IncrementTokenVersion 递增用户的令牌版本号，返回递增后的版本号
+	IncrementTokenVersion(ctx context.Context, id uint) (uint, error)
 }
 
 // userRepository 用户仓库实现
@@ -43,9 +82,9 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 // FindByID 根据ID查找用户
-func (r *userRepository) FindByID(id uint) (*model.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	result := r.db.First(&user, id)
+	result := r.db.WithContext(ctx).First(&user, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrRecordNotFound
@@ -56,9 +95,9 @@ func (r *userRepository) FindByID(id uint) (*model.User, error) {
 }
 
 // FindByMobile 根据手机号查找用户
-func (r *userRepository) FindByMobile(mobile string) (*model.User, error) {
+func (r *userRepository) FindByMobile(ctx context.Context, mobile string) (*model.User, error) {
 	var user model.User
-	result := r.db.Where("mobile = ?", mobile).First(&user)
+	result := r.db.WithContext(ctx).Where("mobile = ?", mobile).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrRecordNotFound
@@ -68,14 +107,91 @@ func (r *userRepository) FindByMobile(mobile string) (*model.User, error) {
 	return &user, nil
 }
 
+// SearchUsers 按昵称前缀或手机号搜索正常状态的用户
+// 手机号只精确匹配允许被手机号搜索的用户，mobile为空时只匹配昵称
+func (r *userRepository) SearchUsers(ctx context.Context, nickname, mobile string, excludeID uint, page, size int) ([]model.User, int64, error) {
+	var users []model.User
+	var count int64
+
+	offset := (page - 1) * size
+
+	// 转义LIKE通配符，只做前缀匹配以便使用昵称索引
+	escaped := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(nickname)
+	match := r.db.WithContext(ctx).Where("nickname LIKE ?", escaped+"%")
+	if mobile != "" {
+		match = match.Or("mobile = ? AND allow_mobile_search = ?", mobile, true)
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("status NOT IN ? AND id <> ?", []int{constant.UserStatusDisabled, constant.UserStatusDeactivating}, excludeID).
+		Where(match)
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("id ASC").Offset(offset).Limit(size).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, count, nil
+}
+
+// FindByMobileHashes 根据手机号摘要批量查找允许通讯录匹配的正常状态用户
+func (r *userRepository) FindByMobileHashes(ctx context.Context, hashes []string, excludeID uint) ([]model.User, error) {
+	var users []model.User
+	if len(hashes) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("mobile_hash IN ? AND allow_mobile_search = ?", hashes, true).
+		Where("status NOT IN ? AND id <> ?", []int{constant.UserStatusDisabled, constant.UserStatusDeactivating}, excludeID).
+		Find(&users).Error
+	return users, err
+}
+
+// FindNormalByIDs 根据ID批量查找正常状态的用户，已禁用、休眠或注销的用户不在结果中
+func (r *userRepository) FindNormalByIDs(ctx context.Context, ids []uint) ([]model.User, error) {
+	var users []model.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ? AND status = ?", ids, constant.UserStatusNormal).Find(&users).Error
+	return users, err
+}
+
+// FindByUsername 根据用户名查找正常状态的用户
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
+	var user model.User
+	result := r.db.WithContext(ctx).Where("username = ? AND status = ?", username, constant.UserStatusNormal).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// UsernameExists 判断用户名是否已被其他用户使用
+// 已注销的用户在清除个人信息前仍占用用户名，避免他人在数据保留期内冒用
+func (r *userRepository) UsernameExists(ctx context.Context, username string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).
+		Where("username = ? AND id <> ?", username, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // Create 创建用户
-func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
 // Update 更新用户信息
-func (r *userRepository) Update(user *model.User) error {
-	result := r.db.Save(user)
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	result := r.db.WithContext(ctx).Save(user)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -86,8 +202,8 @@ func (r *userRepository) Update(user *model.User) error {
 }
 
 // SoftDelete 软删除用户（注销账号）
-func (r *userRepository) SoftDelete(id uint) error {
-	result := r.db.Delete(&model.User{}, id)
+func (r *userRepository) SoftDelete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.User{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -96,3 +212,167 @@ func (r *userRepository) SoftDelete(id uint) error {
 	}
 	return nil
 }
+
+// ScrubDeleted 清除已注销用户的个人信息
+// 仅作用于已软删除的用户，清空手机号、用户名、昵称和头像
+func (r *userRepository) ScrubDeleted(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"mobile":      "",
+			"mobile_hash": "",
+			"username":    "",
+			"nickname":    "",
+			"avatar":      "",
+			"avatar_hash": "",
+			"password":    "",
+		}).Error
+}
+
+// UpdateLastActiveAt 更新用户最近活跃时间，仅在新时间更晚时更新
+func (r *userRepository) UpdateLastActiveAt(ctx context.Context, id uint, activeAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND (last_active_at IS NULL OR last_active_at < ?)", id, activeAt).
+		Update("last_active_at", activeAt).Error
+}
+
+// FindInactiveUsers 查找在指定时间之前最后活跃的正常状态用户
+// 从未记录活跃时间的用户按注册时间判断
+func (r *userRepository) FindInactiveUsers(ctx context.Context, before time.Time, limit int) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Where("status = ?", constant.UserStatusNormal).
+		Where("last_active_at < ? OR (last_active_at IS NULL AND created_at < ?)", before, before).
+		Order("id ASC").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// MarkDormant 将仍处于未活跃状态的正常用户标记为休眠，返回实际标记的数量
+// 更新时再次校验活跃时间，避免覆盖查询后刚活跃的用户
+func (r *userRepository) MarkDormant(ctx context.Context, ids []uint, before time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id IN ? AND status = ?", ids, constant.UserStatusNormal).
+		Where("last_active_at < ? OR (last_active_at IS NULL AND created_at < ?)", before, before).
+		Update("status", constant.UserStatusDormant)
+	return result.RowsAffected, result.Error
+}
+
+// FindActiveUserIDs 按ID顺序分批查找指定时间之后活跃过的用户ID
+func (r *userRepository) FindActiveUserIDs(ctx context.Context, since time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id > ? AND last_active_at >= ?", afterID, since).
+		Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// StartDeactivation 将用户置为注销冷静期
+func (r *userRepository) StartDeactivation(ctx context.Context, id uint, deactivateAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": constant.UserStatusDeactivating, "deactivate_at": deactivateAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// CancelDeactivation 撤销注销，用户恢复正常状态
+// 更新时校验仍处于冷静期，避免与完成注销的定时任务相互覆盖
+func (r *userRepository) CancelDeactivation(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND status = ?", id, constant.UserStatusDeactivating).
+		Updates(map[string]interface{}{"status": constant.UserStatusNormal, "deactivate_at": nil})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindDueDeactivations 查找注销冷静期在指定时间之前结束的用户
+func (r *userRepository) FindDueDeactivations(ctx context.Context, before time.Time, limit int) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND deactivate_at <= ?", constant.UserStatusDeactivating, before).
+		Order("id ASC").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// FinalizeDeactivation 软删除冷静期已结束的用户
+// 删除时再次校验状态和注销生效时间，冷静期内已撤销注销的用户不会被删除
+func (r *userRepository) FinalizeDeactivation(ctx context.Context, id uint, before time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND deactivate_at <= ?", constant.UserStatusDeactivating, before).
+		Delete(&model.User{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ChangeUsername 修改用户名并记录修改前的用户名
+// 更新时校验最近修改时间，并发修改时只有一次成功
+func (r *userRepository) ChangeUsername(ctx context.Context, id uint, username string, changedBefore time.Time, history *model.UsernameHistory) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND (username_changed_at IS NULL OR username_changed_at <= ?)", id, changedBefore).
+			Updates(map[string]interface{}{"username": username, "username_changed_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		changed = true
+
+		if history == nil {
+			return nil
+		}
+		return tx.Create(history).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// GetTokenVersion 获取用户当前的令牌版本号，包括已注销的用户
+func (r *userRepository) GetTokenVersion(ctx context.Context, id uint) (uint, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Unscoped().Select("token_version").Where("id = ?", id).Take(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrRecordNotFound
+		}
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
+
+// IncrementTokenVersion 在数据库中原子递增用户的令牌版本号并读取递增后的值
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, id uint) (uint, error) {
+	var version uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ?", id).
+			UpdateColumn("token_version", gorm.Expr("token_version + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		var user model.User
+		if err := tx.Select("token_version").Where("id = ?", id).Take(&user).Error; err != nil {
+			return err
+		}
+		version = user.TokenVersion
+		return nil
+	})
+	return version, err
+}
+
+// activeUserIDs 返回未注销用户ID的子查询，用于在关系列表和动态查询中排除已注销的用户
+// 账号注销后关系和动态由后台清理任务分批删除，在此之前通过该子查询立即隐藏
+func activeUserIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.User{}).Select("id")
+}
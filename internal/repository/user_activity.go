@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// UserActivityRepository 用户活动记录仓库接口
+type UserActivityRepository interface {
+	// Create 创建活动记录
+	Create(ctx context.Context, activity *model.UserActivity) error
+	// GetUserActivities 分页获取用户的活动记录，最近的在前
+	GetUserActivities(ctx context.Context, userID uint, page, size int) ([]model.UserActivity, int64, error)
+	// DeleteAllByUser 删除用户的所有活动记录
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// userActivityRepository 用户活动记录仓库实现
+type userActivityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserActivityRepository 创建用户活动记录仓库实例
+func NewUserActivityRepository(db *gorm.DB) UserActivityRepository {
+	return &userActivityRepository{db: db}
+}
+
+// Create 创建活动记录
+func (r *userActivityRepository) Create(ctx context.Context, activity *model.UserActivity) error {
+	return r.db.WithContext(ctx).Create(activity).Error
+}
+
+// GetUserActivities 分页获取用户的活动记录
+// 按活动时间倒序，时间相同时按ID倒序，保证分页稳定
+func (r *userActivityRepository) GetUserActivities(ctx context.Context, userID uint, page, size int) ([]model.UserActivity, int64, error) {
+	var activities []model.UserActivity
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&model.UserActivity{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").Offset((page - 1) * size).Limit(size).Find(&activities).Error
+	return activities, count, err
+}
+
+// DeleteAllByUser 删除用户的所有活动记录
+func (r *userActivityRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UserActivity{})
+	return result.RowsAffected, result.Error
+}
@@ -1,18 +1,56 @@
 package repository
 
 import (
+	"app/internal/constant"
 	"app/internal/model"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// RelationExportFilter 关系导出查询条件，零值字段不参与过滤
+type RelationExportFilter struct {
+	AfterID uint      // 只返回ID大于该值的关系
+	Start   time.Time // 创建时间起点（含）
+	End     time.Time // 创建时间终点（不含）
+}
+
+// apply 将查询条件添加到关系表查询
+func (f RelationExportFilter) apply(query *gorm.DB) *gorm.DB {
+	query = query.Where("id > ?", f.AfterID)
+	if !f.Start.IsZero() {
+		query = query.Where("created_at >= ?", f.Start)
+	}
+	if !f.End.IsZero() {
+		query = query.Where("created_at < ?", f.End)
+	}
+	return query
+}
+
 // UserFollowerRepository 粉丝关注仓库接口
 type UserFollowerRepository interface {
-	GetFollower(userID, targetID uint) (*model.UserFollower, error)
-	GetFollowers(userID uint, page, size int) ([]model.UserFollower, int64, error)
-	GetFollowing(userID uint, page, size int) ([]model.UserFollower, int64, error)
-	CreateFollower(follower *model.UserFollower) error
-	DeleteFollower(userID, targetID uint) error
+	GetFollower(ctx context.Context, userID, targetID uint) (*model.UserFollower, error)
+	GetFollowerByID(ctx context.Context, id uint) (*model.UserFollower, error)
+	GetFollowers(ctx context.Context, userID uint, page, size int) ([]model.UserFollower, int64, error)
+	GetFollowing(ctx context.Context, userID uint, page, size int) ([]model.UserFollower, int64, error)
+	GetFollowRequests(ctx context.Context, userID uint, page, size int) ([]model.UserFollower, int64, error)
+	// GetFollowerIDs 按用户ID顺序分批获取已通过关注的粉丝ID，包含已注销的粉丝
+	GetFollowerIDs(ctx context.Context, targetID, afterID uint, limit int) ([]uint, error)
+	// ExportFollows 按ID顺序分批获取已通过的关注关系，不包含已注销用户的关系
+	ExportFollows(ctx context.Context, filter RelationExportFilter, limit int) ([]model.UserFollower, error)
+	CreateFollower(ctx context.Context, follower *model.UserFollower) error
+	UpdateFollowerStatus(ctx context.Context, id uint, status int) error
+	ApproveAllPending(ctx context.Context, targetID uint) (int64, error)
+	DeleteFollower(ctx context.Context, userID, targetID uint) error
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+	BatchCreateFollowers(ctx context.Context, followers []model.UserFollower, chunkSize int) map[uint]error
+	BatchDeleteFollowing(ctx context.Context, userID uint, targetIDs []uint, chunkSize int) map[uint]error
+	BatchDeleteFollowers(ctx context.Context, targetID uint, userIDs []uint, chunkSize int) map[uint]error
+	GetFollowStatuses(ctx context.Context, userID uint, targetIDs []uint) (map[uint]int, error)
+	GetFollowerStatuses(ctx context.Context, targetID uint, userIDs []uint) (map[uint]int, error)
+	CountFollows(ctx context.Context, userID uint) (followers, following int64, err error)
+	CountMutualFollows(ctx context.Context, userID uint, limit int) (map[uint]int64, error)
 }
 
 // userFollowerRepository 粉丝关注仓库实现
@@ -26,9 +64,19 @@ func NewUserFollowerRepository(db *gorm.DB) UserFollowerRepository {
 }
 
 // GetFollower 获取关注关系
-func (r *userFollowerRepository) GetFollower(userID, targetID uint) (*model.UserFollower, error) {
+func (r *userFollowerRepository) GetFollower(ctx context.Context, userID, targetID uint) (*model.UserFollower, error) {
 	var follower model.UserFollower
-	err := r.db.Where("user_id = ? AND target_id = ?", userID, targetID).First(&follower).Error
+	err := r.db.WithContext(ctx).Where("user_id = ? AND target_id = ?", userID, targetID).First(&follower).Error
+	if err != nil {
+		return nil, err
+	}
+	return &follower, nil
+}
+
+// GetFollowerByID 根据ID获取关注关系
+func (r *userFollowerRepository) GetFollowerByID(ctx context.Context, id uint) (*model.UserFollower, error) {
+	var follower model.UserFollower
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&follower).Error
 	if err != nil {
 		return nil, err
 	}
@@ -36,18 +84,22 @@ func (r *userFollowerRepository) GetFollower(userID, targetID uint) (*model.User
 }
 
 // GetFollowers 获取用户的粉丝列表
-func (r *userFollowerRepository) GetFollowers(userID uint, page, size int) ([]model.UserFollower, int64, error) {
+func (r *userFollowerRepository) GetFollowers(ctx context.Context, userID uint, page, size int) ([]model.UserFollower, int64, error) {
 	var followers []model.UserFollower
 	var count int64
 
 	offset := (page - 1) * size
 
-	err := r.db.Model(&model.UserFollower{}).Where("target_id = ?", userID).Count(&count).Error
+	// 只返回已通过的关注关系，不包含已注销的粉丝
+	query := r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("target_id = ? AND status = ? AND user_id IN (?)", userID, int(constant.FollowStatusApproved), activeUserIDs(r.db.WithContext(ctx)))
+
+	err := query.Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Where("target_id = ?", userID).Offset(offset).Limit(size).Find(&followers).Error
+	err = query.Offset(offset).Limit(size).Find(&followers).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -56,18 +108,44 @@ func (r *userFollowerRepository) GetFollowers(userID uint, page, size int) ([]mo
 }
 
 // GetFollowing 获取用户关注的人列表
-func (r *userFollowerRepository) GetFollowing(userID uint, page, size int) ([]model.UserFollower, int64, error) {
+func (r *userFollowerRepository) GetFollowing(ctx context.Context, userID uint, page, size int) ([]model.UserFollower, int64, error) {
+	var followers []model.UserFollower
+	var count int64
+
+	offset := (page - 1) * size
+
+	// 只返回已通过的关注关系，不包含已注销的用户
+	query := r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("user_id = ? AND status = ? AND target_id IN (?)", userID, int(constant.FollowStatusApproved), activeUserIDs(r.db.WithContext(ctx)))
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Offset(offset).Limit(size).Find(&followers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return followers, count, nil
+}
+
+// GetFollowRequests 获取发给用户的待审核关注请求
+func (r *userFollowerRepository) GetFollowRequests(ctx context.Context, userID uint, page, size int) ([]model.UserFollower, int64, error) {
 	var followers []model.UserFollower
 	var count int64
 
 	offset := (page - 1) * size
 
-	err := r.db.Model(&model.UserFollower{}).Where("user_id = ?", userID).Count(&count).Error
+	query := r.db.WithContext(ctx).Model(&model.UserFollower{}).Where("target_id = ? AND status = ?", userID, int(constant.FollowStatusPending))
+
+	err := query.Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Where("user_id = ?", userID).Offset(offset).Limit(size).Find(&followers).Error
+	err = query.Order("created_at DESC").Offset(offset).Limit(size).Find(&followers).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -75,12 +153,262 @@ func (r *userFollowerRepository) GetFollowing(userID uint, page, size int) ([]mo
 	return followers, count, nil
 }
 
+// GetFollowerIDs 按用户ID顺序分批获取已通过关注的粉丝ID
+func (r *userFollowerRepository) GetFollowerIDs(ctx context.Context, targetID, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("target_id = ? AND status = ? AND user_id > ?", targetID, int(constant.FollowStatusApproved), afterID).
+		Order("user_id ASC").Limit(limit).Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// ExportFollows 按ID顺序分批获取已通过的关注关系，不包含已注销用户的关系
+func (r *userFollowerRepository) ExportFollows(ctx context.Context, filter RelationExportFilter, limit int) ([]model.UserFollower, error) {
+	var follows []model.UserFollower
+	query := r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("status = ? AND user_id IN (?) AND target_id IN (?)", int(constant.FollowStatusApproved),
+			activeUserIDs(r.db.WithContext(ctx)), activeUserIDs(r.db.WithContext(ctx)))
+	err := filter.apply(query).Order("id ASC").Limit(limit).Find(&follows).Error
+	return follows, err
+}
+
 // CreateFollower 创建关注关系
-func (r *userFollowerRepository) CreateFollower(follower *model.UserFollower) error {
-	return r.db.Create(follower).Error
+func (r *userFollowerRepository) CreateFollower(ctx context.Context, follower *model.UserFollower) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(follower).Error; err != nil {
+			return err
+		}
+		return bumpRelationVersion(tx, follower.UserID, follower.TargetID)
+	})
+}
+
+// UpdateFollowerStatus 更新关注关系状态
+func (r *userFollowerRepository) UpdateFollowerStatus(ctx context.Context, id uint, status int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var follower model.UserFollower
+		if err := tx.Where("id = ?", id).First(&follower).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.UserFollower{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+			return err
+		}
+		return bumpRelationVersion(tx, follower.UserID, follower.TargetID)
+	})
+}
+
+// ApproveAllPending 通过发给用户的所有待审核关注请求
+func (r *userFollowerRepository) ApproveAllPending(ctx context.Context, targetID uint) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var userIDs []uint
+		if err := tx.Model(&model.UserFollower{}).
+			Where("target_id = ? AND status = ?", targetID, int(constant.FollowStatusPending)).
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&model.UserFollower{}).
+			Where("target_id = ? AND status = ?", targetID, int(constant.FollowStatusPending)).
+			Update("status", int(constant.FollowStatusApproved))
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+		return bumpRelationVersion(tx, append(userIDs, targetID)...)
+	})
+	return affected, err
 }
 
 // DeleteFollower 删除关注关系
-func (r *userFollowerRepository) DeleteFollower(userID, targetID uint) error {
-	return r.db.Where("user_id = ? AND target_id = ?", userID, targetID).Delete(&model.UserFollower{}).Error
+func (r *userFollowerRepository) DeleteFollower(ctx context.Context, userID, targetID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND target_id = ?", userID, targetID).Delete(&model.UserFollower{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return bumpRelationVersion(tx, userID, targetID)
+	})
+}
+
+// DeleteAllByUser 删除用户的所有关注关系，包括其关注他人和被他人关注
+func (r *userFollowerRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 关系另一方的关注数或粉丝数同样会变化
+		var followerIDs, followingIDs []uint
+		if err := tx.Model(&model.UserFollower{}).Where("target_id = ?", userID).Pluck("user_id", &followerIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.UserFollower{}).Where("user_id = ?", userID).Pluck("target_id", &followingIDs).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ? OR target_id = ?", userID, userID).Delete(&model.UserFollower{})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+		if affected == 0 {
+			return nil
+		}
+
+		ids := append(append([]uint{userID}, followerIDs...), followingIDs...)
+		return bumpRelationVersion(tx, ids...)
+	})
+	return affected, err
+}
+
+// BatchCreateFollowers 分批创建关注关系，每批在一个事务中写入并递增相关用户的关注关系版本号
+// 返回写入失败的目标用户ID及原因，某一批失败时只回滚该批，其余批次不受影响
+func (r *userFollowerRepository) BatchCreateFollowers(ctx context.Context, followers []model.UserFollower, chunkSize int) map[uint]error {
+	failed := make(map[uint]error)
+	for start := 0; start < len(followers); start += chunkSize {
+		chunk := followers[start:min(start+chunkSize, len(followers))]
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&chunk).Error; err != nil {
+				return err
+			}
+			ids := []uint{chunk[0].UserID}
+			for _, f := range chunk {
+				ids = append(ids, f.TargetID)
+			}
+			return bumpRelationVersion(tx, ids...)
+		})
+		if err != nil {
+			for _, f := range chunk {
+				failed[f.TargetID] = err
+			}
+		}
+	}
+	return failed
+}
+
+// BatchDeleteFollowing 分批删除用户对目标用户的关注关系，待审核的关注请求同样删除
+// 返回删除失败的目标用户ID及原因，某一批失败时只回滚该批
+func (r *userFollowerRepository) BatchDeleteFollowing(ctx context.Context, userID uint, targetIDs []uint, chunkSize int) map[uint]error {
+	return r.batchDelete(ctx, "user_id = ? AND target_id IN ?", userID, targetIDs, chunkSize)
+}
+
+// BatchDeleteFollowers 分批删除关注用户的粉丝关系
+// 返回删除失败的粉丝用户ID及原因，某一批失败时只回滚该批
+func (r *userFollowerRepository) BatchDeleteFollowers(ctx context.Context, targetID uint, userIDs []uint, chunkSize int) map[uint]error {
+	return r.batchDelete(ctx, "target_id = ? AND user_id IN ?", targetID, userIDs, chunkSize)
+}
+
+// batchDelete 按条件分批删除关注关系，条件的第一个参数为操作用户ID，第二个参数为一批对方用户ID
+func (r *userFollowerRepository) batchDelete(ctx context.Context, query string, ownerID uint, otherIDs []uint, chunkSize int) map[uint]error {
+	failed := make(map[uint]error)
+	for start := 0; start < len(otherIDs); start += chunkSize {
+		chunk := otherIDs[start:min(start+chunkSize, len(otherIDs))]
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result := tx.Where(query, ownerID, chunk).Delete(&model.UserFollower{})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return bumpRelationVersion(tx, append([]uint{ownerID}, chunk...)...)
+		})
+		if err != nil {
+			for _, id := range chunk {
+				failed[id] = err
+			}
+		}
+	}
+	return failed
+}
+
+// CountFollows 统计用户已通过的粉丝数和关注数
+func (r *userFollowerRepository) CountFollows(ctx context.Context, userID uint) (followers, following int64, err error) {
+	err = r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("target_id = ? AND status = ?", userID, int(constant.FollowStatusApproved)).
+		Count(&followers).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = r.db.WithContext(ctx).Model(&model.UserFollower{}).
+		Where("user_id = ? AND status = ?", userID, int(constant.FollowStatusApproved)).
+		Count(&following).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
+
+// CountMutualFollows 统计用户关注的人还关注了谁，返回每个目标被用户关注的人关注的次数
+// 按次数从多到少最多返回limit个，不含用户本人，只统计已通过的关注
+func (r *userFollowerRepository) CountMutualFollows(ctx context.Context, userID uint, limit int) (map[uint]int64, error) {
+	var rows []struct {
+		TargetID uint
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Table("user_follower AS a").
+		Select("b.target_id AS target_id, COUNT(*) AS count").
+		Joins("JOIN user_follower AS b ON b.user_id = a.target_id AND b.status = ? AND b.deleted_at IS NULL", int(constant.FollowStatusApproved)).
+		Where("a.user_id = ? AND a.status = ? AND a.deleted_at IS NULL", userID, int(constant.FollowStatusApproved)).
+		Where("b.target_id <> ?", userID).
+		Group("b.target_id").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TargetID] = row.Count
+	}
+	return counts, nil
+}
+
+// bumpRelationVersion 递增用户的关注关系版本号
+// 使用UpdateColumn避免修改用户的updated_at，资料缓存不受关注关系变化影响
+func bumpRelationVersion(tx *gorm.DB, userIDs ...uint) error {
+	return tx.Model(&model.User{}).Where("id IN ?", userIDs).
+		UpdateColumn("relation_version", gorm.Expr("relation_version + 1")).Error
+}
+
+// GetFollowStatuses 批量获取用户对目标用户的关注状态，未关注的目标不在结果中
+func (r *userFollowerRepository) GetFollowStatuses(ctx context.Context, userID uint, targetIDs []uint) (map[uint]int, error) {
+	statuses := make(map[uint]int, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return statuses, nil
+	}
+
+	var followers []model.UserFollower
+	err := r.db.WithContext(ctx).Select("target_id", "status").
+		Where("user_id = ? AND target_id IN ?", userID, targetIDs).
+		Find(&followers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range followers {
+		statuses[f.TargetID] = f.Status
+	}
+	return statuses, nil
+}
+
+// GetFollowerStatuses 批量获取用户对目标用户的关注状态，用于判断是否为目标用户的粉丝，未关注的用户不在结果中
+func (r *userFollowerRepository) GetFollowerStatuses(ctx context.Context, targetID uint, userIDs []uint) (map[uint]int, error) {
+	statuses := make(map[uint]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return statuses, nil
+	}
+
+	var followers []model.UserFollower
+	err := r.db.WithContext(ctx).Select("user_id", "status").
+		Where("target_id = ? AND user_id IN ?", targetID, userIDs).
+		Find(&followers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range followers {
+		statuses[f.UserID] = f.Status
+	}
+	return statuses, nil
 }
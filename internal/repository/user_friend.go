@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"app/internal/constant"
 	"app/internal/model"
+	"context"
 
 	"gorm.io/gorm"
 )
@@ -9,13 +11,22 @@ import (
 // UserFriendRepository 好友关系仓库接口
 type UserFriendRepository interface {
 	// 好友相关
-	CreateFriend(friend *model.UserFriend) error
-	UpdateFriendStatus(id uint, status int) error
-	DeleteFriend(userID, targetID uint) error
-	GetFriend(userID, targetID uint) (*model.UserFriend, error)
-	GetFriendByID(id uint) (*model.UserFriend, error)
-	GetFriendRequests(userID uint, page, size int) ([]model.UserFriend, int64, error)
-	GetFriends(userID uint, page, size int) ([]model.UserFriend, int64, error)
+	CreateFriend(ctx context.Context, friend *model.UserFriend) error
+	UpdateFriendStatus(ctx context.Context, id uint, status int) error
+	DeleteFriend(ctx context.Context, userID, targetID uint) error
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+	GetFriend(ctx context.Context, userID, targetID uint) (*model.UserFriend, error)
+	GetFriendByID(ctx context.Context, id uint) (*model.UserFriend, error)
+	GetFriendRequests(ctx context.Context, userID uint, page, size int) ([]model.UserFriend, int64, error)
+	GetFriends(ctx context.Context, userID uint, group string, page, size int) ([]model.UserFriend, int64, error)
+	// GetFriendIDs 按用户ID顺序分批获取已确认的好友ID，包含已注销的好友
+	GetFriendIDs(ctx context.Context, userID, afterID uint, limit int) ([]uint, error)
+	// ExportFriends 按ID顺序分批获取已确认的好友关系，每对好友只返回用户ID较小一方的记录，不包含已注销用户的关系
+	ExportFriends(ctx context.Context, filter RelationExportFilter, limit int) ([]model.UserFriend, error)
+	UpdateFriendRemark(ctx context.Context, userID, targetID uint, remark string) error
+	UpdateFriendGroup(ctx context.Context, userID, targetID uint, group string) error
+	GetFriendStatuses(ctx context.Context, userID uint, targetIDs []uint) (map[uint]int, error)
+	CountMutualFriends(ctx context.Context, userID uint, limit int) (map[uint]int64, error)
 }
 
 // userFriendRepository 好友关系仓库实现
@@ -29,90 +40,66 @@ func NewUserFriendRepository(db *gorm.DB) UserFriendRepository {
 }
 
 // CreateFriend 创建好友关系（双记录模式）
-func (r *userFriendRepository) CreateFriend(friend *model.UserFriend) error {
-	// 开启事务
-	tx := r.db.Begin()
-
-	// 创建发起方记录
-	friend.Direction = 0 // 发起方
-	if err := tx.Create(friend).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// 创建接收方视角的记录
-	receiverFriend := &model.UserFriend{
-		UserID:    friend.TargetID, // 接收方视角：自己是UserID
-		TargetID:  friend.UserID,   // 接收方视角：对方是TargetID
-		Status:    friend.Status,
-		Direction: 1, // 接收方
-	}
-
-	if err := tx.Create(receiverFriend).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+func (r *userFriendRepository) CreateFriend(ctx context.Context, friend *model.UserFriend) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 创建发起方记录
+		friend.Direction = constant.FriendDirectionSender
+		if err := tx.Create(friend).Error; err != nil {
+			return err
+		}
 
-	// 提交事务
-	return tx.Commit().Error
+		// 创建接收方视角的记录
+		receiverFriend := &model.UserFriend{
+			UserID:    friend.TargetID, // 接收方视角：自己是UserID
+			TargetID:  friend.UserID,   // 接收方视角：对方是TargetID
+			Status:    friend.Status,
+			Direction: constant.FriendDirectionReceiver,
+			Message:   friend.Message,
+		}
+		return tx.Create(receiverFriend).Error
+	})
 }
 
 // UpdateFriendStatus 更新好友关系状态（双记录模式）
-func (r *userFriendRepository) UpdateFriendStatus(id uint, status int) error {
+func (r *userFriendRepository) UpdateFriendStatus(ctx context.Context, id uint, status int) error {
 	// 先查询要更新的记录，获取UserID和TargetID
 	var friend model.UserFriend
-	if err := r.db.Where("id = ?", id).First(&friend).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&friend).Error; err != nil {
 		return err
 	}
 
-	// 开启事务
-	tx := r.db.Begin()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 更新当前记录状态
+		if err := tx.Model(&model.UserFriend{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+			return err
+		}
 
-	// 更新当前记录状态
-	if err := tx.Model(&model.UserFriend{}).Where("id = ?", id).Update("status", status).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// 更新对应的另一条记录状态
-	if err := tx.Model(&model.UserFriend{}).Where(
-		"user_id = ? AND target_id = ?",
-		friend.TargetID, friend.UserID,
-	).Update("status", status).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// 提交事务
-	return tx.Commit().Error
+		// 更新对应的另一条记录状态
+		return tx.Model(&model.UserFriend{}).Where(
+			"user_id = ? AND target_id = ?",
+			friend.TargetID, friend.UserID,
+		).Update("status", status).Error
+	})
 }
 
 // DeleteFriend 删除好友关系（双记录模式）
-func (r *userFriendRepository) DeleteFriend(userID, targetID uint) error {
-	// 开启事务
-	tx := r.db.Begin()
+func (r *userFriendRepository) DeleteFriend(ctx context.Context, userID, targetID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 删除第一条记录（用户视角）
+		if err := tx.Where("user_id = ? AND target_id = ?", userID, targetID).Delete(&model.UserFriend{}).Error; err != nil {
+			return err
+		}
 
-	// 删除第一条记录（用户视角）
-	if err := tx.Where("user_id = ? AND target_id = ?", userID, targetID).Delete(&model.UserFriend{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// 删除第二条记录（好友视角）
-	if err := tx.Where("user_id = ? AND target_id = ?", targetID, userID).Delete(&model.UserFriend{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// 提交事务
-	return tx.Commit().Error
+		// 删除第二条记录（好友视角）
+		return tx.Where("user_id = ? AND target_id = ?", targetID, userID).Delete(&model.UserFriend{}).Error
+	})
 }
 
 // GetFriend 获取好友关系（双记录模式）
-func (r *userFriendRepository) GetFriend(userID, targetID uint) (*model.UserFriend, error) {
+func (r *userFriendRepository) GetFriend(ctx context.Context, userID, targetID uint) (*model.UserFriend, error) {
 	// 在双记录模式下，只需要查询用户视角的记录
 	var friend model.UserFriend
-	err := r.db.Where("user_id = ? AND target_id = ?", userID, targetID).First(&friend).Error
+	err := r.db.WithContext(ctx).Where("user_id = ? AND target_id = ?", userID, targetID).First(&friend).Error
 	if err != nil {
 		return nil, err
 	}
@@ -120,9 +107,9 @@ func (r *userFriendRepository) GetFriend(userID, targetID uint) (*model.UserFrie
 }
 
 // GetFriendByID 根据ID获取好友关系
-func (r *userFriendRepository) GetFriendByID(id uint) (*model.UserFriend, error) {
+func (r *userFriendRepository) GetFriendByID(ctx context.Context, id uint) (*model.UserFriend, error) {
 	var friend model.UserFriend
-	err := r.db.Where("id = ?", id).First(&friend).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&friend).Error
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +117,7 @@ func (r *userFriendRepository) GetFriendByID(id uint) (*model.UserFriend, error)
 }
 
 // GetFriendRequests 获取好友请求列表（双记录模式）
-func (r *userFriendRepository) GetFriendRequests(userID uint, page, size int) ([]model.UserFriend, int64, error) {
+func (r *userFriendRepository) GetFriendRequests(ctx context.Context, userID uint, page, size int) ([]model.UserFriend, int64, error) {
 	var friends []model.UserFriend
 	var count int64
 
@@ -138,17 +125,17 @@ func (r *userFriendRepository) GetFriendRequests(userID uint, page, size int) ([
 
 	// 在双记录模式下，查询用户视角下的待确认请求
 	// 用户是接收方(Direction=1)且状态为待确认(Status=0)
-	err := r.db.Model(&model.UserFriend{}).Where(
-		"user_id = ? AND status = 0 AND direction = 1",
-		userID,
+	err := r.db.WithContext(ctx).Model(&model.UserFriend{}).Where(
+		"user_id = ? AND status = ? AND direction = ?",
+		userID, int(constant.FriendStatusPending), constant.FriendDirectionReceiver,
 	).Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Where(
-		"user_id = ? AND status = 0 AND direction = 1",
-		userID,
+	err = r.db.WithContext(ctx).Where(
+		"user_id = ? AND status = ? AND direction = ?",
+		userID, int(constant.FriendStatusPending), constant.FriendDirectionReceiver,
 	).Offset(offset).Limit(size).Find(&friends).Error
 	if err != nil {
 		return nil, 0, err
@@ -157,30 +144,119 @@ func (r *userFriendRepository) GetFriendRequests(userID uint, page, size int) ([
 	return friends, count, nil
 }
 
-// GetFriends 获取好友列表（双记录模式）
-func (r *userFriendRepository) GetFriends(userID uint, page, size int) ([]model.UserFriend, int64, error) {
+// GetFriends 获取好友列表（双记录模式），group不为空时只返回该分组的好友
+func (r *userFriendRepository) GetFriends(ctx context.Context, userID uint, group string, page, size int) ([]model.UserFriend, int64, error) {
 	var friends []model.UserFriend
 	var count int64
 
 	offset := (page - 1) * size
 
 	// 在双记录模式下，只需要查询用户视角下的已确认好友
-	// 用户是记录所有者(UserID=userID)且状态为已确认(Status=1)
-	err := r.db.Model(&model.UserFriend{}).Where(
-		"user_id = ? AND status = 1",
-		userID,
-	).Count(&count).Error
+	// 用户是记录所有者(UserID=userID)且状态为已确认(Status=1)，不包含已注销的好友
+	query := r.db.WithContext(ctx).Model(&model.UserFriend{}).Where(
+		"user_id = ? AND status = ? AND target_id IN (?)",
+		userID, int(constant.FriendStatusConfirmed), activeUserIDs(r.db.WithContext(ctx)),
+	)
+	if group != "" {
+		query = query.Where("group_name = ?", group)
+	}
+
+	err := query.Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.Where(
-		"user_id = ? AND status = 1",
-		userID,
-	).Offset(offset).Limit(size).Find(&friends).Error
+	err = query.Offset(offset).Limit(size).Find(&friends).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
 	return friends, count, nil
-}
\ No newline at end of file
+}
+
+// GetFriendIDs 按用户ID顺序分批获取已确认的好友ID（双记录模式下只查询用户视角的记录）
+func (r *userFriendRepository) GetFriendIDs(ctx context.Context, userID, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.UserFriend{}).
+		Where("user_id = ? AND status = ? AND target_id > ?", userID, int(constant.FriendStatusConfirmed), afterID).
+		Order("target_id ASC").Limit(limit).Pluck("target_id", &ids).Error
+	return ids, err
+}
+
+// ExportFriends 按ID顺序分批获取已确认的好友关系
+// 双记录模式下每对好友有两条记录，只返回用户ID较小一方的记录
+func (r *userFriendRepository) ExportFriends(ctx context.Context, filter RelationExportFilter, limit int) ([]model.UserFriend, error) {
+	var friends []model.UserFriend
+	query := r.db.WithContext(ctx).Model(&model.UserFriend{}).
+		Where("status = ? AND user_id < target_id AND user_id IN (?) AND target_id IN (?)", int(constant.FriendStatusConfirmed),
+			activeUserIDs(r.db.WithContext(ctx)), activeUserIDs(r.db.WithContext(ctx)))
+	err := filter.apply(query).Order("id ASC").Limit(limit).Find(&friends).Error
+	return friends, err
+}
+
+// DeleteAllByUser 删除用户的所有好友关系及好友请求（双记录模式下两侧记录均删除）
+func (r *userFriendRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? OR target_id = ?", userID, userID).Delete(&model.UserFriend{})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateFriendRemark 更新好友备注名，只修改用户视角的记录
+func (r *userFriendRepository) UpdateFriendRemark(ctx context.Context, userID, targetID uint, remark string) error {
+	return r.db.WithContext(ctx).Model(&model.UserFriend{}).
+		Where("user_id = ? AND target_id = ?", userID, targetID).
+		Update("remark", remark).Error
+}
+
+// UpdateFriendGroup 更新好友分组，只修改用户视角的记录
+func (r *userFriendRepository) UpdateFriendGroup(ctx context.Context, userID, targetID uint, group string) error {
+	return r.db.WithContext(ctx).Model(&model.UserFriend{}).
+		Where("user_id = ? AND target_id = ?", userID, targetID).
+		Update("group_name", group).Error
+}
+
+// GetFriendStatuses 批量获取用户与目标用户的好友关系状态（双记录模式下只查询用户视角的记录），无关系的目标不在结果中
+func (r *userFriendRepository) GetFriendStatuses(ctx context.Context, userID uint, targetIDs []uint) (map[uint]int, error) {
+	statuses := make(map[uint]int, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return statuses, nil
+	}
+
+	var friends []model.UserFriend
+	err := r.db.WithContext(ctx).Select("target_id", "status").
+		Where("user_id = ? AND target_id IN ?", userID, targetIDs).
+		Find(&friends).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range friends {
+		statuses[f.TargetID] = f.Status
+	}
+	return statuses, nil
+}
+
+// CountMutualFriends 统计好友的好友中与用户的共同好友数，按共同好友数从多到少最多返回limit个，不含用户本人
+func (r *userFriendRepository) CountMutualFriends(ctx context.Context, userID uint, limit int) (map[uint]int64, error) {
+	var rows []struct {
+		TargetID uint
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Table("user_friend AS f1").
+		Select("f2.target_id AS target_id, COUNT(*) AS count").
+		Joins("JOIN user_friend AS f2 ON f2.user_id = f1.target_id AND f2.status = ? AND f2.deleted_at IS NULL", int(constant.FriendStatusConfirmed)).
+		Where("f1.user_id = ? AND f1.status = ? AND f1.deleted_at IS NULL", userID, int(constant.FriendStatusConfirmed)).
+		Where("f2.target_id <> ?", userID).
+		Group("f2.target_id").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TargetID] = row.Count
+	}
+	return counts, nil
+}
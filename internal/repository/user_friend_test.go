@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"app/internal/constant"
+	"app/internal/model"
+	"app/pkg/database/dbtest"
+)
+
+func TestCreateFriend(t *testing.T) {
+	tx := dbtest.Begin(t, testDB)
+	ctx := context.Background()
+	repo := NewUserFriendRepository(tx)
+
+	sender := createTestUser(t, tx, "sender")
+	receiver := createTestUser(t, tx, "receiver")
+	request := &model.UserFriend{UserID: sender.ID, TargetID: receiver.ID, Status: int(constant.FriendStatusPending), Message: "hi"}
+	if err := repo.CreateFriend(ctx, request); err != nil {
+		t.Fatalf("CreateFriend() error = %v", err)
+	}
+
+	// 双记录模式：双方视角各有一条记录，方向相反，状态和附言一致
+	tests := []struct {
+		name      string
+		userID    uint
+		targetID  uint
+		direction int
+	}{
+		{"发起方", sender.ID, receiver.ID, constant.FriendDirectionSender},
+		{"接收方", receiver.ID, sender.ID, constant.FriendDirectionReceiver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			friend, err := repo.GetFriend(ctx, tt.userID, tt.targetID)
+			if err != nil {
+				t.Fatalf("GetFriend() error = %v", err)
+			}
+			if friend.Direction != tt.direction || friend.Status != int(constant.FriendStatusPending) || friend.Message != "hi" {
+				t.Errorf("GetFriend() = direction %d, status %d, message %q", friend.Direction, friend.Status, friend.Message)
+			}
+		})
+	}
+
+	// 请求出现在接收方的好友请求列表中
+	requests, count, err := repo.GetFriendRequests(ctx, receiver.ID, 1, 10)
+	if err != nil {
+		t.Fatalf("GetFriendRequests() error = %v", err)
+	}
+	if count != 1 || len(requests) != 1 || requests[0].TargetID != sender.ID {
+		t.Errorf("GetFriendRequests() = %+v (count %d), want request from %d", requests, count, sender.ID)
+	}
+}
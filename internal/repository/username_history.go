@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// UsernameHistoryRepository 用户名变更记录仓库接口
+type UsernameHistoryRepository interface {
+	// FindProtected 查找指定时间仍在保护期内的旧用户名记录，存在多条时返回最近修改的一条
+	FindProtected(ctx context.Context, username string, now time.Time) (*model.UsernameHistory, error)
+	// DeleteAllByUser 删除用户的所有用户名变更记录
+	DeleteAllByUser(ctx context.Context, userID uint) (int64, error)
+}
+
+// usernameHistoryRepository 用户名变更记录仓库实现
+type usernameHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewUsernameHistoryRepository 创建用户名变更记录仓库实例
+func NewUsernameHistoryRepository(db *gorm.DB) UsernameHistoryRepository {
+	return &usernameHistoryRepository{db: db}
+}
+
+// FindProtected 查找仍在保护期内的旧用户名记录
+func (r *usernameHistoryRepository) FindProtected(ctx context.Context, username string, now time.Time) (*model.UsernameHistory, error) {
+	var history model.UsernameHistory
+	err := r.db.WithContext(ctx).
+		Where("username = ? AND protected_until > ?", username, now).
+		Order("id DESC").First(&history).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &history, nil
+}
+
+// DeleteAllByUser 删除用户的所有用户名变更记录
+func (r *usernameHistoryRepository) DeleteAllByUser(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UsernameHistory{})
+	return result.RowsAffected, result.Error
+}
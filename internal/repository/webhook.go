@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// WebhookDeliveryFilter 投递记录查询条件，零值字段不参与过滤
+type WebhookDeliveryFilter struct {
+	SubscriptionID uint
+	ClientID       uint
+	Status         string
+}
+
+// WebhookRepository Webhook仓库接口
+type WebhookRepository interface {
+	// 订阅相关
+	CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id uint) (*model.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	GetEnabledSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id uint) error
+	// 投递记录相关
+	CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, filter WebhookDeliveryFilter, page, size int) ([]model.WebhookDelivery, int64, error)
+	DeleteFinishedDeliveries(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// webhookRepository Webhook仓库实现
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository 创建Webhook仓库实例
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// CreateSubscription 创建订阅
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+// GetSubscription 获取订阅
+func (r *webhookRepository) GetSubscription(ctx context.Context, id uint) (*model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	result := r.db.WithContext(ctx).First(&sub, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return &sub, nil
+}
+
+// GetSubscriptions 获取所有订阅，按创建顺序排序
+func (r *webhookRepository) GetSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.db.WithContext(ctx).Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// GetEnabledSubscriptions 获取所有启用的订阅
+func (r *webhookRepository) GetEnabledSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("status = ?", constant.WebhookStatusEnabled).Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// UpdateSubscription 更新订阅
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Save(sub).Error
+}
+
+// DeleteSubscription 删除订阅，保留已有的投递记录
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateDeliveries 批量创建投递记录
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
+
+// GetDueDeliveries 获取已到投递时间的待投递记录，按下次投递时间排序
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", constant.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery 认领一条到期的待投递记录，将下次投递时间推迟到锁定截止时间
+// 多个实例同时处理时只有一个能认领成功，投递中断的记录在锁定到期后可被重新认领
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, id uint, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, constant.WebhookDeliveryPending, now).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateDelivery 更新投递结果
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(delivery).Select(
+		"status", "attempts", "next_attempt_at", "response_code", "error_message", "delivered_at",
+	).Updates(delivery).Error
+}
+
+// GetDeliveries 分页获取投递记录，按创建时间倒序排序
+func (r *webhookRepository) GetDeliveries(ctx context.Context, filter WebhookDeliveryFilter, page, size int) ([]model.WebhookDelivery, int64, error) {
+	var deliveries []model.WebhookDelivery
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.WebhookDelivery{})
+	if filter.SubscriptionID > 0 {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.ClientID > 0 {
+		query = query.Where("client_id = ?", filter.ClientID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * size
+	err := query.Order("id DESC").Offset(offset).Limit(size).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// DeleteFinishedDeliveries 删除指定时间之前结束的投递记录，每次最多删除limit条
+func (r *webhookRepository) DeleteFinishedDeliveries(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("status IN ? AND updated_at < ?", []string{constant.WebhookDeliverySuccess, constant.WebhookDeliveryFailed}, before).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,86 @@
+// 管理后台相关路由定义
+package routes
+
+import (
+	"app/internal/container"
+	"app/internal/handler"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminRoutes 注册管理后台相关路由
+func RegisterAdminRoutes(r *gin.RouterGroup) {
+	// 从容器获取管理后台各处理器
+	container := container.GetInstance()
+	statsHandler := container.GetStatsHandler()
+	graphHandler := container.GetGraphHandler()
+	featureHandler := container.GetFeatureHandler()
+	maintenanceHandler := container.GetMaintenanceHandler()
+	jwtKeyHandler := container.GetJWTKeyHandler()
+	webhookHandler := container.GetWebhookHandler()
+	metricsHandler := container.GetMetricsHandler()
+	announcementHandler := container.GetAnnouncementHandler()
+	tagHandler := container.GetTagHandler()
+	configHandler := container.GetConfigHandler()
+	muteHandler := container.GetMuteHandler()
+	funnelHandler := container.GetFunnelHandler()
+
+	// 管理后台相关路由组
+	adminGroup := r.Group("/admin", middleware.RequestLimit("admin"))
+
+	// 注册需要管理员权限的路由
+	registerAdminAuthRoutes(adminGroup, statsHandler, graphHandler, featureHandler, maintenanceHandler, jwtKeyHandler, webhookHandler, metricsHandler, announcementHandler, tagHandler, configHandler, muteHandler, funnelHandler)
+}
+
+// registerAdminAuthRoutes 注册需要管理员权限的路由
+func registerAdminAuthRoutes(group *gin.RouterGroup, statsHandler *handler.StatsHandler, graphHandler *handler.GraphHandler, featureHandler *handler.FeatureHandler, maintenanceHandler *handler.MaintenanceHandler, jwtKeyHandler *handler.JWTKeyHandler, webhookHandler *handler.WebhookHandler, metricsHandler *handler.MetricsHandler, announcementHandler *handler.AnnouncementHandler, tagHandler *handler.TagHandler, configHandler *handler.ConfigHandler, muteHandler *handler.MuteHandler, funnelHandler *handler.FunnelHandler) {
+	// 添加认证和管理员权限中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	authGroup.GET("/stats", statsHandler.GetStats)          // 获取统计数据
+	authGroup.GET("/stats/funnel", funnelHandler.GetFunnel) // 获取登录注册漏斗数据、转化率和每个新用户的短信费用
+
+	authGroup.GET("/graph/export", graphHandler.ExportGraph) // 流式导出关注或好友关系图，支持按创建时间筛选和按关系ID续传
+	middleware.SkipBodyLog(authGroup, "/graph/export")       // 导出内容可能很大，不记录响应体
+
+	authGroup.GET("/features", featureHandler.ListFlags)          // 获取所有功能开关
+	authGroup.PUT("/features/:key", featureHandler.SaveFlag)      // 创建或更新功能开关
+	authGroup.DELETE("/features/:key", featureHandler.DeleteFlag) // 删除功能开关
+
+	authGroup.GET("/config", configHandler.GetEffectiveConfig) // 获取本节点的有效配置，敏感配置项已隐藏
+
+	authGroup.GET("/maintenance", maintenanceHandler.GetMaintenance) // 获取维护模式状态
+	authGroup.PUT("/maintenance", maintenanceHandler.SetMaintenance) // 开启或关闭维护模式
+
+	authGroup.GET("/jwt/keys", jwtKeyHandler.ListKeys)     // 获取所有签名密钥
+	authGroup.POST("/jwt/rotate", jwtKeyHandler.RotateKey) // 轮换签发令牌使用的密钥
+
+	authGroup.GET("/webhooks", webhookHandler.ListWebhooks)                 // 获取所有Webhook订阅
+	authGroup.POST("/webhooks", webhookHandler.CreateWebhook)               // 创建Webhook订阅
+	authGroup.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)            // 更新Webhook订阅
+	authGroup.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)         // 删除Webhook订阅
+	authGroup.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries) // 获取订阅的投递记录
+
+	authGroup.GET("/metrics/repository", metricsHandler.GetRepositoryMetrics)           // 获取仓库方法调用指标
+	authGroup.GET("/metrics/repository/export", metricsHandler.ExportRepositoryMetrics) // 以Prometheus文本格式导出仓库方法调用指标
+	authGroup.GET("/metrics/resilience/export", metricsHandler.ExportResilienceMetrics) // 以Prometheus文本格式导出第三方服务熔断器指标
+	authGroup.GET("/metrics/funnel/export", funnelHandler.ExportFunnelMetrics)          // 以Prometheus文本格式导出登录注册漏斗事件次数
+
+	authGroup.GET("/announcements", announcementHandler.ListAnnouncements)         // 获取所有公告及已读人数
+	authGroup.POST("/announcements", announcementHandler.CreateAnnouncement)       // 发布公告
+	authGroup.DELETE("/announcements/:id", announcementHandler.DeleteAnnouncement) // 删除公告
+
+	authGroup.GET("/tags", tagHandler.ListTags)                   // 获取所有用户标签
+	authGroup.POST("/tags", tagHandler.CreateTag)                 // 创建用户标签
+	authGroup.PUT("/tags/:id", tagHandler.UpdateTag)              // 更新用户标签
+	authGroup.DELETE("/tags/:id", tagHandler.DeleteTag)           // 删除用户标签及其成员
+	authGroup.GET("/tags/:id/users", tagHandler.GetMembers)       // 获取标签成员
+	authGroup.POST("/tags/:id/users", tagHandler.AddMembers)      // 向手动标签添加用户
+	authGroup.DELETE("/tags/:id/users", tagHandler.RemoveMembers) // 从手动标签移除用户
+	authGroup.GET("/users/:id/tags", tagHandler.GetUserTags)      // 获取用户拥有的标签
+
+	authGroup.GET("/users/:id/mute", muteHandler.GetMute)       // 获取用户的禁言状态
+	authGroup.PUT("/users/:id/mute", muteHandler.MuteUser)      // 禁言用户，禁言期间无法发布、评论和点赞
+	authGroup.DELETE("/users/:id/mute", muteHandler.UnmuteUser) // 提前解除用户禁言
+}
@@ -0,0 +1,32 @@
+// 系统公告相关路由定义
+package routes
+
+import (
+	"app/internal/container"
+	"app/internal/handler"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAnnouncementRoutes 注册系统公告相关路由，公告的发布和管理在管理后台路由中注册
+func RegisterAnnouncementRoutes(r *gin.RouterGroup) {
+	// 从容器获取系统公告处理器
+	container := container.GetInstance()
+	announcementHandler := container.GetAnnouncementHandler()
+
+	// 系统公告相关路由组
+	announcementGroup := r.Group("/announcements", middleware.RequestLimit("announcement"))
+
+	// 注册需要认证的公告路由
+	registerAnnouncementAuthRoutes(announcementGroup, announcementHandler)
+}
+
+// registerAnnouncementAuthRoutes 注册需要认证的系统公告路由
+func registerAnnouncementAuthRoutes(group *gin.RouterGroup, handler *handler.AnnouncementHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("", middleware.AuthMiddleware())
+
+	authGroup.GET("", handler.GetUnread)      // 获取当前用户未读的公告
+	authGroup.POST("/read", handler.MarkRead) // 标记公告已读
+}
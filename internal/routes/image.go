@@ -10,13 +10,13 @@ import (
 )
 
 // RegisterImageRoutes 注册图片相关路由
-func RegisterImageRoutes(r *gin.Engine) {
+func RegisterImageRoutes(r *gin.RouterGroup) {
 	// 从容器获取图片处理器
 	container := container.GetInstance()
 	imageHandler := container.GetImageHandler()
 
 	// 图片相关路由组
-	imageGroup := r.Group("/api/images")
+	imageGroup := r.Group("/images", middleware.RequestLimit("images"))
 
 	// 注册需要认证的图片路由
 	registerImageAuthRoutes(imageGroup, imageHandler)
@@ -29,4 +29,10 @@ func registerImageAuthRoutes(group *gin.RouterGroup, handler *handler.ImageHandl
 
 	authGroup.POST("/temp", handler.UploadTempImage)                   // 上传临时图片
 	authGroup.POST("/temp/multiple", handler.UploadMultipleTempImages) // 批量上传临时图片
+	authGroup.POST("/temp/reuse", handler.ReuseTempImage)              // 按内容摘要复用已上传的图片
+	authGroup.POST("/temp/presign", handler.PresignTempImage)          // 获取直传COS的预签名上传地址
+	authGroup.POST("/temp/confirm", handler.ConfirmTempImage)          // 确认直传完成并创建临时图片
+
+	// 上传请求体为图片文件，不记录请求和响应体
+	middleware.SkipBodyLog(authGroup, "/temp", "/temp/multiple")
 }
@@ -0,0 +1,38 @@
+// 开放接口路由定义，供内部服务和合作方通过请求签名调用
+package routes
+
+import (
+	"app/internal/constant"
+	"app/internal/container"
+	"app/internal/handler"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOpenRoutes 注册开放接口路由
+func RegisterOpenRoutes(r *gin.RouterGroup) {
+	// 从容器获取依赖
+	container := container.GetInstance()
+	clientRepo := container.GetAPIClientRepository()
+	statsHandler := container.GetStatsHandler()
+	webhookHandler := container.GetWebhookHandler()
+
+	// 开放接口路由组，所有接口均需校验请求签名
+	openGroup := r.Group("/open", middleware.RequestLimit("open"), middleware.SignatureAuthMiddleware(clientRepo))
+
+	// 注册统计相关接口
+	registerOpenStatsRoutes(openGroup, statsHandler)
+	// 注册Webhook相关接口
+	registerOpenWebhookRoutes(openGroup, webhookHandler)
+}
+
+// registerOpenStatsRoutes 注册统计相关开放接口
+func registerOpenStatsRoutes(group *gin.RouterGroup, statsHandler *handler.StatsHandler) {
+	group.GET("/stats", middleware.APIScopeMiddleware(constant.APIScopeStatsRead), statsHandler.GetStats) // 获取统计数据
+}
+
+// registerOpenWebhookRoutes 注册Webhook相关开放接口
+func registerOpenWebhookRoutes(group *gin.RouterGroup, webhookHandler *handler.WebhookHandler) {
+	group.GET("/webhook/deliveries", middleware.APIScopeMiddleware(constant.APIScopeWebhookRead), webhookHandler.GetClientDeliveries) // 获取本调用方的投递记录
+}
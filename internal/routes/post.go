@@ -10,26 +10,51 @@ import (
 )
 
 // RegisterPostRoutes 注册社交动态相关路由
-func RegisterPostRoutes(r *gin.Engine) {
+func RegisterPostRoutes(r *gin.RouterGroup) {
 	// 从容器获取服务
 	container := container.GetInstance()
 	postHandler := container.GetPostHandler()
+	insightHandler := container.GetPostInsightHandler()
 
 	// 动态相关路由
-	postGroup := r.Group("/api/post")
+	postGroup := r.Group("/post", middleware.RequestLimit("post"))
 
-	// 注册需要认证的动态路由
-	registerPostAuthRoutes(postGroup, postHandler)
+	// 注册动态模块的路由
+	registerPostPublicRoutes(postGroup, postHandler)
+	registerPostAuthRoutes(postGroup, postHandler, insightHandler)
+}
+
+// registerPostPublicRoutes 注册动态模块的公开路由（登录可选）
+func registerPostPublicRoutes(group *gin.RouterGroup, postHandler *handler.PostHandler) {
+	// 添加可选认证中间件，登录用户按用户ID去重
+	publicGroup := group.Group("/", middleware.OptionalAuthMiddleware())
+
+	publicGroup.POST("/view/:post_id", postHandler.RecordView) // 记录动态浏览
 }
 
 // registerPostAuthRoutes 注册需要认证的动态相关路由
-func registerPostAuthRoutes(group *gin.RouterGroup, postHandler *handler.PostHandler) {
+func registerPostAuthRoutes(group *gin.RouterGroup, postHandler *handler.PostHandler, insightHandler *handler.PostInsightHandler) {
 	// 添加认证中间件
 	authGroup := group.Group("/", middleware.AuthMiddleware())
+	// 发布、编辑、恢复、评论和点赞接口需检查用户是否被禁言
+	muteCheck := middleware.MuteMiddleware()
 
-	authGroup.POST("/create", postHandler.CreatePost)            // 创建动态
-	authGroup.GET("/list", postHandler.GetPosts)                 // 获取动态列表
-	authGroup.POST("/like", postHandler.LikePost)                // 点赞动态
-	authGroup.POST("/comment", postHandler.CommentPost)          // 评论动态
-	authGroup.GET("/comments/:post_id", postHandler.GetComments) // 获取评论列表
+	authGroup.POST("/create", muteCheck, postHandler.CreatePost)                    // 创建动态
+	authGroup.GET("/list", postHandler.GetPosts)                                    // 获取动态列表
+	authGroup.GET("/insights", insightHandler.GetInsights)                          // 获取当前用户动态的每日数据分析
+	authGroup.GET("/nearby", postHandler.GetNearbyPosts)                            // 获取地图范围内的附近动态聚合点
+	authGroup.POST("/update", muteCheck, postHandler.UpdatePost)                    // 编辑动态
+	authGroup.GET("/:post_id", postHandler.GetPost)                                 // 获取动态详情
+	authGroup.GET("/:post_id/revisions", postHandler.GetRevisions)                  // 获取动态的修订记录
+	authGroup.GET("/:post_id/translation", postHandler.TranslatePost)               // 翻译动态内容
+	authGroup.POST("/delete", postHandler.DeletePost)                               // 删除动态，移入回收站
+	authGroup.GET("/trash", postHandler.GetTrash)                                   // 获取回收站动态列表
+	authGroup.POST("/trash/restore", muteCheck, postHandler.RestorePost)            // 从回收站恢复动态
+	authGroup.POST("/like", muteCheck, postHandler.LikePost)                        // 点赞动态
+	authGroup.POST("/comment", muteCheck, postHandler.CommentPost)                  // 评论动态
+	authGroup.GET("/comments/:post_id", postHandler.GetComments)                    // 获取评论列表
+	authGroup.GET("/comment/:comment_id/replies", postHandler.GetCommentReplies)    // 获取评论的回复列表
+	authGroup.GET("/comment/:comment_id/translation", postHandler.TranslateComment) // 翻译评论内容
+	authGroup.POST("/comment/like", muteCheck, postHandler.LikeComment)             // 点赞评论
+	authGroup.POST("/comment/unlike", postHandler.UnlikeComment)                    // 取消点赞评论
 }
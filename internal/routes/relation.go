@@ -10,16 +10,20 @@ import (
 )
 
 // RegisterRelationRoutes 注册用户关系相关路由
-func RegisterRelationRoutes(r *gin.Engine) {
+func RegisterRelationRoutes(r *gin.RouterGroup) {
 	// 从容器获取用户关系服务
 	container := container.GetInstance()
 	relationHandler := container.GetRelationHandler()
+	audienceHandler := container.GetAudienceHandler()
+	privacyHandler := container.GetPostPrivacyHandler()
 
 	// 用户关系相关路由
-	relationGroup := r.Group("/api/relation")
+	relationGroup := r.Group("/relation", middleware.RequestLimit("relation"))
 
 	// 注册需要认证的用户关系路由
 	registerRelationAuthRoutes(relationGroup, relationHandler)
+	registerAudienceRoutes(relationGroup, audienceHandler)
+	registerPostExclusionRoutes(relationGroup, privacyHandler)
 }
 
 // registerRelationAuthRoutes 注册需要认证的用户关系相关路由
@@ -27,14 +31,54 @@ func registerRelationAuthRoutes(group *gin.RouterGroup, handler *handler.Relatio
 	// 添加认证中间件
 	authGroup := group.Group("/", middleware.AuthMiddleware())
 
-	authGroup.POST("/follow", handler.FollowUser)                // 关注用户
-	authGroup.POST("/unfollow", handler.UnfollowUser)            // 取消关注
-	authGroup.GET("/followers/:user_id", handler.GetFollowers)   // 获取粉丝列表
-	authGroup.GET("/following/:user_id", handler.GetFollowing)   // 获取关注列表
-	authGroup.POST("/friend/add", handler.AddFriend)             // 添加好友
-	authGroup.POST("/friend/accept", handler.AcceptFriend)       // 接受好友请求
-	authGroup.POST("/friend/reject", handler.RejectFriend)       // 拒绝好友请求
-	authGroup.POST("/friend/delete", handler.DeleteFriend)       // 删除好友
-	authGroup.GET("/friend/requests", handler.GetFriendRequests) // 获取好友请求列表
-	authGroup.GET("/friend/list", handler.GetFriends)            // 获取好友列表
+	authGroup.POST("/follow", handler.FollowUser)                             // 关注用户
+	authGroup.POST("/unfollow", handler.UnfollowUser)                         // 取消关注
+	authGroup.POST("/follow/batch", handler.BatchFollow)                      // 批量关注
+	authGroup.POST("/unfollow/batch", handler.BatchUnfollow)                  // 批量取消关注
+	authGroup.POST("/followers/remove", handler.RemoveFollowers)              // 批量移除粉丝
+	authGroup.GET("/followers/export", handler.ExportFollowers)               // 流式导出当前用户的全部粉丝
+	authGroup.GET("/followers/:user_id", handler.GetFollowers)                // 获取粉丝列表
+	authGroup.GET("/following/:user_id", handler.GetFollowing)                // 获取关注列表
+	authGroup.GET("/counts/:user_id", handler.GetRelationCounts)              // 获取粉丝数和关注数
+	authGroup.POST("/follow/approve", handler.ApproveFollow)                  // 通过关注请求
+	authGroup.POST("/follow/reject", handler.RejectFollow)                    // 拒绝关注请求
+	authGroup.GET("/follow/requests", handler.GetFollowRequests)              // 获取关注请求列表
+	authGroup.POST("/privacy", handler.SetPrivateAccount)                     // 设置私密账号
+	authGroup.POST("/friend/add", handler.AddFriend)                          // 添加好友
+	authGroup.POST("/friend/accept", handler.AcceptFriend)                    // 接受好友请求
+	authGroup.POST("/friend/reject", handler.RejectFriend)                    // 拒绝好友请求
+	authGroup.POST("/friend/delete", handler.DeleteFriend)                    // 删除好友
+	authGroup.GET("/friend/requests", handler.GetFriendRequests)              // 获取好友请求列表
+	authGroup.GET("/friend/list", handler.GetFriends)                         // 获取好友列表，支持按group参数筛选分组
+	authGroup.POST("/friend/remark", handler.UpdateFriendRemark)              // 设置好友备注
+	authGroup.POST("/friend/group", handler.UpdateFriendGroup)                // 设置好友分组
+	authGroup.GET("/recommendations", handler.GetRecommendations)             // 获取好友推荐
+	authGroup.POST("/recommendations/dismiss", handler.DismissRecommendation) // 对推荐的用户标记不感兴趣
+
+	// 粉丝导出的响应体可能很大，不记录到请求日志
+	middleware.SkipBodyLog(authGroup, "/followers/export")
+}
+
+// registerAudienceRoutes 注册好友列表相关路由，好友列表用于设置动态的可见范围
+func registerAudienceRoutes(group *gin.RouterGroup, handler *handler.AudienceHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/audience", middleware.AuthMiddleware())
+
+	authGroup.POST("/create", handler.CreateList)            // 创建好友列表
+	authGroup.GET("/list", handler.GetLists)                 // 获取好友列表
+	authGroup.POST("/update", handler.UpdateList)            // 修改好友列表名称
+	authGroup.POST("/delete", handler.DeleteList)            // 删除好友列表
+	authGroup.GET("/members/:list_id", handler.GetMembers)   // 获取好友列表成员
+	authGroup.POST("/members/add", handler.AddMembers)       // 添加好友列表成员
+	authGroup.POST("/members/remove", handler.RemoveMembers) // 移除好友列表成员
+}
+
+// registerPostExclusionRoutes 注册动态屏蔽相关路由，被屏蔽的用户看不到当前用户的任何动态
+func registerPostExclusionRoutes(group *gin.RouterGroup, handler *handler.PostPrivacyHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/exclusion", middleware.AuthMiddleware())
+
+	authGroup.GET("/list", handler.GetExclusions)       // 获取不让看自己动态的用户列表
+	authGroup.POST("/add", handler.AddExclusions)       // 不让指定用户看自己的动态
+	authGroup.POST("/remove", handler.RemoveExclusions) // 取消屏蔽
 }
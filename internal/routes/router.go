@@ -2,8 +2,13 @@
 package routes
 
 import (
+	"time"
+
+	"app/config"
 	"app/internal/container"
 	"app/internal/middleware"
+	"app/pkg/debug"
+	"app/pkg/poolmonitor"
 	"app/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -12,29 +17,92 @@ import (
 // SetupRouter 配置并注册所有API路由
 // 返回配置完成的Gin路由引擎实例
 func SetupRouter(r *gin.Engine) *gin.Engine {
-	// 应用全局中间件
-	r.Use(middleware.Logger())
-
 	// 预初始化容器
-	_ = container.GetInstance()
+	c := container.GetInstance()
+
+	// 应用全局中间件，跨域中间件需要在路由匹配前处理预检请求
+	// 压缩中间件在请求日志之前，请求日志记录压缩前的响应体
+	// IP归属地中间件在语言协商之前，未指定语言时按归属地选择
+	r.Use(
+		middleware.Compress(),
+		middleware.Logger(),
+		middleware.Recovery(),
+		middleware.SecurityHeaders(),
+		middleware.CORS(),
+		middleware.GeoIP(c.GetGeoIPClient()),
+		middleware.Locale(),
+		middleware.Maintenance(),
+		middleware.ActivityTracker(),
+	)
+
+	// 认证中间件校验令牌版本，用户退出所有设备后旧令牌立即失效
+	middleware.SetTokenVersionLoader(c.GetUserService().GetTokenVersion)
 
 	// 注册基础路由
 	registerBaseRoutes(r)
 
-	// 注册业务模块路由
-	registerModuleRoutes(r)
+	// 按版本注册业务模块路由
+	registerVersionedRoutes(r)
 
 	return r
 }
 
+// APIVersion 描述一个API版本的挂载方式
+// 不同版本可以拥有独立的中间件链和路由注册函数，便于未来版本调整响应结构
+type APIVersion struct {
+	Prefix      string                 // 路由前缀，如 /api/v1
+	Middlewares []gin.HandlerFunc      // 该版本专属的中间件链，在模块路由之前执行
+	Register    func(*gin.RouterGroup) // 该版本的路由注册函数
+}
+
+// apiVersions 已挂载的API版本列表
+// 新增版本（如/api/v2）时在此追加条目并提供对应的注册函数即可
+var apiVersions = []APIVersion{
+	{
+		// v2与v1路由一致，仅调整响应结构（如动态图片返回结构化列表）
+		Prefix:      "/api/v2",
+		Middlewares: []gin.HandlerFunc{middleware.APIVersion(2)},
+		Register:    registerV1Routes,
+	},
+	{
+		Prefix:   "/api/v1",
+		Register: registerV1Routes,
+	},
+	{
+		// 未带版本号的旧路由，与v1行为一致，保留兼容并提示客户端迁移到/api/v1
+		Prefix:      "/api",
+		Middlewares: []gin.HandlerFunc{middleware.Deprecation(time.Time{}, "/api/v1")},
+		Register:    registerV1Routes,
+	},
+}
+
+// registerVersionedRoutes 按版本挂载所有业务模块路由
+func registerVersionedRoutes(r *gin.Engine) {
+	for _, version := range apiVersions {
+		group := r.Group(version.Prefix, version.Middlewares...)
+		version.Register(group)
+	}
+}
+
 // registerBaseRoutes 注册基础路由，如健康检查等不属于特定业务模块的路由
 func registerBaseRoutes(r *gin.Engine) {
 	// 健康检查路由
 	r.GET("/health", HealthCheck)
+
+	// 调试路由，仅管理员可访问
+	metricsHandler := container.GetInstance().GetMetricsHandler()
+	debugGroup := r.Group("/debug", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	debugGroup.GET("/pool", metricsHandler.GetPoolStats)              // 获取连接池采样序列
+	debugGroup.GET("/pool/metrics", metricsHandler.ExportPoolMetrics) // 以Prometheus文本格式导出连接池状态
+
+	// 运行时调试接口，默认关闭
+	if config.GetDebugConfig().Enabled {
+		debug.Register(debugGroup)
+	}
 }
 
-// registerModuleRoutes 注册所有业务模块的路由
-func registerModuleRoutes(r *gin.Engine) {
+// registerV1Routes 注册v1版本的所有业务模块路由
+func registerV1Routes(r *gin.RouterGroup) {
 	// 用户模块路由
 	RegisterUserRoutes(r)
 
@@ -46,9 +114,22 @@ func registerModuleRoutes(r *gin.Engine) {
 
 	// 图片上传模块路由
 	RegisterImageRoutes(r)
+
+	// 分享模块路由
+	RegisterShareRoutes(r)
+
+	// 系统公告模块路由
+	RegisterAnnouncementRoutes(r)
+
+	// 管理后台模块路由
+	RegisterAdminRoutes(r)
+
+	// 开放接口模块路由
+	RegisterOpenRoutes(r)
 }
 
 // HealthCheck 处理健康检查请求
+// 连接池饱和时服务仍可处理请求，只在pool字段中标记，不影响健康状态
 func HealthCheck(c *gin.Context) {
-	response.Success(c, "服务运行正常", gin.H{"status": "ok"})
+	response.Success(c, "服务运行正常", gin.H{"status": "ok", "pool": poolmonitor.Status()})
 }
@@ -0,0 +1,32 @@
+// 分享相关路由定义
+package routes
+
+import (
+	"app/internal/container"
+	"app/internal/handler"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterShareRoutes 注册分享相关路由
+func RegisterShareRoutes(r *gin.RouterGroup) {
+	// 从容器获取分享处理器
+	container := container.GetInstance()
+	shareHandler := container.GetShareHandler()
+
+	// 分享相关路由组
+	shareGroup := r.Group("/share", middleware.RequestLimit("share"))
+
+	// 注册需要认证的分享路由
+	registerShareAuthRoutes(shareGroup, shareHandler)
+}
+
+// registerShareAuthRoutes 注册需要认证的分享相关路由
+func registerShareAuthRoutes(group *gin.RouterGroup, handler *handler.ShareHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.GET("/qrcode", handler.GetQRCode)     // 获取用户主页或动态的分享二维码
+	authGroup.POST("/resolve", handler.ResolveScan) // 解析扫码得到的分享链接
+}
@@ -10,17 +10,35 @@ import (
 )
 
 // RegisterUserRoutes 注册用户相关路由
-func RegisterUserRoutes(r *gin.Engine) {
+func RegisterUserRoutes(r *gin.RouterGroup) {
 	// 从容器获取用户服务
 	container := container.GetInstance()
 	userHandler := container.GetUserHandler()
+	exportHandler := container.GetDataExportHandler()
+	relationHandler := container.GetRelationHandler()
+	featureHandler := container.GetFeatureHandler()
+	notificationHandler := container.GetNotificationHandler()
+	visitHandler := container.GetProfileVisitHandler()
+	profileHandler := container.GetProfileHandler()
+	usernameHandler := container.GetUsernameHandler()
+	activityHandler := container.GetActivityHandler()
+	privacyHandler := container.GetPostPrivacyHandler()
 
 	// 用户相关路由
-	userGroup := r.Group("/api/user")
+	userGroup := r.Group("/user", middleware.RequestLimit("user"))
 
 	// 注册用户模块的路由
 	registerUserPublicRoutes(userGroup, userHandler)
 	registerUserAuthRoutes(userGroup, userHandler)
+	registerUserExportRoutes(userGroup, exportHandler)
+	registerUserDiscoverRoutes(userGroup, relationHandler)
+	registerUserFeatureRoutes(userGroup, featureHandler)
+	registerUserNotificationRoutes(userGroup, notificationHandler)
+	registerUserVisitRoutes(userGroup, visitHandler)
+	registerUserProfileRoutes(userGroup, profileHandler)
+	registerUsernameRoutes(userGroup, usernameHandler)
+	registerUserActivityRoutes(userGroup, activityHandler)
+	registerUserPostPrivacyRoutes(userGroup, privacyHandler)
 }
 
 // registerUserPublicRoutes 注册用户模块的公开路由（无需认证）
@@ -34,7 +52,90 @@ func registerUserAuthRoutes(group *gin.RouterGroup, handler *handler.UserHandler
 	// 添加认证中间件
 	authGroup := group.Group("/", middleware.AuthMiddleware())
 
-	authGroup.POST("/logout", handler.Logout)                // 退出登录
-	authGroup.POST("/deactivate", handler.DeactivateAccount) // 注销账号
-	authGroup.GET("/:id", handler.GetUserInfo)               // 获取用户信息
+	authGroup.POST("/logout", handler.Logout)                         // 退出登录
+	authGroup.POST("/logout-all", handler.LogoutAll)                  // 退出所有设备
+	authGroup.POST("/deactivate", handler.DeactivateAccount)          // 注销账号
+	authGroup.GET("/search", handler.SearchUsers)                     // 按昵称或手机号搜索用户
+	authGroup.POST("/privacy/mobile-search", handler.SetMobileSearch) // 设置是否允许通过手机号搜索到自己
+	authGroup.GET("/:id", handler.GetUserInfo)                        // 获取用户信息
+}
+
+// registerUserExportRoutes 注册用户数据导出路由（需要认证）
+func registerUserExportRoutes(group *gin.RouterGroup, handler *handler.DataExportHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.POST("/:id/export", handler.RequestExport)       // 发起数据导出
+	authGroup.GET("/:id/export/:export_id", handler.GetExport) // 查询导出任务状态
+}
+
+// registerUserDiscoverRoutes 注册通讯录好友发现路由（需要认证）
+func registerUserDiscoverRoutes(group *gin.RouterGroup, handler *handler.RelationHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.POST("/discover", handler.DiscoverContacts) // 根据通讯录手机号摘要发现已注册用户
+}
+
+// registerUserFeatureRoutes 注册功能开关查询路由（需要认证）
+func registerUserFeatureRoutes(group *gin.RouterGroup, handler *handler.FeatureHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.GET("/features", handler.GetUserFeatures) // 获取所有功能开关对当前用户的开启状态
+}
+
+// registerUserNotificationRoutes 注册通知设置路由（需要认证）
+func registerUserNotificationRoutes(group *gin.RouterGroup, handler *handler.NotificationHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.GET("/notification-settings", handler.GetSettings)    // 获取通知设置
+	authGroup.PUT("/notification-settings", handler.UpdateSettings) // 更新通知偏好和免打扰时段
+	authGroup.POST("/devices", handler.RegisterDevice)              // 上报设备推送令牌
+	authGroup.DELETE("/devices/:token", handler.UnregisterDevice)   // 删除设备推送令牌
+}
+
+// registerUserVisitRoutes 注册主页访客路由（需要认证）
+func registerUserVisitRoutes(group *gin.RouterGroup, handler *handler.ProfileVisitHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.POST("/:id/visit", handler.RecordVisit)          // 记录访问他人主页
+	authGroup.GET("/visitors", handler.GetVisitors)            // 获取最近30天的主页访客
+	authGroup.GET("/visits", handler.GetVisited)               // 获取最近30天访问过的主页
+	authGroup.POST("/privacy/visits", handler.SetRecordVisits) // 设置是否记录主页访问足迹
+}
+
+// registerUserProfileRoutes 注册用户主页路由（需要认证）
+func registerUserProfileRoutes(group *gin.RouterGroup, handler *handler.ProfileHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.GET("/:id/profile", handler.GetProfile) // 获取用户主页，一次返回用户信息、计数、关系和最近动态
+}
+
+// registerUsernameRoutes 注册用户名路由（需要认证）
+func registerUsernameRoutes(group *gin.RouterGroup, handler *handler.UsernameHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.PUT("/username", handler.ChangeUsername)            // 修改用户名
+	authGroup.GET("/username/:username", handler.ResolveUsername) // 按用户名查找用户，旧用户名跳转到当前用户名
+}
+
+// registerUserActivityRoutes 注册用户动态时间线路由（需要认证）
+func registerUserActivityRoutes(group *gin.RouterGroup, handler *handler.ActivityHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.GET("/:id/activity", handler.GetActivity) // 获取本人的动态时间线
+}
+
+// registerUserPostPrivacyRoutes 注册动态隐私设置路由（需要认证）
+func registerUserPostPrivacyRoutes(group *gin.RouterGroup, handler *handler.PostPrivacyHandler) {
+	// 添加认证中间件
+	authGroup := group.Group("/", middleware.AuthMiddleware())
+
+	authGroup.POST("/privacy/post-visibility", handler.SetDefaultVisibility) // 设置发布动态的默认可见性
 }
@@ -0,0 +1,27 @@
+package scheduler
+
+import (
+	"context"
+
+	"app/internal/container"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// AccountDeletionTask 账号注销数据清理任务
+// 分阶段清理已注销用户的数据，支持中断后继续
+func AccountDeletionTask(ctx context.Context) error {
+	logger.Info(ctx, "执行账号注销数据清理任务", zap.String("task", "account_deletion"))
+
+	return container.GetInstance().GetAccountDeletionService().ProcessPendingDeletions(ctx)
+}
+
+// AccountDeactivationFinalizeTask 注销冷静期到期处理任务
+// 删除冷静期已结束的账号，并创建数据清理任务
+func AccountDeactivationFinalizeTask(ctx context.Context) error {
+	logger.Info(ctx, "执行注销冷静期到期处理任务", zap.String("task", "account_deactivation_finalize"))
+
+	_, err := container.GetInstance().GetAccountDeletionService().FinalizeDeactivations(ctx)
+	return err
+}
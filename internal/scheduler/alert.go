@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/pkg/notify"
+	"app/pkg/scheduler"
+)
+
+// alertName 通知中的告警名称标签
+const alertName = "SchedulerTaskSLO"
+
+// alertNotifier 将任务SLO告警转换为通知消息发送，实现了scheduler.AlertNotifier接口
+type alertNotifier struct {
+	notifier notify.Notifier
+}
+
+// NewAlertNotifier 创建任务SLO告警通知实例，notifier为nil时返回nil，只记录告警状态不发送通知
+func NewAlertNotifier(notifier notify.Notifier) scheduler.AlertNotifier {
+	if notifier == nil {
+		return nil
+	}
+	return &alertNotifier{notifier: notifier}
+}
+
+// Notify 发送告警通知，实现scheduler.AlertNotifier接口
+func (n *alertNotifier) Notify(ctx context.Context, alerts []scheduler.Alert) error {
+	firing := 0
+	var lines []string
+	msgAlerts := make([]notify.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		summary := alertSummary(alert)
+		status := "告警"
+		if alert.Status == scheduler.AlertStatusResolved {
+			status = "恢复"
+		} else {
+			firing++
+		}
+		lines = append(lines, fmt.Sprintf("- 【%s】**%s**：%s，开始于%s", status, alert.Task, summary, alert.StartsAt.Format(time.DateTime)))
+
+		msgAlert := notify.Alert{
+			Status: alert.Status,
+			Labels: map[string]string{
+				"alertname": alertName,
+				"task":      alert.Task,
+				"rule":      alert.Rule,
+			},
+			Annotations: map[string]string{
+				"summary":     summary,
+				"description": TaskConfigs[alert.Task].Description,
+				"value":       fmt.Sprint(alert.Value),
+				"threshold":   fmt.Sprint(alert.Threshold),
+			},
+			StartsAt: alert.StartsAt,
+		}
+		if alert.EndsAt != nil {
+			msgAlert.EndsAt = *alert.EndsAt
+		}
+		msgAlerts = append(msgAlerts, msgAlert)
+	}
+
+	title := fmt.Sprintf("定时任务SLO告警：%d条告警，%d条恢复", firing, len(alerts)-firing)
+	return n.notifier.Send(ctx, &notify.Message{
+		Title:   title,
+		Content: strings.Join(lines, "\n"),
+		Alerts:  msgAlerts,
+	})
+}
+
+// alertSummary 告警的文字说明
+func alertSummary(alert scheduler.Alert) string {
+	switch alert.Rule {
+	case scheduler.AlertRuleConsecutiveFailures:
+		return fmt.Sprintf("连续失败%.0f次，阈值%.0f次", alert.Value, alert.Threshold)
+	case scheduler.AlertRuleMaxDuration:
+		return fmt.Sprintf("最近一次执行耗时%s，阈值%s", secondsDuration(alert.Value), secondsDuration(alert.Threshold))
+	case scheduler.AlertRuleMissedRuns:
+		return fmt.Sprintf("自最近一次成功后已错过%.0f次执行，阈值%.0f次", alert.Value, alert.Threshold)
+	default:
+		return fmt.Sprintf("%s当前值%v，阈值%v", alert.Rule, alert.Value, alert.Threshold)
+	}
+}
+
+// secondsDuration 将秒数转换为便于阅读的时长
+func secondsDuration(seconds float64) time.Duration {
+	return (time.Duration(seconds * float64(time.Second))).Round(time.Second)
+}
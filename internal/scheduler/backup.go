@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"app/config"
+	"app/internal/backup"
+	"app/pkg/cos"
+	"app/pkg/database"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// ContentBackupTask 用户内容备份任务
+// 未开启定时备份时直接返回，开启后距上次全量备份超过配置的间隔时执行全量备份，否则执行增量备份
+func ContentBackupTask(ctx context.Context) error {
+	cfg := config.GetBackupConfig()
+	if !cfg.Scheduled {
+		return nil
+	}
+
+	logger.Info(ctx, "执行用户内容备份任务", zap.String("task", "content_backup"))
+
+	storage, err := cos.GetStorageClient()
+	if err != nil {
+		return fmt.Errorf("获取COS客户端失败: %w", err)
+	}
+
+	manifestKey, manifest, err := backup.New(database.GetDB(), storage, cfg).Backup(ctx, backup.ModeAuto, backup.FormatJSON)
+	if err != nil {
+		return fmt.Errorf("备份用户内容失败: %w", err)
+	}
+
+	logger.Info(ctx, "用户内容备份任务完成", zap.String("mode", manifest.Mode), zap.String("manifest", manifestKey))
+	return nil
+}
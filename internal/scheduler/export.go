@@ -0,0 +1,18 @@
+package scheduler
+
+import (
+	"context"
+
+	"app/internal/container"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// DataExportTask 用户数据导出任务
+// 处理等待中的个人数据导出请求
+func DataExportTask(ctx context.Context) error {
+	logger.Info(ctx, "执行用户数据导出任务", zap.String("task", "data_export"))
+
+	return container.GetInstance().GetDataExportService().ProcessPendingExports(ctx)
+}
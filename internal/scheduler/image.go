@@ -0,0 +1,21 @@
+package scheduler
+
+import (
+	"context"
+
+	"app/internal/container"
+	"app/pkg/logger"
+	"app/pkg/scheduler"
+
+	"go.uber.org/zap"
+)
+
+// ImageURLRefreshTask 图片地址刷新任务
+// 私有桶的图片保存带有效期的预签名URL，在过期前重新生成并保存，存储桶改为公有读后替换为永久URL
+func ImageURLRefreshTask(ctx context.Context) error {
+	logger.Info(ctx, "执行图片地址刷新任务", zap.String("task", "image_url_refresh"))
+
+	refreshed, err := container.GetInstance().GetImageService().RefreshExpiringURLs(ctx)
+	scheduler.SetGauge(ctx, "image_url_refreshed", "最近一次图片地址刷新更新的图片数", float64(refreshed))
+	return err
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/container"
+	"app/pkg/logger"
+	"app/pkg/scheduler"
+
+	"go.uber.org/zap"
+)
+
+// JobTypes 可通过管理接口提交的一次性任务类型
+var JobTypes = map[string]scheduler.JobType{
+	"post_view_flush": {
+		Handler:  PostViewFlushJob,
+		Validate: validatePostViewFlushParams,
+		Timeout:  30 * time.Minute,
+	},
+	"feed_affinity_refresh": {
+		Handler: FeedAffinityRefreshJob,
+		Timeout: 30 * time.Minute,
+	},
+}
+
+// PostViewFlushParams 动态浏览数同步任务参数
+type PostViewFlushParams struct {
+	Date string `json:"date"` // 需要同步的日期，格式为2006-01-02
+}
+
+// parsePostViewFlushParams 解析并校验动态浏览数同步任务参数
+func parsePostViewFlushParams(params json.RawMessage) (time.Time, error) {
+	var p PostViewFlushParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return time.Time{}, fmt.Errorf("解析参数失败: %w", err)
+	}
+	date, err := time.ParseInLocation(constant.StatsDateLayout, p.Date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日期格式无效: %w", err)
+	}
+	year, month, day := time.Now().Date()
+	if !date.Before(time.Date(year, month, day, 0, 0, 0, 0, time.Local)) {
+		return time.Time{}, errors.New("只能同步今天之前的浏览数")
+	}
+	return date, nil
+}
+
+// validatePostViewFlushParams 提交时校验动态浏览数同步任务参数
+func validatePostViewFlushParams(params json.RawMessage) error {
+	_, err := parsePostViewFlushParams(params)
+	return err
+}
+
+// PostViewFlushJob 同步指定日期的动态去重浏览数，用于补偿定时同步失败的日期
+func PostViewFlushJob(ctx context.Context, params json.RawMessage) error {
+	date, err := parsePostViewFlushParams(params)
+	if err != nil {
+		return err
+	}
+
+	count, err := container.GetInstance().GetPostService().FlushViews(ctx, date)
+	if err != nil {
+		return fmt.Errorf("同步动态浏览数失败(%s): %w", date.Format(constant.StatsDateLayout), err)
+	}
+	logger.Info(ctx, "已同步指定日期的动态浏览数", zap.String("date", date.Format(constant.StatsDateLayout)), zap.Int("posts", count))
+	return nil
+}
+
+// FeedAffinityRefreshJob 立即刷新作者亲密度特征，无需参数
+func FeedAffinityRefreshJob(ctx context.Context, _ json.RawMessage) error {
+	return FeedAffinityRefreshTask(ctx)
+}
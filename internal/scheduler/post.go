@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/container"
+	"app/pkg/logger"
+	"app/pkg/scheduler"
+
+	"go.uber.org/zap"
+)
+
+// PostViewFlushTask 动态浏览数同步任务
+// 将当天之前的去重浏览数同步到数据库，回溯多天以补偿错过的执行
+func PostViewFlushTask(ctx context.Context) error {
+	logger.Info(ctx, "执行动态浏览数同步任务", zap.String("task", "post_view_flush"))
+
+	postService := container.GetInstance().GetPostService()
+
+	now := time.Now()
+	for i := constant.PostViewFlushDays; i >= 1; i-- {
+		date := now.AddDate(0, 0, -i)
+		if _, err := postService.FlushViews(ctx, date); err != nil {
+			return fmt.Errorf("同步动态浏览数失败(%s): %w", date.Format(constant.StatsDateLayout), err)
+		}
+	}
+
+	return nil
+}
+
+// PostInsightAggregateTask 动态每日数据汇总任务
+// 汇总当天之前的动态浏览和互动数据到数据库，回溯多天以补偿错过的执行
+func PostInsightAggregateTask(ctx context.Context) error {
+	logger.Info(ctx, "执行动态每日数据汇总任务", zap.String("task", "post_insight_aggregate"))
+
+	insightService := container.GetInstance().GetPostInsightService()
+
+	now := time.Now()
+	for i := constant.PostInsightFlushDays; i >= 1; i-- {
+		date := now.AddDate(0, 0, -i)
+		if _, err := insightService.Aggregate(ctx, date); err != nil {
+			return fmt.Errorf("汇总动态每日数据失败(%s): %w", date.Format(constant.StatsDateLayout), err)
+		}
+	}
+
+	return nil
+}
+
+// PostCounterFlushTask 动态计数同步任务
+// 将Redis中累加的动态点赞数和评论数增量批量同步到数据库
+func PostCounterFlushTask(ctx context.Context) error {
+	logger.Info(ctx, "执行动态计数同步任务", zap.String("task", "post_counter_flush"))
+
+	_, err := container.GetInstance().GetPostCounterService().Flush(ctx)
+	return err
+}
+
+// FeedAffinityRefreshTask 作者亲密度刷新任务
+// 为近期活跃用户重新计算推荐排序所需的作者亲密度特征
+func FeedAffinityRefreshTask(ctx context.Context) error {
+	logger.Info(ctx, "执行作者亲密度刷新任务", zap.String("task", "feed_affinity_refresh"))
+
+	_, err := container.GetInstance().GetPostService().RefreshAffinities(ctx)
+	return err
+}
+
+// PostTrashPurgeTask 动态回收站清除任务
+// 永久清除超过保留期的已删除动态及其图片和评论
+func PostTrashPurgeTask(ctx context.Context) error {
+	logger.Info(ctx, "执行动态回收站清除任务", zap.String("task", "post_trash_purge"))
+
+	_, err := container.GetInstance().GetPostService().PurgeTrash(ctx)
+	return err
+}
+
+// CounterReconcileTask 计数校准任务
+// 按来源表重新统计评论数、评论点赞数等冗余计数，修正不一致的记录并上报偏差指标
+func CounterReconcileTask(ctx context.Context) error {
+	logger.Info(ctx, "执行计数校准任务", zap.String("task", "counter_reconcile"))
+
+	results, err := container.GetInstance().GetCounterService().Reconcile(ctx)
+	for _, result := range results {
+		scheduler.SetGauge(ctx, "counter_reconcile_scanned_rows", "最近一次计数校准扫描的记录数", float64(result.Scanned), "counter", result.Counter)
+		scheduler.SetGauge(ctx, "counter_reconcile_drifted_rows", "最近一次计数校准修正的记录数", float64(result.Drifted), "counter", result.Counter)
+		scheduler.SetGauge(ctx, "counter_reconcile_drift_sum", "最近一次计数校准修正的偏差绝对值之和", float64(result.DriftSum), "counter", result.Counter)
+		scheduler.SetGauge(ctx, "counter_reconcile_drift_max", "最近一次计数校准中单条记录的最大偏差绝对值", float64(result.DriftMax), "counter", result.Counter)
+	}
+	return err
+}
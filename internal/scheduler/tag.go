@@ -0,0 +1,19 @@
+package scheduler
+
+import (
+	"context"
+
+	"app/internal/container"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// UserSegmentRefreshTask 用户分群刷新任务
+// 按规则重新计算所有规则标签的成员，供公告、功能开关和推送按标签圈选用户
+func UserSegmentRefreshTask(ctx context.Context) error {
+	logger.Info(ctx, "执行用户分群刷新任务", zap.String("task", "user_segment_refresh"))
+
+	_, err := container.GetInstance().GetTagService().RefreshSegments(ctx)
+	return err
+}
@@ -1,23 +1,36 @@
 package scheduler
 
 import (
-	"app/pkg/scheduler"
 	"time"
+
+	"app/config"
+	"app/pkg/scheduler"
 )
 
+// NoRetry 任务失败后不重试，用于TaskConfig.RetryCount
+// RetryCount为0表示未指定并使用scheduler.task_defaults.retry_count，需要明确不重试的任务使用该值
+const NoRetry = -1
+
 // TaskConfig 定义任务配置结构
 type TaskConfig struct {
-	Spec           string                // Cron表达式
-	Description    string                // 任务描述
-	Timeout        time.Duration         // 任务超时时间
-	RetryCount     int                   // 失败重试次数
-	Priority       int                   // 任务优先级（1-10，10为最高）
-	Handler        scheduler.TaskHandler // 任务处理函数
-	RunImmediately bool                  // 是否在添加后立即执行任务
-	LockTimeout    time.Duration         // 分布式锁超时时间
+	Spec             string                  // Cron表达式
+	Description      string                  // 任务描述
+	Timeout          time.Duration           // 任务超时时间
+	RetryCount       int                     // 失败重试次数，为0时使用scheduler.task_defaults.retry_count，不重试时设置为NoRetry
+	RetryInterval    time.Duration           // 重试间隔，为0时使用scheduler.task_defaults.retry_interval
+	Priority         int                     // 任务优先级（1-10，10为最高）
+	Handler          scheduler.TaskHandler   // 任务处理函数
+	RunImmediately   bool                    // 是否在添加后立即执行任务
+	LockTimeout      time.Duration           // 分布式锁超时时间，为0时使用scheduler.task_defaults.lock_timeout
+	MisfirePolicy    scheduler.MisfirePolicy // 错过执行策略，为空时跳过错过的执行
+	CatchUpLimit     int                     // 补执行的最大次数，仅catch_up策略有效
+	DependsOn        []string                // 依赖的任务名称，依赖在窗口期内成功执行后才会执行
+	DependencyWindow time.Duration           // 依赖任务成功执行的有效窗口
+	Critical         bool                    // 是否为关键任务，维护模式下仅执行关键任务
+	SLO              scheduler.SLO           // 服务等级目标，启用告警检查时未达标的任务发送告警
 }
 
-// 定义所有定时任务的配置
+// 定义所有定时任务的配置，锁超时时间、重试策略和是否启用可通过scheduler.tasks按任务覆盖，注册时使用EnabledTaskConfigs
 var TaskConfigs = map[string]TaskConfig{
 	"user_cleanup": {
 		Spec:           "0 0 2 * * *", // 每天凌晨2点执行
@@ -28,6 +41,7 @@ var TaskConfigs = map[string]TaskConfig{
 		Handler:        UserCleanupTask,
 		RunImmediately: false,
 		LockTimeout:    30 * time.Minute,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // 停机错过时启动后补执行一次
 	},
 	"system_health": {
 		Spec:           "0 */30 * * * *", // 每30分钟执行一次
@@ -38,15 +52,223 @@ var TaskConfigs = map[string]TaskConfig{
 		Handler:        SystemHealthCheckTask,
 		RunImmediately: true,
 		LockTimeout:    5 * time.Minute,
+		Critical:       true, // 维护期间仍需监控各组件状态
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 2},
 	},
 	"data_statistics": {
-		Spec:           "0 */5 * * * *", // 每5分钟执行一次
-		Description:    "生成系统数据统计报告，包括用户活跃度和系统资源使用情况",
-		Timeout:        60 * time.Minute,
+		Spec:             "0 */5 * * * *", // 每5分钟执行一次
+		Description:      "生成系统数据统计报告，包括用户活跃度和系统资源使用情况",
+		Timeout:          60 * time.Minute,
+		RetryCount:       2,
+		Priority:         4,
+		Handler:          DataStatisticsTask,
+		RunImmediately:   false,
+		LockTimeout:      60 * time.Minute,
+		MisfirePolicy:    scheduler.MisfireRunOnce, // 快照按天覆盖生成，补执行一次即可
+		DependsOn:        []string{"user_cleanup"}, // 用户清理完成后再统计，避免统计到待清理的数据
+		DependencyWindow: 25 * time.Hour,           // 用户清理每天执行一次，预留1小时余量
+	},
+	"data_export": {
+		Spec:           "0 * * * * *", // 每分钟执行一次
+		Description:    "处理用户发起的个人数据导出任务，打包上传并通知用户",
+		Timeout:        10 * time.Minute,
+		RetryCount:     NoRetry,
+		Priority:       6,
+		Handler:        DataExportTask,
+		RunImmediately: false,
+		LockTimeout:    10 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 5, MaxDuration: 5 * time.Minute},
+	},
+	"post_view_flush": {
+		Spec:           "0 10 0 * * *", // 每天凌晨0点10分执行
+		Description:    "将前几天的动态去重浏览数从Redis同步到数据库",
+		Timeout:        30 * time.Minute,
 		RetryCount:     2,
 		Priority:       4,
-		Handler:        DataStatisticsTask,
+		Handler:        PostViewFlushTask,
 		RunImmediately: false,
-		LockTimeout:    60 * time.Minute,
+		LockTimeout:    30 * time.Minute,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // 未同步的数据会过期，停机错过时需补执行
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 1, MaxMissedRuns: 1},
+	},
+	"post_insight_aggregate": {
+		Spec:           "0 20 0 * * *", // 每天凌晨0点20分执行
+		Description:    "将前几天每条动态的浏览、点赞、评论、分享和带来的关注数汇总到数据库，供作者查看数据分析",
+		Timeout:        30 * time.Minute,
+		RetryCount:     2,
+		Priority:       4,
+		Handler:        PostInsightAggregateTask,
+		RunImmediately: false,
+		LockTimeout:    30 * time.Minute,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // Redis中的计数会过期，停机错过时需补执行
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 1, MaxMissedRuns: 1},
 	},
+	"post_counter_flush": {
+		Spec:           "0 * * * * *", // 每分钟执行一次
+		Description:    "将Redis中累加的动态点赞数和评论数增量同步到数据库",
+		Timeout:        5 * time.Minute,
+		RetryCount:     1,
+		Priority:       6,
+		Handler:        PostCounterFlushTask,
+		RunImmediately: true, // 启动时同步上次停机前未同步的增量
+		LockTimeout:    5 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 5, MaxDuration: time.Minute},
+	},
+	"feed_affinity_refresh": {
+		Spec:           "0 30 * * * *", // 每小时第30分钟执行
+		Description:    "根据近期互动刷新活跃用户的作者亲密度特征，用于动态推荐排序",
+		Timeout:        30 * time.Minute,
+		RetryCount:     NoRetry,
+		Priority:       3,
+		Handler:        FeedAffinityRefreshTask,
+		RunImmediately: false,
+		LockTimeout:    30 * time.Minute,
+	},
+	"friend_recommendation": {
+		Spec:           "0 0 3 * * *", // 每天凌晨3点执行
+		Description:    "根据共同好友、共同关注和通讯录为近期活跃用户计算好友推荐",
+		Timeout:        time.Hour,
+		RetryCount:     NoRetry,
+		Priority:       3,
+		Handler:        FriendRecommendationTask,
+		RunImmediately: false,
+		LockTimeout:    time.Hour,
+	},
+	"profile_visit_purge": {
+		Spec:           "0 15 4 * * *", // 每天凌晨4点15分执行
+		Description:    "删除超过30天的主页访问记录",
+		Timeout:        30 * time.Minute,
+		RetryCount:     1,
+		Priority:       3,
+		Handler:        ProfileVisitPurgeTask,
+		RunImmediately: false,
+		LockTimeout:    30 * time.Minute,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // 停机错过时启动后补执行一次
+	},
+	"post_trash_purge": {
+		Spec:           "0 0 4 * * *", // 每天凌晨4点执行
+		Description:    "永久清除回收站中超过30天的已删除动态，包括图片文件、评论和评论点赞",
+		Timeout:        30 * time.Minute,
+		RetryCount:     1,
+		Priority:       3,
+		Handler:        PostTrashPurgeTask,
+		RunImmediately: false,
+		LockTimeout:    30 * time.Minute,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // 停机错过时启动后补执行一次
+	},
+	"counter_reconcile": {
+		Spec:           "0 30 4 * * *", // 每天凌晨4点30分执行
+		Description:    "按评论和评论点赞记录重新统计动态评论数和评论点赞数，修正不一致的计数",
+		Timeout:        time.Hour,
+		RetryCount:     NoRetry,
+		Priority:       3,
+		Handler:        CounterReconcileTask,
+		RunImmediately: false,
+		LockTimeout:    time.Hour,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // 停机错过时启动后补执行一次
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 2},
+	},
+	"content_backup": {
+		Spec:           "0 30 3 * * *", // 每天凌晨3点30分执行
+		Description:    "备份用户、动态、评论和关系数据到对象存储，按配置的间隔执行全量备份，其余时间执行增量备份",
+		Timeout:        2 * time.Hour,
+		RetryCount:     NoRetry,
+		Priority:       3,
+		Handler:        ContentBackupTask,
+		RunImmediately: false,
+		LockTimeout:    2 * time.Hour,
+		MisfirePolicy:  scheduler.MisfireRunOnce, // 增量备份依赖上一次备份，停机错过时补执行一次
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 1, MaxDuration: 90 * time.Minute, MaxMissedRuns: 1},
+	},
+	"account_deletion": {
+		Spec:           "0 */10 * * * *", // 每10分钟执行一次
+		Description:    "推进账号注销后的数据清理任务，匿名化评论、删除关系和动态，并在保留期后清除个人信息",
+		Timeout:        30 * time.Minute,
+		RetryCount:     NoRetry,
+		Priority:       5,
+		Handler:        AccountDeletionTask,
+		RunImmediately: false,
+		LockTimeout:    30 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 3, MaxMissedRuns: 6}, // 注销清理有时限要求，停滞1小时告警
+	},
+	"account_deactivation_finalize": {
+		Spec:           "0 */10 * * * *", // 每10分钟执行一次
+		Description:    "删除注销冷静期已结束的账号，并创建账号注销数据清理任务",
+		Timeout:        10 * time.Minute,
+		RetryCount:     NoRetry,
+		Priority:       5,
+		Handler:        AccountDeactivationFinalizeTask,
+		RunImmediately: false,
+		LockTimeout:    10 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 3, MaxMissedRuns: 6}, // 冷静期结束后需及时删除账号，停滞1小时告警
+	},
+	"webhook_delivery": {
+		Spec:           "0 * * * * *", // 每分钟执行一次
+		Description:    "重试到期的Webhook投递，并删除超过保留期的投递记录",
+		Timeout:        10 * time.Minute,
+		RetryCount:     NoRetry,
+		Priority:       5,
+		Handler:        WebhookDeliveryTask,
+		RunImmediately: false,
+		LockTimeout:    10 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 5},
+	},
+	"user_segment_refresh": {
+		Spec:           "0 45 * * * *", // 每小时第45分钟执行
+		Description:    "按活跃天数、动态数和粉丝数等规则重新计算规则标签的成员",
+		Timeout:        30 * time.Minute,
+		RetryCount:     1,
+		Priority:       3,
+		Handler:        UserSegmentRefreshTask,
+		RunImmediately: false,
+		LockTimeout:    30 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 3},
+	},
+	"image_url_refresh": {
+		Spec:           "0 20 * * * *", // 每小时第20分钟执行
+		Description:    "重新生成私有存储桶中即将过期的图片预签名URL并保存到数据库",
+		Timeout:        30 * time.Minute,
+		RetryCount:     1,
+		Priority:       5,
+		Handler:        ImageURLRefreshTask,
+		RunImmediately: true, // 启动时刷新停机期间已过期的地址
+		LockTimeout:    30 * time.Minute,
+		SLO:            scheduler.SLO{MaxConsecutiveFailures: 3}, // 刷新提前量默认24小时，连续失败3次仍有余量
+	},
+}
+
+// EnabledTaskConfigs 返回配置中启用的定时任务，scheduler.tasks中配置了enabled: false的任务不返回
+// 锁超时时间和重试策略优先使用scheduler.tasks中的同名配置，其次使用任务本身的设置，均未指定时使用scheduler.task_defaults
+func EnabledTaskConfigs() map[string]TaskConfig {
+	cfg := config.GetSchedulerConfig()
+	defaults := cfg.TaskDefaults
+
+	tasks := make(map[string]TaskConfig, len(TaskConfigs))
+	for name, task := range TaskConfigs {
+		override := cfg.Tasks[name]
+		if override.Enabled != nil && !*override.Enabled {
+			continue
+		}
+
+		task.LockTimeout = config.ParseDuration(override.LockTimeout, task.LockTimeout)
+		if task.LockTimeout <= 0 {
+			task.LockTimeout = config.ParseDuration(defaults.LockTimeout, 0)
+		}
+
+		switch {
+		case override.RetryCount != nil:
+			task.RetryCount = *override.RetryCount
+		case task.RetryCount == NoRetry:
+			task.RetryCount = 0
+		case task.RetryCount == 0:
+			task.RetryCount = defaults.RetryCount
+		}
+		task.RetryInterval = config.ParseDuration(override.RetryInterval, task.RetryInterval)
+		if task.RetryInterval <= 0 {
+			task.RetryInterval = config.ParseDuration(defaults.RetryInterval, 0)
+		}
+
+		tasks[name] = task
+	}
+	return tasks
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 	"runtime"
 
+	"app/internal/constant"
+	"app/internal/container"
 	"app/pkg/database"
 	"app/pkg/logger"
 	"app/pkg/redis"
@@ -14,17 +16,25 @@ import (
 )
 
 // UserCleanupTask 用户清理任务
-// 清理长时间未活跃的用户数据
+// 清理长时间未活跃的用户数据，将其标记为休眠并输出清理指标
 func UserCleanupTask(ctx context.Context) error {
 	logger.Info(ctx, "执行用户清理任务", zap.String("task", "user_cleanup"))
 
-	// TODO: 实现用户清理逻辑
-	// 1. 查询长时间未活跃的用户
-	// 2. 清理或归档相关数据
-	// 3. 更新用户状态
+	result, err := container.GetInstance().GetUserCleanupService().CleanupInactiveUsers(ctx)
+	if result != nil {
+		logger.Info(ctx, "用户清理任务指标",
+			zap.String("task", "user_cleanup"),
+			zap.Int("active_synced", result.ActiveSynced),
+			zap.Int("users_scanned", result.UsersScanned),
+			zap.Int64("users_marked_dormant", result.UsersMarkedDormant),
+			zap.Int("sessions_archived", result.SessionsArchived),
+			zap.Int("temp_images_expired", result.TempImagesExpired),
+			zap.Int64("device_tokens_removed", result.DeviceTokensRemoved))
+	}
+	if err != nil {
+		return fmt.Errorf("清理未活跃用户失败: %w", err)
+	}
 
-	// 模拟任务执行
-	time.Sleep(2 * time.Second)
 	return nil
 }
 
@@ -75,11 +85,11 @@ func checkDatabaseConnection(ctx context.Context) bool {
 // checkRedisConnection 检查Redis连接状态
 func checkRedisConnection(ctx context.Context) bool {
 	logger.Info(ctx, "检查Redis连接")
-	if redis.Client == nil {
+	if redis.GetClient() == nil {
 		logger.Error(ctx, "Redis客户端未初始化")
 		return false
 	}
-	_, err := redis.Client.Ping(ctx).Result()
+	_, err := redis.GetClient().Ping(ctx).Result()
 	if err != nil {
 		logger.Error(ctx, "Redis Ping失败", zap.Error(err))
 		return false
@@ -101,16 +111,37 @@ func checkSystemResources(ctx context.Context) bool {
 }
 
 // DataStatisticsTask 数据统计任务
-// 生成系统数据统计报告
+// 生成系统数据统计报告，按天持久化统计快照
+// 每次执行同时刷新昨天和今天的快照，保证跨天时昨天的数据完整
 func DataStatisticsTask(ctx context.Context) error {
 	logger.Info(ctx, "执行数据统计任务", zap.String("task", "data_statistics"))
 
-	// TODO: 实现数据统计逻辑
-	// 1. 统计用户活跃度
-	// 2. 统计系统使用情况
-	// 3. 生成报告数据
+	statsService := container.GetInstance().GetStatsService()
+
+	now := time.Now()
+	for _, date := range []time.Time{now.AddDate(0, 0, -1), now} {
+		if _, err := statsService.GenerateDailySnapshot(ctx, date); err != nil {
+			return fmt.Errorf("生成统计快照失败(%s): %w", date.Format(constant.StatsDateLayout), err)
+		}
+	}
 
-	// 模拟任务执行
-	time.Sleep(3 * time.Second)
 	return nil
 }
+
+// FriendRecommendationTask 好友推荐计算任务
+// 根据共同好友、共同关注和通讯录为近期活跃用户计算好友推荐
+func FriendRecommendationTask(ctx context.Context) error {
+	logger.Info(ctx, "执行好友推荐计算任务", zap.String("task", "friend_recommendation"))
+
+	_, err := container.GetInstance().GetRelationService().RefreshRecommendations(ctx)
+	return err
+}
+
+// ProfileVisitPurgeTask 主页访问记录清理任务
+// 删除超过30天保留时间的主页访问记录
+func ProfileVisitPurgeTask(ctx context.Context) error {
+	logger.Info(ctx, "执行主页访问记录清理任务", zap.String("task", "profile_visit_purge"))
+
+	_, err := container.GetInstance().GetProfileVisitService().PurgeExpiredVisits(ctx)
+	return err
+}
@@ -0,0 +1,25 @@
+package scheduler
+
+import (
+	"context"
+
+	"app/internal/container"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// WebhookDeliveryTask Webhook投递任务
+// 重试立即投递失败或因服务中断未完成的投递，并清理过期的投递记录
+func WebhookDeliveryTask(ctx context.Context) error {
+	logger.Info(ctx, "执行Webhook投递任务", zap.String("task", "webhook_delivery"))
+
+	webhookService := container.GetInstance().GetWebhookService()
+
+	if _, err := webhookService.DeliverPending(ctx); err != nil {
+		return err
+	}
+
+	_, err := webhookService.PurgeDeliveries(ctx)
+	return err
+}
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/model"
+	"app/internal/ranking"
+	"app/internal/repository"
+	"app/pkg/cos"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// AccountDeletionService 账号注销数据清理服务接口
+type AccountDeletionService interface {
+	// ScheduleDeletion 为已注销的用户创建数据清理任务，已存在任务时不重复创建
+	ScheduleDeletion(ctx context.Context, user *model.User) error
+	// ProcessPendingDeletions 推进未完成的清理任务，由定时任务调用
+	ProcessPendingDeletions(ctx context.Context) error
+	// FinalizeDeactivations 删除注销冷静期已结束的账号并创建清理任务，由定时任务调用，返回删除的账号数量
+	FinalizeDeactivations(ctx context.Context) (int, error)
+}
+
+// accountDeletionService 账号注销数据清理服务实现
+type accountDeletionService struct {
+	deletionRepo  repository.AccountDeletionRepository
+	userRepo      repository.UserRepository
+	postRepo      repository.PostRepository
+	commentRepo   repository.PostCommentRepository
+	tempImageRepo repository.TempImageRepository
+	followerRepo  repository.UserFollowerRepository
+	friendRepo    repository.UserFriendRepository
+	audienceRepo  repository.AudienceListRepository
+	exclusionRepo repository.PostExclusionRepository
+	smsRepo       repository.SMSRepository
+	notifyRepo    repository.NotificationRepository
+	deviceRepo    repository.DeviceTokenRepository
+	visitRepo     repository.ProfileVisitRepository
+	usernameRepo  repository.UsernameHistoryRepository
+	activityRepo  repository.UserActivityRepository
+	announceRepo  repository.AnnouncementRepository
+	tagRepo       repository.TagRepository
+	imageService  ImageService
+	cosClient     *cos.StorageClient
+	smsRetention  time.Duration
+}
+
+// NewAccountDeletionService 创建账号注销数据清理服务实例
+func NewAccountDeletionService(
+	deletionRepo repository.AccountDeletionRepository,
+	userRepo repository.UserRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.PostCommentRepository,
+	tempImageRepo repository.TempImageRepository,
+	followerRepo repository.UserFollowerRepository,
+	friendRepo repository.UserFriendRepository,
+	audienceRepo repository.AudienceListRepository,
+	exclusionRepo repository.PostExclusionRepository,
+	smsRepo repository.SMSRepository,
+	notifyRepo repository.NotificationRepository,
+	deviceRepo repository.DeviceTokenRepository,
+	visitRepo repository.ProfileVisitRepository,
+	usernameRepo repository.UsernameHistoryRepository,
+	activityRepo repository.UserActivityRepository,
+	announceRepo repository.AnnouncementRepository,
+	tagRepo repository.TagRepository,
+	imageService ImageService,
+) (AccountDeletionService, error) {
+	// 获取COS客户端
+	cosClient, err := cos.GetStorageClient()
+	if err != nil {
+		return nil, fmt.Errorf("获取COS客户端失败: %w", err)
+	}
+
+	// 解析短信记录保留期，未配置或配置错误时使用默认值
+	smsRetention, err := time.ParseDuration(config.GetAccountConfig().SMSRetention)
+	if err != nil || smsRetention <= 0 {
+		smsRetention = constant.DeletionDefaultSMSRetention
+	}
+
+	return &accountDeletionService{
+		deletionRepo:  deletionRepo,
+		userRepo:      userRepo,
+		postRepo:      postRepo,
+		commentRepo:   commentRepo,
+		tempImageRepo: tempImageRepo,
+		followerRepo:  followerRepo,
+		friendRepo:    friendRepo,
+		audienceRepo:  audienceRepo,
+		exclusionRepo: exclusionRepo,
+		smsRepo:       smsRepo,
+		notifyRepo:    notifyRepo,
+		deviceRepo:    deviceRepo,
+		visitRepo:     visitRepo,
+		usernameRepo:  usernameRepo,
+		activityRepo:  activityRepo,
+		announceRepo:  announceRepo,
+		tagRepo:       tagRepo,
+		imageService:  imageService,
+		cosClient:     cosClient,
+		smsRetention:  smsRetention,
+	}, nil
+}
+
+// ScheduleDeletion 为已注销的用户创建数据清理任务，已存在任务时不重复创建
+func (s *accountDeletionService) ScheduleDeletion(ctx context.Context, user *model.User) error {
+	_, err := s.deletionRepo.FindByUserID(ctx, user.ID)
+	if err == nil {
+		logger.Info(ctx, "账号注销清理任务已存在", logger.Uint("user_id", user.ID))
+		return nil
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return fmt.Errorf("查询清理任务失败: %w", err)
+	}
+
+	deletion := &model.AccountDeletion{
+		UserID:         user.ID,
+		Mobile:         user.Mobile,
+		Stage:          constant.DeletionStagePurgeFeeds,
+		RetentionUntil: time.Now().Add(s.smsRetention),
+	}
+	if err := s.deletionRepo.Create(ctx, deletion); err != nil {
+		return fmt.Errorf("创建清理任务失败: %w", err)
+	}
+
+	logger.Info(ctx, "账号注销清理任务已创建", logger.Uint("user_id", user.ID), logger.Uint("deletion_id", deletion.ID))
+
+	return nil
+}
+
+// FinalizeDeactivations 删除注销冷静期已结束的账号并创建清理任务
+// 删除时再次校验账号仍处于冷静期，冷静期内重新登录撤销注销的账号不会被删除
+func (s *accountDeletionService) FinalizeDeactivations(ctx context.Context) (int, error) {
+	now := time.Now()
+	finalized := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return finalized, err
+		}
+
+		users, err := s.userRepo.FindDueDeactivations(ctx, now, constant.DeactivationFinalizeBatchSize)
+		if err != nil {
+			return finalized, fmt.Errorf("查询注销冷静期已结束的用户失败: %w", err)
+		}
+
+		for i := range users {
+			user := &users[i]
+
+			deleted, err := s.userRepo.FinalizeDeactivation(ctx, user.ID, now)
+			if err != nil {
+				return finalized, fmt.Errorf("删除注销账号失败: %w", err)
+			}
+			if !deleted {
+				continue
+			}
+			finalized++
+
+			// 账号已删除，创建清理任务失败时只记录错误以便人工处理
+			if err := s.ScheduleDeletion(ctx, user); err != nil {
+				logger.Error(ctx, "创建账号注销清理任务失败", logger.Uint("user_id", user.ID), logger.Err(err))
+			}
+		}
+
+		if len(users) < constant.DeactivationFinalizeBatchSize {
+			break
+		}
+	}
+
+	logger.Info(ctx, "注销冷静期到期账号处理完成", logger.Int("finalized", finalized))
+
+	return finalized, nil
+}
+
+// ProcessPendingDeletions 推进未完成的清理任务，由定时任务调用
+// 每个阶段分批执行并在每批后保存进度，任务中断后从当前阶段继续
+func (s *accountDeletionService) ProcessPendingDeletions(ctx context.Context) error {
+	deletions, err := s.deletionRepo.GetRunnable(ctx, time.Now(), constant.DeletionTaskBatchSize)
+	if err != nil {
+		return fmt.Errorf("获取待处理清理任务失败: %w", err)
+	}
+
+	for i := range deletions {
+		deletion := &deletions[i]
+
+		if err := s.processDeletion(ctx, deletion); err != nil {
+			logger.Error(ctx, "处理账号注销清理任务失败", logger.Uint("deletion_id", deletion.ID), logger.String("stage", deletion.Stage), logger.Err(err))
+			deletion.Attempts++
+			deletion.ErrorMessage = err.Error()
+			if updateErr := s.deletionRepo.Update(ctx, deletion); updateErr != nil {
+				logger.Error(ctx, "更新清理任务状态失败", logger.Uint("deletion_id", deletion.ID), logger.Err(updateErr))
+			}
+			continue
+		}
+
+		logger.Info(ctx, "账号注销清理任务已推进", logger.Uint("deletion_id", deletion.ID), logger.String("stage", deletion.Stage))
+	}
+
+	return nil
+}
+
+// processDeletion 依次执行清理阶段，直到完成或进入保留期等待
+func (s *accountDeletionService) processDeletion(ctx context.Context, deletion *model.AccountDeletion) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var (
+			done bool
+			err  error
+			next string
+		)
+
+		switch deletion.Stage {
+		case constant.DeletionStagePurgeFeeds:
+			done, err = s.purgeFeeds(ctx, deletion)
+			next = constant.DeletionStageAnonymizeComments
+		case constant.DeletionStageAnonymizeComments:
+			done, err = s.anonymizeComments(ctx, deletion)
+			next = constant.DeletionStageRemoveRelations
+		case constant.DeletionStageRemoveRelations:
+			done, err = s.removeRelations(ctx, deletion)
+			next = constant.DeletionStageDeletePosts
+		case constant.DeletionStageDeletePosts:
+			done, err = s.deletePosts(ctx, deletion)
+			next = constant.DeletionStageDeleteTempImages
+		case constant.DeletionStageDeleteTempImages:
+			done, err = s.deleteTempImages(ctx, deletion)
+			next = constant.DeletionStageAwaitRetention
+		case constant.DeletionStageAwaitRetention:
+			// 保留期未结束时等待下次调度
+			if time.Now().Before(deletion.RetentionUntil) {
+				return nil
+			}
+			done = true
+			next = constant.DeletionStageScrubPII
+		case constant.DeletionStageScrubPII:
+			done, err = s.scrubPII(ctx, deletion)
+			next = constant.DeletionStageCompleted
+		case constant.DeletionStageCompleted:
+			return nil
+		default:
+			return fmt.Errorf("未知的清理阶段: %s", deletion.Stage)
+		}
+		if err != nil {
+			return err
+		}
+
+		if done {
+			deletion.Stage = next
+			if next == constant.DeletionStageCompleted {
+				now := time.Now()
+				deletion.CompletedAt = &now
+				deletion.Mobile = ""
+			}
+		}
+		deletion.ErrorMessage = ""
+
+		// 每批处理后保存进度，保证中断后可以继续
+		if err := s.deletionRepo.Update(ctx, deletion); err != nil {
+			return fmt.Errorf("保存清理进度失败: %w", err)
+		}
+	}
+}
+
+// purgeFeeds 清除用户自己的动态推荐缓存，并从粉丝和好友缓存的亲密度和推荐排序结果中移除该用户
+// 账号删除后动态和关系查询已排除该用户，这里只清理缓存，重复执行不影响结果，因此一次处理完所有粉丝和好友
+func (s *accountDeletionService) purgeFeeds(ctx context.Context, deletion *model.AccountDeletion) (bool, error) {
+	if err := ranking.ClearFeeds(deletion.UserID); err != nil {
+		return false, fmt.Errorf("删除用户动态推荐缓存失败: %w", err)
+	}
+	if err := s.forgetAuthor(ctx, deletion.UserID, s.followerRepo.GetFollowerIDs); err != nil {
+		return false, fmt.Errorf("清除粉丝的动态推荐缓存失败: %w", err)
+	}
+	if err := s.forgetAuthor(ctx, deletion.UserID, s.friendRepo.GetFriendIDs); err != nil {
+		return false, fmt.Errorf("清除好友的动态推荐缓存失败: %w", err)
+	}
+	return true, nil
+}
+
+// forgetAuthor 按用户ID顺序分批获取相关用户，从其动态推荐缓存中移除已注销的作者
+func (s *accountDeletionService) forgetAuthor(ctx context.Context, authorID uint, list func(ctx context.Context, userID, afterID uint, limit int) ([]uint, error)) error {
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ids, err := list(ctx, authorID, afterID, constant.DeletionBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := ranking.ForgetAuthor(id, authorID); err != nil {
+				return err
+			}
+		}
+
+		if len(ids) < constant.DeletionBatchSize {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
+
+// anonymizeComments 分批匿名化用户评论，返回是否已全部处理
+func (s *accountDeletionService) anonymizeComments(ctx context.Context, deletion *model.AccountDeletion) (bool, error) {
+	affected, err := s.commentRepo.AnonymizeUserComments(ctx, deletion.UserID, constant.DeletionBatchSize)
+	if err != nil {
+		return false, fmt.Errorf("匿名化用户评论失败: %w", err)
+	}
+	deletion.CommentsAnonymized += affected
+	return affected == 0, nil
+}
+
+// removeRelations 删除用户的关注和好友关系、用户创建和所在的好友列表、动态屏蔽、主页访问记录和活动记录，以及通讯录摘要和好友推荐
+func (s *accountDeletionService) removeRelations(ctx context.Context, deletion *model.AccountDeletion) (bool, error) {
+	followers, err := s.followerRepo.DeleteAllByUser(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("删除关注关系失败: %w", err)
+	}
+	deletion.RelationsRemoved += followers
+
+	friends, err := s.friendRepo.DeleteAllByUser(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("删除好友关系失败: %w", err)
+	}
+	deletion.RelationsRemoved += friends
+
+	audiences, err := s.audienceRepo.DeleteAllByUser(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("删除好友列表失败: %w", err)
+	}
+	deletion.RelationsRemoved += audiences
+
+	exclusions, err := s.exclusionRepo.DeleteAllByUser(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("删除动态屏蔽失败: %w", err)
+	}
+	deletion.RelationsRemoved += exclusions
+
+	visits, err := s.visitRepo.DeleteAllByUser(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("删除主页访问记录失败: %w", err)
+	}
+	deletion.RelationsRemoved += visits
+
+	activities, err := s.activityRepo.DeleteAllByUser(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("删除活动记录失败: %w", err)
+	}
+	deletion.RelationsRemoved += activities
+
+	// 通讯录摘要和好友推荐随关系一并删除
+	if _, err := redis.Del(
+		cachekey.RelationContacts(deletion.UserID).String(),
+		cachekey.RelationRecommendations(deletion.UserID).String(),
+		cachekey.RelationRecommendDismissed(deletion.UserID).String(),
+	); err != nil {
+		return false, fmt.Errorf("删除通讯录摘要和好友推荐失败: %w", err)
+	}
+
+	return true, nil
+}
+
+// deletePosts 分批永久删除用户动态及其在COS中的图片，返回是否已全部处理
+// 先处理正常的动态，再处理用户注销前已删除到回收站的动态，避免回收站中的图片保留到回收站清理时才删除
+func (s *accountDeletionService) deletePosts(ctx context.Context, deletion *model.AccountDeletion) (bool, error) {
+	// 删除的动态不会再被查询到，因此始终取第一页
+	posts, _, err := s.postRepo.GetUserPosts(ctx, deletion.UserID, 1, constant.DeletionBatchSize)
+	if err != nil {
+		return false, fmt.Errorf("查询用户动态失败: %w", err)
+	}
+	if len(posts) == 0 {
+		posts, _, err = s.postRepo.GetTrashedPosts(ctx, deletion.UserID, time.Time{}, 1, constant.DeletionBatchSize)
+		if err != nil {
+			return false, fmt.Errorf("查询用户回收站动态失败: %w", err)
+		}
+	}
+	if len(posts) == 0 {
+		return true, nil
+	}
+
+	for _, post := range posts {
+		images, err := s.imageService.DeletePostImageFiles(ctx, post.ID)
+		deletion.ImagesDeleted += int64(images)
+		if err != nil {
+			return false, err
+		}
+		if err := s.postRepo.PurgePost(ctx, post.ID); err != nil {
+			return false, fmt.Errorf("删除动态失败: %w", err)
+		}
+		deletion.PostsDeleted++
+	}
+
+	logger.Debug(ctx, "已删除一批用户动态", logger.Uint("deletion_id", deletion.ID), logger.Int("count", len(posts)))
+
+	return false, nil
+}
+
+// deleteTempImages 删除用户未使用的临时图片
+func (s *accountDeletionService) deleteTempImages(ctx context.Context, deletion *model.AccountDeletion) (bool, error) {
+	images, err := s.tempImageRepo.GetUserTempImages(ctx, deletion.UserID)
+	if err != nil {
+		return false, fmt.Errorf("查询临时图片失败: %w", err)
+	}
+
+	for _, img := range images {
+		if err := s.cosClient.DeleteFile(ctx, img.Bucket, img.ObjectKey); err != nil {
+			return false, fmt.Errorf("删除临时图片文件失败: %w", err)
+		}
+		if err := s.tempImageRepo.DeleteTempImage(ctx, img.ID); err != nil {
+			return false, fmt.Errorf("删除临时图片记录失败: %w", err)
+		}
+		deletion.ImagesDeleted++
+	}
+
+	logger.Debug(ctx, "已删除用户临时图片", logger.Uint("deletion_id", deletion.ID), logger.Int("count", len(images)))
+
+	return true, nil
+}
+
+// scrubPII 清除短信记录和用户记录中的个人信息，并删除用户的通知设置、设备推送令牌、公告已读回执、用户标签和用户名变更记录
+// 用户名变更记录在数据保留期内保留，避免旧用户名被他人冒用
+func (s *accountDeletionService) scrubPII(ctx context.Context, deletion *model.AccountDeletion) (bool, error) {
+	if deletion.Mobile != "" {
+		affected, err := s.smsRepo.ScrubByPhoneNumber(ctx, deletion.Mobile)
+		if err != nil {
+			return false, fmt.Errorf("清除短信记录个人信息失败: %w", err)
+		}
+		deletion.SMSScrubbed += affected
+	}
+
+	if _, err := s.notifyRepo.DeleteByUser(ctx, deletion.UserID); err != nil {
+		return false, fmt.Errorf("删除通知设置失败: %w", err)
+	}
+	if _, err := s.deviceRepo.DeleteAllByUser(ctx, deletion.UserID); err != nil {
+		return false, fmt.Errorf("删除设备推送令牌失败: %w", err)
+	}
+	if _, err := s.announceRepo.DeleteReadsByUser(ctx, deletion.UserID); err != nil {
+		return false, fmt.Errorf("删除公告已读回执失败: %w", err)
+	}
+	if _, err := s.tagRepo.DeleteAllByUser(ctx, deletion.UserID); err != nil {
+		return false, fmt.Errorf("删除用户标签失败: %w", err)
+	}
+	if _, err := s.usernameRepo.DeleteAllByUser(ctx, deletion.UserID); err != nil {
+		return false, fmt.Errorf("删除用户名变更记录失败: %w", err)
+	}
+
+	if err := s.userRepo.ScrubDeleted(ctx, deletion.UserID); err != nil {
+		return false, fmt.Errorf("清除用户个人信息失败: %w", err)
+	}
+
+	return true, nil
+}
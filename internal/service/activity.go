@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// ActivityService 用户动态时间线服务接口
+// 订阅平台事件写入用户的活动记录，查询时直接读取活动记录，无需合并多张业务表
+type ActivityService interface {
+	EventPublisher
+	// GetTimeline 分页获取用户本人的活动时间线，最近的在前
+	GetTimeline(ctx context.Context, req *dto.GetActivityRequest, userID uint) (*dto.GetActivityResponse, error)
+}
+
+// activityService 用户动态时间线服务实现
+type activityService struct {
+	activityRepo repository.UserActivityRepository
+	userRepo     repository.UserRepository
+	publicIDs    PublicIDService
+}
+
+// NewActivityService 创建用户动态时间线服务实例
+func NewActivityService(
+	activityRepo repository.UserActivityRepository,
+	userRepo repository.UserRepository,
+	publicIDs PublicIDService,
+) ActivityService {
+	return &activityService{
+		activityRepo: activityRepo,
+		userRepo:     userRepo,
+		publicIDs:    publicIDs,
+	}
+}
+
+// Publish 将用户本人操作的事件写入活动记录，其他事件忽略，写入失败只记录日志
+func (s *activityService) Publish(ctx context.Context, event string, data interface{}) {
+	var activity *model.UserActivity
+	switch d := data.(type) {
+	case dto.WebhookPostData:
+		activity = &model.UserActivity{
+			UserID:    d.UserID,
+			Type:      constant.ActivityTypePost,
+			TargetID:  d.ID,
+			PostID:    d.ID,
+			Summary:   truncateRunes(d.Content, constant.ActivitySummaryLength),
+			CreatedAt: d.CreatedAt,
+		}
+	case dto.WebhookCommentData:
+		activity = &model.UserActivity{
+			UserID:    d.UserID,
+			Type:      constant.ActivityTypeComment,
+			TargetID:  d.ID,
+			PostID:    d.PostID,
+			Summary:   truncateRunes(d.Content, constant.ActivitySummaryLength),
+			CreatedAt: d.CreatedAt,
+		}
+	case dto.PostLikedData:
+		activity = &model.UserActivity{
+			UserID:    d.UserID,
+			Type:      constant.ActivityTypePostLike,
+			TargetID:  d.PostID,
+			PostID:    d.PostID,
+			CreatedAt: d.CreatedAt,
+		}
+	case dto.CommentLikedData:
+		activity = &model.UserActivity{
+			UserID:    d.UserID,
+			Type:      constant.ActivityTypeCommentLike,
+			TargetID:  d.CommentID,
+			PostID:    d.PostID,
+			CreatedAt: d.CreatedAt,
+		}
+	case dto.UserFollowedData:
+		activity = &model.UserActivity{
+			UserID:    d.UserID,
+			Type:      constant.ActivityTypeFollow,
+			TargetID:  d.TargetID,
+			CreatedAt: d.CreatedAt,
+		}
+	default:
+		return
+	}
+
+	if err := s.activityRepo.Create(ctx, activity); err != nil {
+		logger.Error(ctx, "写入用户活动记录失败", logger.String("event", event), logger.Uint("user_id", activity.UserID), logger.Err(err))
+	}
+}
+
+// GetTimeline 分页获取用户本人的活动时间线
+// 关注活动附带被关注用户的昵称和头像，用户已注销或不可见时不附带
+func (s *activityService) GetTimeline(ctx context.Context, req *dto.GetActivityRequest, userID uint) (*dto.GetActivityResponse, error) {
+	activities, count, err := s.activityRepo.GetUserActivities(ctx, userID, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取活动记录失败: %w", err)
+	}
+
+	var followedIDs, postIDs, commentIDs []uint
+	for _, activity := range activities {
+		switch activity.Type {
+		case constant.ActivityTypeFollow:
+			followedIDs = append(followedIDs, activity.TargetID)
+		case constant.ActivityTypeComment, constant.ActivityTypeCommentLike:
+			commentIDs = append(commentIDs, activity.TargetID)
+		}
+		if activity.PostID != 0 {
+			postIDs = append(postIDs, activity.PostID)
+		}
+	}
+	followed := make(map[uint]*model.User, len(followedIDs))
+	if len(followedIDs) > 0 {
+		users, err := s.userRepo.FindNormalByIDs(ctx, followedIDs)
+		if err != nil {
+			return nil, fmt.Errorf("查询用户信息失败: %w", err)
+		}
+		for i := range users {
+			followed[users[i].ID] = &users[i]
+		}
+	}
+
+	// 活动记录保存内部ID，返回前转换为公开ID
+	publicUserIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDUser, followedIDs)
+	publicPostIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDPost, postIDs)
+	publicCommentIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDComment, commentIDs)
+
+	list := make([]dto.ActivityItem, 0, len(activities))
+	for _, activity := range activities {
+		var targetID string
+		switch activity.Type {
+		case constant.ActivityTypeFollow:
+			targetID = publicUserIDs[activity.TargetID]
+		case constant.ActivityTypeComment, constant.ActivityTypeCommentLike:
+			targetID = publicCommentIDs[activity.TargetID]
+		default:
+			targetID = publicPostIDs[activity.TargetID]
+		}
+		item := dto.ActivityItem{
+			ID:        activity.ID,
+			Type:      activity.Type,
+			TargetID:  targetID,
+			PostID:    publicPostIDs[activity.PostID],
+			Summary:   activity.Summary,
+			CreatedAt: activity.CreatedAt,
+		}
+		if user, ok := followed[activity.TargetID]; ok && activity.Type == constant.ActivityTypeFollow {
+			item.TargetNickname = user.Nickname
+			item.TargetAvatar = avatarURL(user)
+		}
+		list = append(list, item)
+	}
+
+	return &dto.GetActivityResponse{
+		Total:   int(count),
+		HasMore: int64(req.Page*req.Size) < count,
+		List:    list,
+	}, nil
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// 系统公告相关错误
+var (
+	// ErrAnnouncementNotFound 公告不存在
+	ErrAnnouncementNotFound = errors.New(constant.ErrAnnouncementNotFound)
+	// ErrAnnouncementInvalidSegment 用户分群无效
+	ErrAnnouncementInvalidSegment = errors.New(constant.ErrAnnouncementInvalidSegment)
+	// ErrAnnouncementInvalidExpireAt 过期时间不晚于发布时间
+	ErrAnnouncementInvalidExpireAt = errors.New(constant.ErrAnnouncementInvalidExpireAt)
+	// ErrAnnouncementScheduledPush 定时发布的公告不支持推送
+	ErrAnnouncementScheduledPush = errors.New("定时发布的公告不支持推送通知")
+)
+
+// AnnouncementService 系统公告服务接口
+type AnnouncementService interface {
+	// CreateAnnouncement 发布公告，需要推送时在后台向目标用户发送通知
+	CreateAnnouncement(ctx context.Context, adminID uint, req *dto.CreateAnnouncementRequest) (*dto.AnnouncementInfo, error)
+	// ListAnnouncements 分页获取所有公告及已读人数
+	ListAnnouncements(ctx context.Context, page, size int) (*dto.ListAnnouncementsResponse, error)
+	// DeleteAnnouncement 删除公告，删除后不再向用户展示
+	DeleteAnnouncement(ctx context.Context, id uint) error
+	// GetUnread 获取用户未读的已发布公告
+	GetUnread(ctx context.Context, userID uint) (*dto.UnreadAnnouncementsResponse, error)
+	// MarkRead 记录用户的已读回执，不可见或不存在的公告忽略
+	MarkRead(ctx context.Context, userID uint, ids []uint) error
+}
+
+// announcementService 系统公告服务实现
+type announcementService struct {
+	announcementRepo repository.AnnouncementRepository
+	userRepo         repository.UserRepository
+	tags             TagService
+	notifier         NotificationDispatcher
+}
+
+// NewAnnouncementService 创建系统公告服务实例
+func NewAnnouncementService(
+	announcementRepo repository.AnnouncementRepository,
+	userRepo repository.UserRepository,
+	tags TagService,
+	notifier NotificationDispatcher,
+) AnnouncementService {
+	return &announcementService{
+		announcementRepo: announcementRepo,
+		userRepo:         userRepo,
+		tags:             tags,
+		notifier:         notifier,
+	}
+}
+
+// CreateAnnouncement 发布公告
+func (s *announcementService) CreateAnnouncement(ctx context.Context, adminID uint, req *dto.CreateAnnouncementRequest) (*dto.AnnouncementInfo, error) {
+	announcement := &model.Announcement{
+		Title:     req.Title,
+		Content:   req.Content,
+		Target:    req.Target,
+		Push:      req.Push,
+		CreatedBy: adminID,
+		PublishAt: time.Now(),
+		ExpireAt:  req.ExpireAt,
+	}
+	switch req.Target {
+	case constant.AnnouncementTargetSegment:
+		if !slices.Contains(constant.AnnouncementSegments, req.Segment) {
+			return nil, ErrAnnouncementInvalidSegment
+		}
+		announcement.Segment = req.Segment
+	case constant.AnnouncementTargetTag:
+		if err := s.tags.ValidateNames(ctx, []string{req.Segment}); err != nil {
+			return nil, err
+		}
+		announcement.Segment = req.Segment
+	}
+
+	scheduled := req.PublishAt != nil && req.PublishAt.After(announcement.PublishAt)
+	if scheduled {
+		announcement.PublishAt = *req.PublishAt
+	}
+	if announcement.ExpireAt != nil && !announcement.ExpireAt.After(announcement.PublishAt) {
+		return nil, ErrAnnouncementInvalidExpireAt
+	}
+	// 推送在发布公告时立即执行，定时发布的公告推送时用户还看不到公告
+	if req.Push && scheduled {
+		return nil, ErrAnnouncementScheduledPush
+	}
+
+	if err := s.announcementRepo.Create(ctx, announcement); err != nil {
+		return nil, fmt.Errorf("保存公告失败: %w", err)
+	}
+
+	logger.Info(ctx, "公告已发布",
+		logger.Uint("announcement_id", announcement.ID),
+		logger.Uint("admin_id", adminID),
+		logger.String("target", announcement.Target),
+		logger.String("segment", announcement.Segment))
+
+	if announcement.Push {
+		s.pushAsync(ctx, announcement)
+	}
+
+	info := toAnnouncementInfo(announcement, 0)
+	return &info, nil
+}
+
+// ListAnnouncements 分页获取所有公告
+func (s *announcementService) ListAnnouncements(ctx context.Context, page, size int) (*dto.ListAnnouncementsResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	announcements, total, err := s.announcementRepo.List(ctx, page, size)
+	if err != nil {
+		return nil, fmt.Errorf("查询公告列表失败: %w", err)
+	}
+
+	ids := make([]uint, len(announcements))
+	for i := range announcements {
+		ids[i] = announcements[i].ID
+	}
+	readCounts, err := s.announcementRepo.CountReads(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("统计公告已读人数失败: %w", err)
+	}
+
+	list := make([]dto.AnnouncementInfo, 0, len(announcements))
+	for i := range announcements {
+		list = append(list, toAnnouncementInfo(&announcements[i], readCounts[announcements[i].ID]))
+	}
+
+	return &dto.ListAnnouncementsResponse{
+		Total:    total,
+		List:     list,
+		Segments: constant.AnnouncementSegments,
+	}, nil
+}
+
+// DeleteAnnouncement 删除公告
+func (s *announcementService) DeleteAnnouncement(ctx context.Context, id uint) error {
+	if _, err := s.announcementRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrAnnouncementNotFound
+		}
+		return fmt.Errorf("查询公告失败: %w", err)
+	}
+
+	if err := s.announcementRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("删除公告失败: %w", err)
+	}
+
+	logger.Info(ctx, "公告已删除", logger.Uint("announcement_id", id))
+	return nil
+}
+
+// GetUnread 获取用户未读的已发布公告，最新发布的在前
+func (s *announcementService) GetUnread(ctx context.Context, userID uint) (*dto.UnreadAnnouncementsResponse, error) {
+	visible, err := s.visibleAnnouncements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(visible))
+	for i := range visible {
+		ids[i] = visible[i].ID
+	}
+	read, err := s.announcementRepo.GetReadIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询公告已读回执失败: %w", err)
+	}
+
+	list := make([]dto.UserAnnouncement, 0, len(visible))
+	for _, announcement := range visible {
+		if read[announcement.ID] {
+			continue
+		}
+		list = append(list, dto.UserAnnouncement{
+			ID:        announcement.ID,
+			Title:     announcement.Title,
+			Content:   announcement.Content,
+			PublishAt: announcement.PublishAt,
+		})
+	}
+
+	return &dto.UnreadAnnouncementsResponse{List: list}, nil
+}
+
+// MarkRead 记录用户的已读回执
+func (s *announcementService) MarkRead(ctx context.Context, userID uint, ids []uint) error {
+	visible, err := s.visibleAnnouncements(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	readIDs := make([]uint, 0, len(ids))
+	for _, announcement := range visible {
+		if slices.Contains(ids, announcement.ID) {
+			readIDs = append(readIDs, announcement.ID)
+		}
+	}
+
+	if _, err := s.announcementRepo.MarkRead(ctx, userID, readIDs); err != nil {
+		return fmt.Errorf("记录公告已读回执失败: %w", err)
+	}
+	return nil
+}
+
+// visibleAnnouncements 获取对用户可见的已发布公告
+func (s *announcementService) visibleAnnouncements(ctx context.Context, userID uint) ([]model.Announcement, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	announcements, err := s.announcementRepo.FindPublished(ctx, time.Now(), constant.AnnouncementMaxActive)
+	if err != nil {
+		return nil, fmt.Errorf("查询公告失败: %w", err)
+	}
+
+	// 只在有按标签发布的公告时查询一次用户标签
+	var userTags []string
+	if slices.ContainsFunc(announcements, func(a model.Announcement) bool { return a.Target == constant.AnnouncementTargetTag }) {
+		userTags = s.tags.UserTagNames(ctx, userID)
+	}
+
+	visible := announcements[:0]
+	for _, announcement := range announcements {
+		if announcementTargets(&announcement, user, userTags) {
+			visible = append(visible, announcement)
+		}
+	}
+	return visible, nil
+}
+
+// announcementTargets 判断用户是否在公告的目标范围内，userTags为用户拥有的标签名称
+// 与AnnouncementRepository.FindTargetUserIDs的分群条件保持一致
+func announcementTargets(announcement *model.Announcement, user *model.User, userTags []string) bool {
+	switch announcement.Target {
+	case constant.AnnouncementTargetTag:
+		return slices.Contains(userTags, announcement.Segment)
+	case constant.AnnouncementTargetSegment:
+		return inAnnouncementSegment(announcement, user)
+	default:
+		return true
+	}
+}
+
+// inAnnouncementSegment 判断用户是否属于公告的用户分群
+func inAnnouncementSegment(announcement *model.Announcement, user *model.User) bool {
+	switch announcement.Segment {
+	case constant.AnnouncementSegmentNewUsers:
+		return !user.CreatedAt.Before(announcement.PublishAt.Add(-constant.AnnouncementNewUserWindow))
+	case constant.AnnouncementSegmentExistingUsers:
+		return user.CreatedAt.Before(announcement.PublishAt)
+	case constant.AnnouncementSegmentPrivateAccounts:
+		return user.IsPrivate
+	default:
+		return false
+	}
+}
+
+// pushAsync 在后台按用户的通知设置向公告目标用户发送推送，不影响当前请求
+func (s *announcementService) pushAsync(ctx context.Context, announcement *model.Announcement) {
+	msg := &NotificationMessage{
+		Title:   announcement.Title,
+		Content: truncateRunes(announcement.Content, constant.AnnouncementPushSummaryLength),
+		Data: map[string]string{
+			"type":            constant.NotificationEventAnnouncement,
+			"announcement_id": strconv.FormatUint(uint64(announcement.ID), 10),
+		},
+	}
+
+	// 后台协程使用脱离请求生命周期的上下文，保留请求ID便于关联日志
+	ctx = logger.Detach(ctx)
+	go func() {
+		var afterID uint
+		sent := 0
+		for {
+			ids, err := s.announcementRepo.FindTargetUserIDs(ctx, announcement, afterID, constant.AnnouncementPushBatchSize)
+			if err != nil {
+				logger.Error(ctx, "查询公告推送目标用户失败", logger.Uint("announcement_id", announcement.ID), logger.Err(err))
+				return
+			}
+			for _, userID := range ids {
+				dispatchCtx, cancel := context.WithTimeout(ctx, constant.NotificationDispatchTimeout)
+				s.notifier.Dispatch(dispatchCtx, userID, constant.NotificationEventAnnouncement, msg)
+				cancel()
+			}
+			sent += len(ids)
+			if len(ids) < constant.AnnouncementPushBatchSize {
+				break
+			}
+			afterID = ids[len(ids)-1]
+		}
+		logger.Info(ctx, "公告推送完成", logger.Uint("announcement_id", announcement.ID), logger.Int("users", sent))
+	}()
+}
+
+// toAnnouncementInfo 转换为管理后台的公告信息
+func toAnnouncementInfo(announcement *model.Announcement, readCount int64) dto.AnnouncementInfo {
+	return dto.AnnouncementInfo{
+		ID:        announcement.ID,
+		Title:     announcement.Title,
+		Content:   announcement.Content,
+		Target:    announcement.Target,
+		Segment:   announcement.Segment,
+		Push:      announcement.Push,
+		CreatedBy: announcement.CreatedBy,
+		PublishAt: announcement.PublishAt,
+		ExpireAt:  announcement.ExpireAt,
+		ReadCount: readCount,
+		CreatedAt: announcement.CreatedAt,
+	}
+}
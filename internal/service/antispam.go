@@ -0,0 +1,108 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/pkg/redis"
+)
+
+// 反垃圾相关错误
+var (
+	// ErrRateLimited 操作过于频繁
+	ErrRateLimited = errors.New(constant.ErrRateLimited)
+	// ErrDuplicateContent 重复发布相同内容
+	ErrDuplicateContent = errors.New(constant.ErrDuplicateContent)
+)
+
+// checkPostRate 检查用户发布动态的频率
+func checkPostRate(userID uint) error {
+	cfg := config.GetAntiSpamConfig()
+	return checkRate(cachekey.AntiSpamCount("post", userID), cfg.PostLimit, parseWindow(cfg.PostWindow, constant.AntiSpamDefaultPostWindow))
+}
+
+// checkCommentRate 检查用户发布评论的频率
+func checkCommentRate(userID uint) error {
+	cfg := config.GetAntiSpamConfig()
+	return checkRate(cachekey.AntiSpamCount("comment", userID), cfg.CommentLimit, parseWindow(cfg.CommentWindow, constant.AntiSpamDefaultCommentWindow))
+}
+
+// checkSearchRate 检查用户搜索其他用户的频率，防止批量枚举手机号
+func checkSearchRate(userID uint) error {
+	cfg := config.GetAntiSpamConfig()
+	return checkRate(cachekey.AntiSpamCount("search", userID), cfg.SearchLimit, parseWindow(cfg.SearchWindow, constant.AntiSpamDefaultSearchWindow))
+}
+
+// checkTranslateRate 检查用户翻译动态和评论的频率，控制翻译服务的调用费用
+func checkTranslateRate(userID uint) error {
+	cfg := config.GetAntiSpamConfig()
+	return checkRate(cachekey.AntiSpamCount("translate", userID), cfg.TranslateLimit, parseWindow(cfg.TranslateWindow, constant.AntiSpamDefaultTranslateWindow))
+}
+
+// incrRateScript 累加频率计数，计数没有过期时间时设置窗口过期时间
+// 计数与设置过期时间在同一脚本中执行，不会因设置过期时间失败而留下永久计数导致用户一直被限流
+// KEYS[1]为计数键；ARGV[1]为窗口时长（毫秒）
+const incrRateScript = `
+local count = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`
+
+// checkRate 使用固定窗口计数器检查操作频率，limit不大于0时不限制
+func checkRate(key cachekey.Key, limit int, window time.Duration) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	result, err := redis.Eval(incrRateScript, []string{key.String()}, window.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("检查操作频率失败: %w", err)
+	}
+	count, _ := result.(int64)
+
+	if count > int64(limit) {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// checkDuplicatePost 检查动态内容是否与用户上一条动态相同
+func checkDuplicatePost(userID uint, content string) error {
+	last, err := redis.Get(cachekey.AntiSpamLastPost(userID).String())
+	if err != nil {
+		return nil
+	}
+	if last == contentDigest(content) {
+		return ErrDuplicateContent
+	}
+	return nil
+}
+
+// rememberLastPost 记录用户最近一条动态的内容摘要
+func rememberLastPost(userID uint, content string) {
+	key := cachekey.AntiSpamLastPost(userID)
+	_ = redis.Set(key.String(), contentDigest(content), key.TTL())
+}
+
+// contentDigest 计算内容摘要
+func contentDigest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseWindow 解析限流时间窗口，未配置或配置错误时使用默认值
+func parseWindow(value string, fallback time.Duration) time.Duration {
+	window, err := time.ParseDuration(value)
+	if err != nil || window <= 0 {
+		return fallback
+	}
+	return window
+}
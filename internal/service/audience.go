@@ -0,0 +1,268 @@
+package service
+
+import (
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 好友列表相关错误
+var (
+	ErrAudienceListNotFound    = errors.New("好友列表不存在")
+	ErrAudienceListLimit       = errors.New("好友列表数量已达上限")
+	ErrAudienceListNameEmpty   = errors.New("好友列表名称不能为空")
+	ErrAudienceMemberLimit     = errors.New("好友列表成员数量已达上限")
+	ErrAudienceMemberNotFriend = errors.New("只能添加已确认的好友")
+)
+
+// AudienceService 好友列表服务接口
+type AudienceService interface {
+	// CreateList 创建好友列表，可同时添加初始成员
+	CreateList(ctx context.Context, req *dto.CreateAudienceListRequest, userID uint) (*dto.AudienceListInfo, error)
+	// GetLists 获取用户创建的所有好友列表
+	GetLists(ctx context.Context, userID uint) (*dto.GetAudienceListsResponse, error)
+	// UpdateList 修改好友列表名称
+	UpdateList(ctx context.Context, req *dto.UpdateAudienceListRequest, userID uint) error
+	// DeleteList 删除好友列表，仅该列表可见的动态此后只对作者自己可见
+	DeleteList(ctx context.Context, req *dto.DeleteAudienceListRequest, userID uint) error
+	// AddMembers 添加列表成员，成员只能是已确认的好友
+	AddMembers(ctx context.Context, req *dto.AudienceListMembersRequest, userID uint) error
+	// RemoveMembers 移除列表成员
+	RemoveMembers(ctx context.Context, req *dto.AudienceListMembersRequest, userID uint) error
+	// GetMembers 获取列表成员
+	GetMembers(ctx context.Context, req *dto.GetAudienceListMembersRequest, userID uint) (*dto.GetAudienceListMembersResponse, error)
+}
+
+// audienceService 好友列表服务实现
+type audienceService struct {
+	audienceRepo repository.AudienceListRepository
+	friendRepo   repository.UserFriendRepository
+	userRepo     repository.UserRepository
+}
+
+// NewAudienceService 创建好友列表服务实例
+func NewAudienceService(
+	audienceRepo repository.AudienceListRepository,
+	friendRepo repository.UserFriendRepository,
+	userRepo repository.UserRepository,
+) AudienceService {
+	return &audienceService{
+		audienceRepo: audienceRepo,
+		friendRepo:   friendRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// CreateList 创建好友列表，可同时添加初始成员
+func (s *audienceService) CreateList(ctx context.Context, req *dto.CreateAudienceListRequest, userID uint) (*dto.AudienceListInfo, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrAudienceListNameEmpty
+	}
+
+	count, err := s.audienceRepo.CountUserLists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("统计好友列表失败: %w", err)
+	}
+	if count >= constant.AudienceListMaxLists {
+		return nil, ErrAudienceListLimit
+	}
+
+	memberIDs := uniqueIDs(req.MemberIDs)
+	if len(memberIDs) > constant.AudienceListMaxMembers {
+		return nil, ErrAudienceMemberLimit
+	}
+	if err := s.checkFriends(ctx, userID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	list := &model.AudienceList{UserID: userID, Name: name}
+	if err := s.audienceRepo.CreateList(ctx, list); err != nil {
+		return nil, fmt.Errorf("创建好友列表失败: %w", err)
+	}
+	added, err := s.audienceRepo.AddMembers(ctx, list.ID, memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("添加列表成员失败: %w", err)
+	}
+
+	return &dto.AudienceListInfo{
+		ID:          list.ID,
+		Name:        list.Name,
+		MemberCount: added,
+		CreatedAt:   list.CreatedAt,
+	}, nil
+}
+
+// GetLists 获取用户创建的所有好友列表
+func (s *audienceService) GetLists(ctx context.Context, userID uint) (*dto.GetAudienceListsResponse, error) {
+	lists, err := s.audienceRepo.GetUserLists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取好友列表失败: %w", err)
+	}
+
+	listIDs := make([]uint, len(lists))
+	for i, list := range lists {
+		listIDs[i] = list.ID
+	}
+	counts, err := s.audienceRepo.CountMembers(ctx, listIDs)
+	if err != nil {
+		return nil, fmt.Errorf("统计列表成员失败: %w", err)
+	}
+
+	infos := make([]dto.AudienceListInfo, 0, len(lists))
+	for _, list := range lists {
+		infos = append(infos, dto.AudienceListInfo{
+			ID:          list.ID,
+			Name:        list.Name,
+			MemberCount: counts[list.ID],
+			CreatedAt:   list.CreatedAt,
+		})
+	}
+
+	return &dto.GetAudienceListsResponse{List: infos}, nil
+}
+
+// UpdateList 修改好友列表名称
+func (s *audienceService) UpdateList(ctx context.Context, req *dto.UpdateAudienceListRequest, userID uint) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return ErrAudienceListNameEmpty
+	}
+	if _, err := s.getOwnedList(ctx, req.ListID, userID); err != nil {
+		return err
+	}
+
+	if err := s.audienceRepo.UpdateListName(ctx, req.ListID, name); err != nil {
+		return fmt.Errorf("修改好友列表失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteList 删除好友列表
+func (s *audienceService) DeleteList(ctx context.Context, req *dto.DeleteAudienceListRequest, userID uint) error {
+	if _, err := s.getOwnedList(ctx, req.ListID, userID); err != nil {
+		return err
+	}
+
+	if err := s.audienceRepo.DeleteList(ctx, req.ListID); err != nil {
+		return fmt.Errorf("删除好友列表失败: %w", err)
+	}
+	return nil
+}
+
+// AddMembers 添加列表成员，已在列表中的成员忽略
+func (s *audienceService) AddMembers(ctx context.Context, req *dto.AudienceListMembersRequest, userID uint) error {
+	if _, err := s.getOwnedList(ctx, req.ListID, userID); err != nil {
+		return err
+	}
+
+	memberIDs := uniqueIDs(req.MemberIDs)
+	counts, err := s.audienceRepo.CountMembers(ctx, []uint{req.ListID})
+	if err != nil {
+		return fmt.Errorf("统计列表成员失败: %w", err)
+	}
+	// 按最坏情况估算，已在列表中的成员也计入
+	if counts[req.ListID]+int64(len(memberIDs)) > constant.AudienceListMaxMembers {
+		return ErrAudienceMemberLimit
+	}
+	if err := s.checkFriends(ctx, userID, memberIDs); err != nil {
+		return err
+	}
+
+	if _, err := s.audienceRepo.AddMembers(ctx, req.ListID, memberIDs); err != nil {
+		return fmt.Errorf("添加列表成员失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveMembers 移除列表成员
+func (s *audienceService) RemoveMembers(ctx context.Context, req *dto.AudienceListMembersRequest, userID uint) error {
+	if _, err := s.getOwnedList(ctx, req.ListID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.audienceRepo.RemoveMembers(ctx, req.ListID, uniqueIDs(req.MemberIDs)); err != nil {
+		return fmt.Errorf("移除列表成员失败: %w", err)
+	}
+	return nil
+}
+
+// GetMembers 获取列表成员
+func (s *audienceService) GetMembers(ctx context.Context, req *dto.GetAudienceListMembersRequest, userID uint) (*dto.GetAudienceListMembersResponse, error) {
+	if _, err := s.getOwnedList(ctx, req.ListID, userID); err != nil {
+		return nil, err
+	}
+
+	members, total, err := s.audienceRepo.GetMembers(ctx, req.ListID, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取列表成员失败: %w", err)
+	}
+
+	list := make([]dto.UserBrief, 0, len(members))
+	for _, member := range members {
+		user, err := s.userRepo.FindByID(ctx, member.MemberID)
+		if err != nil {
+			continue // 跳过获取失败的用户
+		}
+		list = append(list, dto.UserBrief{
+			ID:       user.PublicID,
+			Nickname: user.Nickname,
+			Avatar:   avatarURL(user),
+		})
+	}
+
+	return &dto.GetAudienceListMembersResponse{
+		Total: int(total),
+		List:  list,
+	}, nil
+}
+
+// getOwnedList 获取用户自己的好友列表，列表不存在或属于其他用户时统一返回ErrAudienceListNotFound
+func (s *audienceService) getOwnedList(ctx context.Context, listID, userID uint) (*model.AudienceList, error) {
+	list, err := s.audienceRepo.GetList(ctx, listID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAudienceListNotFound
+		}
+		return nil, fmt.Errorf("查询好友列表失败: %w", err)
+	}
+	if list.UserID != userID {
+		return nil, ErrAudienceListNotFound
+	}
+	return list, nil
+}
+
+// checkFriends 检查成员是否都是用户已确认的好友
+func (s *audienceService) checkFriends(ctx context.Context, userID uint, memberIDs []uint) error {
+	statuses, err := s.friendRepo.GetFriendStatuses(ctx, userID, memberIDs)
+	if err != nil {
+		return fmt.Errorf("查询好友关系失败: %w", err)
+	}
+	for _, id := range memberIDs {
+		if status, ok := statuses[id]; !ok || status != int(constant.FriendStatusConfirmed) {
+			return ErrAudienceMemberNotFriend
+		}
+	}
+	return nil
+}
+
+// uniqueIDs 去除ID列表中的重复项和0，保持原有顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"app/config"
+	"app/internal/dto"
+)
+
+// ConfigService 运行配置服务接口
+type ConfigService interface {
+	// GetEffectiveConfig 获取本节点合并后的有效配置，敏感配置项已隐藏
+	GetEffectiveConfig(ctx context.Context) *dto.EffectiveConfigResponse
+}
+
+// configService 运行配置服务实现
+type configService struct{}
+
+// NewConfigService 创建运行配置服务实例
+func NewConfigService() ConfigService {
+	return &configService{}
+}
+
+// GetEffectiveConfig 获取本节点合并后的有效配置
+func (s *configService) GetEffectiveConfig(ctx context.Context) *dto.EffectiveConfigResponse {
+	return &dto.EffectiveConfigResponse{
+		Env:    config.Env(),
+		Files:  config.LoadedFiles(),
+		Config: config.Effective(),
+	}
+}
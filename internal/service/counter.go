@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal/constant"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// CounterReconcileResult 一种计数的校准结果
+type CounterReconcileResult struct {
+	Counter  string // 计数名称
+	Scanned  int    // 扫描的记录数
+	Drifted  int    // 计数不一致并已修正的记录数
+	DriftSum int64  // 偏差绝对值之和
+	DriftMax int64  // 单条记录的最大偏差绝对值
+}
+
+// CounterService 计数校准服务接口
+type CounterService interface {
+	// Reconcile 按来源表分批重新统计冗余计数并修正不一致的记录，出错时返回已完成的校准结果
+	Reconcile(ctx context.Context) ([]CounterReconcileResult, error)
+}
+
+// counterService 计数校准服务实现
+type counterService struct {
+	counterRepo repository.CounterRepository
+	counters    PostCounterService
+}
+
+// NewCounterService 创建计数校准服务实例
+func NewCounterService(counterRepo repository.CounterRepository, counters PostCounterService) CounterService {
+	return &counterService{counterRepo: counterRepo, counters: counters}
+}
+
+// Reconcile 依次校准所有登记的计数
+func (s *counterService) Reconcile(ctx context.Context) ([]CounterReconcileResult, error) {
+	results := make([]CounterReconcileResult, 0, len(repository.Counters))
+	for _, counter := range repository.Counters {
+		result, err := s.reconcile(ctx, counter)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("校准计数%s失败: %w", counter.Name, err)
+		}
+
+		if result.Drifted > 0 {
+			logger.Warn(ctx, "已修正不一致的计数",
+				logger.String("counter", counter.Name),
+				logger.Int("scanned", result.Scanned),
+				logger.Int("drifted", result.Drifted),
+				logger.Int64("drift_sum", result.DriftSum),
+				logger.Int64("drift_max", result.DriftMax))
+		} else {
+			logger.Info(ctx, "计数校准完成，未发现不一致",
+				logger.String("counter", counter.Name),
+				logger.Int("scanned", result.Scanned))
+		}
+	}
+	return results, nil
+}
+
+// reconcile 按ID顺序分批校准一种计数
+func (s *counterService) reconcile(ctx context.Context, counter repository.Counter) (CounterReconcileResult, error) {
+	result := CounterReconcileResult{Counter: counter.Name}
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		lastID, scanned, drifts, err := s.counterRepo.FindDrifts(ctx, counter, afterID, constant.CounterReconcileBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("统计计数失败: %w", err)
+		}
+		result.Scanned += scanned
+
+		drifts, err = s.excludePending(ctx, counter, drifts)
+		if err != nil {
+			return result, err
+		}
+		if len(drifts) > 0 {
+			ids := make([]uint, 0, len(drifts))
+			for _, drift := range drifts {
+				ids = append(ids, drift.ID)
+				diff := drift.Stored - drift.Actual
+				if diff < 0 {
+					diff = -diff
+				}
+				result.DriftSum += diff
+				if diff > result.DriftMax {
+					result.DriftMax = diff
+				}
+			}
+			if err := s.counterRepo.FixCounts(ctx, counter, ids); err != nil {
+				return result, fmt.Errorf("修正计数失败: %w", err)
+			}
+			result.Drifted += len(drifts)
+		}
+
+		if scanned < constant.CounterReconcileBatchSize {
+			return result, nil
+		}
+		afterID = lastID
+	}
+}
+
+// excludePending 排除计数先在Redis中累加且尚未同步到数据库的记录，留到下次校准
+// 这些记录的数据库计数暂时偏小，此时按来源表修正会在增量同步后导致重复计数
+func (s *counterService) excludePending(ctx context.Context, counter repository.Counter, drifts []repository.CounterDrift) ([]repository.CounterDrift, error) {
+	if counter.WriteBehind == "" || len(drifts) == 0 {
+		return drifts, nil
+	}
+
+	ids := make([]uint, len(drifts))
+	for i, drift := range drifts {
+		ids[i] = drift.ID
+	}
+	pending, err := s.counters.PendingBatch(ctx, counter.WriteBehind, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := drifts[:0]
+	for _, drift := range drifts {
+		if _, ok := pending[drift.ID]; !ok {
+			remaining = append(remaining, drift)
+		}
+	}
+	return remaining, nil
+}
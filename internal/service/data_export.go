@@ -0,0 +1,428 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/cos"
+	"app/pkg/logger"
+)
+
+// 数据导出相关错误
+var (
+	// ErrExportNotFound 导出任务不存在
+	ErrExportNotFound = errors.New("导出任务不存在")
+)
+
+// DataExportService 用户数据导出服务接口
+type DataExportService interface {
+	// RequestExport 发起数据导出，已有未完成任务时直接返回该任务
+	RequestExport(ctx context.Context, userID uint) (*dto.DataExportResponse, error)
+	// GetExport 查询导出任务状态
+	GetExport(ctx context.Context, userID, exportID uint) (*dto.DataExportResponse, error)
+	// ProcessPendingExports 处理等待中的导出任务，由定时任务调用
+	ProcessPendingExports(ctx context.Context) error
+}
+
+// dataExportService 用户数据导出服务实现
+type dataExportService struct {
+	exportRepo    repository.DataExportRepository
+	userRepo      repository.UserRepository
+	postRepo      repository.PostRepository
+	commentRepo   repository.PostCommentRepository
+	postImageRepo repository.PostImageRepository
+	followerRepo  repository.UserFollowerRepository
+	friendRepo    repository.UserFriendRepository
+	notifier      NotificationDispatcher
+	cosClient     *cos.StorageClient
+}
+
+// NewDataExportService 创建用户数据导出服务实例
+func NewDataExportService(
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	postRepo repository.PostRepository,
+	commentRepo repository.PostCommentRepository,
+	postImageRepo repository.PostImageRepository,
+	followerRepo repository.UserFollowerRepository,
+	friendRepo repository.UserFriendRepository,
+	notifier NotificationDispatcher,
+) (DataExportService, error) {
+	// 获取COS客户端
+	cosClient, err := cos.GetStorageClient()
+	if err != nil {
+		return nil, fmt.Errorf("获取COS客户端失败: %w", err)
+	}
+
+	return &dataExportService{
+		exportRepo:    exportRepo,
+		userRepo:      userRepo,
+		postRepo:      postRepo,
+		commentRepo:   commentRepo,
+		postImageRepo: postImageRepo,
+		followerRepo:  followerRepo,
+		friendRepo:    friendRepo,
+		notifier:      notifier,
+		cosClient:     cosClient,
+	}, nil
+}
+
+// exportArchive 导出文件的数据结构
+type exportArchive struct {
+	ExportedAt time.Time       `json:"exported_at"`
+	Profile    exportProfile   `json:"profile"`
+	Posts      []exportPost    `json:"posts"`
+	Comments   []exportComment `json:"comments"`
+	Followers  []uint          `json:"followers"`
+	Following  []uint          `json:"following"`
+	Friends    []exportFriend  `json:"friends"`
+}
+
+// exportProfile 导出的用户资料
+type exportProfile struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Mobile    string    `json:"mobile"`
+	Nickname  string    `json:"nickname"`
+	Avatar    string    `json:"avatar"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// exportPost 导出的动态
+type exportPost struct {
+	ID         uint      `json:"id"`
+	Content    string    `json:"content"`
+	Visibility int       `json:"visibility"`
+	Images     []string  `json:"images"`
+	Likes      int       `json:"likes"`
+	Comments   int       `json:"comments"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// exportComment 导出的评论
+type exportComment struct {
+	ID        uint      `json:"id"`
+	PostID    uint      `json:"post_id"`
+	ParentID  *uint     `json:"parent_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// exportFriend 导出的好友关系
+type exportFriend struct {
+	UserID    uint      `json:"user_id"`
+	Status    int       `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RequestExport 发起数据导出，已有未完成任务时直接返回该任务
+func (s *dataExportService) RequestExport(ctx context.Context, userID uint) (*dto.DataExportResponse, error) {
+	// 存在未完成的任务时不重复创建
+	existing, err := s.exportRepo.FindActiveByUserID(ctx, userID)
+	if err == nil {
+		logger.Info(ctx, "存在未完成的数据导出任务", logger.Uint("export_id", existing.ID))
+		return s.buildExportResponse(ctx, existing), nil
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询导出任务失败: %w", err)
+	}
+
+	export := &model.DataExport{
+		UserID: userID,
+		Status: constant.ExportStatusPending,
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("创建导出任务失败: %w", err)
+	}
+
+	logger.Info(ctx, "数据导出任务已创建", logger.Uint("export_id", export.ID))
+
+	return s.buildExportResponse(ctx, export), nil
+}
+
+// GetExport 查询导出任务状态
+func (s *dataExportService) GetExport(ctx context.Context, userID, exportID uint) (*dto.DataExportResponse, error) {
+	export, err := s.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("查询导出任务失败: %w", err)
+	}
+
+	// 只能查询自己的导出任务，对外表现为不存在
+	if export.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+
+	return s.buildExportResponse(ctx, export), nil
+}
+
+// ProcessPendingExports 处理等待中的导出任务，由定时任务调用
+// 处理前先回收处理超时的任务，使用户可以重新发起导出
+func (s *dataExportService) ProcessPendingExports(ctx context.Context) error {
+	reclaimed, err := s.exportRepo.ReclaimStaleExports(ctx, time.Now().Add(-constant.ExportProcessingTimeout))
+	if err != nil {
+		logger.Error(ctx, "回收处理超时的导出任务失败", logger.Err(err))
+	} else if reclaimed > 0 {
+		logger.Warn(ctx, "已回收处理超时的导出任务", logger.Int64("count", reclaimed))
+	}
+
+	exports, err := s.exportRepo.GetPendingExports(ctx, constant.ExportBatchSize)
+	if err != nil {
+		return fmt.Errorf("获取待处理导出任务失败: %w", err)
+	}
+
+	for i := range exports {
+		export := &exports[i]
+
+		// 抢占任务，避免重复处理
+		claimed, err := s.exportRepo.ClaimExport(ctx, export.ID)
+		if err != nil {
+			logger.Error(ctx, "抢占导出任务失败", logger.Uint("export_id", export.ID), logger.Err(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		export.Status = constant.ExportStatusProcessing
+
+		if err := s.processExport(ctx, export); err != nil {
+			logger.Error(ctx, "处理数据导出任务失败", logger.Uint("export_id", export.ID), logger.Err(err))
+			export.Status = constant.ExportStatusFailed
+			export.ErrorMessage = err.Error()
+			// 任务超时或停止时上下文已取消，使用独立的上下文写回失败状态
+			updateCtx, cancel := context.WithTimeout(logger.Detach(ctx), constant.ExportStatusUpdateTimeout)
+			if updateErr := s.exportRepo.Update(updateCtx, export); updateErr != nil {
+				logger.Error(ctx, "更新导出任务状态失败", logger.Uint("export_id", export.ID), logger.Err(updateErr))
+			}
+			cancel()
+			continue
+		}
+
+		logger.Info(ctx, "数据导出任务处理完成", logger.Uint("export_id", export.ID), logger.Int64("file_size", export.FileSize))
+	}
+
+	return nil
+}
+
+// processExport 收集用户数据、打包上传并通知用户
+func (s *dataExportService) processExport(ctx context.Context, export *model.DataExport) error {
+	archive, err := s.collectUserData(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	data, err := buildExportZip(archive)
+	if err != nil {
+		return fmt.Errorf("打包导出文件失败: %w", err)
+	}
+
+	// 上传到COS（使用默认存储桶）
+	objectKey := fmt.Sprintf("%s%d/%d_%d.zip", constant.ExportObjectKeyPrefix, export.UserID, export.ID, time.Now().Unix())
+	if _, err := s.cosClient.UploadFile(ctx, "", objectKey, bytes.NewReader(data), "application/zip"); err != nil {
+		return fmt.Errorf("上传导出文件失败: %w", err)
+	}
+
+	now := time.Now()
+	export.Status = constant.ExportStatusCompleted
+	export.ObjectKey = objectKey
+	export.FileSize = int64(len(data))
+	export.CompletedAt = &now
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return fmt.Errorf("更新导出任务失败: %w", err)
+	}
+
+	s.notifyUser(ctx, export.UserID)
+
+	return nil
+}
+
+// collectUserData 收集用户的资料、动态、评论和关系数据
+func (s *dataExportService) collectUserData(ctx context.Context, userID uint) (*exportArchive, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	archive := &exportArchive{
+		ExportedAt: time.Now(),
+		Profile: exportProfile{
+			ID:        user.ID,
+			Username:  user.Username,
+			Mobile:    user.Mobile,
+			Nickname:  user.Nickname,
+			Avatar:    user.Avatar,
+			CreatedAt: user.CreatedAt,
+		},
+		Posts:     []exportPost{},
+		Comments:  []exportComment{},
+		Followers: []uint{},
+		Following: []uint{},
+		Friends:   []exportFriend{},
+	}
+
+	// 动态及其图片
+	for page := 1; ; page++ {
+		posts, total, err := s.postRepo.GetUserPosts(ctx, userID, page, constant.ExportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("查询用户动态失败: %w", err)
+		}
+		for _, post := range posts {
+			images := []string{}
+			postImages, err := s.postImageRepo.GetPostImages(ctx, post.ID)
+			if err != nil {
+				return nil, fmt.Errorf("查询动态图片失败: %w", err)
+			}
+			for i := range postImages {
+				img := &postImages[i]
+				images = append(images, s.cosClient.RefreshURL(ctx, img.Bucket, img.ObjectKey, postImageURL(img)))
+			}
+			archive.Posts = append(archive.Posts, exportPost{
+				ID:         post.ID,
+				Content:    post.Content,
+				Visibility: post.Visibility,
+				Images:     images,
+				Likes:      post.Likes,
+				Comments:   post.Comments,
+				CreatedAt:  post.CreatedAt,
+			})
+		}
+		if len(posts) == 0 || int64(page*constant.ExportPageSize) >= total {
+			break
+		}
+	}
+
+	// 评论
+	for page := 1; ; page++ {
+		comments, total, err := s.commentRepo.GetUserComments(ctx, userID, page, constant.ExportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("查询用户评论失败: %w", err)
+		}
+		for _, comment := range comments {
+			archive.Comments = append(archive.Comments, exportComment{
+				ID:        comment.ID,
+				PostID:    comment.PostID,
+				ParentID:  comment.ParentID,
+				Content:   comment.Content,
+				CreatedAt: comment.CreatedAt,
+			})
+		}
+		if len(comments) == 0 || int64(page*constant.ExportPageSize) >= total {
+			break
+		}
+	}
+
+	// 粉丝
+	for page := 1; ; page++ {
+		followers, total, err := s.followerRepo.GetFollowers(ctx, userID, page, constant.ExportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("查询粉丝列表失败: %w", err)
+		}
+		for _, follower := range followers {
+			archive.Followers = append(archive.Followers, follower.UserID)
+		}
+		if len(followers) == 0 || int64(page*constant.ExportPageSize) >= total {
+			break
+		}
+	}
+
+	// 关注
+	for page := 1; ; page++ {
+		followings, total, err := s.followerRepo.GetFollowing(ctx, userID, page, constant.ExportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("查询关注列表失败: %w", err)
+		}
+		for _, following := range followings {
+			archive.Following = append(archive.Following, following.TargetID)
+		}
+		if len(followings) == 0 || int64(page*constant.ExportPageSize) >= total {
+			break
+		}
+	}
+
+	// 好友
+	for page := 1; ; page++ {
+		friends, total, err := s.friendRepo.GetFriends(ctx, userID, "", page, constant.ExportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("查询好友列表失败: %w", err)
+		}
+		for _, friend := range friends {
+			archive.Friends = append(archive.Friends, exportFriend{
+				UserID:    friend.TargetID,
+				Status:    friend.Status,
+				CreatedAt: friend.CreatedAt,
+			})
+		}
+		if len(friends) == 0 || int64(page*constant.ExportPageSize) >= total {
+			break
+		}
+	}
+
+	return archive, nil
+}
+
+// notifyUser 按用户的通知设置通知导出已完成，未配置短信模板时不发送短信
+func (s *dataExportService) notifyUser(ctx context.Context, userID uint) {
+	s.notifier.Dispatch(ctx, userID, constant.NotificationEventDataExport, &NotificationMessage{
+		Title:           "数据导出完成",
+		Content:         "您的个人数据导出已完成，请登录应用下载，下载链接24小时内有效。",
+		SMSTemplateCode: config.GetSMSConfig().Aliyun.Templates[constant.ExportSMSTemplateKey],
+	})
+}
+
+// buildExportResponse 构建导出任务响应，已完成的任务附带新的预签名下载链接
+func (s *dataExportService) buildExportResponse(ctx context.Context, export *model.DataExport) *dto.DataExportResponse {
+	resp := &dto.DataExportResponse{
+		ID:           export.ID,
+		Status:       export.Status,
+		FileSize:     export.FileSize,
+		ErrorMessage: export.ErrorMessage,
+		CreatedAt:    export.CreatedAt,
+		CompletedAt:  export.CompletedAt,
+	}
+
+	if export.Status == constant.ExportStatusCompleted && export.ObjectKey != "" {
+		url, err := s.cosClient.GetFileURL(ctx, export.Bucket, export.ObjectKey, constant.ExportDownloadExpiration)
+		if err == nil {
+			expiresAt := time.Now().Add(constant.ExportDownloadExpiration)
+			resp.DownloadURL = url
+			resp.ExpiresAt = &expiresAt
+		}
+	}
+
+	return resp
+}
+
+// buildExportZip 将导出数据序列化为JSON并打包为ZIP
+func buildExportZip(archive *exportArchive) ([]byte, error) {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,21 @@
+package service
+
+import "context"
+
+// eventBus 进程内事件总线，将事件依次分发给所有订阅者
+// 订阅者各自处理失败，不影响其他订阅者和业务结果
+type eventBus struct {
+	subscribers []EventPublisher
+}
+
+// NewEventBus 创建事件总线，业务服务发布的事件按顺序分发给subscribers
+func NewEventBus(subscribers ...EventPublisher) EventPublisher {
+	return &eventBus{subscribers: subscribers}
+}
+
+// Publish 将事件分发给所有订阅者
+func (b *eventBus) Publish(ctx context.Context, event string, data interface{}) {
+	for _, subscriber := range b.subscribers {
+		subscriber.Publish(ctx, event, data)
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/pkg/featureflag"
+)
+
+// 功能开关相关错误
+var (
+	// ErrInvalidFeatureFlagKey 开关标识格式错误
+	ErrInvalidFeatureFlagKey = errors.New("开关标识只能包含小写字母、数字和下划线，且以字母开头，长度为2-50个字符")
+	// ErrFeatureFlagNotFound 开关不存在
+	ErrFeatureFlagNotFound = errors.New("功能开关不存在")
+	// ErrFeatureDisabled 功能未对当前用户开放
+	ErrFeatureDisabled = errors.New("该功能暂未开放")
+)
+
+// featureFlagKeyRegexp 开关标识格式
+var featureFlagKeyRegexp = regexp.MustCompile(constant.FeatureFlagKeyPattern)
+
+// FeatureService 功能开关服务接口
+type FeatureService interface {
+	// ListFlags 获取所有功能开关及未创建开关的默认值
+	ListFlags(ctx context.Context) (*dto.FeatureFlagListResponse, error)
+	// SaveFlag 创建或更新功能开关
+	SaveFlag(ctx context.Context, key string, req *dto.SaveFeatureFlagRequest) (*dto.FeatureFlagInfo, error)
+	// DeleteFlag 删除功能开关，删除后恢复为配置的默认值
+	DeleteFlag(ctx context.Context, key string) error
+	// GetUserFeatures 获取所有功能开关对当前用户的开启状态
+	GetUserFeatures(ctx context.Context, userID uint) *dto.UserFeaturesResponse
+}
+
+// featureService 功能开关服务实现
+type featureService struct {
+	flags *featureflag.Client
+	tags  TagService
+}
+
+// NewFeatureService 创建功能开关服务实例
+func NewFeatureService(flags *featureflag.Client, tags TagService) FeatureService {
+	return &featureService{flags: flags, tags: tags}
+}
+
+// ListFlags 获取所有功能开关
+func (s *featureService) ListFlags(ctx context.Context) (*dto.FeatureFlagListResponse, error) {
+	flags, err := s.flags.List()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.FeatureFlagInfo, 0, len(flags))
+	for i := range flags {
+		list = append(list, toFeatureFlagInfo(&flags[i]))
+	}
+
+	defaults := config.GetFeatureFlagConfig().Defaults
+	if defaults == nil {
+		defaults = map[string]bool{}
+	}
+
+	return &dto.FeatureFlagListResponse{
+		Flags:    list,
+		Defaults: defaults,
+	}, nil
+}
+
+// SaveFlag 创建或更新功能开关
+func (s *featureService) SaveFlag(ctx context.Context, key string, req *dto.SaveFeatureFlagRequest) (*dto.FeatureFlagInfo, error) {
+	if !featureFlagKeyRegexp.MatchString(key) {
+		return nil, ErrInvalidFeatureFlagKey
+	}
+	if err := s.tags.ValidateNames(ctx, req.Tags); err != nil {
+		return nil, err
+	}
+
+	flag := &featureflag.Flag{
+		Key:         key,
+		Description: req.Description,
+		Enabled:     *req.Enabled,
+		Percentage:  req.Percentage,
+		Overrides:   req.Overrides,
+		Tags:        req.Tags,
+	}
+	if err := s.flags.Save(flag); err != nil {
+		return nil, err
+	}
+
+	info := toFeatureFlagInfo(flag)
+	return &info, nil
+}
+
+// DeleteFlag 删除功能开关
+func (s *featureService) DeleteFlag(ctx context.Context, key string) error {
+	if err := s.flags.Delete(key); err != nil {
+		if errors.Is(err, featureflag.ErrFlagNotFound) {
+			return ErrFeatureFlagNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetUserFeatures 获取所有功能开关对当前用户的开启状态
+func (s *featureService) GetUserFeatures(ctx context.Context, userID uint) *dto.UserFeaturesResponse {
+	return &dto.UserFeaturesResponse{
+		Features: s.flags.Evaluate(userID),
+	}
+}
+
+// toFeatureFlagInfo 将功能开关转换为响应信息
+func toFeatureFlagInfo(flag *featureflag.Flag) dto.FeatureFlagInfo {
+	overrides := flag.Overrides
+	if overrides == nil {
+		overrides = map[uint]bool{}
+	}
+	tags := flag.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return dto.FeatureFlagInfo{
+		Key:         flag.Key,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Percentage:  flag.Percentage,
+		Overrides:   overrides,
+		Tags:        tags,
+		UpdatedAt:   flag.UpdatedAt,
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/model"
+	"app/internal/ranking"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// 每纬度对应的距离（公里）
+const kmPerDegree = 111.32
+
+// FeedRequest 发现页查询条件
+type FeedRequest struct {
+	UserID    uint     // 查看者ID
+	Latitude  *float64 // 查看者所在纬度（GCJ-02坐标系），未提供时不使用附近来源
+	Longitude *float64 // 查看者所在经度
+}
+
+// FeedSource 发现页动态来源，返回按来源内部优先级排好序的候选动态
+// 来源只负责召回，去重和按比例混合由DiscoverFeed完成
+type FeedSource interface {
+	// Name 来源名称，与配置feed.discover.weights的key一致
+	Name() string
+	// Candidates 获取最多limit条候选动态，来源不适用于本次请求时返回空列表
+	Candidates(ctx context.Context, req *FeedRequest, limit int) ([]model.Post, error)
+}
+
+// weightedFeedSource 带混合比例的动态来源
+type weightedFeedSource struct {
+	source FeedSource
+	weight int
+}
+
+// DiscoverFeed 发现页动态混合器，按比例从各来源交错取动态并去重
+type DiscoverFeed struct {
+	sources       []weightedFeedSource
+	candidateSize int
+}
+
+// NewDiscoverFeed 创建发现页动态混合器实例，candidateSize为每个来源最多取的候选动态数
+func NewDiscoverFeed(candidateSize int) *DiscoverFeed {
+	if candidateSize <= 0 {
+		candidateSize = constant.FeedDiscoverCandidateSize
+	}
+	return &DiscoverFeed{candidateSize: candidateSize}
+}
+
+// NewDefaultDiscoverFeed 按配置创建包含关注、附近和热门来源的发现页动态混合器
+// 未配置混合比例时只使用关注来源
+func NewDefaultDiscoverFeed(postRepo repository.PostRepository) *DiscoverFeed {
+	cfg := config.GetFeedConfig().Discover
+	weights := cfg.Weights
+	if len(weights) == 0 {
+		weights = map[string]int{constant.FeedSourceFollowed: 1}
+	}
+
+	radius := cfg.NearbyRadius
+	if radius <= 0 {
+		radius = constant.FeedNearbyRadius
+	}
+	nearbyWindow := constant.FeedNearbyWindow
+	if d, err := time.ParseDuration(cfg.NearbyWindow); err == nil && d > 0 {
+		nearbyWindow = d
+	}
+	trendingWindow := constant.FeedTrendingWindow
+	if d, err := time.ParseDuration(cfg.TrendingWindow); err == nil && d > 0 {
+		trendingWindow = d
+	}
+
+	feed := NewDiscoverFeed(cfg.CandidateSize)
+	feed.Register(&followedFeedSource{postRepo: postRepo}, weights[constant.FeedSourceFollowed])
+	feed.Register(&nearbyFeedSource{postRepo: postRepo, radius: radius, window: nearbyWindow}, weights[constant.FeedSourceNearby])
+	feed.Register(&trendingFeedSource{postRepo: postRepo, window: trendingWindow}, weights[constant.FeedSourceTrending])
+	return feed
+}
+
+// Register 注册动态来源，混合比例不大于0时忽略
+func (f *DiscoverFeed) Register(source FeedSource, weight int) {
+	if weight <= 0 {
+		return
+	}
+	f.sources = append(f.sources, weightedFeedSource{source: source, weight: weight})
+}
+
+// Mix 从各来源召回候选动态并按比例交错混合，返回去重后的动态ID
+// 单个来源召回失败只记录日志，其余来源照常混合
+func (f *DiscoverFeed) Mix(ctx context.Context, req *FeedRequest) []uint {
+	lists := make([][]uint, len(f.sources))
+	weights := make([]int, len(f.sources))
+	for i, ws := range f.sources {
+		weights[i] = ws.weight
+		posts, err := ws.source.Candidates(ctx, req, f.candidateSize)
+		if err != nil {
+			logger.Warn(ctx, "获取发现页候选动态失败", logger.String("source", ws.source.Name()), logger.Err(err))
+			continue
+		}
+		ids := make([]uint, len(posts))
+		for j, post := range posts {
+			ids[j] = post.ID
+		}
+		lists[i] = ids
+	}
+	return interleave(lists, weights)
+}
+
+// interleave 按平滑加权轮询从各列表依次取元素，跳过已取过的ID，列表取完后由其余列表补足
+// 如比例为6:2:2时，每10条中依次约有6、2、2条来自三个列表，且同一来源的动态不会集中出现
+func interleave(lists [][]uint, weights []int) []uint {
+	total := 0
+	for _, list := range lists {
+		total += len(list)
+	}
+
+	result := make([]uint, 0, total)
+	seen := make(map[uint]bool, total)
+	next := make([]int, len(lists))
+	current := make([]int, len(lists))
+	for {
+		// 只在仍有剩余元素的列表之间轮询
+		best, sum := -1, 0
+		for i, list := range lists {
+			if next[i] >= len(list) {
+				continue
+			}
+			current[i] += weights[i]
+			sum += weights[i]
+			if best < 0 || current[i] > current[best] {
+				best = i
+			}
+		}
+		if best < 0 {
+			return result
+		}
+		current[best] -= sum
+
+		id := lists[best][next[best]]
+		next[best]++
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+}
+
+// followedFeedSource 关注来源，关注用户和好友对查看者可见的最新动态，按推荐得分排序
+type followedFeedSource struct {
+	postRepo repository.PostRepository
+}
+
+// Name 来源名称
+func (s *followedFeedSource) Name() string {
+	return constant.FeedSourceFollowed
+}
+
+// Candidates 获取关注用户和好友的最新动态，按发布时间和互动热度排序
+func (s *followedFeedSource) Candidates(ctx context.Context, req *FeedRequest, limit int) ([]model.Post, error) {
+	posts, _, err := s.postRepo.GetFollowingPosts(ctx, req.UserID, 1, limit)
+	if err != nil {
+		return nil, err
+	}
+	return rankByEngagement(posts), nil
+}
+
+// nearbyFeedSource 附近来源，查看者附近近期发布的公开动态
+type nearbyFeedSource struct {
+	postRepo repository.PostRepository
+	radius   float64       // 搜索半径（公里）
+	window   time.Duration // 只包含该时长内发布的动态
+}
+
+// Name 来源名称
+func (s *nearbyFeedSource) Name() string {
+	return constant.FeedSourceNearby
+}
+
+// Candidates 获取以查看者位置为中心、边长为两倍半径的范围内的公开动态，未提供位置时返回空列表
+func (s *nearbyFeedSource) Candidates(ctx context.Context, req *FeedRequest, limit int) ([]model.Post, error) {
+	if req.Latitude == nil || req.Longitude == nil {
+		return nil, nil
+	}
+	box := geoBoxAround(*req.Latitude, *req.Longitude, s.radius)
+	return s.postRepo.GetNearbyPosts(ctx, req.UserID, box, time.Now().Add(-s.window), limit)
+}
+
+// geoBoxAround 返回以指定位置为中心、边长为两倍半径（公里）的经纬度矩形
+func geoBoxAround(lat, lng, radius float64) repository.GeoBox {
+	latDelta := radius / kmPerDegree
+	// 经度间距随纬度变小，高纬度地区限制在两极附近不超过180度
+	lngDelta := 180.0
+	if c := math.Cos(lat * math.Pi / 180); c > radius/(kmPerDegree*180) {
+		lngDelta = radius / (kmPerDegree * c)
+	}
+	return repository.GeoBox{
+		MinLat: lat - latDelta, MaxLat: lat + latDelta,
+		MinLng: lng - lngDelta, MaxLng: lng + lngDelta,
+	}
+}
+
+// trendingFeedSource 热门来源，近期互动最多的公开动态
+type trendingFeedSource struct {
+	postRepo repository.PostRepository
+	window   time.Duration // 只包含该时长内发布的动态
+}
+
+// Name 来源名称
+func (s *trendingFeedSource) Name() string {
+	return constant.FeedSourceTrending
+}
+
+// Candidates 获取近期互动最多的公开动态，再按发布时间和互动热度排序，避免较早的热门动态长期置顶
+func (s *trendingFeedSource) Candidates(ctx context.Context, req *FeedRequest, limit int) ([]model.Post, error) {
+	posts, err := s.postRepo.GetPopularPosts(ctx, req.UserID, time.Now().Add(-s.window), limit)
+	if err != nil {
+		return nil, err
+	}
+	return rankByEngagement(posts), nil
+}
+
+// rankByEngagement 按发布时间和互动热度对动态排序，不考虑作者亲密度
+func rankByEngagement(posts []model.Post) []model.Post {
+	candidates := make([]ranking.Candidate, len(posts))
+	byID := make(map[uint]model.Post, len(posts))
+	for i, post := range posts {
+		candidates[i] = ranking.Candidate{
+			PostID:    post.ID,
+			AuthorID:  post.UserID,
+			Likes:     post.Likes,
+			Comments:  post.Comments,
+			Views:     post.Views,
+			CreatedAt: post.CreatedAt,
+		}
+		byID[post.ID] = post
+	}
+
+	ranked := make([]model.Post, 0, len(posts))
+	for _, id := range ranking.Rank(candidates, nil, time.Now()) {
+		ranked = append(ranked, byID[id])
+	}
+	return ranked
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+
+	"app/internal/constant"
+	"app/pkg/logger"
+)
+
+// FollowNotifier 关注事件通知接口
+// 关注私密账号产生请求、请求被通过时调用，可替换为站内信或推送实现
+type FollowNotifier interface {
+	// FollowRequested 有用户请求关注私密账号
+	FollowRequested(ctx context.Context, requestID, followerID, targetID uint)
+	// FollowApproved 关注请求已被通过
+	FollowApproved(ctx context.Context, requestID, followerID, targetID uint)
+}
+
+// logFollowNotifier 仅记录日志的关注事件通知实现
+type logFollowNotifier struct{}
+
+// NewLogFollowNotifier 创建仅记录日志的关注事件通知实例
+func NewLogFollowNotifier() FollowNotifier {
+	return logFollowNotifier{}
+}
+
+// FollowRequested 记录关注请求事件
+func (logFollowNotifier) FollowRequested(ctx context.Context, requestID, followerID, targetID uint) {
+	logger.Info(ctx, "收到关注请求", logger.Uint("request_id", requestID), logger.Uint("follower_id", followerID), logger.Uint("target_id", targetID))
+}
+
+// FollowApproved 记录关注请求通过事件
+func (logFollowNotifier) FollowApproved(ctx context.Context, requestID, followerID, targetID uint) {
+	logger.Info(ctx, "关注请求已通过", logger.Uint("request_id", requestID), logger.Uint("follower_id", followerID), logger.Uint("target_id", targetID))
+}
+
+// dispatchFollowNotifier 按用户通知设置发送关注事件通知的实现
+type dispatchFollowNotifier struct {
+	dispatcher NotificationDispatcher
+}
+
+// NewDispatchFollowNotifier 创建按用户通知设置发送的关注事件通知实例
+func NewDispatchFollowNotifier(dispatcher NotificationDispatcher) FollowNotifier {
+	return dispatchFollowNotifier{dispatcher: dispatcher}
+}
+
+// FollowRequested 通知被关注的私密账号有新的关注请求
+func (n dispatchFollowNotifier) FollowRequested(ctx context.Context, requestID, followerID, targetID uint) {
+	n.dispatcher.Dispatch(ctx, targetID, constant.NotificationEventFollowRequest, &NotificationMessage{
+		Title:   "新的关注请求",
+		Content: "有用户请求关注你，请前往关注请求列表处理。",
+	})
+}
+
+// FollowApproved 通知关注请求的发起人请求已被通过
+func (n dispatchFollowNotifier) FollowApproved(ctx context.Context, requestID, followerID, targetID uint) {
+	n.dispatcher.Dispatch(ctx, followerID, constant.NotificationEventFollowApproved, &NotificationMessage{
+		Title:   "关注请求已通过",
+		Content: "你的关注请求已被通过。",
+	})
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/utils"
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// funnelEvents 本节点登录注册漏斗事件的累计次数，按步骤和失败原因区分
+var funnelEvents = metrics.NewCounterVec("auth_funnel_events_total", "登录注册漏斗事件次数", "step", "reason")
+
+// FunnelEvent 登录注册漏斗事件
+type FunnelEvent struct {
+	Step   string // 漏斗步骤，constant.FunnelCodeSent等
+	Mobile string // 手机号，分析日志中只记录摘要
+	UserID uint   // 用户ID，创建用户和登录成功时提供
+	Reason string // 登录失败原因，constant.LoginFailInvalidCode等
+}
+
+// FunnelService 登录注册漏斗服务接口
+// 每个事件写入分析日志供数据分析平台使用，同时累加本节点的Prometheus计数和Redis中的全局每日计数
+type FunnelService interface {
+	// Record 记录漏斗事件，记录失败时只记录日志，不影响登录流程
+	Record(ctx context.Context, event FunnelEvent)
+	// GetFunnel 获取日期范围内每日的漏斗数据、转化率和每个新用户的短信费用
+	GetFunnel(ctx context.Context, req *dto.GetFunnelRequest) (*dto.GetFunnelResponse, error)
+	// WriteMetrics 以Prometheus文本格式输出本节点的漏斗事件次数
+	WriteMetrics(w io.Writer) error
+}
+
+// funnelService 登录注册漏斗服务实现
+type funnelService struct{}
+
+// NewFunnelService 创建登录注册漏斗服务实例
+func NewFunnelService() FunnelService {
+	return &funnelService{}
+}
+
+// Record 记录漏斗事件
+func (s *funnelService) Record(ctx context.Context, event FunnelEvent) {
+	funnelEvents.Inc(event.Step, event.Reason)
+
+	fields := []zap.Field{logger.String("event", event.Step)}
+	if event.Mobile != "" {
+		fields = append(fields, logger.String("mobile_hash", utils.HashMobile(event.Mobile)))
+	}
+	if event.UserID != 0 {
+		fields = append(fields, logger.Uint("user_id", event.UserID))
+	}
+	if event.Reason != "" {
+		fields = append(fields, logger.String("reason", event.Reason))
+	}
+	logger.Analytics().Info(ctx, "登录注册漏斗事件", fields...)
+
+	key := cachekey.StatsFunnel(time.Now())
+	if _, err := redis.HIncrBy(key.String(), event.Step, 1); err != nil {
+		logger.Warn(ctx, "记录登录注册漏斗计数失败", logger.String("step", event.Step), logger.Err(err))
+		return
+	}
+	if event.Reason != "" {
+		_, _ = redis.HIncrBy(key.String(), constant.FunnelFailReasonFieldPrefix+event.Reason, 1)
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+}
+
+// GetFunnel 获取日期范围内每日的漏斗数据和区间汇总
+func (s *funnelService) GetFunnel(ctx context.Context, req *dto.GetFunnelRequest) (*dto.GetFunnelResponse, error) {
+	// 默认查询最近7天
+	startDate, endDate, err := parseStatsDateRange(req.StartDate, req.EndDate, truncateToDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.GetFunnelResponse{
+		StartDate: startDate.Format(constant.StatsDateLayout),
+		EndDate:   endDate.Format(constant.StatsDateLayout),
+		Summary:   dto.FunnelSummary{FailReasons: map[string]int64{}},
+	}
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		counts, err := redis.HGetAll(cachekey.StatsFunnel(day).String())
+		if err != nil {
+			return nil, err
+		}
+		count := func(field string) int64 {
+			n, _ := strconv.ParseInt(counts[field], 10, 64)
+			return n
+		}
+
+		item := dto.FunnelDay{
+			Date: day.Format(constant.StatsDateLayout),
+			FunnelCounts: dto.FunnelCounts{
+				CodesSent:     count(constant.FunnelCodeSent),
+				CodesVerified: count(constant.FunnelCodeVerified),
+				UsersCreated:  count(constant.FunnelUserCreated),
+				LoginSuccess:  count(constant.FunnelLoginSuccess),
+				LoginFailed:   count(constant.FunnelLoginFailed),
+			},
+		}
+		resp.List = append(resp.List, item)
+
+		sum := &resp.Summary.FunnelCounts
+		sum.CodesSent += item.CodesSent
+		sum.CodesVerified += item.CodesVerified
+		sum.UsersCreated += item.UsersCreated
+		sum.LoginSuccess += item.LoginSuccess
+		sum.LoginFailed += item.LoginFailed
+		for field, value := range counts {
+			if reason, ok := strings.CutPrefix(field, constant.FunnelFailReasonFieldPrefix); ok {
+				n, _ := strconv.ParseInt(value, 10, 64)
+				resp.Summary.FailReasons[reason] += n
+			}
+		}
+	}
+
+	summary := &resp.Summary
+	summary.VerifyRate = ratio(summary.CodesVerified, summary.CodesSent)
+	summary.LoginRate = ratio(summary.LoginSuccess, summary.CodesSent)
+	summary.SignupRate = ratio(summary.UsersCreated, summary.CodesSent)
+	summary.SMSCost = math.Round(float64(summary.CodesSent)*config.GetSMSConfig().Aliyun.UnitPrice*1000) / 1000
+	if summary.UsersCreated > 0 {
+		summary.SMSCostPerSignup = math.Round(summary.SMSCost/float64(summary.UsersCreated)*1000) / 1000
+	}
+
+	return resp, nil
+}
+
+// WriteMetrics 以Prometheus文本格式输出本节点的漏斗事件次数
+func (s *funnelService) WriteMetrics(w io.Writer) error {
+	return metrics.Write(w, funnelEvents.Family())
+}
+
+// ratio 计算转化率，保留4位小数，分母为0时返回0
+func ratio(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)/float64(total)*10000) / 10000
+}
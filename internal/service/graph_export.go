@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// 社交关系图导出相关错误
+var (
+	// ErrInvalidGraphExportTime 导出时间格式错误
+	ErrInvalidGraphExportTime = errors.New("时间格式错误，应为YYYY-MM-DD或RFC3339")
+	// ErrInvalidGraphExportRange 导出时间范围错误
+	ErrInvalidGraphExportRange = errors.New("时间范围无效，开始时间必须早于结束时间")
+)
+
+// graphEdgeColumns CSV格式的列名，与dto.GraphEdge的JSON字段一致
+var graphEdgeColumns = []string{"id", "user_id", "target_id", "created_at"}
+
+// GraphExportService 社交关系图导出服务接口
+type GraphExportService interface {
+	// ExportGraph 按ID顺序分批读取关注或好友关系并写入w，每批写入后立即刷新输出，返回导出的关系数量
+	// 请求条件错误时不写入任何数据
+	ExportGraph(ctx context.Context, req *dto.ExportGraphRequest, w io.Writer) (int, error)
+}
+
+// graphExportService 社交关系图导出服务实现
+type graphExportService struct {
+	followerRepo repository.UserFollowerRepository
+	friendRepo   repository.UserFriendRepository
+}
+
+// NewGraphExportService 创建社交关系图导出服务实例
+func NewGraphExportService(followerRepo repository.UserFollowerRepository, friendRepo repository.UserFriendRepository) GraphExportService {
+	return &graphExportService{
+		followerRepo: followerRepo,
+		friendRepo:   friendRepo,
+	}
+}
+
+// ExportGraph 按ID顺序分批读取关注或好友关系并写入w
+// 每行都包含关系ID，导出中断后可将最后一行的ID作为cursor续传
+func (s *graphExportService) ExportGraph(ctx context.Context, req *dto.ExportGraphRequest, w io.Writer) (int, error) {
+	filter, err := parseGraphExportFilter(req)
+	if err != nil {
+		return 0, err
+	}
+
+	write, flush, err := newGraphEdgeWriter(req.Format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		edges, err := s.loadEdges(ctx, req.Type, filter)
+		if err != nil {
+			return total, fmt.Errorf("查询社交关系失败: %w", err)
+		}
+		for _, edge := range edges {
+			if err := write(edge); err != nil {
+				return total, err
+			}
+		}
+		if err := flush(); err != nil {
+			return total, err
+		}
+		total += len(edges)
+
+		if len(edges) < constant.GraphExportChunkSize {
+			break
+		}
+		filter.AfterID = edges[len(edges)-1].ID
+	}
+
+	logger.Info(ctx, "社交关系图导出完成", logger.String("type", req.Type), logger.Uint("cursor", req.Cursor), logger.Int("edges", total))
+
+	return total, nil
+}
+
+// loadEdges 读取一批关注或好友关系
+func (s *graphExportService) loadEdges(ctx context.Context, relationType string, filter repository.RelationExportFilter) ([]dto.GraphEdge, error) {
+	if relationType == constant.GraphExportTypeFriend {
+		friends, err := s.friendRepo.ExportFriends(ctx, filter, constant.GraphExportChunkSize)
+		if err != nil {
+			return nil, err
+		}
+		edges := make([]dto.GraphEdge, len(friends))
+		for i, f := range friends {
+			edges[i] = dto.GraphEdge{ID: f.ID, UserID: f.UserID, TargetID: f.TargetID, CreatedAt: f.CreatedAt}
+		}
+		return edges, nil
+	}
+
+	follows, err := s.followerRepo.ExportFollows(ctx, filter, constant.GraphExportChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]dto.GraphEdge, len(follows))
+	for i, f := range follows {
+		edges[i] = dto.GraphEdge{ID: f.ID, UserID: f.UserID, TargetID: f.TargetID, CreatedAt: f.CreatedAt}
+	}
+	return edges, nil
+}
+
+// parseGraphExportFilter 解析导出请求中的续传位置和创建时间范围
+func parseGraphExportFilter(req *dto.ExportGraphRequest) (repository.RelationExportFilter, error) {
+	filter := repository.RelationExportFilter{AfterID: req.Cursor}
+
+	if req.Start != "" {
+		start, _, err := parseGraphExportTime(req.Start)
+		if err != nil {
+			return filter, err
+		}
+		filter.Start = start
+	}
+	if req.End != "" {
+		end, dateOnly, err := parseGraphExportTime(req.End)
+		if err != nil {
+			return filter, err
+		}
+		// 只提供日期时包含当天
+		if dateOnly {
+			end = end.AddDate(0, 0, 1)
+		}
+		filter.End = end
+	}
+
+	if !filter.Start.IsZero() && !filter.End.IsZero() && !filter.Start.Before(filter.End) {
+		return filter, ErrInvalidGraphExportRange
+	}
+	return filter, nil
+}
+
+// parseGraphExportTime 解析RFC3339时间或本地时区的日期，返回是否只提供了日期
+func parseGraphExportTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(constant.StatsDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, false, ErrInvalidGraphExportTime
+	}
+	return t, true, nil
+}
+
+// newGraphEdgeWriter 创建关系写入函数，CSV格式首行为列名，JSON Lines格式每行一个对象
+// flush在每批写入后调用，将缓冲的数据发送给客户端
+func newGraphEdgeWriter(format string, w io.Writer) (func(dto.GraphEdge) error, func() error, error) {
+	flushResponse := func() {
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	switch format {
+	case constant.GraphExportFormatJSONL:
+		enc := json.NewEncoder(w)
+		flush := func() error {
+			flushResponse()
+			return nil
+		}
+		return func(edge dto.GraphEdge) error { return enc.Encode(edge) }, flush, nil
+	case constant.GraphExportFormatCSV, "":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(graphEdgeColumns); err != nil {
+			return nil, nil, err
+		}
+		write := func(edge dto.GraphEdge) error {
+			return cw.Write([]string{
+				strconv.FormatUint(uint64(edge.ID), 10),
+				strconv.FormatUint(uint64(edge.UserID), 10),
+				strconv.FormatUint(uint64(edge.TargetID), 10),
+				edge.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		flush := func() error {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			flushResponse()
+			return nil
+		}
+		return write, flush, nil
+	default:
+		return nil, nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
@@ -1,42 +1,105 @@
 package service
 
 import (
+	"app/config"
+	"app/internal/constant"
 	"app/internal/model"
 	"app/internal/repository"
+	"app/internal/utils"
 	"app/pkg/cos"
+	"app/pkg/logger"
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// ErrImageNotFound 服务器上不存在相同内容的图片
+var ErrImageNotFound = errors.New("图片不存在")
+
+// ErrStorageUnavailable 对象存储服务熔断中，请求未发出，稍后重试即可
+var ErrStorageUnavailable = cos.ErrUnavailable
+
+// 直传相关错误
+var (
+	ErrUnsupportedImageType = errors.New("不支持的文件类型")
+	ErrInvalidUploadKey     = errors.New("无效的上传对象")
+	ErrUploadNotFound       = errors.New("上传的文件不存在")
+	ErrUploadTooLarge       = errors.New("文件大小超过限制")
+)
+
+// PresignedUpload 预签名上传信息
+type PresignedUpload struct {
+	UploadURL   string    // 预签名PUT地址
+	ObjectKey   string    // 上传的对象键，确认上传时回传
+	ContentType string    // 上传时必须携带的Content-Type
+	ExpiresAt   time.Time // 上传地址过期时间
+}
+
 // ImageService 图片服务接口
 type ImageService interface {
-	// UploadTempImage 上传临时图片
-	UploadTempImage(ctx context.Context, userID uint, reader io.Reader, filename string, size int64) (*model.TempImage, error)
-	// UploadMultipleTempImages 批量上传临时图片
-	UploadMultipleTempImages(ctx context.Context, userID uint, files []io.Reader, filenames []string, sizes []int64) ([]model.TempImage, []error)
+	// UploadTempImage 流式上传临时图片，文件超过大小上限时返回ErrUploadTooLarge
+	UploadTempImage(ctx context.Context, userID uint, reader io.Reader, filename string) (*model.TempImage, error)
 	// MoveImageToPost 将临时图片移动到动态并关联
 	MoveImageToPost(ctx context.Context, imageID, postID, userID uint) (*model.PostImage, error)
+	// PrepareImagesForPost 按顺序查找用户发布动态时使用的临时图片，重复的ID只保留一次，任一图片不存在或不属于用户时返回ErrImageNotFound
+	PrepareImagesForPost(ctx context.Context, imageIDs []uint, userID uint) ([]*model.TempImage, error)
+	// CopyImagesToPost 按顺序将临时图片复制到动态图片目录，返回待保存的动态图片记录
+	// 复制失败时返回已复制的图片记录和错误，由调用方决定是否通过DiscardPostImages删除已复制的文件
+	CopyImagesToPost(ctx context.Context, temps []*model.TempImage, postID uint) ([]model.PostImage, error)
+	// DiscardPostImages 删除未保存的动态图片在COS中的文件，用于创建动态失败后的补偿，删除失败只记录日志
+	DiscardPostImages(ctx context.Context, images []model.PostImage)
+	// ReleaseTempImages 删除已复制到动态的临时图片文件和记录，删除失败只记录日志
+	ReleaseTempImages(ctx context.Context, temps []*model.TempImage)
+	// ModerateTempImage 校验临时图片归属并审核，图片不存在或不属于用户时返回ErrImageNotFound，未通过审核时返回ErrImageRejected
+	ModerateTempImage(ctx context.Context, imageID, userID uint, scene string) (*model.TempImage, error)
+	// MoveImageToComment 将已审核的临时图片移动到评论并关联
+	MoveImageToComment(ctx context.Context, tempImage *model.TempImage, comment *model.PostComment) (*model.CommentImage, error)
+	// DeletePostImageFiles 删除动态所有图片和评论图片在COS中的文件，包含编辑时移除的图片，返回删除的文件数，图片记录由调用方删除
+	DeletePostImageFiles(ctx context.Context, postID uint) (int, error)
+	// ReuseImageByHash 根据内容摘要复用用户已上传的图片，客户端可据此跳过上传
+	ReuseImageByHash(ctx context.Context, userID uint, hash, filename string) (*model.TempImage, error)
+	// CreatePresignedUpload 生成客户端直传COS的预签名上传地址，对象键限定在用户的临时目录下
+	CreatePresignedUpload(ctx context.Context, userID uint, filename string) (*PresignedUpload, error)
+	// ConfirmPresignedUpload 确认直传完成，校验对象大小和类型后创建临时图片记录
+	ConfirmPresignedUpload(ctx context.Context, userID uint, objectKey string) (*model.TempImage, error)
+	// PostImageURL 返回动态图片的CDN访问地址，预签名URL已过期时重新生成
+	PostImageURL(ctx context.Context, img *model.PostImage) string
+	// CommentImageURL 返回评论图片的CDN访问地址，预签名URL已过期时重新生成
+	CommentImageURL(ctx context.Context, img *model.CommentImage) string
+	// RefreshExpiringURLs 重新生成即将过期的私有桶图片预签名URL并保存，返回更新的图片数量
+	RefreshExpiringURLs(ctx context.Context) (int, error)
 }
 
 // imageService 图片服务实现
 type imageService struct {
-	postImageRepo repository.PostImageRepository
-	tempImageRepo repository.TempImageRepository
-	userRepo      repository.UserRepository
-	cosClient     *cos.StorageClient
-	postRepo      repository.PostRepository
+	postImageRepo    repository.PostImageRepository
+	tempImageRepo    repository.TempImageRepository
+	commentImageRepo repository.CommentImageRepository
+	userRepo         repository.UserRepository
+	cosClient        *cos.StorageClient
+	postRepo         repository.PostRepository
+	moderator        ImageModerator
+	refreshBefore    time.Duration // 预签名URL在过期前多久重新生成
 }
 
 // NewImageService 创建图片服务实例
 func NewImageService(
 	postImageRepo repository.PostImageRepository,
 	tempImageRepo repository.TempImageRepository,
+	commentImageRepo repository.CommentImageRepository,
 	userRepo repository.UserRepository,
 	postRepo repository.PostRepository,
+	moderator ImageModerator,
 ) (ImageService, error) {
 	// 获取COS客户端
 	cosClient, err := cos.GetStorageClient()
@@ -44,79 +107,135 @@ func NewImageService(
 		return nil, fmt.Errorf("获取COS客户端失败: %w", err)
 	}
 
+	// 解析预签名URL的刷新提前量，未配置或配置错误时使用默认值
+	refreshBefore, err := time.ParseDuration(config.GetCOSConfig().URL.RefreshBefore)
+	if err != nil || refreshBefore <= 0 {
+		refreshBefore = constant.ImageURLDefaultRefreshBefore
+	}
+
 	return &imageService{
-		postImageRepo: postImageRepo,
-		tempImageRepo: tempImageRepo,
-		userRepo:      userRepo,
-		postRepo:      postRepo,
-		cosClient:     cosClient,
+		postImageRepo:    postImageRepo,
+		tempImageRepo:    tempImageRepo,
+		commentImageRepo: commentImageRepo,
+		userRepo:         userRepo,
+		postRepo:         postRepo,
+		cosClient:        cosClient,
+		moderator:        moderator,
+		refreshBefore:    refreshBefore,
 	}, nil
 }
 
+// streamResult 流式上传时读取文件的结果
+type streamResult struct {
+	size int64
+	err  error
+}
+
 // UploadTempImage 上传临时图片
-func (s *imageService) UploadTempImage(ctx context.Context, userID uint, reader io.Reader, filename string, size int64) (*model.TempImage, error) {
-	// 生成临时图片的对象键名
+// 先读取文件头解析宽高，再边读取边计算摘要并通过管道上传到COS，内存中只保留文件头
+// 配置了去除元数据时先完整读取文件并去除EXIF等元数据，再上传处理后的内容
+// 用户已上传过相同内容的临时图片时删除本次上传的对象并复用已有图片
+func (s *imageService) UploadTempImage(ctx context.Context, userID uint, reader io.Reader, filename string) (*model.TempImage, error) {
 	objectKey := generateTempImageObjectKey(userID, filename)
-
-	// 获取文件内容类型
 	contentType := getContentTypeByFilename(filename)
 
-	// 上传到COS
-	url, err := s.cosClient.UploadFile("", objectKey, reader, contentType)
-	if err != nil {
-		return nil, fmt.Errorf("上传临时图片到COS失败: %w", err)
+	if stripMetadataEnabled(constant.ImageUploadTypeServer) {
+		// 多读取一个字节用于判断文件是否超过大小上限
+		data, err := io.ReadAll(io.LimitReader(reader, constant.ImageMaxSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("读取上传文件失败: %w", err)
+		}
+		if len(data) > constant.ImageMaxSize {
+			return nil, ErrUploadTooLarge
+		}
+		if data, err = stripImageMetadata(data, contentType); err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
 	}
 
-	// 创建临时图片记录
-	tempImage := &model.TempImage{
-		UserID:      userID,
-		ObjectKey:   objectKey,
-		URL:         url,
-		Bucket:      "", // 使用默认存储桶
-		Size:        size,
-		ContentType: contentType,
+	// 读取文件头解析宽高，文件小于文件头大小时读取全部内容
+	br := bufio.NewReaderSize(reader, constant.ImageHeaderSize)
+	header, err := br.Peek(constant.ImageHeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("读取上传文件失败: %w", err)
 	}
-
-	// 保存到数据库
-	err = s.tempImageRepo.CreateTempImage(tempImage)
+	width, height, err := utils.ImageDimensions(header)
 	if err != nil {
-		return nil, fmt.Errorf("保存临时图片记录失败: %w", err)
+		return nil, ErrUnsupportedImageType
 	}
 
-	return tempImage, nil
-}
+	// 多读取一个字节用于判断文件是否超过大小上限，超限或读取失败时中断上传
+	hasher := sha256.New()
+	pr, pw := io.Pipe()
+	copied := make(chan streamResult, 1)
+	go func() {
+		n, err := io.Copy(io.MultiWriter(pw, hasher), io.LimitReader(br, constant.ImageMaxSize+1))
+		if err == nil && n > constant.ImageMaxSize {
+			err = ErrUploadTooLarge
+		}
+		pw.CloseWithError(err)
+		copied <- streamResult{size: n, err: err}
+	}()
 
-// UploadMultipleTempImages 批量上传临时图片
-func (s *imageService) UploadMultipleTempImages(ctx context.Context, userID uint, files []io.Reader, filenames []string, sizes []int64) ([]model.TempImage, []error) {
-	// 检查参数长度是否一致
-	if len(files) != len(filenames) || len(files) != len(sizes) {
-		return nil, []error{fmt.Errorf("参数数量不匹配")}
+	url, uploadErr := s.cosClient.UploadFile(ctx, "", objectKey, pr, contentType)
+	// 上传失败时关闭管道读取端，结束写入
+	pr.CloseWithError(uploadErr)
+	result := <-copied
+	if uploadErr != nil {
+		// 优先返回读取上传文件的错误，COS客户端不保留读取错误的类型
+		if result.err != nil && !errors.Is(result.err, io.ErrClosedPipe) {
+			if errors.Is(result.err, ErrUploadTooLarge) {
+				return nil, ErrUploadTooLarge
+			}
+			return nil, fmt.Errorf("读取上传文件失败: %w", result.err)
+		}
+		return nil, fmt.Errorf("上传临时图片到COS失败: %w", uploadErr)
 	}
+	size := result.size
+	hash := hex.EncodeToString(hasher.Sum(nil))
 
-	// 存储上传结果
-	results := make([]model.TempImage, 0, len(files))
-	errs := make([]error, len(files))
-
-	// 循环上传每个文件
-	for i, file := range files {
-		tempImage, err := s.UploadTempImage(ctx, userID, file, filenames[i], sizes[i])
-		if err != nil {
-			// 记录错误
-			errs[i] = fmt.Errorf("上传图片 %s 失败: %w", filenames[i], err)
-		} else {
-			// 添加成功结果
-			results = append(results, *tempImage)
-			errs[i] = nil
+	// 复用相同内容的已有临时图片
+	existing, err := s.tempImageRepo.FindByUserAndHash(ctx, userID, hash)
+	if err == nil {
+		if err := s.cosClient.DeleteFile(ctx, "", objectKey); err != nil {
+			logger.Warn(ctx, "删除重复的上传文件失败", logger.String("object_key", objectKey), logger.Err(err))
 		}
+		return s.presentTempImage(ctx, existing), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询临时图片失败: %w", err)
+	}
+
+	// 创建临时图片记录
+	stored := s.storedURL(ctx, "", objectKey, url, objectKey)
+	tempImage := &model.TempImage{
+		UserID:       userID,
+		ObjectKey:    objectKey,
+		URL:          stored.URL,
+		URLPresigned: stored.Presigned,
+		URLExpiresAt: stored.ExpiresAt,
+		Bucket:       "", // 使用默认存储桶
+		Size:         size,
+		Width:        width,
+		Height:       height,
+		ContentType:  contentType,
+		ContentHash:  hash,
+	}
+
+	// 保存到数据库
+	err = s.tempImageRepo.CreateTempImage(ctx, tempImage)
+	if err != nil {
+		return nil, fmt.Errorf("保存临时图片记录失败: %w", err)
 	}
 
-	return results, errs
+	return s.presentTempImage(ctx, tempImage), nil
 }
 
 // MoveImageToPost 将临时图片移动到动态并关联
 func (s *imageService) MoveImageToPost(ctx context.Context, imageID, postID, userID uint) (*model.PostImage, error) {
 	// 查找临时图片
-	tempImage, err := s.tempImageRepo.FindByID(imageID)
+	tempImage, err := s.tempImageRepo.FindByID(ctx, imageID)
 	if err != nil {
 		return nil, fmt.Errorf("查找临时图片记录失败: %w", err)
 	}
@@ -127,7 +246,7 @@ func (s *imageService) MoveImageToPost(ctx context.Context, imageID, postID, use
 	}
 
 	// 验证动态是否存在
-	_, err = s.postRepo.GetPost(postID)
+	_, err = s.postRepo.GetPost(ctx, postID)
 	if err != nil {
 		return nil, fmt.Errorf("动态不存在: %w", err)
 	}
@@ -140,57 +259,558 @@ func (s *imageService) MoveImageToPost(ctx context.Context, imageID, postID, use
 	newObjectKey := generatePostImageObjectKey(userID, postID, filename)
 
 	// 在COS中复制文件到新位置
-	err = s.cosClient.CopyFile("", oldObjectKey, "", newObjectKey)
+	err = s.cosClient.CopyFile(ctx, "", oldObjectKey, "", newObjectKey)
 	if err != nil {
 		return nil, fmt.Errorf("移动图片到最终位置失败: %w", err)
 	}
 
 	// 获取新文件的URL
-	newURL, err := s.cosClient.GetFileURL("", newObjectKey, 0) // 使用永久URL
-	if err != nil {
-		// 如果获取URL失败，使用替代方法
-		newURL = strings.Replace(tempImage.URL, oldObjectKey, newObjectKey, 1)
-	}
+	stored := s.storedURL(ctx, "", newObjectKey, tempImage.URL, oldObjectKey)
 
 	// 创建动态图片记录
 	postImage := &model.PostImage{
-		PostID:      postID,
-		UserID:      userID,
-		ObjectKey:   newObjectKey,
-		URL:         newURL,
-		Bucket:      tempImage.Bucket,
-		Size:        tempImage.Size,
-		ContentType: tempImage.ContentType,
+		PostID:       postID,
+		UserID:       userID,
+		ObjectKey:    newObjectKey,
+		URL:          stored.URL,
+		URLPresigned: stored.Presigned,
+		URLExpiresAt: stored.ExpiresAt,
+		Bucket:       tempImage.Bucket,
+		Size:         tempImage.Size,
+		Width:        tempImage.Width,
+		Height:       tempImage.Height,
+		ContentType:  tempImage.ContentType,
+		ContentHash:  tempImage.ContentHash,
 	}
 
 	// 保存到数据库
-	err = s.postImageRepo.CreatePostImage(postImage)
+	err = s.postImageRepo.CreatePostImage(ctx, postImage)
 	if err != nil {
 		return nil, fmt.Errorf("创建动态图片记录失败: %w", err)
 	}
 
 	// 删除临时图片记录
-	err = s.tempImageRepo.DeleteTempImage(imageID)
+	err = s.tempImageRepo.DeleteTempImage(ctx, imageID)
 	if err != nil {
 		// 仅记录错误，不影响主流程
-		fmt.Printf("删除临时图片记录失败: %v\n", err)
+		logger.Warn(ctx, "删除临时图片记录失败", logger.Uint("image_id", imageID), logger.Err(err))
 	}
 
+	// 返回CDN访问地址，数据库中保存源地址
+	postImage.URL = imageURL(postImage.URL, postImage.ContentHash)
 	return postImage, nil
 }
 
+// PrepareImagesForPost 按顺序查找用户发布动态时使用的临时图片
+func (s *imageService) PrepareImagesForPost(ctx context.Context, imageIDs []uint, userID uint) ([]*model.TempImage, error) {
+	temps := make([]*model.TempImage, 0, len(imageIDs))
+	seen := make(map[uint]bool, len(imageIDs))
+	for _, imageID := range imageIDs {
+		if seen[imageID] {
+			continue
+		}
+		seen[imageID] = true
+
+		tempImage, err := s.tempImageRepo.FindByID(ctx, imageID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: %d", ErrImageNotFound, imageID)
+			}
+			return nil, fmt.Errorf("查找临时图片记录失败: %w", err)
+		}
+		if tempImage.UserID != userID {
+			return nil, fmt.Errorf("%w: %d", ErrImageNotFound, imageID)
+		}
+		temps = append(temps, tempImage)
+	}
+	return temps, nil
+}
+
+// CopyImagesToPost 按顺序将临时图片复制到动态图片目录
+func (s *imageService) CopyImagesToPost(ctx context.Context, temps []*model.TempImage, postID uint) ([]model.PostImage, error) {
+	images := make([]model.PostImage, 0, len(temps))
+	for _, tempImage := range temps {
+		newObjectKey := generatePostImageObjectKey(tempImage.UserID, postID, filepath.Base(tempImage.ObjectKey))
+		if err := s.cosClient.CopyFile(ctx, "", tempImage.ObjectKey, "", newObjectKey); err != nil {
+			return images, fmt.Errorf("复制图片%d到最终位置失败: %w", tempImage.ID, err)
+		}
+
+		stored := s.storedURL(ctx, "", newObjectKey, tempImage.URL, tempImage.ObjectKey)
+		images = append(images, model.PostImage{
+			PostID:       postID,
+			UserID:       tempImage.UserID,
+			ObjectKey:    newObjectKey,
+			URL:          stored.URL,
+			URLPresigned: stored.Presigned,
+			URLExpiresAt: stored.ExpiresAt,
+			Bucket:       tempImage.Bucket,
+			Size:         tempImage.Size,
+			Width:        tempImage.Width,
+			Height:       tempImage.Height,
+			ContentType:  tempImage.ContentType,
+			ContentHash:  tempImage.ContentHash,
+		})
+	}
+	return images, nil
+}
+
+// DiscardPostImages 删除未保存的动态图片在COS中的文件
+func (s *imageService) DiscardPostImages(ctx context.Context, images []model.PostImage) {
+	for _, img := range images {
+		if err := s.cosClient.DeleteFile(ctx, img.Bucket, img.ObjectKey); err != nil {
+			logger.Warn(ctx, "删除未保存的动态图片文件失败", logger.String("object_key", img.ObjectKey), logger.Err(err))
+		}
+	}
+}
+
+// ReleaseTempImages 删除已复制到动态的临时图片文件和记录
+// 先删除文件再删除记录，文件删除失败时保留记录，由临时图片清理任务处理
+func (s *imageService) ReleaseTempImages(ctx context.Context, temps []*model.TempImage) {
+	for _, tempImage := range temps {
+		if err := s.cosClient.DeleteFile(ctx, tempImage.Bucket, tempImage.ObjectKey); err != nil {
+			logger.Warn(ctx, "删除临时图片文件失败", logger.Uint("image_id", tempImage.ID), logger.Err(err))
+			continue
+		}
+		if err := s.tempImageRepo.DeleteTempImage(ctx, tempImage.ID); err != nil {
+			logger.Warn(ctx, "删除临时图片记录失败", logger.Uint("image_id", tempImage.ID), logger.Err(err))
+		}
+	}
+}
+
+// ModerateTempImage 校验临时图片归属并审核
+func (s *imageService) ModerateTempImage(ctx context.Context, imageID, userID uint, scene string) (*model.TempImage, error) {
+	tempImage, err := s.tempImageRepo.FindByID(ctx, imageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, fmt.Errorf("查找临时图片记录失败: %w", err)
+	}
+	if tempImage.UserID != userID {
+		return nil, ErrImageNotFound
+	}
+
+	if err := s.moderator.ModerateImage(ctx, scene, tempImage); err != nil {
+		if errors.Is(err, ErrImageRejected) {
+			return nil, ErrImageRejected
+		}
+		return nil, fmt.Errorf("审核图片失败: %w", err)
+	}
+	return tempImage, nil
+}
+
+// MoveImageToComment 将已审核的临时图片复制到评论图片目录并创建评论图片记录
+func (s *imageService) MoveImageToComment(ctx context.Context, tempImage *model.TempImage, comment *model.PostComment) (*model.CommentImage, error) {
+	newObjectKey := generateCommentImageObjectKey(comment.UserID, comment.ID, filepath.Base(tempImage.ObjectKey))
+	if err := s.cosClient.CopyFile(ctx, "", tempImage.ObjectKey, "", newObjectKey); err != nil {
+		return nil, fmt.Errorf("移动图片到最终位置失败: %w", err)
+	}
+
+	stored := s.storedURL(ctx, "", newObjectKey, tempImage.URL, tempImage.ObjectKey)
+	commentImage := &model.CommentImage{
+		CommentID:    comment.ID,
+		PostID:       comment.PostID,
+		UserID:       comment.UserID,
+		ObjectKey:    newObjectKey,
+		URL:          stored.URL,
+		URLPresigned: stored.Presigned,
+		URLExpiresAt: stored.ExpiresAt,
+		Bucket:       tempImage.Bucket,
+		Size:         tempImage.Size,
+		Width:        tempImage.Width,
+		Height:       tempImage.Height,
+		ContentType:  tempImage.ContentType,
+		ContentHash:  tempImage.ContentHash,
+	}
+	if err := s.commentImageRepo.CreateCommentImage(ctx, commentImage); err != nil {
+		return nil, fmt.Errorf("创建评论图片记录失败: %w", err)
+	}
+
+	if err := s.tempImageRepo.DeleteTempImage(ctx, tempImage.ID); err != nil {
+		// 仅记录错误，不影响主流程
+		logger.Warn(ctx, "删除临时图片记录失败", logger.Uint("image_id", tempImage.ID), logger.Err(err))
+	}
+
+	// 返回CDN访问地址，数据库中保存源地址
+	commentImage.URL = imageURL(commentImage.URL, commentImage.ContentHash)
+	return commentImage, nil
+}
+
+// DeletePostImageFiles 删除动态所有图片和评论图片在COS中的文件，编辑时移除的图片文件保留到此时删除
+func (s *imageService) DeletePostImageFiles(ctx context.Context, postID uint) (int, error) {
+	images, err := s.postImageRepo.GetPostImagesWithDeleted(ctx, postID)
+	if err != nil {
+		return 0, fmt.Errorf("查询动态图片失败: %w", err)
+	}
+	commentImages, err := s.commentImageRepo.GetPostCommentImagesWithDeleted(ctx, postID)
+	if err != nil {
+		return 0, fmt.Errorf("查询评论图片失败: %w", err)
+	}
+
+	deleted := 0
+	for _, img := range images {
+		if err := s.cosClient.DeleteFile(ctx, img.Bucket, img.ObjectKey); err != nil {
+			return deleted, fmt.Errorf("删除动态图片文件失败: %w", err)
+		}
+		deleted++
+	}
+	for _, img := range commentImages {
+		if err := s.cosClient.DeleteFile(ctx, img.Bucket, img.ObjectKey); err != nil {
+			return deleted, fmt.Errorf("删除评论图片文件失败: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// ReuseImageByHash 根据内容摘要复用用户已上传的图片
+func (s *imageService) ReuseImageByHash(ctx context.Context, userID uint, hash, filename string) (*model.TempImage, error) {
+	tempImage, err := s.reuseImageByHash(ctx, userID, hash, filename)
+	if err != nil {
+		return nil, err
+	}
+	return s.presentTempImage(ctx, tempImage), nil
+}
+
+// reuseImageByHash 根据内容摘要查找或复制出可复用的临时图片，返回的记录保留源地址
+// 存在相同内容的临时图片时直接返回；存在相同内容的动态图片时在COS内复制为新的临时图片
+func (s *imageService) reuseImageByHash(ctx context.Context, userID uint, hash, filename string) (*model.TempImage, error) {
+	hash = strings.ToLower(hash)
+
+	// 优先复用临时图片
+	tempImage, err := s.tempImageRepo.FindByUserAndHash(ctx, userID, hash)
+	if err == nil {
+		return tempImage, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询临时图片失败: %w", err)
+	}
+
+	// 其次复用已发布的动态图片，复制对象避免临时图片清理时影响动态图片
+	postImage, err := s.postImageRepo.FindByUserAndHash(ctx, userID, hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, fmt.Errorf("查询动态图片失败: %w", err)
+	}
+
+	if filename == "" {
+		filename = filepath.Base(postImage.ObjectKey)
+	}
+	objectKey := generateTempImageObjectKey(userID, filename)
+	if err := s.cosClient.CopyFile(ctx, postImage.Bucket, postImage.ObjectKey, postImage.Bucket, objectKey); err != nil {
+		return nil, fmt.Errorf("复制已有图片失败: %w", err)
+	}
+
+	stored := s.storedURL(ctx, postImage.Bucket, objectKey, postImage.URL, postImage.ObjectKey)
+	tempImage = &model.TempImage{
+		UserID:       userID,
+		ObjectKey:    objectKey,
+		URL:          stored.URL,
+		URLPresigned: stored.Presigned,
+		URLExpiresAt: stored.ExpiresAt,
+		Bucket:       postImage.Bucket,
+		Size:         postImage.Size,
+		Width:        postImage.Width,
+		Height:       postImage.Height,
+		ContentType:  postImage.ContentType,
+		ContentHash:  hash,
+	}
+	if err := s.tempImageRepo.CreateTempImage(ctx, tempImage); err != nil {
+		return nil, fmt.Errorf("保存临时图片记录失败: %w", err)
+	}
+
+	return tempImage, nil
+}
+
+// CreatePresignedUpload 生成客户端直传COS的预签名上传地址
+// Content-Type纳入签名，客户端必须使用相同的类型上传
+func (s *imageService) CreatePresignedUpload(ctx context.Context, userID uint, filename string) (*PresignedUpload, error) {
+	contentType := getContentTypeByFilename(filename)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, ErrUnsupportedImageType
+	}
+
+	objectKey := generateTempImageObjectKey(userID, filename)
+	uploadURL, err := s.cosClient.GetPresignedPutURL(ctx, "", objectKey, contentType, constant.ImagePresignExpiration)
+	if err != nil {
+		return nil, fmt.Errorf("生成上传地址失败: %w", err)
+	}
+
+	return &PresignedUpload{
+		UploadURL:   uploadURL,
+		ObjectKey:   objectKey,
+		ContentType: contentType,
+		ExpiresAt:   time.Now().Add(constant.ImagePresignExpiration),
+	}, nil
+}
+
+// ConfirmPresignedUpload 确认直传完成并创建临时图片记录
+// 只接受用户自己临时目录下的对象，校验不通过的对象会被删除；重复确认返回已有记录
+func (s *imageService) ConfirmPresignedUpload(ctx context.Context, userID uint, objectKey string) (*model.TempImage, error) {
+	userPrefix := fmt.Sprintf("%s%d/", constant.ImageTempKeyPrefix, userID)
+	if !strings.HasPrefix(objectKey, userPrefix) || strings.Contains(objectKey, "..") {
+		return nil, ErrInvalidUploadKey
+	}
+
+	// 重复确认时直接返回已有记录
+	existing, err := s.tempImageRepo.FindByObjectKey(ctx, objectKey)
+	if err == nil {
+		return s.presentTempImage(ctx, existing), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询临时图片失败: %w", err)
+	}
+
+	info, err := s.cosClient.StatFile(ctx, "", objectKey)
+	if err != nil {
+		if errors.Is(err, cos.ErrObjectNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("获取上传文件信息失败: %w", err)
+	}
+
+	// 直传无法在上传前限制大小和类型，不合规的对象直接删除
+	reject := func(reason error) (*model.TempImage, error) {
+		if err := s.cosClient.DeleteFile(ctx, "", objectKey); err != nil {
+			logger.Warn(ctx, "删除不合规的上传文件失败", logger.String("object_key", objectKey), logger.Err(err))
+		}
+		return nil, reason
+	}
+	if info.Size > constant.ImageMaxSize {
+		return reject(ErrUploadTooLarge)
+	}
+	if info.ContentType != getContentTypeByFilename(objectKey) {
+		return reject(ErrUnsupportedImageType)
+	}
+
+	// 读取文件内容，计算摘要并解析宽高
+	var buf bytes.Buffer
+	if err := s.cosClient.DownloadFile(ctx, "", objectKey, &buf); err != nil {
+		return nil, fmt.Errorf("读取上传文件失败: %w", err)
+	}
+	data := buf.Bytes()
+
+	// 去除元数据后内容有变化时覆盖原对象
+	if stripMetadataEnabled(constant.ImageUploadTypePresign) {
+		stripped, err := stripImageMetadata(data, info.ContentType)
+		if err != nil {
+			return reject(err)
+		}
+		if !bytes.Equal(stripped, data) {
+			if _, err := s.cosClient.UploadFile(ctx, "", objectKey, bytes.NewReader(stripped), info.ContentType); err != nil {
+				return nil, fmt.Errorf("上传处理后的图片到COS失败: %w", err)
+			}
+			data = stripped
+		}
+	}
+
+	width, height, err := utils.ImageDimensions(data)
+	if err != nil {
+		return reject(ErrUnsupportedImageType)
+	}
+	hash := sha256.Sum256(data)
+
+	stored, err := s.cosClient.GetStoredURL(ctx, "", objectKey)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件地址失败: %w", err)
+	}
+
+	tempImage := &model.TempImage{
+		UserID:       userID,
+		ObjectKey:    objectKey,
+		URL:          stored.URL,
+		URLPresigned: stored.Presigned,
+		URLExpiresAt: stored.ExpiresAt,
+		Bucket:       "", // 使用默认存储桶
+		Size:         int64(len(data)),
+		Width:        width,
+		Height:       height,
+		ContentType:  info.ContentType,
+		ContentHash:  hex.EncodeToString(hash[:]),
+	}
+	if err := s.tempImageRepo.CreateTempImage(ctx, tempImage); err != nil {
+		return nil, fmt.Errorf("保存临时图片记录失败: %w", err)
+	}
+
+	return s.presentTempImage(ctx, tempImage), nil
+}
+
+// stripMetadataEnabled 判断上传方式是否需要去除图片元数据，未配置的上传方式默认去除
+func stripMetadataEnabled(uploadType string) bool {
+	enabled, ok := config.GetImageConfig().StripMetadata[uploadType]
+	return !ok || enabled
+}
+
+// stripImageMetadata 去除图片中的EXIF等元数据并按方向信息旋转，无法解析的图片返回ErrUnsupportedImageType
+func stripImageMetadata(data []byte, contentType string) ([]byte, error) {
+	stripped, err := utils.StripImageMetadata(data, contentType)
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsupportedImageFormat) {
+			return nil, ErrUnsupportedImageType
+		}
+		return nil, fmt.Errorf("处理图片元数据失败: %w", err)
+	}
+	return stripped, nil
+}
+
+// presentTempImage 将临时图片地址替换为CDN访问地址，仅用于返回给客户端
+func (s *imageService) presentTempImage(ctx context.Context, image *model.TempImage) *model.TempImage {
+	image.URL = storedImageURL(ctx, s.cosClient, image.Bucket, image.ObjectKey, tempImageURL(image), image.ContentHash)
+	return image
+}
+
+// PostImageURL 返回动态图片的CDN访问地址
+func (s *imageService) PostImageURL(ctx context.Context, img *model.PostImage) string {
+	return storedImageURL(ctx, s.cosClient, img.Bucket, img.ObjectKey, postImageURL(img), img.ContentHash)
+}
+
+// CommentImageURL 返回评论图片的CDN访问地址
+func (s *imageService) CommentImageURL(ctx context.Context, img *model.CommentImage) string {
+	return storedImageURL(ctx, s.cosClient, img.Bucket, img.ObjectKey, commentImageURL(img), img.ContentHash)
+}
+
+// storedURL 生成保存到数据库的图片地址，私有桶使用预签名URL
+// 生成失败时在复制源的地址上替换对象键；预签名URL的签名与对象键绑定，此时标记为已过期，由读取时或URL刷新任务重新生成
+func (s *imageService) storedURL(ctx context.Context, bucket, objectKey, srcURL, srcObjectKey string) cos.StoredURL {
+	stored, err := s.cosClient.GetStoredURL(ctx, bucket, objectKey)
+	if err == nil {
+		return stored
+	}
+	logger.Warn(ctx, "生成图片地址失败", logger.String("object_key", objectKey), logger.Err(err))
+
+	stored = cos.StoredURL{URL: strings.Replace(srcURL, srcObjectKey, objectKey, 1)}
+	if s.cosClient.IsPrivateBucket(bucket) {
+		now := time.Now()
+		stored.Presigned, stored.ExpiresAt = true, &now
+	}
+	return stored
+}
+
+// expiringImage 预签名URL即将过期的图片
+type expiringImage struct {
+	id        uint
+	bucket    string
+	objectKey string
+}
+
+// imageURLSource 保存图片预签名URL的一类记录
+type imageURLSource struct {
+	name   string
+	find   func(ctx context.Context, before time.Time, afterID uint) ([]expiringImage, error)
+	update func(ctx context.Context, id uint, url string, presigned bool, expiresAt *time.Time) error
+}
+
+// RefreshExpiringURLs 按ID顺序分批重新生成动态图片、评论图片和临时图片中即将过期的预签名URL
+// 存储桶改为公有读后，已保存的预签名URL同样替换为永久URL
+func (s *imageService) RefreshExpiringURLs(ctx context.Context) (int, error) {
+	limit := constant.ImageURLRefreshBatchSize
+	sources := []imageURLSource{
+		{
+			name: "post_image",
+			find: func(ctx context.Context, before time.Time, afterID uint) ([]expiringImage, error) {
+				images, err := s.postImageRepo.FindExpiringURLs(ctx, before, afterID, limit)
+				list := make([]expiringImage, 0, len(images))
+				for _, img := range images {
+					list = append(list, expiringImage{id: img.ID, bucket: img.Bucket, objectKey: img.ObjectKey})
+				}
+				return list, err
+			},
+			update: s.postImageRepo.UpdateURL,
+		},
+		{
+			name: "comment_image",
+			find: func(ctx context.Context, before time.Time, afterID uint) ([]expiringImage, error) {
+				images, err := s.commentImageRepo.FindExpiringURLs(ctx, before, afterID, limit)
+				list := make([]expiringImage, 0, len(images))
+				for _, img := range images {
+					list = append(list, expiringImage{id: img.ID, bucket: img.Bucket, objectKey: img.ObjectKey})
+				}
+				return list, err
+			},
+			update: s.commentImageRepo.UpdateURL,
+		},
+		{
+			name: "temp_image",
+			find: func(ctx context.Context, before time.Time, afterID uint) ([]expiringImage, error) {
+				images, err := s.tempImageRepo.FindExpiringURLs(ctx, before, afterID, limit)
+				list := make([]expiringImage, 0, len(images))
+				for _, img := range images {
+					list = append(list, expiringImage{id: img.ID, bucket: img.Bucket, objectKey: img.ObjectKey})
+				}
+				return list, err
+			},
+			update: s.tempImageRepo.UpdateURL,
+		},
+	}
+
+	before := time.Now().Add(s.refreshBefore)
+	refreshed := 0
+	for _, source := range sources {
+		n, err := s.refreshURLs(ctx, source, before)
+		refreshed += n
+		if err != nil {
+			return refreshed, fmt.Errorf("刷新%s的图片地址失败: %w", source.name, err)
+		}
+		if n > 0 {
+			logger.Info(ctx, "已刷新即将过期的图片地址", logger.String("source", source.name), logger.Int("count", n))
+		}
+	}
+	return refreshed, nil
+}
+
+// refreshURLs 按ID顺序分批重新生成一类记录中在before之前过期的预签名URL
+// 生成或保存失败时中止本次刷新，未刷新的记录在读取时按需重新生成，下次执行时继续刷新
+func (s *imageService) refreshURLs(ctx context.Context, source imageURLSource, before time.Time) (int, error) {
+	refreshed := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return refreshed, err
+		}
+
+		images, err := source.find(ctx, before, afterID)
+		if err != nil {
+			return refreshed, fmt.Errorf("查询即将过期的图片地址失败: %w", err)
+		}
+		for _, img := range images {
+			stored, err := s.cosClient.GetStoredURL(ctx, img.bucket, img.objectKey)
+			if err != nil {
+				return refreshed, fmt.Errorf("生成图片%d的地址失败: %w", img.id, err)
+			}
+			if err := source.update(ctx, img.id, stored.URL, stored.Presigned, stored.ExpiresAt); err != nil {
+				return refreshed, fmt.Errorf("保存图片%d的地址失败: %w", img.id, err)
+			}
+			refreshed++
+		}
+
+		if len(images) < constant.ImageURLRefreshBatchSize {
+			return refreshed, nil
+		}
+		afterID = images[len(images)-1].id
+	}
+}
+
 // 生成动态图片的对象键名
 func generatePostImageObjectKey(userID, postID uint, filename string) string {
 	extension := filepath.Ext(filename)
-	timestamp := time.Now().UnixNano() / 1e6 // 毫秒级时间戳
+	timestamp := time.Now().UnixNano() // 纳秒级时间戳，同一动态连续复制多张图片时不重复
 	return fmt.Sprintf("posts/%d/%d/%d%s", userID, postID, timestamp, extension)
 }
 
+// 生成评论图片的对象键名
+func generateCommentImageObjectKey(userID, commentID uint, filename string) string {
+	extension := filepath.Ext(filename)
+	timestamp := time.Now().UnixNano() / 1e6 // 毫秒级时间戳
+	return fmt.Sprintf("comments/%d/%d/%d%s", userID, commentID, timestamp, extension)
+}
+
 // 生成临时图片的对象键名
 func generateTempImageObjectKey(userID uint, filename string) string {
 	extension := filepath.Ext(filename)
 	timestamp := time.Now().UnixNano() / 1e6 // 毫秒时间戳
-	return fmt.Sprintf("temp/%d/%d%s", userID, timestamp, extension)
+	return fmt.Sprintf("%s%d/%d%s", constant.ImageTempKeyPrefix, userID, timestamp, extension)
 }
 
 // 根据文件名获取内容类型
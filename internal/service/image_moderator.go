@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"app/internal/model"
+	"app/pkg/logger"
+)
+
+// ErrImageRejected 图片未通过审核
+var ErrImageRejected = errors.New("图片未通过审核")
+
+// ImageModerator 图片审核接口
+// 图片关联到评论前调用，返回ErrImageRejected时拒绝发布，可替换为数据万象内容审核等实现
+type ImageModerator interface {
+	// ModerateImage 审核用户即将发布的图片，scene为发布场景，如comment
+	ModerateImage(ctx context.Context, scene string, image *model.TempImage) error
+}
+
+// logImageModerator 仅记录日志、不拦截的图片审核实现
+type logImageModerator struct{}
+
+// NewLogImageModerator 创建仅记录日志、不拦截的图片审核实例
+func NewLogImageModerator() ImageModerator {
+	return logImageModerator{}
+}
+
+// ModerateImage 记录待发布的图片并放行
+func (logImageModerator) ModerateImage(ctx context.Context, scene string, image *model.TempImage) error {
+	logger.Info(ctx, "发布图片", logger.String("scene", scene), logger.Uint("user_id", image.UserID),
+		logger.Uint("image_id", image.ID), logger.String("content_hash", image.ContentHash))
+	return nil
+}
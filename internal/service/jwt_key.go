@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/internal/dto"
+	"app/internal/jwtkey"
+	"app/pkg/jwt"
+	"app/pkg/logger"
+)
+
+// JWT签名密钥相关错误
+var (
+	// ErrJWTKeyNotFound 密钥不存在
+	ErrJWTKeyNotFound = errors.New("签名密钥不存在")
+	// ErrJWTKeyCannotRotate 密钥不能用于签发令牌或没有可轮换的密钥
+	ErrJWTKeyCannotRotate = errors.New("没有可用于签发令牌的目标密钥")
+)
+
+// JWTKeyService JWT签名密钥服务接口
+type JWTKeyService interface {
+	// ListKeys 获取所有已配置的签名密钥
+	ListKeys(ctx context.Context) (*dto.JWTKeyListResponse, error)
+	// RotateKey 轮换签发令牌使用的密钥，旧密钥签发的令牌在过期前仍然有效
+	RotateKey(ctx context.Context, req *dto.RotateJWTKeyRequest, operatorID uint) (*dto.JWTKeyListResponse, error)
+}
+
+// jwtKeyService JWT签名密钥服务实现
+type jwtKeyService struct{}
+
+// NewJWTKeyService 创建JWT签名密钥服务实例
+func NewJWTKeyService() JWTKeyService {
+	return &jwtKeyService{}
+}
+
+// ListKeys 获取所有已配置的签名密钥
+func (s *jwtKeyService) ListKeys(ctx context.Context) (*dto.JWTKeyListResponse, error) {
+	keys, err := jwt.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("加载签名密钥失败: %w", err)
+	}
+
+	list := make([]dto.JWTKeyInfo, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, dto.JWTKeyInfo{
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			CanSign:   key.CanSign,
+			Current:   key.Current,
+		})
+	}
+	return &dto.JWTKeyListResponse{Keys: list}, nil
+}
+
+// RotateKey 轮换签发令牌使用的密钥
+// 轮换结果保存在Redis中，所有API服务实例签发新令牌时立即使用新密钥
+func (s *jwtKeyService) RotateKey(ctx context.Context, req *dto.RotateJWTKeyRequest, operatorID uint) (*dto.JWTKeyListResponse, error) {
+	kid, err := jwt.RotationTarget(req.KeyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrKeyNotFound):
+			return nil, ErrJWTKeyNotFound
+		case errors.Is(err, jwt.ErrKeyCannotSign), errors.Is(err, jwt.ErrNoRotationKey):
+			return nil, ErrJWTKeyCannotRotate
+		}
+		return nil, fmt.Errorf("加载签名密钥失败: %w", err)
+	}
+
+	if err := jwtkey.SetCurrent(kid); err != nil {
+		return nil, fmt.Errorf("保存当前签名密钥失败: %w", err)
+	}
+	logger.Info(ctx, "JWT签名密钥已轮换", logger.String("key_id", kid), logger.Uint("operator_id", operatorID))
+
+	return s.ListKeys(ctx)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/maintenance"
+	"app/pkg/logger"
+)
+
+// MaintenanceService 维护模式服务接口
+type MaintenanceService interface {
+	// GetMaintenance 获取当前维护模式状态
+	GetMaintenance(ctx context.Context) (*dto.MaintenanceResponse, error)
+	// SetMaintenance 开启或关闭维护模式
+	SetMaintenance(ctx context.Context, req *dto.SetMaintenanceRequest, operatorID uint) (*dto.MaintenanceResponse, error)
+}
+
+// maintenanceService 维护模式服务实现
+type maintenanceService struct{}
+
+// NewMaintenanceService 创建维护模式服务实例
+func NewMaintenanceService() MaintenanceService {
+	return &maintenanceService{}
+}
+
+// GetMaintenance 获取当前维护模式状态
+func (s *maintenanceService) GetMaintenance(ctx context.Context) (*dto.MaintenanceResponse, error) {
+	state, err := maintenance.Get()
+	if err != nil {
+		return nil, fmt.Errorf("读取维护模式状态失败: %w", err)
+	}
+	return toMaintenanceResponse(state), nil
+}
+
+// SetMaintenance 开启或关闭维护模式
+func (s *maintenanceService) SetMaintenance(ctx context.Context, req *dto.SetMaintenanceRequest, operatorID uint) (*dto.MaintenanceResponse, error) {
+	if !*req.Enabled {
+		if err := maintenance.Disable(); err != nil {
+			return nil, fmt.Errorf("关闭维护模式失败: %w", err)
+		}
+		logger.Info(ctx, "维护模式已关闭", logger.Uint("operator_id", operatorID))
+		return toMaintenanceResponse(nil), nil
+	}
+
+	state := &maintenance.State{
+		Message:    req.Message,
+		RetryAfter: req.RetryAfter,
+		StartedAt:  time.Now(),
+		OperatorID: operatorID,
+	}
+	if state.Message == "" {
+		state.Message = constant.MaintenanceDefaultMessage
+	}
+	if state.RetryAfter <= 0 {
+		state.RetryAfter = constant.MaintenanceDefaultRetryAfter
+	}
+
+	// 重复开启时保留最初的开始时间
+	if current, err := maintenance.Get(); err == nil && current != nil {
+		state.StartedAt = current.StartedAt
+	}
+
+	if err := maintenance.Enable(state); err != nil {
+		return nil, fmt.Errorf("开启维护模式失败: %w", err)
+	}
+	logger.Info(ctx, "维护模式已开启", logger.Uint("operator_id", operatorID), logger.Int("retry_after", state.RetryAfter))
+	return toMaintenanceResponse(state), nil
+}
+
+// toMaintenanceResponse 将维护状态转换为响应，state为nil表示未处于维护模式
+func toMaintenanceResponse(state *maintenance.State) *dto.MaintenanceResponse {
+	if state == nil {
+		return &dto.MaintenanceResponse{Enabled: false}
+	}
+	return &dto.MaintenanceResponse{
+		Enabled:    true,
+		Message:    state.Message,
+		RetryAfter: state.RetryAfter,
+		StartedAt:  &state.StartedAt,
+		OperatorID: state.OperatorID,
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"app/internal/constant"
+	"app/internal/model"
+	"app/pkg/cdn"
+	"app/pkg/cos"
+)
+
+// avatarURL 返回用户头像的CDN访问地址，头像覆盖写入同一对象，按内容摘要追加版本参数刷新缓存
+func avatarURL(user *model.User) string {
+	return cdn.GetSigner().URL(user.Avatar, user.AvatarHash)
+}
+
+// imageURL 返回图片的CDN访问地址，数据库中始终保存源地址
+func imageURL(rawURL, hash string) string {
+	return cdn.GetSigner().URL(rawURL, hash)
+}
+
+// storedImageURL 返回数据库中保存的图片地址对应的CDN访问地址
+// 私有桶的预签名URL已过期时按对象键重新生成，URL刷新任务未及时处理时也不会返回失效的地址
+func storedImageURL(ctx context.Context, client *cos.StorageClient, bucket, objectKey string, stored cos.StoredURL, hash string) string {
+	return imageURL(client.RefreshURL(ctx, bucket, objectKey, stored), hash)
+}
+
+// postImageURL 动态图片保存的访问地址
+func postImageURL(img *model.PostImage) cos.StoredURL {
+	return cos.StoredURL{URL: img.URL, Presigned: img.URLPresigned, ExpiresAt: img.URLExpiresAt}
+}
+
+// commentImageURL 评论图片保存的访问地址
+func commentImageURL(img *model.CommentImage) cos.StoredURL {
+	return cos.StoredURL{URL: img.URL, Presigned: img.URLPresigned, ExpiresAt: img.URLExpiresAt}
+}
+
+// tempImageURL 临时图片保存的访问地址
+func tempImageURL(img *model.TempImage) cos.StoredURL {
+	return cos.StoredURL{URL: img.URL, Presigned: img.URLPresigned, ExpiresAt: img.URLExpiresAt}
+}
+
+// thumbURL 在图片访问地址上追加数据万象缩略处理参数
+// CDN鉴权只校验路径，签名后追加处理参数不影响鉴权
+func thumbURL(url string) string {
+	if url == "" {
+		return ""
+	}
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+	return url + sep + constant.ImageThumbProcess
+}
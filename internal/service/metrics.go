@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"app/internal/dto"
+	"app/pkg/metrics"
+	"app/pkg/poolmonitor"
+	"app/pkg/resilience"
+)
+
+// MetricsService 运行指标服务接口
+type MetricsService interface {
+	// GetRepositoryMetrics 获取本节点仓库各方法的调用指标，用于定位热点查询
+	GetRepositoryMetrics(ctx context.Context, req *dto.RepositoryMetricsRequest) *dto.RepositoryMetricsResponse
+	// WriteRepositoryMetrics 以Prometheus文本格式输出仓库各方法的调用指标
+	WriteRepositoryMetrics(w io.Writer) error
+	// GetPoolStats 获取本节点数据库和Redis连接池的采样序列
+	GetPoolStats(ctx context.Context) *dto.PoolStatsResponse
+	// WritePoolMetrics 以Prometheus文本格式输出连接池状态和告警次数
+	WritePoolMetrics(w io.Writer) error
+	// WriteResilienceMetrics 以Prometheus文本格式输出第三方服务熔断器的状态和请求统计
+	WriteResilienceMetrics(w io.Writer) error
+}
+
+// metricsService 运行指标服务实现
+type metricsService struct {
+	registry *metrics.Registry
+}
+
+// NewMetricsService 创建运行指标服务实例
+func NewMetricsService() MetricsService {
+	return &metricsService{registry: metrics.Repositories}
+}
+
+// GetRepositoryMetrics 获取仓库各方法的调用指标，按指定字段倒序排列
+func (s *metricsService) GetRepositoryMetrics(ctx context.Context, req *dto.RepositoryMetricsRequest) *dto.RepositoryMetricsResponse {
+	snapshot := s.registry.Snapshot()
+	list := make([]dto.RepositoryMethodMetrics, len(snapshot))
+	for i, stats := range snapshot {
+		list[i] = dto.RepositoryMethodMetrics{
+			Repository:  stats.Component,
+			Method:      stats.Method,
+			Calls:       stats.Calls,
+			Errors:      stats.Errors,
+			ErrorRate:   stats.ErrorRate(),
+			TotalTimeMs: milliseconds(stats.TotalTime),
+			AvgTimeMs:   milliseconds(stats.AvgDuration()),
+			MaxTimeMs:   milliseconds(stats.MaxDuration),
+		}
+	}
+
+	key := func(m dto.RepositoryMethodMetrics) float64 { return m.TotalTimeMs }
+	switch req.SortBy {
+	case "avg_time":
+		key = func(m dto.RepositoryMethodMetrics) float64 { return m.AvgTimeMs }
+	case "max_time":
+		key = func(m dto.RepositoryMethodMetrics) float64 { return m.MaxTimeMs }
+	case "calls":
+		key = func(m dto.RepositoryMethodMetrics) float64 { return float64(m.Calls) }
+	case "errors":
+		key = func(m dto.RepositoryMethodMetrics) float64 { return float64(m.Errors) }
+	case "error_rate":
+		key = func(m dto.RepositoryMethodMetrics) float64 { return m.ErrorRate }
+	}
+	// 快照已按仓库和方法名称排序，稳定排序保证相同值的顺序固定
+	sort.SliceStable(list, func(i, j int) bool { return key(list[i]) > key(list[j]) })
+
+	if req.Limit > 0 && len(list) > req.Limit {
+		list = list[:req.Limit]
+	}
+	return &dto.RepositoryMetricsResponse{Methods: list}
+}
+
+// WriteRepositoryMetrics 以Prometheus文本格式输出仓库各方法的调用指标
+func (s *metricsService) WriteRepositoryMetrics(w io.Writer) error {
+	return s.registry.WriteMetrics(w)
+}
+
+// GetPoolStats 获取本节点数据库和Redis连接池的采样序列
+func (s *metricsService) GetPoolStats(ctx context.Context) *dto.PoolStatsResponse {
+	samples := poolmonitor.Samples()
+	if samples == nil {
+		samples = []poolmonitor.Sample{}
+	}
+	return &dto.PoolStatsResponse{
+		Status:  poolmonitor.Status(),
+		Samples: samples,
+	}
+}
+
+// WritePoolMetrics 以Prometheus文本格式输出连接池状态和告警次数
+func (s *metricsService) WritePoolMetrics(w io.Writer) error {
+	return poolmonitor.WriteMetrics(w)
+}
+
+// WriteResilienceMetrics 以Prometheus文本格式输出第三方服务熔断器的状态和请求统计
+func (s *metricsService) WriteResilienceMetrics(w io.Writer) error {
+	return resilience.WriteMetrics(w)
+}
+
+// milliseconds 将时长转换为毫秒
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/internal/dto"
+	"app/internal/mute"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// MuteService 用户禁言服务接口
+type MuteService interface {
+	// GetMute 获取用户的禁言状态
+	GetMute(ctx context.Context, userID uint) (*dto.MuteResponse, error)
+	// MuteUser 禁言用户，重复禁言时以本次的时长和原因为准
+	MuteUser(ctx context.Context, req *dto.MuteUserRequest, operatorID uint) (*dto.MuteResponse, error)
+	// UnmuteUser 提前解除用户禁言
+	UnmuteUser(ctx context.Context, req *dto.UnmuteUserRequest, operatorID uint) error
+}
+
+// muteService 用户禁言服务实现
+type muteService struct {
+	userRepo repository.UserRepository
+}
+
+// NewMuteService 创建用户禁言服务实例
+func NewMuteService(userRepo repository.UserRepository) MuteService {
+	return &muteService{userRepo: userRepo}
+}
+
+// GetMute 获取用户的禁言状态
+func (s *muteService) GetMute(ctx context.Context, userID uint) (*dto.MuteResponse, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	state, err := mute.Get(userID)
+	if err != nil {
+		return nil, fmt.Errorf("读取禁言状态失败: %w", err)
+	}
+	return toMuteResponse(userID, state), nil
+}
+
+// MuteUser 禁言用户，禁言记录到审计日志
+func (s *muteService) MuteUser(ctx context.Context, req *dto.MuteUserRequest, operatorID uint) (*dto.MuteResponse, error) {
+	if err := s.checkUser(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	state := &mute.State{
+		Reason:     req.Reason,
+		MutedAt:    now,
+		ExpiresAt:  now.Add(time.Duration(req.Duration) * time.Second),
+		OperatorID: operatorID,
+	}
+	if err := mute.Set(req.UserID, state); err != nil {
+		return nil, fmt.Errorf("保存禁言状态失败: %w", err)
+	}
+
+	logger.Audit().Info(ctx, "禁言用户",
+		logger.Uint("user_id", req.UserID),
+		logger.Uint("operator_id", operatorID),
+		logger.String("reason", req.Reason),
+		logger.Time("expires_at", state.ExpiresAt))
+	return toMuteResponse(req.UserID, state), nil
+}
+
+// UnmuteUser 提前解除用户禁言，解除记录到审计日志
+func (s *muteService) UnmuteUser(ctx context.Context, req *dto.UnmuteUserRequest, operatorID uint) error {
+	if err := s.checkUser(ctx, req.UserID); err != nil {
+		return err
+	}
+	if err := mute.Clear(req.UserID); err != nil {
+		return fmt.Errorf("解除禁言失败: %w", err)
+	}
+
+	logger.Audit().Info(ctx, "解除用户禁言",
+		logger.Uint("user_id", req.UserID),
+		logger.Uint("operator_id", operatorID),
+		logger.String("reason", req.Reason))
+	return nil
+}
+
+// checkUser 校验用户是否存在
+func (s *muteService) checkUser(ctx context.Context, userID uint) error {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	return nil
+}
+
+// toMuteResponse 将禁言状态转换为响应，state为nil表示未被禁言
+func toMuteResponse(userID uint, state *mute.State) *dto.MuteResponse {
+	if state == nil {
+		return &dto.MuteResponse{UserID: userID, Muted: false}
+	}
+	return &dto.MuteResponse{
+		UserID:     userID,
+		Muted:      true,
+		Reason:     state.Reason,
+		MutedAt:    &state.MutedAt,
+		ExpiresAt:  &state.ExpiresAt,
+		OperatorID: state.OperatorID,
+	}
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata" // 内置时区数据，运行环境缺少时区数据库时也能解析用户设置的时区
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/push"
+	"app/pkg/sms"
+)
+
+// 通知设置相关错误
+var (
+	// ErrNotificationInvalidEvent 不支持的通知事件类型
+	ErrNotificationInvalidEvent = errors.New("不支持的通知事件类型")
+	// ErrNotificationInvalidQuietHours 免打扰时间格式错误
+	ErrNotificationInvalidQuietHours = errors.New("免打扰时间格式错误，应为HH:MM且开始和结束时间不能相同")
+	// ErrNotificationInvalidTimezone 时区无效
+	ErrNotificationInvalidTimezone = errors.New("时区无效")
+)
+
+// NotificationMessage 通知内容
+type NotificationMessage struct {
+	Title           string            // 通知标题
+	Content         string            // 通知正文
+	SMSTemplateCode string            // 短信模板代码，为空时不发送短信
+	Data            map[string]string // 推送时透传给客户端的附加数据，如跳转目标
+}
+
+// NotificationSender 单个通知渠道的发送接口，可替换为实际的推送或邮件实现
+type NotificationSender interface {
+	// Send 向用户发送通知
+	Send(ctx context.Context, user *model.User, msg *NotificationMessage) error
+}
+
+// NotificationDispatcher 通知分发接口
+type NotificationDispatcher interface {
+	// Dispatch 按用户的通知偏好和免打扰时段向用户发送通知，发送失败只记录日志
+	Dispatch(ctx context.Context, userID uint, event string, msg *NotificationMessage)
+}
+
+// NotificationService 用户通知服务接口
+type NotificationService interface {
+	NotificationDispatcher
+	// GetSettings 获取用户的通知设置，未保存的部分返回默认值
+	GetSettings(ctx context.Context, userID uint) (*dto.NotificationSettingsResponse, error)
+	// UpdateSettings 更新用户的通知设置
+	UpdateSettings(ctx context.Context, userID uint, req *dto.UpdateNotificationSettingsRequest) (*dto.NotificationSettingsResponse, error)
+	// InitDefaults 为新注册用户写入默认通知设置
+	InitDefaults(ctx context.Context, userID uint) error
+	// RegisterDevice 上报当前设备的推送令牌
+	RegisterDevice(ctx context.Context, userID uint, req *dto.RegisterDeviceRequest) error
+	// UnregisterDevice 删除当前用户的设备推送令牌，退出登录时调用
+	UnregisterDevice(ctx context.Context, userID uint, token string) error
+	// Alert 通过所有渠道向用户发送账号安全相关的通知，不受通知偏好和免打扰时段限制，发送失败只记录日志
+	Alert(ctx context.Context, userID uint, msg *NotificationMessage)
+}
+
+// notificationService 用户通知服务实现
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	deviceRepo       repository.DeviceTokenRepository
+	userRepo         repository.UserRepository
+	senders          map[string]NotificationSender
+}
+
+// NewNotificationService 创建用户通知服务实例
+// 邮件暂未接入服务商，仅记录日志
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	deviceRepo repository.DeviceTokenRepository,
+	userRepo repository.UserRepository,
+	smsRepo repository.SMSRepository,
+	pushClient *push.Client,
+) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		deviceRepo:       deviceRepo,
+		userRepo:         userRepo,
+		senders: map[string]NotificationSender{
+			constant.NotificationChannelPush:  pushNotificationSender{deviceRepo: deviceRepo, client: pushClient},
+			constant.NotificationChannelSMS:   smsNotificationSender{smsRepo: smsRepo},
+			constant.NotificationChannelEmail: logNotificationSender{channel: constant.NotificationChannelEmail},
+		},
+	}
+}
+
+// Dispatch 按用户的通知偏好和免打扰时段向用户发送通知
+// 免打扰时段内的通知直接丢弃，不会延后发送
+func (s *notificationService) Dispatch(ctx context.Context, userID uint, event string, msg *NotificationMessage) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "查询通知接收用户失败，跳过通知", logger.Uint("user_id", userID), logger.String("event", event), logger.Err(err))
+		return
+	}
+
+	setting, preferences, err := s.loadSettings(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "查询用户通知设置失败，跳过通知", logger.Uint("user_id", userID), logger.String("event", event), logger.Err(err))
+		return
+	}
+
+	if inQuietHours(setting, time.Now()) {
+		logger.Info(ctx, "用户处于免打扰时段，跳过通知", logger.Uint("user_id", userID), logger.String("event", event))
+		return
+	}
+
+	preference, ok := preferences[event]
+	if !ok {
+		logger.Warn(ctx, "未知的通知事件类型，跳过通知", logger.Uint("user_id", userID), logger.String("event", event))
+		return
+	}
+
+	for _, channel := range enabledChannels(preference) {
+		if err := s.senders[channel].Send(ctx, user, msg); err != nil {
+			logger.Warn(ctx, "发送通知失败", logger.Uint("user_id", userID), logger.String("event", event), logger.String("channel", channel), logger.Err(err))
+		}
+	}
+}
+
+// Alert 通过所有渠道向用户发送账号安全相关的通知
+// 用于注销等涉及账号安全的操作，用户关闭的渠道也会发送，确保用户能及时发现非本人操作
+func (s *notificationService) Alert(ctx context.Context, userID uint, msg *NotificationMessage) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "查询通知接收用户失败，跳过通知", logger.Uint("user_id", userID), logger.String("title", msg.Title), logger.Err(err))
+		return
+	}
+
+	for _, channel := range []string{constant.NotificationChannelPush, constant.NotificationChannelSMS, constant.NotificationChannelEmail} {
+		if err := s.senders[channel].Send(ctx, user, msg); err != nil {
+			logger.Warn(ctx, "发送通知失败", logger.Uint("user_id", userID), logger.String("title", msg.Title), logger.String("channel", channel), logger.Err(err))
+		}
+	}
+}
+
+// GetSettings 获取用户的通知设置，未保存的部分返回默认值
+func (s *notificationService) GetSettings(ctx context.Context, userID uint) (*dto.NotificationSettingsResponse, error) {
+	setting, preferences, err := s.loadSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询通知设置失败: %w", err)
+	}
+	return buildNotificationSettingsResponse(setting, preferences), nil
+}
+
+// UpdateSettings 更新用户的通知设置，未传的事件偏好和免打扰时段保持不变
+func (s *notificationService) UpdateSettings(ctx context.Context, userID uint, req *dto.UpdateNotificationSettingsRequest) (*dto.NotificationSettingsResponse, error) {
+	setting, preferences, err := s.loadSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询通知设置失败: %w", err)
+	}
+
+	for _, item := range req.Preferences {
+		preference, ok := preferences[item.Event]
+		if !ok {
+			return nil, ErrNotificationInvalidEvent
+		}
+		preference.Push = item.Push
+		preference.SMS = item.SMS
+		preference.Email = item.Email
+	}
+
+	if req.QuietHours != nil {
+		if err := validateQuietHours(req.QuietHours); err != nil {
+			return nil, err
+		}
+		setting.QuietHoursEnabled = req.QuietHours.Enabled
+		setting.QuietStart = req.QuietHours.Start
+		setting.QuietEnd = req.QuietHours.End
+		setting.Timezone = req.QuietHours.Timezone
+	}
+
+	if err := s.notificationRepo.Save(ctx, setting, preferenceList(preferences)); err != nil {
+		return nil, fmt.Errorf("保存通知设置失败: %w", err)
+	}
+
+	logger.Info(ctx, "用户通知设置已更新", logger.Uint("user_id", userID))
+
+	return buildNotificationSettingsResponse(setting, preferences), nil
+}
+
+// InitDefaults 为新注册用户写入默认通知设置
+func (s *notificationService) InitDefaults(ctx context.Context, userID uint) error {
+	preferences := make(map[string]*model.NotificationPreference, len(constant.NotificationEvents))
+	for _, event := range constant.NotificationEvents {
+		preferences[event] = defaultNotificationPreference(userID, event)
+	}
+	return s.notificationRepo.Save(ctx, defaultNotificationSetting(userID), preferenceList(preferences))
+}
+
+// RegisterDevice 上报当前设备的推送令牌
+// 令牌已属于其他用户时转移给当前用户，超出每个用户的设备数上限时删除最久未上报的令牌
+func (s *notificationService) RegisterDevice(ctx context.Context, userID uint, req *dto.RegisterDeviceRequest) error {
+	if err := s.deviceRepo.Save(ctx, &model.DeviceToken{
+		UserID:   userID,
+		Platform: req.Platform,
+		Token:    req.Token,
+	}); err != nil {
+		return fmt.Errorf("保存设备推送令牌失败: %w", err)
+	}
+
+	if trimmed, err := s.deviceRepo.TrimUserTokens(ctx, userID, constant.DeviceTokenMaxPerUser); err != nil {
+		logger.Warn(ctx, "清理多余的设备推送令牌失败", logger.Uint("user_id", userID), logger.Err(err))
+	} else if trimmed > 0 {
+		logger.Info(ctx, "已删除多余的设备推送令牌", logger.Uint("user_id", userID), logger.Int64("count", trimmed))
+	}
+
+	return nil
+}
+
+// UnregisterDevice 删除当前用户的设备推送令牌，令牌不存在时视为成功
+func (s *notificationService) UnregisterDevice(ctx context.Context, userID uint, token string) error {
+	if _, err := s.deviceRepo.DeleteUserToken(ctx, userID, token); err != nil {
+		return fmt.Errorf("删除设备推送令牌失败: %w", err)
+	}
+	return nil
+}
+
+// loadSettings 读取用户的通知设置和各事件通知偏好，未保存的部分使用默认值
+func (s *notificationService) loadSettings(ctx context.Context, userID uint) (*model.NotificationSetting, map[string]*model.NotificationPreference, error) {
+	setting, err := s.notificationRepo.GetSetting(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+		setting = defaultNotificationSetting(userID)
+	}
+
+	saved, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preferences := make(map[string]*model.NotificationPreference, len(constant.NotificationEvents))
+	for _, event := range constant.NotificationEvents {
+		preferences[event] = defaultNotificationPreference(userID, event)
+	}
+	for i := range saved {
+		// 已下线的事件类型忽略
+		if _, ok := preferences[saved[i].Event]; ok {
+			preferences[saved[i].Event] = &saved[i]
+		}
+	}
+
+	return setting, preferences, nil
+}
+
+// defaultNotificationSetting 默认通知设置，免打扰默认关闭
+func defaultNotificationSetting(userID uint) *model.NotificationSetting {
+	return &model.NotificationSetting{
+		UserID:     userID,
+		QuietStart: constant.NotificationDefaultQuietStart,
+		QuietEnd:   constant.NotificationDefaultQuietEnd,
+		Timezone:   constant.NotificationDefaultTimezone,
+	}
+}
+
+// defaultNotificationPreference 事件的默认通知偏好
+func defaultNotificationPreference(userID uint, event string) *model.NotificationPreference {
+	preference := &model.NotificationPreference{UserID: userID, Event: event}
+	for _, channel := range constant.NotificationDefaultChannels[event] {
+		switch channel {
+		case constant.NotificationChannelPush:
+			preference.Push = true
+		case constant.NotificationChannelSMS:
+			preference.SMS = true
+		case constant.NotificationChannelEmail:
+			preference.Email = true
+		}
+	}
+	return preference
+}
+
+// preferenceList 按事件类型的固定顺序返回通知偏好列表
+func preferenceList(preferences map[string]*model.NotificationPreference) []model.NotificationPreference {
+	list := make([]model.NotificationPreference, 0, len(preferences))
+	for _, event := range constant.NotificationEvents {
+		if preference, ok := preferences[event]; ok {
+			list = append(list, *preference)
+		}
+	}
+	return list
+}
+
+// enabledChannels 返回通知偏好中开启的渠道
+func enabledChannels(preference *model.NotificationPreference) []string {
+	var channels []string
+	if preference.Push {
+		channels = append(channels, constant.NotificationChannelPush)
+	}
+	if preference.SMS {
+		channels = append(channels, constant.NotificationChannelSMS)
+	}
+	if preference.Email {
+		channels = append(channels, constant.NotificationChannelEmail)
+	}
+	return channels
+}
+
+// validateQuietHours 校验免打扰时间和时区
+func validateQuietHours(quietHours *dto.QuietHoursInfo) error {
+	start, err := time.Parse(constant.NotificationQuietTimeLayout, quietHours.Start)
+	if err != nil {
+		return ErrNotificationInvalidQuietHours
+	}
+	end, err := time.Parse(constant.NotificationQuietTimeLayout, quietHours.End)
+	if err != nil || start.Equal(end) {
+		return ErrNotificationInvalidQuietHours
+	}
+	if _, err := time.LoadLocation(quietHours.Timezone); err != nil {
+		return ErrNotificationInvalidTimezone
+	}
+	return nil
+}
+
+// inQuietHours 判断当前时间是否处于用户的免打扰时段，开始时间晚于结束时间时跨天
+// 设置无法解析时视为不在免打扰时段，避免通知被意外丢弃
+func inQuietHours(setting *model.NotificationSetting, now time.Time) bool {
+	if !setting.QuietHoursEnabled {
+		return false
+	}
+
+	loc, err := time.LoadLocation(setting.Timezone)
+	if err != nil {
+		return false
+	}
+	start, err := time.Parse(constant.NotificationQuietTimeLayout, setting.QuietStart)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(constant.NotificationQuietTimeLayout, setting.QuietEnd)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	return minute >= startMinute || minute < endMinute
+}
+
+// buildNotificationSettingsResponse 构建通知设置响应
+func buildNotificationSettingsResponse(setting *model.NotificationSetting, preferences map[string]*model.NotificationPreference) *dto.NotificationSettingsResponse {
+	resp := &dto.NotificationSettingsResponse{
+		Preferences: make([]dto.NotificationPreferenceInfo, 0, len(preferences)),
+		QuietHours: dto.QuietHoursInfo{
+			Enabled:  setting.QuietHoursEnabled,
+			Start:    setting.QuietStart,
+			End:      setting.QuietEnd,
+			Timezone: setting.Timezone,
+		},
+	}
+	for _, preference := range preferenceList(preferences) {
+		resp.Preferences = append(resp.Preferences, dto.NotificationPreferenceInfo{
+			Event: preference.Event,
+			Push:  preference.Push,
+			SMS:   preference.SMS,
+			Email: preference.Email,
+		})
+	}
+	return resp
+}
+
+// logNotificationSender 仅记录日志的通知渠道实现
+type logNotificationSender struct {
+	channel string
+}
+
+// Send 记录通知内容
+func (s logNotificationSender) Send(ctx context.Context, user *model.User, msg *NotificationMessage) error {
+	logger.Info(ctx, "发送通知", logger.String("channel", s.channel), logger.Uint("user_id", user.ID), logger.String("title", msg.Title), logger.String("content", msg.Content))
+	return nil
+}
+
+// pushNotificationSender 推送通知渠道实现，向用户的所有设备发送推送
+// 推送服务返回令牌失效时删除该令牌
+type pushNotificationSender struct {
+	deviceRepo repository.DeviceTokenRepository
+	client     *push.Client // 推送客户端，未配置推送服务时为nil，只记录日志
+}
+
+// Send 向用户上报过令牌的所有设备发送推送，部分设备失败时返回合并的错误
+func (s pushNotificationSender) Send(ctx context.Context, user *model.User, msg *NotificationMessage) error {
+	tokens, err := s.deviceRepo.GetUserTokens(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("查询设备推送令牌失败: %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	if s.client == nil {
+		logger.Info(ctx, "未配置推送服务，跳过推送", logger.Uint("user_id", user.ID), logger.String("title", msg.Title), logger.String("content", msg.Content))
+		return nil
+	}
+
+	message := &push.Message{
+		Title: msg.Title,
+		Body:  msg.Content,
+		Data:  msg.Data,
+	}
+	var errs []error
+	for _, token := range tokens {
+		err := s.client.Send(ctx, push.Platform(token.Platform), token.Token, message)
+		if errors.Is(err, push.ErrTokenInvalid) {
+			logger.Info(ctx, "设备推送令牌已失效，删除令牌", logger.Uint("user_id", user.ID), logger.Uint("token_id", token.ID), logger.Err(err))
+			if err := s.deviceRepo.DeleteToken(ctx, token.Token); err != nil {
+				errs = append(errs, fmt.Errorf("删除失效的设备推送令牌失败: %w", err))
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// smsNotificationSender 短信通知渠道实现，发送结果写入短信记录
+type smsNotificationSender struct {
+	smsRepo repository.SMSRepository
+}
+
+// Send 按通知指定的短信模板发送短信，未配置模板或用户没有手机号时跳过
+func (s smsNotificationSender) Send(ctx context.Context, user *model.User, msg *NotificationMessage) error {
+	if msg.SMSTemplateCode == "" || user.Mobile == "" {
+		logger.Info(ctx, "未配置通知短信模板或用户未绑定手机号，跳过短信通知", logger.Uint("user_id", user.ID))
+		return nil
+	}
+
+	client, err := sms.GetSMSClient()
+	if err != nil {
+		return fmt.Errorf("创建短信客户端失败: %w", err)
+	}
+
+	smsRecord := &model.SMSRecord{
+		PhoneNumber:  user.Mobile,
+		Type:         constant.SMSTypeNotification,
+		Content:      msg.Content,
+		TemplateCode: msg.SMSTemplateCode,
+		Status:       constant.SMSStatusSuccess,
+	}
+
+	smsResp, sendErr := client.SendSMS(ctx, sms.SMSRequest{
+		PhoneNumbers: user.Mobile,
+		TemplateCode: msg.SMSTemplateCode,
+	})
+	if sendErr != nil {
+		smsRecord.Status = constant.SMSStatusFailed
+		smsRecord.ErrorMessage = sendErr.Error()
+	} else {
+		smsRecord.RequestId = smsResp.RequestId
+		smsRecord.BizId = smsResp.BizId
+	}
+	_ = s.smsRepo.Create(ctx, smsRecord)
+
+	return sendErr
+}
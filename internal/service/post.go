@@ -1,38 +1,116 @@
 package service
 
 import (
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/contentpolicy"
 	"app/internal/dto"
 	"app/internal/model"
+	"app/internal/ranking"
 	"app/internal/repository"
+	"app/pkg/featureflag"
+	"app/pkg/geocode"
+	"app/pkg/linkpreview"
+	"app/pkg/logger"
+	"app/pkg/redis"
+	"app/pkg/translate"
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// 评论相关错误
+var (
+	ErrCommentNotFound = errors.New("评论不存在")
+	ErrCommentEmpty    = errors.New("评论内容和图片不能同时为空")
+)
+
+// 动态相关错误
+var (
+	ErrPostNotFound     = errors.New("动态不存在")
+	ErrPostForbidden    = errors.New("无权操作此动态")
+	ErrPostTrashExpired = errors.New("动态已超过恢复期限")
+	ErrPostNotModified  = errors.New("动态内容未修改")
+	ErrPostImageInvalid = errors.New("图片不属于该动态")
+)
+
 // PostService 动态服务接口
 type PostService interface {
 	// CreatePost 创建动态
 	CreatePost(ctx context.Context, req *dto.CreatePostRequest, userID uint) (*dto.CreatePostResponse, error)
 	// GetPosts 获取动态列表
 	GetPosts(ctx context.Context, req *dto.GetPostsRequest, userID uint) (*dto.GetPostsResponse, error)
+	// GetPost 获取动态详情，同时记录一次浏览
+	GetPost(ctx context.Context, postID, userID uint) (*dto.PostDetail, error)
+	// GetNearbyPosts 获取地图范围内近期发布的公开动态，按geohash聚合，缩放级别越大聚合格越小
+	GetNearbyPosts(ctx context.Context, req *dto.GetNearbyPostsRequest, userID uint) (*dto.GetNearbyPostsResponse, error)
+	// GetRecentPosts 获取作者对查看者可见的最近动态及可见动态总数，图片和位置批量查询
+	GetRecentPosts(ctx context.Context, author *model.User, viewerID uint, limit int) ([]dto.PostDetail, int64, error)
+	// UpdatePost 编辑动态内容和图片，每次编辑记录一条修订
+	UpdatePost(ctx context.Context, req *dto.UpdatePostRequest, userID uint) (*dto.UpdatePostResponse, error)
+	// GetRevisions 获取动态的修订记录，仅动态作者可以查看
+	GetRevisions(ctx context.Context, req *dto.GetPostRevisionsRequest, userID uint) (*dto.GetPostRevisionsResponse, error)
+	// DeletePost 删除动态，动态移入回收站，保留期内可以恢复
+	DeletePost(ctx context.Context, req *dto.DeletePostRequest, userID uint) error
+	// GetTrash 获取回收站中仍可恢复的动态列表
+	GetTrash(ctx context.Context, req *dto.GetTrashRequest, userID uint) (*dto.GetTrashResponse, error)
+	// RestorePost 从回收站恢复动态
+	RestorePost(ctx context.Context, req *dto.RestorePostRequest, userID uint) error
+	// PurgeTrash 永久清除超过保留期的已删除动态，由定时任务调用
+	PurgeTrash(ctx context.Context) (int, error)
 	// LikePost 点赞动态
 	LikePost(ctx context.Context, req *dto.LikePostRequest, userID uint) error
 	// CommentPost 评论动态
 	CommentPost(ctx context.Context, req *dto.CommentPostRequest, userID uint) (*dto.CommentPostResponse, error)
-	// GetComments 获取评论列表
-	GetComments(ctx context.Context, req *dto.GetCommentsRequest) (*dto.GetCommentsResponse, error)
+	// GetComments 获取评论列表，userID为当前查看者，用于标记是否已点赞
+	GetComments(ctx context.Context, req *dto.GetCommentsRequest, userID uint) (*dto.GetCommentsResponse, error)
+	// GetCommentReplies 获取评论的回复列表
+	GetCommentReplies(ctx context.Context, req *dto.GetCommentRepliesRequest, userID uint) (*dto.GetCommentsResponse, error)
+	// LikeComment 点赞评论
+	LikeComment(ctx context.Context, req *dto.LikeCommentRequest, userID uint) error
+	// UnlikeComment 取消点赞评论
+	UnlikeComment(ctx context.Context, req *dto.LikeCommentRequest, userID uint) error
+	// RecordView 记录动态浏览，同一访客每天只计一次
+	RecordView(ctx context.Context, postID uint, viewer string) error
+	// RecordClientView 记录客户端上报的动态浏览，动态不存在或查看者无权查看时返回ErrPostNotFound
+	RecordClientView(ctx context.Context, postID, viewerID uint, viewer string) error
+	// FlushViews 将指定日期的去重浏览数同步到数据库，由定时任务调用
+	FlushViews(ctx context.Context, date time.Time) (int, error)
+	// RefreshAffinities 刷新近期活跃用户的作者亲密度特征，由定时任务调用
+	RefreshAffinities(ctx context.Context) (int, error)
+	// TranslatePost 将当前用户可见的动态内容翻译为目标语言
+	TranslatePost(ctx context.Context, postID, userID uint, targetLang string) (*dto.TranslationResponse, error)
+	// TranslateComment 将当前用户可见的动态下的评论内容翻译为目标语言
+	TranslateComment(ctx context.Context, commentID, userID uint, targetLang string) (*dto.TranslationResponse, error)
 }
 
 // postService 动态服务实现
 type postService struct {
-	postRepo      repository.PostRepository
-	commentRepo   repository.PostCommentRepository
-	userRepo      repository.UserRepository
-	postImageRepo repository.PostImageRepository
-	imageService  ImageService
+	postRepo         repository.PostRepository
+	commentRepo      repository.PostCommentRepository
+	userRepo         repository.UserRepository
+	postImageRepo    repository.PostImageRepository
+	commentImageRepo repository.CommentImageRepository
+	revisionRepo     repository.PostRevisionRepository
+	audienceRepo     repository.AudienceListRepository
+	likeRepo         repository.CommentLikeRepository
+	locationRepo     repository.LocationRepository
+	linkPreviewRepo  repository.LinkPreviewRepository
+	imageService     ImageService
+	counters         PostCounterService
+	events           EventPublisher
+	notifier         NotificationDispatcher
+	publicIDs        PublicIDService
+	geocoder         *geocode.Client     // 逆地理编码客户端，未配置时为nil，不解析地址
+	translator       *translate.Client   // 翻译客户端，未配置时为nil，不提供翻译
+	linkPreviews     *linkpreview.Client // 链接预览客户端，未启用时为nil，不生成预览
+	discover         *DiscoverFeed       // 发现页动态混合器
+	features         *featureflag.Client
 }
 
 // NewPostService 创建动态服务实例
@@ -41,61 +119,175 @@ func NewPostService(
 	commentRepo repository.PostCommentRepository,
 	userRepo repository.UserRepository,
 	postImageRepo repository.PostImageRepository,
+	commentImageRepo repository.CommentImageRepository,
+	revisionRepo repository.PostRevisionRepository,
+	audienceRepo repository.AudienceListRepository,
+	likeRepo repository.CommentLikeRepository,
+	locationRepo repository.LocationRepository,
+	linkPreviewRepo repository.LinkPreviewRepository,
 	imageService ImageService,
+	counters PostCounterService,
+	events EventPublisher,
+	notifier NotificationDispatcher,
+	publicIDs PublicIDService,
+	geocoder *geocode.Client,
+	translator *translate.Client,
+	linkPreviews *linkpreview.Client,
+	discover *DiscoverFeed,
+	features *featureflag.Client,
 ) PostService {
 	return &postService{
-		postRepo:      postRepo,
-		commentRepo:   commentRepo,
-		userRepo:      userRepo,
-		postImageRepo: postImageRepo,
-		imageService:  imageService,
+		postRepo:         postRepo,
+		commentRepo:      commentRepo,
+		userRepo:         userRepo,
+		postImageRepo:    postImageRepo,
+		commentImageRepo: commentImageRepo,
+		revisionRepo:     revisionRepo,
+		audienceRepo:     audienceRepo,
+		likeRepo:         likeRepo,
+		locationRepo:     locationRepo,
+		linkPreviewRepo:  linkPreviewRepo,
+		imageService:     imageService,
+		counters:         counters,
+		events:           events,
+		notifier:         notifier,
+		publicIDs:        publicIDs,
+		geocoder:         geocoder,
+		translator:       translator,
+		linkPreviews:     linkPreviews,
+		discover:         discover,
+		features:         features,
 	}
 }
 
 // CreatePost 创建动态
 func (s *postService) CreatePost(ctx context.Context, req *dto.CreatePostRequest, userID uint) (*dto.CreatePostResponse, error) {
+	// 先校验图片，任一图片不存在或不属于当前用户时不创建动态
+	temps, err := s.imageService.PrepareImagesForPost(ctx, req.ImageIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验内容规则，不符合时不计入发布频率
+	imageTypes := make([]string, len(temps))
+	for i, tempImage := range temps {
+		imageTypes[i] = tempImage.ContentType
+	}
+	if err := contentpolicy.ForPost().Validate(contentpolicy.Content{Text: req.Content, ImageCount: len(temps), ImageTypes: imageTypes}); err != nil {
+		return nil, err
+	}
+
+	// 反垃圾检查：重复内容和发布频率
+	if err := checkDuplicatePost(userID, req.Content); err != nil {
+		return nil, err
+	}
+	if err := checkPostRate(userID); err != nil {
+		return nil, err
+	}
+
 	// 创建动态
 	post := &model.Post{
 		UserID:     userID,
 		Content:    req.Content,
+		Language:   translate.DetectLanguage(req.Content),
 		Visibility: req.Visibility, // 使用dto中的可见性值，对应constant.Visibility类型
 		Likes:      0,
 		Comments:   0,
 	}
 
-	// 保存动态基本信息
-	err := s.postRepo.CreatePost(post)
+	// 未指定可见性时使用用户设置的默认可见性
+	if post.Visibility == 0 {
+		visibility, err := s.defaultVisibility(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		post.Visibility = visibility
+	}
+
+	// 仅好友列表可见时，列表必须是自己创建的
+	if constant.Visibility(req.Visibility) == constant.VisibilityList {
+		if req.AudienceListID == nil {
+			return nil, ErrAudienceListNotFound
+		}
+		list, err := s.audienceRepo.GetList(ctx, *req.AudienceListID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrAudienceListNotFound
+			}
+			return nil, fmt.Errorf("查询好友列表失败: %w", err)
+		}
+		if list.UserID != userID {
+			return nil, ErrAudienceListNotFound
+		}
+		post.AudienceListID = &list.ID
+	}
+
+	// 保存位置信息，地址在后台异步解析
+	location, err := s.createLocation(ctx, req.Latitude, req.Longitude)
 	if err != nil {
-		return nil, fmt.Errorf("创建动态失败: %w", err)
+		return nil, err
+	}
+	if location != nil {
+		post.LocationID = &location.ID
 	}
 
-	// 处理图片上传
-	var imageURLs []string
+	// 关联内容中第一个链接的预览，预览在后台异步抓取
+	preview := s.linkPreviewFor(ctx, req.Content)
+	if preview != nil {
+		post.LinkPreviewID = &preview.ID
+	}
 
-	// 处理已上传的图片ID列表
-	if len(req.ImageIDs) > 0 {
-		for _, imageID := range req.ImageIDs {
-			// 移动图片到动态并关联
-			postImage, err := s.imageService.MoveImageToPost(ctx, imageID, post.ID, userID)
-			if err != nil {
-				fmt.Printf("关联图片失败: %v\n", err)
-				continue // 跳过关联失败的图片
-			}
+	// 在同一事务中保存动态和图片记录，任一图片复制失败时动态不会被创建
+	images, err := s.createPostWithImages(ctx, post, temps)
+	if err != nil {
+		return nil, err
+	}
+	rememberLastPost(userID, req.Content)
+	if location != nil {
+		s.resolveAddressAsync(ctx, *location)
+	}
+	s.fetchLinkPreviewAsync(ctx, preview)
 
-			// 添加图片URL到列表
-			imageURLs = append(imageURLs, postImage.URL)
-		}
+	imageURLs := make([]string, 0, len(images))
+	for i := range images {
+		imageURLs = append(imageURLs, s.imageService.PostImageURL(ctx, &images[i]))
 	}
 
+	// 事件数据携带可见性，仅公开动态投递给第三方应用
+	authorID := s.publicIDs.PublicID(ctx, constant.PublicIDUser, userID)
+	s.events.Publish(ctx, constant.WebhookEventPostCreated, dto.WebhookPostData{
+		ID:           post.ID,
+		UserID:       post.UserID,
+		PublicID:     post.PublicID,
+		PublicUserID: authorID,
+		Content:      post.Content,
+		Images:       imageURLs,
+		CreatedAt:    post.CreatedAt,
+		Visibility:   post.Visibility,
+	})
+
 	return &dto.CreatePostResponse{
-		ID:        post.ID,
-		UserID:    post.UserID,
-		Content:   post.Content,
-		Images:    imageURLs,
-		CreatedAt: post.CreatedAt,
+		ID:           post.PublicID,
+		UserID:       authorID,
+		Content:      post.Content,
+		Images:       imageURLs,
+		ImageResults: postImageResults(temps, imageURLs, nil),
+		CreatedAt:    post.CreatedAt,
 	}, nil
 }
 
+// defaultVisibility 返回用户发布动态的默认可见性，未设置时为公开
+func (s *postService) defaultVisibility(ctx context.Context, userID uint) (int, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("查询用户失败: %w", err)
+	}
+	if user.DefaultVisibility == 0 {
+		return int(constant.VisibilityPublic), nil
+	}
+	return user.DefaultVisibility, nil
+}
+
 // GetPosts 获取动态列表
 func (s *postService) GetPosts(ctx context.Context, req *dto.GetPostsRequest, userID uint) (*dto.GetPostsResponse, error) {
 	var posts []model.Post
@@ -105,86 +297,555 @@ func (s *postService) GetPosts(ctx context.Context, req *dto.GetPostsRequest, us
 	// 根据请求参数获取不同的动态列表
 	if req.UserID != nil && *req.UserID > 0 {
 		// 获取指定用户的动态，传递当前用户ID作为查看者ID
-		posts, count, err = s.postRepo.GetUserPosts(*req.UserID, req.Page, req.Size, userID)
+		posts, count, err = s.postRepo.GetUserPosts(ctx, *req.UserID, req.Page, req.Size, userID)
+	} else if req.Sort == constant.PostSortRecommended && s.features.IsEnabled(constant.FeatureRankedFeed, userID) {
+		// 按推荐得分获取关注用户的动态，未开放推荐排序的用户按时间排序
+		posts, count, err = s.getRecommendedPosts(ctx, userID, req.Page, req.Size)
+	} else if req.Sort == constant.PostSortDiscover {
+		// 混合关注、附近和热门动态
+		posts, count, err = s.getDiscoverPosts(ctx, &FeedRequest{UserID: userID, Latitude: req.Latitude, Longitude: req.Longitude}, req.Page, req.Size)
 	} else {
 		// 获取关注用户的动态
-		posts, count, err = s.postRepo.GetFollowingPosts(userID, req.Page, req.Size)
+		posts, count, err = s.postRepo.GetFollowingPosts(ctx, userID, req.Page, req.Size)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("获取动态列表失败: %w", err)
 	}
 
+	// 批量查询动态的位置地址和链接预览
+	addresses := s.loadAddresses(ctx, posts)
+	previews := s.loadLinkPreviews(ctx, posts)
+
 	// 构建动态信息列表
 	postList := make([]dto.PostDetail, 0, len(posts))
+	viewer := fmt.Sprintf("u:%d", userID)
 	for _, post := range posts {
-		user, err := s.userRepo.FindByID(post.UserID)
+		user, err := s.userRepo.FindByID(ctx, post.UserID)
 		if err != nil {
 			continue // 跳过获取失败的用户
 		}
 
-		// 获取动态图片
-		var images string
-		// 从图片关联中获取
-		postImages, err := s.postImageRepo.GetPostImages(post.ID)
-		if err == nil && len(postImages) > 0 {
-			imageURLs := make([]string, len(postImages))
-			for i, img := range postImages {
-				imageURLs[i] = img.URL
+		// 列表展示计为一次曝光，作者浏览自己的动态不计入
+		if post.UserID != userID {
+			if err := s.RecordView(ctx, post.ID, viewer); err != nil {
+				logger.Warn(ctx, "记录动态浏览失败", logger.Uint("post_id", post.ID), logger.Err(err))
+			}
+		}
+
+		detail := s.buildPostDetail(ctx, &post, user, addresses[post.ID])
+		detail.LinkPreview = previews[post.ID]
+		postList = append(postList, detail)
+	}
+
+	return &dto.GetPostsResponse{
+		Total: int(count),
+		List:  postList,
+	}, nil
+}
+
+// GetPost 获取动态详情，动态不存在或当前用户无权查看时均返回ErrPostNotFound
+func (s *postService) GetPost(ctx context.Context, postID, userID uint) (*dto.PostDetail, error) {
+	post, err := s.postRepo.GetVisiblePost(ctx, postID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("获取动态失败: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, post.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+
+	// 查看详情计为一次浏览，作者浏览自己的动态不计入
+	if post.UserID != userID {
+		if err := s.RecordView(ctx, post.ID, fmt.Sprintf("u:%d", userID)); err != nil {
+			logger.Warn(ctx, "记录动态浏览失败", logger.Uint("post_id", post.ID), logger.Err(err))
+		}
+	}
+
+	addresses := s.loadAddresses(ctx, []model.Post{*post})
+	previews := s.loadLinkPreviews(ctx, []model.Post{*post})
+	detail := s.buildPostDetail(ctx, post, user, addresses[post.ID])
+	detail.LinkPreview = previews[post.ID]
+	return &detail, nil
+}
+
+// GetRecentPosts 获取作者对查看者可见的最近动态，展示计为一次曝光
+func (s *postService) GetRecentPosts(ctx context.Context, author *model.User, viewerID uint, limit int) ([]dto.PostDetail, int64, error) {
+	posts, count, err := s.postRepo.GetUserPosts(ctx, author.ID, 1, limit, viewerID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取动态列表失败: %w", err)
+	}
+
+	postIDs := make([]uint, len(posts))
+	for i := range posts {
+		postIDs[i] = posts[i].ID
+	}
+	images := make(map[uint][]model.PostImage, len(posts))
+	postImages, err := s.postImageRepo.GetPostImagesByPostIDs(ctx, postIDs)
+	if err != nil {
+		logger.Warn(ctx, "查询动态图片失败", logger.Uint("user_id", author.ID), logger.Err(err))
+	}
+	for _, img := range postImages {
+		images[img.PostID] = append(images[img.PostID], img)
+	}
+	addresses := s.loadAddresses(ctx, posts)
+	previews := s.loadLinkPreviews(ctx, posts)
+
+	list := make([]dto.PostDetail, 0, len(posts))
+	viewer := fmt.Sprintf("u:%d", viewerID)
+	for i := range posts {
+		post := &posts[i]
+		// 作者浏览自己的动态不计入
+		if author.ID != viewerID {
+			if err := s.RecordView(ctx, post.ID, viewer); err != nil {
+				logger.Warn(ctx, "记录动态浏览失败", logger.Uint("post_id", post.ID), logger.Err(err))
+			}
+		}
+
+		detail := s.newPostDetail(ctx, post, author, s.toPostImageInfos(ctx, images[post.ID]), addresses[post.ID])
+		detail.LinkPreview = previews[post.ID]
+		list = append(list, detail)
+	}
+	return list, count, nil
+}
+
+// buildPostDetail 根据动态和作者信息构建动态详情
+func (s *postService) buildPostDetail(ctx context.Context, post *model.Post, user *model.User, address string) dto.PostDetail {
+	return s.newPostDetail(ctx, post, user, s.loadImages(ctx, post.ID), address)
+}
+
+// newPostDetail 根据动态、作者和已查询的图片信息构建动态详情
+// 点赞数和评论数合并尚未同步到数据库的增量
+func (s *postService) newPostDetail(ctx context.Context, post *model.Post, user *model.User, images []dto.PostImageInfo, address string) dto.PostDetail {
+	likes, comments := s.counters.Pending(ctx, post.ID)
+	return dto.PostDetail{
+		ID:         post.PublicID,
+		UserID:     user.PublicID,
+		Nickname:   user.Nickname,
+		Avatar:     avatarURL(user),
+		Content:    post.Content,
+		Language:   post.Language,
+		Images:     images,
+		LocationID: post.LocationID,
+		Address:    address,
+		Likes:      post.Likes + int(likes),
+		Comments:   post.Comments + int(comments),
+		Views:      post.Views + s.pendingViews(post.ID),
+		Edited:     post.EditedAt != nil,
+		EditedAt:   post.EditedAt,
+		CreatedAt:  post.CreatedAt,
+		UpdatedAt:  post.UpdatedAt,
+	}
+}
+
+// loadImages 获取动态的图片信息，查询失败时返回空列表
+func (s *postService) loadImages(ctx context.Context, postID uint) []dto.PostImageInfo {
+	images := []dto.PostImageInfo{}
+	postImages, err := s.postImageRepo.GetPostImages(ctx, postID)
+	if err == nil {
+		images = append(images, s.toPostImageInfos(ctx, postImages)...)
+	}
+	return images
+}
+
+// toPostImageInfos 将动态图片记录转换为响应中的图片信息
+func (s *postService) toPostImageInfos(ctx context.Context, postImages []model.PostImage) []dto.PostImageInfo {
+	images := make([]dto.PostImageInfo, 0, len(postImages))
+	for _, img := range postImages {
+		url := s.imageService.PostImageURL(ctx, &img)
+		images = append(images, dto.PostImageInfo{
+			ID:       img.ID,
+			URL:      url,
+			Width:    img.Width,
+			Height:   img.Height,
+			ThumbURL: thumbURL(url),
+		})
+	}
+	return images
+}
+
+// UpdatePost 编辑动态，只能编辑自己的动态
+// 新增图片使用已上传的临时图片，移除的图片在修订记录中仍可查看；内容和图片都未变化时不记录修订
+func (s *postService) UpdatePost(ctx context.Context, req *dto.UpdatePostRequest, userID uint) (*dto.UpdatePostResponse, error) {
+	post, err := s.postRepo.GetPost(ctx, req.PostID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("查询动态失败: %w", err)
+	}
+	if post.UserID != userID {
+		return nil, ErrPostForbidden
+	}
+
+	// 移除的图片必须属于该动态
+	current, err := s.postImageRepo.GetPostImages(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("查询动态图片失败: %w", err)
+	}
+	owned := make(map[uint]bool, len(current))
+	for _, img := range current {
+		owned[img.ID] = true
+	}
+	removed := make([]uint, 0, len(req.RemoveImageIDs))
+	for _, id := range req.RemoveImageIDs {
+		if !owned[id] {
+			return nil, ErrPostImageInvalid
+		}
+		if !containsID(removed, id) {
+			removed = append(removed, id)
+		}
+	}
+
+	if req.Content == post.Content && len(req.AddImageIDs) == 0 && len(removed) == 0 {
+		return nil, ErrPostNotModified
+	}
+
+	// 按全部新增图片都关联成功计算编辑后的图片数，校验通过后再移动图片
+	adding := 0
+	for i, id := range req.AddImageIDs {
+		if !containsID(req.AddImageIDs[:i], id) {
+			adding++
+		}
+	}
+	if err := contentpolicy.ForPost().Validate(contentpolicy.Content{Text: req.Content, ImageCount: len(current) - len(removed) + adding}); err != nil {
+		return nil, err
+	}
+
+	// 移动新增的图片到动态，跳过移动失败的图片
+	added := make([]uint, 0, len(req.AddImageIDs))
+	for _, imageID := range req.AddImageIDs {
+		postImage, err := s.imageService.MoveImageToPost(ctx, imageID, post.ID, userID)
+		if err != nil {
+			logger.Warn(ctx, "关联图片失败", logger.Uint("post_id", post.ID), logger.Uint("image_id", imageID), logger.Err(err))
+			continue
+		}
+		added = append(added, postImage.ID)
+	}
+
+	if req.Content == post.Content && len(added) == 0 && len(removed) == 0 {
+		return nil, ErrPostNotModified
+	}
+
+	revision := &model.PostRevision{
+		PostID:          post.ID,
+		EditorID:        userID,
+		ContentBefore:   post.Content,
+		ContentAfter:    req.Content,
+		AddedImageIDs:   formatIDs(added),
+		RemovedImageIDs: formatIDs(removed),
+	}
+	post.Content = req.Content
+	post.Language = translate.DetectLanguage(req.Content)
+	preview := s.linkPreviewFor(ctx, req.Content)
+	post.LinkPreviewID = nil
+	if preview != nil {
+		post.LinkPreviewID = &preview.ID
+	}
+	if err := s.revisionRepo.UpdatePostWithRevision(ctx, post, revision, removed); err != nil {
+		return nil, err
+	}
+	s.fetchLinkPreviewAsync(ctx, preview)
+
+	return &dto.UpdatePostResponse{
+		ID:       post.PublicID,
+		Content:  post.Content,
+		Images:   s.loadImages(ctx, post.ID),
+		EditedAt: post.EditedAt,
+	}, nil
+}
+
+// GetRevisions 获取动态的修订记录，仅动态作者可以查看
+func (s *postService) GetRevisions(ctx context.Context, req *dto.GetPostRevisionsRequest, userID uint) (*dto.GetPostRevisionsResponse, error) {
+	post, err := s.postRepo.GetPost(ctx, req.PostID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("查询动态失败: %w", err)
+	}
+	if post.UserID != userID {
+		return nil, ErrPostForbidden
+	}
+
+	revisions, count, err := s.revisionRepo.GetPostRevisions(ctx, post.ID, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取动态修订记录失败: %w", err)
+	}
+
+	// 批量查询修订中涉及的图片，包含已移除的图片
+	var imageIDs []uint
+	for _, rev := range revisions {
+		imageIDs = append(imageIDs, parseIDs(rev.AddedImageIDs)...)
+		imageIDs = append(imageIDs, parseIDs(rev.RemovedImageIDs)...)
+	}
+	postImages, err := s.postImageRepo.GetPostImagesByIDs(ctx, imageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询动态图片失败: %w", err)
+	}
+	imageByID := make(map[uint]model.PostImage, len(postImages))
+	for _, img := range postImages {
+		imageByID[img.ID] = img
+	}
+	lookup := func(ids []uint) []dto.PostImageInfo {
+		found := make([]model.PostImage, 0, len(ids))
+		for _, id := range ids {
+			if img, ok := imageByID[id]; ok {
+				found = append(found, img)
 			}
-			images = strings.Join(imageURLs, ",")
 		}
+		return s.toPostImageInfos(ctx, found)
+	}
+
+	editorIDs := make([]uint, 0, len(revisions))
+	for _, rev := range revisions {
+		editorIDs = append(editorIDs, rev.EditorID)
+	}
+	editors := s.publicIDs.PublicIDs(ctx, constant.PublicIDUser, editorIDs)
+
+	list := make([]dto.PostRevisionDetail, 0, len(revisions))
+	for _, rev := range revisions {
+		list = append(list, dto.PostRevisionDetail{
+			ID:            rev.ID,
+			EditorID:      editors[rev.EditorID],
+			ContentBefore: rev.ContentBefore,
+			ContentAfter:  rev.ContentAfter,
+			AddedImages:   lookup(parseIDs(rev.AddedImageIDs)),
+			RemovedImages: lookup(parseIDs(rev.RemovedImageIDs)),
+			CreatedAt:     rev.CreatedAt,
+		})
+	}
+
+	return &dto.GetPostRevisionsResponse{
+		Total: int(count),
+		List:  list,
+	}, nil
+}
+
+// formatIDs 将ID列表格式化为逗号分隔的字符串
+func formatIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseIDs 解析逗号分隔的ID列表，忽略无法解析的项
+func parseIDs(s string) []uint {
+	if s == "" {
+		return nil
+	}
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+// containsID 判断ID列表中是否包含指定ID
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
 
-		postList = append(postList, dto.PostDetail{
-			ID:        post.ID,
-			UserID:    post.UserID,
-			Nickname:  user.Nickname,
-			Avatar:    user.Avatar,
+// DeletePost 删除动态，只能删除自己的动态
+// 动态移入回收站后不再出现在动态列表、推荐和评论等查询中，图片和评论保留到永久清除
+func (s *postService) DeletePost(ctx context.Context, req *dto.DeletePostRequest, userID uint) error {
+	post, err := s.postRepo.GetPost(ctx, req.PostID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPostNotFound
+		}
+		return fmt.Errorf("查询动态失败: %w", err)
+	}
+	if post.UserID != userID {
+		return ErrPostForbidden
+	}
+
+	if err := s.postRepo.DeletePost(ctx, post.ID); err != nil {
+		return fmt.Errorf("删除动态失败: %w", err)
+	}
+	return nil
+}
+
+// GetTrash 获取回收站中仍可恢复的动态列表，按删除时间倒序
+func (s *postService) GetTrash(ctx context.Context, req *dto.GetTrashRequest, userID uint) (*dto.GetTrashResponse, error) {
+	since := time.Now().Add(-constant.PostTrashRetention)
+	posts, count, err := s.postRepo.GetTrashedPosts(ctx, userID, since, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取回收站动态列表失败: %w", err)
+	}
+
+	list := make([]dto.TrashPostDetail, 0, len(posts))
+	for _, post := range posts {
+		list = append(list, dto.TrashPostDetail{
+			ID:        post.PublicID,
 			Content:   post.Content,
-			Images:    images,
-			Likes:     post.Likes,
-			Comments:  post.Comments,
+			Images:    s.loadImages(ctx, post.ID),
 			CreatedAt: post.CreatedAt,
+			DeletedAt: post.DeletedAt.Time,
+			ExpiresAt: post.DeletedAt.Time.Add(constant.PostTrashRetention),
 		})
 	}
 
-	return &dto.GetPostsResponse{
+	return &dto.GetTrashResponse{
 		Total: int(count),
-		List:  postList,
+		List:  list,
 	}, nil
 }
 
+// RestorePost 从回收站恢复动态，只能恢复自己在保留期内删除的动态
+func (s *postService) RestorePost(ctx context.Context, req *dto.RestorePostRequest, userID uint) error {
+	post, err := s.postRepo.GetTrashedPost(ctx, req.PostID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPostNotFound
+		}
+		return fmt.Errorf("查询动态失败: %w", err)
+	}
+	if post.UserID != userID {
+		return ErrPostForbidden
+	}
+	if time.Since(post.DeletedAt.Time) >= constant.PostTrashRetention {
+		return ErrPostTrashExpired
+	}
+
+	if err := s.postRepo.RestorePost(ctx, post.ID); err != nil {
+		return fmt.Errorf("恢复动态失败: %w", err)
+	}
+	return nil
+}
+
+// PurgeTrash 分批永久清除超过保留期的已删除动态，包括图片文件、图片记录、评论和评论点赞
+// 单条动态清除失败时中止本次执行，下次执行时重试
+func (s *postService) PurgeTrash(ctx context.Context) (int, error) {
+	before := time.Now().Add(-constant.PostTrashRetention)
+	purged := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+
+		// 已清除的动态不会再被查询到，因此始终取第一批
+		posts, err := s.postRepo.GetExpiredTrashedPosts(ctx, before, constant.PostTrashPurgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("查询待清除动态失败: %w", err)
+		}
+
+		for _, post := range posts {
+			if _, err := s.imageService.DeletePostImageFiles(ctx, post.ID); err != nil {
+				return purged, err
+			}
+			if err := s.postRepo.PurgePost(ctx, post.ID); err != nil {
+				return purged, fmt.Errorf("永久删除动态失败: %w", err)
+			}
+			purged++
+		}
+
+		if len(posts) < constant.PostTrashPurgeBatchSize {
+			break
+		}
+	}
+
+	logger.Info(ctx, "已永久清除回收站中过期的动态", logger.Int("count", purged))
+	return purged, nil
+}
+
 // LikePost 点赞动态
 func (s *postService) LikePost(ctx context.Context, req *dto.LikePostRequest, userID uint) error {
 	// 检查动态是否存在
-	_, err := s.postRepo.GetPost(req.PostID)
+	post, err := s.postRepo.GetPost(ctx, req.PostID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("动态不存在")
+			return ErrPostNotFound
 		}
 		return fmt.Errorf("查询动态失败: %w", err)
 	}
 
-	// 增加点赞数
-	err = s.postRepo.IncrementPostLikes(req.PostID)
+	// 增加点赞数，先在Redis中累加，由定时任务同步到数据库
+	err = s.counters.Incr(ctx, req.PostID, constant.PostCounterLikes, 1)
 	if err != nil {
 		return fmt.Errorf("点赞失败: %w", err)
 	}
 
+	s.events.Publish(ctx, constant.EventPostLiked, dto.PostLikedData{
+		PostID:       post.ID,
+		UserID:       userID,
+		PublicPostID: post.PublicID,
+		PublicUserID: s.publicIDs.PublicID(ctx, constant.PublicIDUser, userID),
+		CreatedAt:    time.Now(),
+	})
+
+	if post.UserID != userID {
+		s.notifyAsync(ctx, post.UserID, constant.NotificationEventPostLike, &NotificationMessage{
+			Title:   "收到新的点赞",
+			Content: s.actorName(ctx, userID) + "赞了你的动态",
+			Data:    map[string]string{"event": constant.NotificationEventPostLike, "post_id": post.PublicID},
+		})
+	}
+
 	return nil
 }
 
 // CommentPost 评论动态
 func (s *postService) CommentPost(ctx context.Context, req *dto.CommentPostRequest, userID uint) (*dto.CommentPostResponse, error) {
 	// 检查动态是否存在
-	_, err := s.postRepo.GetPost(req.PostID)
+	post, err := s.postRepo.GetPost(ctx, req.PostID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("动态不存在")
+			return nil, ErrPostNotFound
 		}
 		return nil, fmt.Errorf("查询动态失败: %w", err)
 	}
 
+	if strings.TrimSpace(req.Content) == "" && req.ImageID == nil {
+		return nil, ErrCommentEmpty
+	}
+
+	// 校验内容规则，不符合时不计入评论频率
+	policy := contentpolicy.ForComment()
+	content := contentpolicy.Content{Text: req.Content}
+	if req.ImageID != nil {
+		content.ImageCount = 1
+	}
+	if err := policy.Validate(content); err != nil {
+		return nil, err
+	}
+
+	// 反垃圾检查：评论频率
+	if err := checkCommentRate(userID); err != nil {
+		return nil, err
+	}
+
+	// 附带图片时先校验归属并审核，未通过时不创建评论
+	var tempImage *model.TempImage
+	if req.ImageID != nil {
+		tempImage, err = s.imageService.ModerateTempImage(ctx, *req.ImageID, userID, constant.ImageSceneComment)
+		if err != nil {
+			return nil, err
+		}
+		// 图片类型在读取临时图片后才能校验
+		if err := policy.Validate(contentpolicy.Content{ImageTypes: []string{tempImage.ContentType}}); err != nil {
+			return nil, err
+		}
+	}
+
 	// 创建评论
 	comment := &model.PostComment{
 		PostID:   req.PostID,
@@ -193,63 +854,582 @@ func (s *postService) CommentPost(ctx context.Context, req *dto.CommentPostReque
 		ParentID: req.ParentID,
 	}
 
-	// 使用事务创建评论
-	err = s.commentRepo.CreateCommentWithTransaction(comment, req.PostID)
+	err = s.commentRepo.CreateComment(ctx, comment)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("创建评论失败: %w", err)
+	}
+	// 评论数先在Redis中累加，累加失败时由计数校准任务修正
+	if err := s.counters.Incr(ctx, req.PostID, constant.PostCounterComments, 1); err != nil {
+		logger.Warn(ctx, "增加动态评论数失败", logger.Uint("post_id", req.PostID), logger.Err(err))
+	}
+
+	// 关联评论图片
+	var image *dto.CommentImageInfo
+	if tempImage != nil {
+		commentImage, err := s.imageService.MoveImageToComment(ctx, tempImage, comment)
+		if err != nil {
+			logger.Warn(ctx, "关联评论图片失败", logger.Uint("comment_id", comment.ID), logger.Uint("image_id", tempImage.ID), logger.Err(err))
+		} else {
+			image = toCommentImageInfo(commentImage.URL, commentImage)
+		}
+	}
+	var webhookImage string
+	if image != nil {
+		webhookImage = image.URL
 	}
 
 	// 获取用户信息以返回昵称和头像
-	user, _ := s.userRepo.FindByID(userID)
+	user, _ := s.userRepo.FindByID(ctx, userID)
 
-	var nickname, avatar string
+	var nickname, avatar, publicUserID string
 	if user != nil {
 		nickname = user.Nickname
-		avatar = user.Avatar
+		avatar = avatarURL(user)
+		publicUserID = user.PublicID
 	}
+	parentID := publicParentID(s.commentParentIDs(ctx, []model.PostComment{*comment}), comment.ParentID)
+
+	// 事件数据携带所属动态的可见性，仅公开动态下的评论投递给第三方应用
+	s.events.Publish(ctx, constant.WebhookEventCommentCreated, dto.WebhookCommentData{
+		ID:             comment.ID,
+		PostID:         comment.PostID,
+		UserID:         comment.UserID,
+		PublicID:       comment.PublicID,
+		PublicPostID:   post.PublicID,
+		PublicUserID:   publicUserID,
+		PublicParentID: parentID,
+		Content:        comment.Content,
+		Image:          webhookImage,
+		CreatedAt:      comment.CreatedAt,
+		PostVisibility: post.Visibility,
+	})
+
+	s.notifyComment(ctx, post, comment, nickname)
 
 	return &dto.CommentPostResponse{
-		ID:        comment.ID,
-		PostID:    comment.PostID,
-		UserID:    comment.UserID,
+		ID:        comment.PublicID,
+		PostID:    post.PublicID,
+		UserID:    publicUserID,
 		Nickname:  nickname,
 		Avatar:    avatar,
 		Content:   comment.Content,
-		ParentID:  comment.ParentID,
+		ParentID:  parentID,
+		Image:     image,
 		CreatedAt: comment.CreatedAt,
 	}, nil
 }
 
-// GetComments 获取评论列表
-func (s *postService) GetComments(ctx context.Context, req *dto.GetCommentsRequest) (*dto.GetCommentsResponse, error) {
+// GetComments 获取评论列表，userID为当前查看者，用于标记是否已点赞
+// 只返回一级评论，回复通过GetCommentReplies按需展开
+func (s *postService) GetComments(ctx context.Context, req *dto.GetCommentsRequest, userID uint) (*dto.GetCommentsResponse, error) {
+	// 检查动态是否存在，回收站中的动态不展示评论
+	if _, err := s.postRepo.GetPost(ctx, req.PostID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("查询动态失败: %w", err)
+	}
+
 	// 获取评论列表
-	comments, count, err := s.commentRepo.GetPostComments(req.PostID, req.Page, req.Size)
+	comments, count, err := s.commentRepo.GetPostComments(ctx, req.PostID, req.Page, req.Size, req.Sort)
 	if err != nil {
 		return nil, fmt.Errorf("获取评论列表失败: %w", err)
 	}
 
-	// 构建评论信息列表
+	commentList, err := s.buildCommentDetails(ctx, comments, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.GetCommentsResponse{
+		Total:   int(count),
+		HasMore: int64(req.Page*req.Size) < count,
+		List:    commentList,
+	}, nil
+}
+
+// GetCommentReplies 获取评论的回复列表，userID为当前查看者，用于标记是否已点赞
+func (s *postService) GetCommentReplies(ctx context.Context, req *dto.GetCommentRepliesRequest, userID uint) (*dto.GetCommentsResponse, error) {
+	// 检查评论是否存在
+	if _, err := s.commentRepo.GetComment(ctx, req.CommentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("查询评论失败: %w", err)
+	}
+
+	replies, count, err := s.commentRepo.GetCommentReplies(ctx, req.CommentID, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取回复列表失败: %w", err)
+	}
+
+	replyList, err := s.buildCommentDetails(ctx, replies, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.GetCommentsResponse{
+		Total:   int(count),
+		HasMore: int64(req.Page*req.Size) < count,
+		List:    replyList,
+	}, nil
+}
+
+// buildCommentDetails 构建评论详情列表，填充作者信息、点赞状态和回复数
+func (s *postService) buildCommentDetails(ctx context.Context, comments []model.PostComment, userID uint) ([]dto.CommentDetail, error) {
+	commentIDs := make([]uint, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+
+	// 查询当前用户已点赞的评论
+	liked, err := s.likeRepo.GetLikedCommentIDs(ctx, userID, commentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("获取评论点赞状态失败: %w", err)
+	}
+
+	// 统计各评论的回复数
+	replyCounts, err := s.commentRepo.CountReplies(ctx, commentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("统计评论回复数失败: %w", err)
+	}
+
+	// 查询评论附带的图片
+	images, err := s.commentImageRepo.GetByCommentIDs(ctx, commentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("获取评论图片失败: %w", err)
+	}
+
+	// 评论只保存内部ID，所属动态和父评论的公开ID批量查询
+	postIDs := make([]uint, len(comments))
+	for i, comment := range comments {
+		postIDs[i] = comment.PostID
+	}
+	publicPostIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDPost, postIDs)
+	parents := s.commentParentIDs(ctx, comments)
+
 	commentList := make([]dto.CommentDetail, 0, len(comments))
 	for _, comment := range comments {
-		user, err := s.userRepo.FindByID(comment.UserID)
+		user, err := s.userRepo.FindByID(ctx, comment.UserID)
 		if err != nil {
 			continue // 跳过获取失败的用户
 		}
 
+		var image *dto.CommentImageInfo
+		if img, ok := images[comment.ID]; ok {
+			image = toCommentImageInfo(s.imageService.CommentImageURL(ctx, &img), &img)
+		}
+
 		commentList = append(commentList, dto.CommentDetail{
-			ID:        comment.ID,
-			PostID:    comment.PostID,
-			UserID:    comment.UserID,
-			Nickname:  user.Nickname,
-			Avatar:    user.Avatar,
-			Content:   comment.Content,
-			ParentID:  comment.ParentID,
-			CreatedAt: comment.CreatedAt,
+			ID:         comment.PublicID,
+			PostID:     publicPostIDs[comment.PostID],
+			UserID:     user.PublicID,
+			Nickname:   user.Nickname,
+			Avatar:     avatarURL(user),
+			Content:    comment.Content,
+			ParentID:   publicParentID(parents, comment.ParentID),
+			Image:      image,
+			Likes:      comment.Likes,
+			Liked:      liked[comment.ID],
+			ReplyCount: replyCounts[comment.ID],
+			CreatedAt:  comment.CreatedAt,
 		})
 	}
 
-	return &dto.GetCommentsResponse{
-		Total: int(count),
-		List:  commentList,
-	}, nil
+	return commentList, nil
+}
+
+// commentParentIDs 批量查询评论的父评论公开ID，key为父评论的内部ID
+func (s *postService) commentParentIDs(ctx context.Context, comments []model.PostComment) map[uint]string {
+	var parentIDs []uint
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			parentIDs = append(parentIDs, *comment.ParentID)
+		}
+	}
+	if len(parentIDs) == 0 {
+		return nil
+	}
+	return s.publicIDs.PublicIDs(ctx, constant.PublicIDComment, parentIDs)
+}
+
+// publicParentID 返回父评论的公开ID，一级评论或父评论的公开ID未查到时返回nil
+func publicParentID(parents map[uint]string, parentID *uint) *string {
+	if parentID == nil {
+		return nil
+	}
+	publicID, ok := parents[*parentID]
+	if !ok {
+		return nil
+	}
+	return &publicID
+}
+
+// toCommentImageInfo 转换评论图片信息，url为图片的CDN访问地址
+func toCommentImageInfo(url string, img *model.CommentImage) *dto.CommentImageInfo {
+	return &dto.CommentImageInfo{
+		URL:      url,
+		Width:    img.Width,
+		Height:   img.Height,
+		ThumbURL: thumbURL(url),
+	}
+}
+
+// LikeComment 点赞评论
+func (s *postService) LikeComment(ctx context.Context, req *dto.LikeCommentRequest, userID uint) error {
+	// 检查评论是否存在
+	comment, err := s.commentRepo.GetComment(ctx, req.CommentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("评论不存在")
+		}
+		return fmt.Errorf("查询评论失败: %w", err)
+	}
+
+	liked, err := s.likeRepo.LikeComment(ctx, req.CommentID, userID)
+	if err != nil {
+		return fmt.Errorf("点赞评论失败: %w", err)
+	}
+
+	var publicPostID string
+	if liked {
+		publicPostID = s.publicIDs.PublicID(ctx, constant.PublicIDPost, comment.PostID)
+		s.events.Publish(ctx, constant.EventCommentLiked, dto.CommentLikedData{
+			CommentID:       comment.ID,
+			PostID:          comment.PostID,
+			UserID:          userID,
+			PublicCommentID: comment.PublicID,
+			PublicPostID:    publicPostID,
+			PublicUserID:    s.publicIDs.PublicID(ctx, constant.PublicIDUser, userID),
+			CreatedAt:       time.Now(),
+		})
+	}
+
+	// 重复点赞和已匿名化的评论不通知
+	if liked && comment.UserID != 0 && comment.UserID != userID {
+		s.notifyAsync(ctx, comment.UserID, constant.NotificationEventCommentLike, &NotificationMessage{
+			Title:   "收到新的点赞",
+			Content: s.actorName(ctx, userID) + "赞了你的评论",
+			Data: map[string]string{
+				"event":      constant.NotificationEventCommentLike,
+				"post_id":    publicPostID,
+				"comment_id": comment.PublicID,
+			},
+		})
+	}
+
+	return nil
+}
+
+// UnlikeComment 取消点赞评论
+func (s *postService) UnlikeComment(ctx context.Context, req *dto.LikeCommentRequest, userID uint) error {
+	if _, err := s.likeRepo.UnlikeComment(ctx, req.CommentID, userID); err != nil {
+		return fmt.Errorf("取消点赞评论失败: %w", err)
+	}
+
+	return nil
+}
+
+// notifyComment 通知动态作者有新评论，回复评论时同时通知被回复的评论作者，不通知评论者自己
+func (s *postService) notifyComment(ctx context.Context, post *model.Post, comment *model.PostComment, nickname string) {
+	data := map[string]string{
+		"event":      constant.NotificationEventComment,
+		"post_id":    post.PublicID,
+		"comment_id": comment.PublicID,
+	}
+	summary := truncateRunes(comment.Content, constant.NotificationContentSummaryLength)
+	if nickname == "" {
+		nickname = "有用户"
+	}
+
+	var parentUserID uint
+	if comment.ParentID != nil {
+		if parent, err := s.commentRepo.GetComment(ctx, *comment.ParentID); err == nil {
+			parentUserID = parent.UserID
+		}
+	}
+	if parentUserID != 0 && parentUserID != comment.UserID {
+		s.notifyAsync(ctx, parentUserID, constant.NotificationEventComment, &NotificationMessage{
+			Title:   "收到新的回复",
+			Content: nickname + "回复了你的评论：" + summary,
+			Data:    data,
+		})
+	}
+
+	if post.UserID != comment.UserID && post.UserID != parentUserID {
+		s.notifyAsync(ctx, post.UserID, constant.NotificationEventComment, &NotificationMessage{
+			Title:   "收到新的评论",
+			Content: nickname + "评论了你的动态：" + summary,
+			Data:    data,
+		})
+	}
+}
+
+// actorName 返回触发通知的用户昵称，查询失败时使用通用称呼
+func (s *postService) actorName(ctx context.Context, userID uint) string {
+	if user, err := s.userRepo.FindByID(ctx, userID); err == nil && user.Nickname != "" {
+		return user.Nickname
+	}
+	return "有用户"
+}
+
+// notifyAsync 在后台按用户的通知设置发送通知，不影响当前请求
+func (s *postService) notifyAsync(ctx context.Context, userID uint, event string, msg *NotificationMessage) {
+	// 后台协程使用脱离请求生命周期的上下文，保留请求ID便于关联日志
+	ctx = logger.Detach(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, constant.NotificationDispatchTimeout)
+		defer cancel()
+
+		s.notifier.Dispatch(ctx, userID, event, msg)
+	}()
+}
+
+// RecordView 记录动态浏览，同一访客每天只计一次
+// viewer为访客标识，登录用户为用户ID，匿名访客为IP和User-Agent组合哈希
+func (s *postService) RecordView(ctx context.Context, postID uint, viewer string) error {
+	now := time.Now()
+	key := cachekey.PostViews(now, postID)
+	if _, err := redis.PFAdd(key.String(), viewer); err != nil {
+		return fmt.Errorf("记录浏览失败: %w", err)
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+
+	dirtyKey := cachekey.PostViewsDirty(now)
+	if _, err := redis.SAdd(dirtyKey.String(), postID); err != nil {
+		return fmt.Errorf("记录待同步动态失败: %w", err)
+	}
+	_, _ = redis.Expire(dirtyKey.String(), dirtyKey.TTL())
+
+	// 只有浏览的动态也需要汇总每日数据
+	markPostInsightDirty(ctx, now, postID)
+
+	return nil
+}
+
+// RecordClientView 记录客户端上报的动态浏览
+// 上报接口无需登录且接受任意ID，先确认查看者可见该动态，避免不存在的ID被写入待同步集合使其无限增长，
+// 也避免通过上报结果探测不可见动态是否存在；viewerID为0表示匿名访客，作者浏览自己的动态不计入
+func (s *postService) RecordClientView(ctx context.Context, postID, viewerID uint, viewer string) error {
+	post, err := s.postRepo.GetVisiblePost(ctx, postID, viewerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPostNotFound
+		}
+		return fmt.Errorf("查询动态失败: %w", err)
+	}
+	if viewerID != 0 && post.UserID == viewerID {
+		return nil
+	}
+	return s.RecordView(ctx, postID, viewer)
+}
+
+// FlushViews 将指定日期的去重浏览数同步到数据库，由定时任务调用
+// 同步成功的动态会从待同步集合中移除，避免重复累加
+func (s *postService) FlushViews(ctx context.Context, date time.Time) (int, error) {
+	day := date.Format(constant.PostViewKeyDateLayout)
+	dirtyKey := cachekey.PostViewsDirty(date).String()
+
+	members, err := redis.SMembers(dirtyKey)
+	if err != nil {
+		return 0, fmt.Errorf("获取待同步动态失败: %w", err)
+	}
+
+	flushed := 0
+	for _, member := range members {
+		postID, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			_, _ = redis.SRem(dirtyKey, member)
+			continue
+		}
+
+		views, err := redis.PFCount(cachekey.PostViews(date, uint(postID)).String())
+		if err != nil {
+			return flushed, fmt.Errorf("统计动态浏览数失败: %w", err)
+		}
+
+		if views > 0 {
+			if err := s.postRepo.AddPostViews(ctx, uint(postID), views); err != nil {
+				return flushed, fmt.Errorf("更新动态浏览数失败: %w", err)
+			}
+		}
+		if _, err := redis.SRem(dirtyKey, member); err != nil {
+			return flushed, fmt.Errorf("移除已同步动态失败: %w", err)
+		}
+		flushed++
+	}
+
+	logger.Info(ctx, "动态浏览数同步完成", logger.String("date", day), logger.Int("posts", flushed))
+
+	return flushed, nil
+}
+
+// pendingViews 获取动态尚未同步到数据库的去重浏览数
+// 包含当天和回溯天数内仍在待同步集合中的日期，避免前一天的浏览数在同步前从详情中消失
+func (s *postService) pendingViews(postID uint) int64 {
+	now := time.Now()
+	var total int64
+	for i := 0; i <= constant.PostViewFlushDays; i++ {
+		day := now.AddDate(0, 0, -i)
+		// 已同步的日期浏览数已计入数据库，不再重复累加
+		if i > 0 {
+			dirty, err := redis.SIsMember(cachekey.PostViewsDirty(day).String(), postID)
+			if err != nil || !dirty {
+				continue
+			}
+		}
+		views, err := redis.PFCount(cachekey.PostViews(day, postID).String())
+		if err != nil {
+			continue
+		}
+		total += views
+	}
+	return total
+}
+
+// getRecommendedPosts 按推荐得分分页获取关注用户的动态
+// 排序结果缓存一段时间，保证翻页期间顺序稳定
+func (s *postService) getRecommendedPosts(ctx context.Context, userID uint, page, size int) ([]model.Post, int64, error) {
+	postIDs, ok := ranking.GetCachedFeed(userID)
+	if !ok || page == 1 {
+		var err error
+		postIDs, err = s.rankFollowingPosts(ctx, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := ranking.CacheFeed(userID, postIDs); err != nil {
+			logger.Warn(ctx, "缓存推荐排序结果失败", logger.Err(err))
+		}
+	}
+
+	return s.pagePostsByIDs(ctx, postIDs, userID, page, size)
+}
+
+// getDiscoverPosts 分页获取发现页动态
+// 混合结果缓存一段时间，保证翻页期间顺序稳定，请求第一页时重新混合
+func (s *postService) getDiscoverPosts(ctx context.Context, req *FeedRequest, page, size int) ([]model.Post, int64, error) {
+	postIDs, ok := ranking.GetCachedDiscoverFeed(req.UserID)
+	if !ok || page == 1 {
+		postIDs = s.discover.Mix(ctx, req)
+		if err := ranking.CacheDiscoverFeed(req.UserID, postIDs); err != nil {
+			logger.Warn(ctx, "缓存发现页混合结果失败", logger.Err(err))
+		}
+	}
+
+	return s.pagePostsByIDs(ctx, postIDs, req.UserID, page, size)
+}
+
+// pagePostsByIDs 按给定的动态ID顺序分页查询查看者的动态，已删除或作者屏蔽了查看者的动态会被跳过
+func (s *postService) pagePostsByIDs(ctx context.Context, postIDs []uint, viewerID uint, page, size int) ([]model.Post, int64, error) {
+	total := int64(len(postIDs))
+	start := (page - 1) * size
+	if start >= len(postIDs) {
+		return []model.Post{}, total, nil
+	}
+	end := start + size
+	if end > len(postIDs) {
+		end = len(postIDs)
+	}
+	pageIDs := postIDs[start:end]
+
+	posts, err := s.postRepo.GetPostsByIDs(ctx, pageIDs, viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 按排序结果重新排列，已删除的动态会被跳过
+	postMap := make(map[uint]model.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	ordered := make([]model.Post, 0, len(pageIDs))
+	for _, id := range pageIDs {
+		if post, ok := postMap[id]; ok {
+			ordered = append(ordered, post)
+		}
+	}
+
+	return ordered, total, nil
+}
+
+// rankFollowingPosts 对关注用户的最新动态计算推荐得分并排序
+func (s *postService) rankFollowingPosts(ctx context.Context, userID uint) ([]uint, error) {
+	posts, _, err := s.postRepo.GetFollowingPosts(ctx, userID, 1, constant.FeedCandidateSize)
+	if err != nil {
+		return nil, err
+	}
+
+	affinities, ok, err := ranking.LoadAffinity(userID)
+	if err != nil || !ok {
+		// 缓存缺失时即时计算
+		affinities, err = s.refreshAffinity(ctx, userID)
+		if err != nil {
+			logger.Warn(ctx, "计算作者亲密度失败，按无亲密度排序", logger.Err(err))
+			affinities = map[uint]float64{}
+		}
+	}
+
+	candidates := make([]ranking.Candidate, 0, len(posts))
+	for _, post := range posts {
+		candidates = append(candidates, ranking.Candidate{
+			PostID:    post.ID,
+			AuthorID:  post.UserID,
+			Likes:     post.Likes,
+			Comments:  post.Comments,
+			Views:     post.Views,
+			CreatedAt: post.CreatedAt,
+		})
+	}
+
+	return ranking.Rank(candidates, affinities, time.Now()), nil
+}
+
+// RefreshAffinities 刷新近期活跃用户的作者亲密度特征，由定时任务调用
+func (s *postService) RefreshAffinities(ctx context.Context) (int, error) {
+	since := time.Now().Add(-constant.FeedActiveUserWindow)
+	refreshed := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return refreshed, err
+		}
+
+		ids, err := s.userRepo.FindActiveUserIDs(ctx, since, afterID, constant.FeedAffinityBatchSize)
+		if err != nil {
+			return refreshed, fmt.Errorf("查询活跃用户失败: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		for _, id := range ids {
+			if _, err := s.refreshAffinity(ctx, id); err != nil {
+				logger.Warn(ctx, "刷新作者亲密度失败", logger.Uint("user_id", id), logger.Err(err))
+				continue
+			}
+			refreshed++
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	logger.Info(ctx, "作者亲密度刷新完成", logger.Int("users", refreshed))
+
+	return refreshed, nil
+}
+
+// refreshAffinity 根据近期互动次数计算并缓存用户对各作者的亲密度
+func (s *postService) refreshAffinity(ctx context.Context, userID uint) (map[uint]float64, error) {
+	counts, err := s.commentRepo.CountCommentsByAuthor(ctx, userID, time.Now().Add(-constant.FeedAffinityWindow))
+	if err != nil {
+		return nil, err
+	}
+	if err := ranking.SaveAffinity(userID, counts); err != nil {
+		return nil, err
+	}
+
+	affinities := make(map[uint]float64, len(counts))
+	for authorID, count := range counts {
+		affinities[authorID] = ranking.AffinityFromCount(count)
+	}
+	return affinities, nil
 }
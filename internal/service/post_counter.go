@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// incrPostCounterScript 追加计数日志并累加未同步增量，两步在同一脚本中执行，日志与增量始终一致
+// KEYS[1]为日志Stream，KEYS[2]为增量哈希；ARGV依次为动态ID、计数名称、增量和哈希字段
+const incrPostCounterScript = `
+redis.call('XADD', KEYS[1], '*', 'post_id', ARGV[1], 'counter', ARGV[2], 'delta', ARGV[3])
+return redis.call('HINCRBY', KEYS[2], ARGV[4], ARGV[3])
+`
+
+// settlePostCounterScript 从增量哈希中扣减已同步到数据库的增量，并记录扣减到的日志位置
+// KEYS[1]为增量哈希，KEYS[2]为扣减位置；ARGV[1]为日志位置，其后依次为哈希字段和增量
+const settlePostCounterScript = `
+for i = 2, #ARGV, 2 do
+	if redis.call('HINCRBY', KEYS[1], ARGV[i], -tonumber(ARGV[i + 1])) == 0 then
+		redis.call('HDEL', KEYS[1], ARGV[i])
+	end
+end
+redis.call('SET', KEYS[2], ARGV[1])
+return 1
+`
+
+// PostCounterService 动态计数写回服务接口
+// 点赞数和评论数先在Redis中累加，同时写入计数日志，由定时任务批量同步到数据库
+type PostCounterService interface {
+	// Incr 累加动态计数，counter为constant.PostCounterLikes或constant.PostCounterComments
+	Incr(ctx context.Context, postID uint, counter string, delta int64) error
+	// Pending 获取动态尚未同步到数据库的点赞数和评论数增量，读取失败时返回零值
+	Pending(ctx context.Context, postID uint) (likes, comments int64)
+	// PendingBatch 批量获取动态某项计数尚未同步到数据库的增量，只返回增量不为0的动态
+	PendingBatch(ctx context.Context, counter string, postIDs []uint) (map[uint]int64, error)
+	// Flush 将计数日志中尚未同步的增量批量同步到数据库，返回同步的日志条数，由定时任务调用
+	Flush(ctx context.Context) (int, error)
+}
+
+// postCounterService 动态计数写回服务实现
+type postCounterService struct {
+	counterRepo repository.CounterRepository
+}
+
+// NewPostCounterService 创建动态计数写回服务实例
+func NewPostCounterService(counterRepo repository.CounterRepository) PostCounterService {
+	return &postCounterService{counterRepo: counterRepo}
+}
+
+// Incr 追加计数日志并累加未同步增量
+func (s *postCounterService) Incr(ctx context.Context, postID uint, counter string, delta int64) error {
+	keys := []string{cachekey.PostCounterLog().String(), cachekey.PostCounterPending().String()}
+	if _, err := redis.Eval(incrPostCounterScript, keys, postID, counter, delta, pendingField(postID, counter)); err != nil {
+		return fmt.Errorf("累加动态计数失败: %w", err)
+	}
+	return nil
+}
+
+// Pending 获取动态尚未同步到数据库的点赞数和评论数增量
+func (s *postCounterService) Pending(ctx context.Context, postID uint) (likes, comments int64) {
+	values, err := redis.HMGet(cachekey.PostCounterPending().String(),
+		pendingField(postID, constant.PostCounterLikes), pendingField(postID, constant.PostCounterComments))
+	if err != nil || len(values) != 2 {
+		return 0, 0
+	}
+	return parseCount(values[0]), parseCount(values[1])
+}
+
+// PendingBatch 批量获取动态某项计数尚未同步到数据库的增量
+func (s *postCounterService) PendingBatch(ctx context.Context, counter string, postIDs []uint) (map[uint]int64, error) {
+	pending := make(map[uint]int64)
+	if len(postIDs) == 0 {
+		return pending, nil
+	}
+
+	fields := make([]string, len(postIDs))
+	for i, postID := range postIDs {
+		fields[i] = pendingField(postID, counter)
+	}
+	values, err := redis.HMGet(cachekey.PostCounterPending().String(), fields...)
+	if err != nil {
+		return nil, fmt.Errorf("获取未同步的动态计数失败: %w", err)
+	}
+	for i, value := range values {
+		if delta := parseCount(value); delta != 0 {
+			pending[postIDs[i]] = delta
+		}
+	}
+	return pending, nil
+}
+
+// Flush 将计数日志中尚未同步的增量批量同步到数据库
+// 每批先在一个事务中累加计数并保存同步位置，再从增量哈希中扣减并删除已同步的日志
+// 任一步骤中断时，下次执行按数据库中的同步位置继续，不会重复或遗漏增量
+func (s *postCounterService) Flush(ctx context.Context) (int, error) {
+	position, err := s.counterRepo.GetCheckpoint(ctx, constant.PostCounterCheckpoint)
+	if err != nil {
+		return 0, fmt.Errorf("获取计数同步位置失败: %w", err)
+	}
+
+	// 上次同步数据库后未完成扣减时，先按日志补扣
+	if err := s.settle(ctx, position); err != nil {
+		return 0, err
+	}
+
+	flushed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return flushed, err
+		}
+
+		entries, err := redis.XRangeN(cachekey.PostCounterLog().String(), afterPosition(position), "+", constant.PostCounterFlushBatchSize)
+		if err != nil {
+			return flushed, fmt.Errorf("读取计数日志失败: %w", err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		last := entries[len(entries)-1].ID
+		batch := aggregatePostCounters(ctx, entries)
+		if err := s.counterRepo.ApplyPostCounterDeltas(ctx, constant.PostCounterCheckpoint, last, batch.deltas()); err != nil {
+			return flushed, fmt.Errorf("同步动态计数失败: %w", err)
+		}
+		position = last
+
+		if err := s.deduct(batch, last); err != nil {
+			return flushed, err
+		}
+		// 保留最后一条日志之后的部分，已同步的日志不再需要
+		if _, err := redis.XTrimMinID(cachekey.PostCounterLog().String(), last); err != nil {
+			logger.Warn(ctx, "删除已同步的计数日志失败", logger.Err(err))
+		}
+		flushed += len(entries)
+
+		if len(entries) < constant.PostCounterFlushBatchSize {
+			break
+		}
+	}
+
+	if flushed > 0 {
+		logger.Info(ctx, "动态计数同步完成", logger.Int("entries", flushed), logger.String("position", position))
+	}
+	return flushed, nil
+}
+
+// settle 从增量哈希中补扣已同步到数据库但尚未扣减的日志，即扣减位置之后、同步位置及之前的日志
+func (s *postCounterService) settle(ctx context.Context, position string) error {
+	if position == "" {
+		return nil
+	}
+
+	settled, err := redis.Get(cachekey.PostCounterSettled().String())
+	if err != nil && !errors.Is(err, redis.ErrKeyNotFound) {
+		return fmt.Errorf("获取计数扣减位置失败: %w", err)
+	}
+	if settled != "" && compareStreamID(settled, position) >= 0 {
+		return nil
+	}
+
+	start := "-"
+	if settled != "" {
+		start = afterPosition(settled)
+	}
+	entries, err := redis.XRange(cachekey.PostCounterLog().String(), start, position)
+	if err != nil {
+		return fmt.Errorf("读取计数日志失败: %w", err)
+	}
+
+	logger.Warn(ctx, "上次计数同步未完成，补扣已同步的增量", logger.String("settled", settled), logger.String("position", position), logger.Int("entries", len(entries)))
+	return s.deduct(aggregatePostCounters(ctx, entries), position)
+}
+
+// deduct 从增量哈希中扣减已同步的增量，并将扣减位置更新为position
+func (s *postCounterService) deduct(batch postCounterBatch, position string) error {
+	args := make([]interface{}, 0, 1+2*len(batch.fields))
+	args = append(args, position)
+	for field, delta := range batch.fields {
+		args = append(args, field, delta)
+	}
+
+	keys := []string{cachekey.PostCounterPending().String(), cachekey.PostCounterSettled().String()}
+	if _, err := redis.Eval(settlePostCounterScript, keys, args...); err != nil {
+		return fmt.Errorf("扣减已同步的动态计数失败: %w", err)
+	}
+	return nil
+}
+
+// postCounterBatch 一批计数日志按动态和计数名称汇总后的增量
+type postCounterBatch struct {
+	fields map[string]int64 // 增量哈希字段对应的增量
+	posts  map[uint]*repository.PostCounterDelta
+}
+
+// deltas 返回按动态汇总的增量
+func (b postCounterBatch) deltas() []repository.PostCounterDelta {
+	deltas := make([]repository.PostCounterDelta, 0, len(b.posts))
+	for _, delta := range b.posts {
+		deltas = append(deltas, *delta)
+	}
+	return deltas
+}
+
+// aggregatePostCounters 汇总一批计数日志，无法解析的日志记录后跳过
+func aggregatePostCounters(ctx context.Context, entries []goredis.XMessage) postCounterBatch {
+	batch := postCounterBatch{
+		fields: make(map[string]int64),
+		posts:  make(map[uint]*repository.PostCounterDelta),
+	}
+	for _, entry := range entries {
+		postID, err1 := strconv.ParseUint(fmt.Sprint(entry.Values["post_id"]), 10, 64)
+		delta, err2 := strconv.ParseInt(fmt.Sprint(entry.Values["delta"]), 10, 64)
+		counter := fmt.Sprint(entry.Values["counter"])
+		if err1 != nil || err2 != nil || (counter != constant.PostCounterLikes && counter != constant.PostCounterComments) {
+			logger.Warn(ctx, "跳过无法解析的计数日志", logger.String("id", entry.ID))
+			continue
+		}
+
+		batch.fields[pendingField(uint(postID), counter)] += delta
+		post, ok := batch.posts[uint(postID)]
+		if !ok {
+			post = &repository.PostCounterDelta{PostID: uint(postID)}
+			batch.posts[uint(postID)] = post
+		}
+		if counter == constant.PostCounterLikes {
+			post.Likes += delta
+		} else {
+			post.Comments += delta
+		}
+	}
+	return batch
+}
+
+// pendingField 增量哈希中动态某项计数对应的字段
+func pendingField(postID uint, counter string) string {
+	return strconv.FormatUint(uint64(postID), 10) + ":" + counter
+}
+
+// parseCount 解析HMGET返回的计数值，字段不存在或格式错误时返回0
+func parseCount(value interface{}) int64 {
+	s, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// afterPosition 返回XRANGE从指定日志ID之后（不含）开始读取的起始参数，未同步过时从头读取
+func afterPosition(position string) string {
+	if position == "" {
+		return "-"
+	}
+	return "(" + position
+}
+
+// compareStreamID 比较两个Stream消息ID（毫秒时间戳-序号）的先后
+func compareStreamID(a, b string) int {
+	aMs, aSeq := splitStreamID(a)
+	bMs, bSeq := splitStreamID(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq != bSeq:
+		if aSeq < bSeq {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// splitStreamID 拆分Stream消息ID为毫秒时间戳和序号
+func splitStreamID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	msValue, _ := strconv.ParseUint(ms, 10, 64)
+	seqValue, _ := strconv.ParseUint(seq, 10, 64)
+	return msValue, seqValue
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+)
+
+// ErrPostImagesFailed 创建动态时有图片关联失败，动态未创建
+var ErrPostImagesFailed = errors.New("关联动态图片失败")
+
+// PostImagesError 创建动态时图片关联失败的详情，动态和已复制的图片均已回滚
+type PostImagesError struct {
+	Results []dto.PostImageResult // 各图片的关联结果
+	Err     error                 // 导致失败的错误
+}
+
+// Error 返回错误信息
+func (e *PostImagesError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrPostImagesFailed, e.Err)
+}
+
+// Is 使errors.Is(err, ErrPostImagesFailed)成立
+func (e *PostImagesError) Is(target error) bool {
+	return target == ErrPostImagesFailed
+}
+
+// Unwrap 返回导致失败的错误
+func (e *PostImagesError) Unwrap() error {
+	return e.Err
+}
+
+// createPostWithImages 在事务中创建动态，并将临时图片复制到动态图片目录后保存图片记录
+// 复制图片或保存记录失败时事务回滚，并删除已复制的图片文件；成功后删除临时图片
+func (s *postService) createPostWithImages(ctx context.Context, post *model.Post, temps []*model.TempImage) ([]model.PostImage, error) {
+	var images []model.PostImage
+	var copyErr error
+	err := s.postRepo.CreatePostWithImages(ctx, post, func(postID uint) ([]model.PostImage, error) {
+		images, copyErr = s.imageService.CopyImagesToPost(ctx, temps, postID)
+		return images, copyErr
+	})
+	if err != nil {
+		// 动态已回滚，删除已复制到动态目录的文件
+		s.imageService.DiscardPostImages(ctx, images)
+		if copyErr != nil {
+			return nil, &PostImagesError{Results: postImageResults(temps, make([]string, len(images)), copyErr), Err: copyErr}
+		}
+		return nil, fmt.Errorf("创建动态失败: %w", err)
+	}
+
+	s.imageService.ReleaseTempImages(ctx, temps)
+	return images, nil
+}
+
+// postImageResults 构建各图片的关联结果
+// attached为已复制的图片地址，顺序与temps一致；failure为空时已复制的图片均已关联，否则已复制的图片已回滚，
+// 其后的一张图片关联失败，其余图片未处理
+func postImageResults(temps []*model.TempImage, attached []string, failure error) []dto.PostImageResult {
+	results := make([]dto.PostImageResult, len(temps))
+	for i, tempImage := range temps {
+		result := dto.PostImageResult{ImageID: tempImage.ID}
+		switch {
+		case i < len(attached) && failure == nil:
+			result.Status = constant.PostImageStatusAttached
+			result.URL = attached[i]
+		case i < len(attached):
+			result.Status = constant.PostImageStatusRolledBack
+		case i == len(attached) && failure != nil:
+			result.Status = constant.PostImageStatusFailed
+			result.Error = failure.Error()
+		default:
+			result.Status = constant.PostImageStatusSkipped
+		}
+		results[i] = result
+	}
+	return results
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"gorm.io/gorm"
+)
+
+// PostInsightService 动态数据分析服务接口
+// 浏览数沿用动态浏览统计的去重数据，点赞、评论、分享和带来的关注数订阅平台事件按天记录在Redis中，由定时任务汇总到post_stats表
+type PostInsightService interface {
+	// Publish 接收平台事件，按天记录动态的互动计数
+	Publish(ctx context.Context, event string, data interface{})
+	// Aggregate 汇总指定日期的动态每日数据并保存，返回汇总的动态数，由定时任务调用
+	Aggregate(ctx context.Context, date time.Time) (int, error)
+	// GetInsights 获取当前用户的动态在日期范围内的每日数据，当天的数据在次日汇总后才能查询
+	GetInsights(ctx context.Context, req *dto.GetPostInsightsRequest, userID uint) (*dto.GetPostInsightsResponse, error)
+}
+
+// postInsightService 动态数据分析服务实现
+type postInsightService struct {
+	statsRepo repository.PostStatsRepository
+	postRepo  repository.PostRepository
+	publicIDs PublicIDService
+}
+
+// NewPostInsightService 创建动态数据分析服务实例
+func NewPostInsightService(statsRepo repository.PostStatsRepository, postRepo repository.PostRepository, publicIDs PublicIDService) PostInsightService {
+	return &postInsightService{
+		statsRepo: statsRepo,
+		postRepo:  postRepo,
+		publicIDs: publicIDs,
+	}
+}
+
+// Publish 接收平台事件，按天记录动态的互动计数，记录失败时只记录日志
+func (s *postInsightService) Publish(ctx context.Context, event string, data interface{}) {
+	switch d := data.(type) {
+	case dto.PostLikedData:
+		s.record(ctx, d.CreatedAt, d.PostID, constant.PostInsightLikes)
+	case dto.WebhookCommentData:
+		s.record(ctx, d.CreatedAt, d.PostID, constant.PostInsightComments)
+	case dto.PostSharedData:
+		s.record(ctx, d.CreatedAt, d.PostID, constant.PostInsightShares)
+	case dto.UserFollowedData:
+		if d.SourcePostID == 0 {
+			return
+		}
+		// 只统计关注的是来源动态作者的情况
+		post, err := s.postRepo.GetPost(ctx, d.SourcePostID)
+		if err != nil || post.UserID != d.TargetID {
+			return
+		}
+		s.record(ctx, d.CreatedAt, post.ID, constant.PostInsightFollowerGains)
+	}
+}
+
+// record 将动态当天的指定计数加一
+func (s *postInsightService) record(ctx context.Context, at time.Time, postID uint, field string) {
+	key := cachekey.PostInsights(at, postID)
+	if _, err := redis.HIncrBy(key.String(), field, 1); err != nil {
+		logger.Warn(ctx, "记录动态互动计数失败", logger.Uint("post_id", postID), logger.String("field", field), logger.Err(err))
+		return
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+
+	markPostInsightDirty(ctx, at, postID)
+}
+
+// markPostInsightDirty 将动态加入当天待汇总的集合，记录失败时只记录日志
+func markPostInsightDirty(ctx context.Context, day time.Time, postID uint) {
+	key := cachekey.PostInsightsDirty(day)
+	if _, err := redis.SAdd(key.String(), postID); err != nil {
+		logger.Warn(ctx, "记录待汇总动态失败", logger.Uint("post_id", postID), logger.Err(err))
+		return
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+}
+
+// Aggregate 汇总指定日期的动态每日数据并保存
+// 每次写入当日的完整数值，待汇总集合保留到过期，重复汇总同一日期结果不变
+func (s *postInsightService) Aggregate(ctx context.Context, date time.Time) (int, error) {
+	day := truncateToDay(date)
+	members, err := redis.SMembers(cachekey.PostInsightsDirty(day).String())
+	if err != nil {
+		return 0, fmt.Errorf("获取待汇总动态失败: %w", err)
+	}
+
+	postIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		if id, err := strconv.ParseUint(member, 10, 64); err == nil {
+			postIDs = append(postIDs, uint(id))
+		}
+	}
+
+	saved := 0
+	for start := 0; start < len(postIDs); start += constant.PostInsightBatchSize {
+		end := min(start+constant.PostInsightBatchSize, len(postIDs))
+		// 已删除或作者已注销的动态不再汇总
+		posts, err := s.postRepo.GetPostsByIDs(ctx, postIDs[start:end])
+		if err != nil {
+			return saved, fmt.Errorf("查询动态失败: %w", err)
+		}
+
+		stats := make([]model.PostStats, 0, len(posts))
+		for _, post := range posts {
+			item, err := collectPostStats(day, &post)
+			if err != nil {
+				return saved, err
+			}
+			stats = append(stats, item)
+		}
+		if err := s.statsRepo.SavePostStats(ctx, stats); err != nil {
+			return saved, fmt.Errorf("保存动态每日数据失败: %w", err)
+		}
+		saved += len(stats)
+	}
+
+	logger.Info(ctx, "动态每日数据汇总完成", logger.String("date", day.Format(constant.StatsDateLayout)), logger.Int("posts", saved))
+	return saved, nil
+}
+
+// collectPostStats 从Redis读取动态当日的浏览数和互动计数
+func collectPostStats(day time.Time, post *model.Post) (model.PostStats, error) {
+	views, err := redis.PFCount(cachekey.PostViews(day, post.ID).String())
+	if err != nil {
+		return model.PostStats{}, fmt.Errorf("统计动态浏览数失败: %w", err)
+	}
+	counts, err := redis.HGetAll(cachekey.PostInsights(day, post.ID).String())
+	if err != nil {
+		return model.PostStats{}, fmt.Errorf("读取动态互动计数失败: %w", err)
+	}
+
+	count := func(field string) int64 {
+		n, _ := strconv.ParseInt(counts[field], 10, 64)
+		return n
+	}
+	return model.PostStats{
+		PostID:        post.ID,
+		UserID:        post.UserID,
+		Date:          day,
+		Views:         views,
+		Likes:         count(constant.PostInsightLikes),
+		Comments:      count(constant.PostInsightComments),
+		Shares:        count(constant.PostInsightShares),
+		FollowerGains: count(constant.PostInsightFollowerGains),
+	}, nil
+}
+
+// GetInsights 获取当前用户的动态在日期范围内的每日数据
+func (s *postInsightService) GetInsights(ctx context.Context, req *dto.GetPostInsightsRequest, userID uint) (*dto.GetPostInsightsResponse, error) {
+	// 默认查询截至昨天的7天，当天的数据尚未汇总
+	startDate, endDate, err := parseStatsDateRange(req.StartDate, req.EndDate, truncateToDay(time.Now()).AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	// 指定动态时只能查询自己的动态
+	if req.PostID != 0 {
+		post, err := s.postRepo.GetPost(ctx, req.PostID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrPostNotFound
+			}
+			return nil, fmt.Errorf("查询动态失败: %w", err)
+		}
+		if post.UserID != userID {
+			return nil, ErrPostForbidden
+		}
+	}
+
+	rows, err := s.statsRepo.GetUserPostStats(ctx, userID, req.PostID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("获取动态数据失败: %w", err)
+	}
+
+	// 按动态分组，rows已按日期升序
+	items := make(map[uint]*dto.PostInsightItem)
+	var order []uint
+	for _, row := range rows {
+		item, ok := items[row.PostID]
+		if !ok {
+			item = &dto.PostInsightItem{}
+			items[row.PostID] = item
+			order = append(order, row.PostID)
+		}
+		metrics := toPostInsightMetrics(&row)
+		item.Daily = append(item.Daily, dto.PostInsightDay{
+			Date:               row.Date.Format(constant.StatsDateLayout),
+			PostInsightMetrics: metrics,
+		})
+		addPostInsightMetrics(&item.Summary, metrics)
+	}
+
+	publicIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDPost, order)
+	resp := &dto.GetPostInsightsResponse{
+		StartDate: startDate.Format(constant.StatsDateLayout),
+		EndDate:   endDate.Format(constant.StatsDateLayout),
+		Posts:     make([]dto.PostInsightItem, 0, len(order)),
+	}
+	for _, postID := range order {
+		item := items[postID]
+		item.PostID = publicIDs[postID]
+		addPostInsightMetrics(&resp.Summary, item.Summary)
+		resp.Posts = append(resp.Posts, *item)
+	}
+	sort.SliceStable(resp.Posts, func(i, j int) bool {
+		return resp.Posts[i].Summary.Views > resp.Posts[j].Summary.Views
+	})
+
+	return resp, nil
+}
+
+// toPostInsightMetrics 将每日数据记录转换为指标
+func toPostInsightMetrics(row *model.PostStats) dto.PostInsightMetrics {
+	return dto.PostInsightMetrics{
+		Views:         row.Views,
+		Likes:         row.Likes,
+		Comments:      row.Comments,
+		Shares:        row.Shares,
+		FollowerGains: row.FollowerGains,
+	}
+}
+
+// addPostInsightMetrics 将指标累加到合计中
+func addPostInsightMetrics(sum *dto.PostInsightMetrics, m dto.PostInsightMetrics) {
+	sum.Views += m.Views
+	sum.Likes += m.Likes
+	sum.Comments += m.Comments
+	sum.Shares += m.Shares
+	sum.FollowerGains += m.FollowerGains
+}
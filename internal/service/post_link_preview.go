@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/pkg/linkpreview"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// linkPreviewFor 查找或创建动态内容中第一个链接的预览记录，不含链接或未启用链接预览时返回nil
+// 创建记录失败只记录日志，不影响动态发布
+func (s *postService) linkPreviewFor(ctx context.Context, content string) *model.LinkPreview {
+	if s.linkPreviews == nil {
+		return nil
+	}
+	rawURL := linkpreview.ExtractURL(content)
+	if rawURL == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(rawURL))
+	preview, err := s.linkPreviewRepo.FindOrCreate(ctx, hex.EncodeToString(sum[:]), rawURL)
+	if err != nil {
+		logger.Warn(ctx, "创建链接预览失败", logger.String("url", rawURL), logger.Err(err))
+		return nil
+	}
+	return preview
+}
+
+// fetchLinkPreviewAsync 在后台抓取待抓取的链接预览并回写，抓取失败只记录日志
+func (s *postService) fetchLinkPreviewAsync(ctx context.Context, preview *model.LinkPreview) {
+	if s.linkPreviews == nil || preview == nil || preview.Status != constant.LinkPreviewPending {
+		return
+	}
+	p := *preview
+
+	// 后台协程使用脱离请求生命周期的上下文，保留请求ID便于关联日志
+	ctx = logger.Detach(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, constant.LinkPreviewFetchTimeout)
+		defer cancel()
+
+		if err := s.fetchLinkPreview(ctx, &p); err != nil {
+			logger.Warn(ctx, "抓取链接预览失败", logger.Uint("link_preview_id", p.ID), logger.String("url", p.URL), logger.Err(err))
+		}
+	}()
+}
+
+// retryFetchLinkPreview 重新提交超时仍待抓取的链接，同一链接在重试间隔内只提交一次
+func (s *postService) retryFetchLinkPreview(ctx context.Context, preview *model.LinkPreview) {
+	if s.linkPreviews == nil {
+		return
+	}
+	key := cachekey.LinkPreviewRetry(preview.ID)
+	if ok, err := redis.SetNX(key.String(), 1, key.TTL()); err != nil || !ok {
+		return
+	}
+	s.fetchLinkPreviewAsync(ctx, preview)
+}
+
+// fetchLinkPreview 抓取链接预览并回写数据库，网页不可访问或未提供预览信息时标记为失败，不再重试
+func (s *postService) fetchLinkPreview(ctx context.Context, preview *model.LinkPreview) error {
+	result, fetchErr := s.linkPreviews.Fetch(ctx, preview.URL)
+
+	now := time.Now()
+	preview.FetchedAt = &now
+	preview.Status = constant.LinkPreviewFailed
+	if fetchErr == nil {
+		preview.Status = constant.LinkPreviewSuccess
+		preview.Title = result.Title
+		preview.Description = result.Description
+		preview.ImageURL = result.Image
+		preview.SiteName = result.SiteName
+	}
+
+	// 请求超时可能是上下文已取消，使用脱离原上下文的新上下文回写结果，保留请求ID
+	writeCtx, cancel := context.WithTimeout(logger.Detach(ctx), constant.LinkPreviewFetchTimeout)
+	defer cancel()
+	if err := s.linkPreviewRepo.UpdatePreview(writeCtx, preview); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// loadLinkPreviews 批量获取动态的链接预览，返回动态ID到预览信息的映射，只包含抓取成功的预览
+// 超时仍待抓取的链接会重新提交后台抓取，本次不返回预览
+func (s *postService) loadLinkPreviews(ctx context.Context, posts []model.Post) map[uint]*dto.LinkPreviewInfo {
+	previews := make(map[uint]*dto.LinkPreviewInfo)
+
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		if post.LinkPreviewID != nil {
+			ids = append(ids, *post.LinkPreviewID)
+		}
+	}
+	if len(ids) == 0 {
+		return previews
+	}
+
+	records, err := s.linkPreviewRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		logger.Warn(ctx, "查询链接预览失败", logger.Err(err))
+		return previews
+	}
+
+	byID := make(map[uint]*dto.LinkPreviewInfo, len(records))
+	for i := range records {
+		record := &records[i]
+		switch record.Status {
+		case constant.LinkPreviewSuccess:
+			byID[record.ID] = &dto.LinkPreviewInfo{
+				URL:         record.URL,
+				Title:       record.Title,
+				Description: record.Description,
+				Image:       record.ImageURL,
+				SiteName:    record.SiteName,
+			}
+		case constant.LinkPreviewPending:
+			if time.Since(record.CreatedAt) > constant.LinkPreviewFetchTimeout {
+				s.retryFetchLinkPreview(ctx, record)
+			}
+		}
+	}
+	for _, post := range posts {
+		if post.LinkPreviewID != nil {
+			if info, ok := byID[*post.LinkPreviewID]; ok {
+				previews[post.ID] = info
+			}
+		}
+	}
+	return previews
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/model"
+	"app/pkg/geohash"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// ErrInvalidLocation 经纬度不完整或超出范围
+var ErrInvalidLocation = errors.New("位置信息无效")
+
+// createLocation 保存动态附带的经纬度，未附带位置时返回nil
+func (s *postService) createLocation(ctx context.Context, lat, lng *float64) (*model.Location, error) {
+	if lat == nil && lng == nil {
+		return nil, nil
+	}
+	if lat == nil || lng == nil || *lat < -90 || *lat > 90 || *lng < -180 || *lng > 180 {
+		return nil, ErrInvalidLocation
+	}
+
+	location := &model.Location{
+		Latitude:  *lat,
+		Longitude: *lng,
+		Geohash:   geohash.Encode(*lat, *lng, constant.LocationGeohashPrecision),
+	}
+	if err := s.locationRepo.CreateLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// resolveAddressAsync 在后台解析位置地址并回写，解析失败只记录日志，不影响动态发布
+func (s *postService) resolveAddressAsync(ctx context.Context, location model.Location) {
+	if s.geocoder == nil {
+		return
+	}
+
+	// 后台协程使用脱离请求生命周期的上下文，保留请求ID便于关联日志
+	ctx = logger.Detach(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, constant.GeocodeResolveTimeout)
+		defer cancel()
+
+		if _, err := s.resolveAddress(ctx, location); err != nil {
+			logger.Warn(ctx, "解析动态位置地址失败", logger.Uint("location_id", location.ID), logger.Err(err))
+		}
+	}()
+}
+
+// retryResolveAddress 重新提交解析失败的位置，同一位置在重试间隔内只提交一次
+func (s *postService) retryResolveAddress(ctx context.Context, location model.Location) {
+	if s.geocoder == nil {
+		return
+	}
+	key := cachekey.GeocodeRetry(location.ID)
+	if ok, err := redis.SetNX(key.String(), 1, key.TTL()); err != nil || !ok {
+		return
+	}
+	s.resolveAddressAsync(ctx, location)
+}
+
+// resolveAddress 解析位置地址并回写数据库
+func (s *postService) resolveAddress(ctx context.Context, location model.Location) (string, error) {
+	address, err := s.geocoder.ReverseGeocode(ctx, location.Latitude, location.Longitude)
+	if err != nil {
+		return "", err
+	}
+	if err := s.locationRepo.UpdateAddress(ctx, location.ID, address); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// loadAddresses 批量获取动态的位置地址，返回动态ID到地址的映射
+// 尚未解析出地址的位置会重新提交后台解析，本次返回空地址
+func (s *postService) loadAddresses(ctx context.Context, posts []model.Post) map[uint]string {
+	addresses := make(map[uint]string)
+
+	locationIDs := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		if post.LocationID != nil {
+			locationIDs = append(locationIDs, *post.LocationID)
+		}
+	}
+	if len(locationIDs) == 0 {
+		return addresses
+	}
+
+	locations, err := s.locationRepo.GetLocationsByIDs(ctx, locationIDs)
+	if err != nil {
+		logger.Warn(ctx, "查询动态位置失败", logger.Err(err))
+		return addresses
+	}
+
+	byID := make(map[uint]string, len(locations))
+	for _, location := range locations {
+		if location.Address == "" && time.Since(location.CreatedAt) > constant.GeocodeResolveTimeout {
+			s.retryResolveAddress(ctx, location)
+		}
+		byID[location.ID] = location.Address
+	}
+	for _, post := range posts {
+		if post.LocationID != nil {
+			addresses[post.ID] = byID[*post.LocationID]
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/repository"
+	"app/pkg/geohash"
+)
+
+// zoomPrecisions 地图缩放级别对应的聚合geohash长度，下标为缩放级别
+// 每级缩放比例尺减半，geohash每增加一位格子缩小4到8倍，按屏幕内约8格选择
+var zoomPrecisions = [...]int{1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 5, 6, 6, 7, 7, 8, 8, 8, 8}
+
+// GetNearbyPosts 获取地图范围内的公开动态，按geohash聚合为带数量的标记点
+func (s *postService) GetNearbyPosts(ctx context.Context, req *dto.GetNearbyPostsRequest, userID uint) (*dto.GetNearbyPostsResponse, error) {
+	radius := req.Radius
+	if radius <= 0 {
+		radius = constant.NearbyMapDefaultRadius
+	}
+	radius = min(radius, constant.NearbyMapMaxRadius)
+	box := geoBoxAround(*req.Latitude, *req.Longitude, radius)
+
+	precision := clusterPrecision(req.Zoom, radius)
+	prefixes := coverPrefixes(box, precision)
+	clusters, err := s.postRepo.GetNearbyClusters(ctx, userID, box, prefixes, precision,
+		time.Now().Add(-constant.NearbyMapWindow), constant.NearbyMapMaxClusters)
+	if err != nil {
+		return nil, fmt.Errorf("查询附近动态失败: %w", err)
+	}
+
+	// 只有一条动态的聚合点返回动态ID
+	var postIDs []uint
+	for _, cluster := range clusters {
+		if cluster.Count == 1 {
+			postIDs = append(postIDs, cluster.PostID)
+		}
+	}
+	publicIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDPost, postIDs)
+
+	resp := &dto.GetNearbyPostsResponse{
+		Precision: precision,
+		Clusters:  make([]dto.NearbyPostCluster, 0, len(clusters)),
+	}
+	for _, cluster := range clusters {
+		item := dto.NearbyPostCluster{
+			Geohash:   cluster.Geohash,
+			Latitude:  cluster.Latitude,
+			Longitude: cluster.Longitude,
+			Count:     cluster.Count,
+		}
+		if cluster.Count == 1 {
+			item.PostID = publicIDs[cluster.PostID]
+		}
+		resp.Clusters = append(resp.Clusters, item)
+	}
+	return resp, nil
+}
+
+// clusterPrecision 返回聚合使用的geohash长度
+// 指定缩放级别时按级别查表，否则取搜索范围直径上不超过NearbyMapGridCells格的最大长度
+func clusterPrecision(zoom int, radius float64) int {
+	if zoom > 0 {
+		return zoomPrecisions[min(zoom, len(zoomPrecisions)-1)]
+	}
+	precision := 1
+	for p := 2; p < constant.LocationGeohashPrecision; p++ {
+		latDeg, _ := geohash.CellSize(p)
+		if 2*radius/(latDeg*kmPerDegree) > constant.NearbyMapGridCells {
+			break
+		}
+		precision = p
+	}
+	return precision
+}
+
+// coverPrefixes 返回查询时匹配的geohash前缀
+// 从聚合长度开始逐位缩短，直到覆盖范围所需的前缀数不超过NearbyMapMaxCoverCells
+func coverPrefixes(box repository.GeoBox, precision int) []string {
+	bounds := geohash.Box{MinLat: box.MinLat, MaxLat: box.MaxLat, MinLng: box.MinLng, MaxLng: box.MaxLng}
+	for p := precision; p > 1; p-- {
+		// 先按格子大小估算数量，避免范围较大时枚举大量格子
+		latDeg, lngDeg := geohash.CellSize(p)
+		rows := math.Ceil((box.MaxLat-box.MinLat)/latDeg) + 1
+		cols := math.Ceil((box.MaxLng-box.MinLng)/lngDeg) + 1
+		if rows*cols > 4*constant.NearbyMapMaxCoverCells {
+			continue
+		}
+		if prefixes := geohash.Cover(bounds, p); len(prefixes) <= constant.NearbyMapMaxCoverCells {
+			return prefixes
+		}
+	}
+	return geohash.Cover(bounds, 1)
+}
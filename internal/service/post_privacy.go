@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/repository"
+)
+
+// 动态屏蔽相关错误
+var (
+	ErrPostExclusionSelf  = errors.New("不能屏蔽自己")
+	ErrPostExclusionLimit = errors.New("屏蔽的用户数量已达上限")
+)
+
+// PostPrivacyService 动态隐私设置服务接口
+// 屏蔽在查询动态时生效，被屏蔽的用户在动态列表、推荐、发现页和主页中都看不到作者的动态，作者不会收到任何提示
+type PostPrivacyService interface {
+	// SetDefaultVisibility 设置发布动态时未指定可见性使用的默认值，只影响之后发布的动态
+	SetDefaultVisibility(ctx context.Context, req *dto.SetDefaultVisibilityRequest, userID uint) error
+	// AddExclusions 不让指定的用户看自己的动态，已屏蔽的用户忽略
+	AddExclusions(ctx context.Context, req *dto.PostExclusionsRequest, userID uint) error
+	// RemoveExclusions 取消屏蔽
+	RemoveExclusions(ctx context.Context, req *dto.PostExclusionsRequest, userID uint) error
+	// GetExclusions 获取屏蔽的用户列表
+	GetExclusions(ctx context.Context, req *dto.GetPostExclusionsRequest, userID uint) (*dto.GetPostExclusionsResponse, error)
+}
+
+// postPrivacyService 动态隐私设置服务实现
+type postPrivacyService struct {
+	exclusionRepo repository.PostExclusionRepository
+	userRepo      repository.UserRepository
+}
+
+// NewPostPrivacyService 创建动态隐私设置服务实例
+func NewPostPrivacyService(exclusionRepo repository.PostExclusionRepository, userRepo repository.UserRepository) PostPrivacyService {
+	return &postPrivacyService{
+		exclusionRepo: exclusionRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// SetDefaultVisibility 设置发布动态的默认可见性
+func (s *postPrivacyService) SetDefaultVisibility(ctx context.Context, req *dto.SetDefaultVisibilityRequest, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if user.DefaultVisibility == req.Visibility {
+		return nil
+	}
+
+	user.DefaultVisibility = req.Visibility
+	return s.userRepo.Update(ctx, user)
+}
+
+// AddExclusions 不让指定的用户看自己的动态
+func (s *postPrivacyService) AddExclusions(ctx context.Context, req *dto.PostExclusionsRequest, userID uint) error {
+	targetIDs := uniqueIDs(req.UserIDs)
+	for _, id := range targetIDs {
+		if id == userID {
+			return ErrPostExclusionSelf
+		}
+	}
+
+	count, err := s.exclusionRepo.CountExclusions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("统计屏蔽用户失败: %w", err)
+	}
+	// 按最坏情况估算，已屏蔽的用户也计入
+	if count+int64(len(targetIDs)) > constant.PostExclusionMaxUsers {
+		return ErrPostExclusionLimit
+	}
+
+	// 只能屏蔽存在的用户，休眠的用户恢复活跃后仍受屏蔽
+	for _, id := range targetIDs {
+		if _, err := s.userRepo.FindByID(ctx, id); err != nil {
+			return ErrUserNotFound
+		}
+	}
+
+	if _, err := s.exclusionRepo.AddExclusions(ctx, userID, targetIDs); err != nil {
+		return fmt.Errorf("屏蔽用户失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveExclusions 取消屏蔽
+func (s *postPrivacyService) RemoveExclusions(ctx context.Context, req *dto.PostExclusionsRequest, userID uint) error {
+	if _, err := s.exclusionRepo.RemoveExclusions(ctx, userID, uniqueIDs(req.UserIDs)); err != nil {
+		return fmt.Errorf("取消屏蔽失败: %w", err)
+	}
+	return nil
+}
+
+// GetExclusions 获取屏蔽的用户列表，按屏蔽时间倒序
+func (s *postPrivacyService) GetExclusions(ctx context.Context, req *dto.GetPostExclusionsRequest, userID uint) (*dto.GetPostExclusionsResponse, error) {
+	exclusions, total, err := s.exclusionRepo.GetExclusions(ctx, userID, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取屏蔽用户失败: %w", err)
+	}
+
+	list := make([]dto.PostExclusionInfo, 0, len(exclusions))
+	for _, exclusion := range exclusions {
+		user, err := s.userRepo.FindByID(ctx, exclusion.TargetID)
+		if err != nil {
+			continue // 跳过获取失败的用户
+		}
+		list = append(list, dto.PostExclusionInfo{
+			UserBrief: dto.UserBrief{
+				ID:       user.PublicID,
+				Nickname: user.Nickname,
+				Avatar:   avatarURL(user),
+			},
+			ExcludedAt: exclusion.CreatedAt,
+		})
+	}
+
+	return &dto.GetPostExclusionsResponse{
+		Total:   int(total),
+		HasMore: int64(req.Page*req.Size) < total,
+		List:    list,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/internal/dto"
+	"app/pkg/translate"
+
+	"gorm.io/gorm"
+)
+
+// 翻译相关错误
+var (
+	// ErrTranslateUnavailable 未配置翻译服务
+	ErrTranslateUnavailable = errors.New("翻译服务暂不可用")
+	// ErrTranslateLanguage 不支持的目标语言
+	ErrTranslateLanguage = errors.New("不支持的目标语言")
+)
+
+// TranslatePost 将当前用户可见的动态内容翻译为目标语言
+// 动态语言与目标语言相同时直接返回原文，不请求翻译服务
+func (s *postService) TranslatePost(ctx context.Context, postID, userID uint, targetLang string) (*dto.TranslationResponse, error) {
+	post, err := s.postRepo.GetVisiblePost(ctx, postID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, fmt.Errorf("获取动态失败: %w", err)
+	}
+
+	return s.translate(ctx, post.Content, post.Language, userID, targetLang)
+}
+
+// TranslateComment 将评论内容翻译为目标语言，评论所在的动态对当前用户不可见时返回ErrCommentNotFound
+func (s *postService) TranslateComment(ctx context.Context, commentID, userID uint, targetLang string) (*dto.TranslationResponse, error) {
+	comment, err := s.commentRepo.GetComment(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("查询评论失败: %w", err)
+	}
+	if _, err := s.postRepo.GetVisiblePost(ctx, comment.PostID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("获取动态失败: %w", err)
+	}
+
+	return s.translate(ctx, comment.Content, translate.DetectLanguage(comment.Content), userID, targetLang)
+}
+
+// translate 校验目标语言和翻译频率后翻译内容，sourceLang为本地识别的原文语言
+func (s *postService) translate(ctx context.Context, content, sourceLang string, userID uint, targetLang string) (*dto.TranslationResponse, error) {
+	if s.translator == nil {
+		return nil, ErrTranslateUnavailable
+	}
+	target := translate.NormalizeLanguage(targetLang)
+	if target == "" {
+		return nil, ErrTranslateLanguage
+	}
+	if sourceLang == target {
+		return &dto.TranslationResponse{SourceLang: sourceLang, TargetLang: target, Text: content}, nil
+	}
+
+	if err := checkTranslateRate(userID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.translator.Translate(ctx, content, target)
+	if err != nil {
+		return nil, fmt.Errorf("翻译失败: %w", err)
+	}
+	if result.SourceLang != "" {
+		sourceLang = result.SourceLang
+	}
+
+	return &dto.TranslationResponse{
+		SourceLang: sourceLang,
+		TargetLang: target,
+		Text:       result.Text,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// ProfileService 用户主页服务接口
+type ProfileService interface {
+	// GetProfile 获取用户主页聚合信息，包括用户信息、计数、与查看者的关系和最近动态
+	GetProfile(ctx context.Context, userID, viewerID uint) (*dto.GetProfileResponse, error)
+}
+
+// profileService 用户主页服务实现
+type profileService struct {
+	userRepo     repository.UserRepository
+	followerRepo repository.UserFollowerRepository
+	friendRepo   repository.UserFriendRepository
+	posts        PostService
+	store        redis.Store
+}
+
+// NewProfileService 创建用户主页服务实例
+func NewProfileService(
+	userRepo repository.UserRepository,
+	followerRepo repository.UserFollowerRepository,
+	friendRepo repository.UserFriendRepository,
+	posts PostService,
+	store redis.Store,
+) ProfileService {
+	return &profileService{
+		userRepo:     userRepo,
+		followerRepo: followerRepo,
+		friendRepo:   friendRepo,
+		posts:        posts,
+		store:        store,
+	}
+}
+
+// profileCounts 缓存的主页粉丝数和关注数
+type profileCounts struct {
+	Followers int64 `json:"followers"`
+	Following int64 `json:"following"`
+}
+
+// GetProfile 获取用户主页聚合信息，禁用或注销中的用户视为不存在
+func (s *profileService) GetProfile(ctx context.Context, userID, viewerID uint) (*dto.GetProfileResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	if user.Status == constant.UserStatusDisabled || user.Status == constant.UserStatusDeactivating {
+		return nil, ErrUserNotFound
+	}
+
+	counts, err := s.loadCounts(ctx, userID, userID == viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, postCount, err := s.posts.GetRecentPosts(ctx, user, viewerID, constant.UserProfileRecentPosts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.GetProfileResponse{
+		User: dto.ProfileUser{
+			ID:        user.PublicID,
+			Username:  user.Username,
+			Nickname:  user.Nickname,
+			Avatar:    avatarURL(user),
+			IsPrivate: user.IsPrivate,
+			CreatedAt: user.CreatedAt,
+		},
+		Counts: dto.ProfileCounts{
+			Followers: counts.Followers,
+			Following: counts.Following,
+			Posts:     postCount,
+		},
+		RecentPosts: posts,
+	}
+
+	if userID != viewerID {
+		resp.Relationship, err = s.loadRelationship(ctx, user, viewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// loadCounts 获取粉丝数和关注数，优先读取缓存；fresh为true时跳过缓存，用于本人查看主页时立即看到变化
+func (s *profileService) loadCounts(ctx context.Context, userID uint, fresh bool) (*profileCounts, error) {
+	key := cachekey.UserProfileCounts(userID)
+	if !fresh {
+		var counts profileCounts
+		err := s.store.GetObj(key.String(), &counts)
+		if err == nil {
+			return &counts, nil
+		}
+		if !errors.Is(err, redis.ErrKeyNotFound) {
+			logger.Warn(ctx, "读取主页计数缓存失败", logger.Uint("user_id", userID), logger.Err(err))
+		}
+	}
+
+	followers, following, err := s.followerRepo.CountFollows(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("统计关注数失败: %w", err)
+	}
+	counts := &profileCounts{Followers: followers, Following: following}
+	if err := s.store.SetObj(key.String(), counts, key.TTL()); err != nil {
+		logger.Warn(ctx, "缓存主页计数失败", logger.Uint("user_id", userID), logger.Err(err))
+	}
+	return counts, nil
+}
+
+// loadRelationship 获取查看者与主页用户的关注和好友关系
+func (s *profileService) loadRelationship(ctx context.Context, user *model.User, viewerID uint) (*dto.ProfileRelationship, error) {
+	following, err := s.followerRepo.GetFollowStatuses(ctx, viewerID, []uint{user.ID})
+	if err != nil {
+		return nil, fmt.Errorf("查询关注状态失败: %w", err)
+	}
+	followedBy, err := s.followerRepo.GetFollowStatuses(ctx, user.ID, []uint{viewerID})
+	if err != nil {
+		return nil, fmt.Errorf("查询关注状态失败: %w", err)
+	}
+	friends, err := s.friendRepo.GetFriendStatuses(ctx, viewerID, []uint{user.ID})
+	if err != nil {
+		return nil, fmt.Errorf("查询好友状态失败: %w", err)
+	}
+
+	friendStatus, ok := friends[user.ID]
+	if !ok {
+		friendStatus = -1
+	}
+	return &dto.ProfileRelationship{
+		FollowStatus: following[user.ID],
+		FollowedBy:   followedBy[viewerID] == int(constant.FollowStatusApproved),
+		FriendStatus: friendStatus,
+	}, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// 主页访客相关错误
+var (
+	// ErrVisitsDisabled 未开启访问足迹，不能查看访客
+	ErrVisitsDisabled = errors.New("未开启访问足迹，不能查看访客记录")
+)
+
+// ProfileVisitService 主页访客服务接口
+type ProfileVisitService interface {
+	// RecordVisit 记录用户访问他人主页，同一访客一小时内重复访问同一主页只记录一次
+	RecordVisit(ctx context.Context, visitorID, visiteeID uint) error
+	// GetVisitors 获取最近30天访问过用户主页的访客，按最近访问时间倒序
+	GetVisitors(ctx context.Context, req *dto.GetVisitorsRequest, userID uint) (*dto.GetVisitorsResponse, error)
+	// GetVisited 获取用户最近30天访问过的主页，按最近访问时间倒序
+	GetVisited(ctx context.Context, req *dto.GetVisitorsRequest, userID uint) (*dto.GetVisitorsResponse, error)
+	// SetRecordVisits 设置是否记录主页访问足迹
+	SetRecordVisits(ctx context.Context, req *dto.SetRecordVisitsRequest, userID uint) error
+	// PurgeExpiredVisits 删除超过保留时间的访问记录，由定时任务调用，返回删除的数量
+	PurgeExpiredVisits(ctx context.Context) (int64, error)
+}
+
+// profileVisitService 主页访客服务实现
+type profileVisitService struct {
+	visitRepo repository.ProfileVisitRepository
+	userRepo  repository.UserRepository
+}
+
+// NewProfileVisitService 创建主页访客服务实例
+func NewProfileVisitService(visitRepo repository.ProfileVisitRepository, userRepo repository.UserRepository) ProfileVisitService {
+	return &profileVisitService{
+		visitRepo: visitRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// RecordVisit 记录用户访问他人主页
+// 访问自己的主页或访客关闭了访问足迹时不记录；先在Redis中占位去重，再写入数据库
+func (s *profileVisitService) RecordVisit(ctx context.Context, visitorID, visiteeID uint) error {
+	if visitorID == visiteeID {
+		return nil
+	}
+
+	visitee, err := s.userRepo.FindByID(ctx, visiteeID)
+	if err != nil || visitee.Status != constant.UserStatusNormal {
+		return ErrUserNotFound
+	}
+
+	visitor, err := s.userRepo.FindByID(ctx, visitorID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !visitor.RecordVisits {
+		return nil
+	}
+
+	key := cachekey.UserProfileVisit(visiteeID, visitorID)
+	ok, err := redis.SetNX(key.String(), 1, key.TTL())
+	if err != nil {
+		return fmt.Errorf("访问去重失败: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	if err := s.visitRepo.Create(ctx, &model.ProfileVisit{VisitorID: visitorID, VisiteeID: visiteeID}); err != nil {
+		// 写入失败时释放占位，下次访问可以重新记录
+		_, _ = redis.Del(key.String())
+		return fmt.Errorf("记录主页访问失败: %w", err)
+	}
+	return nil
+}
+
+// GetVisitors 获取最近30天访问过用户主页的访客
+// 只有开启了访问足迹的用户可以查看，关闭访问足迹的访客不会出现在列表中
+func (s *profileVisitService) GetVisitors(ctx context.Context, req *dto.GetVisitorsRequest, userID uint) (*dto.GetVisitorsResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.RecordVisits {
+		return nil, ErrVisitsDisabled
+	}
+
+	since := time.Now().Add(-constant.ProfileVisitRetention)
+	summaries, count, err := s.visitRepo.GetVisitors(ctx, userID, since, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取访客记录失败: %w", err)
+	}
+	return s.buildVisitorsResponse(ctx, req, summaries, count)
+}
+
+// GetVisited 获取用户最近30天访问过的主页
+func (s *profileVisitService) GetVisited(ctx context.Context, req *dto.GetVisitorsRequest, userID uint) (*dto.GetVisitorsResponse, error) {
+	since := time.Now().Add(-constant.ProfileVisitRetention)
+	summaries, count, err := s.visitRepo.GetVisited(ctx, userID, since, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("获取访问记录失败: %w", err)
+	}
+	return s.buildVisitorsResponse(ctx, req, summaries, count)
+}
+
+// buildVisitorsResponse 查询用户昵称和头像并组装访问记录列表
+func (s *profileVisitService) buildVisitorsResponse(ctx context.Context, req *dto.GetVisitorsRequest, summaries []repository.VisitSummary, count int64) (*dto.GetVisitorsResponse, error) {
+	ids := make([]uint, len(summaries))
+	for i, summary := range summaries {
+		ids[i] = summary.UserID
+	}
+	users, err := s.userRepo.FindNormalByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户信息失败: %w", err)
+	}
+	userIndex := make(map[uint]int, len(users))
+	for i := range users {
+		userIndex[users[i].ID] = i
+	}
+
+	list := make([]dto.ProfileVisitor, 0, len(summaries))
+	for _, summary := range summaries {
+		i, ok := userIndex[summary.UserID]
+		if !ok {
+			continue
+		}
+		list = append(list, dto.ProfileVisitor{
+			UserID:        users[i].PublicID,
+			Nickname:      users[i].Nickname,
+			Avatar:        avatarURL(&users[i]),
+			Visits:        summary.Visits,
+			LastVisitedAt: summary.LastVisitedAt,
+		})
+	}
+
+	return &dto.GetVisitorsResponse{
+		Total:   int(count),
+		HasMore: int64(req.Page*req.Size) < count,
+		List:    list,
+	}, nil
+}
+
+// SetRecordVisits 设置是否记录主页访问足迹
+func (s *profileVisitService) SetRecordVisits(ctx context.Context, req *dto.SetRecordVisitsRequest, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if user.RecordVisits == *req.Allow {
+		return nil
+	}
+
+	user.RecordVisits = *req.Allow
+	return s.userRepo.Update(ctx, user)
+}
+
+// PurgeExpiredVisits 分批删除超过保留时间的访问记录
+func (s *profileVisitService) PurgeExpiredVisits(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-constant.ProfileVisitRetention)
+	var purged int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+
+		deleted, err := s.visitRepo.DeleteBefore(ctx, before, constant.ProfileVisitPurgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("删除过期访问记录失败: %w", err)
+		}
+		purged += deleted
+		if deleted < constant.ProfileVisitPurgeBatchSize {
+			break
+		}
+	}
+
+	logger.Info(ctx, "过期访问记录清理完成", logger.Int64("deleted", purged))
+
+	return purged, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/repository"
+	"app/pkg/idgen"
+	"app/pkg/logger"
+)
+
+// 公开ID相关错误
+var (
+	// ErrInvalidID 客户端传入的ID格式错误，或过渡期结束后仍传入数字ID
+	ErrInvalidID = idgen.ErrInvalid
+	// ErrPublicIDNotFound 公开ID不存在
+	ErrPublicIDNotFound = errors.New("ID不存在")
+)
+
+// PublicIDService 公开ID服务接口
+// 接口中的用户、动态和评论只返回公开ID，内部仍使用自增ID关联；过渡期内客户端传入的数字ID按内部ID处理
+type PublicIDService interface {
+	// Resolve 将客户端传入的ID引用解析为内部ID，kind为constant.PublicIDUser等
+	// 格式错误时返回ErrInvalidID，公开ID不存在时返回ErrPublicIDNotFound；数字ID不检查是否存在，由业务查询决定
+	Resolve(ctx context.Context, kind string, ref idgen.Ref) (uint, error)
+	// ResolveAll 批量解析ID引用，返回的内部ID与refs一一对应，任一ID解析失败时返回错误
+	ResolveAll(ctx context.Context, kind string, refs []idgen.Ref) ([]uint, error)
+	// PublicIDs 批量查询内部ID对应的公开ID，查询失败时只记录日志，未查到的ID不在结果中
+	PublicIDs(ctx context.Context, kind string, ids []uint) map[uint]string
+	// PublicID 查询单个内部ID对应的公开ID，未查到时返回空字符串
+	PublicID(ctx context.Context, kind string, id uint) string
+}
+
+// publicIDService 公开ID服务实现
+type publicIDService struct {
+	repo repository.PublicIDRepository
+}
+
+// NewPublicIDService 创建公开ID服务实例
+func NewPublicIDService(repo repository.PublicIDRepository) PublicIDService {
+	return &publicIDService{repo: repo}
+}
+
+// table 返回资源类型对应的数据表，未知类型属于编程错误
+func (s *publicIDService) table(kind string) repository.PublicIDTable {
+	table, ok := repository.PublicIDTables[kind]
+	if !ok {
+		panic("未知的公开ID资源类型: " + kind)
+	}
+	return table
+}
+
+// Resolve 将客户端传入的ID引用解析为内部ID
+func (s *publicIDService) Resolve(ctx context.Context, kind string, ref idgen.Ref) (uint, error) {
+	ids, err := s.ResolveAll(ctx, kind, []idgen.Ref{ref})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// ResolveAll 批量解析ID引用，公开ID合并为一次查询
+func (s *publicIDService) ResolveAll(ctx context.Context, kind string, refs []idgen.Ref) ([]uint, error) {
+	table := s.table(kind)
+	acceptInternal := config.GetPublicIDConfig().AcceptInternal
+
+	ids := make([]uint, len(refs))
+	publicIDs := make([]string, len(refs))
+	var lookup []string
+	for i, ref := range refs {
+		if id, ok := ref.Internal(); ok {
+			if !acceptInternal {
+				return nil, ErrInvalidID
+			}
+			ids[i] = id
+			continue
+		}
+		publicID, ok := ref.Public()
+		if !ok {
+			return nil, ErrInvalidID
+		}
+		publicIDs[i] = publicID
+		lookup = append(lookup, publicID)
+	}
+	if len(lookup) == 0 {
+		return ids, nil
+	}
+
+	found := make(map[string]uint, len(lookup))
+	for start := 0; start < len(lookup); start += constant.PublicIDBatchSize {
+		end := min(start+constant.PublicIDBatchSize, len(lookup))
+		batch, err := s.repo.FindIDs(ctx, table, lookup[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("查询公开ID失败: %w", err)
+		}
+		for publicID, id := range batch {
+			found[publicID] = id
+		}
+	}
+	for i, publicID := range publicIDs {
+		if publicID == "" {
+			continue
+		}
+		id, ok := found[publicID]
+		if !ok {
+			return nil, ErrPublicIDNotFound
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// PublicIDs 批量查询内部ID对应的公开ID
+func (s *publicIDService) PublicIDs(ctx context.Context, kind string, ids []uint) map[uint]string {
+	table := s.table(kind)
+	result := make(map[uint]string, len(ids))
+
+	seen := make(map[uint]bool, len(ids))
+	var lookup []uint
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		lookup = append(lookup, id)
+	}
+
+	for start := 0; start < len(lookup); start += constant.PublicIDBatchSize {
+		end := min(start+constant.PublicIDBatchSize, len(lookup))
+		batch, err := s.repo.FindPublicIDs(ctx, table, lookup[start:end])
+		if err != nil {
+			logger.Warn(ctx, "查询公开ID失败", logger.String("kind", kind), logger.Err(err))
+			return result
+		}
+		for id, publicID := range batch {
+			result[id] = publicID
+		}
+	}
+	return result
+}
+
+// PublicID 查询单个内部ID对应的公开ID
+func (s *publicIDService) PublicID(ctx context.Context, kind string, id uint) string {
+	return s.PublicIDs(ctx, kind, []uint{id})[id]
+}
@@ -5,8 +5,13 @@ import (
 	"app/internal/dto"
 	"app/internal/model"
 	"app/internal/repository"
+	"app/pkg/featureflag"
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,10 +22,31 @@ type RelationService interface {
 	FollowUser(ctx context.Context, req *dto.FollowUserRequest, userID uint) (*dto.FollowUserResponse, error)
 	// UnfollowUser 取消关注用户
 	UnfollowUser(ctx context.Context, req *dto.UnfollowUserRequest, userID uint) error
+	// BatchFollow 批量关注用户，返回每个目标用户的处理结果
+	BatchFollow(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error)
+	// BatchUnfollow 批量取消关注用户，返回每个目标用户的处理结果
+	BatchUnfollow(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error)
+	// RemoveFollowers 批量移除粉丝，返回每个粉丝的处理结果
+	RemoveFollowers(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error)
 	// GetFollowers 获取粉丝列表
 	GetFollowers(ctx context.Context, req *dto.GetFollowersRequest) (*dto.GetFollowersResponse, error)
+	// IterateFollowers 返回按用户ID顺序分批读取全部粉丝的函数，每次调用返回下一批，读取完毕后返回空批次
+	// 用于流式导出粉丝较多的用户的粉丝列表，不包含已注销或被封禁的粉丝
+	IterateFollowers(ctx context.Context, userID uint) func() ([]dto.UserBrief, error)
 	// GetFollowing 获取关注列表
 	GetFollowing(ctx context.Context, req *dto.GetFollowingRequest) (*dto.GetFollowingResponse, error)
+	// GetRelationVersion 获取用户的关注关系版本号，关注数或粉丝数变化时递增
+	GetRelationVersion(ctx context.Context, userID uint) (uint64, error)
+	// GetRelationCounts 获取用户的粉丝数和关注数
+	GetRelationCounts(ctx context.Context, userID uint) (*dto.RelationCountsResponse, error)
+	// ApproveFollow 通过关注请求
+	ApproveFollow(ctx context.Context, req *dto.ApproveFollowRequest, userID uint) error
+	// RejectFollow 拒绝关注请求
+	RejectFollow(ctx context.Context, req *dto.RejectFollowRequest, userID uint) error
+	// GetFollowRequests 获取关注请求列表
+	GetFollowRequests(ctx context.Context, req *dto.GetFollowRequestsRequest, userID uint) (*dto.GetFollowRequestsResponse, error)
+	// SetPrivateAccount 设置私密账号，关闭时自动通过所有待审核的关注请求
+	SetPrivateAccount(ctx context.Context, req *dto.SetPrivateAccountRequest, userID uint) error
 	// AddFriend 添加好友
 	AddFriend(ctx context.Context, req *dto.AddFriendRequest, userID uint) (*dto.AddFriendResponse, error)
 	// AcceptFriend 接受好友请求
@@ -33,6 +59,18 @@ type RelationService interface {
 	GetFriendRequests(ctx context.Context, req *dto.GetFriendRequestsRequest, userID uint) (*dto.GetFriendRequestsResponse, error)
 	// GetFriends 获取好友列表
 	GetFriends(ctx context.Context, req *dto.GetFriendsRequest, userID uint) (*dto.GetFriendsResponse, error)
+	// UpdateFriendRemark 设置好友备注
+	UpdateFriendRemark(ctx context.Context, req *dto.UpdateFriendRemarkRequest, userID uint) error
+	// UpdateFriendGroup 设置好友分组
+	UpdateFriendGroup(ctx context.Context, req *dto.UpdateFriendGroupRequest, userID uint) error
+	// DiscoverContacts 根据通讯录手机号摘要发现已注册的用户
+	DiscoverContacts(ctx context.Context, req *dto.DiscoverContactsRequest, userID uint) (*dto.DiscoverContactsResponse, error)
+	// GetRecommendations 获取好友推荐，lang为推荐理由文案的语言
+	GetRecommendations(ctx context.Context, userID uint, lang string) (*dto.GetRecommendationsResponse, error)
+	// DismissRecommendation 对推荐的用户标记不感兴趣
+	DismissRecommendation(ctx context.Context, req *dto.DismissRecommendationRequest, userID uint) error
+	// RefreshRecommendations 为近期活跃用户重新计算好友推荐，由定时任务调用
+	RefreshRecommendations(ctx context.Context) (int, error)
 }
 
 // relationService 用户关系服务实现
@@ -40,6 +78,10 @@ type relationService struct {
 	followerRepo repository.UserFollowerRepository
 	friendRepo   repository.UserFriendRepository
 	userRepo     repository.UserRepository
+	notifier     FollowNotifier
+	features     *featureflag.Client
+	events       EventPublisher
+	publicIDs    PublicIDService
 }
 
 // NewRelationService 创建用户关系服务实例
@@ -47,18 +89,26 @@ func NewRelationService(
 	followerRepo repository.UserFollowerRepository,
 	friendRepo repository.UserFriendRepository,
 	userRepo repository.UserRepository,
+	notifier FollowNotifier,
+	features *featureflag.Client,
+	events EventPublisher,
+	publicIDs PublicIDService,
 ) RelationService {
 	return &relationService{
 		followerRepo: followerRepo,
 		friendRepo:   friendRepo,
 		userRepo:     userRepo,
+		notifier:     notifier,
+		features:     features,
+		events:       events,
+		publicIDs:    publicIDs,
 	}
 }
 
 // FollowUser 关注用户
 func (s *relationService) FollowUser(ctx context.Context, req *dto.FollowUserRequest, userID uint) (*dto.FollowUserResponse, error) {
 	// 检查目标用户是否存在
-	_, err := s.userRepo.FindByID(req.TargetID)
+	target, err := s.userRepo.FindByID(ctx, req.TargetID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("目标用户不存在")
@@ -67,31 +117,49 @@ func (s *relationService) FollowUser(ctx context.Context, req *dto.FollowUserReq
 	}
 
 	// 检查是否已关注
-	existingFollower, err := s.followerRepo.GetFollower(userID, req.TargetID)
+	existingFollower, err := s.followerRepo.GetFollower(ctx, userID, req.TargetID)
 	exists := err == nil && existingFollower != nil
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 	if exists {
+		if existingFollower.Status == int(constant.FollowStatusPending) {
+			return nil, errors.New("已经发送过关注请求")
+		}
 		return nil, errors.New("已经关注该用户")
 	}
 
-	// 创建关注关系
+	// 创建关注关系，关注私密账号时需要对方审核
 	newFollower := &model.UserFollower{
 		UserID:   userID,
 		TargetID: req.TargetID,
+		Status:   int(constant.FollowStatusApproved),
+	}
+	if target.IsPrivate {
+		newFollower.Status = int(constant.FollowStatusPending)
 	}
 
 	// 保存到数据库
-	err = s.followerRepo.CreateFollower(newFollower)
+	err = s.followerRepo.CreateFollower(ctx, newFollower)
 	if err != nil {
 		return nil, err
 	}
 
+	if newFollower.Status == int(constant.FollowStatusPending) {
+		s.notifier.FollowRequested(ctx, newFollower.ID, userID, req.TargetID)
+	} else {
+		var sourcePostID uint
+		if req.SourcePostID != nil {
+			sourcePostID = *req.SourcePostID
+		}
+		s.publishFollowed(ctx, userID, req.TargetID, sourcePostID, newFollower.CreatedAt)
+	}
+
 	return &dto.FollowUserResponse{
 		ID:        newFollower.ID,
-		UserID:    newFollower.UserID,
-		TargetID:  newFollower.TargetID,
+		UserID:    s.publicIDs.PublicID(ctx, constant.PublicIDUser, userID),
+		TargetID:  target.PublicID,
+		Status:    newFollower.Status,
 		CreatedAt: newFollower.CreatedAt,
 	}, nil
 }
@@ -99,7 +167,7 @@ func (s *relationService) FollowUser(ctx context.Context, req *dto.FollowUserReq
 // UnfollowUser 取消关注用户
 func (s *relationService) UnfollowUser(ctx context.Context, req *dto.UnfollowUserRequest, userID uint) error {
 	// 检查是否已关注
-	follower, err := s.followerRepo.GetFollower(userID, req.TargetID)
+	follower, err := s.followerRepo.GetFollower(ctx, userID, req.TargetID)
 	exists := err == nil && follower != nil
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return err
@@ -108,14 +176,14 @@ func (s *relationService) UnfollowUser(ctx context.Context, req *dto.UnfollowUse
 		return errors.New("未关注该用户")
 	}
 
-	// 删除关注关系
-	return s.followerRepo.DeleteFollower(userID, req.TargetID)
+	// 删除关注关系，待审核的关注请求同样以此撤回
+	return s.followerRepo.DeleteFollower(ctx, userID, req.TargetID)
 }
 
 // GetFollowers 获取粉丝列表
 func (s *relationService) GetFollowers(ctx context.Context, req *dto.GetFollowersRequest) (*dto.GetFollowersResponse, error) {
 	// 获取粉丝关系列表
-	followers, total, err := s.followerRepo.GetFollowers(req.UserID, req.Page, req.Size)
+	followers, total, err := s.followerRepo.GetFollowers(ctx, req.UserID, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -124,16 +192,16 @@ func (s *relationService) GetFollowers(ctx context.Context, req *dto.GetFollower
 	list := make([]dto.UserBrief, 0, len(followers))
 	for _, follower := range followers {
 		// 获取粉丝用户信息
-		user, err := s.userRepo.FindByID(follower.UserID)
+		user, err := s.userRepo.FindByID(ctx, follower.UserID)
 		if err != nil {
 			continue
 		}
 
 		// 添加到列表
 		list = append(list, dto.UserBrief{
-			ID:       user.ID,
+			ID:       user.PublicID,
 			Nickname: user.Nickname,
-			Avatar:   user.Avatar,
+			Avatar:   avatarURL(user),
 		})
 	}
 
@@ -143,10 +211,52 @@ func (s *relationService) GetFollowers(ctx context.Context, req *dto.GetFollower
 	}, nil
 }
 
+// IterateFollowers 返回按用户ID顺序分批读取全部粉丝的函数
+// 每批先按游标读取粉丝ID再批量查询用户，整批粉丝均不可见时继续读取下一批，避免提前返回空批次
+func (s *relationService) IterateFollowers(ctx context.Context, userID uint) func() ([]dto.UserBrief, error) {
+	var afterID uint
+	done := false
+	return func() ([]dto.UserBrief, error) {
+		for !done {
+			ids, err := s.followerRepo.GetFollowerIDs(ctx, userID, afterID, constant.FollowerExportChunkSize)
+			if err != nil {
+				return nil, fmt.Errorf("获取粉丝列表失败: %w", err)
+			}
+			if len(ids) < constant.FollowerExportChunkSize {
+				done = true
+			}
+			if len(ids) == 0 {
+				break
+			}
+			afterID = ids[len(ids)-1]
+
+			users, err := s.userRepo.FindNormalByIDs(ctx, ids)
+			if err != nil {
+				return nil, fmt.Errorf("获取粉丝信息失败: %w", err)
+			}
+			if len(users) == 0 {
+				continue
+			}
+			sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+			list := make([]dto.UserBrief, len(users))
+			for i := range users {
+				list[i] = dto.UserBrief{
+					ID:       users[i].PublicID,
+					Nickname: users[i].Nickname,
+					Avatar:   avatarURL(&users[i]),
+				}
+			}
+			return list, nil
+		}
+		return nil, nil
+	}
+}
+
 // GetFollowing 获取关注列表
 func (s *relationService) GetFollowing(ctx context.Context, req *dto.GetFollowingRequest) (*dto.GetFollowingResponse, error) {
 	// 获取关注关系列表
-	followings, total, err := s.followerRepo.GetFollowing(req.UserID, req.Page, req.Size)
+	followings, total, err := s.followerRepo.GetFollowing(ctx, req.UserID, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -155,16 +265,16 @@ func (s *relationService) GetFollowing(ctx context.Context, req *dto.GetFollowin
 	list := make([]dto.UserBrief, 0, len(followings))
 	for _, following := range followings {
 		// 获取关注用户信息
-		user, err := s.userRepo.FindByID(following.TargetID)
+		user, err := s.userRepo.FindByID(ctx, following.TargetID)
 		if err != nil {
 			continue
 		}
 
 		// 添加到列表
 		list = append(list, dto.UserBrief{
-			ID:       user.ID,
+			ID:       user.PublicID,
 			Nickname: user.Nickname,
-			Avatar:   user.Avatar,
+			Avatar:   avatarURL(user),
 		})
 	}
 
@@ -174,10 +284,35 @@ func (s *relationService) GetFollowing(ctx context.Context, req *dto.GetFollowin
 	}, nil
 }
 
+// GetRelationVersion 获取用户的关注关系版本号
+func (s *relationService) GetRelationVersion(ctx context.Context, userID uint) (uint64, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, ErrUserNotFound
+		}
+		return 0, err
+	}
+	return user.RelationVersion, nil
+}
+
+// GetRelationCounts 获取用户的粉丝数和关注数
+func (s *relationService) GetRelationCounts(ctx context.Context, userID uint) (*dto.RelationCountsResponse, error) {
+	followers, following, err := s.followerRepo.CountFollows(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RelationCountsResponse{
+		UserID:    s.publicIDs.PublicID(ctx, constant.PublicIDUser, userID),
+		Followers: followers,
+		Following: following,
+	}, nil
+}
+
 // AddFriend 添加好友
 func (s *relationService) AddFriend(ctx context.Context, req *dto.AddFriendRequest, userID uint) (*dto.AddFriendResponse, error) {
 	// 检查目标用户是否存在
-	_, err := s.userRepo.FindByID(req.TargetID)
+	target, err := s.userRepo.FindByID(ctx, req.TargetID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("目标用户不存在")
@@ -186,7 +321,7 @@ func (s *relationService) AddFriend(ctx context.Context, req *dto.AddFriendReque
 	}
 
 	// 检查是否已经是好友
-	friend, err := s.friendRepo.GetFriend(userID, req.TargetID)
+	friend, err := s.friendRepo.GetFriend(ctx, userID, req.TargetID)
 	isFriend := err == nil && friend != nil
 	if err != nil {
 		return nil, err
@@ -207,19 +342,21 @@ func (s *relationService) AddFriend(ctx context.Context, req *dto.AddFriendReque
 		TargetID:  req.TargetID,
 		Status:    int(constant.FriendStatusPending),
 		Direction: 0, // 发起方
+		Message:   req.Message,
 	}
 
 	// 保存到数据库
-	err = s.friendRepo.CreateFriend(friendRequest)
+	err = s.friendRepo.CreateFriend(ctx, friendRequest)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.AddFriendResponse{
 		ID:        friendRequest.ID,
-		UserID:    friendRequest.UserID,
-		TargetID:  friendRequest.TargetID,
+		UserID:    s.publicIDs.PublicID(ctx, constant.PublicIDUser, userID),
+		TargetID:  target.PublicID,
 		Status:    friendRequest.Status,
+		Message:   friendRequest.Message,
 		CreatedAt: friendRequest.CreatedAt,
 	}, nil
 }
@@ -227,7 +364,7 @@ func (s *relationService) AddFriend(ctx context.Context, req *dto.AddFriendReque
 // AcceptFriend 接受好友请求
 func (s *relationService) AcceptFriend(ctx context.Context, req *dto.AcceptFriendRequest, userID uint) error {
 	// 获取好友请求
-	friendRequest, err := s.friendRepo.GetFriendByID(req.RequestID)
+	friendRequest, err := s.friendRepo.GetFriendByID(ctx, req.RequestID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("好友请求不存在")
@@ -246,13 +383,13 @@ func (s *relationService) AcceptFriend(ctx context.Context, req *dto.AcceptFrien
 	}
 
 	// 更新好友请求状态为已接受
-	return s.friendRepo.UpdateFriendStatus(friendRequest.ID, int(constant.FriendStatusConfirmed))
+	return s.friendRepo.UpdateFriendStatus(ctx, friendRequest.ID, int(constant.FriendStatusConfirmed))
 }
 
 // RejectFriend 拒绝好友请求
 func (s *relationService) RejectFriend(ctx context.Context, req *dto.RejectFriendRequest, userID uint) error {
 	// 获取好友请求
-	friendRequest, err := s.friendRepo.GetFriendByID(req.RequestID)
+	friendRequest, err := s.friendRepo.GetFriendByID(ctx, req.RequestID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("好友请求不存在")
@@ -271,13 +408,13 @@ func (s *relationService) RejectFriend(ctx context.Context, req *dto.RejectFrien
 	}
 
 	// 更新请求状态为已拒绝
-	return s.friendRepo.UpdateFriendStatus(friendRequest.ID, 2) // 拒绝状态值为2
+	return s.friendRepo.UpdateFriendStatus(ctx, friendRequest.ID, 2) // 拒绝状态值为2
 }
 
 // DeleteFriend 删除好友
 func (s *relationService) DeleteFriend(ctx context.Context, req *dto.DeleteFriendRequest, userID uint) error {
 	// 检查是否是好友关系
-	friend, err := s.friendRepo.GetFriend(userID, req.TargetID)
+	friend, err := s.friendRepo.GetFriend(ctx, userID, req.TargetID)
 	isFriend := err == nil && friend != nil && friend.Status == int(constant.FriendStatusConfirmed)
 	if err != nil {
 		return err
@@ -287,13 +424,13 @@ func (s *relationService) DeleteFriend(ctx context.Context, req *dto.DeleteFrien
 	}
 
 	// 删除好友关系（双向）
-	return s.friendRepo.DeleteFriend(userID, req.TargetID)
+	return s.friendRepo.DeleteFriend(ctx, userID, req.TargetID)
 }
 
 // GetFriendRequests 获取好友请求列表
 func (s *relationService) GetFriendRequests(ctx context.Context, req *dto.GetFriendRequestsRequest, userID uint) (*dto.GetFriendRequestsResponse, error) {
 	// 获取好友请求列表
-	requests, total, err := s.friendRepo.GetFriendRequests(userID, req.Page, req.Size)
+	requests, total, err := s.friendRepo.GetFriendRequests(ctx, userID, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -302,7 +439,7 @@ func (s *relationService) GetFriendRequests(ctx context.Context, req *dto.GetFri
 	list := make([]dto.FriendRequestItem, 0, len(requests))
 	for _, request := range requests {
 		// 获取请求用户信息
-		user, err := s.userRepo.FindByID(request.UserID)
+		user, err := s.userRepo.FindByID(ctx, request.UserID)
 		if err != nil {
 			continue
 		}
@@ -310,10 +447,10 @@ func (s *relationService) GetFriendRequests(ctx context.Context, req *dto.GetFri
 		// 添加到列表
 		list = append(list, dto.FriendRequestItem{
 			ID:        request.ID,
-			UserID:    user.ID,
+			UserID:    user.PublicID,
 			Nickname:  user.Nickname,
-			Avatar:    user.Avatar,
-			Message:   "", // Message字段在请求中不存在，使用空字符串
+			Avatar:    avatarURL(user),
+			Message:   request.Message,
 			CreatedAt: request.CreatedAt,
 		})
 	}
@@ -327,7 +464,7 @@ func (s *relationService) GetFriendRequests(ctx context.Context, req *dto.GetFri
 // GetFriends 获取好友列表
 func (s *relationService) GetFriends(ctx context.Context, req *dto.GetFriendsRequest, userID uint) (*dto.GetFriendsResponse, error) {
 	// 获取好友关系列表
-	friends, total, err := s.friendRepo.GetFriends(userID, req.Page, req.Size)
+	friends, total, err := s.friendRepo.GetFriends(ctx, userID, req.Group, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -336,16 +473,20 @@ func (s *relationService) GetFriends(ctx context.Context, req *dto.GetFriendsReq
 	list := make([]dto.FriendItem, 0, len(friends))
 	for _, friend := range friends {
 		// 获取好友用户信息
-		user, err := s.userRepo.FindByID(friend.TargetID)
+		user, err := s.userRepo.FindByID(ctx, friend.TargetID)
 		if err != nil {
 			continue
 		}
 
 		// 添加到列表
 		list = append(list, dto.FriendItem{
-			ID:       user.ID,
-			Nickname: user.Nickname,
-			Avatar:   user.Avatar,
+			ID:        friend.ID,
+			UserID:    user.PublicID,
+			Nickname:  user.Nickname,
+			Avatar:    avatarURL(user),
+			Remark:    friend.Remark,
+			Group:     friend.GroupName,
+			CreatedAt: friend.CreatedAt,
 		})
 	}
 
@@ -354,3 +495,156 @@ func (s *relationService) GetFriends(ctx context.Context, req *dto.GetFriendsReq
 		List:  list,
 	}, nil
 }
+
+// UpdateFriendRemark 设置好友备注
+func (s *relationService) UpdateFriendRemark(ctx context.Context, req *dto.UpdateFriendRemarkRequest, userID uint) error {
+	if err := s.checkConfirmedFriend(ctx, userID, req.TargetID); err != nil {
+		return err
+	}
+	return s.friendRepo.UpdateFriendRemark(ctx, userID, req.TargetID, strings.TrimSpace(req.Remark))
+}
+
+// UpdateFriendGroup 设置好友分组
+func (s *relationService) UpdateFriendGroup(ctx context.Context, req *dto.UpdateFriendGroupRequest, userID uint) error {
+	if err := s.checkConfirmedFriend(ctx, userID, req.TargetID); err != nil {
+		return err
+	}
+	return s.friendRepo.UpdateFriendGroup(ctx, userID, req.TargetID, strings.TrimSpace(req.Group))
+}
+
+// checkConfirmedFriend 检查是否为已确认的好友关系
+func (s *relationService) checkConfirmedFriend(ctx context.Context, userID, targetID uint) error {
+	friend, err := s.friendRepo.GetFriend(ctx, userID, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("不是好友关系")
+		}
+		return err
+	}
+	if friend.Status != int(constant.FriendStatusConfirmed) {
+		return errors.New("不是好友关系")
+	}
+	return nil
+}
+
+// ApproveFollow 通过关注请求
+func (s *relationService) ApproveFollow(ctx context.Context, req *dto.ApproveFollowRequest, userID uint) error {
+	followRequest, err := s.getPendingFollowRequest(ctx, req.RequestID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.followerRepo.UpdateFollowerStatus(ctx, followRequest.ID, int(constant.FollowStatusApproved)); err != nil {
+		return err
+	}
+
+	s.notifier.FollowApproved(ctx, followRequest.ID, followRequest.UserID, userID)
+	s.publishFollowed(ctx, followRequest.UserID, userID, 0, time.Now())
+	return nil
+}
+
+// publishFollowed 发布关注生效事件，事件数据同时携带内部ID和公开ID
+// sourcePostID为用户关注时所在的动态，为0表示来源未知；关注私密账号时请求通过后不再保留来源
+func (s *relationService) publishFollowed(ctx context.Context, userID, targetID, sourcePostID uint, at time.Time) {
+	publicIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDUser, []uint{userID, targetID})
+	s.events.Publish(ctx, constant.EventUserFollowed, dto.UserFollowedData{
+		UserID:         userID,
+		TargetID:       targetID,
+		PublicUserID:   publicIDs[userID],
+		PublicTargetID: publicIDs[targetID],
+		CreatedAt:      at,
+		SourcePostID:   sourcePostID,
+	})
+}
+
+// RejectFollow 拒绝关注请求，拒绝后删除请求记录，对方可以重新发起
+func (s *relationService) RejectFollow(ctx context.Context, req *dto.RejectFollowRequest, userID uint) error {
+	followRequest, err := s.getPendingFollowRequest(ctx, req.RequestID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.followerRepo.DeleteFollower(ctx, followRequest.UserID, userID)
+}
+
+// getPendingFollowRequest 获取发给当前用户的待审核关注请求
+func (s *relationService) getPendingFollowRequest(ctx context.Context, requestID, userID uint) (*model.UserFollower, error) {
+	followRequest, err := s.followerRepo.GetFollowerByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("关注请求不存在")
+		}
+		return nil, err
+	}
+
+	// 检查请求是否发给当前用户
+	if followRequest.TargetID != userID {
+		return nil, errors.New("无权操作此关注请求")
+	}
+
+	// 检查请求状态
+	if followRequest.Status != int(constant.FollowStatusPending) {
+		return nil, errors.New("关注请求已处理")
+	}
+
+	return followRequest, nil
+}
+
+// GetFollowRequests 获取关注请求列表
+func (s *relationService) GetFollowRequests(ctx context.Context, req *dto.GetFollowRequestsRequest, userID uint) (*dto.GetFollowRequestsResponse, error) {
+	requests, total, err := s.followerRepo.GetFollowRequests(ctx, userID, req.Page, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// 构建响应数据
+	list := make([]dto.FollowRequestItem, 0, len(requests))
+	for _, request := range requests {
+		// 获取请求用户信息
+		user, err := s.userRepo.FindByID(ctx, request.UserID)
+		if err != nil {
+			continue
+		}
+
+		list = append(list, dto.FollowRequestItem{
+			ID:        request.ID,
+			UserID:    user.PublicID,
+			Nickname:  user.Nickname,
+			Avatar:    avatarURL(user),
+			CreatedAt: request.CreatedAt,
+		})
+	}
+
+	return &dto.GetFollowRequestsResponse{
+		Total: int(total),
+		List:  list,
+	}, nil
+}
+
+// SetPrivateAccount 设置私密账号，关闭时自动通过所有待审核的关注请求
+func (s *relationService) SetPrivateAccount(ctx context.Context, req *dto.SetPrivateAccountRequest, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("用户不存在")
+		}
+		return err
+	}
+
+	if user.IsPrivate == *req.IsPrivate {
+		return nil
+	}
+
+	user.IsPrivate = *req.IsPrivate
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if !user.IsPrivate {
+		if _, err := s.followerRepo.ApproveAllPending(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/pkg/logger"
+)
+
+// 批量关系操作中单个目标的失败原因
+const (
+	batchReasonSelf           = "不能关注自己"
+	batchReasonNotFound       = "目标用户不存在"
+	batchReasonRequested      = "已经发送过关注请求"
+	batchReasonFollowing      = "已经关注该用户"
+	batchReasonNotFollowing   = "未关注该用户"
+	batchReasonNotFollower    = "该用户不是你的粉丝"
+	batchReasonFollowFailed   = "关注用户失败"
+	batchReasonUnfollowFailed = "取消关注失败"
+	batchReasonRemoveFailed   = "移除粉丝失败"
+)
+
+// BatchFollow 批量关注用户
+// 跳过自己、不存在或不可见的用户以及已关注的用户，其余用户分批写入，关注私密账号时发送关注请求
+func (s *relationService) BatchFollow(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error) {
+	targetIDs := uniqueTargetIDs(req.TargetIDs)
+
+	users, err := s.userRepo.FindNormalByIDs(ctx, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询目标用户失败: %w", err)
+	}
+	targets := make(map[uint]*model.User, len(users))
+	for i := range users {
+		targets[users[i].ID] = &users[i]
+	}
+
+	statuses, err := s.followerRepo.GetFollowStatuses(ctx, userID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询关注状态失败: %w", err)
+	}
+
+	reasons := make(map[uint]string)
+	var followers []model.UserFollower
+	for _, id := range targetIDs {
+		target, found := targets[id]
+		switch status, following := statuses[id]; {
+		case id == userID:
+			reasons[id] = batchReasonSelf
+		case !found:
+			reasons[id] = batchReasonNotFound
+		case following && status == int(constant.FollowStatusPending):
+			reasons[id] = batchReasonRequested
+		case following:
+			reasons[id] = batchReasonFollowing
+		default:
+			follower := model.UserFollower{UserID: userID, TargetID: id, Status: int(constant.FollowStatusApproved)}
+			if target.IsPrivate {
+				follower.Status = int(constant.FollowStatusPending)
+			}
+			followers = append(followers, follower)
+		}
+	}
+
+	failed := s.followerRepo.BatchCreateFollowers(ctx, followers, constant.RelationBatchChunkSize)
+	done := make(map[uint]string, len(followers))
+	for _, follower := range followers {
+		if err, ok := failed[follower.TargetID]; ok {
+			logger.Error(ctx, "批量关注写入失败", logger.Uint("user_id", userID), logger.Uint("target_id", follower.TargetID), logger.Err(err))
+			reasons[follower.TargetID] = batchReasonFollowFailed
+			continue
+		}
+
+		if follower.Status == int(constant.FollowStatusPending) {
+			done[follower.TargetID] = constant.RelationBatchRequested
+			s.notifier.FollowRequested(ctx, follower.ID, userID, follower.TargetID)
+			continue
+		}
+		done[follower.TargetID] = constant.RelationBatchFollowed
+		s.publishFollowed(ctx, userID, follower.TargetID, 0, follower.CreatedAt)
+	}
+
+	return s.buildBatchRelationResponse(ctx, req, targetIDs, done, reasons), nil
+}
+
+// BatchUnfollow 批量取消关注用户，待审核的关注请求同样撤回
+func (s *relationService) BatchUnfollow(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error) {
+	targetIDs := uniqueTargetIDs(req.TargetIDs)
+
+	statuses, err := s.followerRepo.GetFollowStatuses(ctx, userID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询关注状态失败: %w", err)
+	}
+
+	reasons := make(map[uint]string)
+	var following []uint
+	for _, id := range targetIDs {
+		if _, ok := statuses[id]; !ok {
+			reasons[id] = batchReasonNotFollowing
+			continue
+		}
+		following = append(following, id)
+	}
+
+	failed := s.followerRepo.BatchDeleteFollowing(ctx, userID, following, constant.RelationBatchChunkSize)
+	done := make(map[uint]string, len(following))
+	for _, id := range following {
+		if err, ok := failed[id]; ok {
+			logger.Error(ctx, "批量取消关注失败", logger.Uint("user_id", userID), logger.Uint("target_id", id), logger.Err(err))
+			reasons[id] = batchReasonUnfollowFailed
+			continue
+		}
+		done[id] = constant.RelationBatchUnfollowed
+	}
+
+	return s.buildBatchRelationResponse(ctx, req, targetIDs, done, reasons), nil
+}
+
+// RemoveFollowers 批量移除粉丝，只处理已通过的关注，待审核的关注请求应通过拒绝请求处理
+func (s *relationService) RemoveFollowers(ctx context.Context, req *dto.BatchRelationRequest, userID uint) (*dto.BatchRelationResponse, error) {
+	followerIDs := uniqueTargetIDs(req.TargetIDs)
+
+	statuses, err := s.followerRepo.GetFollowerStatuses(ctx, userID, followerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询粉丝状态失败: %w", err)
+	}
+
+	reasons := make(map[uint]string)
+	var approved []uint
+	for _, id := range followerIDs {
+		if statuses[id] != int(constant.FollowStatusApproved) {
+			reasons[id] = batchReasonNotFollower
+			continue
+		}
+		approved = append(approved, id)
+	}
+
+	failed := s.followerRepo.BatchDeleteFollowers(ctx, userID, approved, constant.RelationBatchChunkSize)
+	done := make(map[uint]string, len(approved))
+	for _, id := range approved {
+		if err, ok := failed[id]; ok {
+			logger.Error(ctx, "批量移除粉丝失败", logger.Uint("user_id", userID), logger.Uint("follower_id", id), logger.Err(err))
+			reasons[id] = batchReasonRemoveFailed
+			continue
+		}
+		done[id] = constant.RelationBatchRemoved
+	}
+
+	return s.buildBatchRelationResponse(ctx, req, followerIDs, done, reasons), nil
+}
+
+// uniqueTargetIDs 按首次出现的顺序去除重复的用户ID
+func uniqueTargetIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// buildBatchRelationResponse 按目标顺序组装每个目标的处理结果
+// 结果中的目标ID为用户的公开ID，用户不存在时原样返回请求中的ID，便于客户端对应
+func (s *relationService) buildBatchRelationResponse(
+	ctx context.Context,
+	req *dto.BatchRelationRequest,
+	ids []uint,
+	done map[uint]string,
+	reasons map[uint]string,
+) *dto.BatchRelationResponse {
+	targetIDs := s.publicIDs.PublicIDs(ctx, constant.PublicIDUser, ids)
+	for i, id := range req.TargetIDs {
+		if _, ok := targetIDs[id]; !ok && i < len(req.TargetRefs) {
+			targetIDs[id] = req.TargetRefs[i].String()
+		}
+	}
+
+	resp := &dto.BatchRelationResponse{Results: make([]dto.BatchRelationResult, 0, len(ids))}
+	for _, id := range ids {
+		if status, ok := done[id]; ok {
+			resp.Succeeded++
+			resp.Results = append(resp.Results, dto.BatchRelationResult{TargetID: targetIDs[id], Success: true, Status: status})
+			continue
+		}
+		resp.Failed++
+		resp.Results = append(resp.Results, dto.BatchRelationResult{TargetID: targetIDs[id], Error: reasons[id]})
+	}
+	return resp
+}
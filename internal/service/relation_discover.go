@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"app/internal/constant"
+	"app/internal/dto"
+)
+
+// DiscoverContacts 根据通讯录手机号摘要匹配已注册用户，并返回与当前用户的关注和好友状态
+// 关闭了手机号搜索的用户不会被匹配到
+func (s *relationService) DiscoverContacts(ctx context.Context, req *dto.DiscoverContactsRequest, userID uint) (*dto.DiscoverContactsResponse, error) {
+	if !s.features.IsEnabled(constant.FeatureContactDiscovery, userID) {
+		return nil, ErrFeatureDisabled
+	}
+
+	// 与搜索共用频率限制，防止批量枚举手机号
+	if err := checkSearchRate(userID); err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(req.MobileHashes))
+	seen := make(map[string]bool, len(req.MobileHashes))
+	for _, hash := range req.MobileHashes {
+		hash = strings.ToLower(hash)
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+
+	// 保存摘要用于计算通讯录好友推荐
+	saveContactHashes(ctx, userID, hashes)
+
+	users, err := s.userRepo.FindByMobileHashes(ctx, hashes, userID)
+	if err != nil {
+		return nil, fmt.Errorf("匹配通讯录失败: %w", err)
+	}
+
+	targetIDs := make([]uint, len(users))
+	for i, user := range users {
+		targetIDs[i] = user.ID
+	}
+	followStatuses, err := s.followerRepo.GetFollowStatuses(ctx, userID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询关注状态失败: %w", err)
+	}
+	friendStatuses, err := s.friendRepo.GetFriendStatuses(ctx, userID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询好友状态失败: %w", err)
+	}
+
+	list := make([]dto.DiscoveredContact, 0, len(users))
+	for i := range users {
+		user := &users[i]
+		friendStatus, ok := friendStatuses[user.ID]
+		if !ok {
+			friendStatus = -1
+		}
+		list = append(list, dto.DiscoveredContact{
+			MobileHash:   user.MobileHash,
+			UserID:       user.PublicID,
+			Nickname:     user.Nickname,
+			Avatar:       avatarURL(user),
+			FollowStatus: followStatuses[user.ID],
+			FriendStatus: friendStatus,
+		})
+	}
+
+	return &dto.DiscoverContactsResponse{List: list}, nil
+}
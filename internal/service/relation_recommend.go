@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/pkg/i18n"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// ErrRecommendDismissSelf 不能对自己标记不感兴趣
+var ErrRecommendDismissSelf = errors.New("不能对自己标记不感兴趣")
+
+// recommendation 缓存中的推荐记录，按得分从高到低排列
+type recommendation struct {
+	UserID  uint              `json:"user_id"`
+	Score   float64           `json:"score"`
+	Reasons []recommendReason `json:"reasons"`
+}
+
+// recommendReason 缓存中的推荐理由，展示文案在读取时按语言生成
+type recommendReason struct {
+	Type  string `json:"type"`
+	Count int64  `json:"count"`
+}
+
+// GetRecommendations 获取好友推荐，过滤掉已建立关系和不感兴趣的用户
+// 推荐结果由定时任务离线计算，尚未计算过的用户在首次请求时计算
+func (s *relationService) GetRecommendations(ctx context.Context, userID uint, lang string) (*dto.GetRecommendationsResponse, error) {
+	var recs []recommendation
+	err := redis.GetObj(cachekey.RelationRecommendations(userID).String(), &recs)
+	if errors.Is(err, redis.ErrKeyNotFound) {
+		recs, err = s.refreshRecommendation(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取好友推荐失败: %w", err)
+	}
+
+	// 计算之后可能已关注、添加好友或标记不感兴趣，读取时再过滤一次
+	recs, err = s.filterRecommendations(ctx, userID, recs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(recs))
+	for i, rec := range recs {
+		ids[i] = rec.UserID
+	}
+	users, err := s.userRepo.FindNormalByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户信息失败: %w", err)
+	}
+	userIndex := make(map[uint]int, len(users))
+	for i := range users {
+		userIndex[users[i].ID] = i
+	}
+
+	list := make([]dto.RecommendedUser, 0, len(recs))
+	for _, rec := range recs {
+		i, ok := userIndex[rec.UserID]
+		if !ok {
+			continue
+		}
+		reasons := make([]dto.RecommendationReason, len(rec.Reasons))
+		for j, reason := range rec.Reasons {
+			reasons[j] = dto.RecommendationReason{
+				Type:  reason.Type,
+				Count: reason.Count,
+				Text:  recommendReasonText(lang, reason),
+			}
+		}
+		list = append(list, dto.RecommendedUser{
+			UserID:   users[i].PublicID,
+			Nickname: users[i].Nickname,
+			Avatar:   avatarURL(&users[i]),
+			Reasons:  reasons,
+		})
+	}
+
+	return &dto.GetRecommendationsResponse{List: list}, nil
+}
+
+// DismissRecommendation 对推荐的用户标记不感兴趣，屏蔽期内不再推荐该用户
+func (s *relationService) DismissRecommendation(ctx context.Context, req *dto.DismissRecommendationRequest, userID uint) error {
+	if req.UserID == userID {
+		return ErrRecommendDismissSelf
+	}
+
+	key := cachekey.RelationRecommendDismissed(userID)
+	if _, err := redis.SAdd(key.String(), req.UserID); err != nil {
+		return fmt.Errorf("记录不感兴趣失败: %w", err)
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+	return nil
+}
+
+// RefreshRecommendations 为近期活跃用户重新计算好友推荐，由定时任务调用
+func (s *relationService) RefreshRecommendations(ctx context.Context) (int, error) {
+	since := time.Now().Add(-constant.RecommendationActiveUserWindow)
+	refreshed := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return refreshed, err
+		}
+
+		ids, err := s.userRepo.FindActiveUserIDs(ctx, since, afterID, constant.RecommendationBatchSize)
+		if err != nil {
+			return refreshed, fmt.Errorf("查询活跃用户失败: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		for _, id := range ids {
+			if _, err := s.refreshRecommendation(ctx, id); err != nil {
+				logger.Warn(ctx, "计算好友推荐失败", logger.Uint("user_id", id), logger.Err(err))
+				continue
+			}
+			refreshed++
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	logger.Info(ctx, "好友推荐计算完成", logger.Int("users", refreshed))
+
+	return refreshed, nil
+}
+
+// refreshRecommendation 根据共同好友、共同关注和通讯录计算用户的好友推荐并缓存
+// 没有推荐时同样缓存空列表，避免重复计算
+func (s *relationService) refreshRecommendation(ctx context.Context, userID uint) ([]recommendation, error) {
+	candidates := make(map[uint]*recommendation)
+	addReason := func(targetID uint, reasonType string, count int64, weight float64) {
+		rec, ok := candidates[targetID]
+		if !ok {
+			rec = &recommendation{UserID: targetID}
+			candidates[targetID] = rec
+		}
+		rec.Reasons = append(rec.Reasons, recommendReason{Type: reasonType, Count: count})
+		rec.Score += weight * float64(max(count, 1))
+	}
+
+	mutualFriends, err := s.friendRepo.CountMutualFriends(ctx, userID, constant.RecommendationCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("统计共同好友失败: %w", err)
+	}
+	for targetID, count := range mutualFriends {
+		addReason(targetID, constant.RecommendReasonMutualFriends, count, constant.RecommendWeightMutualFriend)
+	}
+
+	mutualFollows, err := s.followerRepo.CountMutualFollows(ctx, userID, constant.RecommendationCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("统计共同关注失败: %w", err)
+	}
+	for targetID, count := range mutualFollows {
+		addReason(targetID, constant.RecommendReasonMutualFollows, count, constant.RecommendWeightMutualFollow)
+	}
+
+	hashes, err := redis.SMembers(cachekey.RelationContacts(userID).String())
+	if err != nil {
+		return nil, fmt.Errorf("读取通讯录摘要失败: %w", err)
+	}
+	contacts, err := s.userRepo.FindByMobileHashes(ctx, hashes, userID)
+	if err != nil {
+		return nil, fmt.Errorf("匹配通讯录失败: %w", err)
+	}
+	for _, contact := range contacts {
+		addReason(contact.ID, constant.RecommendReasonContact, 0, constant.RecommendWeightContact)
+	}
+
+	recs := make([]recommendation, 0, len(candidates))
+	for _, rec := range candidates {
+		recs = append(recs, *rec)
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Score != recs[j].Score {
+			return recs[i].Score > recs[j].Score
+		}
+		return recs[i].UserID < recs[j].UserID
+	})
+
+	recs, err = s.filterRecommendations(ctx, userID, recs)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) > constant.RecommendationMaxSize {
+		recs = recs[:constant.RecommendationMaxSize]
+	}
+
+	key := cachekey.RelationRecommendations(userID)
+	if err := redis.SetObj(key.String(), recs, key.TTL()); err != nil {
+		return nil, fmt.Errorf("缓存好友推荐失败: %w", err)
+	}
+	return recs, nil
+}
+
+// filterRecommendations 过滤掉已关注、已是好友或有待处理好友请求，以及标记为不感兴趣的用户，保持原有顺序
+func (s *relationService) filterRecommendations(ctx context.Context, userID uint, recs []recommendation) ([]recommendation, error) {
+	if len(recs) == 0 {
+		return recs, nil
+	}
+
+	ids := make([]uint, len(recs))
+	for i, rec := range recs {
+		ids[i] = rec.UserID
+	}
+	followStatuses, err := s.followerRepo.GetFollowStatuses(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询关注状态失败: %w", err)
+	}
+	friendStatuses, err := s.friendRepo.GetFriendStatuses(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询好友状态失败: %w", err)
+	}
+	dismissed, err := redis.SMembers(cachekey.RelationRecommendDismissed(userID).String())
+	if err != nil {
+		return nil, fmt.Errorf("读取不感兴趣记录失败: %w", err)
+	}
+	dismissedSet := make(map[string]bool, len(dismissed))
+	for _, id := range dismissed {
+		dismissedSet[id] = true
+	}
+
+	filtered := make([]recommendation, 0, len(recs))
+	for _, rec := range recs {
+		if _, ok := followStatuses[rec.UserID]; ok {
+			continue
+		}
+		if _, ok := friendStatuses[rec.UserID]; ok {
+			continue
+		}
+		if dismissedSet[strconv.FormatUint(uint64(rec.UserID), 10)] {
+			continue
+		}
+		filtered = append(filtered, rec)
+	}
+	return filtered, nil
+}
+
+// saveContactHashes 保存用户上传的通讯录手机号摘要，用于离线计算通讯录推荐，保存失败只记录日志
+func saveContactHashes(ctx context.Context, userID uint, hashes []string) {
+	if len(hashes) == 0 {
+		return
+	}
+	members := make([]interface{}, len(hashes))
+	for i, hash := range hashes {
+		members[i] = hash
+	}
+
+	key := cachekey.RelationContacts(userID)
+	if _, err := redis.SAdd(key.String(), members...); err != nil {
+		logger.Warn(ctx, "保存通讯录摘要失败", logger.Uint("user_id", userID), logger.Err(err))
+		return
+	}
+	_, _ = redis.Expire(key.String(), key.TTL())
+}
+
+// recommendReasonText 按语言生成推荐理由的展示文案
+func recommendReasonText(lang string, reason recommendReason) string {
+	format := i18n.T(lang, constant.RecommendReasonText[reason.Type])
+	if !strings.Contains(format, "%d") {
+		return format
+	}
+	return fmt.Sprintf(format, reason.Count)
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/cos"
+	"app/pkg/idgen"
+	"app/pkg/qrcode"
+
+	"gorm.io/gorm"
+)
+
+// 分享相关错误
+var (
+	ErrShareTargetNotFound = errors.New("分享的内容不存在")
+	ErrInvalidShareLink    = errors.New("无效的分享链接")
+)
+
+// ShareService 分享服务接口
+type ShareService interface {
+	// GetQRCode 获取用户主页或动态的分享二维码，图片生成后缓存在COS中
+	GetQRCode(ctx context.Context, req *dto.ShareQRCodeRequest, userID uint) (*dto.ShareQRCodeResponse, error)
+	// ResolveScan 解析扫码得到的深度链接，校验签名和访问权限后返回目标内容信息
+	ResolveScan(ctx context.Context, req *dto.ResolveScanRequest, userID uint) (*dto.ResolveScanResponse, error)
+}
+
+// shareService 分享服务实现
+type shareService struct {
+	userRepo      repository.UserRepository
+	postRepo      repository.PostRepository
+	postImageRepo repository.PostImageRepository
+	friendRepo    repository.UserFriendRepository
+	audienceRepo  repository.AudienceListRepository
+	publicIDs     PublicIDService
+	events        EventPublisher
+	cosClient     *cos.StorageClient
+}
+
+// NewShareService 创建分享服务实例
+func NewShareService(
+	userRepo repository.UserRepository,
+	postRepo repository.PostRepository,
+	postImageRepo repository.PostImageRepository,
+	friendRepo repository.UserFriendRepository,
+	audienceRepo repository.AudienceListRepository,
+	publicIDs PublicIDService,
+	events EventPublisher,
+) (ShareService, error) {
+	// 获取COS客户端
+	cosClient, err := cos.GetStorageClient()
+	if err != nil {
+		return nil, fmt.Errorf("获取COS客户端失败: %w", err)
+	}
+
+	return &shareService{
+		userRepo:      userRepo,
+		postRepo:      postRepo,
+		postImageRepo: postImageRepo,
+		friendRepo:    friendRepo,
+		audienceRepo:  audienceRepo,
+		publicIDs:     publicIDs,
+		events:        events,
+		cosClient:     cosClient,
+	}, nil
+}
+
+// GetQRCode 获取分享二维码
+// 同一深度链接和尺寸的二维码只生成一次，之后直接返回COS中已有的图片
+func (s *shareService) GetQRCode(ctx context.Context, req *dto.ShareQRCodeRequest, userID uint) (*dto.ShareQRCodeResponse, error) {
+	user, post, err := s.loadTarget(ctx, req.Type, req.TargetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	size := config.GetShareConfig().QRCodeSize
+	if size <= 0 {
+		size = constant.ShareQRCodeDefaultSize
+	}
+	// 新生成的链接使用公开ID
+	publicID := user.PublicID
+	if post != nil {
+		publicID = post.PublicID
+	}
+	link := buildDeepLink(req.Type, publicID)
+
+	// 对象键包含链接和尺寸的摘要，配置变更后自动生成新图片
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", link, size)))
+	objectKey := fmt.Sprintf("%s%s/%s/%s.png", constant.ShareQRCodeKeyPrefix, req.Type, publicID, hex.EncodeToString(sum[:8]))
+
+	var fileURL string
+	_, err = s.cosClient.StatFile(ctx, "", objectKey)
+	switch {
+	case err == nil:
+		fileURL, err = s.cosClient.GetFileURL(ctx, "", objectKey, 0)
+		if err != nil {
+			return nil, fmt.Errorf("获取二维码地址失败: %w", err)
+		}
+	case errors.Is(err, cos.ErrObjectNotFound):
+		data, err := qrcode.Encode(link, size)
+		if err != nil {
+			return nil, fmt.Errorf("生成二维码失败: %w", err)
+		}
+		fileURL, err = s.cosClient.UploadFile(ctx, "", objectKey, bytes.NewReader(data), "image/png")
+		if err != nil {
+			return nil, fmt.Errorf("上传二维码失败: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("查询二维码失败: %w", err)
+	}
+
+	if post != nil {
+		s.events.Publish(ctx, constant.EventPostShared, dto.PostSharedData{
+			PostID:    post.ID,
+			UserID:    userID,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return &dto.ShareQRCodeResponse{
+		URL:      imageURL(fileURL, ""),
+		DeepLink: link,
+	}, nil
+}
+
+// ResolveScan 解析扫码内容
+func (s *shareService) ResolveScan(ctx context.Context, req *dto.ResolveScanRequest, userID uint) (*dto.ResolveScanResponse, error) {
+	targetType, rawID, err := parseDeepLink(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := s.resolveLinkTarget(ctx, targetType, rawID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, post, err := s.loadTarget(ctx, targetType, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ResolveScanResponse{
+		Type: targetType,
+		ID:   user.PublicID,
+	}
+	brief := dto.UserBrief{
+		ID:       user.PublicID,
+		Nickname: user.Nickname,
+		Avatar:   avatarURL(user),
+	}
+
+	if post == nil {
+		resp.User = &brief
+		return resp, nil
+	}
+
+	resp.ID = post.PublicID
+	sharedPost := &dto.SharedPost{
+		ID:       post.PublicID,
+		Author:   brief,
+		Content:  truncateRunes(post.Content, constant.ShareContentSummaryLength),
+		Likes:    post.Likes,
+		Comments: post.Comments,
+	}
+	if images, err := s.postImageRepo.GetPostImages(ctx, post.ID); err == nil && len(images) > 0 {
+		cover := &images[0]
+		sharedPost.CoverURL = thumbURL(storedImageURL(ctx, s.cosClient, cover.Bucket, cover.ObjectKey, postImageURL(cover), cover.ContentHash))
+	}
+	resp.Post = sharedPost
+
+	return resp, nil
+}
+
+// resolveLinkTarget 将深度链接中的ID解析为内部ID
+// 切换到公开ID前生成的链接使用数字ID，链接经过签名，不受公开ID过渡期配置的限制
+func (s *shareService) resolveLinkTarget(ctx context.Context, targetType, rawID string) (uint, error) {
+	ref := idgen.Ref(rawID)
+	if id, ok := ref.Internal(); ok {
+		return id, nil
+	}
+
+	kind := constant.PublicIDUser
+	if targetType == constant.ShareTargetPost {
+		kind = constant.PublicIDPost
+	}
+	id, err := s.publicIDs.Resolve(ctx, kind, ref)
+	switch {
+	case errors.Is(err, ErrInvalidID):
+		return 0, ErrInvalidShareLink
+	case errors.Is(err, ErrPublicIDNotFound):
+		return 0, ErrShareTargetNotFound
+	case err != nil:
+		return 0, err
+	}
+	return id, nil
+}
+
+// loadTarget 加载分享目标并校验当前用户的访问权限
+// 返回: 目标用户（动态则为作者）、目标动态（目标为用户时为nil）
+// 目标不存在、作者已禁用或无权查看时统一返回ErrShareTargetNotFound，避免泄露内容是否存在
+func (s *shareService) loadTarget(ctx context.Context, targetType string, targetID, userID uint) (*model.User, *model.Post, error) {
+	var post *model.Post
+	ownerID := targetID
+
+	switch targetType {
+	case constant.ShareTargetUser:
+	case constant.ShareTargetPost:
+		p, err := s.postRepo.GetPost(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, ErrShareTargetNotFound
+			}
+			return nil, nil, fmt.Errorf("获取动态失败: %w", err)
+		}
+		visible, err := s.canViewPost(ctx, p, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !visible {
+			return nil, nil, ErrShareTargetNotFound
+		}
+		post = p
+		ownerID = p.UserID
+	default:
+		return nil, nil, ErrShareTargetNotFound
+	}
+
+	user, err := s.userRepo.FindByID(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil, ErrShareTargetNotFound
+		}
+		return nil, nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if user.Status == constant.UserStatusDisabled || user.Status == constant.UserStatusDeactivating {
+		return nil, nil, ErrShareTargetNotFound
+	}
+
+	return user, post, nil
+}
+
+// canViewPost 判断用户是否可以查看动态：公开动态所有人可见，仅好友可见的动态作者和已确认的好友可见，私密动态仅作者可见
+func (s *shareService) canViewPost(ctx context.Context, post *model.Post, userID uint) (bool, error) {
+	if post.UserID == userID {
+		return true, nil
+	}
+
+	switch constant.Visibility(post.Visibility) {
+	case constant.VisibilityPublic:
+		return true, nil
+	case constant.VisibilityFriends:
+		return s.isConfirmedFriend(ctx, userID, post.UserID)
+	case constant.VisibilityList:
+		// 好友列表可见的动态要求查看者仍是作者的好友且在列表中
+		if post.AudienceListID == nil {
+			return false, nil
+		}
+		friend, err := s.isConfirmedFriend(ctx, userID, post.UserID)
+		if err != nil || !friend {
+			return false, err
+		}
+		member, err := s.audienceRepo.IsMember(ctx, *post.AudienceListID, userID)
+		if err != nil {
+			return false, fmt.Errorf("查询好友列表成员失败: %w", err)
+		}
+		return member, nil
+	default:
+		return false, nil
+	}
+}
+
+// isConfirmedFriend 检查用户与作者是否为已确认的好友
+func (s *shareService) isConfirmedFriend(ctx context.Context, userID, authorID uint) (bool, error) {
+	friend, err := s.friendRepo.GetFriend(ctx, userID, authorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("查询好友关系失败: %w", err)
+	}
+	return friend.Status == int(constant.FriendStatusConfirmed), nil
+}
+
+// deepLinkBase 返回去掉末尾斜杠的深度链接前缀
+func deepLinkBase() string {
+	base := config.GetShareConfig().DeepLinkBase
+	if base == "" {
+		base = constant.ShareDefaultDeepLinkBase
+	}
+	return strings.TrimRight(base, "/")
+}
+
+// buildDeepLink 生成带签名的分享深度链接，格式为 前缀/类型/ID?sig=签名
+func buildDeepLink(targetType, targetID string) string {
+	return fmt.Sprintf("%s/%s/%s?%s=%s", deepLinkBase(), targetType, targetID,
+		constant.ShareSignParam, signShareTarget(targetType, targetID))
+}
+
+// parseDeepLink 解析分享深度链接并校验签名，返回目标类型和链接中的ID（公开ID或旧链接的数字ID）
+func parseDeepLink(link string) (string, string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(link), deepLinkBase()+"/")
+	if !ok {
+		return "", "", ErrInvalidShareLink
+	}
+
+	u, err := url.Parse(rest)
+	if err != nil {
+		return "", "", ErrInvalidShareLink
+	}
+	targetType, targetID, ok := strings.Cut(u.Path, "/")
+	if !ok || targetID == "" {
+		return "", "", ErrInvalidShareLink
+	}
+
+	expected := signShareTarget(targetType, targetID)
+	if !hmac.Equal([]byte(u.Query().Get(constant.ShareSignParam)), []byte(expected)) {
+		return "", "", ErrInvalidShareLink
+	}
+
+	return targetType, targetID, nil
+}
+
+// signShareTarget 计算分享目标的HMAC-SHA256签名，截取前缀以缩短二维码内容
+// 数字ID的签名内容与切换到公开ID前一致，已分享的旧链接仍然有效
+func signShareTarget(targetType, targetID string) string {
+	mac := hmac.New(sha256.New, []byte(config.GetShareConfig().SignKey))
+	fmt.Fprintf(mac, "%s:%s", targetType, targetID)
+	return hex.EncodeToString(mac.Sum(nil))[:constant.ShareSignLength]
+}
+
+// truncateRunes 按字符截断文本，超出部分以省略号代替
+func truncateRunes(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit]) + "…"
+}
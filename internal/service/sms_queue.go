@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+	"app/pkg/sms"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 队列消息的字段名
+const (
+	smsQueueField          = "record_id"  // 短信记录ID
+	smsQueueRequestIDField = "request_id" // 加入队列时的请求ID，发送时沿用以便关联日志
+)
+
+// SMSQueue 短信发送队列
+// 发送请求先保存为待发送的短信记录并加入Redis Streams，由后台消费者发送并更新记录状态
+// 发送失败的消息不确认，超过重试间隔后重新认领发送，超过最大尝试次数后标记为发送失败
+type SMSQueue interface {
+	// Enqueue 保存待发送的短信记录并加入发送队列，TemplateParam为JSON格式的模板参数
+	Enqueue(ctx context.Context, record *model.SMSRecord) error
+	// Start 启动后台消费者，重复调用无效
+	Start()
+	// Stop 停止后台消费者，等待正在发送的短信处理完毕
+	Stop()
+}
+
+// smsQueue 基于Redis Streams消费者组的短信发送队列实现
+type smsQueue struct {
+	smsRepo  repository.SMSRepository
+	consumer string // 消费者名称，每个进程唯一
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSMSQueue 创建短信发送队列实例
+func NewSMSQueue(smsRepo repository.SMSRepository) SMSQueue {
+	hostname, _ := os.Hostname()
+	return &smsQueue{
+		smsRepo:  smsRepo,
+		consumer: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Enqueue 保存待发送的短信记录并加入发送队列，加入队列失败时将记录标记为发送失败
+func (q *smsQueue) Enqueue(ctx context.Context, record *model.SMSRecord) error {
+	record.Status = constant.SMSStatusPending
+	if err := q.smsRepo.Create(ctx, record); err != nil {
+		return fmt.Errorf("保存短信记录失败: %w", err)
+	}
+
+	args := &goredis.XAddArgs{
+		Stream: cachekey.SMSQueue().String(),
+		MaxLen: constant.SMSQueueMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{smsQueueField: record.ID, smsQueueRequestIDField: logger.RequestID(ctx)},
+	}
+	if _, err := redis.XAdd(args); err != nil {
+		record.Status = constant.SMSStatusFailed
+		record.ErrorMessage = "加入发送队列失败"
+		_ = q.smsRepo.Update(ctx, record)
+		return fmt.Errorf("短信加入发送队列失败: %w", err)
+	}
+	return nil
+}
+
+// Start 启动后台消费者
+func (q *smsQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+	q.done = make(chan struct{})
+	go func() {
+		defer close(q.done)
+		q.run(ctx)
+	}()
+}
+
+// Stop 停止后台消费者，未发送的消息留在队列中由下次启动的消费者处理
+func (q *smsQueue) Stop() {
+	q.mu.Lock()
+	cancel, done := q.cancel, q.done
+	q.cancel = nil
+	q.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// run 循环消费队列直到ctx取消
+// 每轮先认领超过重试间隔未确认的消息（发送失败待重试或其他消费者未处理完），没有时阻塞读取新消息
+func (q *smsQueue) run(ctx context.Context) {
+	stream := cachekey.SMSQueue().String()
+	groupReady := false
+
+	for ctx.Err() == nil {
+		if !groupReady {
+			if err := q.createGroup(stream); err != nil {
+				logger.Error(ctx, "创建短信队列消费者组失败", logger.Err(err))
+				q.backoff(ctx)
+				continue
+			}
+			groupReady = true
+		}
+
+		messages, err := q.fetch(stream)
+		if err != nil {
+			// 流被删除后消费者组随之删除，需重新创建
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				groupReady = false
+			}
+			logger.Error(ctx, "读取短信发送队列失败", logger.Err(err))
+			q.backoff(ctx)
+			continue
+		}
+
+		// 停止消费者时已取出的消息仍需发送完毕并更新记录
+		for _, msg := range messages {
+			q.handle(context.WithoutCancel(ctx), stream, msg)
+		}
+	}
+}
+
+// createGroup 创建消费者组，已存在时忽略
+func (q *smsQueue) createGroup(stream string) error {
+	_, err := redis.XGroupCreateMkStream(stream, constant.SMSQueueGroup, "0")
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// fetch 获取待处理的消息，优先认领需要重试的消息
+func (q *smsQueue) fetch(stream string) ([]goredis.XMessage, error) {
+	claimArgs := &goredis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    constant.SMSQueueGroup,
+		Consumer: q.consumer,
+		MinIdle:  constant.SMSQueueRetryInterval,
+		Start:    "0",
+		Count:    constant.SMSQueueBatchSize,
+	}
+	claimed, _, err := redis.XAutoClaim(claimArgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(claimed) > 0 {
+		return claimed, nil
+	}
+
+	readArgs := &goredis.XReadGroupArgs{
+		Group:    constant.SMSQueueGroup,
+		Consumer: q.consumer,
+		Streams:  []string{stream, ">"},
+		Count:    constant.SMSQueueBatchSize,
+		Block:    constant.SMSQueueBlock,
+	}
+	streams, err := redis.XReadGroup(readArgs)
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []goredis.XMessage
+	for _, s := range streams {
+		messages = append(messages, s.Messages...)
+	}
+	return messages, nil
+}
+
+// handle 发送一条消息对应的短信，处理完毕（成功、记录不存在或超过最大尝试次数）时确认并删除消息
+func (q *smsQueue) handle(ctx context.Context, stream string, msg goredis.XMessage) {
+	if q.send(ctx, msg) {
+		if _, err := redis.XAck(stream, constant.SMSQueueGroup, msg.ID); err != nil {
+			logger.Error(ctx, "确认短信队列消息失败", logger.String("message_id", msg.ID), logger.Err(err))
+			return
+		}
+		_, _ = redis.XDel(stream, msg.ID)
+	}
+}
+
+// send 发送短信并更新记录状态，返回消息是否已处理完毕
+func (q *smsQueue) send(ctx context.Context, msg goredis.XMessage) bool {
+	if requestID, _ := msg.Values[smsQueueRequestIDField].(string); requestID != "" {
+		ctx = logger.WithRequestID(ctx, requestID)
+	}
+
+	value, _ := msg.Values[smsQueueField].(string)
+	recordID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		logger.Error(ctx, "短信队列消息格式错误", logger.String("message_id", msg.ID))
+		return true
+	}
+
+	record, err := q.smsRepo.FindByID(ctx, uint(recordID))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return true
+		}
+		logger.Error(ctx, "查询短信记录失败", logger.Uint("record_id", uint(recordID)), logger.Err(err))
+		return false
+	}
+	// 重复投递的消息，记录已处理过
+	if record.Status != constant.SMSStatusPending {
+		return true
+	}
+
+	params := map[string]string{}
+	if record.TemplateParam != "" {
+		if err := json.Unmarshal([]byte(record.TemplateParam), &params); err != nil {
+			record.Status = constant.SMSStatusFailed
+			record.ErrorMessage = "短信模板参数格式错误"
+			_ = q.smsRepo.Update(ctx, record)
+			return true
+		}
+	}
+
+	record.Attempts++
+	resp, sendErr := q.dispatch(ctx, record, params)
+	if sendErr == nil {
+		record.Status = constant.SMSStatusSuccess
+		record.ErrorMessage = ""
+		record.RequestId = resp.RequestId
+		record.BizId = resp.BizId
+	} else {
+		record.ErrorMessage = sendErr.Error()
+		if record.Attempts >= constant.SMSQueueMaxAttempts {
+			record.Status = constant.SMSStatusFailed
+		}
+	}
+	if err := q.smsRepo.Update(ctx, record); err != nil {
+		logger.Error(ctx, "更新短信记录失败", logger.Uint("record_id", record.ID), logger.Err(err))
+	}
+
+	switch {
+	case sendErr == nil:
+		logger.Info(ctx, "短信发送成功", logger.Uint("record_id", record.ID), logger.Int("attempts", record.Attempts))
+		return true
+	case record.Status == constant.SMSStatusFailed:
+		logger.Error(ctx, "短信发送失败，已达到最大尝试次数", logger.Uint("record_id", record.ID), logger.Int("attempts", record.Attempts), logger.Err(sendErr))
+		return true
+	default:
+		logger.Warn(ctx, "短信发送失败，等待重试", logger.Uint("record_id", record.ID), logger.Int("attempts", record.Attempts), logger.Err(sendErr))
+		return false
+	}
+}
+
+// dispatch 调用短信服务商发送短信
+func (q *smsQueue) dispatch(ctx context.Context, record *model.SMSRecord, params map[string]string) (*sms.SMSResponse, error) {
+	client, err := sms.GetSMSClient()
+	if err != nil {
+		return nil, fmt.Errorf("创建短信客户端失败: %w", err)
+	}
+	return client.SendSMS(ctx, sms.SMSRequest{
+		PhoneNumbers:  record.PhoneNumber,
+		TemplateCode:  record.TemplateCode,
+		TemplateParam: params,
+	})
+}
+
+// backoff 读取队列失败后等待一段时间再重试
+func (q *smsQueue) backoff(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(constant.SMSQueueErrorBackoff):
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// 统计相关错误
+var (
+	// ErrInvalidStatsDate 统计日期格式错误
+	ErrInvalidStatsDate = errors.New("日期格式错误，应为YYYY-MM-DD")
+	// ErrInvalidStatsRange 统计日期范围错误
+	ErrInvalidStatsRange = fmt.Errorf("日期范围无效，开始日期不能晚于结束日期且跨度不能超过%d天", constant.StatsMaxQueryDays)
+)
+
+// StatsService 数据统计服务接口
+type StatsService interface {
+	// GenerateDailySnapshot 生成指定日期的统计快照并持久化
+	GenerateDailySnapshot(ctx context.Context, date time.Time) (*model.DailyStatistics, error)
+	// GetStats 获取日期范围内的统计数据
+	GetStats(ctx context.Context, req *dto.GetStatsRequest) (*dto.GetStatsResponse, error)
+}
+
+// statsService 数据统计服务实现
+type statsService struct {
+	statsRepo repository.StatisticsRepository
+}
+
+// NewStatsService 创建数据统计服务实例
+func NewStatsService(statsRepo repository.StatisticsRepository) StatsService {
+	return &statsService{
+		statsRepo: statsRepo,
+	}
+}
+
+// GenerateDailySnapshot 生成指定日期的统计快照并持久化
+func (s *statsService) GenerateDailySnapshot(ctx context.Context, date time.Time) (*model.DailyStatistics, error) {
+	start := truncateToDay(date)
+	end := start.AddDate(0, 0, 1)
+
+	newUsers, err := s.statsRepo.CountNewUsers(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计新注册用户失败: %w", err)
+	}
+
+	totalUsers, err := s.statsRepo.CountUsers(ctx, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计累计用户失败: %w", err)
+	}
+
+	newPosts, err := s.statsRepo.CountPosts(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计新增动态失败: %w", err)
+	}
+
+	newComments, err := s.statsRepo.CountComments(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计新增评论失败: %w", err)
+	}
+
+	smsCount, err := s.statsRepo.CountSuccessSMS(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计短信发送量失败: %w", err)
+	}
+
+	storageBytes, err := s.statsRepo.SumStorageBytes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("统计存储占用失败: %w", err)
+	}
+
+	// 日活数据来自Redis HyperLogLog，读取失败时不影响其他指标
+	activeUsers, err := redis.PFCount(cachekey.StatsDAU(start).String())
+	if err != nil {
+		logger.Warn(ctx, "读取日活跃用户数失败", logger.String("date", start.Format(constant.StatsDateLayout)), logger.Err(err))
+		activeUsers = 0
+	}
+
+	unitPrice := config.GetSMSConfig().Aliyun.UnitPrice
+	stats := &model.DailyStatistics{
+		Date:         start,
+		ActiveUsers:  activeUsers,
+		NewUsers:     newUsers,
+		TotalUsers:   totalUsers,
+		NewPosts:     newPosts,
+		NewComments:  newComments,
+		SMSCount:     smsCount,
+		SMSCost:      math.Round(float64(smsCount)*unitPrice*1000) / 1000,
+		StorageBytes: storageBytes,
+	}
+
+	if err := s.statsRepo.SaveDailyStatistics(ctx, stats); err != nil {
+		return nil, fmt.Errorf("保存统计快照失败: %w", err)
+	}
+
+	logger.Info(ctx, "生成每日统计快照成功",
+		logger.String("date", start.Format(constant.StatsDateLayout)),
+		logger.Int64("active_users", activeUsers),
+		logger.Int64("new_users", newUsers),
+		logger.Int64("new_posts", newPosts))
+
+	return stats, nil
+}
+
+// GetStats 获取日期范围内的统计数据
+func (s *statsService) GetStats(ctx context.Context, req *dto.GetStatsRequest) (*dto.GetStatsResponse, error) {
+	// 默认查询最近7天
+	startDate, endDate, err := parseStatsDateRange(req.StartDate, req.EndDate, truncateToDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.statsRepo.GetDailyStatistics(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("获取统计数据失败: %w", err)
+	}
+
+	// 构建响应数据
+	resp := &dto.GetStatsResponse{
+		StartDate: startDate.Format(constant.StatsDateLayout),
+		EndDate:   endDate.Format(constant.StatsDateLayout),
+		List:      make([]dto.DailyStatsItem, 0, len(snapshots)),
+	}
+	for _, item := range snapshots {
+		resp.List = append(resp.List, dto.DailyStatsItem{
+			Date:         item.Date.Format(constant.StatsDateLayout),
+			ActiveUsers:  item.ActiveUsers,
+			NewUsers:     item.NewUsers,
+			TotalUsers:   item.TotalUsers,
+			NewPosts:     item.NewPosts,
+			NewComments:  item.NewComments,
+			SMSCount:     item.SMSCount,
+			SMSCost:      item.SMSCost,
+			StorageBytes: item.StorageBytes,
+		})
+
+		resp.Summary.NewUsers += item.NewUsers
+		resp.Summary.NewPosts += item.NewPosts
+		resp.Summary.NewComments += item.NewComments
+		resp.Summary.SMSCount += item.SMSCount
+		resp.Summary.SMSCost += item.SMSCost
+		if item.ActiveUsers > resp.Summary.PeakDAU {
+			resp.Summary.PeakDAU = item.ActiveUsers
+		}
+		resp.Summary.StorageBytes = item.StorageBytes
+	}
+	resp.Summary.SMSCost = math.Round(resp.Summary.SMSCost*1000) / 1000
+
+	return resp, nil
+}
+
+// parseStatsDateRange 解析统计查询的日期范围，未指定结束日期时使用defaultEnd，未指定开始日期时查询结束日期前7天
+func parseStatsDateRange(start, end string, defaultEnd time.Time) (time.Time, time.Time, error) {
+	endDate := defaultEnd
+	if end != "" {
+		d, err := time.ParseInLocation(constant.StatsDateLayout, end, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatsDate
+		}
+		endDate = d
+	}
+	startDate := endDate.AddDate(0, 0, -6)
+	if start != "" {
+		d, err := time.ParseInLocation(constant.StatsDateLayout, start, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatsDate
+		}
+		startDate = d
+	}
+
+	if startDate.After(endDate) || endDate.Sub(startDate) >= constant.StatsMaxQueryDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidStatsRange
+	}
+	return startDate, endDate, nil
+}
+
+// truncateToDay 将时间截断到本地时区当天零点
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// 用户标签相关错误
+var (
+	// ErrTagNotFound 标签不存在
+	ErrTagNotFound = errors.New(constant.ErrTagNotFound)
+	// ErrTagInvalidName 标签名称格式错误
+	ErrTagInvalidName = errors.New(constant.ErrTagInvalidName)
+	// ErrTagNameTaken 标签名称已存在
+	ErrTagNameTaken = errors.New(constant.ErrTagNameTaken)
+	// ErrTagInvalidRule 规则无效
+	ErrTagInvalidRule = errors.New(constant.ErrTagInvalidRule)
+	// ErrTagRuleManaged 规则标签不能手动修改成员
+	ErrTagRuleManaged = errors.New(constant.ErrTagRuleManaged)
+)
+
+// tagNameRegexp 标签名称格式
+var tagNameRegexp = regexp.MustCompile(constant.TagNamePattern)
+
+// SegmentMembership 用户分群成员查询接口，供功能开关、公告和推送等按标签圈选用户
+type SegmentMembership interface {
+	// UserTagNames 获取用户拥有的标签名称，查询失败时返回nil
+	UserTagNames(ctx context.Context, userID uint) []string
+	// MemberIDs 按用户ID顺序分批获取标签成员ID
+	MemberIDs(ctx context.Context, tagName string, afterID uint, limit int) ([]uint, error)
+}
+
+// TagService 用户标签服务接口
+type TagService interface {
+	SegmentMembership
+	// ListTags 获取所有标签
+	ListTags(ctx context.Context) (*dto.ListTagsResponse, error)
+	// CreateTag 创建标签，规则标签的成员在下次定时计算后生效
+	CreateTag(ctx context.Context, req *dto.CreateTagRequest) (*dto.TagInfo, error)
+	// UpdateTag 更新标签说明或规则
+	UpdateTag(ctx context.Context, id uint, req *dto.UpdateTagRequest) (*dto.TagInfo, error)
+	// DeleteTag 删除标签及其成员记录
+	DeleteTag(ctx context.Context, id uint) error
+	// AddMembers 向手动标签添加用户，不存在或状态异常的用户忽略
+	AddMembers(ctx context.Context, id uint, userIDs []uint) (*dto.TagMembersResponse, error)
+	// RemoveMembers 从手动标签移除用户
+	RemoveMembers(ctx context.Context, id uint, userIDs []uint) (*dto.TagMembersResponse, error)
+	// GetMembers 分页获取标签成员
+	GetMembers(ctx context.Context, id uint, page, size int) (*dto.GetTagMembersResponse, error)
+	// GetUserTags 获取用户拥有的所有标签
+	GetUserTags(ctx context.Context, userID uint) (*dto.UserTagsResponse, error)
+	// ValidateNames 校验标签名称均已创建
+	ValidateNames(ctx context.Context, names []string) error
+	// RefreshSegments 按规则重新计算所有规则标签的成员，由定时任务调用，返回计算的标签数量
+	RefreshSegments(ctx context.Context) (int, error)
+}
+
+// tagService 用户标签服务实现
+type tagService struct {
+	tagRepo  repository.TagRepository
+	userRepo repository.UserRepository
+}
+
+// NewTagService 创建用户标签服务实例
+func NewTagService(tagRepo repository.TagRepository, userRepo repository.UserRepository) TagService {
+	return &tagService{
+		tagRepo:  tagRepo,
+		userRepo: userRepo,
+	}
+}
+
+// ListTags 获取所有标签
+func (s *tagService) ListTags(ctx context.Context) (*dto.ListTagsResponse, error) {
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询标签列表失败: %w", err)
+	}
+
+	list := make([]dto.TagInfo, 0, len(tags))
+	for i := range tags {
+		list = append(list, toTagInfo(&tags[i]))
+	}
+	return &dto.ListTagsResponse{List: list, Rules: constant.TagRules}, nil
+}
+
+// CreateTag 创建标签
+func (s *tagService) CreateTag(ctx context.Context, req *dto.CreateTagRequest) (*dto.TagInfo, error) {
+	if !tagNameRegexp.MatchString(req.Name) {
+		return nil, ErrTagInvalidName
+	}
+
+	existing, err := s.tagRepo.FindByNames(ctx, []string{req.Name})
+	if err != nil {
+		return nil, fmt.Errorf("查询标签失败: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, ErrTagNameTaken
+	}
+
+	tag := &model.Tag{
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+	}
+	if req.Type == constant.TagTypeRule {
+		if !slices.Contains(constant.TagRules, req.Rule) || req.Threshold < 1 {
+			return nil, ErrTagInvalidRule
+		}
+		tag.Rule = req.Rule
+		tag.Threshold = req.Threshold
+	}
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, fmt.Errorf("保存标签失败: %w", err)
+	}
+
+	logger.Info(ctx, "标签已创建", logger.Uint("tag_id", tag.ID), logger.String("name", tag.Name), logger.String("type", tag.Type))
+
+	info := toTagInfo(tag)
+	return &info, nil
+}
+
+// UpdateTag 更新标签，修改规则后成员在下次定时计算后更新
+func (s *tagService) UpdateTag(ctx context.Context, id uint, req *dto.UpdateTagRequest) (*dto.TagInfo, error) {
+	tag, err := s.getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		tag.Description = *req.Description
+	}
+	if req.Rule != nil || req.Threshold != nil {
+		if tag.Type != constant.TagTypeRule {
+			return nil, ErrTagInvalidRule
+		}
+		if req.Rule != nil {
+			if !slices.Contains(constant.TagRules, *req.Rule) {
+				return nil, ErrTagInvalidRule
+			}
+			tag.Rule = *req.Rule
+		}
+		if req.Threshold != nil {
+			if *req.Threshold < 1 {
+				return nil, ErrTagInvalidRule
+			}
+			tag.Threshold = *req.Threshold
+		}
+	}
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, fmt.Errorf("更新标签失败: %w", err)
+	}
+
+	info := toTagInfo(tag)
+	return &info, nil
+}
+
+// DeleteTag 删除标签，引用该标签的功能开关和公告不再匹配任何用户
+func (s *tagService) DeleteTag(ctx context.Context, id uint) error {
+	if _, err := s.getTag(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.tagRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("删除标签失败: %w", err)
+	}
+
+	logger.Info(ctx, "标签已删除", logger.Uint("tag_id", id))
+	return nil
+}
+
+// AddMembers 向手动标签添加用户
+func (s *tagService) AddMembers(ctx context.Context, id uint, userIDs []uint) (*dto.TagMembersResponse, error) {
+	tag, err := s.getManualTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindNormalByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	ids := make([]uint, len(users))
+	for i := range users {
+		ids[i] = users[i].ID
+	}
+
+	added, err := s.tagRepo.AddMembers(ctx, tag.ID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("添加标签成员失败: %w", err)
+	}
+	if err := s.tagRepo.RefreshMemberCount(ctx, tag.ID); err != nil {
+		logger.Warn(ctx, "更新标签成员数量失败", logger.Uint("tag_id", tag.ID), logger.Err(err))
+	}
+
+	return &dto.TagMembersResponse{Affected: added}, nil
+}
+
+// RemoveMembers 从手动标签移除用户
+func (s *tagService) RemoveMembers(ctx context.Context, id uint, userIDs []uint) (*dto.TagMembersResponse, error) {
+	tag, err := s.getManualTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.tagRepo.RemoveMembers(ctx, tag.ID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("移除标签成员失败: %w", err)
+	}
+	if err := s.tagRepo.RefreshMemberCount(ctx, tag.ID); err != nil {
+		logger.Warn(ctx, "更新标签成员数量失败", logger.Uint("tag_id", tag.ID), logger.Err(err))
+	}
+
+	return &dto.TagMembersResponse{Affected: removed}, nil
+}
+
+// GetMembers 分页获取标签成员
+func (s *tagService) GetMembers(ctx context.Context, id uint, page, size int) (*dto.GetTagMembersResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	if _, err := s.getTag(ctx, id); err != nil {
+		return nil, err
+	}
+
+	members, total, err := s.tagRepo.GetMembers(ctx, id, page, size)
+	if err != nil {
+		return nil, fmt.Errorf("查询标签成员失败: %w", err)
+	}
+
+	ids := make([]uint, len(members))
+	for i := range members {
+		ids[i] = members[i].UserID
+	}
+	users, err := s.userRepo.FindNormalByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	nicknames := make(map[uint]string, len(users))
+	for _, user := range users {
+		nicknames[user.ID] = user.Nickname
+	}
+
+	list := make([]dto.TagMemberInfo, 0, len(members))
+	for _, member := range members {
+		list = append(list, dto.TagMemberInfo{
+			UserID:    member.UserID,
+			Nickname:  nicknames[member.UserID],
+			CreatedAt: member.CreatedAt,
+		})
+	}
+	return &dto.GetTagMembersResponse{Total: total, List: list}, nil
+}
+
+// GetUserTags 获取用户拥有的所有标签
+func (s *tagService) GetUserTags(ctx context.Context, userID uint) (*dto.UserTagsResponse, error) {
+	tags, err := s.tagRepo.GetUserTags(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询用户标签失败: %w", err)
+	}
+
+	list := make([]dto.TagInfo, 0, len(tags))
+	for i := range tags {
+		list = append(list, toTagInfo(&tags[i]))
+	}
+	return &dto.UserTagsResponse{List: list}, nil
+}
+
+// UserTagNames 获取用户拥有的标签名称
+func (s *tagService) UserTagNames(ctx context.Context, userID uint) []string {
+	tags, err := s.tagRepo.GetUserTags(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "查询用户标签失败", logger.Uint("user_id", userID), logger.Err(err))
+		return nil
+	}
+
+	names := make([]string, len(tags))
+	for i := range tags {
+		names[i] = tags[i].Name
+	}
+	return names
+}
+
+// MemberIDs 按用户ID顺序分批获取标签成员ID
+func (s *tagService) MemberIDs(ctx context.Context, tagName string, afterID uint, limit int) ([]uint, error) {
+	return s.tagRepo.GetMemberIDs(ctx, tagName, afterID, limit)
+}
+
+// ValidateNames 校验标签名称均已创建
+func (s *tagService) ValidateNames(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags, err := s.tagRepo.FindByNames(ctx, names)
+	if err != nil {
+		return fmt.Errorf("查询标签失败: %w", err)
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(tags, func(tag model.Tag) bool { return tag.Name == name }) {
+			return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+		}
+	}
+	return nil
+}
+
+// RefreshSegments 按规则重新计算所有规则标签的成员
+// 单个标签计算失败时继续计算其他标签，全部完成后返回第一个错误
+func (s *tagService) RefreshSegments(ctx context.Context) (int, error) {
+	tags, err := s.tagRepo.FindRuleTags(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("查询规则标签失败: %w", err)
+	}
+
+	refreshed := 0
+	var firstErr error
+	for i := range tags {
+		if err := s.refreshTag(ctx, &tags[i]); err != nil {
+			logger.Error(ctx, "计算规则标签成员失败", logger.Uint("tag_id", tags[i].ID), logger.String("name", tags[i].Name), logger.Err(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, firstErr
+}
+
+// refreshTag 计算单个规则标签的成员
+// 匹配的用户写入本次计算时间，全部写入后移除计算时间早于本次的成员，计算中途失败时保留原有成员
+func (s *tagService) refreshTag(ctx context.Context, tag *model.Tag) error {
+	// 数据库时间精度为秒，截断后比较避免误删本次写入的成员
+	now := time.Now().Truncate(time.Second)
+
+	var afterID uint
+	matched := 0
+	for {
+		ids, err := s.tagRepo.FindRuleMatches(ctx, tag, now, afterID, constant.TagRefreshBatchSize)
+		if err != nil {
+			return fmt.Errorf("查询匹配用户失败: %w", err)
+		}
+		if err := s.tagRepo.UpsertRuleMembers(ctx, tag.ID, ids, now); err != nil {
+			return fmt.Errorf("写入标签成员失败: %w", err)
+		}
+		matched += len(ids)
+		if len(ids) < constant.TagRefreshBatchSize {
+			break
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	removed, err := s.tagRepo.FinishRefresh(ctx, tag.ID, now)
+	if err != nil {
+		return fmt.Errorf("移除过期标签成员失败: %w", err)
+	}
+
+	logger.Info(ctx, "规则标签成员已更新",
+		logger.Uint("tag_id", tag.ID),
+		logger.String("name", tag.Name),
+		logger.Int("matched", matched),
+		logger.Int64("removed", removed))
+	return nil
+}
+
+// getTag 获取标签，不存在时返回ErrTagNotFound
+func (s *tagService) getTag(ctx context.Context, id uint) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("查询标签失败: %w", err)
+	}
+	return tag, nil
+}
+
+// getManualTag 获取手动标签，规则标签返回ErrTagRuleManaged
+func (s *tagService) getManualTag(ctx context.Context, id uint) (*model.Tag, error) {
+	tag, err := s.getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Type != constant.TagTypeManual {
+		return nil, ErrTagRuleManaged
+	}
+	return tag, nil
+}
+
+// toTagInfo 转换为标签信息
+func toTagInfo(tag *model.Tag) dto.TagInfo {
+	return dto.TagInfo{
+		ID:          tag.ID,
+		Name:        tag.Name,
+		Description: tag.Description,
+		Type:        tag.Type,
+		Rule:        tag.Rule,
+		Threshold:   tag.Threshold,
+		MemberCount: tag.MemberCount,
+		RefreshedAt: tag.RefreshedAt,
+		CreatedAt:   tag.CreatedAt,
+		UpdatedAt:   tag.UpdatedAt,
+	}
+}
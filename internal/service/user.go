@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"app/config"
+	"app/internal/cachekey"
 	"app/internal/constant"
 	"app/internal/dto"
 	"app/internal/model"
@@ -15,7 +18,6 @@ import (
 	"app/pkg/jwt"
 	"app/pkg/logger"
 	"app/pkg/redis"
-	"app/pkg/sms"
 )
 
 // 错误常量定义
@@ -24,8 +26,14 @@ var (
 	ErrUserNotFound = errors.New(constant.ErrUserNotFound)
 	// ErrInvalidCode 验证码无效错误
 	ErrInvalidCode = errors.New(constant.ErrInvalidCode)
+	// ErrCodeLocked 验证码错误次数过多错误
+	ErrCodeLocked = errors.New(constant.ErrCodeLocked)
 	// ErrDeactivateFailed 注销失败错误
 	ErrDeactivateFailed = errors.New(constant.ErrDeactivateFailed)
+	// ErrAccountDeactivated 注销冷静期已结束，账号已注销错误
+	ErrAccountDeactivated = errors.New(constant.ErrAccountDeactivated)
+	// ErrAccountDisabled 账号已被禁用错误
+	ErrAccountDisabled = errors.New(constant.ErrAccountDisabled)
 )
 
 // UserService 用户服务接口
@@ -36,29 +44,52 @@ type UserService interface {
 	VerificationCodeLogin(ctx context.Context, req *dto.VerificationCodeLoginRequest) (*dto.LoginResponse, error)
 	// Logout 退出登录
 	Logout(ctx context.Context, req *dto.LogoutRequest) (*dto.LogoutResponse, error)
-	// DeactivateAccount 注销账号
-	DeactivateAccount(ctx context.Context, req *dto.DeactivateAccountRequest) error
+	// LogoutAll 退出所有设备，此前签发的令牌全部失效
+	LogoutAll(ctx context.Context, userID uint) error
+	// GetTokenVersion 获取用户当前的令牌版本号，低于该版本的令牌已失效
+	GetTokenVersion(ctx context.Context, userID uint) (uint, error)
+	// DeactivateAccount 申请注销账号，账号进入注销冷静期
+	DeactivateAccount(ctx context.Context, req *dto.DeactivateAccountRequest) (*dto.DeactivateAccountResponse, error)
 	// GetUserInfo 获取用户信息
 	GetUserInfo(ctx context.Context, id uint) (*dto.UserInfoResponse, error)
+	// SearchUsers 按昵称前缀或手机号搜索用户
+	SearchUsers(ctx context.Context, req *dto.SearchUsersRequest, userID uint) (*dto.SearchUsersResponse, error)
+	// SetMobileSearch 设置是否允许他人通过手机号搜索到自己
+	SetMobileSearch(ctx context.Context, req *dto.SetMobileSearchRequest, userID uint) error
 }
 
 // userService 用户服务实现
 type userService struct {
-	userRepo     repository.UserRepository
-	smsRepo      repository.SMSRepository
-	imageService ImageService
+	userRepo        repository.UserRepository
+	smsQueue        SMSQueue
+	imageService    ImageService
+	deletionService AccountDeletionService
+	notifications   NotificationService
+	events          EventPublisher
+	funnel          FunnelService
+	store           redis.Store
 }
 
 // NewUserService 创建用户服务实例
 func NewUserService(
 	userRepo repository.UserRepository,
-	smsRepo repository.SMSRepository,
+	smsQueue SMSQueue,
 	imageService ImageService,
+	deletionService AccountDeletionService,
+	notifications NotificationService,
+	events EventPublisher,
+	funnel FunnelService,
+	store redis.Store,
 ) UserService {
 	return &userService{
-		userRepo:     userRepo,
-		smsRepo:      smsRepo,
-		imageService: imageService,
+		userRepo:        userRepo,
+		smsQueue:        smsQueue,
+		imageService:    imageService,
+		deletionService: deletionService,
+		notifications:   notifications,
+		events:          events,
+		funnel:          funnel,
+		store:           store,
 	}
 }
 
@@ -68,135 +99,277 @@ func generateVerificationCode(length int) string {
 	return utils.GenerateRandomDigits(length)
 }
 
-// SendVerificationCode 发送验证码
-func (s *userService) SendVerificationCode(ctx context.Context, req *dto.SendVerificationCodeRequest) (*dto.SendVerificationCodeResponse, error) {
-	logger.Info(ctx, "开始处理发送验证码请求", logger.String("mobile", req.Mobile), logger.String("type", string(req.Type)))
-
-	code := generateVerificationCode(constant.VerificationCodeLength)
+// hashVerificationCode 计算验证码摘要，手机号和类型参与计算，避免摘要在不同场景间复用
+func hashVerificationCode(mobile string, codeType dto.VerificationType, code string) string {
+	sum := sha256.Sum256([]byte(mobile + ":" + string(codeType) + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
 
-	// 确定验证码类型前缀
-	prefix := constant.VerificationCodePrefixLogin
-	if req.Type == dto.VerificationTypeDeactivate {
-		prefix = constant.VerificationCodePrefixDeactivate
-	}
+// saveVerificationCodeScript 覆盖保存验证码摘要、类型和尝试次数并设置过期时间
+// 删除旧验证码、写入和设置过期时间在同一脚本中执行，不会留下没有过期时间的验证码
+// KEYS[1]为验证码键；ARGV依次为摘要字段、摘要、类型字段、类型、尝试次数字段和过期时长（毫秒）
+const saveVerificationCodeScript = `
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2], ARGV[3], ARGV[4], ARGV[5], 0)
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+return 1
+`
+
+// verifyCodeScript 校验验证码，返回校验结果和累加后的尝试次数
+// 类型检查、累加尝试次数、锁定检查、比对摘要和删除验证码在同一脚本中执行，
+// 同一验证码并发校验时只有一个请求能够成功，验证码过期后也不会因累加次数而重建
+// 比对的是包含手机号和类型的SHA-256摘要，脚本内的字符串比较不会泄露验证码本身
+// KEYS[1]为验证码键；ARGV依次为摘要字段、类型字段、尝试次数字段、验证码类型、提交的验证码摘要和最大尝试次数
+const verifyCodeScript = `
+local saved = redis.call('HMGET', KEYS[1], ARGV[1], ARGV[2])
+if not saved[1] then
+	return {0, 0}
+end
+if saved[2] ~= ARGV[4] then
+	return {1, 0}
+end
+local attempts = redis.call('HINCRBY', KEYS[1], ARGV[3], 1)
+if attempts > tonumber(ARGV[6]) then
+	return {2, attempts}
+end
+if saved[1] ~= ARGV[5] then
+	return {3, attempts}
+end
+redis.call('DEL', KEYS[1])
+return {4, attempts}
+`
+
+// verifyCodeScript 返回的校验结果
+const (
+	verifyCodeMissing      = 0 // 验证码不存在或已过期
+	verifyCodeTypeMismatch = 1 // 验证码类型不匹配
+	verifyCodeLocked       = 2 // 尝试次数超过上限
+	verifyCodeMismatch     = 3 // 验证码不匹配
+	verifyCodeMatched      = 4 // 校验成功，验证码已删除
+)
 
-	// 保存验证码到Redis
-	key := prefix + req.Mobile
-	err := redis.Set(key, code, constant.VerificationCodeExpiration)
-	if err != nil {
-		logger.Error(ctx, "保存验证码到Redis失败", logger.String("mobile", req.Mobile), logger.String("type", string(req.Type)), logger.Err(err))
-		return nil, fmt.Errorf("保存验证码失败: %w", err)
-	}
+// saveVerificationCode 保存验证码摘要、类型和尝试次数
+func (s *userService) saveVerificationCode(key, mobile string, codeType dto.VerificationType, code string) error {
+	_, err := redis.Eval(saveVerificationCodeScript, []string{key},
+		constant.VerificationCodeFieldHash, hashVerificationCode(mobile, codeType, code),
+		constant.VerificationCodeFieldType, string(codeType),
+		constant.VerificationCodeFieldAttempts,
+		constant.VerificationCodeExpiration.Milliseconds(),
+	)
+	return err
+}
 
-	// 获取短信客户端
-	client, err := sms.GetSMSClient()
+// verifyCode 校验验证码
+// 每次校验先累加尝试次数，超过上限后验证码锁定；校验成功后删除验证码
+func (s *userService) verifyCode(ctx context.Context, key, mobile string, codeType dto.VerificationType, code string) error {
+	result, err := redis.Eval(verifyCodeScript, []string{key},
+		constant.VerificationCodeFieldHash,
+		constant.VerificationCodeFieldType,
+		constant.VerificationCodeFieldAttempts,
+		string(codeType),
+		hashVerificationCode(mobile, codeType, code),
+		constant.VerificationCodeMaxAttempts,
+	)
 	if err != nil {
-		logger.Error(ctx, "创建短信客户端失败", logger.String("mobile", req.Mobile), logger.Err(err))
-		return nil, fmt.Errorf("创建短信客户端失败: %w", err)
+		logger.Error(ctx, "校验验证码失败", logger.String("mobile", mobile), logger.Err(err))
+		return ErrInvalidCode
 	}
-
-	// 获取短信模板
-	smsConfig := config.GetSMSConfig()
-	templateCode := smsConfig.Aliyun.Templates["verification_code"]
-	if templateCode == "" {
-		logger.Error(ctx, "短信模板配置错误", logger.String("mobile", req.Mobile))
-		return nil, fmt.Errorf("短信模板配置错误")
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		logger.Error(ctx, "验证码校验结果格式错误", logger.String("mobile", mobile), logger.Any("result", result))
+		return ErrInvalidCode
 	}
-
-	// 构建短信内容
-	var smsContent string
-	switch req.Type {
-	case dto.VerificationTypeLogin:
-		smsContent = fmt.Sprintf("您的登录验证码是：%s，5分钟内有效。", code)
-	case dto.VerificationTypeDeactivate:
-		smsContent = fmt.Sprintf("您的账号注销验证码是：%s，5分钟内有效。请谨慎操作，注销后账号将无法恢复。", code)
+	status, _ := values[0].(int64)
+	attempts, _ := values[1].(int64)
+
+	switch status {
+	case verifyCodeMatched:
+		logger.Debug(ctx, "验证码验证成功，已删除缓存", logger.String("mobile", mobile), logger.String("type", string(codeType)))
+		return nil
+	case verifyCodeMissing:
+		logger.Warn(ctx, "验证码不存在或已过期", logger.String("mobile", mobile), logger.String("type", string(codeType)))
+		return ErrInvalidCode
+	case verifyCodeTypeMismatch:
+		logger.Warn(ctx, "验证码类型不匹配", logger.String("mobile", mobile), logger.String("type", string(codeType)))
+		return ErrInvalidCode
+	case verifyCodeLocked:
+		logger.Warn(ctx, "验证码已锁定", logger.String("mobile", mobile), logger.String("type", string(codeType)), logger.Int64("attempts", attempts))
+		return ErrCodeLocked
 	default:
-		smsContent = fmt.Sprintf("您的验证码是：%s，5分钟内有效。", code)
+		logger.Warn(ctx, "验证码不匹配", logger.String("mobile", mobile), logger.String("type", string(codeType)), logger.Int64("attempts", attempts))
+		if attempts == constant.VerificationCodeMaxAttempts {
+			return ErrCodeLocked
+		}
+		return ErrInvalidCode
 	}
+}
+
+// SendVerificationCode 发送验证码
+func (s *userService) SendVerificationCode(ctx context.Context, req *dto.SendVerificationCodeRequest) (*dto.SendVerificationCodeResponse, error) {
+	logger.Info(ctx, "开始处理发送验证码请求", logger.String("mobile", req.Mobile), logger.String("type", string(req.Type)))
 
-	// 发送短信
-	smsReq := sms.SMSRequest{
-		PhoneNumbers:  req.Mobile,
-		TemplateCode:  templateCode,
-		TemplateParam: map[string]string{"code": code},
+	// 按验证码类型选择短信模板，未配置模板的类型不发送验证码
+	template, err := verificationTemplate(req.Type)
+	if err != nil {
+		logger.Warn(ctx, "验证码类型未配置短信模板", logger.String("mobile", req.Mobile), logger.String("type", string(req.Type)))
+		return nil, err
 	}
 
-	smsResp, err := client.SendSMS(smsReq)
+	code := generateVerificationCode(constant.VerificationCodeLength)
+	smsContent, templateParam, err := renderVerificationTemplate(template, code)
 	if err != nil {
-		logger.Error(ctx, "发送短信失败", logger.String("mobile", req.Mobile), logger.Err(err))
-		return nil, fmt.Errorf("发送短信失败: %w", err)
+		logger.Error(ctx, "构建验证码短信失败", logger.String("mobile", req.Mobile), logger.String("type", string(req.Type)), logger.Err(err))
+		return nil, fmt.Errorf("构建验证码短信失败: %w", err)
 	}
 
-	// 记录短信发送信息
+	// 保存验证码摘要到Redis，重新发送会覆盖旧验证码并重置尝试次数
+	key := cachekey.VerificationCode(string(req.Type), req.Mobile).String()
+	if err := s.saveVerificationCode(key, req.Mobile, req.Type, code); err != nil {
+		logger.Error(ctx, "保存验证码到Redis失败", logger.String("mobile", req.Mobile), logger.String("type", string(req.Type)), logger.Err(err))
+		return nil, fmt.Errorf("保存验证码失败: %w", err)
+	}
+
+	// 保存短信记录并加入发送队列，由后台消费者发送，短信服务商暂时不可用时自动重试
 	smsRecord := &model.SMSRecord{
 		PhoneNumber:   req.Mobile,
 		Type:          constant.SMSTypeVerification,
 		Content:       smsContent,
-		TemplateCode:  templateCode,
-		TemplateParam: fmt.Sprintf(`{"code":"%s"}`, code),
-		Status:        "success",
-		RequestId:     smsResp.RequestId,
-		BizId:         smsResp.BizId,
+		TemplateCode:  template.Code,
+		TemplateParam: templateParam,
+	}
+	if err := s.smsQueue.Enqueue(ctx, smsRecord); err != nil {
+		logger.Error(ctx, "短信加入发送队列失败", logger.String("mobile", req.Mobile), logger.Err(err))
+		return nil, fmt.Errorf("发送短信失败: %w", err)
 	}
-	_ = s.smsRepo.Create(smsRecord)
 
-	logger.Info(ctx, "验证码发送成功", logger.String("mobile", req.Mobile))
+	logger.Info(ctx, "验证码短信已加入发送队列", logger.String("mobile", req.Mobile), logger.Uint("record_id", smsRecord.ID))
+	if req.Type == dto.VerificationTypeLogin {
+		s.funnel.Record(ctx, FunnelEvent{Step: constant.FunnelCodeSent, Mobile: req.Mobile})
+	}
 
 	return &dto.SendVerificationCodeResponse{Message: "验证码已发送"}, nil
 }
 
-// VerificationCodeLogin 验证码登录
+// VerificationCodeLogin 验证码登录，登录失败时按失败原因记录漏斗事件
 func (s *userService) VerificationCodeLogin(ctx context.Context, req *dto.VerificationCodeLoginRequest) (*dto.LoginResponse, error) {
-	logger.Info(ctx, "开始处理验证码登录请求", logger.String("mobile", req.Mobile))
-
-	// 从Redis获取验证码（登录验证码）
-	key := constant.VerificationCodePrefixLogin + req.Mobile
-	savedCode, err := redis.Get(key)
+	resp, err := s.verificationCodeLogin(ctx, req)
 	if err != nil {
-		logger.Error(ctx, "获取验证码失败", logger.String("mobile", req.Mobile), logger.Err(err))
-		return nil, ErrInvalidCode
+		s.funnel.Record(ctx, FunnelEvent{Step: constant.FunnelLoginFailed, Mobile: req.Mobile, Reason: loginFailReason(err)})
+		return nil, err
 	}
+	return resp, nil
+}
 
-	if savedCode != req.Code {
-		logger.Warn(ctx, "验证码不匹配", logger.String("mobile", req.Mobile), logger.String("input_code", req.Code), logger.String("saved_code", savedCode))
-		return nil, ErrInvalidCode
+// loginFailReason 返回登录错误对应的漏斗失败原因
+func loginFailReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidCode):
+		return constant.LoginFailInvalidCode
+	case errors.Is(err, ErrCodeLocked):
+		return constant.LoginFailCodeLocked
+	case errors.Is(err, ErrAccountDeactivated):
+		return constant.LoginFailDeactivated
+	case errors.Is(err, ErrAccountDisabled):
+		return constant.LoginFailDisabled
+	default:
+		return constant.LoginFailInternal
 	}
+}
 
-	// 验证成功后删除验证码
-	_, _ = redis.Del(key)
-	logger.Debug(ctx, "验证码验证成功，已删除缓存", logger.String("mobile", req.Mobile))
+// verificationCodeLogin 校验验证码并签发令牌，用户不存在时创建新用户
+func (s *userService) verificationCodeLogin(ctx context.Context, req *dto.VerificationCodeLoginRequest) (*dto.LoginResponse, error) {
+	logger.Info(ctx, "开始处理验证码登录请求", logger.String("mobile", req.Mobile))
+
+	// 校验登录验证码
+	key := cachekey.VerificationCode(string(dto.VerificationTypeLogin), req.Mobile).String()
+	if err := s.verifyCode(ctx, key, req.Mobile, dto.VerificationTypeLogin, req.Code); err != nil {
+		return nil, err
+	}
+	s.funnel.Record(ctx, FunnelEvent{Step: constant.FunnelCodeVerified, Mobile: req.Mobile})
 
 	// 查找用户
-	user, err := s.userRepo.FindByMobile(req.Mobile)
+	user, err := s.userRepo.FindByMobile(ctx, req.Mobile)
 	if err != nil {
 		// 如果用户不存在，则创建新用户
 		logger.Info(ctx, "用户不存在，创建新用户", logger.String("mobile", req.Mobile))
 
 		user = &model.User{
-			Mobile:   req.Mobile,
-			Username: req.Mobile,                            // 默认使用手机号作为用户名
-			Nickname: "用户" + req.Mobile[len(req.Mobile)-4:], // 使用手机号后4位作为昵称
-			Status:   constant.UserStatusNormal,             // 正常状态
+			Mobile:     req.Mobile,
+			MobileHash: utils.HashMobile(req.Mobile),
+			Username:   req.Mobile,                            // 默认使用手机号作为用户名
+			Nickname:   "用户" + req.Mobile[len(req.Mobile)-4:], // 使用手机号后4位作为昵称
+			Status:     constant.UserStatusNormal,             // 正常状态
 		}
 
 		// 保存新用户
-		err = s.userRepo.Create(user)
+		err = s.userRepo.Create(ctx, user)
 		if err != nil {
 			logger.Error(ctx, "创建用户失败", logger.String("mobile", req.Mobile), logger.Err(err))
 			return nil, fmt.Errorf("创建用户失败: %w", err)
 		}
 
 		logger.Info(ctx, "新用户创建成功", logger.String("mobile", user.Mobile))
+		s.funnel.Record(ctx, FunnelEvent{Step: constant.FunnelUserCreated, Mobile: req.Mobile, UserID: user.ID})
+
+		// 写入默认通知设置，失败时发送通知仍按默认值处理
+		if err := s.notifications.InitDefaults(ctx, user.ID); err != nil {
+			logger.Warn(ctx, "写入默认通知设置失败", logger.Uint("user_id", user.ID), logger.Err(err))
+		}
+
+		s.events.Publish(ctx, constant.WebhookEventUserCreated, dto.WebhookUserData{
+			ID:        user.ID,
+			PublicID:  user.PublicID,
+			Nickname:  user.Nickname,
+			CreatedAt: user.CreatedAt,
+		})
+	}
+
+	// 休眠用户重新登录后恢复正常状态
+	if user.Status == constant.UserStatusDormant {
+		user.Status = constant.UserStatusNormal
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			logger.Error(ctx, "恢复休眠用户状态失败", logger.String("mobile", user.Mobile), logger.Err(err))
+			return nil, fmt.Errorf("恢复用户状态失败: %w", err)
+		}
+		logger.Info(ctx, "休眠用户已恢复正常状态", logger.String("mobile", user.Mobile))
+	}
+
+	// 注销冷静期内重新登录撤销注销，冷静期已结束但账号尚未删除时按已注销处理
+	deactivationCancelled := false
+	if user.Status == constant.UserStatusDeactivating {
+		if user.DeactivateAt == nil || !time.Now().Before(*user.DeactivateAt) {
+			logger.Warn(ctx, "注销冷静期已结束，拒绝登录", logger.String("mobile", user.Mobile))
+			return nil, ErrAccountDeactivated
+		}
+		cancelled, err := s.userRepo.CancelDeactivation(ctx, user.ID)
+		if err != nil {
+			logger.Error(ctx, "撤销注销失败", logger.String("mobile", user.Mobile), logger.Err(err))
+			return nil, fmt.Errorf("撤销注销失败: %w", err)
+		}
+		if !cancelled {
+			// 撤销前账号已被定时任务删除
+			return nil, ErrAccountDeactivated
+		}
+		user.Status = constant.UserStatusNormal
+		user.DeactivateAt = nil
+		deactivationCancelled = true
+		logger.Info(ctx, "用户重新登录，已撤销注销", logger.String("mobile", user.Mobile))
+
+		s.alertAsync(ctx, user.ID, &NotificationMessage{
+			Title:           "账号注销已撤销",
+			Content:         "您的账号已重新登录，注销申请已撤销。如非本人操作，请及时修改登录手机号。",
+			SMSTemplateCode: config.GetSMSConfig().Aliyun.Templates[constant.DeactivationCancelledSMSTemplateKey],
+		})
 	}
 
 	// 检查用户状态
 	if user.Status != constant.UserStatusNormal {
 		logger.Warn(ctx, "账号已被禁用", logger.String("mobile", user.Mobile), logger.Int("status", user.Status))
-		return nil, errors.New("账号已被禁用")
+		return nil, ErrAccountDisabled
 	}
 
 	// 生成JWT令牌
-	token, err := jwt.GenerateToken(user.ID, user.Username, "")
+	token, err := jwt.GenerateToken(user.ID, user.Username, user.TokenVersion)
 	if err != nil {
 		logger.Error(ctx, "生成令牌失败", logger.Err(err))
 		return nil, fmt.Errorf("生成令牌失败: %w", err)
@@ -204,17 +377,19 @@ func (s *userService) VerificationCodeLogin(ctx context.Context, req *dto.Verifi
 
 	// 构建响应
 	response := &dto.LoginResponse{
-		Token: token,
+		Token:                 token,
+		DeactivationCancelled: deactivationCancelled,
 	}
 
 	// 填充用户信息
-	response.User.ID = user.ID
+	response.User.ID = user.PublicID
 	response.User.Username = user.Username
 	response.User.Mobile = user.Mobile
 	response.User.Nickname = user.Nickname
-	response.User.Avatar = user.Avatar
+	response.User.Avatar = avatarURL(user)
 
 	logger.Info(ctx, "用户登录成功", logger.String("mobile", user.Mobile))
+	s.funnel.Record(ctx, FunnelEvent{Step: constant.FunnelLoginSuccess, Mobile: req.Mobile, UserID: user.ID})
 
 	return response, nil
 }
@@ -223,6 +398,13 @@ func (s *userService) VerificationCodeLogin(ctx context.Context, req *dto.Verifi
 func (s *userService) Logout(ctx context.Context, req *dto.LogoutRequest) (*dto.LogoutResponse, error) {
 	logger.Info(ctx, "开始处理退出登录请求")
 
+	// 退出登录的设备不再接收推送，删除失败不影响退出登录
+	if req.DeviceToken != "" {
+		if err := s.notifications.UnregisterDevice(ctx, req.UserID, req.DeviceToken); err != nil {
+			logger.Warn(ctx, "删除设备推送令牌失败", logger.Uint("user_id", req.UserID), logger.Err(err))
+		}
+	}
+
 	// 解析令牌，获取过期时间
 	claims, err := jwt.ParseToken(req.Token)
 	if err != nil {
@@ -244,9 +426,12 @@ func (s *userService) Logout(ctx context.Context, req *dto.LogoutRequest) (*dto.
 		return &dto.LogoutResponse{Message: "退出登录成功"}, nil
 	}
 
-	// 将令牌加入黑名单，过期时间与令牌相同
-	blacklistKey := constant.TokenBlacklistPrefix + req.Token
-	err = redis.Set(blacklistKey, "revoked", ttl)
+	// 将令牌ID加入黑名单，过期时间与令牌相同，没有ID的令牌按令牌原文记录
+	tokenID := claims.ID
+	if tokenID == "" {
+		tokenID = req.Token
+	}
+	err = s.store.Set(cachekey.TokenBlacklist(tokenID).String(), "revoked", ttl)
 	if err != nil {
 		logger.Error(ctx, "将令牌加入黑名单失败", logger.String("token", req.Token), logger.Err(err))
 		return nil, fmt.Errorf("退出登录失败: %w", err)
@@ -257,54 +442,74 @@ func (s *userService) Logout(ctx context.Context, req *dto.LogoutRequest) (*dto.
 	return &dto.LogoutResponse{Message: "退出登录成功"}, nil
 }
 
-// DeactivateAccount 注销账号
-func (s *userService) DeactivateAccount(ctx context.Context, req *dto.DeactivateAccountRequest) error {
+// DeactivateAccount 申请注销账号
+// 账号进入注销冷静期并立即退出所有设备，冷静期内重新登录可撤销注销，冷静期结束后由定时任务删除账号
+func (s *userService) DeactivateAccount(ctx context.Context, req *dto.DeactivateAccountRequest) (*dto.DeactivateAccountResponse, error) {
 	logger.Info(ctx, "开始处理注销账号请求", logger.String("mobile", req.Mobile))
 
-	// 验证验证码（注销验证码）
-	key := constant.VerificationCodePrefixDeactivate + req.Mobile
-	savedCode, err := redis.Get(key)
-	if err != nil {
-		logger.Error(ctx, "获取注销验证码失败", logger.String("mobile", req.Mobile), logger.Err(err))
-		return ErrInvalidCode
-	}
-
-	if savedCode != req.Code {
-		logger.Warn(ctx, "注销验证码不匹配", logger.String("mobile", req.Mobile), logger.String("input_code", req.Code), logger.String("saved_code", savedCode))
-		return ErrInvalidCode
+	// 校验注销验证码
+	key := cachekey.VerificationCode(string(dto.VerificationTypeDeactivate), req.Mobile).String()
+	if err := s.verifyCode(ctx, key, req.Mobile, dto.VerificationTypeDeactivate, req.Code); err != nil {
+		return nil, err
 	}
 
-	// 验证成功后删除验证码
-	_, _ = redis.Del(key)
-	logger.Debug(ctx, "注销验证码验证成功，已删除缓存", logger.String("mobile", req.Mobile))
-
 	// 查找用户
-	user, err := s.userRepo.FindByID(req.UserID)
+	user, err := s.userRepo.FindByID(ctx, req.UserID)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			logger.Warn(ctx, "要注销的用户不存在")
-			return ErrUserNotFound
+			return nil, ErrUserNotFound
 		}
 		logger.Error(ctx, "查询用户失败", logger.Err(err))
-		return fmt.Errorf("查询用户失败: %w", err)
+		return nil, fmt.Errorf("查询用户失败: %w", err)
 	}
 
 	// 验证手机号是否匹配
 	if user.Mobile != req.Mobile {
 		logger.Warn(ctx, "手机号不匹配，注销失败", logger.String("request_mobile", req.Mobile), logger.String("user_mobile", user.Mobile))
-		return errors.New("手机号不匹配，注销失败")
+		return nil, errors.New("手机号不匹配，注销失败")
 	}
 
-	// 执行注销操作（软删除）
-	err = s.userRepo.SoftDelete(req.UserID)
-	if err != nil {
+	// 已在注销冷静期内时不重新计算冷静期
+	if user.Status == constant.UserStatusDeactivating && user.DeactivateAt != nil {
+		return &dto.DeactivateAccountResponse{DeactivateAt: *user.DeactivateAt}, nil
+	}
+
+	// 进入注销冷静期
+	now := time.Now()
+	deactivateAt := now.Add(constant.DeactivationCoolingOff)
+	if err := s.userRepo.StartDeactivation(ctx, user.ID, deactivateAt); err != nil {
 		logger.Error(ctx, "执行账号注销失败", logger.Err(err))
-		return ErrDeactivateFailed
+		return nil, ErrDeactivateFailed
+	}
+
+	// 使已签发的令牌全部失效，冷静期内账号暂停使用；账号已进入冷静期，失败时只记录错误
+	revokedKey := cachekey.TokenRevokedBefore(user.ID)
+	if err := s.store.Set(revokedKey.String(), now.Unix(), revokedKey.TTL()); err != nil {
+		logger.Error(ctx, "记录令牌失效时间失败", logger.Uint("user_id", user.ID), logger.Err(err))
 	}
 
-	logger.Info(ctx, "账号注销成功", logger.String("mobile", user.Mobile))
+	s.alertAsync(ctx, user.ID, &NotificationMessage{
+		Title:           "账号注销申请已提交",
+		Content:         fmt.Sprintf("您的账号将于%s注销，此前重新登录即可撤销注销。如非本人操作，请尽快登录。", deactivateAt.Format("2006-01-02 15:04")),
+		SMSTemplateCode: config.GetSMSConfig().Aliyun.Templates[constant.DeactivationRequestedSMSTemplateKey],
+	})
+
+	logger.Info(ctx, "账号进入注销冷静期", logger.String("mobile", user.Mobile), logger.String("deactivate_at", deactivateAt.Format(time.RFC3339)))
+
+	return &dto.DeactivateAccountResponse{DeactivateAt: deactivateAt}, nil
+}
+
+// alertAsync 在后台通过所有渠道向用户发送账号安全通知，不影响当前请求
+func (s *userService) alertAsync(ctx context.Context, userID uint, msg *NotificationMessage) {
+	// 后台协程使用脱离请求生命周期的上下文，保留请求ID便于关联日志
+	ctx = logger.Detach(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, constant.NotificationDispatchTimeout)
+		defer cancel()
 
-	return nil
+		s.notifications.Alert(ctx, userID, msg)
+	}()
 }
 
 // GetUserInfo 获取用户信息
@@ -312,7 +517,7 @@ func (s *userService) GetUserInfo(ctx context.Context, id uint) (*dto.UserInfoRe
 	logger.Info(ctx, "开始获取用户信息")
 
 	// 根据ID查找用户
-	user, err := s.userRepo.FindByID(id)
+	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			logger.Warn(ctx, "用户不存在")
@@ -324,13 +529,15 @@ func (s *userService) GetUserInfo(ctx context.Context, id uint) (*dto.UserInfoRe
 
 	// 构建响应
 	response := &dto.UserInfoResponse{
-		ID:        user.ID,
+		ID:        user.PublicID,
 		Username:  user.Username,
 		Mobile:    user.Mobile,
 		Nickname:  user.Nickname,
-		Avatar:    user.Avatar,
+		Avatar:    avatarURL(user),
 		Status:    user.Status,
+		IsPrivate: user.IsPrivate,
 		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: user.UpdatedAt,
 	}
 
 	logger.Info(ctx, "获取用户信息成功", logger.String("username", user.Username))
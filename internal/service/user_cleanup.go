@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/repository"
+	"app/pkg/cos"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// UserCleanupResult 用户清理任务执行结果
+type UserCleanupResult struct {
+	ActiveSynced        int   // 同步到数据库的最近活跃时间数量
+	UsersScanned        int   // 检查的未活跃用户数量
+	UsersMarkedDormant  int64 // 标记为休眠的用户数量
+	SessionsArchived    int   // 失效会话的用户数量
+	TempImagesExpired   int   // 删除的临时图片数量
+	DeviceTokensRemoved int64 // 删除的设备推送令牌数量
+}
+
+// UserCleanupService 用户清理服务接口
+type UserCleanupService interface {
+	// CleanupInactiveUsers 清理长时间未活跃的用户，由定时任务调用
+	CleanupInactiveUsers(ctx context.Context) (*UserCleanupResult, error)
+}
+
+// userCleanupService 用户清理服务实现
+type userCleanupService struct {
+	userRepo      repository.UserRepository
+	tempImageRepo repository.TempImageRepository
+	deviceRepo    repository.DeviceTokenRepository
+	cosClient     *cos.StorageClient
+	dormantAfter  time.Duration
+	tempImageTTL  time.Duration
+}
+
+// NewUserCleanupService 创建用户清理服务实例
+func NewUserCleanupService(
+	userRepo repository.UserRepository,
+	tempImageRepo repository.TempImageRepository,
+	deviceRepo repository.DeviceTokenRepository,
+) (UserCleanupService, error) {
+	// 获取COS客户端
+	cosClient, err := cos.GetStorageClient()
+	if err != nil {
+		return nil, fmt.Errorf("获取COS客户端失败: %w", err)
+	}
+
+	// 解析清理阈值，未配置或配置错误时使用默认值
+	accountConfig := config.GetAccountConfig()
+	dormantAfter, err := time.ParseDuration(accountConfig.DormantAfter)
+	if err != nil || dormantAfter <= 0 {
+		dormantAfter = constant.UserDefaultDormantAfter
+	}
+	tempImageTTL, err := time.ParseDuration(accountConfig.TempImageTTL)
+	if err != nil || tempImageTTL <= 0 {
+		tempImageTTL = constant.UserDefaultTempImageTTL
+	}
+
+	return &userCleanupService{
+		userRepo:      userRepo,
+		tempImageRepo: tempImageRepo,
+		deviceRepo:    deviceRepo,
+		cosClient:     cosClient,
+		dormantAfter:  dormantAfter,
+		tempImageTTL:  tempImageTTL,
+	}, nil
+}
+
+// CleanupInactiveUsers 清理长时间未活跃的用户，由定时任务调用
+// 先同步Redis中的最近活跃时间，再分批将超过阈值的用户标记为休眠，
+// 失效其已签发的令牌和设备推送令牌，并删除其过期的临时图片
+func (s *userCleanupService) CleanupInactiveUsers(ctx context.Context) (*UserCleanupResult, error) {
+	result := &UserCleanupResult{}
+
+	synced, err := s.syncLastActive(ctx)
+	result.ActiveSynced = synced
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	before := now.Add(-s.dormantAfter)
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		users, err := s.userRepo.FindInactiveUsers(ctx, before, constant.UserCleanupBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("查询未活跃用户失败: %w", err)
+		}
+		if len(users) == 0 {
+			break
+		}
+		result.UsersScanned += len(users)
+
+		ids := make([]uint, 0, len(users))
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+
+		marked, err := s.userRepo.MarkDormant(ctx, ids, before)
+		if err != nil {
+			return result, fmt.Errorf("标记休眠用户失败: %w", err)
+		}
+		result.UsersMarkedDormant += marked
+
+		for _, id := range ids {
+			if err := s.archiveSessions(id, now); err != nil {
+				logger.Warn(ctx, "失效用户会话失败", logger.Uint("user_id", id), logger.Err(err))
+			} else {
+				result.SessionsArchived++
+			}
+
+			// 休眠用户需要重新登录，重新登录后客户端会再次上报推送令牌
+			removed, err := s.deviceRepo.DeleteAllByUser(ctx, id)
+			if err != nil {
+				logger.Warn(ctx, "删除用户设备推送令牌失败", logger.Uint("user_id", id), logger.Err(err))
+			}
+			result.DeviceTokensRemoved += removed
+
+			expired, err := s.expireTempImages(ctx, id, now.Add(-s.tempImageTTL))
+			result.TempImagesExpired += expired
+			if err != nil {
+				logger.Warn(ctx, "删除用户临时图片失败", logger.Uint("user_id", id), logger.Err(err))
+			}
+		}
+
+		// 不足一批说明已处理完毕
+		if len(users) < constant.UserCleanupBatchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// syncLastActive 将Redis中记录的最近活跃时间分批同步到数据库
+func (s *userCleanupService) syncLastActive(ctx context.Context) (int, error) {
+	synced := 0
+	var cursor uint64
+	for {
+		values, next, err := redis.HScan(cachekey.UserLastActive().String(), cursor, "", constant.UserLastActiveSyncBatchSize)
+		if err != nil {
+			return synced, fmt.Errorf("读取最近活跃时间失败: %w", err)
+		}
+
+		// HScan返回字段和值交替排列的列表
+		for i := 0; i+1 < len(values); i += 2 {
+			userID, err := strconv.ParseUint(values[i], 10, 64)
+			if err != nil {
+				continue
+			}
+			unix, err := strconv.ParseInt(values[i+1], 10, 64)
+			if err != nil {
+				continue
+			}
+			if err := s.userRepo.UpdateLastActiveAt(ctx, uint(userID), time.Unix(unix, 0)); err != nil {
+				logger.Warn(ctx, "同步用户最近活跃时间失败", logger.Uint64("user_id", userID), logger.Err(err))
+				continue
+			}
+			synced++
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return synced, nil
+}
+
+// archiveSessions 失效用户在指定时间之前签发的所有令牌
+func (s *userCleanupService) archiveSessions(userID uint, revokedAt time.Time) error {
+	key := cachekey.TokenRevokedBefore(userID)
+	return redis.Set(key.String(), revokedAt.Unix(), key.TTL())
+}
+
+// expireTempImages 删除用户在指定时间之前上传的临时图片，返回删除的数量
+func (s *userCleanupService) expireTempImages(ctx context.Context, userID uint, before time.Time) (int, error) {
+	images, err := s.tempImageRepo.GetUserTempImagesBefore(ctx, userID, before)
+	if err != nil {
+		return 0, fmt.Errorf("查询临时图片失败: %w", err)
+	}
+
+	deleted := 0
+	for _, img := range images {
+		if err := s.cosClient.DeleteFile(ctx, img.Bucket, img.ObjectKey); err != nil {
+			return deleted, fmt.Errorf("删除临时图片文件失败: %w", err)
+		}
+		if err := s.tempImageRepo.DeleteTempImage(ctx, img.ID); err != nil {
+			return deleted, fmt.Errorf("删除临时图片记录失败: %w", err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
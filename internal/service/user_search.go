@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"app/internal/dto"
+)
+
+// mobilePattern 中国大陆手机号格式，关键词符合该格式时同时按手机号精确匹配
+var mobilePattern = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// SearchUsers 按昵称前缀或手机号搜索用户，不返回手机号等隐私信息
+func (s *userService) SearchUsers(ctx context.Context, req *dto.SearchUsersRequest, userID uint) (*dto.SearchUsersResponse, error) {
+	if err := checkSearchRate(userID); err != nil {
+		return nil, err
+	}
+
+	keyword := strings.TrimSpace(req.Keyword)
+	var mobile string
+	if mobilePattern.MatchString(keyword) {
+		mobile = keyword
+	}
+
+	users, count, err := s.userRepo.SearchUsers(ctx, keyword, mobile, userID, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("搜索用户失败: %w", err)
+	}
+
+	list := make([]dto.UserBrief, 0, len(users))
+	for i := range users {
+		list = append(list, dto.UserBrief{
+			ID:       users[i].PublicID,
+			Nickname: users[i].Nickname,
+			Avatar:   avatarURL(&users[i]),
+		})
+	}
+
+	return &dto.SearchUsersResponse{
+		Total:   int(count),
+		HasMore: int64(req.Page*req.Size) < count,
+		List:    list,
+	}, nil
+}
+
+// SetMobileSearch 设置是否允许他人通过手机号搜索到自己
+func (s *userService) SetMobileSearch(ctx context.Context, req *dto.SetMobileSearchRequest, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if user.AllowMobileSearch == *req.Allow {
+		return nil
+	}
+
+	user.AllowMobileSearch = *req.Allow
+	return s.userRepo.Update(ctx, user)
+}
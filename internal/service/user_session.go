@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"app/internal/cachekey"
+	"app/internal/repository"
+	"app/pkg/logger"
+)
+
+// LogoutAll 退出所有设备
+// 递增数据库中的令牌版本号并同步到缓存，认证中间件据此拒绝此前签发的令牌，包括当前请求使用的令牌
+func (s *userService) LogoutAll(ctx context.Context, userID uint) error {
+	version, err := s.userRepo.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("更新令牌版本失败: %w", err)
+	}
+
+	// 缓存未更新时旧令牌在缓存过期前仍然有效，返回错误由客户端重试
+	key := cachekey.TokenVersion(userID)
+	if err := s.store.Set(key.String(), version, key.TTL()); err != nil {
+		return fmt.Errorf("缓存令牌版本失败: %w", err)
+	}
+
+	logger.Info(ctx, "用户退出所有设备", logger.Uint("user_id", userID), logger.Uint("token_version", version))
+	return nil
+}
+
+// GetTokenVersion 获取用户当前的令牌版本号，优先读取缓存，缓存缺失时从数据库读取并写入缓存
+// 写入缓存时不覆盖已有的值，避免与同时进行的LogoutAll交错时用旧版本号覆盖新版本号
+func (s *userService) GetTokenVersion(ctx context.Context, userID uint) (uint, error) {
+	key := cachekey.TokenVersion(userID)
+	if value, err := s.store.Get(key.String()); err == nil {
+		if version, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return uint(version), nil
+		}
+	}
+
+	version, err := s.userRepo.GetTokenVersion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, ErrUserNotFound
+		}
+		return 0, fmt.Errorf("查询令牌版本失败: %w", err)
+	}
+
+	if _, err := s.store.SetNX(key.String(), version, key.TTL()); err != nil {
+		logger.Warn(ctx, "缓存令牌版本失败", logger.Uint("user_id", userID), logger.Err(err))
+	}
+	return version, nil
+}
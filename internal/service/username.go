@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/internal/cachekey"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/redis"
+)
+
+// 用户名相关错误
+var (
+	// ErrUsernameTaken 用户名已被使用，包括其他用户保护期内的旧用户名
+	ErrUsernameTaken = errors.New(constant.ErrUsernameTaken)
+	// ErrUsernameReserved 保留用户名
+	ErrUsernameReserved = errors.New(constant.ErrUsernameReserved)
+	// ErrUsernameChangeTooSoon 距上次修改用户名不足30天
+	ErrUsernameChangeTooSoon = errors.New(constant.ErrUsernameChangeTooSoon)
+)
+
+// UsernameService 用户名服务接口
+type UsernameService interface {
+	// ChangeUsername 修改用户名，30天内只能修改一次
+	ChangeUsername(ctx context.Context, req *dto.ChangeUsernameRequest, userID uint) (*dto.ChangeUsernameResponse, error)
+	// ResolveUsername 按用户名查找用户，保护期内的旧用户名返回用户当前的用户名
+	ResolveUsername(ctx context.Context, username string) (*dto.ResolveUsernameResponse, error)
+}
+
+// usernameService 用户名服务实现
+type usernameService struct {
+	userRepo    repository.UserRepository
+	historyRepo repository.UsernameHistoryRepository
+	reserved    map[string]bool // 小写的保留用户名
+}
+
+// NewUsernameService 创建用户名服务实例
+func NewUsernameService(userRepo repository.UserRepository, historyRepo repository.UsernameHistoryRepository) UsernameService {
+	reserved := make(map[string]bool)
+	for _, word := range constant.UsernameReservedWords {
+		reserved[word] = true
+	}
+	for _, word := range config.GetAccountConfig().ReservedUsernames {
+		reserved[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+
+	return &usernameService{
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+		reserved:    reserved,
+	}
+}
+
+// ChangeUsername 修改用户名
+// 新用户名不能是保留用户名，不能与其他用户的用户名或保护期内的旧用户名重复，比较时不区分大小写
+// 修改前的用户名在保护期内为本人保留，默认的手机号用户名不记录，避免通过旧用户名查到手机号对应的用户
+func (s *usernameService) ChangeUsername(ctx context.Context, req *dto.ChangeUsernameRequest, userID uint) (*dto.ChangeUsernameResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	now := time.Now()
+	if user.Username == req.Username {
+		return &dto.ChangeUsernameResponse{Username: user.Username, NextChangeAt: nextUsernameChangeAt(user, now)}, nil
+	}
+	if user.UsernameChangedAt != nil && now.Before(user.UsernameChangedAt.Add(constant.UsernameChangeInterval)) {
+		return nil, ErrUsernameChangeTooSoon
+	}
+
+	name := strings.ToLower(req.Username)
+	if s.reserved[name] {
+		return nil, ErrUsernameReserved
+	}
+
+	// 占用新用户名，避免并发修改为同一用户名
+	key := cachekey.UsernameClaim(name)
+	ok, err := redis.SetNX(key.String(), userID, key.TTL())
+	if err != nil {
+		return nil, fmt.Errorf("占用用户名失败: %w", err)
+	}
+	if !ok {
+		return nil, ErrUsernameTaken
+	}
+	defer func() { _, _ = redis.Del(key.String()) }()
+
+	exists, err := s.userRepo.UsernameExists(ctx, req.Username, userID)
+	if err != nil {
+		return nil, fmt.Errorf("检查用户名失败: %w", err)
+	}
+	if exists {
+		return nil, ErrUsernameTaken
+	}
+	protected, err := s.historyRepo.FindProtected(ctx, req.Username, now)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("检查用户名失败: %w", err)
+	}
+	if protected != nil && protected.UserID != userID {
+		return nil, ErrUsernameTaken
+	}
+
+	var history *model.UsernameHistory
+	if user.Username != "" && user.Username != user.Mobile {
+		history = &model.UsernameHistory{
+			UserID:         userID,
+			Username:       user.Username,
+			ProtectedUntil: now.Add(constant.UsernameReuseProtection),
+		}
+	}
+	changed, err := s.userRepo.ChangeUsername(ctx, userID, req.Username, now.Add(-constant.UsernameChangeInterval), history)
+	if err != nil {
+		return nil, fmt.Errorf("修改用户名失败: %w", err)
+	}
+	if !changed {
+		return nil, ErrUsernameChangeTooSoon
+	}
+
+	logger.Info(ctx, "用户名修改成功", logger.Uint("user_id", userID), logger.String("username", req.Username))
+
+	return &dto.ChangeUsernameResponse{
+		Username:     req.Username,
+		NextChangeAt: now.Add(constant.UsernameChangeInterval),
+	}, nil
+}
+
+// ResolveUsername 按用户名查找用户
+// 用户名未被使用但属于某用户保护期内的旧用户名时，返回该用户及其当前的用户名
+func (s *usernameService) ResolveUsername(ctx context.Context, username string) (*dto.ResolveUsernameResponse, error) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err == nil {
+		return &dto.ResolveUsernameResponse{UserID: user.PublicID, Username: user.Username}, nil
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	history, err := s.historyRepo.FindProtected(ctx, username, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("查询用户名变更记录失败: %w", err)
+	}
+
+	user, err = s.userRepo.FindByID(ctx, history.UserID)
+	if err != nil || user.Status != constant.UserStatusNormal {
+		return nil, ErrUserNotFound
+	}
+
+	return &dto.ResolveUsernameResponse{UserID: user.PublicID, Username: user.Username, Redirected: true}, nil
+}
+
+// nextUsernameChangeAt 返回用户下次可以修改用户名的时间，可以立即修改时返回当前时间
+func nextUsernameChangeAt(user *model.User, now time.Time) time.Time {
+	if user.UsernameChangedAt == nil {
+		return now
+	}
+	next := user.UsernameChangedAt.Add(constant.UsernameChangeInterval)
+	if next.Before(now) {
+		return now
+	}
+	return next
+}
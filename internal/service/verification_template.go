@@ -0,0 +1,115 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/dto"
+)
+
+// ErrUnsupportedVerificationType 验证码类型未配置短信模板错误
+var ErrUnsupportedVerificationType = errors.New(constant.ErrUnsupportedVerificationType)
+
+// requiredVerificationTypes 必须配置短信模板的验证码类型，缺少时服务无法启动
+var requiredVerificationTypes = []dto.VerificationType{
+	dto.VerificationTypeLogin,
+	dto.VerificationTypeDeactivate,
+}
+
+// supportedVerificationTypes 允许配置短信模板的验证码类型
+var supportedVerificationTypes = map[dto.VerificationType]bool{
+	dto.VerificationTypeLogin:        true,
+	dto.VerificationTypeDeactivate:   true,
+	dto.VerificationTypeChangeMobile: true,
+}
+
+// verificationPlaceholder 模板参数和短信内容中的占位符
+var verificationPlaceholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// 验证码短信模板支持的占位符
+const (
+	placeholderCode    = "{code}"
+	placeholderMinutes = "{minutes}"
+)
+
+// ValidateVerificationTemplates 校验验证码短信模板配置，服务启动时调用
+// 必须配置的类型缺少模板代码、出现未知的验证码类型或占位符、模板参数中不包含验证码时返回错误
+func ValidateVerificationTemplates() error {
+	templates := config.GetSMSConfig().Templates
+	for _, codeType := range requiredVerificationTypes {
+		if templates[string(codeType)].Code == "" {
+			return fmt.Errorf("验证码类型%s未配置短信模板代码", codeType)
+		}
+	}
+
+	for name, tpl := range templates {
+		if !supportedVerificationTypes[dto.VerificationType(name)] {
+			return fmt.Errorf("未知的验证码类型: %s", name)
+		}
+		// 未配置模板代码的可选类型不发送短信，无需校验
+		if tpl.Code == "" {
+			continue
+		}
+
+		hasCode := false
+		for param, value := range tpl.Params {
+			if err := checkPlaceholders(value); err != nil {
+				return fmt.Errorf("验证码类型%s的模板参数%s: %w", name, param, err)
+			}
+			if strings.Contains(value, placeholderCode) {
+				hasCode = true
+			}
+		}
+		if !hasCode {
+			return fmt.Errorf("验证码类型%s的模板参数中没有%s占位符", name, placeholderCode)
+		}
+		if err := checkPlaceholders(tpl.Content); err != nil {
+			return fmt.Errorf("验证码类型%s的短信内容: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkPlaceholders 检查文本中是否只包含支持的占位符
+func checkPlaceholders(text string) error {
+	for _, p := range verificationPlaceholder.FindAllString(text, -1) {
+		if p != placeholderCode && p != placeholderMinutes {
+			return fmt.Errorf("不支持的占位符%s", p)
+		}
+	}
+	return nil
+}
+
+// verificationTemplate 获取验证码类型对应的短信模板，未配置模板代码时返回ErrUnsupportedVerificationType
+func verificationTemplate(codeType dto.VerificationType) (config.SMSTemplateConfig, error) {
+	tpl, ok := config.GetSMSConfig().Templates[string(codeType)]
+	if !ok || tpl.Code == "" || !supportedVerificationTypes[codeType] {
+		return config.SMSTemplateConfig{}, ErrUnsupportedVerificationType
+	}
+	return tpl, nil
+}
+
+// renderVerificationTemplate 替换模板中的占位符
+// 返回: 短信内容和JSON格式的模板参数
+func renderVerificationTemplate(tpl config.SMSTemplateConfig, code string) (string, string, error) {
+	replacer := strings.NewReplacer(
+		placeholderCode, code,
+		placeholderMinutes, strconv.Itoa(int(constant.VerificationCodeExpiration.Minutes())),
+	)
+
+	params := make(map[string]string, len(tpl.Params))
+	for name, value := range tpl.Params {
+		params[name] = replacer.Replace(value)
+	}
+	paramJSON, err := json.Marshal(params)
+	if err != nil {
+		return "", "", err
+	}
+	return replacer.Replace(tpl.Content), string(paramJSON), nil
+}
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/internal/constant"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/internal/utils"
+	"app/pkg/logger"
+)
+
+// Webhook相关错误
+var (
+	// ErrWebhookNotFound 订阅不存在
+	ErrWebhookNotFound = errors.New("Webhook订阅不存在")
+	// ErrWebhookInvalidURL 接收地址格式错误
+	ErrWebhookInvalidURL = errors.New("接收地址必须是http或https地址")
+	// ErrWebhookInvalidEvent 事件类型不支持
+	ErrWebhookInvalidEvent = errors.New("不支持的事件类型")
+	// ErrWebhookClientNotFound 调用方不存在或已禁用
+	ErrWebhookClientNotFound = errors.New("调用方不存在或已禁用")
+)
+
+// webhookSecretCharset 生成签名密钥使用的字符集
+const webhookSecretCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// webhookEventIDCharset 生成事件ID使用的字符集
+const webhookEventIDCharset = "0123456789abcdef"
+
+// EventPublisher 平台事件发布接口
+// 业务操作成功后调用，发布失败只记录日志，不影响业务结果
+type EventPublisher interface {
+	// Publish 发布事件，data为事件数据
+	Publish(ctx context.Context, event string, data interface{})
+}
+
+// WebhookService Webhook服务接口
+type WebhookService interface {
+	EventPublisher
+	// ListSubscriptions 获取所有订阅
+	ListSubscriptions(ctx context.Context) (*dto.ListWebhooksResponse, error)
+	// CreateSubscription 创建订阅，签名密钥仅在响应中返回一次
+	CreateSubscription(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.SaveWebhookResponse, error)
+	// UpdateSubscription 更新订阅，重新生成签名密钥时在响应中返回新密钥
+	UpdateSubscription(ctx context.Context, id uint, req *dto.UpdateWebhookRequest) (*dto.SaveWebhookResponse, error)
+	// DeleteSubscription 删除订阅，未完成的投递将不再重试
+	DeleteSubscription(ctx context.Context, id uint) error
+	// GetDeliveries 分页获取投递记录
+	GetDeliveries(ctx context.Context, req *dto.GetWebhookDeliveriesRequest) (*dto.GetWebhookDeliveriesResponse, error)
+	// DeliverPending 投递所有到期的待投递记录，返回处理的记录数
+	DeliverPending(ctx context.Context) (int, error)
+	// PurgeDeliveries 删除超过保留期的已结束投递记录，返回删除的记录数
+	PurgeDeliveries(ctx context.Context) (int64, error)
+}
+
+// webhookService Webhook服务实现
+type webhookService struct {
+	webhookRepo   repository.WebhookRepository
+	clientRepo    repository.APIClientRepository
+	client        *http.Client
+	timeout       time.Duration
+	maxAttempts   int
+	retryInterval time.Duration
+	logRetention  time.Duration
+}
+
+// NewWebhookService 创建Webhook服务实例
+func NewWebhookService(webhookRepo repository.WebhookRepository, clientRepo repository.APIClientRepository) WebhookService {
+	cfg := config.GetWebhookConfig()
+
+	// 解析投递配置，未配置或配置错误时使用默认值
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = constant.WebhookDefaultTimeout
+	}
+	retryInterval, err := time.ParseDuration(cfg.RetryInterval)
+	if err != nil || retryInterval <= 0 {
+		retryInterval = constant.WebhookDefaultRetryInterval
+	}
+	logRetention, err := time.ParseDuration(cfg.LogRetention)
+	if err != nil || logRetention <= 0 {
+		logRetention = constant.WebhookDefaultLogRetention
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = constant.WebhookDefaultMaxAttempts
+	}
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		clientRepo:  clientRepo,
+		client: &http.Client{
+			Timeout: timeout,
+			// 不跟随重定向，3xx响应视为投递失败
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		timeout:       timeout,
+		maxAttempts:   maxAttempts,
+		retryInterval: retryInterval,
+		logRetention:  logRetention,
+	}
+}
+
+// Publish 为订阅了该事件的每个订阅创建投递记录，并在后台立即投递
+// 立即投递失败或服务中断的记录由定时任务按退避间隔重试；平台内部事件和非公开内容的事件不投递
+func (s *webhookService) Publish(ctx context.Context, event string, data interface{}) {
+	if !slices.Contains(constant.WebhookEvents, event) {
+		return
+	}
+	if public, ok := data.(dto.PublicEventData); ok && !public.IsPublic() {
+		return
+	}
+
+	subs, err := s.webhookRepo.GetEnabledSubscriptions(ctx)
+	if err != nil {
+		logger.Error(ctx, "查询Webhook订阅失败", logger.String("event", event), logger.Err(err))
+		return
+	}
+
+	var matched []model.WebhookSubscription
+	for _, sub := range subs {
+		if slices.Contains(strings.Split(sub.Events, ","), event) {
+			matched = append(matched, sub)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	now := time.Now()
+	eventID := utils.GenerateRandomString(32, webhookEventIDCharset)
+	payload, err := json.Marshal(dto.WebhookEvent{
+		ID:        eventID,
+		Event:     event,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		logger.Error(ctx, "序列化Webhook事件失败", logger.String("event", event), logger.Err(err))
+		return
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(matched))
+	for _, sub := range matched {
+		deliveries = append(deliveries, model.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			ClientID:       sub.ClientID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        string(payload),
+			Status:         constant.WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+		})
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Error(ctx, "创建Webhook投递记录失败", logger.String("event", event), logger.Err(err))
+		return
+	}
+
+	// 后台协程使用脱离请求生命周期的上下文，保留请求ID便于关联日志
+	ctx = logger.Detach(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(len(deliveries))*(s.timeout+5*time.Second))
+		defer cancel()
+
+		for i := range deliveries {
+			if _, err := s.deliver(ctx, &deliveries[i]); err != nil {
+				logger.Error(ctx, "投递Webhook事件失败", logger.Uint("delivery_id", deliveries[i].ID), logger.Err(err))
+			}
+		}
+	}()
+}
+
+// DeliverPending 投递所有到期的待投递记录
+func (s *webhookService) DeliverPending(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		deliveries, err := s.webhookRepo.GetDueDeliveries(ctx, time.Now(), constant.WebhookDeliveryBatchSize)
+		if err != nil {
+			return processed, fmt.Errorf("查询待投递记录失败: %w", err)
+		}
+
+		for i := range deliveries {
+			if ctx.Err() != nil {
+				return processed, ctx.Err()
+			}
+			delivered, err := s.deliver(ctx, &deliveries[i])
+			if err != nil {
+				return processed, err
+			}
+			if delivered {
+				processed++
+			}
+		}
+
+		if len(deliveries) < constant.WebhookDeliveryBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+// deliver 认领并投递一条记录，记录已被其他实例认领时返回false
+func (s *webhookService) deliver(ctx context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	now := time.Now()
+	claimed, err := s.webhookRepo.ClaimDelivery(ctx, delivery.ID, now, now.Add(constant.WebhookClaimLease))
+	if err != nil {
+		return false, fmt.Errorf("认领投递记录失败: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	// 订阅已删除或禁用时不再投递
+	sub, err := s.webhookRepo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return false, fmt.Errorf("查询Webhook订阅失败: %w", err)
+	}
+	if sub == nil || sub.Status != constant.WebhookStatusEnabled {
+		delivery.Status = constant.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.ErrorMessage = "订阅已删除或已禁用"
+		return true, s.webhookRepo.UpdateDelivery(ctx, delivery)
+	}
+
+	statusCode, sendErr := s.send(ctx, sub, delivery)
+	delivery.Attempts++
+	delivery.ResponseCode = statusCode
+
+	if sendErr == nil {
+		deliveredAt := time.Now()
+		delivery.Status = constant.WebhookDeliverySuccess
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &deliveredAt
+		delivery.ErrorMessage = ""
+	} else {
+		delivery.ErrorMessage = truncateRunes(sendErr.Error(), constant.WebhookErrorMaxLength-1)
+		if delivery.Attempts >= s.maxAttempts {
+			delivery.Status = constant.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			nextAttemptAt := time.Now().Add(s.backoff(delivery.Attempts))
+			delivery.Status = constant.WebhookDeliveryPending
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+	}
+
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return true, fmt.Errorf("更新投递记录失败: %w", err)
+	}
+	return true, nil
+}
+
+// send 发送签名后的事件请求，返回响应状态码，非2xx响应视为失败
+func (s *webhookService) send(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery) (int, error) {
+	secret, err := utils.DecryptAES(sub.Secret, []byte(config.GetAPISignConfig().SecretKey))
+	if err != nil {
+		return 0, fmt.Errorf("解密签名密钥失败: %w", err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constant.WebhookEventHeader, delivery.Event)
+	req.Header.Set(constant.WebhookDeliveryHeader, delivery.EventID)
+	req.Header.Set(constant.WebhookTimestampHeader, timestamp)
+	req.Header.Set(constant.WebhookSignatureHeader, signWebhookPayload(secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("响应状态码%d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff 计算第attempts次失败后的重试间隔，每次翻倍，不超过上限
+func (s *webhookService) backoff(attempts int) time.Duration {
+	interval := s.retryInterval
+	for i := 1; i < attempts; i++ {
+		interval *= 2
+		if interval >= constant.WebhookMaxRetryInterval {
+			return constant.WebhookMaxRetryInterval
+		}
+	}
+	return interval
+}
+
+// signWebhookPayload 以签名密钥对"时间戳.请求体"计算HMAC-SHA256，返回小写十六进制
+func signWebhookPayload(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PurgeDeliveries 删除超过保留期的已结束投递记录
+func (s *webhookService) PurgeDeliveries(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-s.logRetention)
+	var total int64
+	for {
+		deleted, err := s.webhookRepo.DeleteFinishedDeliveries(ctx, before, constant.WebhookDeliveryBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("删除投递记录失败: %w", err)
+		}
+		total += deleted
+		if deleted < constant.WebhookDeliveryBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// ListSubscriptions 获取所有订阅
+func (s *webhookService) ListSubscriptions(ctx context.Context) (*dto.ListWebhooksResponse, error) {
+	subs, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询Webhook订阅失败: %w", err)
+	}
+
+	list := make([]dto.WebhookInfo, 0, len(subs))
+	for i := range subs {
+		list = append(list, toWebhookInfo(&subs[i]))
+	}
+
+	return &dto.ListWebhooksResponse{
+		List:   list,
+		Events: constant.WebhookEvents,
+	}, nil
+}
+
+// CreateSubscription 创建订阅
+func (s *webhookService) CreateSubscription(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.SaveWebhookResponse, error) {
+	client, err := s.clientRepo.FindByID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrWebhookClientNotFound
+		}
+		return nil, fmt.Errorf("查询调用方失败: %w", err)
+	}
+	if client.Status != constant.APIClientStatusEnabled {
+		return nil, ErrWebhookClientNotFound
+	}
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, encrypted, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &model.WebhookSubscription{
+		ClientID: client.ID,
+		URL:      req.URL,
+		Events:   events,
+		Secret:   encrypted,
+		Status:   constant.WebhookStatusEnabled,
+	}
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("创建Webhook订阅失败: %w", err)
+	}
+
+	return &dto.SaveWebhookResponse{
+		WebhookInfo: toWebhookInfo(sub),
+		Secret:      secret,
+	}, nil
+}
+
+// UpdateSubscription 更新订阅
+func (s *webhookService) UpdateSubscription(ctx context.Context, id uint, req *dto.UpdateWebhookRequest) (*dto.SaveWebhookResponse, error) {
+	sub, err := s.webhookRepo.GetSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("查询Webhook订阅失败: %w", err)
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *req.URL
+	}
+	if len(req.Events) > 0 {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		sub.Events = events
+	}
+	if req.Status != nil {
+		sub.Status = *req.Status
+	}
+
+	var secret string
+	if req.ResetSecret {
+		var encrypted string
+		secret, encrypted, err = generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = encrypted
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("更新Webhook订阅失败: %w", err)
+	}
+
+	return &dto.SaveWebhookResponse{
+		WebhookInfo: toWebhookInfo(sub),
+		Secret:      secret,
+	}, nil
+}
+
+// DeleteSubscription 删除订阅
+func (s *webhookService) DeleteSubscription(ctx context.Context, id uint) error {
+	err := s.webhookRepo.DeleteSubscription(ctx, id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// GetDeliveries 分页获取投递记录
+func (s *webhookService) GetDeliveries(ctx context.Context, req *dto.GetWebhookDeliveriesRequest) (*dto.GetWebhookDeliveriesResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 20
+	}
+
+	filter := repository.WebhookDeliveryFilter{
+		SubscriptionID: req.SubscriptionID,
+		ClientID:       req.ClientID,
+		Status:         req.Status,
+	}
+	deliveries, total, err := s.webhookRepo.GetDeliveries(ctx, filter, req.Page, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("查询投递记录失败: %w", err)
+	}
+
+	list := make([]dto.WebhookDeliveryInfo, 0, len(deliveries))
+	for _, d := range deliveries {
+		list = append(list, dto.WebhookDeliveryInfo{
+			ID:             d.ID,
+			SubscriptionID: d.SubscriptionID,
+			EventID:        d.EventID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseCode:   d.ResponseCode,
+			ErrorMessage:   d.ErrorMessage,
+			NextAttemptAt:  d.NextAttemptAt,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		})
+	}
+
+	return &dto.GetWebhookDeliveriesResponse{
+		Total: int(total),
+		List:  list,
+	}, nil
+}
+
+// validateWebhookURL 校验接收地址是否为http或https绝对地址
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrWebhookInvalidURL
+	}
+	return nil
+}
+
+// normalizeWebhookEvents 校验事件类型并去重，返回逗号分隔的事件列表
+func normalizeWebhookEvents(events []string) (string, error) {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(constant.WebhookEvents, event) {
+			return "", ErrWebhookInvalidEvent
+		}
+		if !slices.Contains(result, event) {
+			result = append(result, event)
+		}
+	}
+	return strings.Join(result, ","), nil
+}
+
+// generateWebhookSecret 生成签名密钥，返回明文和加密后的密钥
+func generateWebhookSecret() (string, string, error) {
+	secretKey := config.GetAPISignConfig().SecretKey
+	if secretKey == "" {
+		return "", "", errors.New("未配置签名密钥的加密密钥")
+	}
+
+	secret := utils.GenerateRandomString(constant.WebhookSecretLength, webhookSecretCharset)
+	encrypted, err := utils.EncryptAES([]byte(secret), []byte(secretKey))
+	if err != nil {
+		return "", "", fmt.Errorf("加密签名密钥失败: %w", err)
+	}
+	return secret, encrypted, nil
+}
+
+// toWebhookInfo 转换订阅信息
+func toWebhookInfo(sub *model.WebhookSubscription) dto.WebhookInfo {
+	events := []string{}
+	if sub.Events != "" {
+		events = strings.Split(sub.Events, ",")
+	}
+	return dto.WebhookInfo{
+		ID:        sub.ID,
+		ClientID:  sub.ClientID,
+		URL:       sub.URL,
+		Events:    events,
+		Status:    sub.Status,
+		CreatedAt: sub.CreatedAt,
+		UpdatedAt: sub.UpdatedAt,
+	}
+}
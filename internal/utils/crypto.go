@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"io"
 )
@@ -103,3 +104,10 @@ func HashPassword(password string) string {
 	hash := sha256.Sum256([]byte(password))
 	return base64.StdEncoding.EncodeToString(hash[:])
 }
+
+// HashMobile 计算手机号的SHA-256摘要（小写十六进制），用于通讯录匹配
+// 客户端使用相同算法对通讯录号码计算摘要后上传，服务器不接收明文号码
+func HashMobile(mobile string) string {
+	sum := sha256.Sum256([]byte(mobile))
+	return hex.EncodeToString(sum[:])
+}
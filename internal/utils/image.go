@@ -3,8 +3,13 @@ package utils
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"image"
+	_ "image/gif"  // 注册GIF解码器
+	_ "image/jpeg" // 注册JPEG解码器
+	_ "image/png"  // 注册PNG解码器
 	"io"
 	"strings"
 	"time"
@@ -101,3 +106,45 @@ func ParseBase64Image(base64Data string) (io.Reader, string, int64, error) {
 
 	return reader, filename, size, nil
 }
+
+// ImageDimensions 读取图片的宽高，只解析文件头，不解码像素数据
+// 支持JPEG、PNG、GIF和WebP格式
+func ImageDimensions(data []byte) (int, int, error) {
+	if width, height, ok := webpDimensions(data); ok {
+		return width, height, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, ErrUnsupportedImageFormat
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// webpDimensions 解析WebP文件头中的宽高，标准库不支持WebP解码
+func webpDimensions(data []byte) (int, int, bool) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, false
+	}
+
+	switch string(data[12:16]) {
+	case "VP8 ": // 有损格式，关键帧起始码后为14位宽高
+		if data[23] != 0x9d || data[24] != 0x01 || data[25] != 0x2a {
+			return 0, 0, false
+		}
+		width := int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+		return width, height, true
+	case "VP8L": // 无损格式，签名后依次为14位的宽减一和高减一
+		if data[20] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, true
+	case "VP8X": // 扩展格式，画布宽高为24位的值减一
+		width := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		height := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return width + 1, height + 1, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,256 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// jpegQuality 按方向信息旋转后重新编码JPEG的质量
+const jpegQuality = 90
+
+// 不影响显示、可能包含拍摄位置和设备信息的元数据
+var (
+	// jpegStrippedMarkers JPEG中去除的段：APP1（EXIF、XMP）、APP13（IPTC）和注释
+	jpegStrippedMarkers = map[byte]bool{0xE1: true, 0xED: true, 0xFE: true}
+	// pngStrippedChunks PNG中去除的数据块
+	pngStrippedChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+	// webpStrippedChunks WebP中去除的数据块
+	webpStrippedChunks = map[string]bool{"EXIF": true, "XMP ": true}
+)
+
+// StripImageMetadata 去除图片中的EXIF等元数据，JPEG和PNG按EXIF方向信息旋转后重新编码，使去除元数据后仍能正确显示
+// WebP只去除元数据块不做旋转，GIF和其他格式原样返回
+// 参数: data - 图片内容, contentType - 图片的内容类型
+// 返回: 处理后的图片内容，文件结构无法解析时返回ErrUnsupportedImageFormat
+func StripImageMetadata(data []byte, contentType string) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEG 去除JPEG中的元数据段，方向信息需要旋转时解码后重新编码
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, ErrUnsupportedImageFormat
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	orientation := 1
+	pos := 2
+	for {
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, ErrUnsupportedImageFormat
+		}
+		marker := data[pos+1]
+		// 段之间的填充字节
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+		// 扫描数据开始后不再有需要去除的段，剩余内容原样保留
+		if marker == 0xDA {
+			out.Write(data[pos:])
+			break
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:pos+4]))
+		if end > len(data) {
+			return nil, ErrUnsupportedImageFormat
+		}
+		segment := data[pos:end]
+		if marker == 0xE1 && bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) {
+			orientation = exifOrientation(segment[10:])
+		}
+		if !jpegStrippedMarkers[marker] {
+			out.Write(segment)
+		}
+		pos = end
+	}
+
+	if orientation <= 1 {
+		return out.Bytes(), nil
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImageFormat
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, orient(img, orientation), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// stripPNG 去除PNG中的元数据块，eXIf块中的方向信息需要旋转时解码后重新编码
+func stripPNG(data []byte) ([]byte, error) {
+	const signatureLen = 8
+	if len(data) < signatureLen || string(data[1:4]) != "PNG" {
+		return nil, ErrUnsupportedImageFormat
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:signatureLen])
+	orientation := 1
+	pos := signatureLen
+	for pos < len(data) {
+		if pos+12 > len(data) {
+			return nil, ErrUnsupportedImageFormat
+		}
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrUnsupportedImageFormat
+		}
+		chunkType := string(data[pos+4 : pos+8])
+		if chunkType == "eXIf" {
+			orientation = exifOrientation(data[pos+8 : pos+8+length])
+		}
+		if !pngStrippedChunks[chunkType] {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+
+	if orientation <= 1 {
+		return out.Bytes(), nil
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImageFormat
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, orient(img, orientation)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// stripWebP 去除WebP中的EXIF和XMP数据块，并清除扩展格式头中对应的标志位
+func stripWebP(data []byte) ([]byte, error) {
+	const headerLen = 12
+	if len(data) < headerLen || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrUnsupportedImageFormat
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:headerLen])
+	pos := headerLen
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, ErrUnsupportedImageFormat
+		}
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		// 数据块按偶数字节对齐
+		end := pos + 8 + size + size%2
+		if size < 0 || end > len(data) {
+			return nil, ErrUnsupportedImageFormat
+		}
+		chunkType := string(data[pos : pos+4])
+		switch {
+		case webpStrippedChunks[chunkType]:
+		case chunkType == "VP8X" && size > 0:
+			chunk := append([]byte(nil), data[pos:end]...)
+			chunk[8] &^= 0x08 | 0x04 // EXIF和XMP标志位
+			out.Write(chunk)
+		default:
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+
+	result := out.Bytes()
+	binary.LittleEndian.PutUint32(result[4:8], uint32(len(result)-8))
+	return result, nil
+}
+
+// exifOrientation 读取EXIF（TIFF结构）第一个IFD中的方向信息，不存在或无法解析时返回1
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		// 方向信息的标签为0x0112，类型为SHORT，值直接存放在条目中
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value < 1 || value > 8 {
+				return 1
+			}
+			return value
+		}
+	}
+	return 1
+}
+
+// orient 按EXIF方向值变换图片，使其按正常方向显示
+// 2-水平翻转, 3-旋转180度, 4-垂直翻转, 5-转置, 6-顺时针旋转90度, 7-反转置, 8-逆时针旋转90度
+func orient(src image.Image, orientation int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// 先转换为RGBA以便直接读写像素
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			si := rgba.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], rgba.Pix[si:si+4])
+		}
+	}
+	return dst
+}
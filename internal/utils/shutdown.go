@@ -3,25 +3,31 @@ package utils
 import (
 	"app/pkg/database"
 	"app/pkg/logger"
+	"app/pkg/poolmonitor"
 	"app/pkg/redis"
+	"context"
 	"fmt"
+	"os"
 )
 
 // CloseResources 按照依赖关系的相反顺序关闭所有资源
 // 确保资源释放的正确顺序，避免依赖问题
 func CloseResources() {
+	// 停止连接池监控
+	poolmonitor.Stop()
+
 	// 关闭数据库连接
 	if err := database.Close(); err != nil {
-		fmt.Printf("关闭数据库连接失败: %v\n", err)
+		logger.Error(context.Background(), "关闭数据库连接失败", logger.Err(err))
 	}
 
 	// 关闭Redis连接
 	if err := redis.Close(); err != nil {
-		fmt.Printf("关闭Redis连接失败: %v\n", err)
+		logger.Error(context.Background(), "关闭Redis连接失败", logger.Err(err))
 	}
 
-	// 关闭日志系统
+	// 关闭日志系统，同步日志失败时无法再写入日志，错误直接写入标准错误
 	if err := logger.Close(); err != nil {
-		fmt.Printf("关闭日志系统失败: %v\n", err)
+		fmt.Fprintf(os.Stderr, "关闭日志系统失败: %v\n", err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,136 @@
+// Package cdn 提供对象存储文件的CDN访问地址，支持私有桶的签名鉴权和基于内容摘要的缓存刷新
+package cdn
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"app/config"
+)
+
+const (
+	// 默认鉴权参数名
+	defaultSignParam = "sign"
+	// 默认签名有效期
+	defaultExpire = time.Hour
+	// 缓存刷新版本参数名
+	versionParam = "v"
+	// 版本参数取内容摘要的前缀长度
+	versionLength = 12
+)
+
+// bucketRule 单个存储桶的CDN规则
+type bucketRule struct {
+	domain    string
+	private   bool
+	signKey   string
+	signParam string
+	expire    time.Duration
+	cacheBust bool
+}
+
+// Signer CDN地址生成器，根据文件源地址的域名匹配存储桶配置
+type Signer struct {
+	rules map[string]*bucketRule // key为源地址或CDN地址的域名
+	now   func() time.Time
+}
+
+var (
+	defaultSigner *Signer
+	once          sync.Once
+)
+
+// GetSigner 获取基于全局配置的CDN地址生成器
+func GetSigner() *Signer {
+	once.Do(func() {
+		defaultSigner = NewSigner(config.GetCDNConfig(), config.GetCOSConfig().Tencent)
+	})
+	return defaultSigner
+}
+
+// NewSigner 创建CDN地址生成器
+// 参数: cdnConfig - CDN配置, cosConfig - 对象存储配置，用于识别各存储桶的源地址域名
+func NewSigner(cdnConfig config.CDNConfig, cosConfig config.TencentCOSConfig) *Signer {
+	s := &Signer{
+		rules: make(map[string]*bucketRule),
+		now:   time.Now,
+	}
+
+	for bucket, cfg := range cdnConfig.Buckets {
+		rule := &bucketRule{
+			domain:    cfg.Domain,
+			private:   cfg.Private,
+			signKey:   cfg.SignKey,
+			signParam: cfg.SignParam,
+			expire:    defaultExpire,
+			cacheBust: cfg.CacheBust,
+		}
+		if rule.signParam == "" {
+			rule.signParam = defaultSignParam
+		}
+		if d, err := time.ParseDuration(cfg.Expire); err == nil && d > 0 {
+			rule.expire = d
+		}
+
+		// 源站域名、COS自定义域名和CDN域名都映射到同一规则
+		s.rules[fmt.Sprintf("%s.cos.%s.myqcloud.com", bucket, cosConfig.Region)] = rule
+		if customDomain := cosConfig.Buckets[bucket]; customDomain != "" {
+			s.rules[customDomain] = rule
+		}
+		if cfg.Domain != "" {
+			s.rules[cfg.Domain] = rule
+		}
+	}
+
+	return s
+}
+
+// URL 将文件源地址转换为CDN访问地址
+// 参数: rawURL - 存储的文件地址, version - 文件内容摘要，为空时不追加缓存刷新参数
+// 返回: CDN访问地址，地址无法解析或未配置对应存储桶时原样返回
+func (s *Signer) URL(rawURL, version string) string {
+	if rawURL == "" || len(s.rules) == 0 {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	rule, ok := s.rules[u.Host]
+	if !ok {
+		return rawURL
+	}
+
+	if rule.domain != "" {
+		u.Scheme = "https"
+		u.Host = rule.domain
+	}
+
+	query := u.Query()
+	if rule.cacheBust && version != "" {
+		if len(version) > versionLength {
+			version = version[:versionLength]
+		}
+		query.Set(versionParam, strings.ToLower(version))
+	}
+	if rule.private && rule.signKey != "" {
+		query.Set(rule.signParam, s.sign(rule, u.EscapedPath()))
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// sign 生成TypeA鉴权签名，格式为 timestamp-rand-uid-md5hash
+// 时间戳按有效期的一半取整，同一时间段内生成的地址相同，便于客户端和CDN缓存
+func (s *Signer) sign(rule *bucketRule, path string) string {
+	timestamp := s.now().Truncate(rule.expire / 2).Unix()
+	sum := md5.Sum([]byte(fmt.Sprintf("%s-%d-0-0-%s", path, timestamp, rule.signKey)))
+	return fmt.Sprintf("%d-0-0-%s", timestamp, hex.EncodeToString(sum[:]))
+}
@@ -2,47 +2,77 @@
 package cos
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
+
+	"app/config"
+	"app/pkg/resilience"
 )
 
 // StorageProvider 对象存储服务提供商接口，所有对象存储服务提供商都需要实现此接口
+// 各方法的ctx用于取消请求，其中的请求ID通过X-Request-ID请求头传给对象存储服务，便于关联日志
 type StorageProvider interface {
 	// UploadFile 上传文件
 	// 参数: bucket - 存储桶名称, objectKey - 对象键, reader - 文件内容读取器, contentType - 内容类型
 	// 返回: 访问URL和可能的错误
-	UploadFile(bucket, objectKey string, reader io.Reader, contentType string) (string, error)
+	UploadFile(ctx context.Context, bucket, objectKey string, reader io.Reader, contentType string) (string, error)
 
 	// DownloadFile 下载文件
 	// 参数: bucket - 存储桶名称, objectKey - 对象键, writer - 文件内容写入器
 	// 返回: 可能的错误
-	DownloadFile(bucket, objectKey string, writer io.Writer) error
+	DownloadFile(ctx context.Context, bucket, objectKey string, writer io.Writer) error
 
 	// DeleteFile 删除文件
 	// 参数: bucket - 存储桶名称, objectKey - 对象键
 	// 返回: 可能的错误
-	DeleteFile(bucket, objectKey string) error
+	DeleteFile(ctx context.Context, bucket, objectKey string) error
 
 	// GetFileURL 获取文件访问URL
 	// 参数: bucket - 存储桶名称, objectKey - 对象键, expires - URL过期时间
 	// 返回: 访问URL和可能的错误
-	GetFileURL(bucket, objectKey string, expires time.Duration) (string, error)
+	GetFileURL(ctx context.Context, bucket, objectKey string, expires time.Duration) (string, error)
 
 	// ListFiles 列出文件
 	// 参数: bucket - 存储桶名称, prefix - 前缀
 	// 返回: 文件列表和可能的错误
-	ListFiles(bucket, prefix string) ([]FileInfo, error)
+	ListFiles(ctx context.Context, bucket, prefix string) ([]FileInfo, error)
 
 	// CopyFile 复制文件
 	// 参数: srcBucket - 源存储桶名称, srcObjectKey - 源对象键, destBucket - 目标存储桶名称, destObjectKey - 目标对象键
 	// 返回: 可能的错误
-	CopyFile(srcBucket, srcObjectKey, destBucket, destObjectKey string) error
+	CopyFile(ctx context.Context, srcBucket, srcObjectKey, destBucket, destObjectKey string) error
 
 	// MoveFile 移动文件
 	// 参数: srcBucket - 源存储桶名称, srcObjectKey - 源对象键, destBucket - 目标存储桶名称, destObjectKey - 目标对象键
 	// 返回: 可能的错误
-	MoveFile(srcBucket, srcObjectKey, destBucket, destObjectKey string) error
+	MoveFile(ctx context.Context, srcBucket, srcObjectKey, destBucket, destObjectKey string) error
+
+	// GetPresignedPutURL 获取预签名上传URL，客户端可直接使用PUT方法上传文件
+	// 参数: bucket - 存储桶名称, objectKey - 对象键, contentType - 内容类型（为空时不限制）, expires - URL过期时间
+	// 返回: 预签名上传URL和可能的错误
+	GetPresignedPutURL(ctx context.Context, bucket, objectKey, contentType string, expires time.Duration) (string, error)
+
+	// StatFile 获取文件元信息
+	// 参数: bucket - 存储桶名称, objectKey - 对象键
+	// 返回: 文件信息和可能的错误，文件不存在时返回ErrObjectNotFound
+	StatFile(ctx context.Context, bucket, objectKey string) (*FileInfo, error)
+}
+
+// 对象存储相关错误
+var (
+	// ErrObjectNotFound 对象不存在
+	ErrObjectNotFound = errors.New("对象不存在")
+	// ErrUnavailable 对象存储服务连续失败后熔断，请求未发出
+	ErrUnavailable = errors.New("对象存储服务暂时不可用")
+)
+
+// errorClassifier 服务提供商可选实现的接口，用于区分服务故障和请求本身的错误
+// 未实现时除对象不存在外的所有错误都视为服务故障
+type errorClassifier interface {
+	IsServiceError(err error) bool
 }
 
 // FileInfo 文件信息结构体
@@ -52,6 +82,7 @@ type FileInfo struct {
 	LastModified time.Time // 最后修改时间
 	ETag         string    // 文件的 ETag
 	StorageClass string    // 存储类型
+	ContentType  string    // 内容类型，仅StatFile返回
 }
 
 // StorageRequest 通用存储请求参数结构体
@@ -63,52 +94,206 @@ type StorageRequest struct {
 }
 
 // StorageClient 对象存储客户端结构体，提供统一的对象存储接口
+// 所有请求通过熔断器发出并使用独立的超时，幂等操作失败后按配置重试，熔断期间请求直接返回ErrUnavailable
 type StorageClient struct {
-	provider StorageProvider // 对象存储服务提供商实现
+	provider StorageProvider      // 对象存储服务提供商实现
+	breaker  *resilience.Breaker  // 同一服务提供商的所有客户端共用的熔断器
+	policy   resilience.Policy    // 普通请求的超时和重试策略
+	transfer resilience.Policy    // 上传和下载请求的超时和重试策略
+	classify func(err error) bool // 判断错误是否为服务故障
 }
 
 // NewStorageClient 创建对象存储客户端实例
-// 参数: provider - 实现了StorageProvider接口的对象存储服务提供商
+// 参数: name - 服务提供商名称，用于区分熔断器, provider - 实现了StorageProvider接口的对象存储服务提供商
 // 返回: 对象存储客户端指针
-func NewStorageClient(provider StorageProvider) *StorageClient {
-	return &StorageClient{
+func NewStorageClient(name string, provider StorageProvider) *StorageClient {
+	cfg := config.GetCOSConfig().Resilience
+	policy := resilience.Policy{
+		Timeout:       config.ParseDuration(cfg.Timeout, 0),
+		MaxRetries:    cfg.MaxRetries,
+		RetryInterval: config.ParseDuration(cfg.RetryInterval, 0),
+	}
+	transfer := policy
+	transfer.Timeout = config.ParseDuration(cfg.TransferTimeout, 0)
+
+	client := &StorageClient{
 		provider: provider,
+		breaker: resilience.Register("cos_"+name, resilience.BreakerConfig{
+			FailureThreshold: cfg.FailureThreshold,
+			OpenTimeout:      config.ParseDuration(cfg.OpenTimeout, 0),
+		}),
+		policy:   policy,
+		transfer: transfer,
+		classify: func(err error) bool { return !errors.Is(err, ErrObjectNotFound) },
 	}
+	if c, ok := provider.(errorClassifier); ok {
+		client.classify = c.IsServiceError
+	}
+	return client
 }
 
-// UploadFile 上传文件，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) UploadFile(bucket, objectKey string, reader io.Reader, contentType string) (string, error) {
-	return c.provider.UploadFile(bucket, objectKey, reader, contentType)
+// UploadFile 上传文件，内容可重新读取（实现io.Seeker）时失败后重试
+func (c *StorageClient) UploadFile(ctx context.Context, bucket, objectKey string, reader io.Reader, contentType string) (string, error) {
+	seeker, retryable := reader.(io.Seeker)
+	var start int64
+	if retryable {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			retryable = false
+		}
+	}
+
+	// 无法重新读取的内容（如管道）可能因上传方读取失败而中断，这类错误不是服务故障
+	var source *sourceReader
+	if !retryable {
+		source = &sourceReader{Reader: reader}
+		reader = source
+	}
+
+	var url string
+	err := c.do(ctx, c.transfer, retryable, func(ctx context.Context) error {
+		if retryable {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		var err error
+		url, err = c.provider.UploadFile(ctx, bucket, objectKey, reader, contentType)
+		if err != nil && source != nil && source.err != nil {
+			return &sourceError{err: err}
+		}
+		return err
+	})
+	return url, err
 }
 
-// DownloadFile 下载文件，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) DownloadFile(bucket, objectKey string, writer io.Writer) error {
-	return c.provider.DownloadFile(bucket, objectKey, writer)
+// DownloadFile 下载文件，writer可以清空（实现Reset方法，如bytes.Buffer）时失败后重试
+func (c *StorageClient) DownloadFile(ctx context.Context, bucket, objectKey string, writer io.Writer) error {
+	resetter, retryable := writer.(interface{ Reset() })
+	return c.do(ctx, c.transfer, retryable, func(ctx context.Context) error {
+		if retryable {
+			resetter.Reset()
+		}
+		return c.provider.DownloadFile(ctx, bucket, objectKey, writer)
+	})
 }
 
-// DeleteFile 删除文件，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) DeleteFile(bucket, objectKey string) error {
-	return c.provider.DeleteFile(bucket, objectKey)
+// DeleteFile 删除文件，失败后重试
+func (c *StorageClient) DeleteFile(ctx context.Context, bucket, objectKey string) error {
+	return c.do(ctx, c.policy, true, func(ctx context.Context) error {
+		return c.provider.DeleteFile(ctx, bucket, objectKey)
+	})
 }
 
-// GetFileURL 获取文件访问URL，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) GetFileURL(bucket, objectKey string, expires time.Duration) (string, error) {
-	return c.provider.GetFileURL(bucket, objectKey, expires)
+// GetFileURL 获取文件访问URL
+// URL在本地签名生成，不计入熔断统计；熔断期间或生成失败时返回缓存中仍然有效的URL
+func (c *StorageClient) GetFileURL(ctx context.Context, bucket, objectKey string, expires time.Duration) (string, error) {
+	key := urlCacheKey(bucket, objectKey, expires)
+	if !c.breaker.Ready() {
+		if url, ok := cachedURLs.get(key); ok {
+			c.breaker.Fallback()
+			return url, nil
+		}
+	}
+
+	url, err := c.provider.GetFileURL(ctx, bucket, objectKey, expires)
+	if err != nil {
+		if cached, ok := cachedURLs.get(key); ok {
+			c.breaker.Fallback()
+			return cached, nil
+		}
+		return "", err
+	}
+	cachedURLs.set(key, url, expires)
+	return url, nil
+}
+
+// ListFiles 列出文件，失败后重试
+func (c *StorageClient) ListFiles(ctx context.Context, bucket, prefix string) ([]FileInfo, error) {
+	var files []FileInfo
+	err := c.do(ctx, c.policy, true, func(ctx context.Context) error {
+		var err error
+		files, err = c.provider.ListFiles(ctx, bucket, prefix)
+		return err
+	})
+	return files, err
+}
+
+// CopyFile 复制文件，目标对象相同，失败后重试
+func (c *StorageClient) CopyFile(ctx context.Context, srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
+	return c.do(ctx, c.policy, true, func(ctx context.Context) error {
+		return c.provider.CopyFile(ctx, srcBucket, srcObjectKey, destBucket, destObjectKey)
+	})
+}
+
+// MoveFile 移动文件，源文件可能已被删除，失败后不重试
+func (c *StorageClient) MoveFile(ctx context.Context, srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
+	return c.do(ctx, c.policy, false, func(ctx context.Context) error {
+		return c.provider.MoveFile(ctx, srcBucket, srcObjectKey, destBucket, destObjectKey)
+	})
+}
+
+// GetPresignedPutURL 获取预签名上传URL
+// URL在本地签名生成，不计入熔断统计；熔断期间客户端无法上传，直接返回ErrUnavailable
+func (c *StorageClient) GetPresignedPutURL(ctx context.Context, bucket, objectKey, contentType string, expires time.Duration) (string, error) {
+	if !c.breaker.Ready() {
+		return "", fmt.Errorf("%w: %w", ErrUnavailable, resilience.ErrCircuitOpen)
+	}
+	return c.provider.GetPresignedPutURL(ctx, bucket, objectKey, contentType, expires)
+}
+
+// StatFile 获取文件元信息，失败后重试
+func (c *StorageClient) StatFile(ctx context.Context, bucket, objectKey string) (*FileInfo, error) {
+	var info *FileInfo
+	err := c.do(ctx, c.policy, true, func(ctx context.Context) error {
+		var err error
+		info, err = c.provider.StatFile(ctx, bucket, objectKey)
+		return err
+	})
+	return info, err
+}
+
+// do 通过熔断器执行请求，熔断中时返回包装了ErrUnavailable的错误
+func (c *StorageClient) do(ctx context.Context, policy resilience.Policy, retryable bool, fn func(ctx context.Context) error) error {
+	isFailure := func(err error) bool {
+		var se *sourceError
+		return !errors.As(err, &se) && c.classify(err)
+	}
+	err := c.breaker.Do(ctx, resilience.Call{Policy: policy, Retryable: retryable, IsFailure: isFailure}, fn)
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return err
+}
+
+// sourceReader 记录上传内容读取错误的读取器
+type sourceReader struct {
+	io.Reader
+	err error
+}
+
+// Read 实现io.Reader接口，记录EOF以外的读取错误
+func (r *sourceReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
 }
 
-// ListFiles 列出文件，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) ListFiles(bucket, prefix string) ([]FileInfo, error) {
-	return c.provider.ListFiles(bucket, prefix)
+// sourceError 因读取上传内容失败而中断的上传错误，不计入熔断
+type sourceError struct {
+	err error
 }
 
-// CopyFile 复制文件，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) CopyFile(srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
-	return c.provider.CopyFile(srcBucket, srcObjectKey, destBucket, destObjectKey)
+// Error 返回上传失败的原因
+func (e *sourceError) Error() string {
+	return e.err.Error()
 }
 
-// MoveFile 移动文件，内部委托给具体的对象存储服务提供商实现
-func (c *StorageClient) MoveFile(srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
-	return c.provider.MoveFile(srcBucket, srcObjectKey, destBucket, destObjectKey)
+// Unwrap 返回上传失败的原始错误
+func (e *sourceError) Unwrap() error {
+	return e.err
 }
 
 // ProviderType 对象存储服务提供商类型，用于标识不同的对象存储服务提供商
@@ -153,5 +338,5 @@ func GetStorageClient(providerType ...ProviderType) (*StorageClient, error) {
 	}
 
 	// 创建并返回对象存储客户端
-	return NewStorageClient(provider), nil
+	return NewStorageClient(string(pType), provider), nil
 }
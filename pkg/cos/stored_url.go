@@ -0,0 +1,69 @@
+package cos
+
+import (
+	"context"
+	"time"
+
+	"app/config"
+)
+
+// StoredURL 保存到数据库的文件访问地址
+type StoredURL struct {
+	URL       string
+	Presigned bool       // 是否为带有效期的预签名URL
+	ExpiresAt *time.Time // 预签名URL的过期时间，永久URL为nil
+}
+
+// ExpiresWithin 判断预签名URL是否会在指定时长内过期，永久URL始终返回false
+func (u StoredURL) ExpiresWithin(d time.Duration) bool {
+	if !u.Presigned {
+		return false
+	}
+	return u.ExpiresAt == nil || !time.Now().Add(d).Before(*u.ExpiresAt)
+}
+
+// IsPrivateBucket 判断存储桶是否为私有读，bucket为空时判断默认存储桶
+func (c *StorageClient) IsPrivateBucket(bucket string) bool {
+	cfg := config.GetCOSConfig()
+	if bucket == "" {
+		bucket = cfg.Tencent.DefaultBucket
+	}
+	for _, name := range cfg.URL.PrivateBuckets {
+		if name == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStoredURL 获取保存到数据库的文件访问地址
+// 公有读的存储桶返回永久URL；私有读的存储桶返回按配置有效期生成的预签名URL，需在过期前通过RefreshURL重新生成
+func (c *StorageClient) GetStoredURL(ctx context.Context, bucket, objectKey string) (StoredURL, error) {
+	if !c.IsPrivateBucket(bucket) {
+		url, err := c.GetFileURL(ctx, bucket, objectKey, 0)
+		return StoredURL{URL: url}, err
+	}
+
+	// 不使用URL缓存，缓存中的URL已消耗部分有效期，与记录的过期时间不符
+	// 先计算过期时间，记录的时间不晚于URL实际过期的时间
+	expires := config.ParseDuration(config.GetCOSConfig().URL.PresignExpire, 0)
+	expiresAt := time.Now().Add(expires)
+	url, err := c.provider.GetFileURL(ctx, bucket, objectKey, expires)
+	if err != nil {
+		return StoredURL{}, err
+	}
+	return StoredURL{URL: url, Presigned: true, ExpiresAt: &expiresAt}, nil
+}
+
+// RefreshURL 返回数据库中保存的文件地址的可用版本，用于读取时的兜底
+// 预签名URL已过期时按对象键重新生成，生成失败时返回原地址；永久URL和未过期的预签名URL原样返回
+func (c *StorageClient) RefreshURL(ctx context.Context, bucket, objectKey string, stored StoredURL) string {
+	if !stored.ExpiresWithin(0) {
+		return stored.URL
+	}
+	fresh, err := c.GetStoredURL(ctx, bucket, objectKey)
+	if err != nil || fresh.URL == "" {
+		return stored.URL
+	}
+	return fresh.URL
+}
@@ -2,6 +2,7 @@ package cos
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"app/config"
+	"app/pkg/logger"
 
 	"github.com/tencentyun/cos-go-sdk-v5"
 )
@@ -46,17 +48,18 @@ func createTencentClient(cfg config.TencentCOSConfig) (*cos.Client, error) {
 	// 将 COS 服务的 URL 解析为一个 URL 结构体
 	u, err := url.Parse(fmt.Sprintf("https://%s.cos.%s.myqcloud.com", cfg.DefaultBucket, cfg.Region))
 	if err != nil {
-		return nil, fmt.Errorf("解析腾讯云COS URL失败: %v", err)
+		return nil, fmt.Errorf("解析腾讯云COS URL失败: %w", err)
 	}
 
 	// 基于 URL 创建 COS 客户端
 	b := &cos.BaseURL{BucketURL: u}
 	client := cos.NewClient(b, &http.Client{
-		Transport: &cos.AuthorizationTransport{
+		Transport: &requestIDTransport{next: &cos.AuthorizationTransport{
 			SecretID:  cfg.SecretID,
 			SecretKey: cfg.SecretKey,
-		},
+		}},
 	})
+	disableSDKRetry(client)
 
 	return client, nil
 }
@@ -71,20 +74,41 @@ func (p *TencentCOSProvider) getBucketClient(bucket string) (*cos.Client, error)
 	// 获取存储桶对象
 	bucketURL, err := url.Parse(fmt.Sprintf("https://%s.cos.%s.myqcloud.com", bucket, p.config.Region))
 	if err != nil {
-		return nil, fmt.Errorf("解析存储桶URL失败: %v", err)
+		return nil, fmt.Errorf("解析存储桶URL失败: %w", err)
 	}
 
 	// 创建并返回客户端
-	return cos.NewClient(&cos.BaseURL{BucketURL: bucketURL}, &http.Client{
-		Transport: &cos.AuthorizationTransport{
+	client := cos.NewClient(&cos.BaseURL{BucketURL: bucketURL}, &http.Client{
+		Transport: &requestIDTransport{next: &cos.AuthorizationTransport{
 			SecretID:  p.config.SecretID,
 			SecretKey: p.config.SecretKey,
-		},
-	}), nil
+		}},
+	})
+	disableSDKRetry(client)
+	return client, nil
+}
+
+// disableSDKRetry 关闭SDK内置的无间隔重试，由StorageClient按配置退避重试并统计熔断
+func disableSDKRetry(client *cos.Client) {
+	client.Conf.RetryOpt.Count = 1
+}
+
+// requestIDTransport 将上下文中的请求ID添加到发往对象存储服务的请求头
+type requestIDTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip 实现http.RoundTripper接口，请求ID为空时不添加请求头
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id := logger.RequestID(req.Context()); id != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(logger.RequestIDHeader, id)
+	}
+	return t.next.RoundTrip(req)
 }
 
 // UploadFile 上传文件，实现StorageProvider接口
-func (p *TencentCOSProvider) UploadFile(bucket, objectKey string, reader io.Reader, contentType string) (string, error) {
+func (p *TencentCOSProvider) UploadFile(ctx context.Context, bucket, objectKey string, reader io.Reader, contentType string) (string, error) {
 	// 获取存储桶客户端
 	bucketClient, err := p.getBucketClient(bucket)
 	if err != nil {
@@ -105,9 +129,9 @@ func (p *TencentCOSProvider) UploadFile(bucket, objectKey string, reader io.Read
 	}
 
 	// 上传文件
-	_, err = bucketClient.Object.Put(context.Background(), objectKey, reader, options)
+	_, err = bucketClient.Object.Put(ctx, objectKey, reader, options)
 	if err != nil {
-		return "", fmt.Errorf("上传文件失败: %v", err)
+		return "", fmt.Errorf("上传文件失败: %w", err)
 	}
 
 	// 返回文件URL
@@ -115,7 +139,7 @@ func (p *TencentCOSProvider) UploadFile(bucket, objectKey string, reader io.Read
 }
 
 // DownloadFile 下载文件，实现StorageProvider接口
-func (p *TencentCOSProvider) DownloadFile(bucket, objectKey string, writer io.Writer) error {
+func (p *TencentCOSProvider) DownloadFile(ctx context.Context, bucket, objectKey string, writer io.Writer) error {
 	// 获取存储桶客户端
 	bucketClient, err := p.getBucketClient(bucket)
 	if err != nil {
@@ -123,23 +147,23 @@ func (p *TencentCOSProvider) DownloadFile(bucket, objectKey string, writer io.Wr
 	}
 
 	// 下载文件
-	resp, err := bucketClient.Object.Get(context.Background(), objectKey, nil)
+	resp, err := bucketClient.Object.Get(ctx, objectKey, nil)
 	if err != nil {
-		return fmt.Errorf("下载文件失败: %v", err)
+		return fmt.Errorf("下载文件失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 将响应内容写入writer
 	_, err = io.Copy(writer, resp.Body)
 	if err != nil {
-		return fmt.Errorf("写入文件内容失败: %v", err)
+		return fmt.Errorf("写入文件内容失败: %w", err)
 	}
 
 	return nil
 }
 
 // DeleteFile 删除文件，实现StorageProvider接口
-func (p *TencentCOSProvider) DeleteFile(bucket, objectKey string) error {
+func (p *TencentCOSProvider) DeleteFile(ctx context.Context, bucket, objectKey string) error {
 	// 获取存储桶客户端
 	bucketClient, err := p.getBucketClient(bucket)
 	if err != nil {
@@ -147,16 +171,16 @@ func (p *TencentCOSProvider) DeleteFile(bucket, objectKey string) error {
 	}
 
 	// 删除文件
-	_, err = bucketClient.Object.Delete(context.Background(), objectKey)
+	_, err = bucketClient.Object.Delete(ctx, objectKey)
 	if err != nil {
-		return fmt.Errorf("删除文件失败: %v", err)
+		return fmt.Errorf("删除文件失败: %w", err)
 	}
 
 	return nil
 }
 
 // GetFileURL 获取文件访问URL，实现StorageProvider接口
-func (p *TencentCOSProvider) GetFileURL(bucket, objectKey string, expires time.Duration) (string, error) {
+func (p *TencentCOSProvider) GetFileURL(ctx context.Context, bucket, objectKey string, expires time.Duration) (string, error) {
 	// 如果未指定存储桶，则使用默认存储桶
 	if bucket == "" {
 		bucket = p.config.DefaultBucket
@@ -176,7 +200,7 @@ func (p *TencentCOSProvider) GetFileURL(bucket, objectKey string, expires time.D
 
 	// 生成预签名URL
 	presignedURL, err := bucketClient.Object.GetPresignedURL(
-		context.Background(),
+		ctx,
 		http.MethodGet,
 		objectKey,
 		p.config.SecretID,
@@ -185,14 +209,14 @@ func (p *TencentCOSProvider) GetFileURL(bucket, objectKey string, expires time.D
 		nil,
 	)
 	if err != nil {
-		return "", fmt.Errorf("生成预签名URL失败: %v", err)
+		return "", fmt.Errorf("生成预签名URL失败: %w", err)
 	}
 
 	return presignedURL.String(), nil
 }
 
 // ListFiles 列出文件，实现StorageProvider接口
-func (p *TencentCOSProvider) ListFiles(bucket, prefix string) ([]FileInfo, error) {
+func (p *TencentCOSProvider) ListFiles(ctx context.Context, bucket, prefix string) ([]FileInfo, error) {
 	// 获取存储桶客户端
 	bucketClient, err := p.getBucketClient(bucket)
 	if err != nil {
@@ -208,9 +232,9 @@ func (p *TencentCOSProvider) ListFiles(bucket, prefix string) ([]FileInfo, error
 	opt := &cos.BucketGetOptions{
 		Prefix: prefix,
 	}
-	result, _, err := bucketClient.Bucket.Get(context.Background(), opt)
+	result, _, err := bucketClient.Bucket.Get(ctx, opt)
 	if err != nil {
-		return nil, fmt.Errorf("列出文件失败: %v", err)
+		return nil, fmt.Errorf("列出文件失败: %w", err)
 	}
 
 	// 转换为通用文件信息结构
@@ -244,7 +268,7 @@ func (p *TencentCOSProvider) getFileURL(bucket, objectKey string) string {
 }
 
 // CopyFile 复制文件，实现StorageProvider接口
-func (p *TencentCOSProvider) CopyFile(srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
+func (p *TencentCOSProvider) CopyFile(ctx context.Context, srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
 	// 获取目标存储桶客户端
 	destClient, err := p.getBucketClient(destBucket)
 	if err != nil {
@@ -264,29 +288,104 @@ func (p *TencentCOSProvider) CopyFile(srcBucket, srcObjectKey, destBucket, destO
 	sourceURL := fmt.Sprintf("https://%s.cos.%s.myqcloud.com/%s", srcBucket, p.config.Region, srcObjectKey)
 
 	// 复制对象
-	_, _, err = destClient.Object.Copy(context.Background(), destObjectKey, sourceURL, nil)
+	_, _, err = destClient.Object.Copy(ctx, destObjectKey, sourceURL, nil)
 	if err != nil {
-		return fmt.Errorf("复制文件失败: %v", err)
+		return fmt.Errorf("复制文件失败: %w", err)
 	}
 
 	return nil
 }
 
 // MoveFile 移动文件，实现StorageProvider接口
-func (p *TencentCOSProvider) MoveFile(srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
+func (p *TencentCOSProvider) MoveFile(ctx context.Context, srcBucket, srcObjectKey, destBucket, destObjectKey string) error {
 	// 移动文件实际上是先复制，再删除源文件
 	// 先复制文件
-	err := p.CopyFile(srcBucket, srcObjectKey, destBucket, destObjectKey)
+	err := p.CopyFile(ctx, srcBucket, srcObjectKey, destBucket, destObjectKey)
 	if err != nil {
-		return fmt.Errorf("移动文件时复制失败: %v", err)
+		return fmt.Errorf("移动文件时复制失败: %w", err)
 	}
 
 	// 删除源文件
-	err = p.DeleteFile(srcBucket, srcObjectKey)
+	err = p.DeleteFile(ctx, srcBucket, srcObjectKey)
 	if err != nil {
 		// 如果删除源文件失败，记录错误但不中断操作，因为文件已经成功复制
-		fmt.Printf("警告: 移动文件时删除源文件失败: %v\n", err)
+		logger.Warn(ctx, "移动文件时删除源文件失败",
+			logger.String("bucket", srcBucket), logger.String("object_key", srcObjectKey), logger.Err(err))
 	}
 
 	return nil
 }
+
+// GetPresignedPutURL 获取预签名上传URL，实现StorageProvider接口
+func (p *TencentCOSProvider) GetPresignedPutURL(ctx context.Context, bucket, objectKey, contentType string, expires time.Duration) (string, error) {
+	// 预签名URL必须使用COS官方域名
+	bucketClient, err := p.getBucketClient(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	// 指定内容类型时将其纳入签名，客户端上传时必须携带相同的Content-Type
+	var opt *cos.PresignedURLOptions
+	if contentType != "" {
+		header := http.Header{}
+		header.Set("Content-Type", contentType)
+		opt = &cos.PresignedURLOptions{Header: &header}
+	}
+
+	presignedURL, err := bucketClient.Object.GetPresignedURL(
+		ctx,
+		http.MethodPut,
+		objectKey,
+		p.config.SecretID,
+		p.config.SecretKey,
+		expires,
+		opt,
+	)
+	if err != nil {
+		return "", fmt.Errorf("生成预签名上传URL失败: %w", err)
+	}
+
+	return presignedURL.String(), nil
+}
+
+// StatFile 获取文件元信息，实现StorageProvider接口
+func (p *TencentCOSProvider) StatFile(ctx context.Context, bucket, objectKey string) (*FileInfo, error) {
+	// 获取存储桶客户端
+	bucketClient, err := p.getBucketClient(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	// 通过HEAD请求获取对象元信息
+	resp, err := bucketClient.Object.Head(ctx, objectKey, nil)
+	if err != nil {
+		if cos.IsNotFoundError(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	lastModified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return &FileInfo{
+		Key:          objectKey,
+		Size:         resp.ContentLength,
+		LastModified: lastModified,
+		ETag:         resp.Header.Get("ETag"),
+		StorageClass: resp.Header.Get("x-cos-storage-class"),
+		ContentType:  resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// IsServiceError 判断错误是否为对象存储服务故障，实现errorClassifier接口
+// 对象不存在和限流以外的4xx响应是请求本身的错误，不计入熔断也不重试
+func (p *TencentCOSProvider) IsServiceError(err error) bool {
+	if errors.Is(err, ErrObjectNotFound) {
+		return false
+	}
+	var resp *cos.ErrorResponse
+	if errors.As(err, &resp) && resp.Response != nil {
+		code := resp.Response.StatusCode
+		return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package cos
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// urlCacheSize URL缓存的最大条目数，写满后清理过期条目，仍然写满时清空
+const urlCacheSize = 10000
+
+// cachedURLs 最近生成的预签名URL，对象存储服务熔断期间返回缓存中仍然有效的URL
+var cachedURLs = &urlCache{entries: make(map[string]urlCacheEntry)}
+
+// urlCache 预签名URL缓存，并发安全
+type urlCache struct {
+	mu      sync.Mutex
+	entries map[string]urlCacheEntry
+}
+
+// urlCacheEntry URL缓存条目
+type urlCacheEntry struct {
+	url        string
+	validUntil time.Time // 超过该时间后不再返回，保证返回的URL至少还有一半有效期
+}
+
+// urlCacheKey URL缓存的键
+func urlCacheKey(bucket, objectKey string, expires time.Duration) string {
+	return bucket + "/" + objectKey + "@" + strconv.FormatInt(int64(expires), 10)
+}
+
+// get 获取仍然有效的URL
+func (c *urlCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.validUntil) {
+		return "", false
+	}
+	return entry.url, true
+}
+
+// set 缓存URL，永久URL不依赖对象存储服务生成，不缓存
+func (c *urlCache) set(key, url string, expires time.Duration) {
+	if expires <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= urlCacheSize {
+		for k, entry := range c.entries {
+			if now.After(entry.validUntil) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= urlCacheSize {
+			c.entries = make(map[string]urlCacheEntry)
+		}
+	}
+	c.entries[key] = urlCacheEntry{url: url, validUntil: now.Add(expires / 2)}
+}
@@ -0,0 +1,64 @@
+// Package dbtest 提供仓库层测试使用的数据库环境
+//
+// 测试数据库通常使用SQLite内存数据库，由测试代码传入方言，避免在生产构建中引入SQLite驱动：
+//
+//	db, err := dbtest.Open(sqlite.Open(dbtest.MemoryDSN))
+//	...
+//	tx := dbtest.Begin(t, db)
+//	repo := repository.NewPostRepository(tx)
+//
+// Begin 为每个测试开启独立事务并在测试结束后回滚，测试之间互不影响。
+// 仓库中的事务需使用 db.Transaction，嵌套在测试事务中时会以保存点执行。
+package dbtest
+
+import (
+	"fmt"
+	"testing"
+
+	"app/internal/model"
+	"app/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// MemoryDSN SQLite内存数据库连接串
+// 使用共享缓存，使同一进程内的连接访问同一个内存数据库
+const MemoryDSN = "file::memory:?cache=shared"
+
+// Open 打开测试数据库并自动迁移所有模型
+func Open(dialector gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, database.NewGormConfig())
+	if err != nil {
+		return nil, fmt.Errorf("连接测试数据库失败: %w", err)
+	}
+
+	// 内存数据库在最后一个连接关闭后即被销毁，且SQLite不支持并发写入，因此只保留一个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取底层SQL连接失败: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, m := range model.All() {
+		if err := db.AutoMigrate(m); err != nil {
+			return nil, fmt.Errorf("迁移模型 %T 失败: %w", m, err)
+		}
+	}
+
+	return db, nil
+}
+
+// Begin 为当前测试开启事务，测试结束后自动回滚
+func Begin(t testing.TB, db *gorm.DB) *gorm.DB {
+	t.Helper()
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("开启测试事务失败: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+
+	return tx
+}
@@ -2,10 +2,13 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"app/config"
+	"app/pkg/retry"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -15,39 +18,71 @@ import (
 // DB 全局数据库连接实例
 var DB *gorm.DB
 
+var (
+	// pool 全局数据库实例使用的连接池，重建连接时替换其底层连接
+	pool *switchablePool
+	// monitor 连接健康检查器
+	monitor *retry.Monitor
+)
+
+// 连接相关常量
+const (
+	// pingTimeout 连接测试超时时间
+	pingTimeout = 5 * time.Second
+	// retiredCloseDelay 重建连接后延迟关闭旧连接的时间，等待正在执行的查询和事务完成
+	retiredCloseDelay = 30 * time.Second
+)
+
 // Init 初始化数据库连接并配置连接池
+// 连接失败时按配置的策略重试，等待数据库就绪，初始化成功后启动连接健康检查
 func Init() error {
 	cfg := config.GetDatabaseConfig()
-
-	// 构建DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
-
-	// 解析连接时间配置
-	connMaxLifetime, _ := time.ParseDuration(cfg.ConnMaxLifetime)
-	connMaxIdleTime, _ := time.ParseDuration(cfg.ConnMaxIdleTime)
-	maxIdleConns := cfg.MaxConnections / 4
-
-	// GORM配置
-	gormConfig := &gorm.Config{
-		NamingStrategy: schema.NamingStrategy{
-			SingularTable: true, // 使用单数表名
-		},
-		DisableForeignKeyConstraintWhenMigrating: true, // 禁用外键约束
+	policy := retry.NewPolicy(cfg.Retry)
+
+	var sqlDB *sql.DB
+	err := retry.Do("数据库", policy, func() error {
+		var err error
+		sqlDB, err = open(cfg)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	// 连接数据库
-	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
+	// 使用可替换底层连接的连接池创建GORM实例
+	pool = newSwitchablePool(sqlDB)
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: buildDSN(cfg), Conn: pool}), NewGormConfig())
 	if err != nil {
+		_ = sqlDB.Close()
 		return fmt.Errorf("连接数据库失败: %w", err)
 	}
 
-	// 获取底层SQL DB连接并配置连接池
-	sqlDB, err := db.DB()
+	// 设置全局数据库实例
+	DB = db
+	monitor = retry.StartMonitor("数据库", policy, ping, func() error {
+		return reconnect(cfg)
+	})
+	return nil
+}
+
+// buildDSN 构建数据库连接字符串
+func buildDSN(cfg config.DatabaseConfig) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+}
+
+// open 打开数据库连接、配置连接池并测试连接
+func open(cfg config.DatabaseConfig) (*sql.DB, error) {
+	sqlDB, err := sql.Open("mysql", buildDSN(cfg))
 	if err != nil {
-		return fmt.Errorf("获取底层SQL连接失败: %w", err)
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
 	}
 
+	// 解析连接时间配置
+	connMaxLifetime, _ := time.ParseDuration(cfg.ConnMaxLifetime)
+	connMaxIdleTime, _ := time.ParseDuration(cfg.ConnMaxIdleTime)
+	maxIdleConns := cfg.MaxConnections / 4
+
 	// 配置连接池
 	sqlDB.SetMaxOpenConns(cfg.MaxConnections)
 	sqlDB.SetMaxIdleConns(maxIdleConns)
@@ -55,22 +90,60 @@ func Init() error {
 	sqlDB.SetConnMaxIdleTime(connMaxIdleTime)
 
 	// 测试连接
-	if err := sqlDB.Ping(); err != nil {
-		return fmt.Errorf("数据库连接测试失败: %w", err)
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
 	}
 
-	// 设置全局数据库实例
-	DB = db
+	return sqlDB, nil
+}
+
+// ping 检查全局数据库实例的连接状态
+func ping() error {
+	sqlDB, _ := pool.GetDBConn()
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// reconnect 创建新的连接替换全局数据库实例的底层连接，旧连接延迟关闭
+func reconnect(cfg config.DatabaseConfig) error {
+	sqlDB, err := open(cfg)
+	if err != nil {
+		return err
+	}
+
+	old := pool.swap(sqlDB)
+	time.AfterFunc(retiredCloseDelay, func() {
+		_ = old.Close()
+	})
 	return nil
 }
 
+// NewGormConfig 创建GORM配置
+// 所有数据库连接（包括测试数据库）共用相同的命名策略和插件
+func NewGormConfig() *gorm.Config {
+	return &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true, // 使用单数表名
+		},
+		DisableForeignKeyConstraintWhenMigrating: true, // 禁用外键约束
+		Plugins: map[string]gorm.Plugin{
+			publicIDPlugin{}.Name(): publicIDPlugin{}, // 创建记录时生成公开ID
+		},
+	}
+}
+
 // GetDB 获取数据库连接实例
 func GetDB() *gorm.DB {
 	return DB
 }
 
-// Close 关闭数据库连接
+// Close 停止连接健康检查并关闭数据库连接
 func Close() error {
+	monitor.Stop()
 	if DB == nil {
 		return nil
 	}
@@ -108,17 +181,11 @@ func CheckDBHealth() (map[string]interface{}, error) {
 
 // GetDBStats 获取数据库连接池统计信息
 func GetDBStats() (map[string]interface{}, error) {
-	if DB == nil {
-		return nil, fmt.Errorf("数据库未初始化")
-	}
-
-	sqlDB, err := DB.DB()
+	stats, err := PoolStats()
 	if err != nil {
-		return nil, fmt.Errorf("获取底层SQL连接失败: %w", err)
+		return nil, err
 	}
 
-	// 获取连接池统计信息
-	stats := sqlDB.Stats()
 	return map[string]interface{}{
 		"max_open_connections": stats.MaxOpenConnections,
 		"open_connections":     stats.OpenConnections,
@@ -130,3 +197,16 @@ func GetDBStats() (map[string]interface{}, error) {
 		"max_lifetime_closed":  stats.MaxLifetimeClosed,
 	}, nil
 }
+
+// PoolStats 获取当前底层连接的连接池统计信息，重建连接后统计从零开始
+func PoolStats() (sql.DBStats, error) {
+	if DB == nil {
+		return sql.DBStats{}, fmt.Errorf("数据库未初始化")
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return sql.DBStats{}, fmt.Errorf("获取底层SQL连接失败: %w", err)
+	}
+	return sqlDB.Stats(), nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+// switchablePool 可替换底层连接的连接池
+// GORM实例持有该连接池，重建连接时只替换内部的*sql.DB，已注入各仓库的GORM实例无需更新
+type switchablePool struct {
+	db atomic.Pointer[sql.DB]
+}
+
+// newSwitchablePool 创建可替换底层连接的连接池
+func newSwitchablePool(db *sql.DB) *switchablePool {
+	p := &switchablePool{}
+	p.db.Store(db)
+	return p
+}
+
+// swap 替换底层连接，返回被替换的连接
+func (p *switchablePool) swap(db *sql.DB) *sql.DB {
+	return p.db.Swap(db)
+}
+
+// PrepareContext 创建预处理语句
+func (p *switchablePool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.Load().PrepareContext(ctx, query)
+}
+
+// ExecContext 执行不返回结果集的语句
+func (p *switchablePool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.Load().ExecContext(ctx, query, args...)
+}
+
+// QueryContext 执行查询并返回结果集
+func (p *switchablePool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.Load().QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext 执行查询并返回单行结果
+func (p *switchablePool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.Load().QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx 开启事务，事务期间始终使用开启时的底层连接
+func (p *switchablePool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.db.Load().BeginTx(ctx, opts)
+}
+
+// GetDBConn 返回当前的底层连接，供GORM的DB()方法使用
+func (p *switchablePool) GetDBConn() (*sql.DB, error) {
+	return p.db.Load(), nil
+}
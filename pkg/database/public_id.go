@@ -0,0 +1,55 @@
+package database
+
+import (
+	"reflect"
+
+	"app/pkg/idgen"
+
+	"gorm.io/gorm"
+)
+
+// publicIDField 公开ID的字段名，模型包含该字段时创建记录前自动生成
+const publicIDField = "PublicID"
+
+// publicIDPlugin 创建记录前为未设置公开ID的记录生成公开ID
+type publicIDPlugin struct{}
+
+// Name 返回插件名称
+func (publicIDPlugin) Name() string {
+	return "public_id"
+}
+
+// Initialize 注册创建前的回调
+func (publicIDPlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("public_id:generate", generatePublicIDs)
+}
+
+// generatePublicIDs 为单条或批量创建的记录生成公开ID，已设置的公开ID保持不变
+func generatePublicIDs(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(publicIDField)
+	if field == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	set := func(rv reflect.Value) {
+		if _, zero := field.ValueOf(ctx, rv); zero {
+			if err := field.Set(ctx, rv, idgen.New()); err != nil {
+				_ = db.AddError(err)
+			}
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			set(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		set(rv)
+	}
+}
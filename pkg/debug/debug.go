@@ -0,0 +1,143 @@
+// Package debug 提供运行时调试接口，包括pprof性能分析、GC统计、goroutine堆栈和构建信息，用于生产环境排查性能问题
+// 接口会暴露进程内部状态，只应挂载在需要管理员权限的路由组下
+package debug
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rtdebug "runtime/debug"
+	rtpprof "runtime/pprof"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recentPauses 返回的最近GC暂停次数
+const recentPauses = 16
+
+// Register 在路由组下注册调试接口，pprof的路径需为 /debug/pprof/，路由组应挂载在 /debug
+func Register(group *gin.RouterGroup) {
+	group.GET("/pprof/", gin.WrapF(pprof.Index))
+	group.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	group.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	group.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	group.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	group.GET("/pprof/:name", handleProfile)
+
+	group.GET("/gc", handleGCStats)
+	group.GET("/goroutines", handleGoroutines)
+	group.GET("/buildinfo", handleBuildInfo)
+}
+
+// handleProfile 输出指定名称的profile，如heap、goroutine、allocs、block、mutex、threadcreate
+func handleProfile(c *gin.Context) {
+	pprof.Handler(c.Param("name")).ServeHTTP(c.Writer, c.Request)
+}
+
+// GCStats GC和内存统计
+type GCStats struct {
+	NumGC          uint32    `json:"num_gc"`
+	LastGC         time.Time `json:"last_gc"`
+	PauseTotalMs   float64   `json:"pause_total_ms"`
+	RecentPausesMs []float64 `json:"recent_pauses_ms"` // 最近的GC暂停时间，最新的在前
+	GCCPUFraction  float64   `json:"gc_cpu_fraction"`  // 程序启动以来GC占用的CPU时间比例
+	NextGC         uint64    `json:"next_gc"`          // 下一次GC的目标堆大小
+	HeapAlloc      uint64    `json:"heap_alloc"`
+	HeapInuse      uint64    `json:"heap_inuse"`
+	HeapSys        uint64    `json:"heap_sys"`
+	HeapObjects    uint64    `json:"heap_objects"`
+	Sys            uint64    `json:"sys"`
+	NumGoroutine   int       `json:"num_goroutine"`
+	GOMAXPROCS     int       `json:"gomaxprocs"`
+}
+
+// handleGCStats 输出GC和内存统计，读取内存统计会短暂暂停程序
+func handleGCStats(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var gc rtdebug.GCStats
+	rtdebug.ReadGCStats(&gc)
+	recent := gc.Pause
+	if len(recent) > recentPauses {
+		recent = recent[:recentPauses]
+	}
+	pauses := make([]float64, 0, len(recent))
+	for _, d := range recent {
+		pauses = append(pauses, float64(d)/float64(time.Millisecond))
+	}
+
+	c.JSON(http.StatusOK, GCStats{
+		NumGC:          mem.NumGC,
+		LastGC:         gc.LastGC,
+		PauseTotalMs:   float64(gc.PauseTotal) / float64(time.Millisecond),
+		RecentPausesMs: pauses,
+		GCCPUFraction:  mem.GCCPUFraction,
+		NextGC:         mem.NextGC,
+		HeapAlloc:      mem.HeapAlloc,
+		HeapInuse:      mem.HeapInuse,
+		HeapSys:        mem.HeapSys,
+		HeapObjects:    mem.HeapObjects,
+		Sys:            mem.Sys,
+		NumGoroutine:   runtime.NumGoroutine(),
+		GOMAXPROCS:     runtime.GOMAXPROCS(0),
+	})
+}
+
+// handleGoroutines 以文本格式输出所有goroutine的堆栈
+// 默认输出每个goroutine的完整堆栈，debug=1时按相同堆栈合并计数
+func handleGoroutines(c *gin.Context) {
+	level := 2
+	if c.Query("debug") == "1" {
+		level = 1
+	}
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = rtpprof.Lookup("goroutine").WriteTo(c.Writer, level)
+}
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	GoVersion string            `json:"go_version"`
+	Path      string            `json:"path"`     // 主包路径
+	Version   string            `json:"version"`  // 主模块版本，本地构建时为(devel)
+	Settings  map[string]string `json:"settings"` // 构建参数，包含vcs.revision、vcs.time等
+	Deps      []BuildDep        `json:"deps"`
+}
+
+// BuildDep 依赖模块
+type BuildDep struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Replace string `json:"replace,omitempty"` // 替换后的模块路径
+}
+
+// handleBuildInfo 输出二进制文件的构建信息
+func handleBuildInfo(c *gin.Context) {
+	info, ok := rtdebug.ReadBuildInfo()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "未找到构建信息"})
+		return
+	}
+
+	res := BuildInfo{
+		GoVersion: info.GoVersion,
+		Path:      info.Path,
+		Version:   info.Main.Version,
+		Settings:  make(map[string]string, len(info.Settings)),
+		Deps:      make([]BuildDep, 0, len(info.Deps)),
+	}
+	for _, s := range info.Settings {
+		res.Settings[s.Key] = s.Value
+	}
+	for _, dep := range info.Deps {
+		d := BuildDep{Path: dep.Path, Version: dep.Version}
+		if dep.Replace != nil {
+			d.Replace = dep.Replace.Path + "@" + dep.Replace.Version
+		}
+		res.Deps = append(res.Deps, d)
+	}
+	c.JSON(http.StatusOK, res)
+}
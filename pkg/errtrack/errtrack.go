@@ -0,0 +1,98 @@
+// Package errtrack 提供错误追踪上报的统一接口和实现，支持接入Sentry等第三方错误追踪服务
+package errtrack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"app/config"
+)
+
+// 默认上报请求超时时间
+const defaultTimeout = 3 * time.Second
+
+// Event 错误事件
+type Event struct {
+	Message   string            // 错误描述
+	Stack     string            // 调用栈
+	RequestID string            // 请求ID，便于与日志关联
+	UserID    uint              // 当前用户ID，未登录时为0
+	Method    string            // 请求方法
+	URL       string            // 请求地址
+	Tags      map[string]string // 附加标签
+	Timestamp time.Time         // 发生时间
+}
+
+// Provider 错误追踪服务提供商接口，所有服务提供商都需要实现此接口
+type Provider interface {
+	// Report 上报错误事件
+	// 参数: ctx - 上下文, event - 错误事件
+	// 返回: 可能的错误
+	Report(ctx context.Context, event *Event) error
+}
+
+// ProviderType 错误追踪服务提供商类型
+type ProviderType string
+
+// 支持的错误追踪服务提供商类型
+const (
+	SentryProvider ProviderType = "sentry" // Sentry
+)
+
+var (
+	defaultProvider Provider
+	initErr         error
+	once            sync.Once
+)
+
+// GetProvider 根据全局配置获取错误追踪服务提供商
+// 返回: 服务提供商和可能的错误，未配置DSN时返回nil提供商
+func GetProvider() (Provider, error) {
+	once.Do(func() {
+		defaultProvider, initErr = NewProvider(config.GetErrTrackConfig())
+	})
+	return defaultProvider, initErr
+}
+
+// NewProvider 根据配置创建错误追踪服务提供商，未配置DSN时返回nil提供商
+func NewProvider(cfg config.ErrTrackConfig) (Provider, error) {
+	if cfg.DSN == "" {
+		return nil, nil
+	}
+
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	// 默认使用Sentry
+	pType := ProviderType(cfg.Provider)
+	if pType == "" {
+		pType = SentryProvider
+	}
+
+	switch pType {
+	case SentryProvider:
+		reporter, err := NewSentryReporter(cfg.DSN, cfg.Environment, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return reporter, nil
+	default:
+		return nil, fmt.Errorf("不支持的错误追踪服务提供商类型: %s", pType)
+	}
+}
+
+// Report 使用全局配置的服务提供商上报错误事件，未配置时不做任何操作
+func Report(ctx context.Context, event *Event) error {
+	provider, err := GetProvider()
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return nil
+	}
+	return provider.Report(ctx, event)
+}
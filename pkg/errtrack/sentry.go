@@ -0,0 +1,126 @@
+package errtrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sentryClientName 上报时标识的客户端名称
+const sentryClientName = "livefe-app/1.0"
+
+// SentryReporter Sentry错误追踪服务提供商，通过Store接口上报事件，实现了Provider接口
+type SentryReporter struct {
+	storeURL    string
+	publicKey   string
+	environment string
+	client      *http.Client
+}
+
+// NewSentryReporter 创建Sentry错误追踪服务提供商实例
+// 参数: dsn - 项目DSN，格式为 https://公钥@主机/项目ID, environment - 环境名称, timeout - 上报超时时间
+func NewSentryReporter(dsn, environment string, timeout time.Duration) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("解析Sentry DSN失败: %w", err)
+	}
+	projectID := strings.Trim(u.Path, "/")
+	if u.User == nil || u.User.Username() == "" || projectID == "" {
+		return nil, fmt.Errorf("无效的Sentry DSN: %s", u.Redacted())
+	}
+
+	return &SentryReporter{
+		storeURL:    fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, projectID),
+		publicKey:   u.User.Username(),
+		environment: environment,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// sentryEvent Sentry事件结构
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Message     string            `json:"message"`
+	Environment string            `json:"environment,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Request     *sentryRequest    `json:"request,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+// sentryUser 事件关联的用户
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+// sentryRequest 事件关联的请求
+type sentryRequest struct {
+	URL    string `json:"url"`
+	Method string `json:"method"`
+}
+
+// Report 上报错误事件，实现Provider接口
+func (p *SentryReporter) Report(ctx context.Context, event *Event) error {
+	tags := make(map[string]string, len(event.Tags)+1)
+	for k, v := range event.Tags {
+		tags[k] = v
+	}
+	if event.RequestID != "" {
+		tags["request_id"] = event.RequestID
+	}
+
+	payload := sentryEvent{
+		EventID:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   event.Timestamp.UTC().Format(time.RFC3339),
+		Level:       "error",
+		Platform:    "go",
+		Logger:      "errtrack",
+		Message:     event.Message,
+		Environment: p.environment,
+		Tags:        tags,
+	}
+	if event.UserID > 0 {
+		payload.User = &sentryUser{ID: fmt.Sprint(event.UserID)}
+	}
+	if event.URL != "" {
+		payload.Request = &sentryRequest{URL: event.URL, Method: event.Method}
+	}
+	if event.Stack != "" {
+		payload.Extra = map[string]string{"stack": event.Stack}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化Sentry事件失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s",
+		sentryClientName, p.publicKey))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("上报Sentry事件失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Sentry返回异常状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+// Package featureflag 提供功能开关，支持按用户比例灰度发布、按用户标签开启和按用户强制开启或关闭
+// 开关保存在Redis哈希表中，各服务实例在本地缓存开关并定期刷新
+package featureflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"app/config"
+	"app/pkg/redis"
+)
+
+const (
+	// 未指定时保存所有开关的哈希表键，字段为开关标识，值为开关的JSON
+	defaultStoreKey = "feature:flags"
+	// 默认本地缓存刷新间隔
+	defaultRefreshInterval = 30 * time.Second
+	// 灰度分桶数量，灰度比例以百分比表示
+	bucketCount = 100
+)
+
+// ErrFlagNotFound 开关不存在
+var ErrFlagNotFound = errors.New("功能开关不存在")
+
+// Flag 功能开关
+type Flag struct {
+	Key         string        `json:"key"`         // 开关标识
+	Description string        `json:"description"` // 开关说明
+	Enabled     bool          `json:"enabled"`     // 总开关，关闭时对所有用户关闭，按用户覆盖也不生效
+	Percentage  int           `json:"percentage"`  // 灰度比例（0-100），按用户ID稳定分桶
+	Overrides   map[uint]bool `json:"overrides"`   // 按用户覆盖灰度结果，key为用户ID
+	Tags        []string      `json:"tags"`        // 对拥有任一标签的用户开启，不受灰度比例限制
+	UpdatedAt   time.Time     `json:"updated_at"`  // 最近修改时间
+}
+
+// Evaluate 计算开关对指定用户是否开启
+// 依次判断总开关、按用户覆盖、用户标签和灰度比例，未登录用户（userID为0）只在全量开启时可见
+// userTags为用户拥有的标签，开关未设置标签时不使用
+func (f *Flag) Evaluate(userID uint, userTags []string) bool {
+	if !f.Enabled {
+		return false
+	}
+	if enabled, ok := f.Overrides[userID]; ok {
+		return enabled
+	}
+	for _, tag := range f.Tags {
+		if slices.Contains(userTags, tag) {
+			return true
+		}
+	}
+	if f.Percentage >= bucketCount {
+		return true
+	}
+	if f.Percentage <= 0 || userID == 0 {
+		return false
+	}
+	return Bucket(f.Key, userID) < f.Percentage
+}
+
+// Bucket 计算用户在开关下的灰度分桶（0-99）
+// 分桶由开关标识和用户ID共同决定，同一用户在不同开关下的分桶相互独立，调大比例时已开启的用户保持开启
+func Bucket(key string, userID uint) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", key, userID)
+	return int(h.Sum32() % bucketCount)
+}
+
+// TagResolver 查询用户拥有的标签，用于按用户标签开启的开关
+type TagResolver func(userID uint) []string
+
+// Client 功能开关客户端
+// 读取开关时使用本地缓存，修改开关后立即刷新本实例的缓存，其他实例在刷新间隔内生效
+type Client struct {
+	store    redis.Store     // 开关存储
+	storeKey string          // 保存所有开关的哈希表键
+	refresh  time.Duration   // 本地缓存刷新间隔
+	defaults map[string]bool // 开关未创建时的默认值
+	tags     TagResolver     // 用户标签查询，未设置时按标签开启的规则不生效
+
+	mu       sync.RWMutex
+	flags    map[string]*Flag
+	loadedAt time.Time
+}
+
+// NewClient 创建功能开关客户端实例
+// 参数: store - 开关存储, storeKey - 保存开关的哈希表键，为空时使用默认键, refresh - 本地缓存刷新间隔, defaults - 开关未创建时的默认值
+// 返回: 功能开关客户端指针
+func NewClient(store redis.Store, storeKey string, refresh time.Duration, defaults map[string]bool) *Client {
+	if storeKey == "" {
+		storeKey = defaultStoreKey
+	}
+	if refresh <= 0 {
+		refresh = defaultRefreshInterval
+	}
+	return &Client{
+		store:    store,
+		storeKey: storeKey,
+		refresh:  refresh,
+		defaults: defaults,
+	}
+}
+
+// GetClient 根据全局配置创建功能开关客户端
+// 参数: store - 开关存储, storeKey - 保存开关的哈希表键
+// 返回: 功能开关客户端指针
+func GetClient(store redis.Store, storeKey string) *Client {
+	cfg := config.GetFeatureFlagConfig()
+
+	refresh := defaultRefreshInterval
+	if d, err := time.ParseDuration(cfg.RefreshInterval); err == nil && d > 0 {
+		refresh = d
+	}
+
+	return NewClient(store, storeKey, refresh, cfg.Defaults)
+}
+
+// SetTagResolver 设置用户标签查询，应在处理请求前调用
+func (c *Client) SetTagResolver(resolver TagResolver) {
+	c.tags = resolver
+}
+
+// IsEnabled 判断开关对指定用户是否开启，开关未创建时返回配置的默认值
+func (c *Client) IsEnabled(key string, userID uint) bool {
+	if flag, ok := c.snapshot()[key]; ok {
+		var userTags []string
+		if len(flag.Tags) > 0 {
+			userTags = c.userTags(userID)
+		}
+		return flag.Evaluate(userID, userTags)
+	}
+	return c.defaults[key]
+}
+
+// Evaluate 计算所有开关对指定用户的开启状态，包括仅配置了默认值的开关
+func (c *Client) Evaluate(userID uint) map[string]bool {
+	result := make(map[string]bool, len(c.defaults))
+	for key, enabled := range c.defaults {
+		result[key] = enabled
+	}
+
+	// 只在有开关按标签开启时查询一次用户标签
+	var userTags []string
+	loaded := false
+	for key, flag := range c.snapshot() {
+		if len(flag.Tags) > 0 && !loaded {
+			userTags = c.userTags(userID)
+			loaded = true
+		}
+		result[key] = flag.Evaluate(userID, userTags)
+	}
+	return result
+}
+
+// userTags 查询用户拥有的标签，未登录用户或未设置标签查询时返回nil
+func (c *Client) userTags(userID uint) []string {
+	if c.tags == nil || userID == 0 {
+		return nil
+	}
+	return c.tags(userID)
+}
+
+// List 从存储中读取所有开关，按开关标识排序
+func (c *Client) List() ([]Flag, error) {
+	flags, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Flag, 0, len(flags))
+	for _, flag := range flags {
+		list = append(list, *flag)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list, nil
+}
+
+// Get 从存储中读取指定开关
+func (c *Client) Get(key string) (*Flag, error) {
+	value, err := c.store.HGet(c.storeKey, key)
+	if err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return nil, ErrFlagNotFound
+		}
+		return nil, fmt.Errorf("读取功能开关失败: %w", err)
+	}
+
+	var flag Flag
+	if err := json.Unmarshal([]byte(value), &flag); err != nil {
+		return nil, fmt.Errorf("解析功能开关失败: %w", err)
+	}
+	return &flag, nil
+}
+
+// Save 创建或更新开关，并刷新本实例的缓存
+func (c *Client) Save(flag *Flag) error {
+	flag.UpdatedAt = time.Now()
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("序列化功能开关失败: %w", err)
+	}
+	if _, err := c.store.HSet(c.storeKey, flag.Key, string(data)); err != nil {
+		return fmt.Errorf("保存功能开关失败: %w", err)
+	}
+
+	c.invalidate()
+	return nil
+}
+
+// Delete 删除开关，删除后恢复为配置的默认值，并刷新本实例的缓存
+func (c *Client) Delete(key string) error {
+	n, err := c.store.HDel(c.storeKey, key)
+	if err != nil {
+		return fmt.Errorf("删除功能开关失败: %w", err)
+	}
+	if n == 0 {
+		return ErrFlagNotFound
+	}
+
+	c.invalidate()
+	return nil
+}
+
+// snapshot 返回本地缓存的开关，缓存过期时从存储中重新加载
+// 加载失败时继续使用旧的缓存，并等到下一个刷新间隔再重试，避免存储故障时每次判断都访问存储
+func (c *Client) snapshot() map[string]*Flag {
+	c.mu.RLock()
+	if time.Since(c.loadedAt) < c.refresh {
+		flags := c.flags
+		c.mu.RUnlock()
+		return flags
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 其他协程可能已经完成加载
+	if time.Since(c.loadedAt) < c.refresh {
+		return c.flags
+	}
+	if flags, err := c.load(); err == nil {
+		c.flags = flags
+	}
+	c.loadedAt = time.Now()
+	return c.flags
+}
+
+// invalidate 使本地缓存失效，下次读取时重新加载
+func (c *Client) invalidate() {
+	c.mu.Lock()
+	c.loadedAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// load 从存储中读取所有开关，无法解析的开关将被忽略
+func (c *Client) load() (map[string]*Flag, error) {
+	values, err := c.store.HGetAll(c.storeKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取功能开关失败: %w", err)
+	}
+
+	flags := make(map[string]*Flag, len(values))
+	for key, value := range values {
+		var flag Flag
+		if err := json.Unmarshal([]byte(value), &flag); err != nil {
+			continue
+		}
+		flags[key] = &flag
+	}
+	return flags, nil
+}
@@ -0,0 +1,59 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 高德地图逆地理编码接口
+const amapRegeoURL = "https://restapi.amap.com/v3/geocode/regeo"
+
+// AMapGeocodeProvider 高德地图逆地理编码提供商，实现了Provider接口
+type AMapGeocodeProvider struct {
+	key    string
+	client *http.Client
+}
+
+// NewAMapGeocodeProvider 创建高德地图逆地理编码提供商实例
+func NewAMapGeocodeProvider(key string, timeout time.Duration) *AMapGeocodeProvider {
+	return &AMapGeocodeProvider{
+		key:    key,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// amapResponse 高德地图逆地理编码响应
+type amapResponse struct {
+	Status    string `json:"status"`
+	Info      string `json:"info"`
+	Regeocode struct {
+		// 无结果时高德返回空数组而非字符串
+		FormattedAddress json.RawMessage `json:"formatted_address"`
+	} `json:"regeocode"`
+}
+
+// ReverseGeocode 将经纬度解析为地址，实现Provider接口
+func (p *AMapGeocodeProvider) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	query := url.Values{}
+	// 高德的坐标顺序为"经度,纬度"
+	query.Set("location", fmt.Sprintf("%f,%f", lng, lat))
+	query.Set("key", p.key)
+
+	var resp amapResponse
+	if err := getJSON(ctx, p.client, amapRegeoURL+"?"+query.Encode(), &resp); err != nil {
+		return "", err
+	}
+	if resp.Status != "1" {
+		return "", fmt.Errorf("高德地图返回错误: %s", resp.Info)
+	}
+
+	var address string
+	if err := json.Unmarshal(resp.Regeocode.FormattedAddress, &address); err != nil {
+		return "", ErrNoResult
+	}
+	return address, nil
+}
@@ -0,0 +1,120 @@
+// Package geocode 提供逆地理编码服务的统一接口和实现，将经纬度解析为可展示的地址
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/config"
+	"app/pkg/redis"
+)
+
+const (
+	// 未指定时使用的缓存键前缀，后缀为保留4位小数的纬度,经度（约11米精度）
+	defaultKeyPrefix = "geocode:"
+	// 默认缓存时间
+	defaultCacheTTL = 30 * 24 * time.Hour
+	// 默认请求超时时间
+	defaultTimeout = 3 * time.Second
+)
+
+// ErrNoResult 服务未返回可用的地址
+var ErrNoResult = errors.New("未解析到地址")
+
+// Provider 逆地理编码服务提供商接口，所有服务提供商都需要实现此接口
+type Provider interface {
+	// ReverseGeocode 将经纬度（GCJ-02坐标系）解析为地址
+	// 参数: ctx - 上下文, lat - 纬度, lng - 经度
+	// 返回: 可展示的地址和可能的错误
+	ReverseGeocode(ctx context.Context, lat, lng float64) (string, error)
+}
+
+// Client 逆地理编码客户端，在服务提供商之上增加Redis缓存
+type Client struct {
+	provider  Provider    // 逆地理编码服务提供商实现
+	store     redis.Store // 解析结果缓存
+	keyPrefix string      // 缓存键前缀
+	cacheTTL  time.Duration
+}
+
+// NewClient 创建逆地理编码客户端实例
+// 参数: provider - 服务提供商, store - 缓存存储, keyPrefix - 缓存键前缀，为空时使用默认前缀, cacheTTL - 缓存时间
+// 返回: 逆地理编码客户端指针
+func NewClient(provider Provider, store redis.Store, keyPrefix string, cacheTTL time.Duration) *Client {
+	if keyPrefix == "" {
+		keyPrefix = defaultKeyPrefix
+	}
+	return &Client{
+		provider:  provider,
+		store:     store,
+		keyPrefix: keyPrefix,
+		cacheTTL:  cacheTTL,
+	}
+}
+
+// ReverseGeocode 将经纬度解析为地址，优先读取缓存，缓存读写失败不影响解析
+func (c *Client) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	key := fmt.Sprintf("%s%.4f,%.4f", c.keyPrefix, lat, lng)
+	if address, err := c.store.Get(key); err == nil && address != "" {
+		return address, nil
+	}
+
+	address, err := c.provider.ReverseGeocode(ctx, lat, lng)
+	if err != nil {
+		return "", err
+	}
+	if address == "" {
+		return "", ErrNoResult
+	}
+
+	_ = c.store.Set(key, address, c.cacheTTL)
+	return address, nil
+}
+
+// ProviderType 逆地理编码服务提供商类型
+type ProviderType string
+
+// 支持的逆地理编码服务提供商类型
+const (
+	TencentProvider ProviderType = "tencent" // 腾讯位置服务
+	AMapProvider    ProviderType = "amap"    // 高德地图
+)
+
+// GetGeocodeClient 根据配置创建逆地理编码客户端
+// 参数: store - 缓存存储, keyPrefix - 缓存键前缀
+// 返回: 逆地理编码客户端指针和可能的错误，未配置服务密钥时返回nil客户端
+func GetGeocodeClient(store redis.Store, keyPrefix string) (*Client, error) {
+	cfg := config.GetGeocodeConfig()
+	if cfg.Key == "" {
+		return nil, nil
+	}
+
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	cacheTTL := defaultCacheTTL
+	if d, err := time.ParseDuration(cfg.CacheTTL); err == nil && d > 0 {
+		cacheTTL = d
+	}
+
+	// 默认使用腾讯位置服务
+	pType := ProviderType(cfg.Provider)
+	if pType == "" {
+		pType = TencentProvider
+	}
+
+	var provider Provider
+	switch pType {
+	case TencentProvider:
+		provider = NewTencentGeocodeProvider(cfg.Key, timeout)
+	case AMapProvider:
+		provider = NewAMapGeocodeProvider(cfg.Key, timeout)
+	default:
+		return nil, fmt.Errorf("不支持的逆地理编码服务提供商类型: %s", pType)
+	}
+
+	return NewClient(provider, store, keyPrefix, cacheTTL), nil
+}
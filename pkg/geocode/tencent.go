@@ -0,0 +1,82 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 腾讯位置服务逆地址解析接口
+const tencentGeocoderURL = "https://apis.map.qq.com/ws/geocoder/v1/"
+
+// TencentGeocodeProvider 腾讯位置服务逆地理编码提供商，实现了Provider接口
+type TencentGeocodeProvider struct {
+	key    string
+	client *http.Client
+}
+
+// NewTencentGeocodeProvider 创建腾讯位置服务逆地理编码提供商实例
+func NewTencentGeocodeProvider(key string, timeout time.Duration) *TencentGeocodeProvider {
+	return &TencentGeocodeProvider{
+		key:    key,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// tencentResponse 腾讯位置服务逆地址解析响应
+type tencentResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Result  struct {
+		Address            string `json:"address"`
+		FormattedAddresses struct {
+			Recommend string `json:"recommend"`
+		} `json:"formatted_addresses"`
+	} `json:"result"`
+}
+
+// ReverseGeocode 将经纬度解析为地址，实现Provider接口
+func (p *TencentGeocodeProvider) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	query := url.Values{}
+	query.Set("location", fmt.Sprintf("%f,%f", lat, lng))
+	query.Set("key", p.key)
+
+	var resp tencentResponse
+	if err := getJSON(ctx, p.client, tencentGeocoderURL+"?"+query.Encode(), &resp); err != nil {
+		return "", err
+	}
+	if resp.Status != 0 {
+		return "", fmt.Errorf("腾讯位置服务返回错误: %d %s", resp.Status, resp.Message)
+	}
+
+	// 优先使用推荐的地标描述，如"海淀区中关村大街"
+	if resp.Result.FormattedAddresses.Recommend != "" {
+		return resp.Result.FormattedAddresses.Recommend, nil
+	}
+	return resp.Result.Address, nil
+}
+
+// getJSON 发送GET请求并解析JSON响应
+func getJSON(ctx context.Context, client *http.Client, rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求逆地理编码服务失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("逆地理编码服务返回状态码: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析逆地理编码响应失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package geohash 提供经纬度的geohash编码
+// geohash将经纬度交替二分编码为Base32字符串，前缀相同的位置位于同一矩形格内，编码越长格子越小
+// 可按前缀匹配查询范围内的位置，或按前缀分组聚合附近的位置
+package geohash
+
+import "strings"
+
+// MaxPrecision 支持的最大编码长度，12位时格子约为3.7厘米
+const MaxPrecision = 12
+
+// base32 geohash使用的Base32字母表，不含a、i、l、o
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Box 经纬度矩形范围
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// Center 返回矩形的中心点
+func (b Box) Center() (lat, lng float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLng + b.MaxLng) / 2
+}
+
+// Encode 将经纬度编码为指定长度的geohash，长度超出范围时按1或MaxPrecision处理
+func Encode(lat, lng float64, precision int) string {
+	precision = clampPrecision(precision)
+	box := Box{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	even := true // 偶数位编码经度，奇数位编码纬度
+	bit, ch := 0, 0
+	for sb.Len() < precision {
+		if even {
+			mid := (box.MinLng + box.MaxLng) / 2
+			if lng >= mid {
+				ch |= 1 << (4 - bit)
+				box.MinLng = mid
+			} else {
+				box.MaxLng = mid
+			}
+		} else {
+			mid := (box.MinLat + box.MaxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				box.MinLat = mid
+			} else {
+				box.MaxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+			continue
+		}
+		sb.WriteByte(base32[ch])
+		bit, ch = 0, 0
+	}
+	return sb.String()
+}
+
+// Decode 返回geohash对应的矩形范围，含有非法字符时忽略该字符之后的部分
+func Decode(hash string) Box {
+	box := Box{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		v := strings.IndexByte(base32, hash[i])
+		if v < 0 {
+			break
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := v&(1<<bit) != 0
+			if even {
+				mid := (box.MinLng + box.MaxLng) / 2
+				if set {
+					box.MinLng = mid
+				} else {
+					box.MaxLng = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if set {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box
+}
+
+// CellSize 返回指定长度的geohash格子的纬度和经度跨度（度）
+func CellSize(precision int) (latDeg, lngDeg float64) {
+	precision = clampPrecision(precision)
+	bits := precision * 5
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / float64(uint64(1)<<latBits), 360 / float64(uint64(1)<<lngBits)
+}
+
+// Cover 返回覆盖矩形范围的所有指定长度的geohash，查询范围内的位置时按这些前缀匹配
+// 范围较大而长度较长时结果数量很多，调用方应先按范围选择合适的长度
+func Cover(box Box, precision int) []string {
+	precision = clampPrecision(precision)
+	latStep, lngStep := CellSize(precision)
+	box = clampBox(box)
+
+	// 从左下角所在格子的中心开始逐格移动，每个格子编码一次
+	origin := Decode(Encode(box.MinLat, box.MinLng, precision))
+	var hashes []string
+	for lat := (origin.MinLat + origin.MaxLat) / 2; lat-latStep/2 <= box.MaxLat && lat < 90; lat += latStep {
+		for lng := (origin.MinLng + origin.MaxLng) / 2; lng-lngStep/2 <= box.MaxLng && lng < 180; lng += lngStep {
+			hashes = append(hashes, Encode(lat, lng, precision))
+		}
+	}
+	return hashes
+}
+
+// clampPrecision 将编码长度限制在1到MaxPrecision之间
+func clampPrecision(precision int) int {
+	return min(max(precision, 1), MaxPrecision)
+}
+
+// clampBox 将矩形范围限制在合法的经纬度内
+func clampBox(box Box) Box {
+	box.MinLat = max(box.MinLat, -90)
+	box.MaxLat = min(box.MaxLat, 90)
+	box.MinLng = max(box.MinLng, -180)
+	box.MaxLng = min(box.MaxLng, 180)
+	return box
+}
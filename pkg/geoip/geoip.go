@@ -0,0 +1,186 @@
+// Package geoip 提供IP归属地解析的统一接口和实现，将客户端IP解析为国家或地区和省份
+// 优先查询本地MaxMind DB格式的数据库，未命中时可回退到在线服务并缓存结果
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"app/config"
+	"app/pkg/redis"
+)
+
+const (
+	// 未指定时使用的缓存键前缀，后缀为IP地址
+	defaultKeyPrefix = "geoip:"
+	// 默认缓存时间
+	defaultCacheTTL = 7 * 24 * time.Hour
+	// 默认请求超时时间，解析在请求处理链路上进行，超时时间应较短
+	defaultTimeout = 500 * time.Millisecond
+)
+
+// ContextKey 请求上下文中保存客户端IP归属地的键
+const ContextKey = "geoip"
+
+// ErrNotFound 未解析到IP归属地，内网地址、保留地址和数据库中不存在的地址返回此错误
+var ErrNotFound = errors.New("未解析到IP归属地")
+
+// Location IP归属地
+type Location struct {
+	CountryCode string `json:"country_code"` // ISO 3166-1两位国家或地区代码，如CN，在线服务只能识别中国时其他国家为空
+	Country     string `json:"country"`      // 国家或地区名称
+	Region      string `json:"region"`       // 省份或州名称，部分IP只能解析到国家
+}
+
+// String 返回用于日志的归属地描述，如"CN/广东省"
+func (l *Location) String() string {
+	country := l.CountryCode
+	if country == "" {
+		country = l.Country
+	}
+	if l.Region == "" {
+		return country
+	}
+	return country + "/" + l.Region
+}
+
+// NewContext 返回携带IP归属地的上下文
+func NewContext(ctx context.Context, location *Location) context.Context {
+	return context.WithValue(ctx, ContextKey, location)
+}
+
+// FromContext 获取上下文中的IP归属地，未解析时返回nil
+// 兼容gin.Context（通过c.Set保存）和NewContext返回的上下文
+func FromContext(ctx context.Context) *Location {
+	if ctx == nil {
+		return nil
+	}
+	location, _ := ctx.Value(ContextKey).(*Location)
+	return location
+}
+
+// Provider IP归属地提供商接口，所有提供商都需要实现此接口
+type Provider interface {
+	// Lookup 解析IP归属地
+	// 参数: ctx - 上下文, ip - 公网IP地址
+	// 返回: IP归属地和可能的错误，未找到时返回ErrNotFound
+	Lookup(ctx context.Context, ip netip.Addr) (*Location, error)
+}
+
+// Client IP归属地客户端，优先查询本地数据库，未命中时查询在线服务并缓存结果
+type Client struct {
+	local     Provider    // 本地数据库，未配置时为nil
+	remote    Provider    // 在线服务，未配置时为nil
+	store     redis.Store // 在线服务解析结果缓存
+	keyPrefix string      // 缓存键前缀
+	cacheTTL  time.Duration
+}
+
+// NewClient 创建IP归属地客户端实例
+// 参数: local - 本地数据库提供商, remote - 在线服务提供商, store - 缓存存储, keyPrefix - 缓存键前缀，为空时使用默认前缀, cacheTTL - 缓存时间
+// 返回: IP归属地客户端指针，local和remote可以有一个为nil
+func NewClient(local, remote Provider, store redis.Store, keyPrefix string, cacheTTL time.Duration) *Client {
+	if keyPrefix == "" {
+		keyPrefix = defaultKeyPrefix
+	}
+	return &Client{
+		local:     local,
+		remote:    remote,
+		store:     store,
+		keyPrefix: keyPrefix,
+		cacheTTL:  cacheTTL,
+	}
+}
+
+// Lookup 解析IP归属地，不是公网地址时直接返回ErrNotFound
+func (c *Client) Lookup(ctx context.Context, ip string) (*Location, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return nil, ErrNotFound
+	}
+
+	var localErr error
+	if c.local != nil {
+		location, err := c.local.Lookup(ctx, addr)
+		if err == nil {
+			return location, nil
+		}
+		localErr = err
+	}
+	if c.remote == nil {
+		if localErr == nil {
+			localErr = ErrNotFound
+		}
+		return nil, localErr
+	}
+
+	// 在线服务的结果按IP缓存，缓存读写失败不影响解析
+	key := c.keyPrefix + addr.String()
+	var cached Location
+	if err := c.store.GetObj(key, &cached); err == nil {
+		return &cached, nil
+	}
+
+	location, err := c.remote.Lookup(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.store.SetObj(key, location, c.cacheTTL)
+	return location, nil
+}
+
+// ProviderType 在线IP归属地服务提供商类型
+type ProviderType string
+
+// 支持的在线IP归属地服务提供商类型
+const (
+	TencentProvider ProviderType = "tencent" // 腾讯位置服务
+)
+
+// GetGeoIPClient 根据配置创建IP归属地客户端
+// 参数: store - 缓存存储, keyPrefix - 缓存键前缀
+// 返回: IP归属地客户端指针和可能的错误，未配置本地数据库和在线服务时返回nil客户端
+func GetGeoIPClient(store redis.Store, keyPrefix string) (*Client, error) {
+	cfg := config.GetGeoIPConfig()
+
+	var local Provider
+	if cfg.MMDBPath != "" {
+		db, err := OpenMMDB(cfg.MMDBPath)
+		if err != nil {
+			return nil, err
+		}
+		local = db
+	}
+
+	var remote Provider
+	if cfg.Provider != "" && cfg.Key != "" {
+		timeout := defaultTimeout
+		if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+			timeout = d
+		}
+
+		switch ProviderType(cfg.Provider) {
+		case TencentProvider:
+			remote = NewTencentGeoIPProvider(cfg.Key, timeout)
+		default:
+			return nil, fmt.Errorf("不支持的IP归属地服务提供商类型: %s", cfg.Provider)
+		}
+	}
+
+	if local == nil && remote == nil {
+		return nil, nil
+	}
+
+	cacheTTL := defaultCacheTTL
+	if d, err := time.ParseDuration(cfg.CacheTTL); err == nil && d > 0 {
+		cacheTTL = d
+	}
+	return NewClient(local, remote, store, keyPrefix, cacheTTL), nil
+}
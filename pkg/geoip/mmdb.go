@@ -0,0 +1,379 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+)
+
+// MaxMind DB格式说明见 https://maxmind.github.io/MaxMind-DB/
+// 文件由搜索树、16字节的分隔区、数据区和元数据组成，元数据以固定标记开头
+// 搜索树按IP地址的二进制位逐位查找，叶子记录指向数据区中的归属地记录
+
+// mmdbMetadataMarker 元数据开始标记
+var mmdbMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// 数据区的数据类型
+const (
+	mmdbExtended = 0
+	mmdbPointer  = 1
+	mmdbString   = 2
+	mmdbDouble   = 3
+	mmdbBytes    = 4
+	mmdbUint16   = 5
+	mmdbUint32   = 6
+	mmdbMap      = 7
+	mmdbInt32    = 8
+	mmdbUint64   = 9
+	mmdbUint128  = 10
+	mmdbArray    = 11
+	mmdbBool     = 14
+	mmdbFloat    = 15
+)
+
+const (
+	// mmdbDataSectionSeparator 搜索树和数据区之间分隔区的字节数
+	mmdbDataSectionSeparator = 16
+	// mmdbMaxDepth 解码嵌套结构的最大深度，防止损坏的文件导致无限递归
+	mmdbMaxDepth = 32
+)
+
+// 记录中名称的语言，优先使用简体中文
+var mmdbNameLanguages = []string{"zh-CN", "en"}
+
+// errInvalidMMDB 数据库文件格式错误
+var errInvalidMMDB = errors.New("IP归属地数据库格式错误")
+
+// MMDBProvider 本地MaxMind DB格式数据库（如GeoLite2-City、GeoLite2-Country）的IP归属地提供商，实现了Provider接口
+// 数据库文件在打开时整体读入内存，查询不访问磁盘，更新数据库文件后需重启服务
+type MMDBProvider struct {
+	tree       []byte      // 搜索树
+	data       mmdbDecoder // 数据区
+	nodeCount  uint        // 搜索树节点数
+	recordSize uint        // 每条记录的位数：24、28或32
+	ipVersion  uint        // 数据库的IP版本：4或6
+	ipv4Start  uint        // IPv6数据库中IPv4地址（::/96）所在子树的根节点
+}
+
+// OpenMMDB 读取并解析MaxMind DB格式的数据库文件
+func OpenMMDB(path string) (*MMDBProvider, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取IP归属地数据库失败: %w", err)
+	}
+
+	markerIndex := bytes.LastIndex(file, mmdbMetadataMarker)
+	if markerIndex < 0 {
+		return nil, errInvalidMMDB
+	}
+	meta, _, err := mmdbDecoder{buf: file[markerIndex+len(mmdbMetadataMarker):]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析IP归属地数据库元数据失败: %w", err)
+	}
+	metadata, ok := meta.(map[string]interface{})
+	if !ok {
+		return nil, errInvalidMMDB
+	}
+
+	p := &MMDBProvider{
+		nodeCount:  mmdbUint(metadata["node_count"]),
+		recordSize: mmdbUint(metadata["record_size"]),
+		ipVersion:  mmdbUint(metadata["ip_version"]),
+	}
+	if p.recordSize != 24 && p.recordSize != 28 && p.recordSize != 32 {
+		return nil, fmt.Errorf("不支持的IP归属地数据库记录大小: %d", p.recordSize)
+	}
+	if p.ipVersion != 4 && p.ipVersion != 6 {
+		return nil, fmt.Errorf("不支持的IP归属地数据库IP版本: %d", p.ipVersion)
+	}
+
+	// 每个节点包含左右两条记录
+	treeSize := p.nodeCount * p.recordSize / 4
+	dataStart := treeSize + mmdbDataSectionSeparator
+	if dataStart > uint(markerIndex) {
+		return nil, errInvalidMMDB
+	}
+	p.tree = file[:treeSize]
+	p.data = mmdbDecoder{buf: file[dataStart:markerIndex]}
+
+	// IPv4地址在IPv6数据库中位于::/96，沿左子树走96步即为IPv4子树的根节点
+	if p.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < p.nodeCount; i++ {
+			node = p.readNode(node, 0)
+		}
+		p.ipv4Start = node
+	}
+	return p, nil
+}
+
+// Lookup 解析IP归属地，实现Provider接口
+func (p *MMDBProvider) Lookup(ctx context.Context, ip netip.Addr) (*Location, error) {
+	var addr []byte
+	node := uint(0)
+	if ip.Is4() {
+		a := ip.As4()
+		addr = a[:]
+		node = p.ipv4Start
+	} else {
+		if p.ipVersion == 4 {
+			return nil, ErrNotFound
+		}
+		a := ip.As16()
+		addr = a[:]
+	}
+
+	for i := 0; i < len(addr)*8 && node < p.nodeCount; i++ {
+		bit := uint(addr[i>>3]>>(7-uint(i&7))) & 1
+		node = p.readNode(node, bit)
+	}
+	if node == p.nodeCount {
+		return nil, ErrNotFound
+	}
+	if node < p.nodeCount {
+		return nil, errInvalidMMDB
+	}
+
+	// 叶子记录减去节点数和分隔区大小即为数据区中的偏移
+	record, _, err := p.data.decode(node-p.nodeCount-mmdbDataSectionSeparator, 0)
+	if err != nil {
+		return nil, err
+	}
+	return mmdbLocation(record)
+}
+
+// readNode 读取节点的左（bit为0）或右（bit为1）记录
+func (p *MMDBProvider) readNode(node, bit uint) uint {
+	offset := node * p.recordSize / 4
+	if offset+p.recordSize/4 > uint(len(p.tree)) {
+		// 损坏的文件按未找到处理
+		return p.nodeCount
+	}
+	b := p.tree[offset:]
+
+	switch p.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		// 中间字节的高4位属于左记录，低4位属于右记录
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// mmdbLocation 从GeoIP2、GeoLite2格式的记录中读取国家和省份
+// 没有所在国家时使用注册国家，如卫星和匿名代理地址
+func mmdbLocation(record interface{}) (*Location, error) {
+	fields, _ := record.(map[string]interface{})
+	country, _ := fields["country"].(map[string]interface{})
+	if country == nil {
+		country, _ = fields["registered_country"].(map[string]interface{})
+	}
+	if country == nil {
+		return nil, ErrNotFound
+	}
+
+	location := &Location{
+		CountryCode: mmdbStringValue(country["iso_code"]),
+		Country:     mmdbName(country),
+	}
+	if subdivisions, ok := fields["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		if region, ok := subdivisions[0].(map[string]interface{}); ok {
+			location.Region = mmdbName(region)
+		}
+	}
+	return location, nil
+}
+
+// mmdbName 按语言优先级读取记录中的名称
+func mmdbName(record map[string]interface{}) string {
+	names, _ := record["names"].(map[string]interface{})
+	for _, lang := range mmdbNameLanguages {
+		if name := mmdbStringValue(names[lang]); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// mmdbStringValue 将解码后的值转换为字符串，类型不符时返回空字符串
+func mmdbStringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// mmdbUint 将解码后的无符号整数转换为uint，类型不符时返回0
+func mmdbUint(v interface{}) uint {
+	n, _ := v.(uint64)
+	return uint(n)
+}
+
+// mmdbDecoder 数据区解码器，指针的偏移相对于buf的起始位置
+type mmdbDecoder struct {
+	buf []byte
+}
+
+// decode 解码offset处的值，返回值和下一个值的偏移
+// 字符串解码为string，整数解码为uint64或int64，浮点数解码为float64，映射和数组解码为map[string]interface{}和[]interface{}
+func (d mmdbDecoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > mmdbMaxDepth || offset >= uint(len(d.buf)) {
+		return nil, 0, errInvalidMMDB
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	typ := ctrl >> 5
+	if typ == mmdbPointer {
+		pointer, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	if typ == mmdbExtended {
+		if offset >= uint(len(d.buf)) {
+			return nil, 0, errInvalidMMDB
+		}
+		typ = 7 + d.buf[offset]
+		offset++
+	}
+
+	size, offset, err := d.size(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch typ {
+	case mmdbMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, errInvalidMMDB
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+			offset = next
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+			offset = next
+		}
+		return a, offset, nil
+	case mmdbBool:
+		// 布尔值没有数据部分，大小即为值
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, errInvalidMMDB
+	}
+	b := d.buf[offset : offset+size]
+	next := offset + size
+
+	switch typ {
+	case mmdbString:
+		return string(b), next, nil
+	case mmdbBytes:
+		return append([]byte(nil), b...), next, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, errInvalidMMDB
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, errInvalidMMDB
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64, mmdbUint128:
+		// 归属地记录中不使用128位整数，超出64位时只保留低64位
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, next, nil
+	case mmdbInt32:
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int64(int32(n)), next, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: 未知的数据类型%d", errInvalidMMDB, typ)
+	}
+}
+
+// size 读取值的大小，小于29时直接保存在控制字节中，否则由后续1到3个字节表示
+func (d mmdbDecoder) size(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+
+	n := size - 28
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, errInvalidMMDB
+	}
+	var v uint
+	for _, c := range d.buf[offset : offset+n] {
+		v = v<<8 | uint(c)
+	}
+	switch size {
+	case 29:
+		v += 29
+	case 30:
+		v += 285
+	default:
+		v += 65821
+	}
+	return v, offset + n, nil
+}
+
+// pointer 读取指针指向的偏移，指针的长度和基数由控制字节的第4、5位决定
+func (d mmdbDecoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	n := uint(ctrl>>3&0x3) + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, errInvalidMMDB
+	}
+	b := d.buf[offset : offset+n]
+	prefix := uint(ctrl & 0x7)
+
+	var pointer uint
+	switch n {
+	case 1:
+		pointer = prefix<<8 | uint(b[0])
+	case 2:
+		pointer = (prefix<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		pointer = (prefix<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+	return pointer, offset + n, nil
+}
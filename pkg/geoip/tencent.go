@@ -0,0 +1,89 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+const (
+	// 腾讯位置服务IP定位接口
+	tencentIPLocationURL = "https://apis.map.qq.com/ws/location/v1/ip"
+	// 腾讯位置服务中国的国家代码（ISO 3166-1数字代码）
+	tencentNationCodeChina = 156
+	// 腾讯位置服务IP不存在或无法定位的状态码
+	tencentStatusNoResult = 375
+)
+
+// TencentGeoIPProvider 腾讯位置服务IP归属地提供商，实现了Provider接口
+type TencentGeoIPProvider struct {
+	key    string
+	client *http.Client
+}
+
+// NewTencentGeoIPProvider 创建腾讯位置服务IP归属地提供商实例
+func NewTencentGeoIPProvider(key string, timeout time.Duration) *TencentGeoIPProvider {
+	return &TencentGeoIPProvider{
+		key:    key,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// tencentResponse 腾讯位置服务IP定位响应
+type tencentResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Result  struct {
+		AdInfo struct {
+			Nation     string `json:"nation"`
+			NationCode int    `json:"nation_code"`
+			Province   string `json:"province"`
+		} `json:"ad_info"`
+	} `json:"result"`
+}
+
+// Lookup 解析IP归属地，实现Provider接口
+// 腾讯位置服务只返回国家名称和数字代码，目前只将中国转换为两位国家代码
+func (p *TencentGeoIPProvider) Lookup(ctx context.Context, ip netip.Addr) (*Location, error) {
+	query := url.Values{}
+	query.Set("ip", ip.String())
+	query.Set("key", p.key)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tencentIPLocationURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求IP归属地服务失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("IP归属地服务返回状态码: %d", resp.StatusCode)
+	}
+	var result tencentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析IP归属地响应失败: %v", err)
+	}
+	if result.Status == tencentStatusNoResult {
+		return nil, ErrNotFound
+	}
+	if result.Status != 0 {
+		return nil, fmt.Errorf("腾讯位置服务返回错误: %d %s", result.Status, result.Message)
+	}
+
+	info := result.Result.AdInfo
+	if info.Nation == "" {
+		return nil, ErrNotFound
+	}
+	location := &Location{Country: info.Nation, Region: info.Province}
+	if info.NationCode == tencentNationCodeChina {
+		location.CountryCode = "CN"
+	}
+	return location, nil
+}
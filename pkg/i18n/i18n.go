@@ -0,0 +1,144 @@
+// Package i18n 提供响应消息的多语言翻译功能
+// 代码中的中文消息即为翻译键，简体中文无需翻译，其他语言从 locales 目录下的消息目录中查找
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN" // 简体中文，消息原文
+	EnUS = "en-US" // 美式英语
+)
+
+// DefaultLanguage 默认语言，请求未指定或指定了不支持的语言时使用
+const DefaultLanguage = ZhCN
+
+// ContextKey 请求上下文中保存当前语言的键
+const ContextKey = "lang"
+
+// errorSeparator 包装错误时各层信息之间的分隔符，与 fmt.Errorf("xxx: %w") 的格式一致
+const errorSeparator = ": "
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	catalogs map[string]map[string]string
+	once     sync.Once
+)
+
+// loadCatalogs 加载所有语言的消息目录，文件名即语言标识
+func loadCatalogs() {
+	catalogs = make(map[string]map[string]string)
+
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("读取消息目录失败: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := localeFS.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("读取消息目录失败: %v", err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("解析消息目录 %s 失败: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+}
+
+// T 将消息翻译为指定语言，未找到翻译时返回原文
+func T(lang, message string) string {
+	if lang == DefaultLanguage || message == "" {
+		return message
+	}
+
+	once.Do(loadCatalogs)
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// TranslateError 翻译错误信息，包装错误按分隔符逐段翻译，无法翻译的片段（如底层驱动错误）保留原文
+func TranslateError(lang, text string) string {
+	if lang == DefaultLanguage || text == "" {
+		return text
+	}
+
+	parts := strings.Split(text, errorSeparator)
+	for i, part := range parts {
+		parts[i] = T(lang, part)
+	}
+	return strings.Join(parts, errorSeparator)
+}
+
+// Match 根据Accept-Language请求头选择最匹配的支持语言
+// 按权重从高到低依次匹配完整语言标签和主语言（如en匹配en-US），均不匹配时返回默认语言
+func Match(acceptLanguage string) string {
+	type candidate struct {
+		tag    string
+		weight float64
+	}
+
+	var candidates []candidate
+	for _, item := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		if tag == "" {
+			continue
+		}
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = v
+			}
+		}
+		if weight > 0 {
+			candidates = append(candidates, candidate{tag: tag, weight: weight})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].weight > candidates[j].weight
+	})
+
+	for _, c := range candidates {
+		if lang := normalize(c.tag); lang != "" {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// normalize 将语言标签映射为支持的语言，不支持时返回空字符串
+func normalize(tag string) string {
+	primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	switch primary {
+	case "zh":
+		return ZhCN
+	case "en":
+		return EnUS
+	default:
+		return ""
+	}
+}
+
+// chineseCountries 默认使用中文的国家或地区，ISO 3166-1两位代码
+var chineseCountries = map[string]bool{"CN": true, "HK": true, "MO": true, "TW": true, "SG": true}
+
+// ForCountry 根据国家或地区代码选择默认语言，用于请求未携带Accept-Language时按IP归属地选择
+// 使用中文的国家或地区及未知代码返回默认语言，其他国家或地区返回英语
+func ForCountry(countryCode string) string {
+	if countryCode == "" || chineseCountries[strings.ToUpper(countryCode)] {
+		return DefaultLanguage
+	}
+	return EnUS
+}
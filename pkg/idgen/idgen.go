@@ -0,0 +1,130 @@
+// Package idgen 生成对外公开的ID
+// 公开ID采用ULID格式：48位毫秒时间戳加80位随机数，编码为26位Crockford Base32字符串
+// 按生成时间有序且不暴露数据量，数据库内部仍使用自增ID关联
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Length 公开ID的长度
+const Length = 26
+
+// encoding Crockford Base32字母表，不含I、L、O、U
+const encoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ErrInvalid 公开ID格式错误
+var ErrInvalid = errors.New("ID格式错误")
+
+// decoding 字符到5位取值的映射，不在字母表中的字符为0xFF，小写字母与大写等价
+var decoding = func() [256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = 0xFF
+	}
+	for i := 0; i < len(encoding); i++ {
+		table[encoding[i]] = byte(i)
+		if c := encoding[i]; c >= 'A' && c <= 'Z' {
+			table[c+'a'-'A'] = byte(i)
+		}
+	}
+	return table
+}()
+
+// generator 单调递增的ULID生成器，同一毫秒内生成的ID在上一个ID的随机部分上加一，保证有序
+type generator struct {
+	mu       sync.Mutex
+	lastTime uint64
+	lastHigh uint16 // 随机部分的高16位
+	lastLow  uint64 // 随机部分的低64位
+}
+
+var defaultGenerator = &generator{}
+
+// New 生成公开ID
+func New() string {
+	return defaultGenerator.next(time.Now())
+}
+
+// next 生成指定时间的ULID
+func (g *generator) next(now time.Time) string {
+	ms := uint64(now.UnixMilli())
+
+	g.mu.Lock()
+	if ms <= g.lastTime {
+		// 同一毫秒内或时钟回拨时沿用上一个时间戳，随机部分加一
+		ms = g.lastTime
+		g.lastLow++
+		if g.lastLow == 0 {
+			g.lastHigh++
+		}
+	} else {
+		var entropy [10]byte
+		if _, err := rand.Read(entropy[:]); err != nil {
+			panic("idgen: 读取随机数失败: " + err.Error())
+		}
+		g.lastTime = ms
+		g.lastHigh = binary.BigEndian.Uint16(entropy[:2])
+		g.lastLow = binary.BigEndian.Uint64(entropy[2:])
+	}
+	high, low := g.lastHigh, g.lastLow
+	g.mu.Unlock()
+
+	var raw [16]byte
+	raw[0] = byte(ms >> 40)
+	raw[1] = byte(ms >> 32)
+	raw[2] = byte(ms >> 24)
+	raw[3] = byte(ms >> 16)
+	raw[4] = byte(ms >> 8)
+	raw[5] = byte(ms)
+	binary.BigEndian.PutUint16(raw[6:8], high)
+	binary.BigEndian.PutUint64(raw[8:], low)
+	return encode(raw)
+}
+
+// encode 将128位数据编码为26位Base32字符串，首字符只使用3位
+func encode(raw [16]byte) string {
+	hi := binary.BigEndian.Uint64(raw[:8])
+	lo := binary.BigEndian.Uint64(raw[8:])
+
+	var out [Length]byte
+	for i := Length - 1; i >= 0; i-- {
+		out[i] = encoding[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// Valid 判断字符串是否为格式正确的公开ID，不区分大小写
+func Valid(s string) bool {
+	if len(s) != Length {
+		return false
+	}
+	// 首字符超过7时超出128位
+	if v := decoding[s[0]]; v > 7 {
+		return false
+	}
+	for i := 1; i < Length; i++ {
+		if decoding[s[i]] == 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize 校验公开ID并转换为大写的规范形式
+func Normalize(s string) (string, error) {
+	if !Valid(s) {
+		return "", ErrInvalid
+	}
+	out := []byte(s)
+	for i, c := range out {
+		out[i] = encoding[decoding[c]]
+	}
+	return string(out), nil
+}
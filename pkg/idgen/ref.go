@@ -0,0 +1,61 @@
+package idgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Ref 客户端传入的ID引用
+// 切换到公开ID的过渡期内同时接受公开ID和内部自增ID，JSON中可以是字符串或数字
+type Ref string
+
+// UnmarshalJSON 解析JSON中的字符串或数字
+func (r *Ref) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*r = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*r = Ref(s)
+		return nil
+	}
+	if _, err := strconv.ParseUint(string(data), 10, 32); err != nil {
+		return ErrInvalid
+	}
+	*r = Ref(data)
+	return nil
+}
+
+// String 返回客户端传入的原始值
+func (r Ref) String() string {
+	return string(r)
+}
+
+// IsZero 判断是否未传入
+func (r Ref) IsZero() bool {
+	return r == ""
+}
+
+// Internal 按内部自增ID解析，不是十进制数字时返回false
+func (r Ref) Internal() (uint, bool) {
+	id, err := strconv.ParseUint(string(r), 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// Public 按公开ID解析并返回规范形式，格式错误时返回false
+func (r Ref) Public() (string, bool) {
+	id, err := Normalize(string(r))
+	if err != nil {
+		return "", false
+	}
+	return id, true
+}
@@ -29,13 +29,14 @@ const (
 
 // CustomClaims 自定义JWT声明结构体
 type CustomClaims struct {
-	UserID               uint   `json:"user_id"`  // 用户ID
-	Username             string `json:"username"` // 用户名
+	UserID               uint   `json:"user_id"`       // 用户ID
+	Username             string `json:"username"`      // 用户名
+	TokenVersion         uint   `json:"token_version"` // 签发时用户的令牌版本号，用户退出所有设备后版本号递增，旧令牌失效
 	jwt.RegisteredClaims        // 标准JWT声明
 }
 
-// GenerateToken 生成包含用户信息的JWT令牌
-func GenerateToken(userID uint, username string, _ string) (string, error) {
+// GenerateToken 生成包含用户信息的JWT令牌，version为用户当前的令牌版本号
+func GenerateToken(userID uint, username string, version uint) (string, error) {
 	jwtConfig := config.GetJWTConfig()
 
 	expDuration, err := time.ParseDuration(jwtConfig.ExpiresTime)
@@ -45,8 +46,9 @@ func GenerateToken(userID uint, username string, _ string) (string, error) {
 
 	now := time.Now()
 	claims := CustomClaims{
-		UserID:   userID,
-		Username: username,
+		UserID:       userID,
+		Username:     username,
+		TokenVersion: version,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(expDuration)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -56,8 +58,18 @@ func GenerateToken(userID uint, username string, _ string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtConfig.SecretKey))
+	ring, err := getKeyRing()
+	if err != nil {
+		return "", fmt.Errorf("加载签名密钥失败: %w", err)
+	}
+	key := ring.current()
+
+	// 使用轮换密钥签发的令牌在头部写入kid，校验时据此选择密钥
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.ID != LegacyKeyID {
+		token.Header["kid"] = key.ID
+	}
+	tokenString, err := token.SignedString(key.signKey)
 	if err != nil {
 		return "", fmt.Errorf("签名令牌失败: %w", err)
 	}
@@ -66,18 +78,17 @@ func GenerateToken(userID uint, username string, _ string) (string, error) {
 }
 
 // ParseToken 解析JWT令牌并提取其中的声明信息
+// 根据令牌头部的kid选择校验密钥，轮换后旧密钥签发的令牌在过期前仍然有效
 func ParseToken(tokenString string) (*CustomClaims, error) {
 	if tokenString == "" {
 		return nil, ErrTokenNotProvided
 	}
 
-	jwtConfig := config.GetJWTConfig()
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
-		}
-		return []byte(jwtConfig.SecretKey), nil
-	})
+	ring, err := getKeyRing()
+	if err != nil {
+		return nil, fmt.Errorf("加载签名密钥失败: %w", err)
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, ring.lookup)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -119,7 +130,7 @@ func RefreshToken(tokenString string) (string, error) {
 		}
 	}
 
-	return GenerateToken(claims.UserID, claims.Username, "")
+	return GenerateToken(claims.UserID, claims.Username, claims.TokenVersion)
 }
 
 // parseTokenWithoutValidation 解析JWT令牌但不验证过期时间
@@ -128,13 +139,11 @@ func parseTokenWithoutValidation(tokenString string) (*CustomClaims, error) {
 		return nil, ErrTokenNotProvided
 	}
 
-	jwtConfig := config.GetJWTConfig()
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
-		}
-		return []byte(jwtConfig.SecretKey), nil
-	}, jwt.WithoutClaimsValidation())
+	ring, err := getKeyRing()
+	if err != nil {
+		return nil, fmt.Errorf("加载签名密钥失败: %w", err)
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, ring.lookup, jwt.WithoutClaimsValidation())
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenMalformed) {
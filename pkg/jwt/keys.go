@@ -0,0 +1,266 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"app/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 签名算法
+const (
+	AlgorithmHS256 = "HS256" // HMAC-SHA256对称签名
+	AlgorithmRS256 = "RS256" // RSA-SHA256非对称签名
+)
+
+// LegacyKeyID secret_key对应的密钥ID，该密钥签发的令牌头部不含kid，头部不含kid的令牌也使用该密钥校验
+const LegacyKeyID = "legacy"
+
+// 密钥相关错误
+var (
+	ErrKeyNotFound   = errors.New("签名密钥不存在")
+	ErrKeyCannotSign = errors.New("密钥未配置私钥，不能用于签发令牌")
+	ErrNoSigningKey  = errors.New("未配置JWT签名密钥")
+	ErrNoRotationKey = errors.New("没有可轮换的其他密钥")
+)
+
+// Key JWT签名密钥
+type Key struct {
+	ID        string
+	Algorithm string
+	method    jwt.SigningMethod
+	signKey   interface{} // 签名密钥，HS256为[]byte，RS256为*rsa.PrivateKey，为nil时只能校验
+	verifyKey interface{} // 校验密钥，HS256为[]byte，RS256为*rsa.PublicKey
+}
+
+// CanSign 判断密钥是否可以签发令牌
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeyInfo 密钥信息，不含密钥内容
+type KeyInfo struct {
+	ID        string // 密钥ID
+	Algorithm string // 签名算法
+	CanSign   bool   // 是否可以签发令牌
+	Current   bool   // 是否为当前签发令牌使用的密钥
+}
+
+// keyRing 所有可用于校验令牌的密钥
+type keyRing struct {
+	keys      map[string]*Key
+	order     []string // 密钥的配置顺序，默认轮换时按此顺序选择下一个密钥
+	defaultID string   // 未轮换时签发令牌使用的密钥ID
+}
+
+var (
+	ring     *keyRing
+	ringErr  error
+	ringOnce sync.Once
+
+	// keySelector 读取当前签发令牌使用的密钥ID，用于多实例间同步轮换结果
+	keySelector func() string
+)
+
+// SetKeySelector 设置当前密钥ID的读取函数，需在处理请求前调用
+// 读取函数返回空字符串或不可签名的密钥ID时使用配置的默认密钥
+func SetKeySelector(selector func() string) {
+	keySelector = selector
+}
+
+// getKeyRing 加载配置中的所有密钥，配置在运行期间不会变化，只加载一次
+func getKeyRing() (*keyRing, error) {
+	ringOnce.Do(func() {
+		ring, ringErr = loadKeyRing(config.GetJWTConfig())
+	})
+	return ring, ringErr
+}
+
+// loadKeyRing 根据配置创建密钥集合
+func loadKeyRing(cfg config.JWTConfig) (*keyRing, error) {
+	r := &keyRing{keys: make(map[string]*Key)}
+
+	if cfg.SecretKey != "" {
+		r.add(&Key{
+			ID:        LegacyKeyID,
+			Algorithm: AlgorithmHS256,
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(cfg.SecretKey),
+			verifyKey: []byte(cfg.SecretKey),
+		})
+	}
+
+	for _, kc := range cfg.Keys {
+		if kc.ID == "" || kc.ID == LegacyKeyID {
+			return nil, fmt.Errorf("无效的JWT密钥ID: %q", kc.ID)
+		}
+		if _, exists := r.keys[kc.ID]; exists {
+			return nil, fmt.Errorf("重复的JWT密钥ID: %s", kc.ID)
+		}
+		key, err := loadKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("加载JWT密钥 %s 失败: %w", kc.ID, err)
+		}
+		r.add(key)
+	}
+
+	r.defaultID = cfg.CurrentKeyID
+	if r.defaultID == "" {
+		r.defaultID = LegacyKeyID
+	}
+	key, ok := r.keys[r.defaultID]
+	if !ok {
+		return nil, ErrNoSigningKey
+	}
+	if !key.CanSign() {
+		return nil, ErrKeyCannotSign
+	}
+
+	return r, nil
+}
+
+// loadKey 根据单个密钥配置创建密钥
+func loadKey(kc config.JWTKeyConfig) (*Key, error) {
+	algorithm := kc.Algorithm
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+
+	switch algorithm {
+	case AlgorithmHS256:
+		if kc.Secret == "" {
+			return nil, errors.New("未配置对称密钥")
+		}
+		return &Key{
+			ID:        kc.ID,
+			Algorithm: algorithm,
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(kc.Secret),
+			verifyKey: []byte(kc.Secret),
+		}, nil
+	case AlgorithmRS256:
+		key := &Key{ID: kc.ID, Algorithm: algorithm, method: jwt.SigningMethodRS256}
+		if kc.PrivateKeyFile != "" {
+			data, err := os.ReadFile(kc.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("读取私钥文件失败: %w", err)
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("解析私钥失败: %w", err)
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		}
+		if kc.PublicKeyFile != "" {
+			data, err := os.ReadFile(kc.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("读取公钥文件失败: %w", err)
+			}
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("解析公钥失败: %w", err)
+			}
+			key.verifyKey = publicKey
+		}
+		if key.verifyKey == nil {
+			return nil, errors.New("未配置私钥或公钥文件")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("不支持的签名算法: %s", algorithm)
+	}
+}
+
+// add 添加密钥
+func (r *keyRing) add(key *Key) {
+	r.keys[key.ID] = key
+	r.order = append(r.order, key.ID)
+}
+
+// current 返回当前签发令牌使用的密钥
+func (r *keyRing) current() *Key {
+	if keySelector != nil {
+		if key, ok := r.keys[keySelector()]; ok && key.CanSign() {
+			return key
+		}
+	}
+	return r.keys[r.defaultID]
+}
+
+// lookup 根据令牌头部的kid查找校验密钥，不含kid的令牌使用secret_key校验
+func (r *keyRing) lookup(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = LegacyKeyID
+	}
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
+}
+
+// Keys 返回所有已配置密钥的信息，按配置顺序排列
+func Keys() ([]KeyInfo, error) {
+	r, err := getKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	currentID := r.current().ID
+	infos := make([]KeyInfo, 0, len(r.order))
+	for _, id := range r.order {
+		key := r.keys[id]
+		infos = append(infos, KeyInfo{
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			CanSign:   key.CanSign(),
+			Current:   key.ID == currentID,
+		})
+	}
+	return infos, nil
+}
+
+// RotationTarget 确定轮换后使用的密钥ID
+// 指定密钥ID时校验其存在且可以签名；未指定时按配置顺序选择当前密钥之后的下一个可签名密钥
+func RotationTarget(id string) (string, error) {
+	r, err := getKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		key, ok := r.keys[id]
+		if !ok {
+			return "", ErrKeyNotFound
+		}
+		if !key.CanSign() {
+			return "", ErrKeyCannotSign
+		}
+		return id, nil
+	}
+
+	currentID := r.current().ID
+	start := 0
+	for i, kid := range r.order {
+		if kid == currentID {
+			start = i
+			break
+		}
+	}
+	for i := 1; i < len(r.order); i++ {
+		key := r.keys[r.order[(start+i)%len(r.order)]]
+		if key.CanSign() {
+			return key.ID, nil
+		}
+	}
+	return "", ErrNoRotationKey
+}
@@ -0,0 +1,291 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// 最多跟随的跳转次数
+	maxRedirects = 3
+	// 标题长度上限（字符）
+	maxTitleLength = 255
+	// 描述长度上限（字符）
+	maxDescriptionLength = 500
+)
+
+// ErrForbiddenAddress 链接指向内网、回环等非公网地址
+var ErrForbiddenAddress = errors.New("不允许访问的地址")
+
+// blockedNetworks 禁止访问的网段，net.IP的分类方法未覆盖的保留地址
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级NAT
+	"192.0.0.0/24",  // IETF协议分配
+	"198.18.0.0/15", // 网络基准测试
+	"240.0.0.0/4",   // 保留地址
+	"64:ff9b::/96",  // IPv4/IPv6转换
+)
+
+// Fetcher 网页抓取器，只访问公网地址，并限制超时、跳转次数和读取大小
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+	userAgent   string
+}
+
+// NewFetcher 创建网页抓取器实例
+// 参数: timeout - 单次抓取的总超时, maxBodySize - 读取的网页大小上限（字节）, userAgent - 请求头User-Agent
+// 返回: 网页抓取器指针
+func NewFetcher(timeout time.Duration, maxBodySize int64, userAgent string) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// 在DNS解析后、建立连接前检查实际连接的地址，防止域名解析到内网地址或DNS重绑定
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicIP(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		// 不使用环境变量中的代理，代理会绕过连接地址检查
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Fetcher{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errors.New("跳转次数过多")
+				}
+				return checkURL(req.URL)
+			},
+		},
+		maxBodySize: maxBodySize,
+		userAgent:   userAgent,
+	}
+}
+
+// Fetch 抓取网页并解析预览信息
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Preview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("解析链接失败: %w", err)
+	}
+	if err := checkURL(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求网页失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求网页失败，状态码: %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrUnsupportedContent
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBodySize), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("识别网页编码失败: %w", err)
+	}
+
+	preview := parse(body, resp.Request.URL)
+	if preview.Title == "" && preview.Description == "" {
+		return nil, ErrNoPreview
+	}
+	return preview, nil
+}
+
+// checkURL 检查链接的协议和主机，主机为IP地址时检查是否为公网地址
+func checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: 不支持的协议%s", ErrForbiddenAddress, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// isPublicIP 判断是否为可访问的公网地址
+func isPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// parse 从网页head中解析OpenGraph元数据，未提供时使用title和description元素
+func parse(r io.Reader, pageURL *url.URL) *Preview {
+	var title, ogTitle, description, ogDescription, image, siteName string
+	z := html.NewTokenizer(r)
+	inTitle := false
+
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tag := z.Token()
+			switch tag.Data {
+			case "body":
+				break loop
+			case "title":
+				inTitle = true
+			case "meta":
+				key, content := metaAttrs(tag)
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDescription = content
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if image == "" {
+						image = content
+					}
+				case "og:site_name":
+					siteName = content
+				case "description":
+					description = content
+				}
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = string(z.Text())
+			}
+		case html.EndTagToken:
+			tag := z.Token()
+			if tag.Data == "title" {
+				inTitle = false
+			} else if tag.Data == "head" {
+				break loop
+			}
+		}
+	}
+
+	if ogTitle != "" {
+		title = ogTitle
+	}
+	if ogDescription != "" {
+		description = ogDescription
+	}
+	if siteName == "" {
+		siteName = pageURL.Hostname()
+	}
+
+	return &Preview{
+		URL:         pageURL.String(),
+		Title:       truncate(title, maxTitleLength),
+		Description: truncate(description, maxDescriptionLength),
+		Image:       resolveImage(pageURL, image),
+		SiteName:    truncate(siteName, maxTitleLength),
+	}
+}
+
+// metaAttrs 返回meta元素的property或name属性（小写）及content属性
+func metaAttrs(tag html.Token) (string, string) {
+	var key, content string
+	for _, attr := range tag.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = attr.Val
+		}
+	}
+	return key, content
+}
+
+// resolveImage 将预览图片地址解析为绝对地址，只保留http和https地址
+func resolveImage(pageURL *url.URL, image string) string {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return ""
+	}
+	ref, err := url.Parse(image)
+	if err != nil {
+		return ""
+	}
+	abs := pageURL.ResolveReference(ref)
+	if abs.Scheme != "http" && abs.Scheme != "https" {
+		return ""
+	}
+	return abs.String()
+}
+
+// truncate 合并连续空白并按字符数截断
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+// mustParseCIDRs 解析网段列表，格式错误时panic
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,137 @@
+// Package linkpreview 抓取网页的OpenGraph元数据，生成动态中链接的预览信息
+// 抓取时只允许访问公网地址，防止通过动态内容探测内网服务
+package linkpreview
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/pkg/redis"
+)
+
+const (
+	// 未指定时使用的缓存键前缀，后缀为链接SHA-256摘要
+	defaultKeyPrefix = "linkpreview:"
+	// 默认缓存时间
+	defaultCacheTTL = 24 * time.Hour
+	// 默认请求超时时间
+	defaultTimeout = 5 * time.Second
+	// 默认读取的网页大小上限，OpenGraph元数据位于head中，无需读取完整网页
+	defaultMaxBodySize = 512 << 10
+	// 默认请求头User-Agent
+	defaultUserAgent = "Mozilla/5.0 (compatible; LivefeLinkPreview/1.0)"
+	// 链接长度上限，超过时不生成预览
+	maxURLLength = 2048
+)
+
+var (
+	// ErrNoPreview 网页未包含可用的标题或描述
+	ErrNoPreview = errors.New("未解析到链接预览")
+	// ErrUnsupportedContent 链接不是HTML网页
+	ErrUnsupportedContent = errors.New("链接内容不是网页")
+)
+
+// urlPattern 匹配文本中的http和https链接，遇到空白、引号、尖括号和中文标点时结束
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'，。！？；：、（）【】《》“”‘’]+`)
+
+// Preview 链接预览信息
+type Preview struct {
+	URL         string `json:"url"`         // 最终访问的网页地址，发生跳转时为跳转后的地址
+	Title       string `json:"title"`       // 标题，优先使用og:title
+	Description string `json:"description"` // 描述，优先使用og:description
+	Image       string `json:"image"`       // 预览图片的绝对地址
+	SiteName    string `json:"site_name"`   // 网站名称，未提供og:site_name时为域名
+}
+
+// Client 链接预览客户端，在网页抓取之上增加Redis缓存
+type Client struct {
+	fetcher   *Fetcher    // 网页抓取器
+	store     redis.Store // 预览结果缓存
+	keyPrefix string      // 缓存键前缀
+	cacheTTL  time.Duration
+}
+
+// NewClient 创建链接预览客户端实例
+// 参数: fetcher - 网页抓取器, store - 缓存存储, keyPrefix - 缓存键前缀，为空时使用默认前缀, cacheTTL - 缓存时间
+// 返回: 链接预览客户端指针
+func NewClient(fetcher *Fetcher, store redis.Store, keyPrefix string, cacheTTL time.Duration) *Client {
+	if keyPrefix == "" {
+		keyPrefix = defaultKeyPrefix
+	}
+	return &Client{
+		fetcher:   fetcher,
+		store:     store,
+		keyPrefix: keyPrefix,
+		cacheTTL:  cacheTTL,
+	}
+}
+
+// Fetch 获取链接的预览信息，按链接摘要缓存结果，缓存读写失败不影响抓取
+func (c *Client) Fetch(ctx context.Context, rawURL string) (*Preview, error) {
+	sum := sha256.Sum256([]byte(rawURL))
+	key := c.keyPrefix + hex.EncodeToString(sum[:])
+	var cached Preview
+	// 与抓取时的规则一致，标题或描述任一不为空即为有效预览
+	if err := c.store.GetObj(key, &cached); err == nil && (cached.Title != "" || cached.Description != "") {
+		return &cached, nil
+	}
+
+	preview, err := c.fetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = c.store.SetObj(key, preview, c.cacheTTL)
+	return preview, nil
+}
+
+// ExtractURL 返回文本中的第一个链接，不含链接或链接过长时返回空字符串
+func ExtractURL(text string) string {
+	match := urlPattern.FindString(text)
+	// 去掉句末的英文标点，如"见 https://example.com/a."
+	match = strings.TrimRight(match, ".,;:!?)]}")
+	if len(match) > maxURLLength || len(match) <= len("https://") {
+		return ""
+	}
+	return match
+}
+
+// CountURLs 返回文本中的链接数量，与ExtractURL使用相同的匹配规则
+func CountURLs(text string) int {
+	return len(urlPattern.FindAllStringIndex(text, -1))
+}
+
+// GetLinkPreviewClient 根据配置创建链接预览客户端
+// 参数: store - 缓存存储, keyPrefix - 缓存键前缀
+// 返回: 链接预览客户端指针，未启用链接预览时返回nil客户端
+func GetLinkPreviewClient(store redis.Store, keyPrefix string) *Client {
+	cfg := config.GetLinkPreviewConfig()
+	if !cfg.Enabled {
+		return nil
+	}
+
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	cacheTTL := defaultCacheTTL
+	if d, err := time.ParseDuration(cfg.CacheTTL); err == nil && d > 0 {
+		cacheTTL = d
+	}
+	maxBodySize := int64(defaultMaxBodySize)
+	if cfg.MaxBodySize > 0 {
+		maxBodySize = cfg.MaxBodySize
+	}
+	userAgent := defaultUserAgent
+	if cfg.UserAgent != "" {
+		userAgent = cfg.UserAgent
+	}
+
+	return NewClient(NewFetcher(timeout, maxBodySize, userAgent), store, keyPrefix, cacheTTL)
+}
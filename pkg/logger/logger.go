@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,8 @@ const (
 	RequestIDKey = "request_id"
 	// UserIDKey 用户ID的上下文键名
 	UserIDKey = "userID"
+	// ClientLocationKey 客户端IP归属地的上下文键名
+	ClientLocationKey = "client_location"
 )
 
 // 日志级别常量
@@ -54,6 +57,8 @@ var (
 	logger *zap.Logger
 	// SugaredLogger 提供更便捷的API的sugar日志实例
 	SugaredLogger *zap.SugaredLogger
+	// namedLoggers 配置了独立输出的日志实例，key为日志名称，只在Init时写入
+	namedLoggers map[string]*zap.Logger
 )
 
 // timeEncoder 自定义时间编码器，格式化为"2006-01-02 15:04:05.000"格式
@@ -62,47 +67,26 @@ func timeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 }
 
 // Init 初始化日志系统
-// 根据配置创建日志记录器，设置日志级别、格式、输出路径等
+// 根据配置创建应用日志记录器，以及logger.outputs中配置了独立输出的日志记录器
 // 返回初始化过程中可能出现的错误
 func Init() error {
 	// 获取配置
 	cfg := config.GetLoggerConfig()
 
-	// 确保日志路径是绝对路径
-	if !filepath.IsAbs(cfg.OutputPath) {
-		// 获取当前工作目录
-		cwd, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("获取当前工作目录失败: %w", err)
-		}
-		cfg.OutputPath = filepath.Join(cwd, cfg.OutputPath)
-	}
-
-	// 创建日志目录
-	if err := os.MkdirAll(filepath.Dir(cfg.OutputPath), 0755); err != nil {
-		return fmt.Errorf("创建日志目录失败: %w", err)
-	}
-
-	// 配置日志级别
-	level := getZapLevel(cfg.Level)
-
-	// 配置日志编码器
-	encoder := createEncoder(cfg.Format)
-
-	// 配置日志轮转
-	lumberJackLogger := &lumberjack.Logger{
-		Filename:   cfg.OutputPath,
-		MaxSize:    cfg.MaxSize,    // 每个日志文件的最大大小（MB）
-		MaxBackups: cfg.MaxBackups, // 保留的旧日志文件的最大数量
-		MaxAge:     cfg.MaxAge,     // 保留旧日志文件的最大天数
-		Compress:   cfg.Compress,   // 是否压缩旧日志文件
-	}
-
-	// 创建输出目标
-	writeSyncer := createWriteSyncer(lumberJackLogger, cfg.Console)
-
 	// 创建核心
-	core := zapcore.NewCore(encoder, writeSyncer, level)
+	core, err := newCore(config.LoggerOutputConfig{
+		Level:      cfg.Level,
+		Format:     cfg.Format,
+		OutputPath: cfg.OutputPath,
+		MaxSize:    cfg.MaxSize,
+		MaxAge:     cfg.MaxAge,
+		MaxBackups: cfg.MaxBackups,
+		Compress:   &cfg.Compress,
+		Console:    &cfg.Console,
+	})
+	if err != nil {
+		return err
+	}
 
 	// 创建日志记录器选项
 	options := []zap.Option{
@@ -135,9 +119,79 @@ func Init() error {
 	// 创建SugaredLogger
 	SugaredLogger = logger.Sugar()
 
+	// 创建独立输出的日志记录器，未配置的项沿用应用日志的配置
+	named := make(map[string]*zap.Logger, len(cfg.Outputs))
+	for name, output := range cfg.Outputs {
+		core, err := newCore(inheritOutput(output, cfg))
+		if err != nil {
+			return fmt.Errorf("初始化%s日志失败: %w", name, err)
+		}
+		named[name] = zap.New(core, options...).Named(name)
+	}
+	namedLoggers = named
+
 	return nil
 }
 
+// inheritOutput 用应用日志的配置补全独立输出的日志中未配置的项
+func inheritOutput(output config.LoggerOutputConfig, cfg config.LoggerConfig) config.LoggerOutputConfig {
+	if output.Level == "" {
+		output.Level = cfg.Level
+	}
+	if output.Format == "" {
+		output.Format = cfg.Format
+	}
+	if output.MaxSize == 0 {
+		output.MaxSize = cfg.MaxSize
+	}
+	if output.MaxAge == 0 {
+		output.MaxAge = cfg.MaxAge
+	}
+	if output.MaxBackups == 0 {
+		output.MaxBackups = cfg.MaxBackups
+	}
+	if output.Compress == nil {
+		output.Compress = &cfg.Compress
+	}
+	if output.Console == nil {
+		output.Console = &cfg.Console
+	}
+	return output
+}
+
+// newCore 按输出配置创建日志核心，日志文件按配置轮转
+func newCore(cfg config.LoggerOutputConfig) (zapcore.Core, error) {
+	outputPath := cfg.OutputPath
+	// 确保日志路径是绝对路径
+	if !filepath.IsAbs(outputPath) {
+		// 获取当前工作目录
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("获取当前工作目录失败: %w", err)
+		}
+		outputPath = filepath.Join(cwd, outputPath)
+	}
+
+	// 创建日志目录
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %w", err)
+	}
+
+	// 配置日志轮转
+	lumberJackLogger := &lumberjack.Logger{
+		Filename:   outputPath,
+		MaxSize:    cfg.MaxSize,    // 每个日志文件的最大大小（MB）
+		MaxBackups: cfg.MaxBackups, // 保留的旧日志文件的最大数量
+		MaxAge:     cfg.MaxAge,     // 保留旧日志文件的最大天数
+		Compress:   *cfg.Compress,  // 是否压缩旧日志文件
+	}
+
+	// 创建输出目标
+	writeSyncer := createWriteSyncer(lumberJackLogger, *cfg.Console)
+
+	return zapcore.NewCore(createEncoder(cfg.Format), writeSyncer, getZapLevel(cfg.Level)), nil
+}
+
 // getZapLevel 将字符串日志级别转换为zap日志级别
 func getZapLevel(levelStr string) zapcore.Level {
 	switch strings.ToLower(levelStr) {
@@ -195,25 +249,39 @@ func createWriteSyncer(fileLogger *lumberjack.Logger, enableConsole bool) zapcor
 // Close 关闭日志记录器，确保所有日志都被写入
 // 返回同步过程中可能出现的错误
 func Close() error {
-	if logger != nil {
-		err := logger.Sync()
-		// 忽略标准输出/标准错误的同步错误，这些错误通常在应用关闭时发生
-		// 当标准输出已关闭但日志系统仍尝试同步时会出现这些错误
-		if err != nil && (strings.Contains(err.Error(), "sync /dev/stdout") ||
-			strings.Contains(err.Error(), "sync /dev/stderr") ||
-			strings.Contains(err.Error(), "The handle is invalid")) {
-			return nil
-		}
-		return err
+	if logger == nil {
+		return nil
 	}
-	return nil
+	errs := []error{syncLogger(logger)}
+	for _, l := range namedLoggers {
+		errs = append(errs, syncLogger(l))
+	}
+	return errors.Join(errs...)
+}
+
+// syncLogger 同步日志记录器的缓冲
+func syncLogger(l *zap.Logger) error {
+	err := l.Sync()
+	// 忽略标准输出/标准错误的同步错误，这些错误通常在应用关闭时发生
+	// 当标准输出已关闭但日志系统仍尝试同步时会出现这些错误
+	if err != nil && (strings.Contains(err.Error(), "sync /dev/stdout") ||
+		strings.Contains(err.Error(), "sync /dev/stderr") ||
+		strings.Contains(err.Error(), "The handle is invalid")) {
+		return nil
+	}
+	return err
 }
 
 // WithContext 从上下文中获取请求ID和用户ID，并添加到日志字段中
 // 返回带有上下文信息的日志记录器
 func WithContext(ctx context.Context) *zap.Logger {
+	return withContext(logger, ctx)
+}
+
+// withContext 为日志记录器添加上下文中的请求ID和用户ID
+func withContext(l *zap.Logger, ctx context.Context) *zap.Logger {
 	if ctx == nil {
-		return logger.With(
+		return l.With(
 			String("request_id", ""),
 			String("userID", ""),
 		)
@@ -223,11 +291,7 @@ func WithContext(ctx context.Context) *zap.Logger {
 	fields := []zap.Field{}
 
 	// 添加请求ID（始终添加，如果不存在则为空字符串）
-	requestID := ""
-	if id, ok := ctx.Value(RequestIDKey).(string); ok && id != "" {
-		requestID = id
-	}
-	fields = append(fields, String("request_id", requestID))
+	fields = append(fields, String("request_id", RequestID(ctx)))
 
 	// 添加用户ID（始终添加，如果不存在则为空字符串）
 	userID := ""
@@ -238,8 +302,13 @@ func WithContext(ctx context.Context) *zap.Logger {
 	}
 	fields = append(fields, String("userID", userID))
 
+	// 添加客户端IP归属地（仅在已解析时添加）
+	if location := ClientLocation(ctx); location != "" {
+		fields = append(fields, String(ClientLocationKey, location))
+	}
+
 	// 返回带有字段的日志记录器
-	return logger.With(fields...)
+	return l.With(fields...)
 }
 
 // WithContextS 从上下文中获取请求ID和用户ID，并添加到SugaredLogger字段中
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// 日志名称，对应logger.outputs中的配置项
+const (
+	// AccessLog 请求日志，记录每个HTTP请求和响应
+	AccessLog = "access"
+	// AuditLog 审计日志，记录管理操作及调用方身份
+	AuditLog = "audit"
+	// AnalyticsLog 分析日志，记录登录注册漏斗等结构化事件，由日志采集导入数据分析平台
+	AnalyticsLog = "analytics"
+)
+
+// NamedLogger 按名称区分的日志记录器
+// 配置了logger.outputs.<name>时按独立的级别、格式和轮转设置写入单独的文件，否则写入应用日志
+type NamedLogger struct {
+	name string
+}
+
+// Named 获取指定名称的日志记录器
+func Named(name string) NamedLogger {
+	return NamedLogger{name: name}
+}
+
+// Access 获取请求日志记录器
+func Access() NamedLogger {
+	return Named(AccessLog)
+}
+
+// Audit 获取审计日志记录器
+func Audit() NamedLogger {
+	return Named(AuditLog)
+}
+
+// Analytics 获取分析日志记录器
+func Analytics() NamedLogger {
+	return Named(AnalyticsLog)
+}
+
+// base 返回名称对应的zap日志实例，未配置独立输出时使用应用日志并标记名称
+func (l NamedLogger) base() *zap.Logger {
+	if named, ok := namedLoggers[l.name]; ok {
+		return named
+	}
+	return logger.Named(l.name)
+}
+
+// Debug 记录调试级别日志
+func (l NamedLogger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	withContext(l.base(), ctx).Debug(msg, fields...)
+}
+
+// Info 记录信息级别日志
+func (l NamedLogger) Info(ctx context.Context, msg string, fields ...zap.Field) {
+	withContext(l.base(), ctx).Info(msg, fields...)
+}
+
+// Warn 记录警告级别日志
+func (l NamedLogger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	withContext(l.base(), ctx).Warn(msg, fields...)
+}
+
+// Error 记录错误级别日志
+func (l NamedLogger) Error(ctx context.Context, msg string, fields ...zap.Field) {
+	withContext(l.base(), ctx).Error(msg, fields...)
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 传递请求ID的HTTP头，收到的请求和调用下游服务时使用
+const RequestIDHeader = "X-Request-ID"
+
+// NewRequestID 生成新的请求ID
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// RequestID 获取上下文中的请求ID，不存在时返回空字符串
+// 兼容gin.Context（通过c.Set保存）和WithRequestID返回的上下文
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(RequestIDKey).(string)
+	return id
+}
+
+// WithRequestID 返回携带请求ID的上下文，用于没有gin.Context的调用链，如异步任务和定时任务
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, requestID)
+}
+
+// ClientLocation 获取上下文中的客户端IP归属地描述，未解析时返回空字符串
+func ClientLocation(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	location, _ := ctx.Value(ClientLocationKey).(string)
+	return location
+}
+
+// WithClientLocation 返回携带客户端IP归属地描述的上下文，日志中以client_location字段记录
+func WithClientLocation(ctx context.Context, location string) context.Context {
+	return context.WithValue(ctx, ClientLocationKey, location)
+}
+
+// Detach 返回不随原上下文取消、只保留请求ID、用户ID和客户端IP归属地的新上下文
+// 用于请求返回后仍在后台执行的操作；gin.Context在请求结束后会被复用，不能直接传给后台协程
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if ctx == nil {
+		return detached
+	}
+	if id := RequestID(ctx); id != "" {
+		detached = WithRequestID(detached, id)
+	}
+	if location := ClientLocation(ctx); location != "" {
+		detached = WithClientLocation(detached, location)
+	}
+	switch id := ctx.Value(UserIDKey).(type) {
+	case string:
+		if id != "" {
+			detached = context.WithValue(detached, UserIDKey, id)
+		}
+	case uint:
+		if id > 0 {
+			detached = context.WithValue(detached, UserIDKey, fmt.Sprintf("%d", id))
+		}
+	}
+	return detached
+}
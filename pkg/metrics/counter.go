@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CounterVec 按标签值分组的计数器，并发安全
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string // 标签名，Inc时按相同顺序传入标签值
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+// counterValue 一组标签值对应的计数
+type counterValue struct {
+	labels []string
+	count  uint64
+}
+
+// NewCounterVec 创建计数器
+// 参数: name - 指标名称, help - 指标说明, labels - 标签名
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: make(map[string]*counterValue),
+	}
+}
+
+// Inc 将标签值对应的计数加一，标签值数量与标签名不一致时按标签名数量截断或补空
+func (c *CounterVec) Inc(values ...string) {
+	labels := make([]string, len(c.labels))
+	copy(labels, values)
+	key := strings.Join(labels, "\x00")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.values[key]
+	if !ok {
+		v = &counterValue{labels: labels}
+		c.values[key] = v
+	}
+	v.count++
+}
+
+// Family 返回计数器当前值组成的指标，样本按标签值排序
+func (c *CounterVec) Family() *Family {
+	c.mu.Lock()
+	list := make([]counterValue, 0, len(c.values))
+	for _, v := range c.values {
+		list = append(list, *v)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return strings.Join(list[i].labels, "\x00") < strings.Join(list[j].labels, "\x00")
+	})
+
+	f := &Family{Name: c.name, Help: c.help, Type: Counter}
+	for _, v := range list {
+		pairs := make([]string, 0, 2*len(c.labels))
+		for i, name := range c.labels {
+			pairs = append(pairs, name, v.labels[i])
+		}
+		f.Add(float64(v.count), pairs...)
+	}
+	return f
+}
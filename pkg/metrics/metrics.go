@@ -0,0 +1,124 @@
+// Package metrics 提供进程内的方法调用指标统计，记录各组件方法的调用次数、耗时和错误次数，并以Prometheus文本格式输出
+package metrics
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ContentType Prometheus文本格式的Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MethodStats 单个方法的调用统计
+type MethodStats struct {
+	Component   string        `json:"component"`    // 组件名称，如UserRepository
+	Method      string        `json:"method"`       // 方法名称
+	Calls       uint64        `json:"calls"`        // 调用次数
+	Errors      uint64        `json:"errors"`       // 返回错误的次数
+	TotalTime   time.Duration `json:"total_time"`   // 累计耗时
+	MaxDuration time.Duration `json:"max_duration"` // 单次调用最长耗时
+}
+
+// ErrorRate 返回错误率，未被调用时为0
+func (s MethodStats) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// AvgDuration 返回平均耗时，未被调用时为0
+func (s MethodStats) AvgDuration() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalTime / time.Duration(s.Calls)
+}
+
+// methodKey 方法统计的索引
+type methodKey struct {
+	component string
+	method    string
+}
+
+// Registry 方法调用指标的注册表，并发安全
+type Registry struct {
+	prefix string // 输出指标名称的前缀
+	label  string // 输出时组件名称使用的标签名
+	mu     sync.Mutex
+	stats  map[methodKey]*MethodStats
+}
+
+// NewRegistry 创建指标注册表
+// 参数: prefix - 输出指标名称的前缀，如repository, label - 输出时组件名称使用的标签名
+func NewRegistry(prefix, label string) *Registry {
+	return &Registry{
+		prefix: prefix,
+		label:  label,
+		stats:  make(map[methodKey]*MethodStats),
+	}
+}
+
+// Repositories 仓库层方法调用指标，由仓库的指标装饰器写入
+var Repositories = NewRegistry("repository", "repository")
+
+// Observe 记录一次方法调用
+// 参数: component - 组件名称, method - 方法名称, d - 调用耗时, err - 调用返回的错误，为nil时视为成功
+func (r *Registry) Observe(component, method string, d time.Duration, err error) {
+	key := methodKey{component: component, method: method}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stats[key]
+	if !ok {
+		s = &MethodStats{Component: component, Method: method}
+		r.stats[key] = s
+	}
+	s.Calls++
+	if err != nil {
+		s.Errors++
+	}
+	s.TotalTime += d
+	if d > s.MaxDuration {
+		s.MaxDuration = d
+	}
+}
+
+// Snapshot 返回所有方法调用统计的副本，按组件和方法名称排序
+func (r *Registry) Snapshot() []MethodStats {
+	r.mu.Lock()
+	list := make([]MethodStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		list = append(list, *s)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Component != list[j].Component {
+			return list[i].Component < list[j].Component
+		}
+		return list[i].Method < list[j].Method
+	})
+	return list
+}
+
+// WriteMetrics 以Prometheus文本格式输出所有方法的调用次数、错误次数、累计耗时和最长耗时
+func (r *Registry) WriteMetrics(w io.Writer) error {
+	snapshot := r.Snapshot()
+
+	calls := &Family{Name: r.prefix + "_calls_total", Help: "方法调用次数", Type: Counter}
+	errs := &Family{Name: r.prefix + "_errors_total", Help: "方法返回错误的次数", Type: Counter}
+	total := &Family{Name: r.prefix + "_duration_seconds_sum", Help: "方法调用累计耗时", Type: Counter}
+	longest := &Family{Name: r.prefix + "_duration_seconds_max", Help: "方法单次调用最长耗时", Type: Gauge}
+	for _, s := range snapshot {
+		labels := []string{r.label, s.Component, "method", s.Method}
+		calls.Add(float64(s.Calls), labels...)
+		errs.Add(float64(s.Errors), labels...)
+		total.Add(s.TotalTime.Seconds(), labels...)
+		longest.Add(s.MaxDuration.Seconds(), labels...)
+	}
+	return Write(w, calls, errs, total, longest)
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 指标类型
+const (
+	Counter = "counter" // 只增不减的计数
+	Gauge   = "gauge"   // 可增可减的瞬时值
+)
+
+// Family 一个指标及其所有样本
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Sample 指标样本
+type Sample struct {
+	Labels []string // 成对的标签名和标签值
+	Value  float64
+}
+
+// Add 添加样本，labels为成对的标签名和标签值
+func (f *Family) Add(value float64, labels ...string) {
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+// Write 按Prometheus文本格式输出指标
+func Write(w io.Writer, families ...*Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, f.Help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, sample := range f.Samples {
+			bw.WriteString(f.Name)
+			if len(sample.Labels) > 0 {
+				bw.WriteByte('{')
+				for i := 0; i+1 < len(sample.Labels); i += 2 {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					fmt.Fprintf(bw, "%s=\"%s\"", sample.Labels[i], escapeLabelValue(sample.Labels[i+1]))
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(strconv.FormatFloat(sample.Value, 'g', -1, 64))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// escapeLabelValue 转义标签值中的反斜杠、双引号和换行符
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
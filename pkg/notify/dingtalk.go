@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DingTalkNotifier 钉钉群机器人通知渠道，发送Markdown消息，实现了Notifier接口
+type DingTalkNotifier struct {
+	webhookURL string
+	secret     string
+	client     *http.Client
+}
+
+// NewDingTalkNotifier 创建钉钉群机器人通知渠道实例
+// 参数: webhookURL - 机器人Webhook地址, secret - 加签密钥，机器人未开启加签时为空, client - 发送请求使用的HTTP客户端
+func NewDingTalkNotifier(webhookURL, secret string, client *http.Client) *DingTalkNotifier {
+	return &DingTalkNotifier{webhookURL: webhookURL, secret: secret, client: client}
+}
+
+// dingTalkMessage 钉钉Markdown消息
+type dingTalkMessage struct {
+	MsgType  string `json:"msgtype"`
+	Markdown struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	} `json:"markdown"`
+}
+
+// Send 发送告警通知，实现Notifier接口
+func (n *DingTalkNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := dingTalkMessage{MsgType: "markdown"}
+	payload.Markdown.Title = msg.Title
+	payload.Markdown.Text = "### " + msg.Title + "\n\n" + msg.Content
+
+	body, err := postJSON(ctx, n.client, n.signedURL(time.Now()), payload)
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(DingTalkProvider, body)
+}
+
+// signedURL 开启加签时在地址后追加时间戳和签名
+// 签名为以密钥对"毫秒时间戳\n密钥"计算HMAC-SHA256后的Base64编码
+func (n *DingTalkNotifier) signedURL(now time.Time) string {
+	if n.secret == "" {
+		return n.webhookURL
+	}
+
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(n.secret))
+	mac.Write([]byte(timestamp + "\n" + n.secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	separator := "?"
+	if strings.Contains(n.webhookURL, "?") {
+		separator = "&"
+	}
+	return n.webhookURL + separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+}
@@ -0,0 +1,168 @@
+// Package notify 提供告警通知的统一接口和实现，支持通用Webhook、钉钉群机器人和企业微信群机器人
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"app/config"
+)
+
+// 默认通知请求超时时间
+const defaultTimeout = 5 * time.Second
+
+// 告警状态
+const (
+	// StatusFiring 告警中
+	StatusFiring = "firing"
+	// StatusResolved 已恢复
+	StatusResolved = "resolved"
+)
+
+// Alert 单条告警，字段与Prometheus Alertmanager Webhook的告警格式一致
+type Alert struct {
+	Status      string            // 告警状态：firing、resolved
+	Labels      map[string]string // 标识告警的标签，如任务名称和规则
+	Annotations map[string]string // 告警说明，如摘要和当前值
+	StartsAt    time.Time         // 开始告警时间
+	EndsAt      time.Time         // 恢复时间，告警中时为零值
+}
+
+// Message 告警通知消息
+type Message struct {
+	Title   string  // 标题
+	Content string  // Markdown格式的正文，群机器人使用
+	Alerts  []Alert // 结构化告警，通用Webhook使用
+}
+
+// Notifier 告警通知接口，所有通知渠道都需要实现此接口
+type Notifier interface {
+	// Send 发送告警通知
+	// 参数: ctx - 上下文, msg - 通知消息
+	// 返回: 可能的错误
+	Send(ctx context.Context, msg *Message) error
+}
+
+// ProviderType 通知渠道类型
+type ProviderType string
+
+// 支持的通知渠道类型
+const (
+	WebhookProvider  ProviderType = "webhook"  // 通用Webhook，请求体兼容Alertmanager Webhook格式
+	DingTalkProvider ProviderType = "dingtalk" // 钉钉群机器人
+	WeComProvider    ProviderType = "wecom"    // 企业微信群机器人
+)
+
+// NewNotifier 根据配置创建通知渠道，未配置地址时返回nil
+func NewNotifier(cfg config.NotifierConfig) (Notifier, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch ProviderType(cfg.Type) {
+	case WebhookProvider:
+		return NewWebhookNotifier(cfg.URL, client), nil
+	case DingTalkProvider:
+		return NewDingTalkNotifier(cfg.URL, cfg.Secret, client), nil
+	case WeComProvider:
+		return NewWeComNotifier(cfg.URL, client), nil
+	default:
+		return nil, fmt.Errorf("不支持的通知渠道类型: %s", cfg.Type)
+	}
+}
+
+// NewNotifiers 根据配置创建通知渠道，多个渠道时同时发送，没有可用渠道时返回nil
+func NewNotifiers(cfgs []config.NotifierConfig) (Notifier, error) {
+	var notifiers multiNotifier
+	for _, cfg := range cfgs {
+		notifier, err := NewNotifier(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if notifier != nil {
+			notifiers = append(notifiers, notifier)
+		}
+	}
+
+	switch len(notifiers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return notifiers[0], nil
+	default:
+		return notifiers, nil
+	}
+}
+
+// multiNotifier 同时向多个渠道发送通知
+type multiNotifier []Notifier
+
+// Send 向所有渠道发送通知，部分渠道失败时返回合并后的错误
+func (m multiNotifier) Send(ctx context.Context, msg *Message) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Send(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postJSON 以JSON格式发送POST请求，非2xx响应视为失败，返回响应体
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化通知内容失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送通知失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("通知渠道返回异常状态码: %d", resp.StatusCode)
+	}
+	return respBody, nil
+}
+
+// robotResponse 钉钉和企业微信群机器人的响应
+type robotResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// checkRobotResponse 检查群机器人响应的错误码，为0时表示发送成功
+func checkRobotResponse(provider ProviderType, body []byte) error {
+	var resp robotResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("解析%s响应失败: %w", provider, err)
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("%s返回错误: %d %s", provider, resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// webhookVersion Alertmanager Webhook请求体版本
+const webhookVersion = "4"
+
+// WebhookNotifier 通用Webhook通知渠道，请求体兼容Alertmanager Webhook格式，实现了Notifier接口
+// 已对接Alertmanager的接收端可直接复用
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建通用Webhook通知渠道实例
+// 参数: url - 接收地址, client - 发送请求使用的HTTP客户端
+func NewWebhookNotifier(url string, client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: client}
+}
+
+// webhookPayload Alertmanager Webhook请求体
+type webhookPayload struct {
+	Version           string            `json:"version"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	Alerts            []webhookAlert    `json:"alerts"`
+}
+
+// webhookAlert Alertmanager Webhook中的单条告警
+type webhookAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// Send 发送告警通知，实现Notifier接口
+// 任一告警处于告警中时整体状态为firing，否则为resolved
+func (n *WebhookNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := webhookPayload{
+		Version:           webhookVersion,
+		Status:            StatusResolved,
+		Receiver:          "webhook",
+		GroupLabels:       map[string]string{},
+		CommonLabels:      commonLabels(msg.Alerts),
+		CommonAnnotations: map[string]string{"summary": msg.Title},
+		Alerts:            make([]webhookAlert, 0, len(msg.Alerts)),
+	}
+	for _, alert := range msg.Alerts {
+		if alert.Status == StatusFiring {
+			payload.Status = StatusFiring
+		}
+		payload.Alerts = append(payload.Alerts, webhookAlert{
+			Status:      alert.Status,
+			Labels:      alert.Labels,
+			Annotations: alert.Annotations,
+			StartsAt:    alert.StartsAt,
+			EndsAt:      alert.EndsAt,
+		})
+	}
+
+	_, err := postJSON(ctx, n.client, n.url, payload)
+	return err
+}
+
+// commonLabels 返回所有告警中取值相同的标签
+func commonLabels(alerts []Alert) map[string]string {
+	common := map[string]string{}
+	if len(alerts) == 0 {
+		return common
+	}
+	for key, value := range alerts[0].Labels {
+		common[key] = value
+	}
+	for _, alert := range alerts[1:] {
+		for key, value := range common {
+			if alert.Labels[key] != value {
+				delete(common, key)
+			}
+		}
+	}
+	return common
+}
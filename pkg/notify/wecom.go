@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// WeComNotifier 企业微信群机器人通知渠道，发送Markdown消息，实现了Notifier接口
+type WeComNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewWeComNotifier 创建企业微信群机器人通知渠道实例
+// 参数: webhookURL - 机器人Webhook地址, client - 发送请求使用的HTTP客户端
+func NewWeComNotifier(webhookURL string, client *http.Client) *WeComNotifier {
+	return &WeComNotifier{webhookURL: webhookURL, client: client}
+}
+
+// weComMessage 企业微信Markdown消息
+type weComMessage struct {
+	MsgType  string `json:"msgtype"`
+	Markdown struct {
+		Content string `json:"content"`
+	} `json:"markdown"`
+}
+
+// Send 发送告警通知，实现Notifier接口
+func (n *WeComNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := weComMessage{MsgType: "markdown"}
+	payload.Markdown.Content = "### " + msg.Title + "\n" + msg.Content
+
+	body, err := postJSON(ctx, n.client, n.webhookURL, payload)
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(WeComProvider, body)
+}
@@ -0,0 +1,316 @@
+// Package poolmonitor 定期采样数据库和Redis连接池状态，连接池接近饱和或出现等待时记录告警日志，并保留最近的采样序列用于排查
+package poolmonitor
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"app/config"
+	"app/pkg/database"
+	"app/pkg/logger"
+	"app/pkg/metrics"
+	"app/pkg/redis"
+)
+
+// 未配置时使用的默认值
+const (
+	defaultRetention           = 240
+	defaultSaturationThreshold = 0.8
+	defaultWaitThreshold       = 1
+)
+
+// 连接池名称
+const (
+	PoolDB    = "db"
+	PoolRedis = "redis"
+)
+
+// 告警类型
+const (
+	AlertSaturated = "saturated" // 使用中的连接数接近连接池上限
+	AlertWaiting   = "waiting"   // 两次采样之间出现等待连接（Redis为获取连接超时）
+)
+
+// 连接池状态
+const (
+	StatusOK        = "ok"        // 最近一次采样未触发告警
+	StatusSaturated = "saturated" // 最近一次采样触发了告警
+	StatusUnknown   = "unknown"   // 未启用监控或尚未采样
+)
+
+// DBSample 数据库连接池采样
+type DBSample struct {
+	MaxOpen        int     `json:"max_open_connections"`
+	Open           int     `json:"open_connections"`
+	InUse          int     `json:"in_use"`
+	Idle           int     `json:"idle"`
+	WaitCount      int64   `json:"wait_count"`       // 累计等待连接的次数
+	WaitDurationMs float64 `json:"wait_duration_ms"` // 累计等待连接的时间
+}
+
+// RedisSample Redis连接池采样
+type RedisSample struct {
+	PoolSize   int    `json:"pool_size"` // 连接池容量，集群模式下为0
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+	Hits       uint32 `json:"hits"`     // 累计从连接池取到空闲连接的次数
+	Misses     uint32 `json:"misses"`   // 累计连接池中没有空闲连接的次数
+	Timeouts   uint32 `json:"timeouts"` // 累计获取连接超时的次数
+}
+
+// Alert 一次采样触发的告警
+type Alert struct {
+	Pool string `json:"pool"`
+	Type string `json:"type"`
+}
+
+// Sample 一次采样，获取统计失败的连接池为nil
+type Sample struct {
+	Time   time.Time    `json:"time"`
+	DB     *DBSample    `json:"db,omitempty"`
+	Redis  *RedisSample `json:"redis,omitempty"`
+	Alerts []Alert      `json:"alerts,omitempty"`
+}
+
+// Monitor 连接池监控器
+type Monitor struct {
+	interval            time.Duration
+	retention           int
+	saturationThreshold float64
+	waitThreshold       int64
+
+	mu          sync.RWMutex
+	samples     []Sample         // 最近的采样，按时间正序
+	alertCounts map[Alert]uint64 // 各告警触发的累计次数
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// monitor 全局连接池监控器
+var monitor *Monitor
+
+// Start 按配置启动全局连接池监控，需在数据库和Redis初始化之后调用，未配置采样间隔时不启动
+func Start() {
+	monitor = newMonitor(config.GetPoolMonitorConfig())
+	if monitor != nil {
+		go monitor.run()
+	}
+}
+
+// Stop 停止全局连接池监控，可重复调用
+func Stop() {
+	if monitor == nil {
+		return
+	}
+	monitor.once.Do(func() {
+		close(monitor.stop)
+	})
+}
+
+// newMonitor 创建连接池监控器，未配置采样间隔时返回nil
+func newMonitor(cfg config.PoolMonitorConfig) *Monitor {
+	interval, err := time.ParseDuration(cfg.Interval)
+	if err != nil || interval <= 0 {
+		return nil
+	}
+
+	m := &Monitor{
+		interval:            interval,
+		retention:           cfg.Retention,
+		saturationThreshold: cfg.SaturationThreshold,
+		waitThreshold:       cfg.WaitThreshold,
+		alertCounts:         make(map[Alert]uint64),
+		stop:                make(chan struct{}),
+	}
+	if m.retention <= 0 {
+		m.retention = defaultRetention
+	}
+	if m.saturationThreshold <= 0 || m.saturationThreshold > 1 {
+		m.saturationThreshold = defaultSaturationThreshold
+	}
+	if m.waitThreshold <= 0 {
+		m.waitThreshold = defaultWaitThreshold
+	}
+	return m
+}
+
+// run 按间隔采样，直到监控停止
+func (m *Monitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	m.sample()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.sample()
+		}
+	}
+}
+
+// sample 采样一次连接池状态，与上一次采样比较后记录告警
+func (m *Monitor) sample() {
+	s := Sample{Time: time.Now()}
+	if stats, err := database.PoolStats(); err == nil {
+		s.DB = &DBSample{
+			MaxOpen:        stats.MaxOpenConnections,
+			Open:           stats.OpenConnections,
+			InUse:          stats.InUse,
+			Idle:           stats.Idle,
+			WaitCount:      stats.WaitCount,
+			WaitDurationMs: float64(stats.WaitDuration) / float64(time.Millisecond),
+		}
+	}
+	if stats, poolSize, err := redis.PoolStats(); err == nil {
+		s.Redis = &RedisSample{
+			PoolSize:   poolSize,
+			TotalConns: stats.TotalConns,
+			IdleConns:  stats.IdleConns,
+			StaleConns: stats.StaleConns,
+			Hits:       stats.Hits,
+			Misses:     stats.Misses,
+			Timeouts:   stats.Timeouts,
+		}
+	}
+
+	m.mu.Lock()
+	var prev *Sample
+	if len(m.samples) > 0 {
+		prev = &m.samples[len(m.samples)-1]
+	}
+	s.Alerts = m.check(prev, &s)
+	for _, alert := range s.Alerts {
+		m.alertCounts[alert]++
+	}
+	m.samples = append(m.samples, s)
+	if len(m.samples) > m.retention {
+		m.samples = append(m.samples[:0:0], m.samples[len(m.samples)-m.retention:]...)
+	}
+	m.mu.Unlock()
+
+	m.logAlerts(&s)
+}
+
+// check 检查采样是否触发告警
+// 等待次数等累计值在连接重建后从零开始，小于上一次采样时不比较
+func (m *Monitor) check(prev, s *Sample) []Alert {
+	var alerts []Alert
+	if db := s.DB; db != nil {
+		if db.MaxOpen > 0 && float64(db.InUse) >= float64(db.MaxOpen)*m.saturationThreshold {
+			alerts = append(alerts, Alert{Pool: PoolDB, Type: AlertSaturated})
+		}
+		if prev != nil && prev.DB != nil && db.WaitCount-prev.DB.WaitCount >= m.waitThreshold {
+			alerts = append(alerts, Alert{Pool: PoolDB, Type: AlertWaiting})
+		}
+	}
+	if rs := s.Redis; rs != nil {
+		inUse := int64(rs.TotalConns) - int64(rs.IdleConns)
+		if rs.PoolSize > 0 && float64(inUse) >= float64(rs.PoolSize)*m.saturationThreshold {
+			alerts = append(alerts, Alert{Pool: PoolRedis, Type: AlertSaturated})
+		}
+		if prev != nil && prev.Redis != nil && int64(rs.Timeouts)-int64(prev.Redis.Timeouts) >= m.waitThreshold {
+			alerts = append(alerts, Alert{Pool: PoolRedis, Type: AlertWaiting})
+		}
+	}
+	return alerts
+}
+
+// logAlerts 记录采样触发的告警
+func (m *Monitor) logAlerts(s *Sample) {
+	ctx := context.Background()
+	for _, alert := range s.Alerts {
+		switch alert.Pool {
+		case PoolDB:
+			logger.Warn(ctx, "数据库连接池告警",
+				logger.String("type", alert.Type),
+				logger.Int("in_use", s.DB.InUse),
+				logger.Int("max_open_connections", s.DB.MaxOpen),
+				logger.Int64("wait_count", s.DB.WaitCount),
+				logger.Float64("wait_duration_ms", s.DB.WaitDurationMs))
+		case PoolRedis:
+			logger.Warn(ctx, "Redis连接池告警",
+				logger.String("type", alert.Type),
+				logger.Int("total_conns", int(s.Redis.TotalConns)),
+				logger.Int("idle_conns", int(s.Redis.IdleConns)),
+				logger.Int("pool_size", s.Redis.PoolSize),
+				logger.Int("timeouts", int(s.Redis.Timeouts)))
+		}
+	}
+}
+
+// Samples 返回全局监控器保留的采样序列，按时间正序，未启用监控时返回nil
+func Samples() []Sample {
+	if monitor == nil {
+		return nil
+	}
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	return append([]Sample(nil), monitor.samples...)
+}
+
+// Status 根据最近一次采样返回连接池状态
+func Status() string {
+	if monitor == nil {
+		return StatusUnknown
+	}
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	if len(monitor.samples) == 0 {
+		return StatusUnknown
+	}
+	if len(monitor.samples[len(monitor.samples)-1].Alerts) > 0 {
+		return StatusSaturated
+	}
+	return StatusOK
+}
+
+// WriteMetrics 以Prometheus文本格式输出最近一次采样的连接池状态和各告警的累计触发次数
+func WriteMetrics(w io.Writer) error {
+	dbInUse := &metrics.Family{Name: "pool_db_in_use", Help: "数据库连接池使用中的连接数", Type: metrics.Gauge}
+	dbOpen := &metrics.Family{Name: "pool_db_open_connections", Help: "数据库连接池已建立的连接数", Type: metrics.Gauge}
+	dbMaxOpen := &metrics.Family{Name: "pool_db_max_open_connections", Help: "数据库连接池最大连接数", Type: metrics.Gauge}
+	dbWaitCount := &metrics.Family{Name: "pool_db_wait_count", Help: "当前连接累计等待连接的次数，重建连接后从零开始", Type: metrics.Gauge}
+	dbWaitSeconds := &metrics.Family{Name: "pool_db_wait_duration_seconds", Help: "当前连接累计等待连接的时间，重建连接后从零开始", Type: metrics.Gauge}
+	redisTotal := &metrics.Family{Name: "pool_redis_total_conns", Help: "Redis连接池的连接数", Type: metrics.Gauge}
+	redisIdle := &metrics.Family{Name: "pool_redis_idle_conns", Help: "Redis连接池的空闲连接数", Type: metrics.Gauge}
+	redisSize := &metrics.Family{Name: "pool_redis_pool_size", Help: "Redis连接池容量，集群模式下不输出", Type: metrics.Gauge}
+	redisTimeouts := &metrics.Family{Name: "pool_redis_timeouts", Help: "当前客户端累计获取连接超时的次数，重建连接后从零开始", Type: metrics.Gauge}
+	alerts := &metrics.Family{Name: "pool_alerts_total", Help: "连接池告警累计触发的采样次数", Type: metrics.Counter}
+
+	if monitor != nil {
+		monitor.mu.RLock()
+		if n := len(monitor.samples); n > 0 {
+			s := monitor.samples[n-1]
+			if s.DB != nil {
+				dbInUse.Add(float64(s.DB.InUse))
+				dbOpen.Add(float64(s.DB.Open))
+				dbMaxOpen.Add(float64(s.DB.MaxOpen))
+				dbWaitCount.Add(float64(s.DB.WaitCount))
+				dbWaitSeconds.Add(s.DB.WaitDurationMs / 1000)
+			}
+			if s.Redis != nil {
+				redisTotal.Add(float64(s.Redis.TotalConns))
+				redisIdle.Add(float64(s.Redis.IdleConns))
+				if s.Redis.PoolSize > 0 {
+					redisSize.Add(float64(s.Redis.PoolSize))
+				}
+				redisTimeouts.Add(float64(s.Redis.Timeouts))
+			}
+		}
+		for _, pool := range []string{PoolDB, PoolRedis} {
+			for _, typ := range []string{AlertSaturated, AlertWaiting} {
+				alerts.Add(float64(monitor.alertCounts[Alert{Pool: pool, Type: typ}]), "pool", pool, "type", typ)
+			}
+		}
+		monitor.mu.RUnlock()
+	}
+
+	return metrics.Write(w, dbInUse, dbOpen, dbMaxOpen, dbWaitCount, dbWaitSeconds,
+		redisTotal, redisIdle, redisSize, redisTimeouts, alerts)
+}
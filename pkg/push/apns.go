@@ -0,0 +1,181 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"app/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// APNs相关常量
+const (
+	// APNs生产环境接口地址
+	apnsProductionURL = "https://api.push.apple.com"
+	// APNs开发环境接口地址
+	apnsSandboxURL = "https://api.sandbox.push.apple.com"
+	// 认证令牌的复用时间，苹果要求在20至60分钟之间刷新
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// apnsInvalidTokenReasons 表示设备令牌已失效的APNs错误原因
+var apnsInvalidTokenReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"Unregistered":           true,
+	"DeviceTokenNotForTopic": true,
+}
+
+// APNsProvider 苹果推送服务提供商，使用基于令牌（.p8私钥）的认证，实现了Provider接口
+type APNsProvider struct {
+	baseURL string
+	keyID   string
+	teamID  string
+	topic   string
+	key     *ecdsa.PrivateKey
+	client  *http.Client
+
+	mu       sync.Mutex
+	token    string    // 缓存的认证令牌
+	issuedAt time.Time // 认证令牌的签发时间
+}
+
+// NewAPNsProvider 创建苹果推送服务提供商实例
+// 参数: cfg - APNs配置, timeout - 请求超时时间
+// 返回: 服务提供商指针和可能的错误，私钥文件无法读取或格式错误时返回错误
+func NewAPNsProvider(cfg config.APNsConfig, timeout time.Duration) (*APNsProvider, error) {
+	data, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取APNs私钥文件失败: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("APNs私钥文件格式错误")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析APNs私钥失败: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs私钥不是ECDSA私钥")
+	}
+
+	baseURL := apnsProductionURL
+	if cfg.Sandbox {
+		baseURL = apnsSandboxURL
+	}
+
+	// 默认的Transport会通过TLS协商使用HTTP/2，APNs只支持HTTP/2
+	return &APNsProvider{
+		baseURL: baseURL,
+		keyID:   cfg.KeyID,
+		teamID:  cfg.TeamID,
+		topic:   cfg.BundleID,
+		key:     key,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// apnsResponse APNs错误响应
+type apnsResponse struct {
+	Reason string `json:"reason"`
+}
+
+// Send 向iOS设备发送推送，实现Provider接口
+func (p *APNsProvider) Send(ctx context.Context, token string, msg *Message) error {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化APNs推送内容失败: %w", err)
+	}
+
+	authToken, err := p.authToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求APNs失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result apnsResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode == http.StatusGone || apnsInvalidTokenReasons[result.Reason] {
+		return fmt.Errorf("%w: %s", ErrTokenInvalid, result.Reason)
+	}
+	if result.Reason == "ExpiredProviderToken" {
+		p.resetAuthToken()
+	}
+	return fmt.Errorf("APNs返回错误: %d %s", resp.StatusCode, result.Reason)
+}
+
+// authToken 返回APNs认证令牌，超过复用时间后重新签发
+func (p *APNsProvider) authToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.issuedAt) < apnsTokenTTL {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	t.Header["kid"] = p.keyID
+	signed, err := t.SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("签发APNs认证令牌失败: %w", err)
+	}
+
+	p.token = signed
+	p.issuedAt = now
+	return signed, nil
+}
+
+// resetAuthToken 丢弃缓存的认证令牌，下次推送时重新签发
+func (p *APNsProvider) resetAuthToken() {
+	p.mu.Lock()
+	p.token = ""
+	p.mu.Unlock()
+}
@@ -0,0 +1,223 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"app/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// FCM相关常量
+const (
+	// FCM HTTP v1发送接口，参数为项目ID
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	// FCM发送消息所需的OAuth2授权范围
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// 服务账号未指定时使用的令牌接口
+	googleTokenURL = "https://oauth2.googleapis.com/token"
+	// 访问令牌在过期前提前刷新的时间
+	fcmTokenRefreshAhead = time.Minute
+)
+
+// FCMProvider Firebase云消息服务提供商，使用服务账号获取OAuth2访问令牌，实现了Provider接口
+type FCMProvider struct {
+	sendURL     string
+	clientEmail string
+	tokenURL    string
+	key         *rsa.PrivateKey
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string    // 缓存的访问令牌
+	expiresAt   time.Time // 访问令牌的过期时间
+}
+
+// fcmServiceAccount 服务账号JSON密钥文件中使用的字段
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewFCMProvider 创建Firebase云消息服务提供商实例
+// 参数: cfg - FCM配置, timeout - 请求超时时间
+// 返回: 服务提供商指针和可能的错误，密钥文件无法读取或格式错误时返回错误
+func NewFCMProvider(cfg config.FCMConfig, timeout time.Duration) (*FCMProvider, error) {
+	data, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取FCM服务账号密钥文件失败: %w", err)
+	}
+	var account fcmServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("解析FCM服务账号密钥文件失败: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" {
+		return nil, errors.New("FCM服务账号密钥文件缺少project_id或client_email")
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, errors.New("FCM服务账号私钥格式错误")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析FCM服务账号私钥失败: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("FCM服务账号私钥不是RSA私钥")
+	}
+
+	tokenURL := account.TokenURI
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+
+	return &FCMProvider{
+		sendURL:     fmt.Sprintf(fcmSendURL, account.ProjectID),
+		clientEmail: account.ClientEmail,
+		tokenURL:    tokenURL,
+		key:         key,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// fcmErrorResponse FCM错误响应
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send 向Android设备发送推送，实现Provider接口
+func (p *FCMProvider) Send(ctx context.Context, token string, msg *Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("序列化FCM推送内容失败: %w", err)
+	}
+
+	accessToken, err := p.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求FCM失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result fcmErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	for _, detail := range result.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return fmt.Errorf("%w: %s", ErrTokenInvalid, detail.ErrorCode)
+		}
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		p.resetAccessToken()
+	}
+	return fmt.Errorf("FCM返回错误: %d %s %s", resp.StatusCode, result.Error.Status, result.Error.Message)
+}
+
+// fcmTokenResponse 令牌接口响应
+type fcmTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// getAccessToken 返回OAuth2访问令牌，即将过期时使用服务账号签名的JWT重新换取
+func (p *FCMProvider) getAccessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Until(p.expiresAt) > fcmTokenRefreshAhead {
+		return p.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.clientEmail,
+		"scope": fcmScope,
+		"aud":   p.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("签发FCM授权断言失败: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("获取FCM访问令牌失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("获取FCM访问令牌失败: HTTP %d", resp.StatusCode)
+	}
+	var result fcmTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("解析FCM访问令牌失败: %w", err)
+	}
+
+	p.accessToken = result.AccessToken
+	p.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
+
+// resetAccessToken 丢弃缓存的访问令牌，下次推送时重新获取
+func (p *FCMProvider) resetAccessToken() {
+	p.mu.Lock()
+	p.accessToken = ""
+	p.mu.Unlock()
+}
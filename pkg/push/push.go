@@ -0,0 +1,94 @@
+// Package push 提供移动推送服务的统一接口和实现，iOS设备使用APNs，Android设备使用FCM
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/config"
+)
+
+// 默认请求超时时间
+const defaultTimeout = 5 * time.Second
+
+// ErrTokenInvalid 推送服务返回设备令牌已失效（应用已卸载或令牌已过期），调用方应删除该令牌
+var ErrTokenInvalid = errors.New("设备推送令牌已失效")
+
+// Message 推送消息
+type Message struct {
+	Title string            // 通知标题
+	Body  string            // 通知正文
+	Data  map[string]string // 透传给客户端的附加数据，如跳转目标
+}
+
+// Provider 推送服务提供商接口，所有服务提供商都需要实现此接口
+type Provider interface {
+	// Send 向单个设备发送推送
+	// 参数: ctx - 上下文, token - 设备推送令牌, msg - 推送消息
+	// 返回: 令牌已失效时返回包装了ErrTokenInvalid的错误
+	Send(ctx context.Context, token string, msg *Message) error
+}
+
+// Platform 设备平台
+type Platform string
+
+// 支持的设备平台
+const (
+	PlatformIOS     Platform = "ios"     // iOS设备，使用APNs推送
+	PlatformAndroid Platform = "android" // Android设备，使用FCM推送
+)
+
+// Client 推送客户端，按设备平台选择服务提供商
+type Client struct {
+	providers map[Platform]Provider
+}
+
+// NewClient 创建推送客户端实例
+// 参数: providers - 各设备平台对应的服务提供商
+// 返回: 推送客户端指针
+func NewClient(providers map[Platform]Provider) *Client {
+	return &Client{providers: providers}
+}
+
+// Send 向指定平台的设备发送推送，平台未配置服务提供商时返回错误
+func (c *Client) Send(ctx context.Context, platform Platform, token string, msg *Message) error {
+	provider, ok := c.providers[platform]
+	if !ok {
+		return fmt.Errorf("未配置%s平台的推送服务", platform)
+	}
+	return provider.Send(ctx, token, msg)
+}
+
+// GetPushClient 根据配置创建推送客户端
+// 返回: 推送客户端指针和可能的错误，APNs和FCM均未配置时返回nil客户端
+func GetPushClient() (*Client, error) {
+	cfg := config.GetPushConfig()
+
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	providers := make(map[Platform]Provider)
+	if cfg.APNs.KeyFile != "" {
+		provider, err := NewAPNsProvider(cfg.APNs, timeout)
+		if err != nil {
+			return nil, err
+		}
+		providers[PlatformIOS] = provider
+	}
+	if cfg.FCM.CredentialsFile != "" {
+		provider, err := NewFCMProvider(cfg.FCM, timeout)
+		if err != nil {
+			return nil, err
+		}
+		providers[PlatformAndroid] = provider
+	}
+
+	if len(providers) == 0 {
+		return nil, nil
+	}
+	return NewClient(providers), nil
+}
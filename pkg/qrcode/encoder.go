@@ -0,0 +1,174 @@
+package qrcode
+
+// versionInfo 单个版本在M级纠错下的码字分组参数
+type versionInfo struct {
+	ecPerBlock int       // 每个分块的纠错码字数
+	groups     [2][2]int // 两组分块的 {分块数, 每块数据码字数}
+	alignments []int     // 校正图形中心坐标
+}
+
+// versions 版本1至10在M级纠错下的参数，分享链接较短，10版本（最多213字节）已足够
+var versions = [...]versionInfo{
+	1:  {10, [2][2]int{{1, 16}}, nil},
+	2:  {16, [2][2]int{{1, 28}}, []int{6, 18}},
+	3:  {26, [2][2]int{{1, 44}}, []int{6, 22}},
+	4:  {18, [2][2]int{{2, 32}}, []int{6, 26}},
+	5:  {24, [2][2]int{{2, 43}}, []int{6, 30}},
+	6:  {16, [2][2]int{{4, 27}}, []int{6, 34}},
+	7:  {18, [2][2]int{{4, 31}}, []int{6, 22, 38}},
+	8:  {22, [2][2]int{{2, 38}, {2, 39}}, []int{6, 24, 42}},
+	9:  {22, [2][2]int{{3, 36}, {2, 37}}, []int{6, 26, 46}},
+	10: {26, [2][2]int{{4, 43}, {1, 44}}, []int{6, 28, 50}},
+}
+
+// maxVersion 支持的最大版本
+const maxVersion = len(versions) - 1
+
+// dataCodewords 数据码字总数
+func (v versionInfo) dataCodewords() int {
+	return v.groups[0][0]*v.groups[0][1] + v.groups[1][0]*v.groups[1][1]
+}
+
+// countBits 字节模式下字符计数指示符的位数
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// chooseVersion 选择能容纳内容的最小版本
+func chooseVersion(length int) (int, bool) {
+	for v := 1; v <= maxVersion; v++ {
+		capacity := versions[v].dataCodewords()*8 - 4 - countBits(v)
+		if length*8 <= capacity {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// bitBuffer 按位写入的缓冲区
+type bitBuffer []bool
+
+// append 追加value的低n位，高位在前
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+// encodeData 以字节模式编码内容，补齐到指定版本的数据码字数
+func encodeData(content []byte, version int) []byte {
+	capacity := versions[version].dataCodewords() * 8
+
+	var bits bitBuffer
+	bits.append(0x4, 4) // 字节模式
+	bits.append(len(content), countBits(version))
+	for _, c := range content {
+		bits.append(int(c), 8)
+	}
+
+	// 终止符最多4位，再补齐到整字节
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+
+	// 交替填充0xEC和0x11
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	data := make([]byte, capacity/8)
+	for i, bit := range bits {
+		if bit {
+			data[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return data
+}
+
+// addErrorCorrection 按分块计算纠错码字并交织输出最终码字序列
+func addErrorCorrection(data []byte, version int) []byte {
+	info := versions[version]
+	generator := rsGenerator(info.ecPerBlock)
+
+	var blocks, ecBlocks [][]byte
+	offset := 0
+	for _, group := range info.groups {
+		for i := 0; i < group[0]; i++ {
+			block := data[offset : offset+group[1]]
+			offset += group[1]
+			blocks = append(blocks, block)
+			ecBlocks = append(ecBlocks, rsRemainder(block, generator))
+		}
+	}
+
+	result := make([]byte, 0, len(data)+len(blocks)*info.ecPerBlock)
+	// 数据码字按列交织，第二组的分块比第一组多一个码字
+	longest := info.groups[0][1]
+	if info.groups[1][1] > longest {
+		longest = info.groups[1][1]
+	}
+	for i := 0; i < longest; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// gfMultiply GF(256)乘法，本原多项式为 x^8+x^4+x^3+x^2+1
+func gfMultiply(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		carry := z >> 7
+		z = z<<1 ^ carry*0x1D
+		if (y>>i)&1 == 1 {
+			z ^= x
+		}
+	}
+	return z
+}
+
+// rsGenerator 生成指定次数的里德-所罗门生成多项式系数（不含最高次项）
+func rsGenerator(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder 计算数据多项式除以生成多项式的余数，即纠错码字
+func rsRemainder(data, generator []byte) []byte {
+	result := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range generator {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
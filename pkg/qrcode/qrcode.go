@@ -0,0 +1,107 @@
+// Package qrcode 提供二维码生成功能，以字节模式和M级纠错编码内容并输出PNG图片
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// ErrContentTooLong 内容超出支持的最大版本容量
+var ErrContentTooLong = errors.New("二维码内容过长")
+
+// quietZone 四周留白的模块数
+const quietZone = 4
+
+// QRCode 二维码
+type QRCode struct {
+	Content string // 编码的内容
+	Version int    // 版本号，决定模块数
+	modules [][]bool
+}
+
+// New 生成二维码，自动选择能容纳内容的最小版本和罚分最低的掩码
+func New(content string) (*QRCode, error) {
+	data := []byte(content)
+	version, ok := chooseVersion(len(data))
+	if !ok {
+		return nil, ErrContentTooLong
+	}
+
+	codewords := addErrorCorrection(encodeData(data, version), version)
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns(version)
+	m.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormat(mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			bestMask, bestPenalty = mask, p
+		}
+		m.applyMask(mask)
+	}
+	m.applyMask(bestMask)
+	m.drawFormat(bestMask)
+
+	return &QRCode{
+		Content: content,
+		Version: version,
+		modules: m.modules,
+	}, nil
+}
+
+// Size 每边的模块数（不含留白）
+func (q *QRCode) Size() int {
+	return len(q.modules)
+}
+
+// Image 生成二维码图片，边长不超过size像素，每个模块至少1像素
+func (q *QRCode) Image(size int) image.Image {
+	total := q.Size() + quietZone*2
+	scale := size / total
+	if scale < 1 {
+		scale = 1
+	}
+
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, total*scale, total*scale), palette)
+	for y, row := range q.modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			left, top := (x+quietZone)*scale, (y+quietZone)*scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(left+dx, top+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG 生成PNG格式的二维码图片
+func (q *QRCode) PNG(size int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, q.Image(size)); err != nil {
+		return nil, fmt.Errorf("编码PNG失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode 生成内容对应的PNG格式二维码图片
+// 参数: content - 编码的内容, size - 图片边长上限（像素）
+func Encode(content string, size int) ([]byte, error) {
+	q, err := New(content)
+	if err != nil {
+		return nil, err
+	}
+	return q.PNG(size)
+}
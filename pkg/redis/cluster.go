@@ -0,0 +1,179 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis部署模式
+const (
+	// ModeSingle 单节点模式
+	ModeSingle = "single"
+	// ModeCluster 集群模式
+	ModeCluster = "cluster"
+	// ModeSentinel 哨兵模式
+	ModeSentinel = "sentinel"
+)
+
+// ErrCrossSlot 表示集群模式下多键操作涉及不同的哈希插槽
+var ErrCrossSlot = errors.New("集群模式下多键操作的键必须使用相同的哈希标签")
+
+// newClient 根据部署模式创建Redis客户端，配置了键前缀时为客户端添加前缀钩子
+func newClient(cfg *RedisConfig) (redis.UniversalClient, error) {
+	hook := newPrefixHook(cfg.KeyPrefix)
+
+	var client redis.UniversalClient
+	switch cfg.Mode {
+	case "", ModeSingle:
+		client = redis.NewClient(&redis.Options{
+			Addr:         cfg.Addr,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		})
+	case ModeCluster:
+		if len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("集群模式需要配置节点地址列表")
+		}
+		// 集群模式不支持选择数据库，忽略DB配置
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Password:     cfg.Password,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			// 节点客户端也添加前缀钩子，覆盖遍历主节点和WATCH等直接使用节点客户端的命令
+			// 经集群客户端转发的命令已添加前缀，节点客户端不会重复添加
+			NewClient: func(opt *redis.Options) *redis.Client {
+				node := redis.NewClient(opt)
+				if hook != nil {
+					node.AddHook(hook)
+				}
+				return node
+			},
+		})
+	case ModeSentinel:
+		if len(cfg.Addrs) == 0 || cfg.MasterName == "" {
+			return nil, fmt.Errorf("哨兵模式需要配置哨兵地址列表和主节点名称")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+		})
+	default:
+		return nil, fmt.Errorf("不支持的Redis部署模式: %s", cfg.Mode)
+	}
+
+	if hook != nil {
+		client.AddHook(hook)
+	}
+	return client, nil
+}
+
+// isCluster 判断当前是否为集群模式
+func isCluster() bool {
+	_, ok := GetClient().(*redis.ClusterClient)
+	return ok
+}
+
+// HashTag 生成带哈希标签的键，集群模式下相同标签的键位于同一插槽
+// 例如 HashTag("order", "stream:1") 返回 "{order}:stream:1"
+func HashTag(tag, key string) string {
+	return "{" + tag + "}:" + key
+}
+
+// hashTagOf 提取键中参与插槽计算的部分，规则与Redis集群一致
+func hashTagOf(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+	return key[start+1 : start+1+end]
+}
+
+// checkSameSlot 集群模式下检查多个键是否使用相同的哈希标签
+// 不同标签的键也可能恰好位于同一插槽，这里按标签保守判断
+func checkSameSlot(keys ...string) error {
+	if !isCluster() || len(keys) < 2 {
+		return nil
+	}
+	tag := hashTagOf(keys[0])
+	for _, key := range keys[1:] {
+		if hashTagOf(key) != tag {
+			return ErrCrossSlot
+		}
+	}
+	return nil
+}
+
+// sumPerKey 通过管道逐个键执行命令并累加结果，用于集群模式下的多键命令
+func sumPerKey(ctx context.Context, keys []string, cmd func(pipe redis.Pipeliner, key string) *redis.IntCmd) (int64, error) {
+	cmds := make([]*redis.IntCmd, 0, len(keys))
+	_, err := GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			cmds = append(cmds, cmd(pipe, key))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, c := range cmds {
+		total += c.Val()
+	}
+	return total, nil
+}
+
+// collectFromMasters 在所有主节点上执行查询并汇总结果
+func collectFromMasters(ctx context.Context, cluster *redis.ClusterClient, fn func(ctx context.Context, node *redis.Client) ([]string, error)) ([]string, error) {
+	var mu sync.Mutex
+	var result []string
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		values, err := fn(ctx, node)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		result = append(result, values...)
+		mu.Unlock()
+		return nil
+	})
+	return result, err
+}
+
+// scanCluster 遍历所有主节点中匹配的键
+func scanCluster(ctx context.Context, cluster *redis.ClusterClient, match string, count int64) ([]string, error) {
+	return collectFromMasters(ctx, cluster, func(ctx context.Context, node *redis.Client) ([]string, error) {
+		var keys []string
+		iter := node.Scan(ctx, 0, match, count).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		return keys, iter.Err()
+	})
+}
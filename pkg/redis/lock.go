@@ -38,7 +38,7 @@ func (dl *DistributedLock) Acquire() error {
 	defer cancel()
 
 	// 使用SetNX尝试获取锁
-	success, err := Client.SetNX(ctx, dl.key, dl.value, dl.expiration).Result()
+	success, err := GetClient().SetNX(ctx, dl.key, dl.value, dl.expiration).Result()
 	if err != nil {
 		return err
 	}
@@ -65,7 +65,7 @@ func (dl *DistributedLock) Release() error {
 	`
 
 	// 执行Lua脚本
-	result, err := Client.Eval(ctx, script, []string{dl.key}, dl.value).Int64()
+	result, err := GetClient().Eval(ctx, script, []string{dl.key}, dl.value).Int64()
 	if err != nil {
 		return err
 	}
@@ -83,5 +83,5 @@ func (dl *DistributedLock) TryAcquire() (bool, error) {
 	defer cancel()
 
 	// 使用SetNX尝试获取锁
-	return Client.SetNX(ctx, dl.key, dl.value, dl.expiration).Result()
+	return GetClient().SetNX(ctx, dl.key, dl.value, dl.expiration).Result()
 }
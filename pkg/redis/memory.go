@@ -0,0 +1,417 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryEntry 内存存储中的键值
+type memoryEntry struct {
+	str       string
+	hash      map[string]string
+	set       map[string]struct{}
+	expiresAt time.Time // 零值表示永不过期
+}
+
+// MemoryStore 内存实现的Redis存储，用于单元测试
+// HyperLogLog使用精确集合模拟，计数结果为精确值
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string]*memoryEntry
+	now  func() time.Time
+}
+
+// NewMemoryStore 创建内存存储实例
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		data: make(map[string]*memoryEntry),
+		now:  time.Now,
+	}
+}
+
+// SetClock 设置内存存储使用的时钟，便于测试过期逻辑
+func (m *MemoryStore) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// lookup 获取未过期的键值，调用方需持有锁
+func (m *MemoryStore) lookup(key string) *memoryEntry {
+	entry, ok := m.data[key]
+	if !ok {
+		return nil
+	}
+	if !entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt) {
+		delete(m.data, key)
+		return nil
+	}
+	return entry
+}
+
+// expiresAt 计算过期时间，不大于0表示永不过期
+func (m *MemoryStore) expiresAt(expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return m.now().Add(expiration)
+}
+
+// toString 按Redis的方式将值转换为字符串
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Set 设置键值对并指定过期时间
+func (m *MemoryStore) Set(key string, value interface{}, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = &memoryEntry{str: toString(value), expiresAt: m.expiresAt(expiration)}
+	return nil
+}
+
+// SetNX 当键不存在时设置键值对并指定过期时间，常用于实现分布式锁
+func (m *MemoryStore) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lookup(key) != nil {
+		return false, nil
+	}
+	m.data[key] = &memoryEntry{str: toString(value), expiresAt: m.expiresAt(expiration)}
+	return true, nil
+}
+
+// Get 获取字符串类型的键值
+func (m *MemoryStore) Get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil {
+		return "", ErrKeyNotFound
+	}
+	if entry.hash != nil || entry.set != nil {
+		return "", ErrInvalidType
+	}
+	return entry.str, nil
+}
+
+// GetObj 获取JSON对象并反序列化到指定结构
+func (m *MemoryStore) GetObj(key string, obj interface{}) error {
+	val, err := m.Get(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(val), obj)
+}
+
+// SetObj 设置对象（序列化后存储）
+func (m *MemoryStore) SetObj(key string, obj interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return m.Set(key, data, expiration)
+}
+
+// Incr 将 key 中储存的数字值增一
+func (m *MemoryStore) Incr(key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil {
+		entry = &memoryEntry{str: "0"}
+		m.data[key] = entry
+	}
+	if entry.hash != nil || entry.set != nil {
+		return 0, ErrInvalidType
+	}
+	n, err := strconv.ParseInt(entry.str, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidType
+	}
+	n++
+	entry.str = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+// Del 删除键
+func (m *MemoryStore) Del(keys ...string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for _, key := range keys {
+		if m.lookup(key) != nil {
+			delete(m.data, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Exists 检查键是否存在
+func (m *MemoryStore) Exists(keys ...string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for _, key := range keys {
+		if m.lookup(key) != nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Expire 设置过期时间
+func (m *MemoryStore) Expire(key string, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil {
+		return false, nil
+	}
+	if expiration <= 0 {
+		delete(m.data, key)
+		return true, nil
+	}
+	entry.expiresAt = m.expiresAt(expiration)
+	return true, nil
+}
+
+// TTL 获取剩余过期时间，与Redis一致：键不存在返回-2，未设置过期时间返回-1
+func (m *MemoryStore) TTL(key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil {
+		return -2, nil
+	}
+	if entry.expiresAt.IsZero() {
+		return -1, nil
+	}
+	return entry.expiresAt.Sub(m.now()), nil
+}
+
+// hashEntry 获取或创建哈希表，调用方需持有锁
+func (m *MemoryStore) hashEntry(key string) (*memoryEntry, error) {
+	entry := m.lookup(key)
+	if entry == nil {
+		entry = &memoryEntry{hash: make(map[string]string)}
+		m.data[key] = entry
+	}
+	if entry.hash == nil {
+		return nil, ErrInvalidType
+	}
+	return entry, nil
+}
+
+// HSet 设置哈希表字段，支持 field, value 交替传入或传入 map[string]interface{}
+func (m *MemoryStore) HSet(key string, values ...interface{}) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pairs := values
+	if len(values) == 1 {
+		if fields, ok := values[0].(map[string]interface{}); ok {
+			pairs = make([]interface{}, 0, len(fields)*2)
+			for field, value := range fields {
+				pairs = append(pairs, field, value)
+			}
+		}
+	}
+	if len(pairs)%2 != 0 {
+		return 0, ErrInvalidType
+	}
+
+	entry, err := m.hashEntry(key)
+	if err != nil {
+		return 0, err
+	}
+	var added int64
+	for i := 0; i < len(pairs); i += 2 {
+		field := toString(pairs[i])
+		if _, ok := entry.hash[field]; !ok {
+			added++
+		}
+		entry.hash[field] = toString(pairs[i+1])
+	}
+	return added, nil
+}
+
+// HGet 获取哈希表字段
+func (m *MemoryStore) HGet(key, field string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil || entry.hash == nil {
+		return "", ErrKeyNotFound
+	}
+	val, ok := entry.hash[field]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+	return val, nil
+}
+
+// HGetAll 获取哈希表所有字段和值
+func (m *MemoryStore) HGetAll(key string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]string)
+	entry := m.lookup(key)
+	if entry == nil || entry.hash == nil {
+		return result, nil
+	}
+	for field, value := range entry.hash {
+		result[field] = value
+	}
+	return result, nil
+}
+
+// HIncrBy 为哈希表字段的整数值加上增量
+func (m *MemoryStore) HIncrBy(key, field string, incr int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, err := m.hashEntry(key)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if val, ok := entry.hash[field]; ok {
+		n, err = strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return 0, ErrInvalidType
+		}
+	}
+	n += incr
+	entry.hash[field] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+// HDel 删除哈希表字段
+func (m *MemoryStore) HDel(key string, fields ...string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil || entry.hash == nil {
+		return 0, nil
+	}
+	var deleted int64
+	for _, field := range fields {
+		if _, ok := entry.hash[field]; ok {
+			delete(entry.hash, field)
+			deleted++
+		}
+	}
+	if len(entry.hash) == 0 {
+		delete(m.data, key)
+	}
+	return deleted, nil
+}
+
+// setEntry 获取或创建集合，调用方需持有锁
+func (m *MemoryStore) setEntry(key string) (*memoryEntry, error) {
+	entry := m.lookup(key)
+	if entry == nil {
+		entry = &memoryEntry{set: make(map[string]struct{})}
+		m.data[key] = entry
+	}
+	if entry.set == nil {
+		return nil, ErrInvalidType
+	}
+	return entry, nil
+}
+
+// SAdd 向集合添加一个或多个成员
+func (m *MemoryStore) SAdd(key string, members ...interface{}) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, err := m.setEntry(key)
+	if err != nil {
+		return 0, err
+	}
+	var added int64
+	for _, member := range members {
+		s := toString(member)
+		if _, ok := entry.set[s]; !ok {
+			entry.set[s] = struct{}{}
+			added++
+		}
+	}
+	return added, nil
+}
+
+// SMembers 获取集合所有成员
+func (m *MemoryStore) SMembers(key string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil || entry.set == nil {
+		return []string{}, nil
+	}
+	members := make([]string, 0, len(entry.set))
+	for member := range entry.set {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// SRem 移除集合中一个或多个成员
+func (m *MemoryStore) SRem(key string, members ...interface{}) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.lookup(key)
+	if entry == nil || entry.set == nil {
+		return 0, nil
+	}
+	var removed int64
+	for _, member := range members {
+		s := toString(member)
+		if _, ok := entry.set[s]; ok {
+			delete(entry.set, s)
+			removed++
+		}
+	}
+	if len(entry.set) == 0 {
+		delete(m.data, key)
+	}
+	return removed, nil
+}
+
+// PFAdd 添加元素，基数发生变化时返回1
+func (m *MemoryStore) PFAdd(key string, els ...interface{}) (int64, error) {
+	added, err := m.SAdd(key, els...)
+	if err != nil {
+		return 0, err
+	}
+	if added > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// PFCount 返回多个键合并后的基数
+func (m *MemoryStore) PFCount(keys ...string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	union := make(map[string]struct{})
+	for _, key := range keys {
+		entry := m.lookup(key)
+		if entry == nil || entry.set == nil {
+			continue
+		}
+		for member := range entry.set {
+			union[member] = struct{}{}
+		}
+	}
+	return int64(len(union)), nil
+}
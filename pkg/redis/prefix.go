@@ -0,0 +1,348 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"app/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 键前缀与键之间的分隔符
+const prefixSeparator = ":"
+
+// rawContextKey 标记命令的键已处理或需要按原样访问的上下文键
+type rawContextKey struct{}
+
+// keySpec 命令中键参数的位置
+type keySpec struct {
+	first int // 第一个键参数的位置
+	last  int // 最后一个键参数的位置，为-1表示到最后一个参数
+	step  int // 相邻键参数的间隔
+}
+
+var (
+	// 只有一个键且位于第一个参数的命令
+	singleKey = keySpec{first: 1, last: 1, step: 1}
+	// 所有参数都是键的命令
+	allKeys = keySpec{first: 1, last: -1, step: 1}
+	// 前两个参数是键的命令
+	twoKeys = keySpec{first: 1, last: 2, step: 1}
+	// 键和值交替出现的命令
+	keyValuePairs = keySpec{first: 1, last: -1, step: 2}
+	// 子命令之后为键的命令
+	subcommandKey = keySpec{first: 2, last: 2, step: 1}
+)
+
+// keySpecs 需要添加前缀的命令及其键参数的位置
+// EVAL、XREAD、SCAN等键位置不固定的命令在rewriteKeys中单独处理，未列出的命令（如PUBLISH）不添加前缀
+var keySpecs = map[string]keySpec{
+	// 字符串
+	"get": singleKey, "set": singleKey, "setnx": singleKey, "setex": singleKey, "psetex": singleKey,
+	"getset": singleKey, "getdel": singleKey, "getex": singleKey, "append": singleKey, "strlen": singleKey,
+	"incr": singleKey, "incrby": singleKey, "incrbyfloat": singleKey, "decr": singleKey, "decrby": singleKey,
+	"getrange": singleKey, "setrange": singleKey, "mget": allKeys, "mset": keyValuePairs, "msetnx": keyValuePairs,
+	// 位图
+	"setbit": singleKey, "getbit": singleKey, "bitcount": singleKey, "bitpos": singleKey, "bitfield": singleKey,
+	"bitop": {first: 2, last: -1, step: 1},
+	// 键
+	"del": allKeys, "unlink": allKeys, "exists": allKeys, "touch": allKeys, "watch": allKeys,
+	"expire": singleKey, "pexpire": singleKey, "expireat": singleKey, "pexpireat": singleKey, "persist": singleKey,
+	"ttl": singleKey, "pttl": singleKey, "type": singleKey, "dump": singleKey, "restore": singleKey,
+	"rename": twoKeys, "renamenx": twoKeys, "copy": twoKeys,
+	// 哈希表
+	"hset": singleKey, "hsetnx": singleKey, "hget": singleKey, "hmset": singleKey, "hmget": singleKey,
+	"hgetall": singleKey, "hincrby": singleKey, "hincrbyfloat": singleKey, "hdel": singleKey, "hexists": singleKey,
+	"hlen": singleKey, "hkeys": singleKey, "hvals": singleKey, "hstrlen": singleKey, "hscan": singleKey,
+	// 列表
+	"lpush": singleKey, "rpush": singleKey, "lpushx": singleKey, "rpushx": singleKey, "lpop": singleKey,
+	"rpop": singleKey, "lrange": singleKey, "llen": singleKey, "lindex": singleKey, "lset": singleKey,
+	"linsert": singleKey, "lrem": singleKey, "ltrim": singleKey, "lpos": singleKey,
+	"rpoplpush": twoKeys, "lmove": twoKeys,
+	// 集合
+	"sadd": singleKey, "srem": singleKey, "smembers": singleKey, "sismember": singleKey, "smismember": singleKey,
+	"scard": singleKey, "spop": singleKey, "srandmember": singleKey, "sscan": singleKey, "smove": twoKeys,
+	"sinter": allKeys, "sunion": allKeys, "sdiff": allKeys,
+	"sinterstore": allKeys, "sunionstore": allKeys, "sdiffstore": allKeys,
+	// 有序集合
+	"zadd": singleKey, "zrem": singleKey, "zrange": singleKey, "zrangebyscore": singleKey, "zrevrange": singleKey,
+	"zrevrangebyscore": singleKey, "zrangebylex": singleKey, "zrank": singleKey, "zrevrank": singleKey,
+	"zscore": singleKey, "zmscore": singleKey, "zincrby": singleKey, "zcard": singleKey, "zcount": singleKey,
+	"zlexcount": singleKey, "zremrangebyscore": singleKey, "zremrangebyrank": singleKey,
+	"zremrangebylex": singleKey, "zpopmin": singleKey, "zpopmax": singleKey, "zscan": singleKey,
+	// 地理位置
+	"geoadd": singleKey, "geopos": singleKey, "geodist": singleKey, "geohash": singleKey, "geosearch": singleKey,
+	"georadius": singleKey, "georadius_ro": singleKey, "georadiusbymember": singleKey, "georadiusbymember_ro": singleKey,
+	// HyperLogLog
+	"pfadd": singleKey, "pfcount": allKeys, "pfmerge": allKeys,
+	// 流
+	"xadd": singleKey, "xdel": singleKey, "xlen": singleKey, "xrange": singleKey, "xrevrange": singleKey,
+	"xtrim": singleKey, "xack": singleKey, "xpending": singleKey, "xclaim": singleKey, "xautoclaim": singleKey,
+	"xsetid": singleKey, "xgroup": subcommandKey, "xinfo": subcommandKey,
+}
+
+// KeyPrefix 返回配置的全局键前缀（含末尾分隔符），未配置时为空
+func KeyPrefix() string {
+	prefix := strings.Trim(config.GetRedisConfig().KeyPrefix, prefixSeparator)
+	if prefix == "" {
+		return ""
+	}
+	return prefix + prefixSeparator
+}
+
+// RawContext 返回不添加键前缀的上下文，使用该上下文执行的命令按原样访问键
+// 用于迁移存量键等需要访问其他环境或未加前缀的键的场景
+func RawContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawContextKey{}, true)
+}
+
+// isRaw 判断上下文是否要求按原样访问键
+func isRaw(ctx context.Context) bool {
+	raw, _ := ctx.Value(rawContextKey{}).(bool)
+	return raw
+}
+
+// prefixHook 为命令中的键自动添加全局前缀，并去掉返回结果中键名的前缀
+// 多个环境共用同一Redis时，业务代码和分布式锁、调度器、消息队列等组件无需感知前缀
+type prefixHook struct {
+	prefix string
+}
+
+// newPrefixHook 创建键前缀钩子，前缀为空时返回nil
+func newPrefixHook(prefix string) *prefixHook {
+	if prefix == "" {
+		return nil
+	}
+	return &prefixHook{prefix: prefix}
+}
+
+// DialHook 不处理连接
+func (h *prefixHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook 执行命令前为键添加前缀，执行后去掉结果中的前缀
+// 已处理的命令通过上下文标记，集群模式下转发到节点客户端时不会重复添加
+func (h *prefixHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if isRaw(ctx) {
+			return next(ctx, cmd)
+		}
+		h.rewriteKeys(cmd)
+		err := next(RawContext(ctx), cmd)
+		h.stripResult(cmd)
+		return err
+	}
+}
+
+// ProcessPipelineHook 为管道和事务中的每条命令添加前缀
+func (h *prefixHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if isRaw(ctx) {
+			return next(ctx, cmds)
+		}
+		for _, cmd := range cmds {
+			h.rewriteKeys(cmd)
+		}
+		err := next(RawContext(ctx), cmds)
+		for _, cmd := range cmds {
+			h.stripResult(cmd)
+		}
+		return err
+	}
+}
+
+// rewriteKeys 为命令中的键参数添加前缀
+func (h *prefixHook) rewriteKeys(cmd redis.Cmder) {
+	args := cmd.Args()
+	switch name := cmd.Name(); name {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro":
+		// EVAL script numkeys key [key ...] arg [arg ...]
+		if len(args) > 2 {
+			if n, ok := args[2].(int); ok {
+				h.prefixArgs(args, keySpec{first: 3, last: 2 + n, step: 1})
+			}
+		}
+	case "xread", "xreadgroup":
+		// XREAD ... STREAMS key [key ...] id [id ...]
+		for i, arg := range args {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "streams") {
+				n := (len(args) - i - 1) / 2
+				h.prefixArgs(args, keySpec{first: i + 1, last: i + n, step: 1})
+				break
+			}
+		}
+	case "scan":
+		// SCAN cursor MATCH pattern，未指定模式时在结果中过滤其他前缀的键
+		for i := 2; i < len(args)-1; i++ {
+			if s, ok := args[i].(string); ok && strings.EqualFold(s, "match") {
+				args[i+1] = h.prefixPattern(args[i+1])
+				break
+			}
+		}
+	case "keys":
+		if len(args) > 1 {
+			args[1] = h.prefixPattern(args[1])
+		}
+	default:
+		if spec, ok := keySpecs[name]; ok {
+			h.prefixArgs(args, spec)
+		}
+	}
+}
+
+// prefixArgs 为指定位置的参数添加前缀
+func (h *prefixHook) prefixArgs(args []interface{}, spec keySpec) {
+	last := spec.last
+	if last < 0 || last >= len(args) {
+		last = len(args) - 1
+	}
+	for i := spec.first; i <= last; i += spec.step {
+		if key, ok := args[i].(string); ok {
+			args[i] = h.prefix + key
+		}
+	}
+}
+
+// prefixPattern 为匹配模式添加前缀，前缀中的通配符按字面匹配
+func (h *prefixHook) prefixPattern(arg interface{}) interface{} {
+	pattern, ok := arg.(string)
+	if !ok {
+		return arg
+	}
+	return escapePattern(h.prefix) + pattern
+}
+
+// stripResult 去掉返回结果中键名的前缀，不属于当前前缀的键会被过滤
+func (h *prefixHook) stripResult(cmd redis.Cmder) {
+	switch c := cmd.(type) {
+	case *redis.ScanCmd:
+		if cmd.Name() == "scan" {
+			page, cursor := c.Val()
+			c.SetVal(h.stripKeys(page), cursor)
+		}
+	case *redis.StringSliceCmd:
+		if cmd.Name() == "keys" {
+			c.SetVal(h.stripKeys(c.Val()))
+		}
+	case *redis.XStreamSliceCmd:
+		streams := c.Val()
+		for i := range streams {
+			streams[i].Stream = strings.TrimPrefix(streams[i].Stream, h.prefix)
+		}
+	}
+}
+
+// stripKeys 去掉键名的前缀，只保留带当前前缀的键
+func (h *prefixHook) stripKeys(keys []string) []string {
+	result := keys[:0]
+	for _, key := range keys {
+		if strings.HasPrefix(key, h.prefix) {
+			result = append(result, key[len(h.prefix):])
+		}
+	}
+	return result
+}
+
+// escapePattern 转义匹配模式中的通配符
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// MigrateKeyPrefix 为匹配模式的未加前缀的存量键添加当前配置的全局前缀
+// 目标键已存在时跳过，保留原键的剩余过期时间；dryRun为true时只统计不修改
+// 参数: patterns - 存量键的匹配模式，如 "token:*", dryRun - 是否只统计
+// 返回: 迁移（或将迁移）的键数量
+func MigrateKeyPrefix(patterns []string, dryRun bool) (int, error) {
+	prefix := KeyPrefix()
+	if prefix == "" {
+		return 0, fmt.Errorf("未配置键前缀redis.key_prefix")
+	}
+
+	client := GetClient()
+	if client == nil {
+		return 0, fmt.Errorf("Redis未初始化")
+	}
+	ctx := RawContext(context.Background())
+
+	migrated := 0
+	for _, pattern := range patterns {
+		keys, err := scanRaw(ctx, client, pattern)
+		if err != nil {
+			return migrated, fmt.Errorf("扫描键 %s 失败: %w", pattern, err)
+		}
+		for _, key := range keys {
+			// 模式可能同时匹配已加前缀的键，如前缀与命名空间同名
+			if strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if dryRun {
+				migrated++
+				continue
+			}
+			ok, err := moveKey(ctx, client, key, prefix+key)
+			if err != nil {
+				return migrated, fmt.Errorf("迁移键 %s 失败: %w", key, err)
+			}
+			if ok {
+				migrated++
+			}
+		}
+	}
+	return migrated, nil
+}
+
+// scanRaw 不添加前缀地遍历匹配模式的键，集群模式下遍历所有主节点
+func scanRaw(ctx context.Context, client redis.UniversalClient, pattern string) ([]string, error) {
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		return scanCluster(ctx, cluster, pattern, 1000)
+	}
+
+	var keys []string
+	iter := client.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// moveKey 将键重命名为新键，新键已存在时返回false
+// 集群模式下新旧键可能位于不同插槽，通过DUMP和RESTORE复制后删除原键
+func moveKey(ctx context.Context, client redis.UniversalClient, from, to string) (bool, error) {
+	if _, ok := client.(*redis.ClusterClient); !ok {
+		return client.RenameNX(ctx, from, to).Result()
+	}
+
+	exists, err := client.Exists(ctx, to).Result()
+	if err != nil || exists > 0 {
+		return false, err
+	}
+	value, err := client.Dump(ctx, from).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	ttl, err := client.PTTL(ctx, from).Result()
+	if err != nil {
+		return false, err
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := client.Restore(ctx, to, ttl, value).Err(); err != nil {
+		return false, err
+	}
+	return true, client.Del(ctx, from).Err()
+}
@@ -6,15 +6,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"app/config"
+	"app/pkg/retry"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// Client 全局Redis客户端实例
-var Client *redis.Client
+// clientHolder 包装全局Redis客户端，便于原子替换
+type clientHolder struct {
+	client redis.UniversalClient
+}
+
+var (
+	// current 当前使用的全局Redis客户端，重建连接时整体替换
+	current atomic.Pointer[clientHolder]
+	// monitor 连接健康检查器
+	monitor *retry.Monitor
+)
+
+// GetClient 获取全局Redis客户端实例，未初始化时返回nil
+// 根据配置的部署模式，可能是单节点、集群或哨兵客户端
+// 连接重建后返回新的客户端，调用方不应长期持有返回值
+func GetClient() redis.UniversalClient {
+	if holder := current.Load(); holder != nil {
+		return holder.client
+	}
+	return nil
+}
 
 // 错误常量
 var (
@@ -43,27 +65,49 @@ type RedisConfig struct {
 	DialTimeout  time.Duration // 连接超时时间
 	ReadTimeout  time.Duration // 读取超时时间
 	WriteTimeout time.Duration // 写入超时时间
+
+	Mode             string   // 部署模式
+	Addrs            []string // 集群节点或哨兵地址列表
+	MasterName       string   // 哨兵模式下的主节点名称
+	SentinelPassword string   // 哨兵认证密码
+	KeyPrefix        string   // 全局键前缀（含末尾分隔符），为空时不添加前缀
 }
 
 // Init 初始化Redis连接并测试连接可用性
+// 连接失败时按配置的策略重试，等待Redis就绪，初始化成功后启动连接健康检查
 func Init() error {
+	policy := retry.NewPolicy(config.GetRedisConfig().Retry)
+
+	var client redis.UniversalClient
+	err := retry.Do("Redis", policy, func() error {
+		var err error
+		client, err = connect()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 设置全局客户端实例
+	current.Store(&clientHolder{client: client})
+	monitor = retry.StartMonitor("Redis", policy, ping, reconnect)
+
+	return nil
+}
+
+// connect 根据配置创建Redis客户端并测试连接
+func connect() (redis.UniversalClient, error) {
 	// 获取并解析Redis配置
 	redisConfig, err := parseRedisConfig()
 	if err != nil {
-		return fmt.Errorf("解析Redis配置失败: %w", err)
-	}
-
-	// 创建Redis客户端
-	client := redis.NewClient(&redis.Options{
-		Addr:         redisConfig.Addr,
-		Password:     redisConfig.Password,
-		DB:           redisConfig.DB,
-		PoolSize:     redisConfig.PoolSize,
-		MinIdleConns: redisConfig.MinIdleConns,
-		DialTimeout:  redisConfig.DialTimeout,
-		ReadTimeout:  redisConfig.ReadTimeout,
-		WriteTimeout: redisConfig.WriteTimeout,
-	})
+		return nil, fmt.Errorf("解析Redis配置失败: %w", err)
+	}
+
+	// 根据部署模式创建Redis客户端
+	client, err := newClient(redisConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	// 测试连接
 	ctx, cancel := getContext()
@@ -71,12 +115,32 @@ func Init() error {
 
 	if _, err := client.Ping(ctx).Result(); err != nil {
 		_ = client.Close()
-		return fmt.Errorf("Redis连接测试失败: %w", err)
+		return nil, fmt.Errorf("Redis连接测试失败: %w", err)
 	}
 
-	// 设置全局Client实例
-	Client = client
+	return client, nil
+}
 
+// ping 检查全局客户端的连接状态
+func ping() error {
+	ctx, cancel := getContext()
+	defer cancel()
+	return GetClient().Ping(ctx).Err()
+}
+
+// reconnect 创建新的客户端替换全局客户端，旧客户端在正在执行的命令完成后关闭
+func reconnect() error {
+	client, err := connect()
+	if err != nil {
+		return err
+	}
+
+	old := current.Swap(&clientHolder{client: client})
+	if old != nil {
+		time.AfterFunc(defaultTimeout, func() {
+			_ = old.client.Close()
+		})
+	}
 	return nil
 }
 
@@ -99,17 +163,39 @@ func parseRedisConfig() (*RedisConfig, error) {
 		DialTimeout:  dialTimeout,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
+
+		Mode:             cfg.Mode,
+		Addrs:            cfg.Addrs,
+		MasterName:       cfg.MasterName,
+		SentinelPassword: cfg.SentinelPassword,
+		KeyPrefix:        KeyPrefix(),
 	}, nil
 }
 
-// Close 安全地关闭Redis连接
+// Close 停止连接健康检查并安全地关闭Redis连接
 func Close() error {
-	if Client != nil {
-		return Client.Close()
+	monitor.Stop()
+	if holder := current.Swap(nil); holder != nil {
+		return holder.client.Close()
 	}
 	return nil
 }
 
+// PoolStats 获取全局Redis客户端的连接池统计信息和连接池容量
+// 集群模式下统计为各节点连接池之和，无法与单个节点的容量比较，容量返回0
+func PoolStats() (*redis.PoolStats, int, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, 0, errors.New("Redis未初始化")
+	}
+
+	stats := client.PoolStats()
+	if c, ok := client.(*redis.Client); ok {
+		return stats, c.Options().PoolSize, nil
+	}
+	return stats, 0, nil
+}
+
 // getContext 创建带默认超时的上下文
 func getContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), defaultTimeout)
@@ -121,14 +207,14 @@ func getContext() (context.Context, context.CancelFunc) {
 func Set(key string, value interface{}, expiration time.Duration) error {
 	ctx, cancel := getContext()
 	defer cancel()
-	return Client.Set(ctx, key, value, expiration).Err()
+	return GetClient().Set(ctx, key, value, expiration).Err()
 }
 
 // SetNX 当键不存在时设置键值对并指定过期时间，常用于实现分布式锁
 func SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
 	ctx, cancel := getContext()
 	defer cancel()
-	return Client.SetNX(ctx, key, value, expiration).Result()
+	return GetClient().SetNX(ctx, key, value, expiration).Result()
 }
 
 // Get 获取字符串类型的键值
@@ -136,7 +222,7 @@ func Get(key string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	result, err := Client.Get(ctx, key).Result()
+	result, err := GetClient().Get(ctx, key).Result()
 	if err == redis.Nil {
 		return "", ErrKeyNotFound
 	}
@@ -151,7 +237,7 @@ func GetObj(key string, obj interface{}) error {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	val, err := Client.Get(ctx, key).Result()
+	val, err := GetClient().Get(ctx, key).Result()
 	if err == redis.Nil {
 		return ErrKeyNotFound
 	}
@@ -172,7 +258,7 @@ func SetObj(key string, obj interface{}, expiration time.Duration) error {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Set(ctx, key, data, expiration).Err()
+	return GetClient().Set(ctx, key, data, expiration).Err()
 }
 
 // Del 删除键
@@ -180,7 +266,13 @@ func Del(keys ...string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Del(ctx, keys...).Result()
+	// 集群模式下多个键可能位于不同插槽，需要逐个删除
+	if isCluster() && len(keys) > 1 {
+		return sumPerKey(ctx, keys, func(pipe redis.Pipeliner, key string) *redis.IntCmd {
+			return pipe.Del(ctx, key)
+		})
+	}
+	return GetClient().Del(ctx, keys...).Result()
 }
 
 // Exists 检查键是否存在
@@ -188,7 +280,13 @@ func Exists(keys ...string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Exists(ctx, keys...).Result()
+	// 集群模式下多个键可能位于不同插槽，需要逐个检查
+	if isCluster() && len(keys) > 1 {
+		return sumPerKey(ctx, keys, func(pipe redis.Pipeliner, key string) *redis.IntCmd {
+			return pipe.Exists(ctx, key)
+		})
+	}
+	return GetClient().Exists(ctx, keys...).Result()
 }
 
 // Expire 设置过期时间
@@ -196,7 +294,7 @@ func Expire(key string, expiration time.Duration) (bool, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Expire(ctx, key, expiration).Result()
+	return GetClient().Expire(ctx, key, expiration).Result()
 }
 
 // 哈希表操作
@@ -206,7 +304,7 @@ func HSet(key string, values ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.HSet(ctx, key, values...).Result()
+	return GetClient().HSet(ctx, key, values...).Result()
 }
 
 // HGet 获取哈希表字段
@@ -214,19 +312,35 @@ func HGet(key, field string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	val, err := Client.HGet(ctx, key, field).Result()
+	val, err := GetClient().HGet(ctx, key, field).Result()
 	if err == redis.Nil {
 		return "", ErrKeyNotFound
 	}
 	return val, err
 }
 
+// HMGet 获取哈希表多个字段的值，不存在的字段对应nil
+func HMGet(key string, fields ...string) ([]interface{}, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().HMGet(ctx, key, fields...).Result()
+}
+
 // HGetAll 获取哈希表所有字段和值
 func HGetAll(key string) (map[string]string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.HGetAll(ctx, key).Result()
+	return GetClient().HGetAll(ctx, key).Result()
+}
+
+// HIncrBy 为哈希表字段的整数值加上增量
+func HIncrBy(key, field string, incr int64) (int64, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().HIncrBy(ctx, key, field, incr).Result()
 }
 
 // HDel 删除哈希表字段
@@ -234,7 +348,7 @@ func HDel(key string, fields ...string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.HDel(ctx, key, fields...).Result()
+	return GetClient().HDel(ctx, key, fields...).Result()
 }
 
 // 列表操作
@@ -244,7 +358,7 @@ func LPush(key string, values ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.LPush(ctx, key, values...).Result()
+	return GetClient().LPush(ctx, key, values...).Result()
 }
 
 // RPush 将一个或多个值插入到列表尾部
@@ -252,7 +366,7 @@ func RPush(key string, values ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.RPush(ctx, key, values...).Result()
+	return GetClient().RPush(ctx, key, values...).Result()
 }
 
 // LPop 移出并获取列表的第一个元素
@@ -260,7 +374,7 @@ func LPop(key string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	val, err := Client.LPop(ctx, key).Result()
+	val, err := GetClient().LPop(ctx, key).Result()
 	if err == redis.Nil {
 		return "", ErrKeyNotFound
 	}
@@ -272,7 +386,7 @@ func RPop(key string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	val, err := Client.RPop(ctx, key).Result()
+	val, err := GetClient().RPop(ctx, key).Result()
 	if err == redis.Nil {
 		return "", ErrKeyNotFound
 	}
@@ -284,7 +398,7 @@ func LRange(key string, start, stop int64) ([]string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.LRange(ctx, key, start, stop).Result()
+	return GetClient().LRange(ctx, key, start, stop).Result()
 }
 
 // 集合操作
@@ -294,7 +408,7 @@ func SAdd(key string, members ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.SAdd(ctx, key, members...).Result()
+	return GetClient().SAdd(ctx, key, members...).Result()
 }
 
 // SMembers 获取集合所有成员
@@ -302,7 +416,15 @@ func SMembers(key string) ([]string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.SMembers(ctx, key).Result()
+	return GetClient().SMembers(ctx, key).Result()
+}
+
+// SIsMember 判断成员是否在集合中
+func SIsMember(key string, member interface{}) (bool, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().SIsMember(ctx, key, member).Result()
 }
 
 // SRem 移除集合中一个或多个成员
@@ -310,7 +432,7 @@ func SRem(key string, members ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.SRem(ctx, key, members...).Result()
+	return GetClient().SRem(ctx, key, members...).Result()
 }
 
 // 有序集合操作
@@ -320,7 +442,7 @@ func ZAdd(key string, members ...redis.Z) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ZAdd(ctx, key, members...).Result()
+	return GetClient().ZAdd(ctx, key, members...).Result()
 }
 
 // ZRange 通过索引区间返回有序集合成员
@@ -328,7 +450,7 @@ func ZRange(key string, start, stop int64) ([]string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ZRange(ctx, key, start, stop).Result()
+	return GetClient().ZRange(ctx, key, start, stop).Result()
 }
 
 // ZRem 移除有序集合中的一个或多个成员
@@ -336,7 +458,7 @@ func ZRem(key string, members ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ZRem(ctx, key, members...).Result()
+	return GetClient().ZRem(ctx, key, members...).Result()
 }
 
 // 计数器操作
@@ -346,7 +468,7 @@ func Incr(key string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Incr(ctx, key).Result()
+	return GetClient().Incr(ctx, key).Result()
 }
 
 // IncrBy 将 key 中储存的数字值增加指定增量值
@@ -354,7 +476,7 @@ func IncrBy(key string, value int64) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.IncrBy(ctx, key, value).Result()
+	return GetClient().IncrBy(ctx, key, value).Result()
 }
 
 // IncrByFloat 将 key 中储存的数字值增加指定浮点数增量值
@@ -362,7 +484,7 @@ func IncrByFloat(key string, value float64) (float64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.IncrByFloat(ctx, key, value).Result()
+	return GetClient().IncrByFloat(ctx, key, value).Result()
 }
 
 // Decr 将 key 中储存的数字值减一
@@ -370,7 +492,7 @@ func Decr(key string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Decr(ctx, key).Result()
+	return GetClient().Decr(ctx, key).Result()
 }
 
 // DecrBy 将 key 中储存的数字值减去指定减量值
@@ -378,17 +500,23 @@ func DecrBy(key string, value int64) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.DecrBy(ctx, key, value).Result()
+	return GetClient().DecrBy(ctx, key, value).Result()
 }
 
 // Scan 迭代器操作
 
 // Scan 迭代当前数据库中的数据库键
+// 集群模式下游标无法跨节点延续，会一次性遍历所有主节点并返回游标0
 func Scan(cursor uint64, match string, count int64) ([]string, uint64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	keys, nextCursor, err := Client.Scan(ctx, cursor, match, count).Result()
+	if cluster, ok := GetClient().(*redis.ClusterClient); ok {
+		keys, err := scanCluster(ctx, cluster, match, count)
+		return keys, 0, err
+	}
+
+	keys, nextCursor, err := GetClient().Scan(ctx, cursor, match, count).Result()
 	return keys, nextCursor, err
 }
 
@@ -397,7 +525,7 @@ func HScan(key string, cursor uint64, match string, count int64) ([]string, uint
 	ctx, cancel := getContext()
 	defer cancel()
 
-	values, nextCursor, err := Client.HScan(ctx, key, cursor, match, count).Result()
+	values, nextCursor, err := GetClient().HScan(ctx, key, cursor, match, count).Result()
 	return values, nextCursor, err
 }
 
@@ -406,7 +534,7 @@ func SScan(key string, cursor uint64, match string, count int64) ([]string, uint
 	ctx, cancel := getContext()
 	defer cancel()
 
-	members, nextCursor, err := Client.SScan(ctx, key, cursor, match, count).Result()
+	members, nextCursor, err := GetClient().SScan(ctx, key, cursor, match, count).Result()
 	return members, nextCursor, err
 }
 
@@ -415,7 +543,7 @@ func ZScan(key string, cursor uint64, match string, count int64) ([]string, uint
 	ctx, cancel := getContext()
 	defer cancel()
 
-	values, nextCursor, err := Client.ZScan(ctx, key, cursor, match, count).Result()
+	values, nextCursor, err := GetClient().ZScan(ctx, key, cursor, match, count).Result()
 	return values, nextCursor, err
 }
 
@@ -426,42 +554,58 @@ func Publish(channel string, message interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Publish(ctx, channel, message).Result()
+	return GetClient().Publish(ctx, channel, message).Result()
 }
 
 // Subscribe 订阅给定的一个或多个频道的信息
 func Subscribe(channels ...string) *redis.PubSub {
-	return Client.Subscribe(context.Background(), channels...)
+	return GetClient().Subscribe(context.Background(), channels...)
 }
 
 // PSubscribe 订阅一个或多个符合给定模式的频道
 func PSubscribe(patterns ...string) *redis.PubSub {
-	return Client.PSubscribe(context.Background(), patterns...)
+	return GetClient().PSubscribe(context.Background(), patterns...)
 }
 
 // 事务操作
 
 // TxPipeline 创建一个事务管道
 func TxPipeline() redis.Pipeliner {
-	return Client.TxPipeline()
+	return GetClient().TxPipeline()
 }
 
 // Watch 监视一个或多个key，如果在事务执行之前这个key被其他命令所改动，那么事务将被打断
+// 集群模式下按键选择节点，键需要预先添加前缀，fn中执行的命令仍会自动添加前缀
 func Watch(fn func(*redis.Tx) error, keys ...string) error {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Watch(ctx, fn, keys...)
+	if err := checkSameSlot(keys...); err != nil {
+		return err
+	}
+	prefix := KeyPrefix()
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = prefix + key
+	}
+	return GetClient().Watch(RawContext(ctx), fn, prefixed...)
 }
 
 // 键管理命令
 
 // Keys 查找所有符合给定模式的键
+// 集群模式下汇总所有主节点的结果
 func Keys(pattern string) ([]string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Keys(ctx, pattern).Result()
+	if cluster, ok := GetClient().(*redis.ClusterClient); ok {
+		return collectFromMasters(ctx, cluster, func(ctx context.Context, node *redis.Client) ([]string, error) {
+			return node.Keys(ctx, pattern).Result()
+		})
+	}
+
+	return GetClient().Keys(ctx, pattern).Result()
 }
 
 // Type 返回键所储存的值的类型
@@ -469,7 +613,7 @@ func Type(key string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Type(ctx, key).Result()
+	return GetClient().Type(ctx, key).Result()
 }
 
 // TTL 返回键的剩余生存时间
@@ -477,7 +621,7 @@ func TTL(key string) (time.Duration, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.TTL(ctx, key).Result()
+	return GetClient().TTL(ctx, key).Result()
 }
 
 // Rename 修改键的名称
@@ -485,7 +629,7 @@ func Rename(key, newkey string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Rename(ctx, key, newkey).Result()
+	return GetClient().Rename(ctx, key, newkey).Result()
 }
 
 // RenameNX 仅当 newkey 不存在时修改键的名称
@@ -493,7 +637,7 @@ func RenameNX(key, newkey string) (bool, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.RenameNX(ctx, key, newkey).Result()
+	return GetClient().RenameNX(ctx, key, newkey).Result()
 }
 
 // 位图操作
@@ -503,7 +647,7 @@ func SetBit(key string, offset int64, value int) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.SetBit(ctx, key, offset, value).Result()
+	return GetClient().SetBit(ctx, key, offset, value).Result()
 }
 
 // GetBit 对key所储存的字符串值，获取指定偏移量上的位
@@ -511,7 +655,7 @@ func GetBit(key string, offset int64) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.GetBit(ctx, key, offset).Result()
+	return GetClient().GetBit(ctx, key, offset).Result()
 }
 
 // BitCount 计算字符串中被设置为1的比特位的数量
@@ -519,14 +663,14 @@ func BitCount(key string, bitCount *redis.BitCount) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.BitCount(ctx, key, bitCount).Result()
+	return GetClient().BitCount(ctx, key, bitCount).Result()
 }
 
 // 管道操作
 
 // Pipeline 创建一个管道，用于一次性执行多个命令
 func Pipeline() redis.Pipeliner {
-	return Client.Pipeline()
+	return GetClient().Pipeline()
 }
 
 // Pipelined 在管道中执行命令
@@ -534,7 +678,7 @@ func Pipelined(fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Pipelined(ctx, fn)
+	return GetClient().Pipelined(ctx, fn)
 }
 
 // 地理位置操作
@@ -544,7 +688,7 @@ func GeoAdd(key string, geoLocation ...*redis.GeoLocation) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.GeoAdd(ctx, key, geoLocation...).Result()
+	return GetClient().GeoAdd(ctx, key, geoLocation...).Result()
 }
 
 // GeoPos 从key里返回所有给定位置元素的位置（经度和纬度）
@@ -552,7 +696,7 @@ func GeoPos(key string, members ...string) ([]*redis.GeoPos, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.GeoPos(ctx, key, members...).Result()
+	return GetClient().GeoPos(ctx, key, members...).Result()
 }
 
 // GeoDist 返回两个给定位置之间的距离
@@ -560,7 +704,7 @@ func GeoDist(key string, member1, member2, unit string) (float64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.GeoDist(ctx, key, member1, member2, unit).Result()
+	return GetClient().GeoDist(ctx, key, member1, member2, unit).Result()
 }
 
 // GeoRadius 以给定的经纬度为中心， 返回键包含的位置元素当中， 与中心的距离不超过给定最大距离的所有位置元素
@@ -568,7 +712,7 @@ func GeoRadius(key string, longitude, latitude float64, query *redis.GeoRadiusQu
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.GeoRadius(ctx, key, longitude, latitude, query).Result()
+	return GetClient().GeoRadius(ctx, key, longitude, latitude, query).Result()
 }
 
 // GeoRadiusByMember 以给定的成员为中心， 返回键包含的位置元素当中， 与中心的距离不超过给定最大距离的所有位置元素
@@ -576,7 +720,7 @@ func GeoRadiusByMember(key, member string, query *redis.GeoRadiusQuery) ([]redis
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.GeoRadiusByMember(ctx, key, member, query).Result()
+	return GetClient().GeoRadiusByMember(ctx, key, member, query).Result()
 }
 
 // HyperLogLog操作
@@ -586,7 +730,7 @@ func PFAdd(key string, els ...interface{}) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.PFAdd(ctx, key, els...).Result()
+	return GetClient().PFAdd(ctx, key, els...).Result()
 }
 
 // PFCount 返回给定HyperLogLog的基数估算值
@@ -594,7 +738,7 @@ func PFCount(keys ...string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.PFCount(ctx, keys...).Result()
+	return GetClient().PFCount(ctx, keys...).Result()
 }
 
 // PFMerge 将多个HyperLogLog合并为一个HyperLogLog
@@ -602,7 +746,7 @@ func PFMerge(dest string, keys ...string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.PFMerge(ctx, dest, keys...).Result()
+	return GetClient().PFMerge(ctx, dest, keys...).Result()
 }
 
 // 脚本执行
@@ -612,7 +756,10 @@ func Eval(script string, keys []string, args ...interface{}) (interface{}, error
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Eval(ctx, script, keys, args...).Result()
+	if err := checkSameSlot(keys...); err != nil {
+		return nil, err
+	}
+	return GetClient().Eval(ctx, script, keys, args...).Result()
 }
 
 // EvalSha 执行Lua脚本（通过SHA1校验和）
@@ -620,7 +767,10 @@ func EvalSha(sha1 string, keys []string, args ...interface{}) (interface{}, erro
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.EvalSha(ctx, sha1, keys, args...).Result()
+	if err := checkSameSlot(keys...); err != nil {
+		return nil, err
+	}
+	return GetClient().EvalSha(ctx, sha1, keys, args...).Result()
 }
 
 // ScriptLoad 将脚本加载到脚本缓存中
@@ -628,7 +778,7 @@ func ScriptLoad(script string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ScriptLoad(ctx, script).Result()
+	return GetClient().ScriptLoad(ctx, script).Result()
 }
 
 // ScriptExists 检查脚本是否已经被保存在缓存中
@@ -636,7 +786,7 @@ func ScriptExists(scripts ...string) ([]bool, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ScriptExists(ctx, scripts...).Result()
+	return GetClient().ScriptExists(ctx, scripts...).Result()
 }
 
 // ScriptFlush 从脚本缓存中移除所有脚本
@@ -644,7 +794,7 @@ func ScriptFlush() (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ScriptFlush(ctx).Result()
+	return GetClient().ScriptFlush(ctx).Result()
 }
 
 // 位操作
@@ -657,16 +807,16 @@ func BitOp(op string, destKey string, keys ...string) (int64, error) {
 	// 根据操作类型调用相应的方法
 	switch op {
 	case "AND", "and":
-		return Client.BitOpAnd(ctx, destKey, keys...).Result()
+		return GetClient().BitOpAnd(ctx, destKey, keys...).Result()
 	case "OR", "or":
-		return Client.BitOpOr(ctx, destKey, keys...).Result()
+		return GetClient().BitOpOr(ctx, destKey, keys...).Result()
 	case "XOR", "xor":
-		return Client.BitOpXor(ctx, destKey, keys...).Result()
+		return GetClient().BitOpXor(ctx, destKey, keys...).Result()
 	case "NOT", "not":
 		if len(keys) != 1 {
 			return 0, ErrInvalidBitOpParams
 		}
-		return Client.BitOpNot(ctx, destKey, keys[0]).Result()
+		return GetClient().BitOpNot(ctx, destKey, keys[0]).Result()
 	default:
 		return 0, ErrInvalidBitOp
 	}
@@ -677,7 +827,7 @@ func BitPos(key string, bit int64, pos ...int64) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.BitPos(ctx, key, bit, pos...).Result()
+	return GetClient().BitPos(ctx, key, bit, pos...).Result()
 }
 
 // 流操作
@@ -687,7 +837,7 @@ func XAdd(a *redis.XAddArgs) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XAdd(ctx, a).Result()
+	return GetClient().XAdd(ctx, a).Result()
 }
 
 // XDel 从流中删除消息
@@ -695,7 +845,7 @@ func XDel(stream string, ids ...string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XDel(ctx, stream, ids...).Result()
+	return GetClient().XDel(ctx, stream, ids...).Result()
 }
 
 // XLen 获取流包含的元素数量
@@ -703,7 +853,7 @@ func XLen(stream string) (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XLen(ctx, stream).Result()
+	return GetClient().XLen(ctx, stream).Result()
 }
 
 // XRange 获取流中的消息范围
@@ -711,7 +861,15 @@ func XRange(stream, start, stop string) ([]redis.XMessage, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XRange(ctx, stream, start, stop).Result()
+	return GetClient().XRange(ctx, stream, start, stop).Result()
+}
+
+// XRangeN 获取流中的消息范围，最多返回count条
+func XRangeN(stream, start, stop string, count int64) ([]redis.XMessage, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().XRangeN(ctx, stream, start, stop, count).Result()
 }
 
 // XRevRange 反向获取流中的消息范围
@@ -719,7 +877,15 @@ func XRevRange(stream, start, stop string) ([]redis.XMessage, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XRevRange(ctx, stream, start, stop).Result()
+	return GetClient().XRevRange(ctx, stream, start, stop).Result()
+}
+
+// XTrimMinID 删除流中ID小于minID的消息
+func XTrimMinID(stream, minID string) (int64, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().XTrimMinID(ctx, stream, minID).Result()
 }
 
 // XRead 从流中读取数据
@@ -727,7 +893,10 @@ func XRead(a *redis.XReadArgs) ([]redis.XStream, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XRead(ctx, a).Result()
+	if err := checkSameSlot(a.Streams[:len(a.Streams)/2]...); err != nil {
+		return nil, err
+	}
+	return GetClient().XRead(ctx, a).Result()
 }
 
 // XGroupCreate 创建消费者组
@@ -735,15 +904,43 @@ func XGroupCreate(stream, group, start string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XGroupCreate(ctx, stream, group, start).Result()
+	return GetClient().XGroupCreate(ctx, stream, group, start).Result()
 }
 
-// XReadGroup 读取消费者组中的消息
+// XGroupCreateMkStream 创建消费者组，流不存在时自动创建
+func XGroupCreateMkStream(stream, group, start string) (string, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().XGroupCreateMkStream(ctx, stream, group, start).Result()
+}
+
+// XReadGroup 读取消费者组中的消息，阻塞读取时Block需小于默认超时时间
 func XReadGroup(a *redis.XReadGroupArgs) ([]redis.XStream, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.XReadGroup(ctx, a).Result()
+	if err := checkSameSlot(a.Streams[:len(a.Streams)/2]...); err != nil {
+		return nil, err
+	}
+	return GetClient().XReadGroup(ctx, a).Result()
+}
+
+// XAck 确认消费者组中的消息已处理
+func XAck(stream, group string, ids ...string) (int64, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().XAck(ctx, stream, group, ids...).Result()
+}
+
+// XAutoClaim 将消费者组中空闲时间超过MinIdle的未确认消息转移给指定消费者
+// 返回: 转移的消息和下一次扫描的起始ID
+func XAutoClaim(a *redis.XAutoClaimArgs) ([]redis.XMessage, string, error) {
+	ctx, cancel := getContext()
+	defer cancel()
+
+	return GetClient().XAutoClaim(ctx, a).Result()
 }
 
 // 集群操作
@@ -753,25 +950,39 @@ func ClusterSlots() ([]redis.ClusterSlot, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.ClusterSlots(ctx).Result()
+	return GetClient().ClusterSlots(ctx).Result()
 }
 
 // 其他实用命令
 
 // FlushDB 清空当前数据库中的所有key
+// 集群模式下清空所有主节点
 func FlushDB() (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.FlushDB(ctx).Result()
+	if cluster, ok := GetClient().(*redis.ClusterClient); ok {
+		return "OK", cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return node.FlushDB(ctx).Err()
+		})
+	}
+
+	return GetClient().FlushDB(ctx).Result()
 }
 
 // FlushAll 清空整个 Redis 服务器的数据
+// 集群模式下清空所有主节点
 func FlushAll() (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.FlushAll(ctx).Result()
+	if cluster, ok := GetClient().(*redis.ClusterClient); ok {
+		return "OK", cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return node.FlushAll(ctx).Err()
+		})
+	}
+
+	return GetClient().FlushAll(ctx).Result()
 }
 
 // Time 返回当前服务器时间
@@ -779,15 +990,32 @@ func Time() (time.Time, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Time(ctx).Result()
+	return GetClient().Time(ctx).Result()
 }
 
 // DBSize 返回当前数据库的key数量
+// 集群模式下返回所有主节点的key数量之和
 func DBSize() (int64, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.DBSize(ctx).Result()
+	if cluster, ok := GetClient().(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		var total int64
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			size, err := node.DBSize(ctx).Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			total += size
+			mu.Unlock()
+			return nil
+		})
+		return total, err
+	}
+
+	return GetClient().DBSize(ctx).Result()
 }
 
 // Info 获取Redis服务器的各种信息和统计数值
@@ -795,5 +1023,5 @@ func Info(section ...string) (string, error) {
 	ctx, cancel := getContext()
 	defer cancel()
 
-	return Client.Info(ctx, section...).Result()
+	return GetClient().Info(ctx, section...).Result()
 }
@@ -0,0 +1,146 @@
+package redis
+
+import "time"
+
+// Store Redis存储接口
+// 封装业务中常用的Redis操作，便于在服务中注入并在测试中替换为内存实现
+type Store interface {
+	// 字符串操作
+	Set(key string, value interface{}, expiration time.Duration) error
+	SetNX(key string, value interface{}, expiration time.Duration) (bool, error)
+	Get(key string) (string, error)
+	GetObj(key string, obj interface{}) error
+	SetObj(key string, obj interface{}, expiration time.Duration) error
+	Incr(key string) (int64, error)
+
+	// 键操作
+	Del(keys ...string) (int64, error)
+	Exists(keys ...string) (int64, error)
+	Expire(key string, expiration time.Duration) (bool, error)
+	TTL(key string) (time.Duration, error)
+
+	// 哈希表操作
+	HSet(key string, values ...interface{}) (int64, error)
+	HGet(key, field string) (string, error)
+	HGetAll(key string) (map[string]string, error)
+	HIncrBy(key, field string, incr int64) (int64, error)
+	HDel(key string, fields ...string) (int64, error)
+
+	// 集合操作
+	SAdd(key string, members ...interface{}) (int64, error)
+	SMembers(key string) ([]string, error)
+	SRem(key string, members ...interface{}) (int64, error)
+
+	// HyperLogLog操作
+	PFAdd(key string, els ...interface{}) (int64, error)
+	PFCount(keys ...string) (int64, error)
+}
+
+// clientStore 基于全局Redis客户端的存储实现
+type clientStore struct{}
+
+// NewStore 创建基于全局Redis客户端的存储实例
+// 需要先调用Init初始化Redis连接
+func NewStore() Store {
+	return clientStore{}
+}
+
+// Set 设置键值对并指定过期时间
+func (clientStore) Set(key string, value interface{}, expiration time.Duration) error {
+	return Set(key, value, expiration)
+}
+
+// SetNX 当键不存在时设置键值对并指定过期时间，常用于实现分布式锁
+func (clientStore) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return SetNX(key, value, expiration)
+}
+
+// Get 获取字符串类型的键值
+func (clientStore) Get(key string) (string, error) {
+	return Get(key)
+}
+
+// GetObj 获取JSON对象并反序列化到指定结构
+func (clientStore) GetObj(key string, obj interface{}) error {
+	return GetObj(key, obj)
+}
+
+// SetObj 设置对象（序列化后存储）
+func (clientStore) SetObj(key string, obj interface{}, expiration time.Duration) error {
+	return SetObj(key, obj, expiration)
+}
+
+// Incr 将 key 中储存的数字值增一
+func (clientStore) Incr(key string) (int64, error) {
+	return Incr(key)
+}
+
+// Del 删除键
+func (clientStore) Del(keys ...string) (int64, error) {
+	return Del(keys...)
+}
+
+// Exists 检查键是否存在
+func (clientStore) Exists(keys ...string) (int64, error) {
+	return Exists(keys...)
+}
+
+// Expire 设置过期时间
+func (clientStore) Expire(key string, expiration time.Duration) (bool, error) {
+	return Expire(key, expiration)
+}
+
+// TTL 返回键的剩余生存时间
+func (clientStore) TTL(key string) (time.Duration, error) {
+	return TTL(key)
+}
+
+// HSet 设置哈希表字段
+func (clientStore) HSet(key string, values ...interface{}) (int64, error) {
+	return HSet(key, values...)
+}
+
+// HGet 获取哈希表字段
+func (clientStore) HGet(key, field string) (string, error) {
+	return HGet(key, field)
+}
+
+// HGetAll 获取哈希表所有字段和值
+func (clientStore) HGetAll(key string) (map[string]string, error) {
+	return HGetAll(key)
+}
+
+// HIncrBy 为哈希表字段的整数值加上增量
+func (clientStore) HIncrBy(key, field string, incr int64) (int64, error) {
+	return HIncrBy(key, field, incr)
+}
+
+// HDel 删除哈希表字段
+func (clientStore) HDel(key string, fields ...string) (int64, error) {
+	return HDel(key, fields...)
+}
+
+// SAdd 向集合添加一个或多个成员
+func (clientStore) SAdd(key string, members ...interface{}) (int64, error) {
+	return SAdd(key, members...)
+}
+
+// SMembers 获取集合所有成员
+func (clientStore) SMembers(key string) ([]string, error) {
+	return SMembers(key)
+}
+
+// SRem 移除集合中一个或多个成员
+func (clientStore) SRem(key string, members ...interface{}) (int64, error) {
+	return SRem(key, members...)
+}
+
+// PFAdd 将任意数量的元素添加到指定的HyperLogLog中
+func (clientStore) PFAdd(key string, els ...interface{}) (int64, error) {
+	return PFAdd(key, els...)
+}
+
+// PFCount 返回给定HyperLogLog的基数估算值
+func (clientStore) PFCount(keys ...string) (int64, error) {
+	return PFCount(keys...)
+}
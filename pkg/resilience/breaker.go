@@ -0,0 +1,199 @@
+// Package resilience 提供调用第三方服务时的熔断和重试
+// 连续失败达到阈值后熔断，熔断期间直接返回错误而不再请求服务，熔断到期后放行一个试探请求，成功后恢复
+package resilience
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 服务熔断中，请求未发出
+var ErrCircuitOpen = errors.New("服务熔断中")
+
+// State 熔断器状态
+type State int
+
+// 熔断器状态
+const (
+	StateClosed   State = iota // 正常放行请求
+	StateOpen                  // 熔断中，拒绝所有请求
+	StateHalfOpen              // 熔断到期，只放行一个试探请求
+)
+
+// String 返回状态名称
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig 熔断器配置
+type BreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后熔断
+	OpenTimeout      time.Duration // 熔断持续时间，到期后放行一个试探请求
+}
+
+// Breaker 熔断器，并发安全
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	state    State
+	failures int       // 连续失败次数
+	openedAt time.Time // 最近一次熔断的时间
+	probing  bool      // 半开状态下是否已放行试探请求
+	stats    Stats
+}
+
+// Stats 熔断器的累计统计
+type Stats struct {
+	Successes uint64 // 成功的请求次数
+	Failures  uint64 // 失败的请求次数，不含请求本身错误导致的失败
+	Rejected  uint64 // 熔断期间被拒绝的请求次数
+	Retries   uint64 // 重试次数
+	Fallbacks uint64 // 请求失败或被拒绝时使用降级结果的次数
+	Opens     uint64 // 熔断次数
+}
+
+// 已注册的熔断器，key为名称
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*Breaker)
+)
+
+// Register 获取指定名称的熔断器，不存在时按配置创建
+// 同一依赖服务的多个客户端共用一个熔断器，只有首次创建时的配置生效
+func Register(name string, cfg BreakerConfig) *Breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	if b, ok := breakers[name]; ok {
+		return b
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	b := &Breaker{name: name, cfg: cfg}
+	breakers[name] = b
+	return b
+}
+
+// registered 返回所有已注册的熔断器，按名称排序
+func registered() []*Breaker {
+	breakersMu.Lock()
+	list := make([]*Breaker, 0, len(breakers))
+	for _, b := range breakers {
+		list = append(list, b)
+	}
+	breakersMu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+// Name 返回熔断器名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State 返回熔断器当前状态，熔断已到期时返回半开状态
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Stats 返回累计统计的副本
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// Allow 检查是否放行请求，熔断中返回ErrCircuitOpen
+// 放行的请求必须随后调用Success或Failure报告结果
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = StateHalfOpen
+		b.probing = false
+	}
+	switch b.state {
+	case StateOpen:
+		b.stats.Rejected++
+		return ErrCircuitOpen
+	case StateHalfOpen:
+		if b.probing {
+			b.stats.Rejected++
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Ready 检查熔断器是否处于熔断中，不占用试探请求的名额，也不需要报告结果
+func (b *Breaker) Ready() bool {
+	return b.State() != StateOpen
+}
+
+// Success 报告请求成功，半开状态下恢复正常
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stats.Successes++
+	b.failures = 0
+	b.state = StateClosed
+	b.probing = false
+}
+
+// Failure 报告请求失败，连续失败达到阈值或试探请求失败时熔断
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stats.Failures++
+	b.failures++
+	if b.state == StateHalfOpen || (b.state == StateClosed && b.failures >= b.cfg.FailureThreshold) {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		b.probing = false
+		b.stats.Opens++
+	}
+}
+
+// Release 报告请求因请求本身的错误（如对象不存在、调用方取消）结束，不影响熔断状态
+// 半开状态下释放试探请求的名额
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// Fallback 记录一次降级
+func (b *Breaker) Fallback() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stats.Fallbacks++
+}
+
+// retried 记录一次重试
+func (b *Breaker) retried() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stats.Retries++
+}
@@ -0,0 +1,28 @@
+package resilience
+
+import (
+	"io"
+
+	"app/pkg/metrics"
+)
+
+// WriteMetrics 以Prometheus文本格式输出所有熔断器的状态和累计统计
+func WriteMetrics(w io.Writer) error {
+	state := &metrics.Family{Name: "resilience_breaker_state", Help: "熔断器状态（0-正常，1-熔断中，2-半开）", Type: metrics.Gauge}
+	calls := &metrics.Family{Name: "resilience_calls_total", Help: "按结果统计的请求次数", Type: metrics.Counter}
+	retries := &metrics.Family{Name: "resilience_retries_total", Help: "重试次数", Type: metrics.Counter}
+	fallbacks := &metrics.Family{Name: "resilience_fallbacks_total", Help: "请求失败或被拒绝时使用降级结果的次数", Type: metrics.Counter}
+	opens := &metrics.Family{Name: "resilience_opens_total", Help: "熔断次数", Type: metrics.Counter}
+
+	for _, b := range registered() {
+		s := b.Stats()
+		state.Add(float64(b.State()), "breaker", b.name)
+		calls.Add(float64(s.Successes), "breaker", b.name, "result", "success")
+		calls.Add(float64(s.Failures), "breaker", b.name, "result", "failure")
+		calls.Add(float64(s.Rejected), "breaker", b.name, "result", "rejected")
+		retries.Add(float64(s.Retries), "breaker", b.name)
+		fallbacks.Add(float64(s.Fallbacks), "breaker", b.name)
+		opens.Add(float64(s.Opens), "breaker", b.name)
+	}
+	return metrics.Write(w, state, calls, retries, fallbacks, opens)
+}
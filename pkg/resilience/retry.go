@@ -0,0 +1,102 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Policy 单次调用的超时和重试策略
+type Policy struct {
+	Timeout       time.Duration // 每次请求的超时，为0时不限制
+	MaxRetries    int           // 失败后的最大重试次数，为0时不重试
+	RetryInterval time.Duration // 首次重试间隔，之后每次翻倍并加入随机抖动
+}
+
+// Call 一次通过熔断器的调用
+type Call struct {
+	Policy Policy
+	// Retryable 调用是否可以安全重试，只有幂等操作可以重试
+	Retryable bool
+	// IsFailure 判断错误是否为服务故障，返回false的错误（如对象不存在）不计入熔断且不重试
+	// 为nil时除调用方取消外的所有错误都视为服务故障
+	IsFailure func(error) bool
+}
+
+// Do 通过熔断器执行fn，每次请求使用独立的超时
+// 服务故障且调用可重试时按策略重试，每次重试前重新检查熔断状态；熔断中返回ErrCircuitOpen
+func (b *Breaker) Do(ctx context.Context, call Call, fn func(ctx context.Context) error) error {
+	interval := call.Policy.RetryInterval
+	for attempt := 0; ; attempt++ {
+		if err := b.Allow(); err != nil {
+			return err
+		}
+		if attempt > 0 {
+			b.retried()
+		}
+
+		err := b.attempt(ctx, call.Policy.Timeout, fn)
+		if err == nil {
+			b.Success()
+			return nil
+		}
+		if !call.isFailure(ctx, err) {
+			b.Release()
+			return err
+		}
+		b.Failure()
+
+		if !call.Retryable || attempt >= call.Policy.MaxRetries {
+			return err
+		}
+		if waitErr := sleep(ctx, jitter(interval)); waitErr != nil {
+			return err
+		}
+		interval *= 2
+	}
+}
+
+// attempt 执行一次请求
+func (b *Breaker) attempt(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return fn(attemptCtx)
+}
+
+// isFailure 判断错误是否为服务故障，调用方取消或超时导致的错误不视为服务故障
+func (c Call) isFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if c.IsFailure != nil {
+		return c.IsFailure(err)
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// jitter 在间隔基础上加入最多一半的随机抖动，避免多个请求同时重试
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// sleep 等待指定时长，上下文取消时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package response
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"app/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WeakETag 根据资源的版本信息生成弱ETag，响应语言会一并参与计算，
+// 因此同一资源在不同语言下的缓存互不影响
+func WeakETag(c *gin.Context, parts ...interface{}) string {
+	h := sha1.New()
+	fmt.Fprint(h, c.GetString(i18n.ContextKey))
+	for _, part := range parts {
+		fmt.Fprintf(h, "|%v", part)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:12]) + `"`
+}
+
+// NotModified 设置ETag和缓存头，若请求的If-None-Match与ETag匹配则返回304并返回true，
+// 调用方此时不应再写入响应体
+func NotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	// 响应内容因用户和语言而异，只允许客户端私有缓存，且每次使用前需要重新验证
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Vary", "Authorization, Accept-Language")
+
+	if !etagMatch(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	c.Abort()
+	return true
+}
+
+// etagMatch 按弱比较规则判断If-None-Match中是否包含指定ETag
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,13 @@
 // Package response 提供统一的HTTP响应处理功能，确保API返回标准格式的响应。
+// 响应消息和错误详情会按请求协商的语言翻译，见 pkg/i18n。
 package response
 
 import (
 	"net/http"
 	"time"
 
+	"app/pkg/i18n"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,14 +36,30 @@ func NewResponse(statusCode int, message string, data interface{}, err error) Re
 	return resp
 }
 
+// localize 将响应消息和错误详情翻译为请求上下文中的语言，未经过语言协商中间件时保持原文
+func localize(c *gin.Context, resp Response) Response {
+	lang := c.GetString(i18n.ContextKey)
+	if lang == "" {
+		return resp
+	}
+	resp.Message = i18n.T(lang, resp.Message)
+	resp.Error = i18n.TranslateError(lang, resp.Error)
+	return resp
+}
+
 // Success 返回HTTP 200成功响应
 func Success(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, NewResponse(http.StatusOK, message, data, nil))
+	c.JSON(http.StatusOK, localize(c, NewResponse(http.StatusOK, message, data, nil)))
 }
 
 // Fail 返回指定HTTP状态码的失败响应
 func Fail(c *gin.Context, statusCode int, message string, err error) {
-	c.JSON(statusCode, NewResponse(statusCode, message, nil, err))
+	c.JSON(statusCode, localize(c, NewResponse(statusCode, message, nil, err)))
+}
+
+// FailWithData 返回指定HTTP状态码的失败响应，并附带失败详情数据
+func FailWithData(c *gin.Context, statusCode int, message string, data interface{}, err error) {
+	c.JSON(statusCode, localize(c, NewResponse(statusCode, message, data, err)))
 }
 
 // BadRequest 返回400错误（请求参数错误）
@@ -63,6 +82,11 @@ func NotFound(c *gin.Context, message string, err error) {
 	Fail(c, http.StatusNotFound, message, err)
 }
 
+// TooManyRequests 返回429错误（请求过于频繁）
+func TooManyRequests(c *gin.Context, message string, err error) {
+	Fail(c, http.StatusTooManyRequests, message, err)
+}
+
 // InternalServerError 返回500错误（服务器内部错误）
 func InternalServerError(c *gin.Context, message string, err error) {
 	Fail(c, http.StatusInternalServerError, message, err)
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamList 以统一响应格式分批输出列表，data为{"list":[...]}，每批写入后立即刷新，整个列表不会缓存在内存中
+// next每次返回下一批元素，返回空批次表示列表结束
+// 读取第一批失败时尚未输出任何内容，返回的错误由调用方按普通错误响应处理；之后失败时响应已不完整，调用方应中断连接
+func StreamList[T any](c *gin.Context, message string, next func() ([]T, error)) error {
+	items, err := next()
+	if err != nil {
+		return err
+	}
+
+	resp := localize(c, NewResponse(http.StatusOK, message, nil, nil))
+	msg, err := json.Marshal(resp.Message)
+	if err != nil {
+		return err
+	}
+
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := c.Writer
+	if _, err := w.WriteString(`{"code":200,"message":` + string(msg) + `,"data":{"list":[`); err != nil {
+		return err
+	}
+
+	first := true
+	for len(items) > 0 {
+		for _, item := range items {
+			data, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if !first {
+				data = append([]byte{','}, data...)
+			}
+			first = false
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+
+		if items, err = next(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.WriteString(`]},"timestamp":` + strconv.FormatInt(resp.Timestamp, 10) + `}`); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
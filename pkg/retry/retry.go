@@ -0,0 +1,139 @@
+// Package retry 提供依赖服务连接的重试和健康检查
+// 启动时按指数退避等待数据库、Redis等依赖就绪，运行期间定期检查连接并在持续失败时重建连接
+package retry
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"app/config"
+)
+
+// 默认重试参数
+const (
+	defaultInitialInterval     = time.Second
+	defaultMaxInterval         = 30 * time.Second
+	defaultMaxWait             = time.Minute
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultReconnectAfter      = 3
+)
+
+// Policy 连接重试策略
+type Policy struct {
+	InitialInterval     time.Duration // 首次重试间隔，之后每次翻倍
+	MaxInterval         time.Duration // 最大重试间隔
+	MaxWait             time.Duration // 等待依赖就绪的最长时间，为0时不重试
+	HealthCheckInterval time.Duration // 运行期间检查连接的间隔，为0时不检查
+	ReconnectAfter      int           // 连续检查失败达到该次数后重建连接
+}
+
+// NewPolicy 根据配置创建重试策略，未配置的项使用默认值，显式配置为0的时长表示关闭对应功能
+func NewPolicy(cfg config.ConnectRetryConfig) Policy {
+	policy := Policy{
+		InitialInterval:     config.ParseDuration(cfg.InitialInterval, defaultInitialInterval),
+		MaxInterval:         config.ParseDuration(cfg.MaxInterval, defaultMaxInterval),
+		MaxWait:             config.ParseDuration(cfg.MaxWait, defaultMaxWait),
+		HealthCheckInterval: config.ParseDuration(cfg.HealthCheckInterval, defaultHealthCheckInterval),
+		ReconnectAfter:      cfg.ReconnectAfter,
+	}
+	if policy.InitialInterval <= 0 {
+		policy.InitialInterval = defaultInitialInterval
+	}
+	if policy.MaxInterval < policy.InitialInterval {
+		policy.MaxInterval = policy.InitialInterval
+	}
+	if policy.ReconnectAfter <= 0 {
+		policy.ReconnectAfter = defaultReconnectAfter
+	}
+	return policy
+}
+
+// Do 执行连接函数，失败时按指数退避重试，直到成功或超过最长等待时间
+// 参数: name - 依赖名称，用于日志, policy - 重试策略, connect - 连接函数
+// 返回: 最后一次连接的错误
+func Do(name string, policy Policy, connect func() error) error {
+	deadline := time.Now().Add(policy.MaxWait)
+	interval := policy.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil {
+			if attempt > 1 {
+				log.Printf("%s连接成功，共尝试%d次", name, attempt)
+			}
+			return nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return err
+		}
+		log.Printf("%s连接失败，%s后重试（第%d次）: %v", name, interval, attempt, err)
+		time.Sleep(interval)
+
+		interval *= 2
+		if interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+}
+
+// Monitor 连接健康检查器
+type Monitor struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+// StartMonitor 启动连接健康检查，按策略的间隔执行检查，连续失败达到阈值后重建连接
+// 重建失败时继续检查，下一次达到阈值后再次重建
+// 参数: name - 依赖名称，用于日志, policy - 重试策略, check - 检查函数, reconnect - 重建连接函数
+// 返回: 健康检查器，未配置检查间隔时返回nil
+func StartMonitor(name string, policy Policy, check func() error, reconnect func() error) *Monitor {
+	if policy.HealthCheckInterval <= 0 {
+		return nil
+	}
+
+	m := &Monitor{stop: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(policy.HealthCheckInterval)
+		defer ticker.Stop()
+
+		failures := 0
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+
+			err := check()
+			if err == nil {
+				failures = 0
+				continue
+			}
+			failures++
+			log.Printf("%s健康检查失败（连续%d次）: %v", name, failures, err)
+
+			if failures < policy.ReconnectAfter {
+				continue
+			}
+			if err = reconnect(); err != nil {
+				log.Printf("%s重建连接失败: %v", name, err)
+			} else {
+				log.Printf("%s已重建连接", name)
+			}
+			failures = 0
+		}
+	}()
+	return m
+}
+
+// Stop 停止健康检查，可重复调用
+func (m *Monitor) Stop() {
+	if m == nil {
+		return
+	}
+	m.once.Do(func() {
+		close(m.stop)
+	})
+}
@@ -0,0 +1,502 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// 告警规则
+const (
+	// AlertRuleConsecutiveFailures 连续失败次数
+	AlertRuleConsecutiveFailures = "consecutive_failures"
+	// AlertRuleMaxDuration 执行耗时
+	AlertRuleMaxDuration = "max_duration"
+	// AlertRuleMissedRuns 错过执行次数
+	AlertRuleMissedRuns = "missed_runs"
+)
+
+// AlertRules 所有告警规则
+var AlertRules = []string{AlertRuleConsecutiveFailures, AlertRuleMaxDuration, AlertRuleMissedRuns}
+
+// 告警状态
+const (
+	// AlertStatusFiring 告警中
+	AlertStatusFiring = "firing"
+	// AlertStatusResolved 已恢复
+	AlertStatusResolved = "resolved"
+)
+
+// 告警相关常量
+const (
+	// alertKeyType 告警状态的Redis键类型，哈希表，字段为"任务/规则"
+	alertKeyType = "alert"
+	// silenceKeyType 静默规则的Redis键类型，哈希表，字段为静默规则ID
+	silenceKeyType = "silence"
+	// defaultAlertInterval 未指定时的告警检查间隔
+	defaultAlertInterval = time.Minute
+	// defaultMissedRunGrace 任务未设置超时时间时，计划执行时间经过该时长后仍未成功才计为错过
+	defaultMissedRunGrace = 5 * time.Minute
+)
+
+// 告警相关错误
+var (
+	ErrAlertStoreUnavailable = errors.New("Redis客户端未初始化，无法读取告警状态")
+	ErrInvalidSilence        = errors.New("静默规则无效")
+	ErrSilenceNotFound       = errors.New("静默规则不存在")
+)
+
+// SLO 任务服务等级目标，零值字段不检查
+type SLO struct {
+	MaxConsecutiveFailures int           // 连续失败（含超时）达到该次数时告警
+	MaxDuration            time.Duration // 最近一次执行耗时超过该时长时告警
+	MaxMissedRuns          int           // 自最近一次成功执行后错过的执行达到该次数时告警
+}
+
+// enabled 是否设置了任一目标
+func (o SLO) enabled() bool {
+	return o.MaxConsecutiveFailures > 0 || o.MaxDuration > 0 || o.MaxMissedRuns > 0
+}
+
+// Alert 任务SLO告警
+type Alert struct {
+	Task      string     `json:"task"`              // 任务名称
+	Rule      string     `json:"rule"`              // 告警规则
+	Status    string     `json:"status"`            // 告警状态：firing、resolved
+	Value     float64    `json:"value"`             // 当前值，耗时以秒为单位
+	Threshold float64    `json:"threshold"`         // 阈值，耗时以秒为单位
+	StartsAt  time.Time  `json:"starts_at"`         // 开始告警时间
+	EndsAt    *time.Time `json:"ends_at,omitempty"` // 恢复时间
+	Silenced  bool       `json:"silenced"`          // 当前是否被静默
+	Notified  bool       `json:"notified"`          // 是否已发送告警通知，已通知的告警恢复时发送恢复通知
+}
+
+// field 告警状态在哈希表中的字段
+func (a Alert) field() string {
+	return a.Task + "/" + a.Rule
+}
+
+// AlertNotifier 告警通知接口，告警开始和恢复时调用
+type AlertNotifier interface {
+	// Notify 发送告警通知，返回错误时告警通知将在下次检查时重试
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// Silence 静默规则，生效期间匹配的告警不发送通知，告警状态仍正常记录
+type Silence struct {
+	ID        string    `json:"id"`         // 静默规则ID
+	Tasks     []string  `json:"tasks"`      // 匹配的任务，为空时匹配所有任务
+	Rules     []string  `json:"rules"`      // 匹配的告警规则，为空时匹配所有规则
+	StartsAt  time.Time `json:"starts_at"`  // 生效时间
+	EndsAt    time.Time `json:"ends_at"`    // 失效时间
+	Comment   string    `json:"comment"`    // 静默原因
+	CreatedBy string    `json:"created_by"` // 创建者
+	CreatedAt time.Time `json:"created_at"` // 创建时间
+}
+
+// Matches 静默规则在指定时间是否匹配告警
+func (sl Silence) Matches(task, rule string, now time.Time) bool {
+	if now.Before(sl.StartsAt) || !now.Before(sl.EndsAt) {
+		return false
+	}
+	if len(sl.Tasks) > 0 && !slices.Contains(sl.Tasks, task) {
+		return false
+	}
+	return len(sl.Rules) == 0 || slices.Contains(sl.Rules, rule)
+}
+
+// WithAlerting 启用任务SLO告警检查
+// 按注册选项中的SLO定期检查各任务，告警开始和恢复时调用notifier发送通知，notifier为nil时只记录告警状态
+// interval为0时使用默认值；选主模式下只有主节点检查，启用分布式锁时同一时刻只有一个节点检查
+func WithAlerting(notifier AlertNotifier, interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.alerting = true
+		s.alertNotifier = notifier
+		s.alertInterval = interval
+		if s.alertInterval <= 0 {
+			s.alertInterval = defaultAlertInterval
+		}
+	}
+}
+
+// startAlerting 在后台定期检查任务SLO
+func (s *Scheduler) startAlerting() {
+	if !s.alerting {
+		return
+	}
+	s.alertStop = make(chan struct{})
+	s.alertDone = make(chan struct{})
+
+	go func() {
+		defer close(s.alertDone)
+		ticker := time.NewTicker(s.alertInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.alertStop:
+				return
+			case <-ticker.C:
+				s.runAlertCheck()
+			}
+		}
+	}()
+}
+
+// stopAlerting 停止检查任务SLO
+func (s *Scheduler) stopAlerting() {
+	if s.alertStop == nil {
+		return
+	}
+	close(s.alertStop)
+	<-s.alertDone
+}
+
+// runAlertCheck 执行一次告警检查，多节点部署时通过选主或分布式锁保证只有一个节点检查
+func (s *Scheduler) runAlertCheck() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.alertInterval)
+	defer cancel()
+
+	if !s.IsLeader() || redis.GetClient() == nil {
+		return
+	}
+
+	if s.redisLock {
+		lock := redis.NewLock(s.redisKey(lockKeyType, alertKeyType), s.alertInterval)
+		acquired, err := lock.TryAcquire()
+		if err != nil {
+			logger.Error(ctx, "获取告警检查锁失败", zap.Error(err))
+			return
+		}
+		if !acquired {
+			return
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				logger.Error(ctx, "释放告警检查锁失败", zap.Error(err))
+			}
+		}()
+	}
+
+	if err := s.evaluateAlerts(ctx, time.Now()); err != nil {
+		logger.Error(ctx, "检查任务SLO失败", zap.Error(err))
+	}
+}
+
+// evaluateAlerts 检查各任务SLO并更新告警状态，未通知的告警和已通知告警的恢复通过notifier发送
+func (s *Scheduler) evaluateAlerts(ctx context.Context, now time.Time) error {
+	client := redis.GetClient()
+	if client == nil {
+		return ErrAlertStoreUnavailable
+	}
+
+	current := s.checkSLOs(ctx, now)
+	previous, err := s.loadAlerts(ctx)
+	if err != nil {
+		return err
+	}
+	silences, err := s.Silences(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending, resolved, firing []Alert
+	for field, alert := range current {
+		if prev, exists := previous[field]; exists {
+			alert.StartsAt = prev.StartsAt
+			alert.Notified = prev.Notified
+		} else {
+			alert.StartsAt = now
+		}
+		alert.Silenced = silenced(silences, alert, now)
+		if !alert.Notified && !alert.Silenced {
+			pending = append(pending, alert)
+		}
+		firing = append(firing, alert)
+	}
+
+	var cleared []string
+	for field, prev := range previous {
+		if _, exists := current[field]; exists {
+			continue
+		}
+		cleared = append(cleared, field)
+		if prev.Notified && !silenced(silences, prev, now) {
+			endsAt := now
+			prev.Status = AlertStatusResolved
+			prev.EndsAt = &endsAt
+			resolved = append(resolved, prev)
+		}
+	}
+
+	// 通知成功后标记为已通知，失败时下次检查重试告警通知，恢复通知不重试
+	notifications := make([]Alert, 0, len(pending)+len(resolved))
+	notifications = append(notifications, pending...)
+	notifications = append(notifications, resolved...)
+	if len(notifications) > 0 && s.alertNotifier != nil {
+		sortAlerts(notifications)
+		if err := s.alertNotifier.Notify(ctx, notifications); err != nil {
+			logger.Error(ctx, "发送任务告警通知失败", zap.Int("alerts", len(notifications)), zap.Error(err))
+		} else {
+			for i := range firing {
+				if !firing[i].Silenced {
+					firing[i].Notified = true
+				}
+			}
+		}
+	}
+
+	key := s.redisKey(alertKeyType, "state")
+	if len(cleared) > 0 {
+		if err := client.HDel(ctx, key, cleared...).Err(); err != nil {
+			return fmt.Errorf("清除告警状态失败: %w", err)
+		}
+	}
+	if len(firing) > 0 {
+		values := make([]interface{}, 0, len(firing)*2)
+		for _, alert := range firing {
+			data, err := json.Marshal(alert)
+			if err != nil {
+				return err
+			}
+			values = append(values, alert.field(), data)
+		}
+		if err := client.HSet(ctx, key, values...).Err(); err != nil {
+			return fmt.Errorf("保存告警状态失败: %w", err)
+		}
+	}
+
+	for _, alert := range pending {
+		logger.Warn(ctx, "任务SLO告警", zap.String("task", alert.Task), zap.String("rule", alert.Rule),
+			zap.Float64("value", alert.Value), zap.Float64("threshold", alert.Threshold))
+	}
+	for _, alert := range resolved {
+		logger.Info(ctx, "任务SLO告警已恢复", zap.String("task", alert.Task), zap.String("rule", alert.Rule))
+	}
+	return nil
+}
+
+// checkSLOs 计算各任务当前违反的SLO规则，key为告警状态字段
+func (s *Scheduler) checkSLOs(ctx context.Context, now time.Time) map[string]Alert {
+	type target struct {
+		name     string
+		options  RegisterOption
+		schedule cron.Schedule
+	}
+
+	s.mu.RLock()
+	var targets []target
+	for name, options := range s.options {
+		if options.SLO.enabled() {
+			targets = append(targets, target{name: name, options: options, schedule: s.schedules[name]})
+		}
+	}
+	s.mu.RUnlock()
+
+	paused := s.paused != nil && s.paused(ctx)
+	result := make(map[string]Alert)
+	add := func(task, rule string, value, threshold float64) {
+		alert := Alert{Task: task, Rule: rule, Status: AlertStatusFiring, Value: value, Threshold: threshold}
+		result[alert.field()] = alert
+	}
+
+	for _, t := range targets {
+		slo := t.options.SLO
+
+		records, err := s.RunHistory(ctx, t.name, HistoryLimit)
+		if err != nil {
+			logger.Warn(ctx, "读取任务执行记录失败，跳过SLO检查", zap.String("task", t.name), zap.Error(err))
+			continue
+		}
+
+		if slo.MaxConsecutiveFailures > 0 {
+			if failures := consecutiveFailures(records); failures >= slo.MaxConsecutiveFailures {
+				add(t.name, AlertRuleConsecutiveFailures, float64(failures), float64(slo.MaxConsecutiveFailures))
+			}
+		}
+
+		if slo.MaxDuration > 0 && len(records) > 0 && records[0].Duration() > slo.MaxDuration {
+			add(t.name, AlertRuleMaxDuration, records[0].Duration().Seconds(), slo.MaxDuration.Seconds())
+		}
+
+		// 暂停期间非关键任务不执行，不计为错过；没有成功记录的任务视为首次部署
+		if slo.MaxMissedRuns > 0 && t.schedule != nil && (t.options.Critical || !paused) {
+			if lastSuccess, ok := s.getLastSuccess(t.name); ok {
+				missed := countMissedRuns(t.schedule, lastSuccess, now.Add(-missedRunGrace(t.options)), slo.MaxMissedRuns)
+				if missed >= slo.MaxMissedRuns {
+					add(t.name, AlertRuleMissedRuns, float64(missed), float64(slo.MaxMissedRuns))
+				}
+			}
+		}
+	}
+	return result
+}
+
+// missedRunGrace 计划执行时间经过该时长后仍未成功才计为错过，避免将正在执行的任务计为错过
+func missedRunGrace(options RegisterOption) time.Duration {
+	if options.Timeout > 0 {
+		return options.Timeout
+	}
+	if options.LockTimeout > 0 {
+		return options.LockTimeout
+	}
+	return defaultMissedRunGrace
+}
+
+// loadAlerts 读取当前告警状态，key为告警状态字段
+func (s *Scheduler) loadAlerts(ctx context.Context) (map[string]Alert, error) {
+	values, err := redis.GetClient().HGetAll(ctx, s.redisKey(alertKeyType, "state")).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取告警状态失败: %w", err)
+	}
+
+	alerts := make(map[string]Alert, len(values))
+	for field, value := range values {
+		var alert Alert
+		if err := json.Unmarshal([]byte(value), &alert); err != nil {
+			continue
+		}
+		alerts[field] = alert
+	}
+	return alerts, nil
+}
+
+// Alerts 获取告警中的任务SLO告警，按任务名称和规则排序
+func (s *Scheduler) Alerts(ctx context.Context) ([]Alert, error) {
+	if redis.GetClient() == nil {
+		return nil, ErrAlertStoreUnavailable
+	}
+
+	states, err := s.loadAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	silences, err := s.Silences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	alerts := make([]Alert, 0, len(states))
+	for _, alert := range states {
+		alert.Silenced = silenced(silences, alert, now)
+		alerts = append(alerts, alert)
+	}
+	sortAlerts(alerts)
+	return alerts, nil
+}
+
+// AddSilence 添加静默规则，开始时间为空时立即生效
+func (s *Scheduler) AddSilence(ctx context.Context, silence Silence) (*Silence, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return nil, ErrAlertStoreUnavailable
+	}
+
+	now := time.Now()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if !silence.EndsAt.After(silence.StartsAt) || !silence.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: 失效时间必须晚于生效时间和当前时间", ErrInvalidSilence)
+	}
+	for _, rule := range silence.Rules {
+		if !slices.Contains(AlertRules, rule) {
+			return nil, fmt.Errorf("%w: 不支持的告警规则 %s", ErrInvalidSilence, rule)
+		}
+	}
+
+	silence.ID = uuid.New().String()
+	silence.CreatedAt = now
+	data, err := json.Marshal(silence)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.HSet(ctx, s.redisKey(silenceKeyType, "rules"), silence.ID, data).Err(); err != nil {
+		return nil, fmt.Errorf("保存静默规则失败: %w", err)
+	}
+	return &silence, nil
+}
+
+// Silences 获取未失效的静默规则，按生效时间排序，已失效的规则在读取时删除
+func (s *Scheduler) Silences(ctx context.Context) ([]Silence, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return nil, ErrAlertStoreUnavailable
+	}
+
+	key := s.redisKey(silenceKeyType, "rules")
+	values, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取静默规则失败: %w", err)
+	}
+
+	now := time.Now()
+	silences := make([]Silence, 0, len(values))
+	var expired []string
+	for id, value := range values {
+		var silence Silence
+		if err := json.Unmarshal([]byte(value), &silence); err != nil || !silence.EndsAt.After(now) {
+			expired = append(expired, id)
+			continue
+		}
+		silences = append(silences, silence)
+	}
+	if len(expired) > 0 {
+		if err := client.HDel(ctx, key, expired...).Err(); err != nil {
+			logger.Warn(ctx, "删除已失效的静默规则失败", zap.Error(err))
+		}
+	}
+
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].StartsAt.Before(silences[j].StartsAt)
+	})
+	return silences, nil
+}
+
+// DeleteSilence 删除静默规则
+func (s *Scheduler) DeleteSilence(ctx context.Context, id string) error {
+	client := redis.GetClient()
+	if client == nil {
+		return ErrAlertStoreUnavailable
+	}
+
+	deleted, err := client.HDel(ctx, s.redisKey(silenceKeyType, "rules"), id).Result()
+	if err != nil {
+		return fmt.Errorf("删除静默规则失败: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSilenceNotFound
+	}
+	return nil
+}
+
+// silenced 告警在指定时间是否被任一静默规则匹配
+func silenced(silences []Silence, alert Alert, now time.Time) bool {
+	for _, silence := range silences {
+		if silence.Matches(alert.Task, alert.Rule, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortAlerts 按任务名称和规则排序
+func sortAlerts(alerts []Alert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Task != alerts[j].Task {
+			return alerts[i].Task < alerts[j].Task
+		}
+		return alerts[i].Rule < alerts[j].Rule
+	})
+}
@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// 任务执行状态
+const (
+	// TaskStatusSuccess 执行成功
+	TaskStatusSuccess = "success"
+	// TaskStatusFailed 执行失败
+	TaskStatusFailed = "failed"
+	// TaskStatusTimeout 执行超时
+	TaskStatusTimeout = "timeout"
+	// TaskStatusBlocked 因依赖任务未满足被跳过
+	TaskStatusBlocked = "blocked"
+	// TaskStatusPaused 因调度器暂停被跳过
+	TaskStatusPaused = "paused"
+)
+
+// 依赖状态
+const (
+	// DependencySatisfied 依赖任务在窗口期内执行成功
+	DependencySatisfied = "satisfied"
+	// DependencyUnsatisfied 依赖任务在窗口期内未成功执行
+	DependencyUnsatisfied = "unsatisfied"
+	// DependencyFailed 依赖任务最近一次执行失败或被跳过
+	DependencyFailed = "failed"
+)
+
+// 依赖相关常量
+const (
+	// defaultDependencyWindow 默认依赖窗口期
+	defaultDependencyWindow = 24 * time.Hour
+	// lastFailureKeyType 任务最近一次失败时间的Redis键类型
+	lastFailureKeyType = "last_failure"
+)
+
+// ValidateDependencies 校验所有任务的依赖关系
+// 依赖的任务必须已注册，且依赖关系中不能存在环
+func (s *Scheduler) ValidateDependencies() error {
+	_, err := s.topologicalOrder()
+	return err
+}
+
+// topologicalOrder 按依赖关系返回任务的拓扑顺序，依赖任务排在前面
+func (s *Scheduler) topologicalOrder() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// 入度和下游任务
+	inDegree := make(map[string]int, len(s.options))
+	dependents := make(map[string][]string, len(s.options))
+	for name, options := range s.options {
+		inDegree[name] += 0
+		for _, dep := range options.DependsOn {
+			if _, exists := s.options[dep]; !exists {
+				return nil, fmt.Errorf("任务 %s 依赖的任务 %s 不存在", name, dep)
+			}
+			inDegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	// 按名称排序，保证相同依赖层级的任务顺序稳定
+	var queue []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, name)
+		}
+	}
+	sort.Strings(queue)
+
+	order := make([]string, 0, len(inDegree))
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		order = append(order, name)
+
+		next := dependents[name]
+		sort.Strings(next)
+		for _, dependent := range next {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(inDegree) {
+		return nil, fmt.Errorf("任务依赖关系存在环")
+	}
+
+	return order, nil
+}
+
+// unmetDependencies 返回任务未满足的依赖列表
+func (s *Scheduler) unmetDependencies(options RegisterOption) []string {
+	var unmet []string
+	for dep, status := range s.dependencyStatus(options) {
+		if status != DependencySatisfied {
+			unmet = append(unmet, dep)
+		}
+	}
+	sort.Strings(unmet)
+	return unmet
+}
+
+// dependencyStatus 计算任务各依赖的当前状态
+// 依赖任务在窗口期内成功执行且之后没有失败时视为已满足
+func (s *Scheduler) dependencyStatus(options RegisterOption) map[string]string {
+	if len(options.DependsOn) == 0 {
+		return nil
+	}
+
+	window := options.DependencyWindow
+	if window <= 0 {
+		window = defaultDependencyWindow
+	}
+
+	result := make(map[string]string, len(options.DependsOn))
+	now := time.Now()
+	for _, dep := range options.DependsOn {
+		lastSuccess, ok := s.getLastSuccess(dep)
+		lastFailure, failed := s.getLastFailure(dep)
+		switch {
+		case failed && (!ok || lastFailure.After(lastSuccess)):
+			result[dep] = DependencyFailed
+		case ok && now.Sub(lastSuccess) <= window:
+			result[dep] = DependencySatisfied
+		default:
+			result[dep] = DependencyUnsatisfied
+		}
+	}
+
+	return result
+}
+
+// recordFailure 记录任务执行失败或被跳过，下游任务据此跳过执行
+func (s *Scheduler) recordFailure(ctx context.Context, name, status string) {
+	s.setStatus(name, status)
+	if redis.GetClient() == nil {
+		return
+	}
+	if err := redis.Set(s.redisKey(lastFailureKeyType, name), time.Now().Unix(), 0); err != nil {
+		logger.Warn(ctx, "记录任务失败时间失败", zap.String("task", name), zap.Error(err))
+	}
+}
+
+// getLastFailure 获取任务最近一次失败的时间，不存在时返回false
+func (s *Scheduler) getLastFailure(name string) (time.Time, bool) {
+	return getTimestamp(s.redisKey(lastFailureKeyType, name))
+}
+
+// getStatus 获取任务最近一次执行状态
+func (s *Scheduler) getStatus(name string) string {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	return s.status[name]
+}
+
+// setStatus 更新任务最近一次执行状态
+func (s *Scheduler) setStatus(name, status string) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status[name] = status
+}
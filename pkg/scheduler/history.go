@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// 执行记录触发方式
+const (
+	// TriggerSchedule 按调度计划执行
+	TriggerSchedule = "schedule"
+	// TriggerManual 通过管理接口手动执行
+	TriggerManual = "manual"
+)
+
+// 执行记录相关常量
+const (
+	// historyKeyType 任务执行记录的Redis键类型，列表，最新的记录在最前
+	historyKeyType = "history"
+	// HistoryLimit 每个任务保留的执行记录数
+	HistoryLimit = 50
+	// historyRetention 执行记录的保留时间，任务长期未执行时记录过期
+	historyRetention = 30 * 24 * time.Hour
+)
+
+// ErrHistoryUnavailable Redis未初始化时无法读取执行记录
+var ErrHistoryUnavailable = errors.New("Redis客户端未初始化，无法读取执行记录")
+
+// RunRecord 任务执行记录
+// 只记录实际执行了处理函数的执行，因暂停或依赖未满足被跳过的执行不记录
+type RunRecord struct {
+	RunID      string    `json:"run_id,omitempty"` // 执行ID，与本次执行的日志中的request_id一致
+	Status     string    `json:"status"`           // 执行状态：success、failed、timeout
+	Trigger    string    `json:"trigger"`          // 触发方式：schedule、manual
+	StartedAt  time.Time `json:"started_at"`       // 开始执行时间
+	FinishedAt time.Time `json:"finished_at"`      // 执行结束时间
+	DurationMs int64     `json:"duration_ms"`      // 执行耗时（毫秒）
+	Error      string    `json:"error,omitempty"`  // 执行失败的原因
+}
+
+// Duration 返回执行耗时
+func (r RunRecord) Duration() time.Duration {
+	return time.Duration(r.DurationMs) * time.Millisecond
+}
+
+// Failed 是否执行失败或超时
+func (r RunRecord) Failed() bool {
+	return r.Status == TaskStatusFailed || r.Status == TaskStatusTimeout
+}
+
+// recordRun 在Redis中追加一条执行记录，只保留最近HistoryLimit条
+// 记录写入失败只记录日志，不影响任务执行结果
+func (s *Scheduler) recordRun(ctx context.Context, name, trigger string, startedAt time.Time, elapsed time.Duration, err error) {
+	client := redis.GetClient()
+	if client == nil {
+		return
+	}
+
+	record := RunRecord{
+		RunID:      logger.RequestID(ctx),
+		Status:     TaskStatusSuccess,
+		Trigger:    trigger,
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(elapsed),
+		DurationMs: elapsed.Milliseconds(),
+	}
+	switch {
+	case errors.Is(err, ErrTaskTimeout):
+		record.Status = TaskStatusTimeout
+		record.Error = err.Error()
+	case err != nil:
+		record.Status = TaskStatusFailed
+		record.Error = err.Error()
+	}
+
+	data, marshalErr := json.Marshal(record)
+	if marshalErr != nil {
+		logger.Warn(ctx, "序列化任务执行记录失败", zap.String("task", name), zap.Error(marshalErr))
+		return
+	}
+
+	key := s.redisKey(historyKeyType, name)
+	pipe := client.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, HistoryLimit-1)
+	pipe.Expire(ctx, key, historyRetention)
+	if _, execErr := pipe.Exec(ctx); execErr != nil {
+		logger.Warn(ctx, "保存任务执行记录失败", zap.String("task", name), zap.Error(execErr))
+	}
+}
+
+// RunHistory 获取任务最近的执行记录，最新的记录在最前，limit不大于0或超过HistoryLimit时返回全部保留的记录
+func (s *Scheduler) RunHistory(ctx context.Context, name string, limit int) ([]RunRecord, error) {
+	s.mu.RLock()
+	_, exists := s.entryMap[name]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("任务 %s 不存在", name)
+	}
+
+	client := redis.GetClient()
+	if client == nil {
+		return nil, ErrHistoryUnavailable
+	}
+	if limit <= 0 || limit > HistoryLimit {
+		limit = HistoryLimit
+	}
+
+	values, err := client.LRange(ctx, s.redisKey(historyKeyType, name), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取任务执行记录失败: %w", err)
+	}
+
+	records := make([]RunRecord, 0, len(values))
+	for _, value := range values {
+		var record RunRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// consecutiveFailures 统计执行记录开头连续失败或超时的次数
+func consecutiveFailures(records []RunRecord) int {
+	count := 0
+	for _, record := range records {
+		if !record.Failed() {
+			break
+		}
+		count++
+	}
+	return count
+}
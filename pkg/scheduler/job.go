@@ -0,0 +1,357 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 一次性任务状态，执行结果复用任务执行状态：success、failed、timeout
+const (
+	// JobStatusPending 等待执行
+	JobStatusPending = "pending"
+	// JobStatusRunning 正在执行
+	JobStatusRunning = "running"
+)
+
+// 一次性任务相关常量
+const (
+	// jobKeyType 一次性任务记录的Redis键类型
+	jobKeyType = "job"
+	// jobQueueKeyType 待执行一次性任务队列的Redis键类型，有序集合，分值为计划执行时间
+	jobQueueKeyType = "jobs"
+	// jobPollInterval 检查到期任务的间隔
+	jobPollInterval = time.Second
+	// jobPollBatch 每次检查最多取出的到期任务数
+	jobPollBatch = 100
+	// jobRetention 任务执行结束后记录的保留时间
+	jobRetention = 7 * 24 * time.Hour
+	// MaxJobDelay 一次性任务计划执行时间距现在的最长间隔
+	MaxJobDelay = 30 * 24 * time.Hour
+)
+
+// 一次性任务相关错误
+var (
+	ErrJobTypeNotFound     = errors.New("任务类型不存在")
+	ErrJobTypeExists       = errors.New("任务类型已存在")
+	ErrInvalidJobParams    = errors.New("任务参数无效")
+	ErrJobRunAtOutOfRange  = errors.New("计划执行时间超出允许范围")
+	ErrJobNotFound         = errors.New("一次性任务不存在")
+	ErrJobStoreUnavailable = errors.New("Redis客户端未初始化，无法保存一次性任务")
+)
+
+// JobHandler 一次性任务处理函数，params为提交任务时的JSON参数
+type JobHandler func(ctx context.Context, params json.RawMessage) error
+
+// JobType 一次性任务类型，只有注册过的类型才能通过管理接口提交
+type JobType struct {
+	Handler  JobHandler                         // 任务处理函数
+	Validate func(params json.RawMessage) error // 提交时校验参数，为nil时不校验
+	Timeout  time.Duration                      // 单次执行的超时时间，为0时不限制
+}
+
+// Job 一次性任务
+type Job struct {
+	ID         string          `json:"id"`                    // 任务ID
+	Type       string          `json:"type"`                  // 任务类型
+	Params     json.RawMessage `json:"params,omitempty"`      // 任务参数
+	RunAt      time.Time       `json:"run_at"`                // 计划执行时间
+	Status     string          `json:"status"`                // 任务状态
+	Error      string          `json:"error,omitempty"`       // 执行失败的原因
+	CreatedBy  string          `json:"created_by,omitempty"`  // 提交者
+	RequestID  string          `json:"request_id,omitempty"`  // 提交任务的请求ID，执行时沿用以便与提交请求的日志关联
+	CreatedAt  time.Time       `json:"created_at"`            // 提交时间
+	StartedAt  *time.Time      `json:"started_at,omitempty"`  // 开始执行时间
+	FinishedAt *time.Time      `json:"finished_at,omitempty"` // 执行结束时间
+}
+
+// RegisterJobType 注册一次性任务类型
+func (s *Scheduler) RegisterJobType(name string, jobType JobType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobTypes[name]; exists {
+		return fmt.Errorf("%w: %s", ErrJobTypeExists, name)
+	}
+	s.jobTypes[name] = jobType
+	return nil
+}
+
+// ScheduleJob 提交一次性任务，任务记录保存在Redis中，调度器重启后仍会执行
+// runAt早于当前时间时尽快执行，晚于MaxJobDelay时返回错误
+func (s *Scheduler) ScheduleJob(ctx context.Context, typeName string, params json.RawMessage, runAt time.Time, createdBy string) (*Job, error) {
+	s.mu.RLock()
+	jobType, exists := s.jobTypes[typeName]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrJobTypeNotFound, typeName)
+	}
+	if jobType.Validate != nil {
+		if err := jobType.Validate(params); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJobParams, err)
+		}
+	}
+
+	now := time.Now()
+	if runAt.Before(now) {
+		runAt = now
+	}
+	if runAt.Sub(now) > MaxJobDelay {
+		return nil, ErrJobRunAtOutOfRange
+	}
+
+	client := redis.GetClient()
+	if client == nil {
+		return nil, ErrJobStoreUnavailable
+	}
+
+	job := &Job{
+		ID:        uuid.New().String(),
+		Type:      typeName,
+		Params:    params,
+		RunAt:     runAt,
+		Status:    JobStatusPending,
+		CreatedBy: createdBy,
+		RequestID: logger.RequestID(ctx),
+		CreatedAt: now,
+	}
+	// 任务记录在计划执行时间之后再保留一段时间，避免未执行的记录永久残留
+	if err := s.saveJob(ctx, job, runAt.Sub(now)+jobRetention); err != nil {
+		return nil, err
+	}
+	if err := client.ZAdd(ctx, s.redisKey(jobQueueKeyType, JobStatusPending), goredis.Z{
+		Score:  float64(runAt.Unix()),
+		Member: job.ID,
+	}).Err(); err != nil {
+		return nil, fmt.Errorf("加入待执行队列失败: %w", err)
+	}
+
+	logger.Info(ctx, "已提交一次性任务",
+		zap.String("job_id", job.ID),
+		zap.String("type", typeName),
+		zap.Time("run_at", runAt),
+		zap.String("created_by", createdBy))
+	return job, nil
+}
+
+// GetJob 获取一次性任务，执行结束的任务记录保留一段时间后过期
+func (s *Scheduler) GetJob(ctx context.Context, id string) (*Job, error) {
+	client := redis.GetClient()
+	if client == nil {
+		return nil, ErrJobStoreUnavailable
+	}
+	data, err := client.Get(ctx, s.redisKey(jobKeyType, id)).Bytes()
+	if err == redis.ErrNil {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("解析一次性任务记录失败: %w", err)
+	}
+	return &job, nil
+}
+
+// saveJob 保存一次性任务记录
+func (s *Scheduler) saveJob(ctx context.Context, job *Job, ttl time.Duration) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if err := redis.GetClient().Set(ctx, s.redisKey(jobKeyType, job.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("保存一次性任务记录失败: %w", err)
+	}
+	return nil
+}
+
+// startJobPoller 在后台定期检查到期的一次性任务
+func (s *Scheduler) startJobPoller() {
+	s.jobPollStop = make(chan struct{})
+	s.jobPollDone = make(chan struct{})
+
+	go func() {
+		defer close(s.jobPollDone)
+		ticker := time.NewTicker(jobPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.jobPollStop:
+				return
+			case <-ticker.C:
+				s.pollJobs()
+			}
+		}
+	}()
+}
+
+// stopJobPoller 停止检查到期任务，已开始的任务由Stop等待完成
+func (s *Scheduler) stopJobPoller() {
+	if s.jobPollStop == nil {
+		return
+	}
+	close(s.jobPollStop)
+	<-s.jobPollDone
+}
+
+// pollJobs 取出到期的一次性任务并执行
+// 选主模式下只有主节点执行，调度器暂停期间不执行，到期任务保留在队列中待恢复后执行
+func (s *Scheduler) pollJobs() {
+	ctx := context.Background()
+	if !s.IsLeader() || (s.paused != nil && s.paused(ctx)) {
+		return
+	}
+	client := redis.GetClient()
+	if client == nil {
+		return
+	}
+
+	ids, err := client.ZRangeByScore(ctx, s.redisKey(jobQueueKeyType, JobStatusPending), &goredis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().Unix(), 10),
+		Count: jobPollBatch,
+	}).Result()
+	if err != nil {
+		logger.Error(ctx, "获取到期的一次性任务失败", zap.Error(err))
+		return
+	}
+
+	for _, id := range ids {
+		if s.claimJob(id) {
+			go s.runJob(id)
+		}
+	}
+}
+
+// claimJob 在本节点登记正在执行的任务，任务执行结束前不会重复取出
+func (s *Scheduler) claimJob(id string) bool {
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+
+	if _, running := s.runningJobs[id]; running {
+		return false
+	}
+	s.runningJobs[id] = struct{}{}
+	return true
+}
+
+// releaseJob 取消本节点对任务的登记
+func (s *Scheduler) releaseJob(id string) {
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+	delete(s.runningJobs, id)
+}
+
+// runJob 执行一次性任务
+// 与定时任务相同，通过分布式锁保证只在一个节点执行，执行结束后才从队列中移除，节点异常退出时锁过期后由其他节点重新执行
+func (s *Scheduler) runJob(id string) {
+	defer s.releaseJob(id)
+
+	ctx, done, ok := s.beginRun()
+	if !ok {
+		return
+	}
+	defer done()
+
+	client := redis.GetClient()
+	if client == nil {
+		return
+	}
+	queueKey := s.redisKey(jobQueueKeyType, JobStatusPending)
+
+	job, err := s.GetJob(ctx, id)
+	if errors.Is(err, ErrJobNotFound) {
+		// 任务记录已过期，从队列中移除
+		client.ZRem(ctx, queueKey, id)
+		logger.Warn(ctx, "一次性任务记录不存在，已从队列中移除", zap.String("job_id", id))
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "读取一次性任务失败", zap.String("job_id", id), zap.Error(err))
+		return
+	}
+
+	if job.RequestID != "" {
+		ctx = logger.WithRequestID(ctx, job.RequestID)
+	}
+
+	s.mu.RLock()
+	jobType, exists := s.jobTypes[job.Type]
+	s.mu.RUnlock()
+
+	lockExpiration := jobType.Timeout
+	if lockExpiration <= 0 {
+		lockExpiration = DefaultRegisterOption.LockTimeout
+	}
+	lock := redis.NewLock(s.redisKey(lockKeyType, jobKeyType+":"+id), lockExpiration)
+	acquired, err := lock.TryAcquire()
+	if err != nil {
+		logger.Error(ctx, "获取分布式锁失败", zap.String("job_id", id), zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			logger.Error(ctx, "释放分布式锁失败", zap.String("job_id", id), zap.Error(err))
+		}
+	}()
+
+	// 取出任务后其他节点可能已执行完成，获取锁后确认任务仍在队列中
+	if err := client.ZScore(ctx, queueKey, id).Err(); err != nil {
+		return
+	}
+
+	startedAt := time.Now()
+	job.Status = JobStatusRunning
+	job.StartedAt = &startedAt
+	if err := s.saveJob(ctx, job, lockExpiration+jobRetention); err != nil {
+		logger.Warn(ctx, "更新一次性任务状态失败", zap.String("job_id", id), zap.Error(err))
+	}
+
+	logger.Info(ctx, "开始执行一次性任务", zap.String("job_id", id), zap.String("type", job.Type))
+
+	if !exists {
+		err = fmt.Errorf("%w: %s", ErrJobTypeNotFound, job.Type)
+	} else {
+		err = s.runHandler(ctx, jobKeyType+":"+id, func(ctx context.Context) error {
+			return jobType.Handler(ctx, job.Params)
+		}, jobType.Timeout)
+	}
+	elapsed := time.Since(startedAt)
+
+	switch {
+	case errors.Is(err, ErrTaskTimeout):
+		logger.Error(ctx, "一次性任务执行超时", zap.String("job_id", id), zap.String("type", job.Type), zap.Duration("timeout", jobType.Timeout))
+		job.Status = TaskStatusTimeout
+		job.Error = err.Error()
+	case err != nil:
+		logger.Error(ctx, "一次性任务执行失败", zap.String("job_id", id), zap.String("type", job.Type), zap.Duration("elapsed", elapsed), zap.Error(err))
+		job.Status = TaskStatusFailed
+		job.Error = err.Error()
+	default:
+		logger.Info(ctx, "一次性任务执行成功", zap.String("job_id", id), zap.String("type", job.Type), zap.Duration("elapsed", elapsed))
+		job.Status = TaskStatusSuccess
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err := s.saveJob(ctx, job, jobRetention); err != nil {
+		logger.Warn(ctx, "保存一次性任务结果失败", zap.String("job_id", id), zap.Error(err))
+	}
+	if err := client.ZRem(ctx, queueKey, id).Err(); err != nil {
+		logger.Error(ctx, "从待执行队列移除一次性任务失败", zap.String("job_id", id), zap.Error(err))
+	}
+}
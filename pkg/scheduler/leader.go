@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// 选主相关常量
+const (
+	// leaderKeyType 主节点租约的Redis键类型
+	leaderKeyType = "leader"
+	// defaultLeaseTTL 未指定时主节点租约的有效期
+	defaultLeaseTTL = 15 * time.Second
+	// leaderOpTimeout 单次选主操作的Redis超时时间
+	leaderOpTimeout = 3 * time.Second
+)
+
+// renewLeaseScript 仅当租约仍由本节点持有时续期
+const renewLeaseScript = `
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`
+
+// releaseLeaseScript 仅当租约仍由本节点持有时释放
+const releaseLeaseScript = `
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+else
+	return 0
+end
+`
+
+// LeaderInfo 选主状态
+type LeaderInfo struct {
+	Enabled  bool          `json:"enabled"`   // 是否启用选主模式
+	NodeID   string        `json:"node_id"`   // 本节点标识
+	Leader   string        `json:"leader"`    // 当前主节点标识，租约不存在时为空
+	IsLeader bool          `json:"is_leader"` // 本节点是否为主节点
+	LeaseTTL time.Duration `json:"lease_ttl"` // 当前租约的剩余有效期
+}
+
+// WithLeaderElection 启用选主模式，替代逐个任务加分布式锁
+// 各节点竞争Redis中的主节点租约，只有主节点执行定时任务，其他节点待命，主节点退出或租约过期后由待命节点接管
+// nodeID为空时使用主机名和进程号，leaseTTL为0时使用默认值，主节点每隔三分之一租约有效期续期一次
+func WithLeaderElection(nodeID string, leaseTTL time.Duration) Option {
+	return func(s *Scheduler) {
+		s.leaderElection = true
+		s.nodeID = nodeID
+		if s.nodeID == "" {
+			s.nodeID = defaultNodeID()
+		}
+		s.leaseTTL = leaseTTL
+		if s.leaseTTL <= 0 {
+			s.leaseTTL = defaultLeaseTTL
+		}
+	}
+}
+
+// defaultNodeID 使用主机名和进程号生成节点标识
+func defaultNodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// IsLeader 判断本节点是否应执行定时任务，未启用选主模式时始终返回true
+func (s *Scheduler) IsLeader() bool {
+	return !s.leaderElection || s.leader.Load()
+}
+
+// LeaderInfo 获取选主状态
+func (s *Scheduler) LeaderInfo(ctx context.Context) (*LeaderInfo, error) {
+	info := &LeaderInfo{
+		Enabled:  s.leaderElection,
+		NodeID:   s.nodeID,
+		IsLeader: s.IsLeader(),
+	}
+	if !s.leaderElection {
+		return info, nil
+	}
+
+	client := redis.GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("Redis客户端未初始化")
+	}
+	key := s.leaderKey()
+	leader, err := client.Get(ctx, key).Result()
+	if err == redis.ErrNil {
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.Leader = leader
+	if ttl, err := client.PTTL(ctx, key).Result(); err == nil && ttl > 0 {
+		info.LeaseTTL = ttl
+	}
+	return info, nil
+}
+
+// leaderKey 主节点租约的Redis键，所有节点共用一个租约
+func (s *Scheduler) leaderKey() string {
+	return s.keyPrefix + leaderKeyType
+}
+
+// startElection 立即竞选一次并在后台定期续期或竞选
+func (s *Scheduler) startElection() {
+	s.electionStop = make(chan struct{})
+	s.electionDone = make(chan struct{})
+
+	s.campaign()
+
+	go func() {
+		defer close(s.electionDone)
+		ticker := time.NewTicker(s.leaseTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.electionStop:
+				return
+			case <-ticker.C:
+				s.campaign()
+			}
+		}
+	}()
+}
+
+// stopElection 停止竞选并释放本节点持有的租约，便于待命节点尽快接管
+func (s *Scheduler) stopElection() {
+	if s.electionStop == nil {
+		return
+	}
+	close(s.electionStop)
+	<-s.electionDone
+
+	if !s.leader.Swap(false) {
+		return
+	}
+	client := redis.GetClient()
+	if client == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), leaderOpTimeout)
+	defer cancel()
+	if err := client.Eval(ctx, releaseLeaseScript, []string{s.leaderKey()}, s.nodeID).Err(); err != nil {
+		logger.Warn(ctx, "释放主节点租约失败", zap.String("node", s.nodeID), zap.Error(err))
+		return
+	}
+	logger.Info(ctx, "已释放主节点租约", zap.String("node", s.nodeID))
+}
+
+// campaign 主节点续期租约，待命节点尝试获取租约
+// Redis不可用时主节点无法确认租约仍然有效，立即降为待命，避免与新的主节点同时执行任务
+func (s *Scheduler) campaign() {
+	ctx, cancel := context.WithTimeout(context.Background(), leaderOpTimeout)
+	defer cancel()
+
+	client := redis.GetClient()
+	if client == nil {
+		s.setLeader(ctx, false)
+		return
+	}
+	key := s.leaderKey()
+
+	if s.leader.Load() {
+		renewed, err := client.Eval(ctx, renewLeaseScript, []string{key}, s.nodeID, s.leaseTTL.Milliseconds()).Int64()
+		if err != nil {
+			logger.Error(ctx, "续期主节点租约失败", zap.String("node", s.nodeID), zap.Error(err))
+		}
+		s.setLeader(ctx, err == nil && renewed == 1)
+		return
+	}
+
+	acquired, err := client.SetNX(ctx, key, s.nodeID, s.leaseTTL).Result()
+	if err != nil {
+		logger.Error(ctx, "竞选主节点失败", zap.String("node", s.nodeID), zap.Error(err))
+		return
+	}
+	s.setLeader(ctx, acquired)
+}
+
+// setLeader 更新本节点的主节点状态，成为主节点时补执行错过的任务和需要立即执行的任务
+func (s *Scheduler) setLeader(ctx context.Context, leader bool) {
+	if s.leader.Swap(leader) == leader {
+		return
+	}
+
+	if !leader {
+		logger.Warn(ctx, "本节点失去主节点身份，停止执行定时任务", zap.String("node", s.nodeID))
+		return
+	}
+
+	logger.Info(ctx, "本节点成为主节点，开始执行定时任务", zap.String("node", s.nodeID))
+	s.handleMisfires()
+
+	s.mu.RLock()
+	immediate := make([]func(), 0, len(s.immediate))
+	for _, name := range s.immediate {
+		if job, exists := s.jobs[name]; exists {
+			immediate = append(immediate, job)
+		}
+	}
+	s.mu.RUnlock()
+	for _, job := range immediate {
+		go job()
+	}
+}
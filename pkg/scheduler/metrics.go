@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetricsContentType Prometheus文本格式的Content-Type
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric 一个指标及其所有样本
+type metric struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+// metricSample 指标样本
+type metricSample struct {
+	labels []string // 成对的标签名和标签值
+	value  float64
+}
+
+// add 添加样本
+func (m *metric) add(value float64, labels ...string) {
+	m.samples = append(m.samples, metricSample{labels: labels, value: value})
+}
+
+// WriteMetrics 以Prometheus文本格式输出各任务的执行状态、SLO告警指标和任务上报的指标
+// 执行记录和告警状态保存在Redis中，任一节点输出的指标都相同，Redis不可用时只输出本节点状态
+func (s *Scheduler) WriteMetrics(ctx context.Context, w io.Writer) error {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.entryMap))
+	sloOptions := make(map[string]SLO, len(s.options))
+	for name := range s.entryMap {
+		names = append(names, name)
+		sloOptions[name] = s.options[name].SLO
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+
+	leader := 0.0
+	if s.IsLeader() {
+		leader = 1
+	}
+	isLeader := &metric{name: "scheduler_is_leader", help: "本节点是否执行定时任务，未启用选主模式时恒为1"}
+	isLeader.add(leader)
+
+	lastStatus := &metric{name: "scheduler_task_last_status", help: "任务最近一次执行状态，当前状态的样本值为1"}
+	lastRun := &metric{name: "scheduler_task_last_run_timestamp_seconds", help: "任务最近一次执行结束的Unix时间"}
+	lastDuration := &metric{name: "scheduler_task_last_duration_seconds", help: "任务最近一次执行的耗时"}
+	lastSuccess := &metric{name: "scheduler_task_last_success_timestamp_seconds", help: "任务最近一次成功执行的Unix时间"}
+	failures := &metric{name: "scheduler_task_consecutive_failures", help: "任务当前连续失败（含超时）的次数"}
+	sloFailures := &metric{name: "scheduler_task_slo_max_consecutive_failures", help: "任务SLO允许的连续失败次数"}
+	sloDuration := &metric{name: "scheduler_task_slo_max_duration_seconds", help: "任务SLO允许的最长执行耗时"}
+	sloMissed := &metric{name: "scheduler_task_slo_max_missed_runs", help: "任务SLO允许的错过执行次数"}
+
+	for _, name := range names {
+		if status := s.getStatus(name); status != "" {
+			lastStatus.add(1, "task", name, "status", status)
+		}
+		if records, err := s.RunHistory(ctx, name, HistoryLimit); err == nil {
+			if len(records) > 0 {
+				lastRun.add(float64(records[0].FinishedAt.Unix()), "task", name)
+				lastDuration.add(records[0].Duration().Seconds(), "task", name)
+			}
+			failures.add(float64(consecutiveFailures(records)), "task", name)
+		}
+		if t, ok := s.getLastSuccess(name); ok {
+			lastSuccess.add(float64(t.Unix()), "task", name)
+		}
+
+		slo := sloOptions[name]
+		if slo.MaxConsecutiveFailures > 0 {
+			sloFailures.add(float64(slo.MaxConsecutiveFailures), "task", name)
+		}
+		if slo.MaxDuration > 0 {
+			sloDuration.add(slo.MaxDuration.Seconds(), "task", name)
+		}
+		if slo.MaxMissedRuns > 0 {
+			sloMissed.add(float64(slo.MaxMissedRuns), "task", name)
+		}
+	}
+
+	metrics := []*metric{isLeader, lastStatus, lastRun, lastDuration, lastSuccess, failures, sloFailures, sloDuration, sloMissed}
+
+	if s.alerting {
+		firing := &metric{name: "scheduler_alerts", help: "告警中的任务SLO告警，silenced表示是否被静默"}
+		if alerts, err := s.Alerts(ctx); err == nil {
+			for _, alert := range alerts {
+				firing.add(1, "task", alert.Task, "rule", alert.Rule, "silenced", strconv.FormatBool(alert.Silenced))
+			}
+		}
+		metrics = append(metrics, firing)
+	}
+
+	return writeMetrics(w, append(metrics, s.collectTaskMetrics(names)...))
+}
+
+// collectTaskMetrics 汇总各任务最近一次上报的指标，同名指标合并输出并添加task标签
+func (s *Scheduler) collectTaskMetrics(names []string) []*metric {
+	byName := make(map[string]*metric)
+	for _, name := range names {
+		for _, sample := range s.loadTaskMetrics(name) {
+			m, ok := byName[sample.Name]
+			if !ok {
+				m = &metric{name: sample.Name, help: sample.Help}
+				byName[sample.Name] = m
+			}
+			m.add(sample.Value, append([]string{"task", name}, sample.Labels...)...)
+		}
+	}
+
+	metricNames := make([]string, 0, len(byName))
+	for name := range byName {
+		metricNames = append(metricNames, name)
+	}
+	sort.Strings(metricNames)
+
+	metrics := make([]*metric, 0, len(metricNames))
+	for _, name := range metricNames {
+		metrics = append(metrics, byName[name])
+	}
+	return metrics
+}
+
+// writeMetrics 按Prometheus文本格式输出指标，所有指标均为gauge类型
+func writeMetrics(w io.Writer, metrics []*metric) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", m.name)
+		for _, sample := range m.samples {
+			bw.WriteString(m.name)
+			if len(sample.labels) > 0 {
+				bw.WriteByte('{')
+				for i := 0; i+1 < len(sample.labels); i += 2 {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					fmt.Fprintf(bw, "%s=\"%s\"", sample.labels[i], escapeLabelValue(sample.labels[i+1]))
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(strconv.FormatFloat(sample.value, 'g', -1, 64))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// escapeLabelValue 转义标签值中的反斜杠、双引号和换行符
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -2,9 +2,11 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"app/pkg/logger"
@@ -19,21 +21,91 @@ type Scheduler struct {
 	cron      *cron.Cron
 	entryMap  map[string]cron.EntryID
 	handlers  map[string]TaskHandler
-	redisLock bool // 是否使用Redis分布式锁
+	jobs      map[string]func()              // 包装后的任务执行函数，用于错过执行的补偿
+	schedules map[string]cron.Schedule       // 任务的调度计划
+	options   map[string]RegisterOption      // 任务的注册选项
+	status    map[string]string              // 任务最近一次执行状态
+	redisLock bool                           // 是否使用Redis分布式锁
+	paused    func(ctx context.Context) bool // 判断是否暂停非关键任务，为nil时不暂停
+	keyPrefix string                         // Redis键前缀，锁和执行记录的键均以此开头
 	mu        sync.RWMutex
+	statusMu  sync.RWMutex // 保护任务执行状态，任务执行期间不持有mu
+
+	runCtx       context.Context    // 所有任务执行使用的上下文，停止时取消
+	cancelRuns   context.CancelFunc // 取消运行中的任务
+	running      sync.WaitGroup     // 运行中的任务
+	runMu        sync.Mutex         // 保护stopping，保证停止后不再有新任务加入running
+	stopping     bool               // 是否正在停止，停止后不再执行新任务
+	drainTimeout time.Duration      // 停止时等待运行中任务完成的最长时间，超时后取消任务上下文
+
+	leaderElection bool          // 是否启用选主模式，启用后只有主节点执行定时任务
+	nodeID         string        // 本节点标识，作为主节点租约的值
+	leaseTTL       time.Duration // 主节点租约有效期
+	leader         atomic.Bool   // 本节点当前是否为主节点
+	electionStop   chan struct{} // 通知竞选协程退出
+	electionDone   chan struct{} // 竞选协程已退出
+	immediate      []string      // 选主模式下需要在成为主节点后立即执行的任务
+
+	jobTypes    map[string]JobType  // 已注册的一次性任务类型
+	runningJobs map[string]struct{} // 本节点正在执行的一次性任务
+	jobMu       sync.Mutex          // 保护runningJobs
+	jobPollStop chan struct{}       // 通知到期任务检查协程退出
+	jobPollDone chan struct{}       // 到期任务检查协程已退出
+
+	alerting      bool          // 是否启用任务SLO告警检查
+	alertNotifier AlertNotifier // 告警通知，为nil时只记录告警状态
+	alertInterval time.Duration // 告警检查间隔
+	alertStop     chan struct{} // 通知告警检查协程退出
+	alertDone     chan struct{} // 告警检查协程已退出
 }
 
+// MisfirePolicy 错过执行策略，决定调度器停机期间错过的执行在启动时如何处理
+type MisfirePolicy string
+
+const (
+	// MisfireSkip 跳过错过的执行
+	MisfireSkip MisfirePolicy = "skip"
+	// MisfireRunOnce 启动时补执行一次
+	MisfireRunOnce MisfirePolicy = "run_once"
+	// MisfireCatchUp 启动时按错过次数补执行，最多CatchUpLimit次
+	MisfireCatchUp MisfirePolicy = "catch_up"
+)
+
+// Redis键相关常量，完整的键格式为 前缀+类型:任务名称
+const (
+	// defaultKeyPrefix 未指定时使用的键前缀
+	defaultKeyPrefix = "scheduler:"
+	// lockKeyType 分布式锁
+	lockKeyType = "lock"
+	// lastSuccessKeyType 任务最近一次成功执行时间
+	lastSuccessKeyType = "last_success"
+)
+
+// 停止相关常量
+const (
+	// defaultDrainTimeout 未指定时停止调度器等待运行中任务完成的最长时间
+	defaultDrainTimeout = 30 * time.Second
+	// cancelGracePeriod 取消任务上下文后等待任务退出的时间
+	cancelGracePeriod = 5 * time.Second
+)
+
+// ErrTaskTimeout 任务执行超过注册时指定的超时时间
+var ErrTaskTimeout = errors.New("任务执行超时")
+
 // TaskHandler 任务处理函数类型
 type TaskHandler func(ctx context.Context) error
 
 // TaskInfo 任务信息
 type TaskInfo struct {
-	Name     string    // 任务名称
-	Spec     string    // cron表达式
-	Next     time.Time // 下次执行时间
-	Prev     time.Time // 上次执行时间
-	Running  bool      // 是否正在运行
-	Disabled bool      // 是否禁用
+	Name         string            // 任务名称
+	Spec         string            // cron表达式
+	Next         time.Time         // 下次执行时间
+	Prev         time.Time         // 上次执行时间
+	Running      bool              // 是否正在运行
+	Disabled     bool              // 是否禁用
+	LastStatus   string            // 最近一次执行状态：success-成功，failed-失败，timeout-执行超时，blocked-因依赖未满足被跳过，paused-因调度器暂停被跳过
+	DependsOn    []string          // 依赖的任务列表
+	Dependencies map[string]string // 各依赖任务的当前状态：satisfied-已满足，unsatisfied-未满足，failed-失败
 }
 
 // Init 初始化并返回一个新的调度器
@@ -48,8 +120,19 @@ func Init(opts ...Option) *Scheduler {
 		cron:      c,
 		entryMap:  make(map[string]cron.EntryID),
 		handlers:  make(map[string]TaskHandler),
+		jobs:      make(map[string]func()),
+		schedules: make(map[string]cron.Schedule),
+		options:   make(map[string]RegisterOption),
+		status:    make(map[string]string),
 		redisLock: false,
+		keyPrefix: defaultKeyPrefix,
+
+		jobTypes:    make(map[string]JobType),
+		runningJobs: make(map[string]struct{}),
+
+		drainTimeout: defaultDrainTimeout,
 	}
+	s.runCtx, s.cancelRuns = context.WithCancel(context.Background())
 
 	// 应用选项
 	for _, opt := range opts {
@@ -79,16 +162,51 @@ func WithRedisLock() Option {
 	}
 }
 
+// WithPauseCheck 设置暂停判断函数，返回true时跳过非关键任务的定时执行，手动执行不受影响
+func WithPauseCheck(paused func(ctx context.Context) bool) Option {
+	return func(s *Scheduler) {
+		s.paused = paused
+	}
+}
+
+// WithKeyPrefix 设置Redis键前缀，用于按环境隔离或统一键的命名空间，需以分隔符结尾
+func WithKeyPrefix(prefix string) Option {
+	return func(s *Scheduler) {
+		if prefix != "" {
+			s.keyPrefix = prefix
+		}
+	}
+}
+
+// WithDrainTimeout 设置停止调度器时等待运行中任务完成的最长时间
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(s *Scheduler) {
+		if timeout > 0 {
+			s.drainTimeout = timeout
+		}
+	}
+}
+
 // RegisterOption 注册任务的选项
 type RegisterOption struct {
-	RunImmediately bool          // 是否在添加后立即执行一次
-	LockTimeout    time.Duration // 分布式锁超时时间
+	RunImmediately   bool          // 是否在添加后立即执行一次
+	LockTimeout      time.Duration // 分布式锁超时时间
+	MisfirePolicy    MisfirePolicy // 错过执行策略，为空时等同于跳过
+	CatchUpLimit     int           // 补执行的最大次数，仅MisfireCatchUp策略有效
+	DependsOn        []string      // 依赖的任务，所有依赖在窗口期内成功执行后才会执行
+	DependencyWindow time.Duration // 依赖任务成功执行的有效窗口，为空时使用默认值
+	Critical         bool          // 是否为关键任务，关键任务在调度器暂停期间仍然执行
+	Timeout          time.Duration // 单次执行的超时时间，超时后取消任务上下文并记为超时，为0时不限制
+	SLO              SLO           // 服务等级目标，启用告警检查时按此检查任务，零值时不检查
+	RetryCount       int           // 执行失败后的重试次数，超时和调度器停止时不重试，重试期间持有分布式锁并相应延长锁超时时间
+	RetryInterval    time.Duration // 重试间隔，为0时立即重试
 }
 
 // DefaultRegisterOption 默认注册选项
 var DefaultRegisterOption = RegisterOption{
 	RunImmediately: true,
 	LockTimeout:    5 * time.Minute,
+	MisfirePolicy:  MisfireSkip,
 }
 
 // Register 注册定时任务
@@ -108,17 +226,40 @@ func (s *Scheduler) RegisterWithOptions(name, spec string, handler TaskHandler,
 
 	// 包装处理函数，添加日志和错误处理
 	wrappedHandler := func() {
-		ctx := context.Background()
+		// 调度器停止后不再执行新任务；done在锁释放之后调用，保证Stop返回前所有锁已释放
+		ctx, done, ok := s.beginRun()
+		if !ok {
+			return
+		}
+		defer done()
+
+		// 选主模式下只有主节点执行定时任务
+		if !s.IsLeader() {
+			logger.Debug(ctx, "本节点不是主节点，跳过任务", zap.String("task", name))
+			return
+		}
+
+		// 暂停期间跳过非关键任务，不记录为失败，避免阻塞下游任务
+		if !options.Critical && s.paused != nil && s.paused(ctx) {
+			logger.Info(ctx, "调度器已暂停，跳过非关键任务", zap.String("task", name))
+			s.setStatus(name, TaskStatusPaused)
+			return
+		}
+
 		logger.Info(ctx, "开始执行定时任务", zap.String("task", name))
 
 		// 如果启用了Redis分布式锁，尝试获取锁
 		if s.redisLock {
-			lockKey := fmt.Sprintf("scheduler:lock:%s", name)
+			lockKey := s.redisKey(lockKeyType, name)
 			// 使用选项中指定的锁超时时间，或默认值
 			lockExpiration := options.LockTimeout
 			if lockExpiration <= 0 {
 				lockExpiration = 5 * time.Minute
 			}
+			// 重试期间持有锁，按重试次数延长锁超时时间，避免重试时锁过期被其他节点同时执行
+			if options.RetryCount > 0 {
+				lockExpiration = lockExpiration*time.Duration(options.RetryCount+1) + options.RetryInterval*time.Duration(options.RetryCount)
+			}
 
 			// 创建分布式锁
 			lock := redis.NewLock(lockKey, lockExpiration)
@@ -147,15 +288,40 @@ func (s *Scheduler) RegisterWithOptions(name, spec string, handler TaskHandler,
 			}()
 		}
 
-		// 执行任务
-		start := time.Now()
-		err := handler(ctx)
-		elapsed := time.Since(start)
+		// 检查依赖任务，未满足时跳过本次执行并向下游传播
+		if unmet := s.unmetDependencies(options); len(unmet) > 0 {
+			logger.Warn(ctx, "依赖任务未满足，跳过执行", zap.String("task", name), zap.Strings("dependencies", unmet))
+			s.recordFailure(ctx, name, TaskStatusBlocked)
+			return
+		}
 
-		if err != nil {
+		// 执行任务，失败时按重试策略重试，每次执行分别记录
+		var (
+			err     error
+			elapsed time.Duration
+		)
+		for attempt := 0; ; attempt++ {
+			start := time.Now()
+			err = s.runHandler(ctx, name, handler, options.Timeout)
+			elapsed = time.Since(start)
+			s.recordRun(ctx, name, TriggerSchedule, start, elapsed, err)
+
+			if err == nil || errors.Is(err, ErrTaskTimeout) || attempt >= options.RetryCount || !s.waitRetry(ctx, options.RetryInterval) {
+				break
+			}
+			logger.Warn(ctx, "定时任务执行失败，重试", zap.String("task", name), zap.Int("retry", attempt+1), zap.Error(err))
+		}
+
+		switch {
+		case errors.Is(err, ErrTaskTimeout):
+			logger.Error(ctx, "定时任务执行超时", zap.String("task", name), zap.Duration("timeout", options.Timeout))
+			s.recordFailure(ctx, name, TaskStatusTimeout)
+		case err != nil:
 			logger.Error(ctx, "定时任务执行失败", zap.String("task", name), zap.Duration("elapsed", elapsed), zap.Error(err))
-		} else {
+			s.recordFailure(ctx, name, TaskStatusFailed)
+		default:
 			logger.Info(ctx, "定时任务执行成功", zap.String("task", name), zap.Duration("elapsed", elapsed))
+			s.recordSuccess(ctx, name)
 		}
 	}
 
@@ -182,28 +348,147 @@ func (s *Scheduler) RegisterWithOptions(name, spec string, handler TaskHandler,
 		if err != nil {
 			return fmt.Errorf("解析cron表达式失败: %w", err)
 		}
-		// 添加任务并立即执行一次
+		// 添加任务并立即执行一次，选主模式下在成为主节点后执行
 		entryID = s.cron.Schedule(schedule, cron.FuncJob(wrappedHandler))
-		go wrappedHandler() // 立即执行一次
+		if s.leaderElection {
+			s.immediate = append(s.immediate, name)
+		} else {
+			go wrappedHandler() // 立即执行一次
+		}
 	}
 
 	// 保存任务信息
 	s.entryMap[name] = entryID
 	s.handlers[name] = handler
+	s.jobs[name] = wrappedHandler
+	s.schedules[name] = s.cron.Entry(entryID).Schedule
+	s.options[name] = options
 
 	return nil
 }
 
 // Start 启动调度器
+// 启动前按各任务的错过执行策略处理停机期间错过的执行；选主模式下先竞选一次，由成为主节点的节点处理
 func (s *Scheduler) Start() {
+	if s.leaderElection {
+		s.startElection()
+	} else {
+		s.handleMisfires()
+	}
 	s.cron.Start()
+	s.startJobPoller()
+	s.startAlerting()
 	logger.Info(context.Background(), "定时任务调度器已启动")
 }
 
 // Stop 停止调度器
+// 停止调度新任务后等待运行中的任务完成，超过等待时间后取消任务上下文并再等待一小段时间，选主模式下最后释放主节点租约
+// 任务持有的分布式锁在任务退出时释放，因此Stop返回前锁已释放，不会在进程退出后被误释放
 func (s *Scheduler) Stop() {
+	ctx := context.Background()
 	s.cron.Stop()
-	logger.Info(context.Background(), "定时任务调度器已停止")
+	s.stopJobPoller()
+	s.stopAlerting()
+
+	s.runMu.Lock()
+	s.stopping = true
+	s.runMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(s.drainTimeout):
+		logger.Warn(ctx, "等待运行中的任务完成超时，取消任务", zap.Duration("timeout", s.drainTimeout))
+		s.cancelRuns()
+		select {
+		case <-drained:
+		case <-time.After(cancelGracePeriod):
+			logger.Error(ctx, "取消后仍有任务未退出，其持有的锁将在超时后自动释放")
+		}
+	}
+	s.cancelRuns()
+
+	// 任务结束后再释放主节点租约，避免新的主节点与本节点同时执行任务
+	s.stopElection()
+
+	logger.Info(ctx, "定时任务调度器已停止")
+}
+
+// beginRun 登记一次任务执行，返回任务使用的上下文和执行结束时调用的函数
+// 上下文携带新生成的执行ID（作为请求ID），本次执行的所有日志和下游调用可据此关联
+// 调度器正在停止时返回false，不再执行新任务
+func (s *Scheduler) beginRun() (context.Context, func(), bool) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	if s.stopping {
+		return nil, nil, false
+	}
+	s.running.Add(1)
+	return logger.WithRequestID(s.runCtx, logger.NewRequestID()), s.running.Done, true
+}
+
+// runHandler 在超时时间内执行任务处理函数
+// 超时后取消任务上下文并立即返回ErrTaskTimeout，释放分布式锁和cron的运行状态，避免阻塞后续调度；
+// 未响应取消的处理函数在后台继续运行至结束，停止调度器时仍会等待其退出
+func (s *Scheduler) runHandler(ctx context.Context, name string, handler TaskHandler, timeout time.Duration) error {
+	// 收集处理函数上报的指标，处理函数结束后保存
+	ctx, collector := withTaskMetrics(ctx)
+	if timeout <= 0 {
+		defer s.saveTaskMetrics(ctx, name, collector)
+		return handler(ctx)
+	}
+
+	taskCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer s.saveTaskMetrics(context.WithoutCancel(taskCtx), name, collector)
+		result <- handler(taskCtx)
+	}()
+
+	select {
+	case err := <-result:
+		if errors.Is(taskCtx.Err(), context.DeadlineExceeded) {
+			return ErrTaskTimeout
+		}
+		return err
+	case <-taskCtx.Done():
+	}
+
+	// 调度器停止取消了任务上下文，等待处理函数退出，由Stop控制等待时间
+	if !errors.Is(taskCtx.Err(), context.DeadlineExceeded) {
+		return <-result
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		err := <-result
+		logger.Warn(context.Background(), "超时任务已结束", zap.String("task", name), zap.Error(err))
+	}()
+	return ErrTaskTimeout
+}
+
+// waitRetry 等待重试间隔，调度器停止取消了任务上下文时返回false
+func (s *Scheduler) waitRetry(ctx context.Context, interval time.Duration) bool {
+	if interval <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
 
 // Remove 移除定时任务
@@ -215,6 +500,9 @@ func (s *Scheduler) Remove(name string) {
 		s.cron.Remove(entryID)
 		delete(s.entryMap, name)
 		delete(s.handlers, name)
+		delete(s.jobs, name)
+		delete(s.schedules, name)
+		delete(s.options, name)
 		logger.Info(context.Background(), "定时任务已移除", zap.String("task", name))
 	}
 }
@@ -223,24 +511,37 @@ func (s *Scheduler) Remove(name string) {
 func (s *Scheduler) RunTask(name string) error {
 	s.mu.RLock()
 	handler, exists := s.handlers[name]
+	timeout := s.options[name].Timeout
 	s.mu.RUnlock()
 
 	if !exists {
 		return fmt.Errorf("任务 %s 不存在", name)
 	}
 
+	ctx, done, ok := s.beginRun()
+	if !ok {
+		return fmt.Errorf("调度器已停止，无法执行任务 %s", name)
+	}
+
 	go func() {
-		ctx := context.Background()
+		defer done()
 		logger.Info(ctx, "手动执行定时任务", zap.String("task", name))
 
 		start := time.Now()
-		err := handler(ctx)
+		err := s.runHandler(ctx, name, handler, timeout)
 		elapsed := time.Since(start)
+		s.recordRun(ctx, name, TriggerManual, start, elapsed, err)
 
-		if err != nil {
+		switch {
+		case errors.Is(err, ErrTaskTimeout):
+			logger.Error(ctx, "手动执行定时任务超时", zap.String("task", name), zap.Duration("timeout", timeout))
+			s.recordFailure(ctx, name, TaskStatusTimeout)
+		case err != nil:
 			logger.Error(ctx, "手动执行定时任务失败", zap.String("task", name), zap.Duration("elapsed", elapsed), zap.Error(err))
-		} else {
+			s.recordFailure(ctx, name, TaskStatusFailed)
+		default:
 			logger.Info(ctx, "手动执行定时任务成功", zap.String("task", name), zap.Duration("elapsed", elapsed))
+			s.recordSuccess(ctx, name)
 		}
 	}()
 
@@ -259,12 +560,15 @@ func (s *Scheduler) GetTaskInfo(name string) (*TaskInfo, error) {
 
 	entry := s.cron.Entry(entryID)
 	return &TaskInfo{
-		Name:     name,
-		Spec:     "", // cron库不提供获取spec的方法
-		Next:     entry.Next,
-		Prev:     entry.Prev,
-		Running:  false, // cron库不提供获取运行状态的方法
-		Disabled: false, // cron库不提供获取禁用状态的方法
+		Name:         name,
+		Spec:         "", // cron库不提供获取spec的方法
+		Next:         entry.Next,
+		Prev:         entry.Prev,
+		Running:      false, // cron库不提供获取运行状态的方法
+		Disabled:     false, // cron库不提供获取禁用状态的方法
+		LastStatus:   s.getStatus(name),
+		DependsOn:    s.options[name].DependsOn,
+		Dependencies: s.dependencyStatus(s.options[name]),
 	}, nil
 }
 
@@ -290,12 +594,15 @@ func (s *Scheduler) GetAllTasksInfo() map[string]TaskInfo {
 	for name, entryID := range s.entryMap {
 		entry := s.cron.Entry(entryID)
 		result[name] = TaskInfo{
-			Name:     name,
-			Spec:     "", // cron库不提供获取spec的方法
-			Next:     entry.Next,
-			Prev:     entry.Prev,
-			Running:  false, // cron库不提供获取运行状态的方法
-			Disabled: false, // cron库不提供获取禁用状态的方法
+			Name:         name,
+			Spec:         "", // cron库不提供获取spec的方法
+			Next:         entry.Next,
+			Prev:         entry.Prev,
+			Running:      false, // cron库不提供获取运行状态的方法
+			Disabled:     false, // cron库不提供获取禁用状态的方法
+			LastStatus:   s.getStatus(name),
+			DependsOn:    s.options[name].DependsOn,
+			Dependencies: s.dependencyStatus(s.options[name]),
 		}
 	}
 
@@ -310,7 +617,7 @@ func (s *Scheduler) HealthCheck() bool {
 // cleanupDeadLocks 检查并清理可能存在的死锁
 func (s *Scheduler) cleanupDeadLocks() {
 	ctx := context.Background()
-	redisClient := redis.Client
+	redisClient := redis.GetClient()
 	if redisClient == nil {
 		logger.Error(ctx, "清理死锁失败: Redis客户端未初始化")
 		return
@@ -320,7 +627,7 @@ func (s *Scheduler) cleanupDeadLocks() {
 	s.mu.RLock()
 	var lockKeys []string
 	for name := range s.handlers {
-		lockKeys = append(lockKeys, fmt.Sprintf("scheduler:lock:%s", name))
+		lockKeys = append(lockKeys, s.redisKey(lockKeyType, name))
 	}
 	s.mu.RUnlock()
 
@@ -356,3 +663,121 @@ func (s *Scheduler) cleanupDeadLocks() {
 		}
 	}
 }
+
+// recordSuccess 在Redis中记录任务最近一次成功执行的时间
+func (s *Scheduler) recordSuccess(ctx context.Context, name string) {
+	s.setStatus(name, TaskStatusSuccess)
+	if redis.GetClient() == nil {
+		return
+	}
+	if err := redis.Set(s.redisKey(lastSuccessKeyType, name), time.Now().Unix(), 0); err != nil {
+		logger.Warn(ctx, "记录任务成功执行时间失败", zap.String("task", name), zap.Error(err))
+	}
+}
+
+// getLastSuccess 获取任务最近一次成功执行的时间，不存在时返回false
+func (s *Scheduler) getLastSuccess(name string) (time.Time, bool) {
+	return getTimestamp(s.redisKey(lastSuccessKeyType, name))
+}
+
+// redisKey 生成任务相关的Redis键
+func (s *Scheduler) redisKey(keyType, name string) string {
+	return s.keyPrefix + keyType + ":" + name
+}
+
+// getTimestamp 从Redis读取Unix时间戳，不存在或Redis未初始化时返回false
+func getTimestamp(key string) (time.Time, bool) {
+	if redis.GetClient() == nil {
+		return time.Time{}, false
+	}
+	value, err := redis.Get(key)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var unix int64
+	if _, err := fmt.Sscan(value, &unix); err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
+
+// handleMisfires 按错过执行策略处理各任务在停机期间错过的执行
+// 没有成功执行记录的任务视为首次部署，不做补偿
+// 补执行按依赖拓扑顺序依次进行，保证依赖任务先于下游任务补执行
+func (s *Scheduler) handleMisfires() {
+	ctx := context.Background()
+	if redis.GetClient() == nil {
+		logger.Warn(ctx, "Redis客户端未初始化，跳过错过执行检查")
+		return
+	}
+
+	order, err := s.topologicalOrder()
+	if err != nil {
+		logger.Error(ctx, "任务依赖关系无效，跳过错过执行检查", zap.Error(err))
+		return
+	}
+
+	type catchUp struct {
+		job  func()
+		runs int
+	}
+	var pending []catchUp
+
+	s.mu.RLock()
+	now := time.Now()
+	for _, name := range order {
+		options := s.options[name]
+		if options.MisfirePolicy == "" || options.MisfirePolicy == MisfireSkip {
+			continue
+		}
+
+		lastSuccess, ok := s.getLastSuccess(name)
+		if !ok {
+			continue
+		}
+
+		// 计算错过的次数，最多统计到补执行上限
+		limit := 1
+		if options.MisfirePolicy == MisfireCatchUp && options.CatchUpLimit > 1 {
+			limit = options.CatchUpLimit
+		}
+		missed := countMissedRuns(s.schedules[name], lastSuccess, now, limit)
+		if missed == 0 {
+			continue
+		}
+
+		logger.Info(ctx, "检测到错过执行的任务，开始补执行",
+			zap.String("task", name),
+			zap.String("policy", string(options.MisfirePolicy)),
+			zap.Time("last_success", lastSuccess),
+			zap.Int("runs", missed))
+
+		pending = append(pending, catchUp{job: s.jobs[name], runs: missed})
+	}
+	s.mu.RUnlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	go func() {
+		for _, p := range pending {
+			for i := 0; i < p.runs; i++ {
+				p.job()
+			}
+		}
+	}()
+}
+
+// countMissedRuns 计算在(from, to]区间内应执行但错过的次数，最多返回limit
+func countMissedRuns(schedule cron.Schedule, from, to time.Time, limit int) int {
+	if schedule == nil {
+		return 0
+	}
+
+	count := 0
+	for next := schedule.Next(from); !next.After(to) && count < limit; next = schedule.Next(next) {
+		count++
+	}
+	return count
+}
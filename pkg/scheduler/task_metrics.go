@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"app/pkg/logger"
+	"app/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// taskMetricsKeyType 任务上报指标的Redis键类型，保存任务最近一次上报的指标样本
+const taskMetricsKeyType = "metrics"
+
+// TaskMetric 任务执行中上报的指标样本
+type TaskMetric struct {
+	Name   string   `json:"name"`             // 指标名称
+	Help   string   `json:"help"`             // 指标说明
+	Labels []string `json:"labels,omitempty"` // 成对的标签名和标签值，不含task标签
+	Value  float64  `json:"value"`            // 样本值
+}
+
+// taskMetricsContextKey 任务上下文中保存指标收集器的键
+type taskMetricsContextKey struct{}
+
+// taskMetrics 收集一次任务执行中上报的指标
+type taskMetrics struct {
+	mu      sync.Mutex
+	samples []TaskMetric
+}
+
+// withTaskMetrics 返回带有指标收集器的任务上下文
+func withTaskMetrics(ctx context.Context) (context.Context, *taskMetrics) {
+	collector := &taskMetrics{}
+	return context.WithValue(ctx, taskMetricsContextKey{}, collector), collector
+}
+
+// SetGauge 在任务处理函数中上报gauge指标，由WriteMetrics输出并自动添加task标签
+// 同名同标签的样本以最后一次上报为准；不在任务执行上下文中调用时忽略
+func SetGauge(ctx context.Context, name, help string, value float64, labels ...string) {
+	collector, ok := ctx.Value(taskMetricsContextKey{}).(*taskMetrics)
+	if !ok {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for i, sample := range collector.samples {
+		if sample.Name == name && equalLabels(sample.Labels, labels) {
+			collector.samples[i].Value = value
+			return
+		}
+	}
+	collector.samples = append(collector.samples, TaskMetric{Name: name, Help: help, Labels: labels, Value: value})
+}
+
+// equalLabels 判断两组标签是否相同
+func equalLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// saveTaskMetrics 将本次执行上报的指标保存到Redis，替换上一次执行的指标
+// 未上报指标时保留上一次的指标，保存失败只记录日志
+func (s *Scheduler) saveTaskMetrics(ctx context.Context, name string, collector *taskMetrics) {
+	collector.mu.Lock()
+	samples := append([]TaskMetric(nil), collector.samples...)
+	collector.mu.Unlock()
+	if len(samples) == 0 || redis.GetClient() == nil {
+		return
+	}
+
+	data, err := json.Marshal(samples)
+	if err != nil {
+		logger.Warn(ctx, "序列化任务指标失败", zap.String("task", name), zap.Error(err))
+		return
+	}
+	if err := redis.Set(s.redisKey(taskMetricsKeyType, name), string(data), historyRetention); err != nil {
+		logger.Warn(ctx, "保存任务指标失败", zap.String("task", name), zap.Error(err))
+	}
+}
+
+// loadTaskMetrics 读取任务最近一次上报的指标，不存在或Redis未初始化时返回nil
+func (s *Scheduler) loadTaskMetrics(name string) []TaskMetric {
+	if redis.GetClient() == nil {
+		return nil
+	}
+	value, err := redis.Get(s.redisKey(taskMetricsKeyType, name))
+	if err != nil {
+		return nil
+	}
+	var samples []TaskMetric
+	if err := json.Unmarshal([]byte(value), &samples); err != nil {
+		return nil
+	}
+	return samples
+}
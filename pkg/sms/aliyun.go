@@ -1,12 +1,14 @@
 package sms
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"app/config"
 	"app/internal/constant"
+	"app/pkg/logger"
 
 	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
 	dysmsapi20170525 "github.com/alibabacloud-go/dysmsapi-20170525/v4/client"
@@ -57,12 +59,16 @@ func createClient(cfg config.AliyunSMSConfig) (*dysmsapi20170525.Client, error)
 }
 
 // SendSMS 发送短信，实现SMSProvider接口
-func (c *AliyunSMSProvider) SendSMS(req SMSRequest) (*SMSResponse, error) {
+// 请求ID作为外部流水号（OutId）发送，会原样出现在短信回执中
+func (c *AliyunSMSProvider) SendSMS(ctx context.Context, req SMSRequest) (*SMSResponse, error) {
 	// 构建请求
 	sendSmsRequest := &dysmsapi20170525.SendSmsRequest{
 		PhoneNumbers: tea.String(req.PhoneNumbers),
 		TemplateCode: tea.String(req.TemplateCode),
 	}
+	if requestID := logger.RequestID(ctx); requestID != "" {
+		sendSmsRequest.OutId = tea.String(requestID)
+	}
 
 	// 使用配置中的签名名称（如果请求中未指定）
 	signName := req.SignName
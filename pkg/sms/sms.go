@@ -1,12 +1,16 @@
 // Package sms 提供短信发送服务的统一接口和实现，支持多种短信服务提供商
 package sms
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // SMSProvider 短信服务提供商接口，所有短信服务提供商都需要实现此接口
 type SMSProvider interface {
 	// SendSMS 发送短信，接收通用请求参数，返回通用响应
-	SendSMS(request SMSRequest) (*SMSResponse, error)
+	// ctx中的请求ID在服务商支持时随请求发送，便于与服务商的发送记录关联
+	SendSMS(ctx context.Context, request SMSRequest) (*SMSResponse, error)
 }
 
 // SMSRequest 通用短信请求参数结构体，统一不同服务商的请求格式
@@ -41,10 +45,10 @@ func NewSMSClient(provider SMSProvider) *SMSClient {
 }
 
 // SendSMS 发送短信，内部委托给具体的短信服务提供商实现
-// 参数: request - 短信请求参数
+// 参数: ctx - 携带请求ID的上下文, request - 短信请求参数
 // 返回: 短信发送响应指针和可能的错误
-func (c *SMSClient) SendSMS(request SMSRequest) (*SMSResponse, error) {
-	return c.provider.SendSMS(request)
+func (c *SMSClient) SendSMS(ctx context.Context, request SMSRequest) (*SMSResponse, error) {
+	return c.provider.SendSMS(ctx, request)
 }
 
 // ProviderType 短信服务提供商类型，用于标识不同的短信服务提供商
@@ -0,0 +1,95 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DeepL翻译接口，免费版密钥以":fx"结尾，需使用免费版接口
+const (
+	deeplProURL     = "https://api.deepl.com/v2/translate"
+	deeplFreeURL    = "https://api-free.deepl.com/v2/translate"
+	deeplFreeSuffix = ":fx"
+)
+
+// deeplTargetLanguages DeepL要求区分地区变体的目标语言代码，其余语言使用大写的语言代码
+var deeplTargetLanguages = map[string]string{
+	"zh": "ZH-HANS",
+	"en": "EN-US",
+	"pt": "PT-BR",
+}
+
+// DeepLTranslateProvider DeepL翻译提供商，实现了Provider接口
+type DeepLTranslateProvider struct {
+	authKey string
+	url     string
+	client  *http.Client
+}
+
+// NewDeepLTranslateProvider 创建DeepL翻译提供商实例
+func NewDeepLTranslateProvider(authKey string, timeout time.Duration) *DeepLTranslateProvider {
+	url := deeplProURL
+	if strings.HasSuffix(authKey, deeplFreeSuffix) {
+		url = deeplFreeURL
+	}
+	return &DeepLTranslateProvider{
+		authKey: authKey,
+		url:     url,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// deeplTranslateRequest 文本翻译请求
+type deeplTranslateRequest struct {
+	Text       []string `json:"text"`
+	TargetLang string   `json:"target_lang"`
+}
+
+// deeplTranslateResponse 文本翻译响应
+type deeplTranslateResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+}
+
+// Translate 将文本翻译为目标语言，实现Provider接口
+func (p *DeepLTranslateProvider) Translate(ctx context.Context, text, targetLang string) (*Result, error) {
+	target, ok := deeplTargetLanguages[targetLang]
+	if !ok {
+		target = strings.ToUpper(targetLang)
+	}
+
+	payload, err := json.Marshal(deeplTranslateRequest{
+		Text:       []string{text},
+		TargetLang: target,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化翻译请求失败: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+p.authKey)
+
+	var resp deeplTranslateResponse
+	if err := doJSON(p.client, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Translations) == 0 {
+		return nil, ErrNoResult
+	}
+
+	return &Result{
+		Text:       resp.Translations[0].Text,
+		SourceLang: resp.Translations[0].DetectedSourceLanguage,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 腾讯云机器翻译接口
+const (
+	tencentTMTHost    = "tmt.tencentcloudapi.com"
+	tencentTMTService = "tmt"
+	tencentTMTVersion = "2018-03-21"
+	tencentTMTAction  = "TextTranslate"
+	// 未配置地域时使用的地域
+	tencentDefaultRegion = "ap-guangzhou"
+)
+
+// TencentTranslateProvider 腾讯云机器翻译提供商，实现了Provider接口
+type TencentTranslateProvider struct {
+	secretID  string
+	secretKey string
+	region    string
+	client    *http.Client
+}
+
+// NewTencentTranslateProvider 创建腾讯云机器翻译提供商实例
+func NewTencentTranslateProvider(secretID, secretKey, region string, timeout time.Duration) *TencentTranslateProvider {
+	if region == "" {
+		region = tencentDefaultRegion
+	}
+	return &TencentTranslateProvider{
+		secretID:  secretID,
+		secretKey: secretKey,
+		region:    region,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// tencentTranslateRequest 文本翻译请求
+type tencentTranslateRequest struct {
+	SourceText string `json:"SourceText"`
+	Source     string `json:"Source"`
+	Target     string `json:"Target"`
+	ProjectID  int    `json:"ProjectId"`
+}
+
+// tencentTranslateResponse 文本翻译响应
+type tencentTranslateResponse struct {
+	Response struct {
+		TargetText string `json:"TargetText"`
+		Source     string `json:"Source"`
+		Error      *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error"`
+	} `json:"Response"`
+}
+
+// Translate 将文本翻译为目标语言，实现Provider接口
+func (p *TencentTranslateProvider) Translate(ctx context.Context, text, targetLang string) (*Result, error) {
+	payload, err := json.Marshal(tencentTranslateRequest{
+		SourceText: text,
+		Source:     "auto",
+		Target:     targetLang,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化翻译请求失败: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+tencentTMTHost, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Host", tencentTMTHost)
+	req.Header.Set("X-TC-Action", tencentTMTAction)
+	req.Header.Set("X-TC-Version", tencentTMTVersion)
+	req.Header.Set("X-TC-Region", p.region)
+	req.Header.Set("X-TC-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("Authorization", p.sign(payload, timestamp))
+
+	var resp tencentTranslateResponse
+	if err := doJSON(p.client, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Response.Error != nil {
+		return nil, fmt.Errorf("腾讯云机器翻译返回错误: %s %s", resp.Response.Error.Code, resp.Response.Error.Message)
+	}
+
+	return &Result{
+		Text:       resp.Response.TargetText,
+		SourceLang: resp.Response.Source,
+	}, nil
+}
+
+// sign 按TC3-HMAC-SHA256签名方法生成Authorization请求头
+func (p *TencentTranslateProvider) sign(payload []byte, timestamp int64) string {
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	canonicalRequest := "POST\n/\n\n" +
+		"content-type:application/json; charset=utf-8\nhost:" + tencentTMTHost + "\n\n" +
+		"content-type;host\n" + sha256Hex(payload)
+	credentialScope := date + "/" + tencentTMTService + "/tc3_request"
+	stringToSign := "TC3-HMAC-SHA256\n" + strconv.FormatInt(timestamp, 10) + "\n" + credentialScope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	secretDate := hmacSHA256([]byte("TC3"+p.secretKey), date)
+	secretService := hmacSHA256(secretDate, tencentTMTService)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return "TC3-HMAC-SHA256 Credential=" + p.secretID + "/" + credentialScope +
+		", SignedHeaders=content-type;host, Signature=" + signature
+}
+
+// sha256Hex 计算SHA-256摘要的十六进制表示
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// doJSON 发送请求并解析JSON响应
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求翻译服务失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("翻译服务返回状态码: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析翻译响应失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Package translate 提供文本翻译服务的统一接口和实现，用于翻译动态和评论内容
+package translate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"app/config"
+	"app/pkg/redis"
+)
+
+const (
+	// 未指定时使用的缓存键前缀，后缀为目标语言:原文SHA-256摘要
+	defaultKeyPrefix = "translate:"
+	// 默认缓存时间
+	defaultCacheTTL = 7 * 24 * time.Hour
+	// 默认请求超时时间
+	defaultTimeout = 5 * time.Second
+)
+
+var (
+	// ErrUnsupportedLanguage 不支持的目标语言
+	ErrUnsupportedLanguage = errors.New("不支持的目标语言")
+	// ErrNoResult 服务未返回译文
+	ErrNoResult = errors.New("未返回译文")
+)
+
+// 支持的目标语言，使用ISO 639-1语言代码
+var supportedLanguages = map[string]bool{
+	"zh": true, // 简体中文
+	"en": true, // 英语
+	"ja": true, // 日语
+	"ko": true, // 韩语
+	"fr": true, // 法语
+	"de": true, // 德语
+	"es": true, // 西班牙语
+	"it": true, // 意大利语
+	"ru": true, // 俄语
+	"pt": true, // 葡萄牙语
+}
+
+// Result 翻译结果
+type Result struct {
+	Text       string `json:"text"`        // 译文
+	SourceLang string `json:"source_lang"` // 服务检测到的原文语言，无法识别时为空
+}
+
+// Provider 翻译服务提供商接口，所有服务提供商都需要实现此接口
+type Provider interface {
+	// Translate 将文本翻译为目标语言，原文语言由服务自动检测
+	// 参数: ctx - 上下文, text - 原文, targetLang - 目标语言代码（已规范化的ISO 639-1代码）
+	// 返回: 翻译结果和可能的错误
+	Translate(ctx context.Context, text, targetLang string) (*Result, error)
+}
+
+// Client 翻译客户端，在服务提供商之上增加Redis缓存
+type Client struct {
+	provider  Provider    // 翻译服务提供商实现
+	store     redis.Store // 翻译结果缓存
+	keyPrefix string      // 缓存键前缀
+	cacheTTL  time.Duration
+}
+
+// NewClient 创建翻译客户端实例
+// 参数: provider - 服务提供商, store - 缓存存储, keyPrefix - 缓存键前缀，为空时使用默认前缀, cacheTTL - 缓存时间
+// 返回: 翻译客户端指针
+func NewClient(provider Provider, store redis.Store, keyPrefix string, cacheTTL time.Duration) *Client {
+	if keyPrefix == "" {
+		keyPrefix = defaultKeyPrefix
+	}
+	return &Client{
+		provider:  provider,
+		store:     store,
+		keyPrefix: keyPrefix,
+		cacheTTL:  cacheTTL,
+	}
+}
+
+// Translate 将文本翻译为目标语言，按原文摘要和目标语言缓存译文，缓存读写失败不影响翻译
+func (c *Client) Translate(ctx context.Context, text, targetLang string) (*Result, error) {
+	target := NormalizeLanguage(targetLang)
+	if target == "" {
+		return nil, ErrUnsupportedLanguage
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	key := c.keyPrefix + target + ":" + hex.EncodeToString(sum[:])
+	var cached Result
+	if err := c.store.GetObj(key, &cached); err == nil && cached.Text != "" {
+		return &cached, nil
+	}
+
+	result, err := c.provider.Translate(ctx, text, target)
+	if err != nil {
+		return nil, err
+	}
+	if result.Text == "" {
+		return nil, ErrNoResult
+	}
+	result.SourceLang = NormalizeLanguage(result.SourceLang)
+
+	_ = c.store.SetObj(key, result, c.cacheTTL)
+	return result, nil
+}
+
+// NormalizeLanguage 将语言标识（如zh-CN、en_US、EN）规范化为支持的ISO 639-1语言代码，不支持时返回空字符串
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if !supportedLanguages[lang] {
+		return ""
+	}
+	return lang
+}
+
+// DetectLanguage 按文字所属的书写系统粗略识别文本语言，无需请求翻译服务
+// 含假名时识别为日语，其余按字符最多的书写系统识别；拉丁字母统一识别为英语，无法识别时返回空字符串
+func DetectLanguage(text string) string {
+	var han, kana, hangul, cyrillic, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	if kana > 0 {
+		return "ja"
+	}
+	lang, most := "", 0
+	for _, candidate := range []struct {
+		lang  string
+		count int
+	}{
+		{"zh", han},
+		{"ko", hangul},
+		{"ru", cyrillic},
+		{"en", latin},
+	} {
+		if candidate.count > most {
+			lang, most = candidate.lang, candidate.count
+		}
+	}
+	return lang
+}
+
+// ProviderType 翻译服务提供商类型
+type ProviderType string
+
+// 支持的翻译服务提供商类型
+const (
+	TencentProvider ProviderType = "tencent" // 腾讯云机器翻译
+	DeepLProvider   ProviderType = "deepl"   // DeepL
+)
+
+// GetTranslateClient 根据配置创建翻译客户端
+// 参数: store - 缓存存储, keyPrefix - 缓存键前缀
+// 返回: 翻译客户端指针和可能的错误，未配置服务密钥时返回nil客户端
+func GetTranslateClient(store redis.Store, keyPrefix string) (*Client, error) {
+	cfg := config.GetTranslateConfig()
+	if cfg.SecretKey == "" {
+		return nil, nil
+	}
+
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	cacheTTL := defaultCacheTTL
+	if d, err := time.ParseDuration(cfg.CacheTTL); err == nil && d > 0 {
+		cacheTTL = d
+	}
+
+	// 默认使用腾讯云机器翻译
+	pType := ProviderType(cfg.Provider)
+	if pType == "" {
+		pType = TencentProvider
+	}
+
+	var provider Provider
+	switch pType {
+	case TencentProvider:
+		if cfg.SecretID == "" {
+			return nil, errors.New("腾讯云机器翻译需要配置SecretID")
+		}
+		provider = NewTencentTranslateProvider(cfg.SecretID, cfg.SecretKey, cfg.Region, timeout)
+	case DeepLProvider:
+		provider = NewDeepLTranslateProvider(cfg.SecretKey, timeout)
+	default:
+		return nil, fmt.Errorf("不支持的翻译服务提供商类型: %s", pType)
+	}
+
+	return NewClient(provider, store, keyPrefix, cacheTTL), nil
+}
@@ -12,6 +12,8 @@ import (
 var (
 	// 中国大陆手机号正则表达式
 	mobileCnRegex = regexp.MustCompile(`^1[3-9]\d{9}$`)
+	// 用户名正则表达式：字母开头，4-20位字母、数字或下划线，不能与手机号混淆
+	usernameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{3,19}$`)
 )
 
 // Init 初始化验证器，注册自定义验证规则
@@ -23,10 +25,20 @@ func Init() error {
 	}
 
 	// 注册手机号验证规则 `binding:"mobile_cn"`
-	return v.RegisterValidation("mobile_cn", validateMobileCn)
+	if err := v.RegisterValidation("mobile_cn", validateMobileCn); err != nil {
+		return err
+	}
+
+	// 注册用户名验证规则 `binding:"username"`
+	return v.RegisterValidation("username", validateUsername)
 }
 
 // validateMobileCn 验证中国大陆手机号格式
 func validateMobileCn(fl validator.FieldLevel) bool {
 	return mobileCnRegex.MatchString(fl.Field().String())
 }
+
+// validateUsername 验证用户名格式
+func validateUsername(fl validator.FieldLevel) bool {
+	return usernameRegex.MatchString(fl.Field().String())
+}